@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"kojan-map/admin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAnalyticsHandler handles admin analytics requests.
+type AdminAnalyticsHandler struct {
+	service *service.AdminAnalyticsService
+}
+
+// NewAdminAnalyticsHandler creates a new AdminAnalyticsHandler.
+//
+// Parameters:
+//   - s: 分析サービスのインスタンス
+//
+// Returns:
+//   - *AdminAnalyticsHandler: 新しいハンドラーインスタンス
+func NewAdminAnalyticsHandler(s *service.AdminAnalyticsService) *AdminAnalyticsHandler {
+	return &AdminAnalyticsHandler{service: s}
+}
+
+// GetAnalytics は期間内の利用状況の推移と内訳を取得します。
+//
+// @Summary 分析ダッシュボードを取得
+// @Description 新規ユーザー数・DAU・投稿数・リアクション数・通報数の推移（日・週・月ごと）、ジャンル・地域ごとの投稿数、未対応の通報数と通報から対応までの時間の中央値を返します。値は1時間ごとに作り直す日ごとの集計から読み出します（週・月の activeUsers は1日あたりの平均）
+// @Tags Admin Dashboard
+// @Produce json
+// @Param from query string false "開始日（YYYY-MM-DD、省略時は to の29日前）"
+// @Param to query string false "終了日（YYYY-MM-DD、省略時は今日）"
+// @Param granularity query string false "集計の単位（day / week / month）" default(day)
+// @Success 200 {object} service.AnalyticsResponse "分析"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/analytics [get]
+// @Security BearerAuth
+func (h *AdminAnalyticsHandler) GetAnalytics(c *gin.Context) {
+	result, err := h.service.GetAnalytics(service.AnalyticsQuery{
+		From:        c.Query("from"),
+		To:          c.Query("to"),
+		Granularity: c.Query("granularity"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAnalyticsDate),
+			errors.Is(err, service.ErrInvalidAnalyticsRange),
+			errors.Is(err, service.ErrInvalidGranularity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kojan-map/admin/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAnalyticsHandler_GetAnalytics_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown granularity", "granularity=year"},
+		{"malformed date", "from=2026/01/01"},
+		{"reversed range", "from=2026-02-01&to=2026-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			h := NewAdminAnalyticsHandler(service.NewAdminAnalyticsService(nil, nil))
+			router.GET("/api/admin/analytics", h.GetAnalytics)
+
+			req, _ := http.NewRequest("GET", "/api/admin/analytics?"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kojan-map/admin/service"
+	"kojan-map/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuditHandler handles searching and exporting the admin audit log.
+type AdminAuditHandler struct {
+	service *service.AdminAuditService
+}
+
+// NewAdminAuditHandler creates a new AdminAuditHandler.
+//
+// Parameters:
+//   - s: 監査ログサービスのインスタンス
+//
+// Returns:
+//   - *AdminAuditHandler: 新しいハンドラーインスタンス
+func NewAdminAuditHandler(s *service.AdminAuditService) *AdminAuditHandler {
+	return &AdminAuditHandler{service: s}
+}
+
+// GetAuditLogs は管理者操作の監査ログを取得します。
+// format=csv の場合は、条件に合う監査ログを CSV でダウンロードします。
+//
+// @Summary 監査ログを取得
+// @Description 誰がいつ何を操作したか（操作前後の内容・リクエストID・IPアドレス）を新しい順に取得します。format=csv で CSV を出力します（最大10000件）
+// @Tags Admin Audit
+// @Produce json
+// @Produce text/csv
+// @Param page query int false "ページ番号" default(1)
+// @Param pageSize query int false "1ページあたりの件数" default(20)
+// @Param actor query string false "操作した管理者のgoogleId"
+// @Param action query string false "操作（例: post.delete）"
+// @Param targetType query string false "対象の種類（post / user / application / report / inquiry / sanction / content_filter_rule / outbox_message）"
+// @Param targetId query string false "対象のID"
+// @Param from query string false "この日時以降（RFC3339 または YYYY-MM-DD）"
+// @Param to query string false "この日時より前（RFC3339 または YYYY-MM-DD。日付のみの場合はその日を含む）"
+// @Param format query string false "出力形式（json / csv）" default(json)
+// @Success 200 {object} service.AuditLogListResponse "監査ログ一覧"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/audit [get]
+// @Security BearerAuth
+func (h *AdminAuditHandler) GetAuditLogs(c *gin.Context) {
+	query, err := parseAuditQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+	case "csv":
+		h.exportCSV(c, query)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format"})
+		return
+	}
+
+	result, err := h.service.GetLogs(query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// exportCSV は監査ログを CSV で書き出します。
+func (h *AdminAuditHandler) exportCSV(c *gin.Context, query service.AuditLogQuery) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-%s.csv"`, time.Now().Format("20060102-150405")))
+	c.Status(http.StatusOK)
+	if err := h.service.ExportCSV(c.Writer, query); err != nil {
+		// 書き出し前の失敗のみステータスを変更できる
+		if !c.Writer.Written() {
+			h.respondError(c, err)
+			return
+		}
+		_ = c.Error(err)
+	}
+}
+
+func (h *AdminAuditHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidAuditRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
+
+// parseAuditQuery はクエリパラメータから監査ログの検索条件を作成します。
+func parseAuditQuery(c *gin.Context) (service.AuditLogQuery, error) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	q := service.AuditLogQuery{
+		ActorGoogleID: c.Query("actor"),
+		Action:        c.Query("action"),
+		TargetType:    c.Query("targetType"),
+		TargetID:      c.Query("targetId"),
+		Page:          page,
+		PageSize:      pageSize,
+	}
+
+	var err error
+	if q.From, err = parseAuditTime(c.Query("from"), false); err != nil {
+		return q, errors.New("invalid from")
+	}
+	if q.To, err = parseAuditTime(c.Query("to"), true); err != nil {
+		return q, errors.New("invalid to")
+	}
+	return q, nil
+}
+
+// parseAuditTime は RFC3339 または YYYY-MM-DD（ローカル時刻）を解釈します。
+// endOfDay が true で日付のみの場合は翌日の0時を返します（その日を含めるため）。
+func parseAuditTime(s string, endOfDay bool) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// auditActor は監査ログに記録する操作者（ログイン中の管理者・リクエストID・IPアドレス）を返します。
+func auditActor(c *gin.Context) service.AuditActor {
+	return service.AuditActor{
+		GoogleID:  c.GetString("googleId"),
+		RequestID: c.GetString(middleware.RequestIDKey),
+		IPAddress: c.ClientIP(),
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kojan-map/admin/service"
+	"kojan-map/shared/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAuditHandler_GetAuditLogs(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"invalid format", "?format=xml"},
+		{"invalid from", "?from=yesterday"},
+		{"invalid to", "?to=2026-13-01"},
+	}
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			h := NewAdminAuditHandler(service.NewAdminAuditService(nil))
+			router.GET("/api/admin/audit", h.GetAuditLogs)
+
+			req, _ := http.NewRequest("GET", "/api/admin/audit"+tt.query, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
+
+func TestParseAuditTime(t *testing.T) {
+	t.Run("empty means no bound", func(t *testing.T) {
+		v, err := parseAuditTime("", false)
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("date-only to includes the whole day", func(t *testing.T) {
+		v, err := parseAuditTime("2026-01-02", true)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 1, 3, 0, 0, 0, 0, time.Local), *v)
+	})
+
+	t.Run("accepts RFC3339", func(t *testing.T) {
+		v, err := parseAuditTime("2026-01-02T03:04:05Z", true)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), v.UTC())
+	})
+}
+
+func TestAuditActor(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(middleware.RequestIDMiddleware())
+	var actor service.AuditActor
+	router.GET("/", func(c *gin.Context) {
+		c.Set("googleId", "admin-1")
+		actor = auditActor(c)
+	})
+
+	t.Run("uses the client request ID", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-123")
+		req.RemoteAddr = "192.0.2.1:1234"
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, service.AuditActor{GoogleID: "admin-1", RequestID: "req-123", IPAddress: "192.0.2.1"}, actor)
+		assert.Equal(t, "req-123", resp.Header().Get(middleware.RequestIDHeader))
+	})
+
+	t.Run("replaces an unsafe request ID", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set(middleware.RequestIDHeader, "bad id\n")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.NotEqual(t, "bad id\n", actor.RequestID)
+		assert.Len(t, actor.RequestID, 36)
+		assert.Equal(t, actor.RequestID, resp.Header().Get(middleware.RequestIDHeader))
+	})
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -25,37 +28,182 @@ func NewAdminBusinessHandler(s *service.AdminBusinessService) *AdminBusinessHand
 	return &AdminBusinessHandler{service: s}
 }
 
-// GetApplications は未処理の事業者申請一覧を取得します。
+// GetApplications は事業者申請を検索して取得します。
+// format=csv の場合は、条件に合う全申請を CSV でダウンロードします。
 //
 // @Summary 事業者申請一覧を取得
-// @Description 未処理の事業者申請一覧を取得します
+// @Description 事業者申請を絞り込み・並び替えて取得します。format=csv で条件に合う全件を CSV で出力します
 // @Tags Admin Business
-// @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{} "申請一覧"
+// @Produce text/csv
+// @Param q query string false "事業者名・住所・電話番号・申請者のメールアドレスの部分一致、または申請者の googleId"
+// @Param status query string false "状態（pending / needs_info / approved / rejected）"
+// @Param from query string false "この日時以降の申請（RFC3339 または YYYY-MM-DD）"
+// @Param to query string false "この日時より前の申請（RFC3339 または YYYY-MM-DD。日付のみの場合はその日を含む）"
+// @Param sort query string false "並び順の項目（createdAt / businessName / status）" default(createdAt)
+// @Param order query string false "昇順・降順（asc / desc）" default(desc)
+// @Param page query int false "ページ番号" default(1)
+// @Param pageSize query int false "1ページあたりの件数" default(20)
+// @Param format query string false "出力形式（json / csv）" default(json)
+// @Success 200 {object} service.ApplicationListResponse "申請一覧とページネーション情報"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
 // @Failure 500 {object} map[string]string "サーバーエラー"
 // @Router /api/admin/applications [get]
 // @Security BearerAuth
 func (h *AdminBusinessHandler) GetApplications(c *gin.Context) {
-	applications, err := h.service.GetApplications()
+	p, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query := service.ApplicationQuery{
+		Status:   c.Query("status"),
+		Query:    p.Query,
+		From:     p.From,
+		To:       p.To,
+		Sort:     p.Sort,
+		Order:    p.Order,
+		Page:     p.Page,
+		PageSize: p.PageSize,
+	}
+
+	if p.CSV {
+		exportListCSV(c, "applications", func(w io.Writer) error {
+			return h.service.ExportApplicationsCSV(w, query)
+		})
+		return
+	}
+
+	result, err := h.service.GetApplications(query)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetApplication は事業者申請の詳細を添付書類・状態の履歴・重複の可能性がある申請と合わせて取得します。
+//
+// @Summary 事業者申請の詳細を取得
+// @Description 添付書類（内容を除く）、状態の履歴、申請者・電話番号・法人番号のいずれかが同じ他の申請を含めて取得します
+// @Tags Admin Business
+// @Produce json
+// @Param id path int true "申請ID"
+// @Success 200 {object} service.ApplicationDetail "申請の詳細"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/applications/{id} [get]
+// @Security BearerAuth
+func (h *AdminBusinessHandler) GetApplication(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid application ID"})
+		return
+	}
+
+	detail, err := h.service.GetApplication(int32(id))
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// GetApplicationDocument は事業者申請に添付された書類をダウンロードさせます。
+//
+// @Summary 事業者申請の添付書類を取得
+// @Description 申請者が添付した確認書類（PDF・PNG・JPEG）をダウンロードします
+// @Tags Admin Business
+// @Produce application/pdf
+// @Produce image/png
+// @Produce image/jpeg
+// @Param id path int true "申請ID"
+// @Param documentId path int true "書類ID"
+// @Success 200 {file} file "添付書類"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/applications/{id}/documents/{documentId} [get]
+// @Security BearerAuth
+func (h *AdminBusinessHandler) GetApplicationDocument(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid application ID"})
+		return
+	}
+	documentID, err := strconv.ParseInt(c.Param("documentId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid document ID"})
+		return
+	}
+
+	document, err := h.service.GetApplicationDocument(int32(id), documentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondApplicationError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"applications": applications})
+	// 申請者がアップロードしたファイルをブラウザで開かせない
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, document.ContentType, document.Data)
+}
+
+// RequestApplicationInfo は審査待ちの事業者申請を差し戻し、申請者に追加情報の提出をメールで依頼します。
+//
+// @Summary 事業者申請に追加情報を依頼
+// @Description 申請を needs_info にして、必要な情報を申請者にメールで通知します。申請者が再提出すると pending に戻ります
+// @Tags Admin Business
+// @Accept json
+// @Produce json
+// @Param id path int true "申請ID"
+// @Param request body service.RequestApplicationInfoRequest true "依頼する内容"
+// @Success 200 {object} map[string]bool "依頼成功"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 409 {object} map[string]string "処理済み、または追加情報の依頼中"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/applications/{id}/request-info [put]
+// @Security BearerAuth
+func (h *AdminBusinessHandler) RequestApplicationInfo(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid application ID"})
+		return
+	}
+
+	var req service.RequestApplicationInfoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message is required"})
+		return
+	}
+
+	if err := h.service.RequestApplicationInfo(int32(id), req, auditActor(c)); err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
 // ApproveApplication は指定したIDの事業者申請を承認し、事業者会員を作成します。
+// 申請者のロールを business に変更し、事業者情報と住所の場所を作成して、申請者にメールで通知します。
 //
 // @Summary 事業者申請を承認
-// @Description 指定したIDの事業者申請を承認し、事業者会員を作成します
+// @Description 申請者のロールを business に変更し、カナ名・郵便番号・場所（placeId、または緯度・経度）を指定して事業者情報を作成します。ロールは申請者の次のトークン更新から反映されます
 // @Tags Admin Business
 // @Accept json
 // @Produce json
 // @Param id path int true "申請ID"
-// @Success 200 {object} map[string]bool "承認成功"
+// @Param request body service.ApproveApplicationRequest true "カナ名・郵便番号・場所"
+// @Success 200 {object} models.BusinessMember "作成した事業者情報"
 // @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 409 {object} map[string]string "処理済み・追加情報の依頼中、または申請者が既に事業者"
+// @Failure 500 {object} map[string]string "サーバーエラー"
 // @Router /api/admin/applications/{id}/approve [put]
 // @Security BearerAuth
 func (h *AdminBusinessHandler) ApproveApplication(c *gin.Context) {
@@ -65,25 +213,35 @@ func (h *AdminBusinessHandler) ApproveApplication(c *gin.Context) {
 		return
 	}
 
-	err = h.service.ApproveApplication(int32(id))
+	var req service.ApproveApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kanaBusinessName and a place are required"})
+		return
+	}
+
+	member, err := h.service.ApproveApplication(int32(id), req, auditActor(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondApplicationError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	c.JSON(http.StatusOK, member)
 }
 
-// RejectApplication は指定したIDの事業者申請を却下します。
+// RejectApplication は指定したIDの事業者申請を却下し、申請者に理由をメールで通知します。
 //
 // @Summary 事業者申請を却下
-// @Description 指定したIDの事業者申請を却下します
+// @Description 指定したIDの事業者申請（追加情報の依頼中を含む）を却下し、申請者にメールで通知します。理由は任意です
 // @Tags Admin Business
 // @Accept json
 // @Produce json
 // @Param id path int true "申請ID"
+// @Param request body service.RejectApplicationRequest false "却下の理由"
 // @Success 200 {object} map[string]bool "却下成功"
 // @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 409 {object} map[string]string "処理済み"
+// @Failure 500 {object} map[string]string "サーバーエラー"
 // @Router /api/admin/applications/{id}/reject [put]
 // @Security BearerAuth
 func (h *AdminBusinessHandler) RejectApplication(c *gin.Context) {
@@ -93,11 +251,40 @@ func (h *AdminBusinessHandler) RejectApplication(c *gin.Context) {
 		return
 	}
 
-	err = h.service.RejectApplication(int32(id))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// 理由は任意のため、本文がない場合はそのまま却下する
+	var req service.RejectApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.service.RejectApplication(int32(id), req, auditActor(c)); err != nil {
+		respondApplicationError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
+
+func respondApplicationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidKanaBusinessName),
+		errors.Is(err, service.ErrInvalidZipCode),
+		errors.Is(err, service.ErrBusinessPlaceRequired),
+		errors.Is(err, service.ErrBusinessPlaceNotFound),
+		errors.Is(err, service.ErrRejectReasonTooLong),
+		errors.Is(err, service.ErrInfoRequestRequired),
+		errors.Is(err, service.ErrInfoRequestTooLong):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrApplicationNotFound),
+		errors.Is(err, service.ErrApplicantNotFound),
+		errors.Is(err, service.ErrApplicationDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrApplicationAlreadyProcessed),
+		errors.Is(err, service.ErrApplicationAwaitingInfo),
+		errors.Is(err, service.ErrApplicantAlreadyBusiness):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -3,8 +3,11 @@ package handler
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"kojan-map/admin/service"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,3 +78,76 @@ func TestAdminBusinessHandler_RejectApplication(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.Code)
 	})
 }
+
+func TestAdminBusinessHandler_ApproveApplication_InvalidRequest(t *testing.T) {
+	h := NewAdminBusinessHandler(service.NewAdminBusinessService(nil, nil, nil, nil))
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"invalid id", "/api/admin/applications/abc/approve", `{"kanaBusinessName":"コバトコーヒー","placeId":1}`},
+		{"missing body", "/api/admin/applications/1/approve", ""},
+		{"malformed body", "/api/admin/applications/1/approve", `{"kanaBusinessName":`},
+		{"kana not katakana", "/api/admin/applications/1/approve", `{"kanaBusinessName":"こばと","placeId":1}`},
+		{"no place", "/api/admin/applications/1/approve", `{"kanaBusinessName":"コバトコーヒー"}`},
+	}
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			router.PUT("/api/admin/applications/:id/approve", h.ApproveApplication)
+
+			req, _ := http.NewRequest("PUT", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
+
+func TestAdminBusinessHandler_RejectApplication_ReasonTooLong(t *testing.T) {
+	h := NewAdminBusinessHandler(service.NewAdminBusinessService(nil, nil, nil, nil))
+	router := setupTestRouter()
+	router.PUT("/api/admin/applications/:id/reject", h.RejectApplication)
+
+	body := `{"reason":"` + strings.Repeat("a", 1001) + `"}`
+	req, _ := http.NewRequest("PUT", "/api/admin/applications/1/reject", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestAdminBusinessHandler_ApplicationDetail_InvalidRequest(t *testing.T) {
+	h := NewAdminBusinessHandler(service.NewAdminBusinessService(nil, nil, nil, nil))
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"invalid id on detail", "GET", "/api/admin/applications/abc", ""},
+		{"invalid document id", "GET", "/api/admin/applications/1/documents/abc", ""},
+		{"invalid id on info request", "PUT", "/api/admin/applications/abc/request-info", `{"message":"営業許可証を添付してください"}`},
+		{"missing info request body", "PUT", "/api/admin/applications/1/request-info", ""},
+		{"empty info request", "PUT", "/api/admin/applications/1/request-info", `{"message":"  "}`},
+	}
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			router.GET("/api/admin/applications/:id", h.GetApplication)
+			router.GET("/api/admin/applications/:id/documents/:documentId", h.GetApplicationDocument)
+			router.PUT("/api/admin/applications/:id/request-info", h.RequestApplicationInfo)
+
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -25,38 +27,212 @@ func NewAdminContactHandler(s *service.AdminContactService) *AdminContactHandler
 	return &AdminContactHandler{service: s}
 }
 
-// GetInquiries は全ての問い合わせ情報を取得します。
-// 管理者が問い合わせを一覧表示するために使用します。
+// GetInquiries は問い合わせを検索し、送信者のメールアドレスとともに取得します。
+// format=csv の場合は、条件に合う全問い合わせを CSV でダウンロードします。
 //
 // @Summary 問い合わせ一覧を取得
-// @Description 全ての問い合わせ情報を取得します
+// @Description 問い合わせを絞り込み・並び替えて取得します。format=csv で条件に合う全件を CSV で出力します
 // @Tags Admin Inquiries
-// @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{} "問い合わせ一覧"
+// @Produce text/csv
+// @Param q query string false "件名・本文・送信者のメールアドレスの部分一致、または送信者の googleId"
+// @Param status query string false "状態（open / waiting / closed）"
+// @Param assignee query string false "担当の管理者の googleId（none の場合は担当なし）"
+// @Param from query string false "この日時以降の問い合わせ（RFC3339 または YYYY-MM-DD）"
+// @Param to query string false "この日時より前の問い合わせ（RFC3339 または YYYY-MM-DD。日付のみの場合はその日を含む）"
+// @Param sort query string false "並び順の項目（date / subject / status / updatedAt）" default(date)
+// @Param order query string false "昇順・降順（asc / desc）" default(desc)
+// @Param page query int false "ページ番号" default(1)
+// @Param pageSize query int false "1ページあたりの件数" default(20)
+// @Param format query string false "出力形式（json / csv）" default(json)
+// @Success 200 {object} service.InquiryListResponse "問い合わせ一覧とページネーション情報"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
 // @Failure 500 {object} map[string]string "サーバーエラー"
 // @Router /api/admin/inquiries [get]
 // @Security BearerAuth
 func (h *AdminContactHandler) GetInquiries(c *gin.Context) {
-	asks, err := h.service.GetInquiries()
+	p, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query := service.InquiryQuery{
+		Status:     c.Query("status"),
+		AssigneeID: c.Query("assignee"),
+		Query:      p.Query,
+		From:       p.From,
+		To:         p.To,
+		Sort:       p.Sort,
+		Order:      p.Order,
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+	}
+
+	if p.CSV {
+		exportListCSV(c, "inquiries", func(w io.Writer) error {
+			return h.service.ExportInquiriesCSV(w, query)
+		})
+		return
+	}
+
+	result, err := h.service.GetInquiries(query)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetInquiry は指定したIDの問い合わせと、返信・追加の問い合わせ・メモのやり取りを取得します。
+//
+// @Summary 問い合わせの詳細を取得
+// @Description 問い合わせと送信者のメールアドレス、やり取り（管理者間のメモを含む）を古い順に取得します
+// @Tags Admin Inquiries
+// @Produce json
+// @Param id path int true "問い合わせID"
+// @Success 200 {object} service.InquiryDetail "問い合わせの詳細"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/inquiries/{id} [get]
+// @Security BearerAuth
+func (h *AdminContactHandler) GetInquiry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"asks": asks})
+	inquiry, err := h.service.GetInquiry(int32(id))
+	if err != nil {
+		respondInquiryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, inquiry)
+}
+
+// PostInquiryMessage は問い合わせに返信、または管理者間のメモを追加します。
+// 返信はユーザーにメールで送り、問い合わせをユーザーの返答待ちにします。
+//
+// @Summary 問い合わせに返信・メモを追加
+// @Description internal=false の場合はユーザーに返信（メール送信・状態を waiting に変更）、true の場合は管理者間のメモを追加します
+// @Tags Admin Inquiries
+// @Accept json
+// @Produce json
+// @Param id path int true "問い合わせID"
+// @Param request body service.InquiryMessageRequest true "本文とメモかどうか"
+// @Success 201 {object} models.AskMessage "追加したやり取り"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/inquiries/{id}/messages [post]
+// @Security BearerAuth
+func (h *AdminContactHandler) PostInquiryMessage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var req service.InquiryMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	message, err := h.service.PostInquiryMessage(int32(id), req, auditActor(c))
+	if err != nil {
+		respondInquiryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, message)
 }
 
-// ApproveInquiry は指定したIDの問い合わせを処理済み状態に更新します。
+// UpdateInquiryStatus は問い合わせの状態（open / waiting / closed）を変更します。
 //
-// @Summary 問い合わせを処理済みにする
-// @Description 指定したIDの問い合わせを処理済み状態に更新します
+// @Summary 問い合わせの状態を変更
+// @Description 問い合わせの状態を対応待ち（open）・ユーザーの返答待ち（waiting）・対応済み（closed）に変更します
+// @Tags Admin Inquiries
+// @Accept json
+// @Produce json
+// @Param id path int true "問い合わせID"
+// @Param request body service.InquiryStatusRequest true "変更後の状態"
+// @Success 200 {object} models.Ask "変更後の問い合わせ"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/inquiries/{id}/status [put]
+// @Security BearerAuth
+func (h *AdminContactHandler) UpdateInquiryStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var req service.InquiryStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	ask, err := h.service.UpdateInquiryStatus(int32(id), req, auditActor(c))
+	if err != nil {
+		respondInquiryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ask)
+}
+
+// AssignInquiry は問い合わせの担当の管理者を設定します（空の場合は担当なし）。
+//
+// @Summary 問い合わせの担当者を設定
+// @Description 問い合わせの担当の管理者を設定します。assigneeId が空の場合は担当なしにします
+// @Tags Admin Inquiries
+// @Accept json
+// @Produce json
+// @Param id path int true "問い合わせID"
+// @Param request body service.InquiryAssigneeRequest true "担当の管理者の googleId"
+// @Success 200 {object} models.Ask "変更後の問い合わせ"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/inquiries/{id}/assignee [put]
+// @Security BearerAuth
+func (h *AdminContactHandler) AssignInquiry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var req service.InquiryAssigneeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	ask, err := h.service.AssignInquiry(int32(id), req, auditActor(c))
+	if err != nil {
+		respondInquiryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ask)
+}
+
+// ApproveInquiry は指定したIDの問い合わせを対応済み（closed）にします。
+//
+// @Summary 問い合わせを対応済みにする
+// @Description 指定したIDの問い合わせを対応済み（closed）にします
 // @Tags Admin Inquiries
 // @Accept json
 // @Produce json
 // @Param id path int true "問い合わせID"
 // @Success 200 {object} map[string]bool "処理成功"
 // @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 409 {object} map[string]string "対応済み"
 // @Router /api/admin/inquiries/{id}/approve [put]
 // @Security BearerAuth
 func (h *AdminContactHandler) ApproveInquiry(c *gin.Context) {
@@ -66,25 +242,27 @@ func (h *AdminContactHandler) ApproveInquiry(c *gin.Context) {
 		return
 	}
 
-	err = h.service.ApproveInquiry(int32(id))
+	err = h.service.ApproveInquiry(int32(id), auditActor(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInquiryError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
-// RejectInquiry は指定したIDの問い合わせを却下状態に更新します。
+// RejectInquiry は指定したIDの問い合わせを対応不要として閉じます（closed）。
 //
 // @Summary 問い合わせを却下する
-// @Description 指定したIDの問い合わせを却下状態に更新します
+// @Description 指定したIDの問い合わせを対応不要として閉じます（closed）
 // @Tags Admin Inquiries
 // @Accept json
 // @Produce json
 // @Param id path int true "問い合わせID"
 // @Success 200 {object} map[string]bool "却下成功"
 // @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 409 {object} map[string]string "対応済み"
 // @Router /api/admin/inquiries/{id}/reject [put]
 // @Security BearerAuth
 func (h *AdminContactHandler) RejectInquiry(c *gin.Context) {
@@ -94,11 +272,27 @@ func (h *AdminContactHandler) RejectInquiry(c *gin.Context) {
 		return
 	}
 
-	err = h.service.RejectInquiry(int32(id))
+	err = h.service.RejectInquiry(int32(id), auditActor(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondInquiryError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
+
+func respondInquiryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInquiryStatus),
+		errors.Is(err, service.ErrInquiryMessageRequired),
+		errors.Is(err, service.ErrInquiryMessageTooLong),
+		errors.Is(err, service.ErrInvalidInquiryAssignee):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInquiryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInquiryAlreadyClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -3,8 +3,11 @@ package handler
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"kojan-map/admin/service"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,3 +78,35 @@ func TestAdminContactHandler_RejectInquiry(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.Code)
 	})
 }
+
+func TestAdminContactHandler_ThreadEndpoints_InvalidRequest(t *testing.T) {
+	h := NewAdminContactHandler(service.NewAdminContactService(nil, nil))
+	tests := []struct {
+		name   string
+		method string
+		route  string
+		path   string
+		body   string
+		handle gin.HandlerFunc
+	}{
+		{"detail with invalid ID", "GET", "/api/admin/inquiries/:id", "/api/admin/inquiries/abc", "", h.GetInquiry},
+		{"message with invalid ID", "POST", "/api/admin/inquiries/:id/messages", "/api/admin/inquiries/abc/messages", `{"body":"返信"}`, h.PostInquiryMessage},
+		{"message with malformed body", "POST", "/api/admin/inquiries/:id/messages", "/api/admin/inquiries/1/messages", `{`, h.PostInquiryMessage},
+		{"message with empty body", "POST", "/api/admin/inquiries/:id/messages", "/api/admin/inquiries/1/messages", `{"body":""}`, h.PostInquiryMessage},
+		{"status with unknown status", "PUT", "/api/admin/inquiries/:id/status", "/api/admin/inquiries/1/status", `{"status":"pending"}`, h.UpdateInquiryStatus},
+		{"assignee with malformed body", "PUT", "/api/admin/inquiries/:id/assignee", "/api/admin/inquiries/1/assignee", `[]`, h.AssignInquiry},
+	}
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			router.Handle(tt.method, tt.route, tt.handle)
+
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
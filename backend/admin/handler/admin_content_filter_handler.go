@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kojan-map/admin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminContentFilterHandler handles NG-word dictionary and PII detector management requests.
+type AdminContentFilterHandler struct {
+	service *service.AdminContentFilterService
+}
+
+// NewAdminContentFilterHandler creates a new AdminContentFilterHandler.
+//
+// Parameters:
+//   - s: コンテンツフィルタ管理サービスのインスタンス
+//
+// Returns:
+//   - *AdminContentFilterHandler: 新しいハンドラーインスタンス
+func NewAdminContentFilterHandler(s *service.AdminContentFilterService) *AdminContentFilterHandler {
+	return &AdminContentFilterHandler{service: s}
+}
+
+// GetRules はコンテンツフィルタのルール一覧を取得します。
+//
+// @Summary フィルタルール一覧を取得
+// @Description NGワード辞書と個人情報検出ルールの一覧を取得します
+// @Tags Admin Content Filter
+// @Accept json
+// @Produce json
+// @Param kind query string false "種類（ng_word / phone / email / my_number）"
+// @Success 200 {object} map[string][]models.ContentFilterRule "ルール一覧"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/content-filter/rules [get]
+// @Security BearerAuth
+func (h *AdminContentFilterHandler) GetRules(c *gin.Context) {
+	var kind *string
+	if k := c.Query("kind"); k != "" {
+		kind = &k
+	}
+
+	rules, err := h.service.GetRules(kind)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// CreateRule はNGワードまたは個人情報検出ルールを追加します。
+//
+// @Summary フィルタルールを追加
+// @Description NGワード、または電話番号・メールアドレス・マイナンバーの検出ルールを追加します。actionは reject / mask / review のいずれかです。
+// @Tags Admin Content Filter
+// @Accept json
+// @Produce json
+// @Param request body service.ContentFilterRuleRequest true "ルール"
+// @Success 201 {object} models.ContentFilterRule "作成したルール"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 409 {object} map[string]string "重複"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/content-filter/rules [post]
+// @Security BearerAuth
+func (h *AdminContentFilterHandler) CreateRule(c *gin.Context) {
+	var req service.ContentFilterRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.CreateRule(req, auditActor(c))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule はフィルタルールを更新します。
+//
+// @Summary フィルタルールを更新
+// @Description 指定したIDのフィルタルールを更新します
+// @Tags Admin Content Filter
+// @Accept json
+// @Produce json
+// @Param id path int true "ルールID"
+// @Param request body service.ContentFilterRuleRequest true "ルール"
+// @Success 200 {object} models.ContentFilterRule "更新したルール"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "ルールが見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/content-filter/rules/{id} [put]
+// @Security BearerAuth
+func (h *AdminContentFilterHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rule ID"})
+		return
+	}
+
+	var req service.ContentFilterRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.UpdateRule(int32(id), req, auditActor(c))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule はフィルタルールを削除します。
+//
+// @Summary フィルタルールを削除
+// @Description 指定したIDのフィルタルールを削除します
+// @Tags Admin Content Filter
+// @Accept json
+// @Produce json
+// @Param id path int true "ルールID"
+// @Success 200 {object} SuccessResponse "削除成功"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "ルールが見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/content-filter/rules/{id} [delete]
+// @Security BearerAuth
+func (h *AdminContentFilterHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rule ID"})
+		return
+	}
+
+	if err := h.service.DeleteRule(int32(id), auditActor(c)); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Success: true})
+}
+
+// CheckText は現在のルールでテキストを検査します（保存はしません）。
+//
+// @Summary フィルタの動作確認
+// @Description 現在のルールでテキストを検査し、マスク結果と判定を返します
+// @Tags Admin Content Filter
+// @Accept json
+// @Produce json
+// @Param request body object{text=string} true "検査するテキスト"
+// @Success 200 {object} service.ContentFilterCheckResponse "検査結果"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/content-filter/check [post]
+// @Security BearerAuth
+func (h *AdminContentFilterHandler) CheckText(c *gin.Context) {
+	var req struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.CheckText(req.Text)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondError はサービスのエラーをステータスコードに変換します。
+func (h *AdminContentFilterHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrFilterRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFilterRuleDuplicate):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidFilterKind),
+		errors.Is(err, service.ErrInvalidFilterAction),
+		errors.Is(err, service.ErrFilterPatternNeeded):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
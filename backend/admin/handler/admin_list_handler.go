@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kojan-map/admin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listParams はユーザー・事業者申請・問い合わせの一覧に共通するクエリパラメータです。
+type listParams struct {
+	Query    string
+	From     *time.Time
+	To       *time.Time
+	Sort     string
+	Order    string
+	Page     int
+	PageSize int
+	CSV      bool
+}
+
+// parseListParams はクエリパラメータから一覧の共通の条件を作成します。
+func parseListParams(c *gin.Context) (listParams, error) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	p := listParams{
+		Query:    c.Query("q"),
+		Sort:     c.Query("sort"),
+		Order:    c.Query("order"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+	case "csv":
+		p.CSV = true
+	default:
+		return p, errors.New("invalid format")
+	}
+
+	var err error
+	if p.From, err = parseAuditTime(c.Query("from"), false); err != nil {
+		return p, errors.New("invalid from")
+	}
+	if p.To, err = parseAuditTime(c.Query("to"), true); err != nil {
+		return p, errors.New("invalid to")
+	}
+	return p, nil
+}
+
+// exportListCSV は一覧を name-YYYYMMDD-HHMMSS.csv としてダウンロードさせます。
+// 行は読み込みながら送信するため、書き出し後の失敗はステータスを変更せずに記録だけします。
+func exportListCSV(c *gin.Context, name string, export func(w io.Writer) error) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.csv"`, name, time.Now().Format("20060102-150405")))
+	c.Status(http.StatusOK)
+	if err := export(c.Writer); err != nil {
+		if !c.Writer.Written() {
+			// CSV のヘッダーを外してエラーを JSON で返す
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			respondListError(c, err)
+			return
+		}
+		_ = c.Error(err)
+	}
+}
+
+// respondListError は一覧の検索条件の誤りを 400、それ以外を 500 として返します。
+func respondListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSort),
+		errors.Is(err, service.ErrInvalidListRange),
+		errors.Is(err, service.ErrInvalidUserRole),
+		errors.Is(err, service.ErrInvalidUserStatus),
+		errors.Is(err, service.ErrInvalidApplicationStatus),
+		errors.Is(err, service.ErrInvalidInquiryStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
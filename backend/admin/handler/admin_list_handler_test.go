@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kojan-map/admin/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminListHandlers_InvalidQuery(t *testing.T) {
+	routes := []struct {
+		path    string
+		handler gin.HandlerFunc
+	}{
+		{"/api/admin/users", NewAdminUserHandler(service.NewAdminUserService(nil, nil)).GetUsers},
+		{"/api/admin/applications", NewAdminBusinessHandler(service.NewAdminBusinessService(nil, nil, nil, nil)).GetApplications},
+		{"/api/admin/inquiries", NewAdminContactHandler(service.NewAdminContactService(nil, nil)).GetInquiries},
+	}
+	queries := []struct {
+		name  string
+		query string
+	}{
+		{"unknown format", "format=xml"},
+		{"malformed date", "from=2026/01/01"},
+		{"reversed range", "from=2026-02-01&to=2026-01-01"},
+		{"unknown sort", "sort=password"},
+		{"unknown order", "order=sideways"},
+		{"unknown status", "status=unknown"},
+		{"unknown sort in csv", "format=csv&sort=password"},
+	}
+	for _, r := range routes {
+		for _, q := range queries {
+			t.Run(r.path+" returns 400 for "+q.name, func(t *testing.T) {
+				router := setupTestRouter()
+				router.GET(r.path, r.handler)
+
+				req, _ := http.NewRequest("GET", r.path+"?"+q.query, nil)
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Header().Get("Content-Type"), "application/json")
+			})
+		}
+	}
+}
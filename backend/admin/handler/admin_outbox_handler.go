@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kojan-map/admin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminOutboxHandler handles inspection and replay of outgoing email deliveries.
+type AdminOutboxHandler struct {
+	service *service.AdminOutboxService
+}
+
+// NewAdminOutboxHandler creates a new AdminOutboxHandler.
+//
+// Parameters:
+//   - s: 送信キュー管理サービスのインスタンス
+//
+// Returns:
+//   - *AdminOutboxHandler: 新しいハンドラーインスタンス
+func NewAdminOutboxHandler(s *service.AdminOutboxService) *AdminOutboxHandler {
+	return &AdminOutboxHandler{service: s}
+}
+
+// GetMessages は送信キュー（メール）の一覧を取得します。
+//
+// @Summary 送信キュー一覧を取得
+// @Description メールの送信状況を新しい順に取得します。status=dead で配信不能になったものを確認できます
+// @Tags Admin Outbox
+// @Produce json
+// @Param page query int false "ページ番号" default(1)
+// @Param pageSize query int false "1ページあたりの件数" default(20)
+// @Param status query string false "状態（pending / processing / delivered / dead）"
+// @Param topic query string false "種類（email）"
+// @Success 200 {object} service.OutboxListResponse "送信キュー一覧"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/outbox [get]
+// @Security BearerAuth
+func (h *AdminOutboxHandler) GetMessages(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	result, err := h.service.GetMessages(c.Request.Context(), page, pageSize, c.Query("status"), c.Query("topic"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetMessage は送信キューのメッセージの詳細を取得します。
+//
+// @Summary 送信キューの詳細を取得
+// @Description 指定したIDのメッセージを送信内容・最後のエラーとともに取得します（MFAコードなど機密の内容は含みません）
+// @Tags Admin Outbox
+// @Produce json
+// @Param id path int true "メッセージID"
+// @Success 200 {object} service.OutboxMessageResponse "メッセージ"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "メッセージが見つからない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/outbox/{id} [get]
+// @Security BearerAuth
+func (h *AdminOutboxHandler) GetMessage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid message ID"})
+		return
+	}
+
+	msg, err := h.service.GetMessage(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, msg)
+}
+
+// ReplayMessage は配信不能になったメッセージを再送します。
+//
+// @Summary 配信不能のメッセージを再送
+// @Description 再試行回数を戻して配信待ちにし、監査ログに記録します。同じ冪等キーで送信されるため、受信側で重複を判定できます
+// @Tags Admin Outbox
+// @Produce json
+// @Param id path int true "メッセージID"
+// @Success 200 {object} service.OutboxMessageResponse "再送を受け付けたメッセージ"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "メッセージが見つからない"
+// @Failure 409 {object} map[string]string "配信不能ではない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/outbox/{id}/replay [post]
+// @Security BearerAuth
+func (h *AdminOutboxHandler) ReplayMessage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid message ID"})
+		return
+	}
+
+	msg, err := h.service.ReplayMessage(c.Request.Context(), id, auditActor(c))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, msg)
+}
+
+func (h *AdminOutboxHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOutboxMessageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOutboxNotReplayable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidOutboxStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
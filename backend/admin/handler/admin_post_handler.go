@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"kojan-map/admin/service"
+	"kojan-map/business/pkg/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -64,10 +65,10 @@ func (h *AdminPostHandler) GetPostByID(c *gin.Context) {
 }
 
 // DeletePost は指定したIDの投稿を削除します。
-// 関連する通報も同時に削除されます。
+// 関連する未処理の通報は「投稿を削除」として処理済みになります（通報は記録として残ります）。
 //
 // @Summary 投稿を削除
-// @Description 指定したIDの投稿を削除します。関連する通報も同時に削除されます。
+// @Description 指定したIDの投稿を論理削除します。関連する未処理の通報は「投稿を削除」として処理済みになり、通報者に通知されます。
 // @Tags Admin Posts
 // @Accept json
 // @Produce json
@@ -86,7 +87,7 @@ func (h *AdminPostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	err = h.postService.DeletePost(postID)
+	err = h.postService.DeletePost(postID, auditActor(c))
 	if err != nil {
 		// エラー種別を判別してステータスコードを変更
 		if errors.Is(err, service.ErrPostNotFound) {
@@ -100,3 +101,107 @@ func (h *AdminPostHandler) DeletePost(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "post deleted successfully"})
 }
+
+// GetModerationQueue は審査待ちの投稿一覧を取得します。
+// 新規アカウントの投稿（pending）と通報多数で自動非表示になった投稿（hidden）が対象です。
+//
+// @Summary 審査待ち投稿一覧を取得
+// @Description 審査待ち（pending）および自動非表示（hidden）の投稿を古い順に取得します
+// @Tags Admin Posts
+// @Accept json
+// @Produce json
+// @Param page query int false "ページ番号" default(1)
+// @Param pageSize query int false "1ページあたりの件数" default(20)
+// @Param status query string false "審査状態（pending / hidden）"
+// @Success 200 {object} service.ModerationQueueResponse "審査待ち投稿一覧"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/moderation/posts [get]
+// @Security BearerAuth
+func (h *AdminPostHandler) GetModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	var status *string
+	if statusStr := c.Query("status"); statusStr != "" {
+		if !isQueueStatus(statusStr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+			return
+		}
+		status = &statusStr
+	}
+
+	result, err := h.postService.GetModerationQueue(page, pageSize, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ApprovePost は審査待ちの投稿を承認し、公開します。
+//
+// @Summary 審査待ち投稿を承認
+// @Description 審査待ちまたは自動非表示の投稿を承認して公開します。未処理の通報は処理済みになります。
+// @Tags Admin Posts
+// @Accept json
+// @Produce json
+// @Param postId path int true "投稿ID"
+// @Success 200 {object} SuccessResponse "承認成功"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "投稿が見つからない"
+// @Failure 409 {object} map[string]string "審査待ちではない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/moderation/posts/{postId}/approve [put]
+// @Security BearerAuth
+func (h *AdminPostHandler) ApprovePost(c *gin.Context) {
+	h.moderate(c, h.postService.ApprovePost)
+}
+
+// RejectPost は審査待ちの投稿を却下し、非公開のままにします。
+//
+// @Summary 審査待ち投稿を却下
+// @Description 審査待ちまたは自動非表示の投稿を却下します。未処理の通報は削除扱いで処理済みになります。
+// @Tags Admin Posts
+// @Accept json
+// @Produce json
+// @Param postId path int true "投稿ID"
+// @Success 200 {object} SuccessResponse "却下成功"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "投稿が見つからない"
+// @Failure 409 {object} map[string]string "審査待ちではない"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/moderation/posts/{postId}/reject [put]
+// @Security BearerAuth
+func (h *AdminPostHandler) RejectPost(c *gin.Context) {
+	h.moderate(c, h.postService.RejectPost)
+}
+
+// moderate は承認・却下の共通処理です。
+func (h *AdminPostHandler) moderate(c *gin.Context, action func(postID int, actor service.AuditActor) error) {
+	postID, err := strconv.Atoi(c.Param("postId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		return
+	}
+
+	if err := action(postID, auditActor(c)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrPostNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+		case errors.Is(err, service.ErrPostNotInQueue):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Success: true})
+}
+
+// isQueueStatus は審査キューで指定可能な状態か判定します。
+func isQueueStatus(status string) bool {
+	return status == models.ModerationPending || status == models.ModerationHidden
+}
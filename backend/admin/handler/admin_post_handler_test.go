@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kojan-map/admin/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminPostHandler_GetModerationQueue(t *testing.T) {
+	t.Run("returns 400 for unknown status filter", func(t *testing.T) {
+		router := setupTestRouter()
+		h := NewAdminPostHandler(service.NewAdminPostService(nil))
+		router.GET("/api/admin/moderation/posts", h.GetModerationQueue)
+
+		req, _ := http.NewRequest("GET", "/api/admin/moderation/posts?status=approved", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("accepts pending and hidden filters", func(t *testing.T) {
+		assert.True(t, isQueueStatus("pending"))
+		assert.True(t, isQueueStatus("hidden"))
+		assert.False(t, isQueueStatus("rejected"))
+	})
+}
+
+func TestAdminPostHandler_ModeratePost(t *testing.T) {
+	t.Run("returns 400 for invalid post ID on approve", func(t *testing.T) {
+		router := setupTestRouter()
+		h := NewAdminPostHandler(service.NewAdminPostService(nil))
+		router.PUT("/api/admin/moderation/posts/:postId/approve", h.ApprovePost)
+
+		req, _ := http.NewRequest("PUT", "/api/admin/moderation/posts/invalid/approve", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("returns 400 for invalid post ID on reject", func(t *testing.T) {
+		router := setupTestRouter()
+		h := NewAdminPostHandler(service.NewAdminPostService(nil))
+		router.PUT("/api/admin/moderation/posts/:postId/reject", h.RejectPost)
+
+		req, _ := http.NewRequest("PUT", "/api/admin/moderation/posts/invalid/reject", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
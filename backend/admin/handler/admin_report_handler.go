@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -58,6 +59,43 @@ func (h *AdminReportHandler) GetReports(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetReportGroups は通報を対象の投稿ごとにまとめ、分類ごとの件数とともに取得します。
+//
+// @Summary 投稿ごとの通報一覧を取得
+// @Description 通報を対象の投稿ごとにまとめ、分類（spam / personal_info / harassment / dangerous / wrong_location / other）ごとの件数を返します。分類の重みの合計（score）・件数・最新の通報日時の順に並べ、対応が急がれる投稿を先に表示します。handled を省略した場合は未処理の通報のみを集計します
+// @Tags Admin Reports
+// @Produce json
+// @Param page query int false "ページ番号" default(1)
+// @Param pageSize query int false "1ページあたりの件数" default(20)
+// @Param handled query bool false "処理済みフィルター" default(false)
+// @Success 200 {object} service.ReportGroupListResponse "投稿ごとの通報一覧"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/reports/by-post [get]
+// @Security BearerAuth
+func (h *AdminReportHandler) GetReportGroups(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	var handled *bool
+	if v := c.Query("handled"); v != "" {
+		handledVal, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid handled"})
+			return
+		}
+		handled = &handledVal
+	}
+
+	result, err := h.service.GetReportGroups(page, pageSize, handled)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // GetReportDetail は指定したIDの通報詳細と対象投稿情報を取得します。
 //
 // @Summary 通報詳細を取得
@@ -87,16 +125,20 @@ func (h *AdminReportHandler) GetReportDetail(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
-// HandleReport は指定したIDの通報を処理済み状態に更新します。
+// HandleReport は指定したIDの通報に対応し、同じ投稿への未処理の通報をまとめて処理済みにします。
 //
-// @Summary 通報を処理済みにする
-// @Description 指定したIDの通報を処理済み状態に更新します
+// @Summary 通報に対応する
+// @Description 対応内容（dismiss: 問題なし / remove_post: 投稿を削除 / warn_author: 投稿は残して投稿者に警告 / suspend_author: 投稿を削除して投稿者を利用停止）とメモを指定して通報を処理します。同じ投稿への未処理の通報もまとめて処理され、通報者に対応結果が通知されます
 // @Tags Admin Reports
 // @Accept json
 // @Produce json
 // @Param id path int true "通報ID"
-// @Success 200 {object} map[string]bool "処理成功"
+// @Param request body service.ResolveReportRequest true "対応内容"
+// @Success 200 {object} service.ResolveReportResult "処理結果"
 // @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "通報・投稿が見つからない"
+// @Failure 409 {object} map[string]string "処理済み"
+// @Failure 500 {object} map[string]string "サーバーエラー"
 // @Router /api/admin/reports/{id}/handle [put]
 // @Security BearerAuth
 func (h *AdminReportHandler) HandleReport(c *gin.Context) {
@@ -106,11 +148,29 @@ func (h *AdminReportHandler) HandleReport(c *gin.Context) {
 		return
 	}
 
-	err = h.service.MarkAsHandled(int32(id))
+	var req service.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resolution and note are required"})
+		return
+	}
+
+	result, err := h.service.ResolveReport(int32(id), req, auditActor(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, service.ErrInvalidResolution),
+			errors.Is(err, service.ErrResolutionNoteRequired),
+			errors.Is(err, service.ErrResolutionNoteTooLong),
+			errors.Is(err, service.ErrInvalidSuspendDays):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrReportNotFound), errors.Is(err, service.ErrPostNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrReportAlreadyHandled):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	c.JSON(http.StatusOK, result)
 }
@@ -3,8 +3,11 @@ package handler
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"kojan-map/admin/service"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -82,3 +85,41 @@ func TestAdminReportHandler_HandleReport(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.Code)
 	})
 }
+
+func TestAdminReportHandler_HandleReport_Resolution(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing body", ""},
+		{"missing note", `{"resolution":"dismiss"}`},
+		{"unknown resolution", `{"resolution":"ban","note":"spam"}`},
+		{"invalid suspension days", `{"resolution":"suspend_author","note":"spam","suspendDays":400}`},
+	}
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			h := NewAdminReportHandler(service.NewAdminReportService(nil, nil))
+			router.PUT("/api/admin/reports/:id/handle", h.HandleReport)
+
+			req, _ := http.NewRequest("PUT", "/api/admin/reports/1/handle", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
+
+func TestAdminReportHandler_GetReportGroups_InvalidHandled(t *testing.T) {
+	router := setupTestRouter()
+	h := NewAdminReportHandler(service.NewAdminReportService(nil, nil))
+	router.GET("/api/admin/reports/by-post", h.GetReportGroups)
+
+	req, _ := http.NewRequest("GET", "/api/admin/reports/by-post?handled=maybe", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kojan-map/admin/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminSanctionHandler handles suspending, banning and lifting user accounts.
+type AdminSanctionHandler struct {
+	service *service.AdminSanctionService
+}
+
+// NewAdminSanctionHandler creates a new AdminSanctionHandler.
+//
+// Parameters:
+//   - s: 利用停止・利用禁止サービスのインスタンス
+//
+// Returns:
+//   - *AdminSanctionHandler: 新しいハンドラーインスタンス
+func NewAdminSanctionHandler(s *service.AdminSanctionService) *AdminSanctionHandler {
+	return &AdminSanctionHandler{service: s}
+}
+
+// GetSanctions は警告・利用停止・利用禁止の一覧を取得します。
+//
+// @Summary 利用停止・利用禁止の一覧を取得
+// @Description ユーザーへの警告・利用停止・利用禁止を新しい順に取得します。active=true で現在有効な利用停止・利用禁止のみを取得します
+// @Tags Admin Sanctions
+// @Produce json
+// @Param page query int false "ページ番号" default(1)
+// @Param pageSize query int false "1ページあたりの件数" default(20)
+// @Param userId query string false "対象ユーザーのgoogleId"
+// @Param kind query string false "種類（warning / suspension / ban）"
+// @Param active query bool false "現在有効なもの（true）・それ以外（false）"
+// @Success 200 {object} service.SanctionListResponse "一覧"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/sanctions [get]
+// @Security BearerAuth
+func (h *AdminSanctionHandler) GetSanctions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	q := service.SanctionQuery{
+		UserID:   c.Query("userId"),
+		Kind:     c.Query("kind"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if v := c.Query("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active"})
+			return
+		}
+		q.Active = &active
+	}
+
+	result, err := h.service.GetSanctions(q)
+	if err != nil {
+		respondSanctionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// SuspendUser はユーザーを期限付きで利用停止します。
+//
+// @Summary ユーザーを利用停止
+// @Description 理由と期間（days: 日数 / until: 終了日時。どちらもない場合は7日）を指定してユーザーを利用停止し、ユーザーに通知します。利用停止中はログイン・APIの利用が終了日時を含むエラーで拒否されます
+// @Tags Admin Sanctions
+// @Accept json
+// @Produce json
+// @Param userId path string true "ユーザーのgoogleId"
+// @Param request body service.SuspendUserRequest true "理由と期間"
+// @Success 201 {object} models.UserSanction "利用停止"
+// @Failure 400 {object} map[string]string "不正なリクエスト・管理者は利用停止できない"
+// @Failure 404 {object} map[string]string "ユーザーが見つからない"
+// @Failure 409 {object} map[string]string "利用禁止済み"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/users/{userId}/suspend [post]
+// @Security BearerAuth
+func (h *AdminSanctionHandler) SuspendUser(c *gin.Context) {
+	var req service.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+
+	sanction, err := h.service.SuspendUser(c.Param("userId"), req, auditActor(c))
+	if err != nil {
+		respondSanctionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sanction)
+}
+
+// BanUser はユーザーを無期限で利用禁止にします。
+//
+// @Summary ユーザーを利用禁止
+// @Description 理由を指定してユーザーを無期限で利用禁止にし、ユーザーに通知します（解除するまでログイン・APIの利用が拒否されます）
+// @Tags Admin Sanctions
+// @Accept json
+// @Produce json
+// @Param userId path string true "ユーザーのgoogleId"
+// @Param request body service.BanUserRequest true "理由"
+// @Success 201 {object} models.UserSanction "利用禁止"
+// @Failure 400 {object} map[string]string "不正なリクエスト・管理者は利用禁止できない"
+// @Failure 401 {object} map[string]string "追加認証が必要"
+// @Failure 404 {object} map[string]string "ユーザーが見つからない"
+// @Failure 409 {object} map[string]string "利用禁止済み"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/users/{userId}/ban [post]
+// @Security BearerAuth
+func (h *AdminSanctionHandler) BanUser(c *gin.Context) {
+	var req service.BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+
+	sanction, err := h.service.BanUser(c.Param("userId"), req, auditActor(c))
+	if err != nil {
+		respondSanctionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sanction)
+}
+
+// LiftSanction は有効な利用停止・利用禁止を解除します。
+//
+// @Summary 利用停止・利用禁止を解除
+// @Description 理由を指定して、終了日時より前に利用停止・利用禁止を解除します
+// @Tags Admin Sanctions
+// @Accept json
+// @Produce json
+// @Param id path int true "制裁ID"
+// @Param request body service.LiftSanctionRequest true "解除の理由"
+// @Success 200 {object} models.UserSanction "解除後の内容"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 409 {object} map[string]string "有効ではない（終了・解除済み、または警告）"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/sanctions/{id}/lift [put]
+// @Security BearerAuth
+func (h *AdminSanctionHandler) LiftSanction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sanction ID"})
+		return
+	}
+
+	var req service.LiftSanctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+
+	sanction, err := h.service.LiftSanction(id, req, auditActor(c))
+	if err != nil {
+		respondSanctionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sanction)
+}
+
+// ExtendSanction は有効な利用停止の終了日時を延長します。
+//
+// @Summary 利用停止を延長
+// @Description days（現在の終了日時から延長する日数）または until（新しい終了日時）を指定して利用停止を延長し、ユーザーに通知します。延長後の終了日時は今から最長365日です
+// @Tags Admin Sanctions
+// @Accept json
+// @Produce json
+// @Param id path int true "制裁ID"
+// @Param request body service.ExtendSanctionRequest true "延長する期間"
+// @Success 200 {object} models.UserSanction "延長後の内容"
+// @Failure 400 {object} map[string]string "不正なリクエスト・利用停止ではない"
+// @Failure 404 {object} map[string]string "見つからない"
+// @Failure 409 {object} map[string]string "有効ではない（終了・解除済み）"
+// @Failure 500 {object} map[string]string "サーバーエラー"
+// @Router /api/admin/sanctions/{id}/extend [put]
+// @Security BearerAuth
+func (h *AdminSanctionHandler) ExtendSanction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sanction ID"})
+		return
+	}
+
+	var req service.ExtendSanctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	sanction, err := h.service.ExtendSanction(id, req, auditActor(c))
+	if err != nil {
+		respondSanctionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sanction)
+}
+
+// respondSanctionError はサービスのエラーに対応するステータスで応答します。
+func respondSanctionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSanctionReasonRequired),
+		errors.Is(err, service.ErrSanctionReasonTooLong),
+		errors.Is(err, service.ErrInvalidSanctionPeriod),
+		errors.Is(err, service.ErrInvalidSanctionKind),
+		errors.Is(err, service.ErrCannotSanctionAdmin),
+		errors.Is(err, service.ErrSanctionNotExtendable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSanctionNotFound), errors.Is(err, service.ErrSanctionUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserAlreadyBanned), errors.Is(err, service.ErrSanctionNotActive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kojan-map/admin/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminSanctionHandler_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"suspend without reason", "POST", "/api/admin/users/user-1/suspend", `{"days":7}`},
+		{"suspend too long", "POST", "/api/admin/users/user-1/suspend", `{"reason":"spam","days":400}`},
+		{"suspend with days and until", "POST", "/api/admin/users/user-1/suspend", `{"reason":"spam","days":3,"until":"2099-01-01T00:00:00Z"}`},
+		{"ban without reason", "POST", "/api/admin/users/user-1/ban", `{}`},
+		{"lift without reason", "PUT", "/api/admin/sanctions/1/lift", `{}`},
+		{"lift invalid id", "PUT", "/api/admin/sanctions/abc/lift", `{"reason":"mistake"}`},
+		{"extend invalid id", "PUT", "/api/admin/sanctions/abc/extend", `{"days":3}`},
+		{"list invalid kind", "GET", "/api/admin/sanctions?kind=mute", ""},
+		{"list invalid active", "GET", "/api/admin/sanctions?active=maybe", ""},
+	}
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			h := NewAdminSanctionHandler(service.NewAdminSanctionService(nil, nil, nil))
+			router.GET("/api/admin/sanctions", h.GetSanctions)
+			router.POST("/api/admin/users/:userId/suspend", h.SuspendUser)
+			router.POST("/api/admin/users/:userId/ban", h.BanUser)
+			router.PUT("/api/admin/sanctions/:id/lift", h.LiftSanction)
+			router.PUT("/api/admin/sanctions/:id/extend", h.ExtendSanction)
+
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"kojan-map/admin/service"
+	"kojan-map/business/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StepUpVerifyRequest represents a request to complete the step-up.
+type StepUpVerifyRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// AdminStepUpHandler handles admin step-up (re-authentication) HTTP requests.
+type AdminStepUpHandler struct {
+	service *service.AdminStepUpService
+}
+
+// NewAdminStepUpHandler creates a new AdminStepUpHandler.
+func NewAdminStepUpHandler(s *service.AdminStepUpService) *AdminStepUpHandler {
+	return &AdminStepUpHandler{service: s}
+}
+
+// SendCode は追加認証用の確認コードを管理者のメールアドレスに送信します。
+//
+// @Summary 追加認証コードを送信
+// @Description 削除・承認などの操作の前に必要な追加認証の確認コードをメールで送信します
+// @Tags Admin Auth
+// @Produce json
+// @Success 200 {object} service.StepUpChallengeResponse "送信成功"
+// @Failure 401 {object} map[string]string "認証エラー"
+// @Failure 502 {object} map[string]string "メール送信エラー"
+// @Router /api/admin/step-up [post]
+// @Security BearerAuth
+func (h *AdminStepUpHandler) SendCode(c *gin.Context) {
+	claims, ok := currentClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.SendCode(claims)
+	if err != nil {
+		if errors.Is(err, service.ErrStepUpSendFailed) {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to send verification code"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Verify は確認コードを検証し、追加認証済みのアクセストークンを発行します。
+//
+// @Summary 追加認証
+// @Description 確認コードを検証し、amr・auth_time を含むアクセストークンを発行します
+// @Tags Admin Auth
+// @Accept json
+// @Produce json
+// @Param body body StepUpVerifyRequest true "確認コード"
+// @Success 200 {object} service.StepUpResponse "追加認証済みのトークン"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
+// @Failure 401 {object} map[string]string "コードが正しくない・期限切れ"
+// @Router /api/admin/step-up/verify [post]
+// @Security BearerAuth
+func (h *AdminStepUpHandler) Verify(c *gin.Context) {
+	var req StepUpVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	claims, ok := currentClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.Verify(claims, req.Code)
+	if err != nil {
+		if errors.Is(err, service.ErrStepUpCodeInvalid) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// currentClaims returns the verified token claims set by AuthMiddleware.
+func currentClaims(c *gin.Context) (*jwt.Claims, bool) {
+	value, exists := c.Get("user")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*jwt.Claims)
+	return claims, ok && claims != nil
+}
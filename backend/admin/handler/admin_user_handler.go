@@ -1,8 +1,8 @@
 package handler
 
 import (
+	"io"
 	"net/http"
-	"strconv"
 
 	"kojan-map/admin/service"
 
@@ -19,26 +19,57 @@ func NewAdminUserHandler(s *service.AdminUserService) *AdminUserHandler {
 	return &AdminUserHandler{service: s}
 }
 
-// GetUsers はページネーション付きで登録ユーザーの一覧を取得します。
+// GetUsers は登録ユーザーを検索し、ユーザーごとの投稿数・受けた通報数・した通報数とともに取得します。
+// format=csv の場合は、条件に合う全ユーザーを CSV でダウンロードします。
 //
 // @Summary ユーザー一覧を取得
-// @Description ページネーション付きで登録ユーザーの一覧を取得します
+// @Description 登録ユーザーを絞り込み・並び替えて取得します。format=csv で条件に合う全件を CSV で出力します
 // @Tags Admin Users
-// @Accept json
 // @Produce json
+// @Produce text/csv
+// @Param q query string false "googleId の完全一致、またはメールアドレスの部分一致"
+// @Param role query string false "ロール（user / business / admin）"
+// @Param status query string false "状態（active / suspended / banned / deleted）"
+// @Param from query string false "この日時以降に登録（RFC3339 または YYYY-MM-DD）"
+// @Param to query string false "この日時より前に登録（RFC3339 または YYYY-MM-DD。日付のみの場合はその日を含む）"
+// @Param sort query string false "並び順の項目（registrationDate / gmail / postCount / reportsReceived / reportsFiled）" default(registrationDate)
+// @Param order query string false "昇順・降順（asc / desc）" default(desc)
 // @Param page query int false "ページ番号" default(1)
 // @Param pageSize query int false "1ページあたりの件数" default(20)
-// @Success 200 {object} map[string]interface{} "ユーザー一覧とページネーション情報"
+// @Param format query string false "出力形式（json / csv）" default(json)
+// @Success 200 {object} service.UserListResponse "ユーザー一覧とページネーション情報"
+// @Failure 400 {object} map[string]string "不正なリクエスト"
 // @Failure 500 {object} map[string]string "サーバーエラー"
-// @Router /api/users [get]
+// @Router /api/admin/users [get]
 // @Security BearerAuth
 func (h *AdminUserHandler) GetUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	p, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query := service.UserQuery{
+		Query:          p.Query,
+		Role:           c.Query("role"),
+		Status:         c.Query("status"),
+		RegisteredFrom: p.From,
+		RegisteredTo:   p.To,
+		Sort:           p.Sort,
+		Order:          p.Order,
+		Page:           p.Page,
+		PageSize:       p.PageSize,
+	}
+
+	if p.CSV {
+		exportListCSV(c, "users", func(w io.Writer) error {
+			return h.service.ExportUsersCSV(w, query)
+		})
+		return
+	}
 
-	result, err := h.service.GetUsers(page, pageSize)
+	result, err := h.service.GetUsers(query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondListError(c, err)
 		return
 	}
 
@@ -64,7 +95,7 @@ func (h *AdminUserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err := h.service.DeleteUser(userID)
+	err := h.service.DeleteUser(userID, auditActor(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -4,11 +4,20 @@
 package handler
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
+	adminrepo "kojan-map/admin/repository"
+	"kojan-map/admin/service"
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/outbox"
+	"kojan-map/shared/contentfilter"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -189,3 +198,106 @@ func TestIntegration_ADMIN004_ReportListPagination(t *testing.T) {
 	db.Raw("SELECT COUNT(*) FROM report WHERE reportId >= 20000 AND reportFlag = false").Row().Scan(&unhandledCount)
 	assert.Equal(t, int64(3), unhandledCount, "未処理の通報が3件ではありません")
 }
+
+// auditLogs は対象の監査ログを古い順に取得します
+func auditLogs(t *testing.T, db *gorm.DB, targetType, targetID string) []models.AdminAuditLog {
+	var logs []models.AdminAuditLog
+	err := db.Where("targetType = ? AND targetId = ?", targetType, targetID).Order("auditId ASC").Find(&logs).Error
+	require.NoError(t, err, "監査ログの取得に失敗しました")
+	return logs
+}
+
+// TestIntegration_ADMIN005_ContentFilterRuleAudit フィルタルールの作成・更新・削除が監査ログに記録されることを確認
+func TestIntegration_ADMIN005_ContentFilterRuleAudit(t *testing.T) {
+	db := setupTestDB(t)
+	actor := service.AuditActor{GoogleID: "admin-test-auditor", RequestID: "req-1"}
+	svc := service.NewAdminContentFilterService(db, adminrepo.NewContentFilterRuleRepository(db), contentfilter.NewService(db))
+
+	rule, err := svc.CreateRule(service.ContentFilterRuleRequest{Kind: models.FilterKindNGWord, Pattern: "監査テスト語", Action: models.FilterActionReject}, actor)
+	require.NoError(t, err, "ルールの作成に失敗しました")
+	id := strconv.Itoa(int(rule.ID))
+	defer db.Exec("DELETE FROM content_filter_rule WHERE ruleId = ?", rule.ID)
+
+	_, err = svc.UpdateRule(rule.ID, service.ContentFilterRuleRequest{Kind: models.FilterKindNGWord, Pattern: "監査テスト語", Action: models.FilterActionMask}, actor)
+	require.NoError(t, err, "ルールの更新に失敗しました")
+	require.NoError(t, svc.DeleteRule(rule.ID, actor), "ルールの削除に失敗しました")
+
+	logs := auditLogs(t, db, models.AuditTargetFilterRule, id)
+	require.Len(t, logs, 3)
+	assert.Equal(t, models.AuditActionFilterRuleCreate, logs[0].Action)
+	assert.Nil(t, logs[0].Before)
+	assert.Equal(t, models.AuditActionFilterRuleUpdate, logs[1].Action)
+	assert.Contains(t, *logs[1].Before, models.FilterActionReject)
+	assert.Contains(t, *logs[1].After, models.FilterActionMask)
+	assert.Equal(t, models.AuditActionFilterRuleDelete, logs[2].Action)
+	assert.Nil(t, logs[2].After)
+	assert.Equal(t, "admin-test-auditor", logs[2].ActorGoogleID)
+}
+
+// TestIntegration_ADMIN006_OutboxReplayAudit 配信不能メッセージの再送が本文を含めずに監査ログに記録されることを確認
+func TestIntegration_ADMIN006_OutboxReplayAudit(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	actor := service.AuditActor{GoogleID: "admin-test-auditor"}
+
+	msg, err := outbox.NewMessage(outbox.TopicEmail, fmt.Sprintf("admin-test-replay-%d", time.Now().UnixNano()), map[string]string{"code": "secret-123456"})
+	require.NoError(t, err)
+	require.NoError(t, outbox.Enqueue(db, msg))
+	require.NoError(t, db.Model(&outbox.Message{}).Where("id = ?", msg.ID).Update("status", outbox.StatusDead).Error)
+	id := strconv.FormatInt(msg.ID, 10)
+	defer db.Exec("DELETE FROM outbox WHERE id = ?", msg.ID)
+
+	svc := service.NewAdminOutboxService(db, outbox.NewStore(db), nil)
+	replayed, err := svc.ReplayMessage(ctx, msg.ID, actor)
+	require.NoError(t, err, "再送に失敗しました")
+	assert.Equal(t, outbox.StatusPending, replayed.Status)
+
+	// 配信不能ではないメッセージは再送できず、監査ログも増えない
+	_, err = svc.ReplayMessage(ctx, msg.ID, actor)
+	assert.ErrorIs(t, err, service.ErrOutboxNotReplayable)
+	_, err = svc.ReplayMessage(ctx, -1, actor)
+	assert.ErrorIs(t, err, service.ErrOutboxMessageNotFound)
+
+	logs := auditLogs(t, db, models.AuditTargetOutbox, id)
+	require.Len(t, logs, 1)
+	assert.Equal(t, models.AuditActionOutboxReplay, logs[0].Action)
+	assert.Contains(t, *logs[0].Before, string(outbox.StatusDead))
+	assert.Contains(t, *logs[0].After, string(outbox.StatusPending))
+	assert.NotContains(t, *logs[0].After, "secret-123456")
+}
+
+// TestIntegration_ADMIN007_WarnAuthorKeepsPost 投稿者への警告では投稿を削除しないことを確認
+func TestIntegration_ADMIN007_WarnAuthorKeepsPost(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	reporterGoogleID := createTestUser(t, db, "admin-test-warn-reporter", "warn-reporter@test.com", "user")
+	authorGoogleID := createTestUser(t, db, "admin-test-warn-author", "warn-author@test.com", "user")
+	defer db.Exec("DELETE FROM user_sanction WHERE userId = ?", authorGoogleID)
+	placeID := createTestPlace(t, db, 20020)
+
+	postID, reportID := int32(20020), int32(20020)
+	err := db.Exec("INSERT INTO post (postId, userId, title, text, placeId, numView, numReaction, genreId, postDate) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		postID, authorGoogleID, "警告テスト", "内容", placeID, 0, 0, 1, time.Now()).Error
+	require.NoError(t, err)
+	err = db.Exec("INSERT INTO report (reportId, userId, postId, reason, date, reportFlag, removeFlag) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		reportID, reporterGoogleID, postID, "不適切", time.Now(), false, false).Error
+	require.NoError(t, err)
+
+	svc := service.NewAdminReportService(adminrepo.NewReportRepository(db), db)
+	result, err := svc.ResolveReport(reportID, service.ResolveReportRequest{Resolution: models.ReportResolutionWarnAuthor, Note: "表現に注意してください"},
+		service.AuditActor{GoogleID: "admin-test-auditor"})
+	require.NoError(t, err, "通報の処理に失敗しました")
+	require.NotNil(t, result.Sanction)
+	assert.Equal(t, models.SanctionWarning, result.Sanction.Kind)
+
+	var deleted int64
+	db.Raw("SELECT COUNT(*) FROM post WHERE postId = ? AND deletedAt IS NOT NULL", postID).Row().Scan(&deleted)
+	assert.Zero(t, deleted, "警告で投稿が削除されています")
+
+	var reportFlag, removeFlag bool
+	err = db.Raw("SELECT reportFlag, removeFlag FROM report WHERE reportId = ?", reportID).Row().Scan(&reportFlag, &removeFlag)
+	require.NoError(t, err)
+	assert.True(t, reportFlag, "通報が処理済みになっていません")
+	assert.False(t, removeFlag, "警告でremoveFlagが設定されています")
+}
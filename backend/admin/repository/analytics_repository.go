@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"time"
+
+	"kojan-map/business/pkg/activity"
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// PostFacet は集計に使う投稿のジャンルと場所です。
+type PostFacet struct {
+	GenreID   int32    `gorm:"column:genreId"`
+	Latitude  *float64 `gorm:"column:latitude"`
+	Longitude *float64 `gorm:"column:longitude"`
+}
+
+// HandledReport は対応済みの通報の通報日時と対応日時です。
+type HandledReport struct {
+	Date       time.Time `gorm:"column:date"`
+	ResolvedAt time.Time `gorm:"column:resolvedAt"`
+}
+
+// GenreName はジャンルのIDと名前です。
+type GenreName struct {
+	GenreID   int32  `gorm:"column:genreId"`
+	GenreName string `gorm:"column:genreName"`
+}
+
+// AnalyticsRepository は管理画面の分析の集計（daily_metric）と、集計元のデータベース操作を処理します。
+// 集計元の検索はすべて日時の範囲で絞り込みます（全件の集計はしない）。
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository は新しいAnalyticsRepositoryを作成します。
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// WithTx はトランザクション内で操作するAnalyticsRepositoryを返します。
+func (r *AnalyticsRepository) WithTx(tx *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: tx}
+}
+
+// ReplaceDay は日の集計を metrics で置き換えます。
+func (r *AnalyticsRepository) ReplaceDay(day time.Time, metrics []models.DailyMetric) error {
+	if err := r.db.Where("date = ?", day).Delete(&models.DailyMetric{}).Error; err != nil {
+		return err
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(metrics, 200).Error
+}
+
+// FindRange は from から to までの日の集計を取得します（両端を含む）。
+func (r *AnalyticsRepository) FindRange(from, to time.Time) ([]models.DailyMetric, error) {
+	var metrics []models.DailyMetric
+	result := r.db.Where("date BETWEEN ? AND ?", from, to).Order("date ASC").Find(&metrics)
+	return metrics, result.Error
+}
+
+// RolledUpDays は from から to までのうち、集計済みの日を取得します（両端を含む）。
+func (r *AnalyticsRepository) RolledUpDays(from, to time.Time) ([]time.Time, error) {
+	var days []time.Time
+	result := r.db.Model(&models.DailyMetric{}).Where("date BETWEEN ? AND ?", from, to).Distinct().Pluck("date", &days)
+	return days, result.Error
+}
+
+// CountNewUsers は期間内に登録したユーザー数を数えます。
+func (r *AnalyticsRepository) CountNewUsers(from, to time.Time) (int64, error) {
+	var count int64
+	result := r.db.Table("user").Where("registrationDate >= ? AND registrationDate < ?", from, to).Count(&count)
+	return count, result.Error
+}
+
+// CountActiveUsers はその日に利用したユーザー数を数えます。
+func (r *AnalyticsRepository) CountActiveUsers(day time.Time) (int64, error) {
+	var count int64
+	result := r.db.Model(&activity.DailyActivity{}).Where("date = ?", day).Count(&count)
+	return count, result.Error
+}
+
+// FindPostFacets は期間内の投稿のジャンルと場所を取得します（削除済みの投稿を含む）。
+func (r *AnalyticsRepository) FindPostFacets(from, to time.Time) ([]PostFacet, error) {
+	var facets []PostFacet
+	result := r.db.Table("post").
+		Select("post.genreId, place.latitude, place.longitude").
+		Joins("LEFT JOIN place ON place.placeId = post.placeId").
+		Where("post.postDate >= ? AND post.postDate < ?", from, to).
+		Scan(&facets)
+	return facets, result.Error
+}
+
+// CountReactions は期間内のリアクション数を数えます。
+func (r *AnalyticsRepository) CountReactions(from, to time.Time) (int64, error) {
+	var count int64
+	result := r.db.Table("reaction").Where("createdAt >= ? AND createdAt < ?", from, to).Count(&count)
+	return count, result.Error
+}
+
+// CountReports は期間内の通報数を数えます。
+func (r *AnalyticsRepository) CountReports(from, to time.Time) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Report{}).Where("date >= ? AND date < ?", from, to).Count(&count)
+	return count, result.Error
+}
+
+// FindHandledReports は期間内に対応した通報を取得します。
+func (r *AnalyticsRepository) FindHandledReports(from, to time.Time) ([]HandledReport, error) {
+	var reports []HandledReport
+	result := r.db.Model(&models.Report{}).
+		Select("date, resolvedAt").
+		Where("resolvedAt >= ? AND resolvedAt < ?", from, to).
+		Scan(&reports)
+	return reports, result.Error
+}
+
+// CountBacklog は at の時点で未対応だった通報数を数えます。
+// 対応日時のない処理済みの通報（対応内容の記録より前のもの）は含めません。
+func (r *AnalyticsRepository) CountBacklog(at time.Time) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Report{}).
+		Where("date < ?", at).
+		Where("reportFlag = ? OR resolvedAt >= ?", false, at).
+		Count(&count)
+	return count, result.Error
+}
+
+// FindGenreNames はジャンルの名前を取得します。
+func (r *AnalyticsRepository) FindGenreNames() ([]GenreName, error) {
+	var genres []GenreName
+	result := r.db.Table("genre").Select("genreId, genreName").Scan(&genres)
+	return genres, result.Error
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
-	"kojan-map/shared/models"
+	"time"
+
+	"kojan-map/business/pkg/models"
+	sharedrepo "kojan-map/shared/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AskRepositoryはお問合せのデータベース操作を処理します．
@@ -16,6 +20,11 @@ func NewAskRepository(db *gorm.DB) *AskRepository {
 	return &AskRepository{db: db}
 }
 
+// WithTxはトランザクション内で操作するAskRepositoryを返す機能です．
+func (r *AskRepository) WithTx(tx *gorm.DB) *AskRepository {
+	return &AskRepository{db: tx}
+}
+
 // FindAllは全てのお問い合わせを取得する機能です．
 func (r *AskRepository) FindAll() ([]models.Ask, error) {
 	var asks []models.Ask
@@ -26,19 +35,37 @@ func (r *AskRepository) FindAll() ([]models.Ask, error) {
 	return asks, nil
 }
 
-// FindAllPaginatedはページネーション付きでお問い合わせを取得する機能です．
-func (r *AskRepository) FindAllPaginated(page, pageSize int) ([]models.Ask, int, error) {
-	var asks []models.Ask
-	var total int64
+// AskFilterはお問い合わせの絞り込み条件です．空の項目は条件に含めません．
+type AskFilter struct {
+	Status     string
+	AssigneeID string     // 担当の管理者の googleId（"none" の場合は担当なし）
+	Query      string     // 件名・本文・送信者のメールアドレスの部分一致，または送信者の googleId
+	From       *time.Time // この日時以降のお問い合わせ（含む）
+	To         *time.Time // この日時より前のお問い合わせ（含まない）
+}
+
+// AskUnassignedはAskFilter.AssigneeIDで担当なしを指定する値です．
+const AskUnassigned = "none"
+
+// AskRowは送信者のメールアドレス付きのお問い合わせです．
+type AskRow struct {
+	models.Ask
+	UserEmail string `gorm:"column:userEmail" json:"userEmail"`
+}
 
-	// 総数を取得
-	if err := r.db.Model(&models.Ask{}).Count(&total).Error; err != nil {
+// askColumnsはお問い合わせと送信者のメールアドレスです．
+const askColumns = "ask.*, user.gmail AS userEmail"
+
+// FindAllPaginatedは条件に合うお問い合わせを orderBy の順にページネーション付きで取得する機能です．
+func (r *AskRepository) FindAllPaginated(filter AskFilter, orderBy string, page, pageSize int) ([]AskRow, int, error) {
+	var total int64
+	if err := r.filtered(filter).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// ページネーション付きで取得
+	var asks []AskRow
 	offset := (page - 1) * pageSize
-	result := r.db.Order("date DESC").Offset(offset).Limit(pageSize).Find(&asks)
+	result := r.filtered(filter).Select(askColumns).Order(orderBy).Offset(offset).Limit(pageSize).Find(&asks)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -46,6 +73,54 @@ func (r *AskRepository) FindAllPaginated(page, pageSize int) ([]models.Ask, int,
 	return asks, int(total), nil
 }
 
+// Eachは条件に合うお問い合わせを orderBy の順に1件ずつ読み出して fn を呼び出す機能です（CSV の出力用）．
+func (r *AskRepository) Each(filter AskFilter, orderBy string, fn func(*AskRow) error) error {
+	rows, err := r.filtered(filter).Select(askColumns).Order(orderBy).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ask AskRow
+		if err := r.db.ScanRows(rows, &ask); err != nil {
+			return err
+		}
+		if err := fn(&ask); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filteredは絞り込み条件を適用したクエリを返す機能です．
+func (r *AskRepository) filtered(filter AskFilter) *gorm.DB {
+	query := r.db.Model(&models.Ask{}).
+		Joins("LEFT JOIN user ON user.googleId = ask.userId")
+	if filter.Status != "" {
+		query = query.Where("ask.status = ?", filter.Status)
+	}
+	switch filter.AssigneeID {
+	case "":
+	case AskUnassigned:
+		query = query.Where("ask.assigneeId IS NULL")
+	default:
+		query = query.Where("ask.assigneeId = ?", filter.AssigneeID)
+	}
+	if filter.Query != "" {
+		like := "%" + sharedrepo.EscapeLike(filter.Query) + "%"
+		query = query.Where("ask.userId = ? OR ask.subject LIKE ? OR ask.text LIKE ? OR user.gmail LIKE ?",
+			filter.Query, like, like, like)
+	}
+	if filter.From != nil {
+		query = query.Where("ask.date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("ask.date < ?", *filter.To)
+	}
+	return query
+}
+
 // FindByIdは特定のIDのお問い合わせを取得する機能です．
 func (r *AskRepository) FindByID(id int32) (*models.Ask, error) {
 	var ask models.Ask
@@ -56,22 +131,49 @@ func (r *AskRepository) FindByID(id int32) (*models.Ask, error) {
 	return &ask, nil
 }
 
-// MarkAsHandledは特定のお問い合わせを処理済みとする機能です．
-func (r *AskRepository) MarkAsHandled(id int32) error {
+// FindByIDForUpdateは特定のIDのお問い合わせを取得し，トランザクションの終了まで行をロックする機能です．
+func (r *AskRepository) FindByIDForUpdate(id int32) (*models.Ask, error) {
+	var ask models.Ask
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("askId = ?", id).First(&ask)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &ask, nil
+}
+
+// UpdateStatusは特定のお問い合わせの状態を変更する機能です．closed の場合は処理済み（askFlag）とします．
+func (r *AskRepository) UpdateStatus(id int32, status models.AskStatus, at time.Time) error {
 	return r.db.Model(&models.Ask{}).
 		Where("askId = ?", id).
 		Updates(map[string]interface{}{
-			"askFlag": true,
-			"status":  models.AskStatusHandled,
+			"status":    status,
+			"askFlag":   status == models.AskStatusClosed,
+			"updatedAt": at,
 		}).Error
 }
 
-// MarkAsRejectedは特定のお問い合わせを却下状態にする機能です．
-func (r *AskRepository) MarkAsRejected(id int32) error {
+// UpdateAssigneeは特定のお問い合わせの担当の管理者を変更する機能です．nil の場合は担当なしにします．
+func (r *AskRepository) UpdateAssignee(id int32, assigneeID *string, at time.Time) error {
 	return r.db.Model(&models.Ask{}).
 		Where("askId = ?", id).
 		Updates(map[string]interface{}{
-			"askFlag": false,
-			"status":  models.AskStatusRejected,
+			"assigneeId": assigneeID,
+			"updatedAt":  at,
 		}).Error
 }
+
+// CreateMessageはお問い合わせのやり取りを追加する機能です．
+func (r *AskRepository) CreateMessage(message *models.AskMessage) error {
+	return r.db.Create(message).Error
+}
+
+// FindMessagesはお問い合わせのやり取りを古い順に取得する機能です．withNotes が false の場合は管理者間のメモを含めません．
+func (r *AskRepository) FindMessages(askID int32, withNotes bool) ([]models.AskMessage, error) {
+	var messages []models.AskMessage
+	query := r.db.Where("askId = ?", askID)
+	if !withNotes {
+		query = query.Where("kind <> ?", models.AskMessageNote)
+	}
+	result := query.Order("createdAt ASC, messageId ASC").Find(&messages)
+	return messages, result.Error
+}
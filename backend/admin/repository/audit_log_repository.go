@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"time"
+
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter は監査ログの絞り込み条件です。空の項目は条件に含めません。
+type AuditLogFilter struct {
+	ActorGoogleID string
+	Action        string
+	TargetType    string
+	TargetID      string
+	From          *time.Time // この日時以降（含む）
+	To            *time.Time // この日時より前（含まない）
+}
+
+// AuditLogRepository は管理者操作の監査ログのデータベース操作を処理します。
+// 監査ログは追記のみで、更新・削除の操作は提供しません。
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository は新しいAuditLogRepositoryを作成します。
+// 操作と同じトランザクションで記録する場合は、トランザクションの *gorm.DB を渡します。
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Create は監査ログを追加します。
+func (r *AuditLogRepository) Create(entry *models.AdminAuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// FindAll は条件に合う監査ログをページネーション付きで新しい順に取得します。
+func (r *AuditLogRepository) FindAll(filter AuditLogFilter, page, pageSize int) ([]models.AdminAuditLog, int64, error) {
+	query := r.filtered(filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AdminAuditLog
+	result := query.Order("createdAt DESC, auditId DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&logs)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return logs, total, nil
+}
+
+// filtered は絞り込み条件を適用したクエリを返します。
+func (r *AuditLogRepository) filtered(filter AuditLogFilter) *gorm.DB {
+	query := r.db.Model(&models.AdminAuditLog{})
+	if filter.ActorGoogleID != "" {
+		query = query.Where("actorGoogleId = ?", filter.ActorGoogleID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("targetType = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("targetId = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("createdAt >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("createdAt < ?", *filter.To)
+	}
+	return query
+}
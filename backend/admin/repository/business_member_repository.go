@@ -1,7 +1,7 @@
 package repository
 
 import (
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -16,6 +16,11 @@ func NewBusinessMemberRepository(db *gorm.DB) *BusinessMemberRepository {
 	return &BusinessMemberRepository{db: db}
 }
 
+// WithTx はトランザクション内で操作するBusinessMemberRepositoryを返します。
+func (r *BusinessMemberRepository) WithTx(tx *gorm.DB) *BusinessMemberRepository {
+	return &BusinessMemberRepository{db: tx}
+}
+
 // CountAll は全ての事業者会員の数をカウントします。
 func (r *BusinessMemberRepository) CountAll() (int, error) {
 	var count int64
@@ -23,6 +28,13 @@ func (r *BusinessMemberRepository) CountAll() (int, error) {
 	return int(count), result.Error
 }
 
+// ExistsByUserID はユーザーの事業者会員情報があるかを確認します。
+func (r *BusinessMemberRepository) ExistsByUserID(userID string) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.BusinessMember{}).Where("userId = ?", userID).Count(&count)
+	return count > 0, result.Error
+}
+
 // Create は承認されたリクエストから新しい事業者会員を作成します。
 func (r *BusinessMemberRepository) Create(member *models.BusinessMember) error {
 	return r.db.Create(member).Error
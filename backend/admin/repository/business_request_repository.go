@@ -1,9 +1,13 @@
 package repository
 
 import (
-	"kojan-map/shared/models"
+	"time"
+
+	"kojan-map/business/pkg/models"
+	sharedrepo "kojan-map/shared/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // BusinessRequestRepository は事業者申請のデータベース操作を処理します。
@@ -16,6 +20,11 @@ func NewBusinessRequestRepository(db *gorm.DB) *BusinessRequestRepository {
 	return &BusinessRequestRepository{db: db}
 }
 
+// WithTx はトランザクション内で操作するBusinessRequestRepositoryを返します。
+func (r *BusinessRequestRepository) WithTx(tx *gorm.DB) *BusinessRequestRepository {
+	return &BusinessRequestRepository{db: tx}
+}
+
 // FindAll は全ての事業者申請を取得します。
 func (r *BusinessRequestRepository) FindAll() ([]models.BusinessRequest, error) {
 	var requests []models.BusinessRequest
@@ -26,22 +35,33 @@ func (r *BusinessRequestRepository) FindAll() ([]models.BusinessRequest, error)
 	return requests, nil
 }
 
-// FindAllPaginated はページネーション付きで事業者申請を取得します。
-func (r *BusinessRequestRepository) FindAllPaginated(page, pageSize int, status *string) ([]models.BusinessRequest, int, error) {
-	var requests []models.BusinessRequest
-	var total int64
+// BusinessRequestFilter は事業者申請の絞り込み条件です。空の項目は条件に含めません。
+type BusinessRequestFilter struct {
+	Status string
+	Query  string     // 事業者名・住所・電話番号・申請者のメールアドレスの部分一致、または申請者の googleId
+	From   *time.Time // この日時以降の申請（含む）
+	To     *time.Time // この日時より前の申請（含まない）
+}
 
-	query := r.db.Model(&models.BusinessRequest{})
-	if status != nil && *status != "" {
-		query = query.Where("status = ?", *status)
-	}
+// BusinessRequestRow は申請者のメールアドレス付きの事業者申請です。
+type BusinessRequestRow struct {
+	models.BusinessRequest
+	ApplicantEmail string `gorm:"column:applicantEmail" json:"applicantEmail"`
+}
+
+// businessRequestColumns は事業者申請と申請者のメールアドレスです。
+const businessRequestColumns = "businessReq.*, user.gmail AS applicantEmail"
 
-	if err := query.Count(&total).Error; err != nil {
+// FindAllPaginated は条件に合う事業者申請を orderBy の順にページネーション付きで取得します。
+func (r *BusinessRequestRepository) FindAllPaginated(filter BusinessRequestFilter, orderBy string, page, pageSize int) ([]BusinessRequestRow, int, error) {
+	var total int64
+	if err := r.filtered(filter).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	var requests []BusinessRequestRow
 	offset := (page - 1) * pageSize
-	result := query.Order("createdAt DESC").Offset(offset).Limit(pageSize).Find(&requests)
+	result := r.filtered(filter).Select(businessRequestColumns).Order(orderBy).Offset(offset).Limit(pageSize).Find(&requests)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -49,6 +69,47 @@ func (r *BusinessRequestRepository) FindAllPaginated(page, pageSize int, status
 	return requests, int(total), nil
 }
 
+// Each は条件に合う事業者申請を orderBy の順に1件ずつ読み出して fn を呼び出します（CSV の出力用）。
+func (r *BusinessRequestRepository) Each(filter BusinessRequestFilter, orderBy string, fn func(*BusinessRequestRow) error) error {
+	rows, err := r.filtered(filter).Select(businessRequestColumns).Order(orderBy).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var request BusinessRequestRow
+		if err := r.db.ScanRows(rows, &request); err != nil {
+			return err
+		}
+		if err := fn(&request); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filtered は絞り込み条件を適用したクエリを返します。
+func (r *BusinessRequestRepository) filtered(filter BusinessRequestFilter) *gorm.DB {
+	query := r.db.Model(&models.BusinessRequest{}).
+		Joins("LEFT JOIN user ON user.googleId = businessReq.userId")
+	if filter.Status != "" {
+		query = query.Where("businessReq.status = ?", filter.Status)
+	}
+	if filter.Query != "" {
+		like := "%" + sharedrepo.EscapeLike(filter.Query) + "%"
+		query = query.Where("businessReq.userId = ? OR businessReq.name LIKE ? OR businessReq.address LIKE ? OR businessReq.phone LIKE ? OR user.gmail LIKE ?",
+			filter.Query, like, like, like, like)
+	}
+	if filter.From != nil {
+		query = query.Where("businessReq.createdAt >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("businessReq.createdAt < ?", *filter.To)
+	}
+	return query
+}
+
 // FindByID はIDで事業者申請を検索します。
 func (r *BusinessRequestRepository) FindByID(id int32) (*models.BusinessRequest, error) {
 	var request models.BusinessRequest
@@ -59,11 +120,68 @@ func (r *BusinessRequestRepository) FindByID(id int32) (*models.BusinessRequest,
 	return &request, nil
 }
 
-// UpdateStatus は事業者申請のステータスを更新します。
-func (r *BusinessRequestRepository) UpdateStatus(id int32, status string) error {
+// FindByIDForUpdate はIDで事業者申請を取得し、トランザクションの終了まで行をロックします。
+func (r *BusinessRequestRepository) FindByIDForUpdate(id int32) (*models.BusinessRequest, error) {
+	var request models.BusinessRequest
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("requestId = ?", id).First(&request)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+// UpdateStatus は事業者申請のステータスと更新日時を更新します。
+func (r *BusinessRequestRepository) UpdateStatus(id int32, status string, at time.Time) error {
 	return r.db.Model(&models.BusinessRequest{}).
 		Where("requestId = ?", id).
-		Update("status", status).Error
+		Updates(map[string]interface{}{"status": status, "updatedAt": at}).Error
+}
+
+// CreateHistory は事業者申請の状態の変化を履歴に記録します。
+func (r *BusinessRequestRepository) CreateHistory(history *models.BusinessRequestHistory) error {
+	return r.db.Create(history).Error
+}
+
+// FindHistory は事業者申請の状態の履歴を古い順に取得します。
+func (r *BusinessRequestRepository) FindHistory(id int32) ([]models.BusinessRequestHistory, error) {
+	var history []models.BusinessRequestHistory
+	result := r.db.Where("requestId = ?", id).Order("createdAt ASC, historyId ASC").Find(&history)
+	return history, result.Error
+}
+
+// FindDocuments は事業者申請の添付書類を内容を除いて取得します。
+func (r *BusinessRequestRepository) FindDocuments(id int32) ([]models.BusinessRequestDocument, error) {
+	var documents []models.BusinessRequestDocument
+	result := r.db.Omit("data").Where("requestId = ?", id).Order("documentId ASC").Find(&documents)
+	return documents, result.Error
+}
+
+// FindDocument は事業者申請の添付書類を内容を含めて取得します。
+func (r *BusinessRequestRepository) FindDocument(id int32, documentID int64) (*models.BusinessRequestDocument, error) {
+	var document models.BusinessRequestDocument
+	result := r.db.Where("requestId = ? AND documentId = ?", id, documentID).First(&document)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &document, nil
+}
+
+// FindDuplicates は申請者・電話番号・法人番号のいずれかが同じ他の事業者申請を新しい順に取得します。
+func (r *BusinessRequestRepository) FindDuplicates(request *models.BusinessRequest) ([]BusinessRequestRow, error) {
+	query := r.db.Model(&models.BusinessRequest{}).
+		Joins("LEFT JOIN user ON user.googleId = businessReq.userId").
+		Where("businessReq.requestId <> ?", request.RequestID)
+	match := r.db.Where("businessReq.userId = ?", request.UserID)
+	if request.Phone != "" {
+		match = match.Or("businessReq.phone = ?", request.Phone)
+	}
+	if request.CorporateNumber != nil {
+		match = match.Or("businessReq.corporateNumber = ?", *request.CorporateNumber)
+	}
+
+	var requests []BusinessRequestRow
+	result := query.Where(match).Select(businessRequestColumns).Order("businessReq.createdAt DESC").Find(&requests)
+	return requests, result.Error
 }
 
 // Delete は事業者申請を削除します。
@@ -74,6 +192,6 @@ func (r *BusinessRequestRepository) Delete(id int32) error {
 // CountPending は保留中の事業者申請の数をカウントします。
 func (r *BusinessRequestRepository) CountPending() (int, error) {
 	var count int64
-	result := r.db.Model(&models.BusinessRequest{}).Where("status = ?", "pending").Count(&count)
+	result := r.db.Model(&models.BusinessRequest{}).Where("status = ?", models.BusinessRequestPending).Count(&count)
 	return int(count), result.Error
 }
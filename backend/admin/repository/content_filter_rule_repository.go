@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContentFilterRuleRepositoryはコンテンツフィルタのルールのデータベース操作を処理します．
+type ContentFilterRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewContentFilterRuleRepositoryは新しくContentFilterRuleRepositoryを作成するためのコンストラクタ関数です．
+func NewContentFilterRuleRepository(db *gorm.DB) *ContentFilterRuleRepository {
+	return &ContentFilterRuleRepository{db: db}
+}
+
+// WithTxはトランザクション内で操作するContentFilterRuleRepositoryを返す機能です．
+func (r *ContentFilterRuleRepository) WithTx(tx *gorm.DB) *ContentFilterRuleRepository {
+	return &ContentFilterRuleRepository{db: tx}
+}
+
+// FindAllは種類で絞り込んでルールを取得する機能です．kindがnilの場合は全件を取得します．
+func (r *ContentFilterRuleRepository) FindAll(kind *string) ([]models.ContentFilterRule, error) {
+	var rules []models.ContentFilterRule
+	query := r.db.Order("kind ASC, ruleId ASC")
+	if kind != nil {
+		query = query.Where("kind = ?", *kind)
+	}
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FindByIDは特定のIDのルールを取得する機能です．
+func (r *ContentFilterRuleRepository) FindByID(id int32) (*models.ContentFilterRule, error) {
+	var rule models.ContentFilterRule
+	if err := r.db.Where("ruleId = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindByIDForUpdateは特定のIDのルールを取得し，トランザクションの終了まで行をロックする機能です．
+func (r *ContentFilterRuleRepository) FindByIDForUpdate(id int32) (*models.ContentFilterRule, error) {
+	var rule models.ContentFilterRule
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("ruleId = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ExistsはNGワードの重複を確認する機能です．
+func (r *ContentFilterRuleRepository) Exists(kind, pattern string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.ContentFilterRule{}).
+		Where("kind = ? AND pattern = ?", kind, pattern).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Createはルールを作成する機能です．
+func (r *ContentFilterRuleRepository) Create(rule *models.ContentFilterRule) error {
+	// enabled=false も保存できるよう明示的に全カラムを指定する
+	return r.db.Select("*").Omit("ruleId").Create(rule).Error
+}
+
+// Updateはルールを更新する機能です．
+func (r *ContentFilterRuleRepository) Update(rule *models.ContentFilterRule) error {
+	return r.db.Save(rule).Error
+}
+
+// Deleteはルールを削除する機能です．
+func (r *ContentFilterRuleRepository) Delete(id int32) error {
+	return r.db.Where("ruleId = ?", id).Delete(&models.ContentFilterRule{}).Error
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// samePlaceThreshold は同じ場所とみなす緯度・経度の差です（約11m以内、ユーザー側の投稿と同じ）。
+const samePlaceThreshold = 0.0001
+
+// PlaceRepository は場所のデータベース操作を処理します。
+type PlaceRepository struct {
+	db *gorm.DB
+}
+
+// NewPlaceRepository は新しいPlaceRepositoryを作成します。
+func NewPlaceRepository(db *gorm.DB) *PlaceRepository {
+	return &PlaceRepository{db: db}
+}
+
+// WithTx はトランザクション内で操作するPlaceRepositoryを返します。
+func (r *PlaceRepository) WithTx(tx *gorm.DB) *PlaceRepository {
+	return &PlaceRepository{db: tx}
+}
+
+// FindByID はIDで場所を取得します。
+func (r *PlaceRepository) FindByID(id int32) (*models.Place, error) {
+	var place models.Place
+	result := r.db.Where("placeId = ?", id).First(&place)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &place, nil
+}
+
+// FindOrCreate は緯度・経度の近い場所を取得し、なければ作成します。
+// 事業者の位置は投稿ではないため、既存の場所の投稿数（numPost）は変更しません。
+func (r *PlaceRepository) FindOrCreate(latitude, longitude float64) (*models.Place, error) {
+	var place models.Place
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+			latitude-samePlaceThreshold, latitude+samePlaceThreshold,
+			longitude-samePlaceThreshold, longitude+samePlaceThreshold).
+		Order(gorm.Expr("ABS(latitude - ?) + ABS(longitude - ?)", latitude, longitude)).
+		First(&place)
+	if result.Error == nil {
+		return &place, nil
+	}
+	if result.Error != gorm.ErrRecordNotFound {
+		return nil, result.Error
+	}
+
+	place = models.Place{Latitude: latitude, Longitude: longitude}
+	if err := r.db.Create(&place).Error; err != nil {
+		return nil, err
+	}
+	return &place, nil
+}
@@ -1,7 +1,12 @@
 package repository
 
 import (
-	"kojan-map/shared/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/reportcategory"
 
 	"gorm.io/gorm"
 )
@@ -16,6 +21,11 @@ func NewReportRepository(db *gorm.DB) *ReportRepository {
 	return &ReportRepository{db: db}
 }
 
+// WithTx はトランザクション内で操作するReportRepositoryを返します。
+func (r *ReportRepository) WithTx(tx *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: tx}
+}
+
 // FindAll はページネーションとオプションのフィルター付きで全ての通報を取得します。
 func (r *ReportRepository) FindAll(page, pageSize int, handled *bool) ([]models.Report, int, error) {
 	var reports []models.Report
@@ -54,9 +64,94 @@ func (r *ReportRepository) CountUnprocessed() (int, error) {
 	return int(count), result.Error
 }
 
-// MarkAsHandled は通報を処理済みとしてマークします。
-func (r *ReportRepository) MarkAsHandled(id int32) error {
-	return r.db.Model(&models.Report{}).
-		Where("reportId = ?", id).
-		Update("reportFlag", true).Error
+// FindOpenByPostID は投稿への未処理の通報を古い順に取得します。
+func (r *ReportRepository) FindOpenByPostID(postID int32) ([]models.Report, error) {
+	var reports []models.Report
+	result := r.db.Where("postId = ? AND reportFlag = ?", postID, false).Order("date ASC").Find(&reports)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return reports, nil
+}
+
+// Resolve は未処理の通報を対応内容とともに処理済みにし、更新した件数を返します。
+// 処理済みの通報は更新しません（同時操作による二重処理を防止）。
+func (r *ReportRepository) Resolve(ids []int32, resolution, note, resolvedBy string, removed bool, at time.Time) (int64, error) {
+	result := r.db.Model(&models.Report{}).
+		Where("reportId IN ? AND reportFlag = ?", ids, false).
+		Updates(map[string]interface{}{
+			"reportFlag":     true,
+			"openFlag":       nil,
+			"removeFlag":     removed,
+			"resolution":     resolution,
+			"resolutionNote": note,
+			"resolvedBy":     resolvedBy,
+			"resolvedAt":     at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ReportGroupRow は投稿ごとに集計した通報です。
+type ReportGroupRow struct {
+	PostID      int32     `gorm:"column:postId"`
+	ReportCount int       `gorm:"column:reportCount"`
+	Score       int       `gorm:"column:score"`
+	LatestAt    time.Time `gorm:"column:latestAt"`
+}
+
+// ReportCategoryCount は投稿・分類ごとの通報数です。
+type ReportCategoryCount struct {
+	PostID   int32  `gorm:"column:postId"`
+	Category string `gorm:"column:category"`
+	Count    int    `gorm:"column:count"`
+}
+
+// FindGroupedByPost は通報を投稿ごとに集計し、分類の重みの合計（score）・件数・最新の通報日時の順に取得します。
+func (r *ReportRepository) FindGroupedByPost(page, pageSize int, handled *bool) ([]ReportGroupRow, int, error) {
+	query := r.db.Model(&models.Report{})
+	if handled != nil {
+		query = query.Where("reportFlag = ?", *handled)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Distinct("postId").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []ReportGroupRow
+	result := query.
+		Select("postId, COUNT(*) AS reportCount, SUM(" + categoryWeightSQL() + ") AS score, MAX(date) AS latestAt").
+		Group("postId").
+		Order("score DESC, reportCount DESC, latestAt DESC, postId DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return rows, int(total), nil
+}
+
+// CountByPostAndCategory は指定した投稿への通報数を分類ごとに数えます。
+func (r *ReportRepository) CountByPostAndCategory(postIDs []int32, handled *bool) ([]ReportCategoryCount, error) {
+	var counts []ReportCategoryCount
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+	query := r.db.Model(&models.Report{}).Where("postId IN ?", postIDs)
+	if handled != nil {
+		query = query.Where("reportFlag = ?", *handled)
+	}
+	result := query.Select("postId, category, COUNT(*) AS count").Group("postId, category").Scan(&counts)
+	return counts, result.Error
+}
+
+// categoryWeightSQL は通報の分類の重みを返すSQLの式です（未定義の分類は Other と同じ）。
+func categoryWeightSQL() string {
+	var b strings.Builder
+	b.WriteString("CASE category")
+	for _, c := range reportcategory.All() {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", c, reportcategory.Weight(c))
+	}
+	fmt.Fprintf(&b, " ELSE %d END", reportcategory.Weight(reportcategory.Other))
+	return b.String()
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"time"
+
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserSanctionFilter は警告・利用停止・利用禁止の絞り込み条件です。空の項目は条件に含めません。
+type UserSanctionFilter struct {
+	UserID string
+	Kind   string
+	Active *bool     // true は有効な利用停止・利用禁止のみ、false はそれ以外
+	At     time.Time // Active を判定する日時
+}
+
+// UserSanctionRepository はユーザーへの警告・利用停止・利用禁止のデータベース操作を処理します。
+type UserSanctionRepository struct {
+	db *gorm.DB
+}
+
+// NewUserSanctionRepository は新しいUserSanctionRepositoryを作成します。
+func NewUserSanctionRepository(db *gorm.DB) *UserSanctionRepository {
+	return &UserSanctionRepository{db: db}
+}
+
+// WithTx はトランザクション内で操作するUserSanctionRepositoryを返します。
+func (r *UserSanctionRepository) WithTx(tx *gorm.DB) *UserSanctionRepository {
+	return &UserSanctionRepository{db: tx}
+}
+
+// Create は警告・利用停止・利用禁止を記録します。
+func (r *UserSanctionRepository) Create(sanction *models.UserSanction) error {
+	return r.db.Create(sanction).Error
+}
+
+// FindByIDForUpdate はIDで警告・利用停止・利用禁止を検索し、トランザクションの終了まで行をロックします。
+func (r *UserSanctionRepository) FindByIDForUpdate(id int64) (*models.UserSanction, error) {
+	var sanction models.UserSanction
+	result := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("sanctionId = ?", id).First(&sanction)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &sanction, nil
+}
+
+// FindAll は条件に合う警告・利用停止・利用禁止をページネーション付きで新しい順に取得します。
+func (r *UserSanctionRepository) FindAll(filter UserSanctionFilter, page, pageSize int) ([]models.UserSanction, int64, error) {
+	query := r.db.Model(&models.UserSanction{})
+	if filter.UserID != "" {
+		query = query.Where("userId = ?", filter.UserID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Active != nil {
+		active := "kind IN ? AND liftedAt IS NULL AND startsAt <= ? AND (endsAt IS NULL OR endsAt > ?)"
+		restricting := []string{models.SanctionSuspension, models.SanctionBan}
+		if *filter.Active {
+			query = query.Where(active, restricting, filter.At, filter.At)
+		} else {
+			query = query.Where("NOT ("+active+")", restricting, filter.At, filter.At)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var sanctions []models.UserSanction
+	result := query.Order("createdAt DESC, sanctionId DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&sanctions)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return sanctions, total, nil
+}
+
+// Lift は有効な利用停止・利用禁止を解除します。解除した件数を返します（既に解除済みの場合は 0）。
+func (r *UserSanctionRepository) Lift(id int64, liftedBy, reason string, at time.Time) (int64, error) {
+	result := r.db.Model(&models.UserSanction{}).
+		Where("sanctionId = ? AND liftedAt IS NULL", id).
+		Updates(map[string]interface{}{
+			"liftedAt":   at,
+			"liftedBy":   liftedBy,
+			"liftReason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// UpdateEndsAt は利用停止の終了日時を変更します。
+func (r *UserSanctionRepository) UpdateEndsAt(id int64, endsAt time.Time) error {
+	return r.db.Model(&models.UserSanction{}).
+		Where("sanctionId = ?", id).
+		Update("endsAt", endsAt).Error
+}
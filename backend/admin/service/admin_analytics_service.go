@@ -0,0 +1,427 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	adminrepo "kojan-map/admin/repository"
+	"kojan-map/business/pkg/activity"
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// Analytics errors
+var (
+	ErrInvalidAnalyticsDate  = errors.New("from and to must be dates in YYYY-MM-DD format")
+	ErrInvalidAnalyticsRange = errors.New("from must not be after to, and the range must be at most 731 days")
+	ErrInvalidGranularity    = errors.New("granularity must be one of day, week, month")
+)
+
+// Analytics granularities
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week" // 月曜始まり
+	GranularityMonth = "month"
+)
+
+// Analytics limits
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 731
+	analyticsDateLayout  = "2006-01-02"
+	// areaGridDegrees is the size of the latitude/longitude grid used as the area of a post (約11km)
+	areaGridDegrees = 0.1
+	// unknownArea is the area of posts without a place
+	unknownArea = "unknown"
+	// maxAreas is the number of areas returned, busiest first
+	maxAreas = 20
+)
+
+// handleTimeBuckets are the upper bounds (minutes) of the time-to-handle histogram; longer times go to "inf"
+var handleTimeBuckets = []int{15, 60, 180, 360, 720, 1440, 2880, 4320, 10080, 20160, 43200}
+
+// AnalyticsQuery represents the range and granularity of the analytics
+type AnalyticsQuery struct {
+	From        string // YYYY-MM-DD（省略時は to の29日前）
+	To          string // YYYY-MM-DD（省略時は今日）
+	Granularity string // day, week, month（省略時は day）
+}
+
+// AnalyticsPoint represents the metrics of one period
+type AnalyticsPoint struct {
+	Period   string `json:"period"` // 期間の初日（YYYY-MM-DD）
+	NewUsers int64  `json:"newUsers"`
+	// ActiveUsers is the DAU (the average per day for week and month)
+	ActiveUsers    float64 `json:"activeUsers"`
+	Posts          int64   `json:"posts"`
+	Reactions      int64   `json:"reactions"`
+	Reports        int64   `json:"reports"`
+	ReportsHandled int64   `json:"reportsHandled"`
+	// ReportBacklog is the number of open reports at the end of the period
+	ReportBacklog int64 `json:"reportBacklog"`
+}
+
+// GenreCount represents the number of posts in a genre
+type GenreCount struct {
+	GenreID   int32  `json:"genreId"`
+	GenreName string `json:"genreName"`
+	Count     int64  `json:"count"`
+}
+
+// AreaCount represents the number of posts in an area
+// Area is the south-west corner ("lat,lng") of a 0.1 degree grid cell, or "unknown"
+type AreaCount struct {
+	Area  string `json:"area"`
+	Count int64  `json:"count"`
+}
+
+// ModerationStats represents the report backlog and how fast reports are handled in the range
+type ModerationStats struct {
+	Backlog        int64 `json:"backlog"` // 期間の最終日の終わりに未対応の通報数
+	ReportsHandled int64 `json:"reportsHandled"`
+	// MedianHandleHours is the median time from report to resolution (estimated from the histogram; nil if none handled)
+	MedianHandleHours *float64 `json:"medianHandleHours"`
+}
+
+// AnalyticsResponse represents the admin analytics
+type AnalyticsResponse struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Granularity  string           `json:"granularity"`
+	Series       []AnalyticsPoint `json:"series"`
+	PostsByGenre []GenreCount     `json:"postsByGenre"`
+	PostsByArea  []AreaCount      `json:"postsByArea"`
+	Moderation   ModerationStats  `json:"moderation"`
+	// RolledUpAt is when the newest rollup in the range was made (nil if nothing is rolled up yet)
+	RolledUpAt *time.Time `json:"rolledUpAt,omitempty"`
+}
+
+// AdminAnalyticsService handles the admin analytics
+// 集計ジョブ（AnalyticsRollup）が日ごとの値を daily_metric テーブルに保存し、分析はその値だけを読み出します
+type AdminAnalyticsService struct {
+	db   *gorm.DB
+	repo *adminrepo.AnalyticsRepository
+	now  func() time.Time
+}
+
+// NewAdminAnalyticsService creates a new AdminAnalyticsService
+func NewAdminAnalyticsService(db *gorm.DB, repo *adminrepo.AnalyticsRepository) *AdminAnalyticsService {
+	return &AdminAnalyticsService{db: db, repo: repo, now: time.Now}
+}
+
+// GetAnalytics returns the time series and breakdowns for the range from the rollups
+func (s *AdminAnalyticsService) GetAnalytics(q AnalyticsQuery) (*AnalyticsResponse, error) {
+	from, to, err := analyticsRange(q, activity.Day(s.now()))
+	if err != nil {
+		return nil, err
+	}
+	if q.Granularity == "" {
+		q.Granularity = GranularityDay
+	}
+	if q.Granularity != GranularityDay && q.Granularity != GranularityWeek && q.Granularity != GranularityMonth {
+		return nil, ErrInvalidGranularity
+	}
+
+	metrics, err := s.repo.FindRange(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analytics: %w", err)
+	}
+	genres, err := s.repo.FindGenreNames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genres: %w", err)
+	}
+
+	return buildAnalytics(metrics, from, to, q.Granularity, genres), nil
+}
+
+// RollupDay recomputes the metrics of a day from the source tables
+func (s *AdminAnalyticsService) RollupDay(day time.Time) error {
+	day = activity.Day(day)
+	end := day.AddDate(0, 0, 1)
+	now := s.now()
+
+	var metrics []models.DailyMetric
+	add := func(metric, dimension string, value int64) {
+		metrics = append(metrics, models.DailyMetric{Date: day, Metric: metric, Dimension: dimension, Value: value, UpdatedAt: now})
+	}
+
+	newUsers, err := s.repo.CountNewUsers(day, end)
+	if err != nil {
+		return fmt.Errorf("failed to count new users: %w", err)
+	}
+	add(models.MetricNewUsers, "", newUsers)
+
+	activeUsers, err := s.repo.CountActiveUsers(day)
+	if err != nil {
+		return fmt.Errorf("failed to count active users: %w", err)
+	}
+	add(models.MetricActiveUsers, "", activeUsers)
+
+	posts, err := s.repo.FindPostFacets(day, end)
+	if err != nil {
+		return fmt.Errorf("failed to find posts: %w", err)
+	}
+	add(models.MetricPosts, "", int64(len(posts)))
+	byGenre := make(map[int32]int64)
+	byArea := make(map[string]int64)
+	for _, p := range posts {
+		byGenre[p.GenreID]++
+		byArea[areaOf(p.Latitude, p.Longitude)]++
+	}
+	for genreID, n := range byGenre {
+		add(models.MetricPostsByGenre, strconv.Itoa(int(genreID)), n)
+	}
+	for area, n := range byArea {
+		add(models.MetricPostsByArea, area, n)
+	}
+
+	reactions, err := s.repo.CountReactions(day, end)
+	if err != nil {
+		return fmt.Errorf("failed to count reactions: %w", err)
+	}
+	add(models.MetricReactions, "", reactions)
+
+	reports, err := s.repo.CountReports(day, end)
+	if err != nil {
+		return fmt.Errorf("failed to count reports: %w", err)
+	}
+	add(models.MetricReports, "", reports)
+
+	handled, err := s.repo.FindHandledReports(day, end)
+	if err != nil {
+		return fmt.Errorf("failed to find handled reports: %w", err)
+	}
+	add(models.MetricReportsHandled, "", int64(len(handled)))
+	histogram := make(map[string]int64)
+	for _, r := range handled {
+		histogram[handleTimeBucket(r.ResolvedAt.Sub(r.Date))]++
+	}
+	for bucket, n := range histogram {
+		add(models.MetricHandleTime, bucket, n)
+	}
+
+	backlog, err := s.repo.CountBacklog(end)
+	if err != nil {
+		return fmt.Errorf("failed to count report backlog: %w", err)
+	}
+	add(models.MetricReportBacklog, "", backlog)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.repo.WithTx(tx).ReplaceDay(day, metrics)
+	})
+}
+
+// RollupRecent rolls up today and yesterday, and the days in the last backfillDays that have no rollup yet
+func (s *AdminAnalyticsService) RollupRecent(backfillDays int) (int, error) {
+	today := activity.Day(s.now())
+	days := []time.Time{today, today.AddDate(0, 0, -1)}
+
+	if backfillDays > 2 {
+		from, to := today.AddDate(0, 0, -backfillDays+1), today.AddDate(0, 0, -2)
+		done, err := s.repo.RolledUpDays(from, to)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find rolled up days: %w", err)
+		}
+		rolled := make(map[string]bool, len(done))
+		for _, d := range done {
+			rolled[d.Format(analyticsDateLayout)] = true
+		}
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			if !rolled[d.Format(analyticsDateLayout)] {
+				days = append(days, d)
+			}
+		}
+	}
+
+	for i, d := range days {
+		if err := s.RollupDay(d); err != nil {
+			return i, fmt.Errorf("failed to roll up %s: %w", d.Format(analyticsDateLayout), err)
+		}
+	}
+	return len(days), nil
+}
+
+// analyticsRange parses the range of the query (defaults to the last 30 days up to today)
+func analyticsRange(q AnalyticsQuery, today time.Time) (time.Time, time.Time, error) {
+	to := today
+	if q.To != "" {
+		t, err := time.ParseInLocation(analyticsDateLayout, q.To, today.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidAnalyticsDate
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if q.From != "" {
+		t, err := time.ParseInLocation(analyticsDateLayout, q.From, today.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidAnalyticsDate
+		}
+		from = t
+	}
+	if from.After(to) || !to.Before(from.AddDate(0, 0, maxAnalyticsDays)) {
+		return time.Time{}, time.Time{}, ErrInvalidAnalyticsRange
+	}
+	return from, to, nil
+}
+
+// periodStart returns the first day of the period containing day
+func periodStart(day time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case GranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+// buildAnalytics aggregates the daily rollups into periods and breakdowns
+func buildAnalytics(metrics []models.DailyMetric, from, to time.Time, granularity string, genres []adminrepo.GenreName) *AnalyticsResponse {
+	resp := &AnalyticsResponse{
+		From:         from.Format(analyticsDateLayout),
+		To:           to.Format(analyticsDateLayout),
+		Granularity:  granularity,
+		Series:       []AnalyticsPoint{},
+		PostsByGenre: []GenreCount{},
+		PostsByArea:  []AreaCount{},
+	}
+
+	// 期間ごとの点（データのない期間も0で返す）と、期間の日数
+	index := make(map[string]int)
+	days := make(map[string]int)
+	dayPeriod := make(map[string]string)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		period := periodStart(d, granularity).Format(analyticsDateLayout)
+		if _, ok := index[period]; !ok {
+			index[period] = len(resp.Series)
+			resp.Series = append(resp.Series, AnalyticsPoint{Period: period})
+		}
+		days[period]++
+		dayPeriod[d.Format(analyticsDateLayout)] = period
+	}
+
+	byGenre := make(map[int32]int64)
+	byArea := make(map[string]int64)
+	histogram := make(map[string]int64)
+	for _, m := range metrics {
+		day := m.Date.Format(analyticsDateLayout)
+		period, ok := dayPeriod[day]
+		if !ok {
+			continue
+		}
+		p := &resp.Series[index[period]]
+		switch m.Metric {
+		case models.MetricNewUsers:
+			p.NewUsers += m.Value
+		case models.MetricActiveUsers:
+			p.ActiveUsers += float64(m.Value) / float64(days[period])
+		case models.MetricPosts:
+			p.Posts += m.Value
+		case models.MetricReactions:
+			p.Reactions += m.Value
+		case models.MetricReports:
+			p.Reports += m.Value
+		case models.MetricReportsHandled:
+			p.ReportsHandled += m.Value
+			resp.Moderation.ReportsHandled += m.Value
+		case models.MetricReportBacklog:
+			// metrics は日付順のため、期間（範囲）の最後に集計した日の値が残る
+			p.ReportBacklog = m.Value
+			resp.Moderation.Backlog = m.Value
+		case models.MetricPostsByGenre:
+			if id, err := strconv.Atoi(m.Dimension); err == nil {
+				byGenre[int32(id)] += m.Value
+			}
+		case models.MetricPostsByArea:
+			byArea[m.Dimension] += m.Value
+		case models.MetricHandleTime:
+			histogram[m.Dimension] += m.Value
+		}
+		if resp.RolledUpAt == nil || m.UpdatedAt.After(*resp.RolledUpAt) {
+			updatedAt := m.UpdatedAt
+			resp.RolledUpAt = &updatedAt
+		}
+	}
+	for i := range resp.Series {
+		resp.Series[i].ActiveUsers = math.Round(resp.Series[i].ActiveUsers*10) / 10
+	}
+
+	names := make(map[int32]string, len(genres))
+	for _, g := range genres {
+		names[g.GenreID] = g.GenreName
+	}
+	for id, n := range byGenre {
+		resp.PostsByGenre = append(resp.PostsByGenre, GenreCount{GenreID: id, GenreName: names[id], Count: n})
+	}
+	sort.Slice(resp.PostsByGenre, func(i, j int) bool {
+		a, b := resp.PostsByGenre[i], resp.PostsByGenre[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.GenreID < b.GenreID)
+	})
+	for area, n := range byArea {
+		resp.PostsByArea = append(resp.PostsByArea, AreaCount{Area: area, Count: n})
+	}
+	sort.Slice(resp.PostsByArea, func(i, j int) bool {
+		a, b := resp.PostsByArea[i], resp.PostsByArea[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Area < b.Area)
+	})
+	if len(resp.PostsByArea) > maxAreas {
+		resp.PostsByArea = resp.PostsByArea[:maxAreas]
+	}
+
+	resp.Moderation.MedianHandleHours = medianHandleHours(histogram)
+	return resp
+}
+
+// areaOf returns the grid cell of a place ("lat,lng" of the south-west corner)
+func areaOf(lat, lng *float64) string {
+	if lat == nil || lng == nil {
+		return unknownArea
+	}
+	cell := func(v float64) float64 { return math.Floor(v/areaGridDegrees) * areaGridDegrees }
+	return fmt.Sprintf("%.1f,%.1f", cell(*lat), cell(*lng))
+}
+
+// handleTimeBucket returns the histogram bucket of a time-to-handle
+func handleTimeBucket(d time.Duration) string {
+	minutes := d.Minutes()
+	for _, upper := range handleTimeBuckets {
+		if minutes <= float64(upper) {
+			return strconv.Itoa(upper)
+		}
+	}
+	return "inf"
+}
+
+// medianHandleHours estimates the median time-to-handle from the histogram
+// 中央値を含む区間の中で線形補間します（上限のない区間の場合は区間の下限）
+func medianHandleHours(histogram map[string]int64) *float64 {
+	var total int64
+	for _, n := range histogram {
+		total += n
+	}
+	if total == 0 {
+		return nil
+	}
+
+	target := float64(total) / 2
+	var cumulative int64
+	lower := 0.0
+	for _, upper := range handleTimeBuckets {
+		n := histogram[strconv.Itoa(upper)]
+		if n > 0 && float64(cumulative+n) >= target {
+			minutes := lower + (float64(upper)-lower)*(target-float64(cumulative))/float64(n)
+			hours := math.Round(minutes/60*10) / 10
+			return &hours
+		}
+		cumulative += n
+		lower = float64(upper)
+	}
+	hours := math.Round(lower/60*10) / 10
+	return &hours
+}
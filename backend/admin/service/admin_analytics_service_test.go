@@ -0,0 +1,133 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	adminrepo "kojan-map/admin/repository"
+	"kojan-map/business/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyticsDay(s string) time.Time {
+	t, _ := time.ParseInLocation(analyticsDateLayout, s, time.Local)
+	return t
+}
+
+func TestAnalyticsRange(t *testing.T) {
+	today := analyticsDay("2026-03-31")
+
+	from, to, err := analyticsRange(AnalyticsQuery{}, today)
+	require.NoError(t, err)
+	assert.Equal(t, analyticsDay("2026-03-02"), from)
+	assert.Equal(t, today, to)
+
+	from, to, err = analyticsRange(AnalyticsQuery{From: "2026-01-01", To: "2026-01-31"}, today)
+	require.NoError(t, err)
+	assert.Equal(t, analyticsDay("2026-01-01"), from)
+	assert.Equal(t, analyticsDay("2026-01-31"), to)
+
+	_, _, err = analyticsRange(AnalyticsQuery{From: "2026/01/01"}, today)
+	assert.ErrorIs(t, err, ErrInvalidAnalyticsDate)
+	_, _, err = analyticsRange(AnalyticsQuery{From: "2026-02-01", To: "2026-01-01"}, today)
+	assert.ErrorIs(t, err, ErrInvalidAnalyticsRange)
+	_, _, err = analyticsRange(AnalyticsQuery{From: "2023-01-01"}, today)
+	assert.ErrorIs(t, err, ErrInvalidAnalyticsRange)
+}
+
+func TestAdminAnalyticsService_GetAnalytics_Validation(t *testing.T) {
+	// 入力の検証はDBに接続する前に行う
+	s := NewAdminAnalyticsService(nil, nil)
+	_, err := s.GetAnalytics(AnalyticsQuery{Granularity: "year"})
+	assert.ErrorIs(t, err, ErrInvalidGranularity)
+	_, err = s.GetAnalytics(AnalyticsQuery{To: "tomorrow"})
+	assert.ErrorIs(t, err, ErrInvalidAnalyticsDate)
+}
+
+func TestPeriodStart(t *testing.T) {
+	wed := analyticsDay("2026-03-04")
+	assert.Equal(t, wed, periodStart(wed, GranularityDay))
+	assert.Equal(t, analyticsDay("2026-03-02"), periodStart(wed, GranularityWeek))
+	assert.Equal(t, analyticsDay("2026-03-02"), periodStart(analyticsDay("2026-03-08"), GranularityWeek)) // 日曜は前の月曜から
+	assert.Equal(t, analyticsDay("2026-03-01"), periodStart(wed, GranularityMonth))
+}
+
+func TestBuildAnalytics(t *testing.T) {
+	metric := func(day, name, dim string, v int64) models.DailyMetric {
+		return models.DailyMetric{Date: analyticsDay(day), Metric: name, Dimension: dim, Value: v, UpdatedAt: analyticsDay(day).Add(time.Hour)}
+	}
+	metrics := []models.DailyMetric{
+		metric("2026-03-02", models.MetricActiveUsers, "", 10),
+		metric("2026-03-02", models.MetricPosts, "", 3),
+		metric("2026-03-02", models.MetricPostsByGenre, "1", 2),
+		metric("2026-03-02", models.MetricPostsByGenre, "2", 1),
+		metric("2026-03-02", models.MetricPostsByArea, "33.5,133.5", 3),
+		metric("2026-03-02", models.MetricReportBacklog, "", 4),
+		metric("2026-03-03", models.MetricActiveUsers, "", 20),
+		metric("2026-03-03", models.MetricNewUsers, "", 5),
+		metric("2026-03-03", models.MetricPostsByGenre, "2", 4),
+		metric("2026-03-03", models.MetricReportsHandled, "", 2),
+		metric("2026-03-03", models.MetricHandleTime, "60", 2),
+		metric("2026-03-03", models.MetricReportBacklog, "", 2),
+		metric("2026-03-09", models.MetricReportBacklog, "", 7),
+	}
+	genres := []adminrepo.GenreName{{GenreID: 1, GenreName: "food"}, {GenreID: 2, GenreName: "event"}}
+
+	t.Run("day", func(t *testing.T) {
+		resp := buildAnalytics(metrics, analyticsDay("2026-03-02"), analyticsDay("2026-03-04"), GranularityDay, genres)
+		require.Len(t, resp.Series, 3)
+		assert.Equal(t, "2026-03-02", resp.Series[0].Period)
+		assert.Equal(t, 10.0, resp.Series[0].ActiveUsers)
+		assert.Equal(t, int64(5), resp.Series[1].NewUsers)
+		assert.Equal(t, AnalyticsPoint{Period: "2026-03-04"}, resp.Series[2]) // データのない日も0で返す
+
+		assert.Equal(t, []GenreCount{{GenreID: 2, GenreName: "event", Count: 5}, {GenreID: 1, GenreName: "food", Count: 2}}, resp.PostsByGenre)
+		assert.Equal(t, []AreaCount{{Area: "33.5,133.5", Count: 3}}, resp.PostsByArea)
+		assert.Equal(t, int64(2), resp.Moderation.Backlog)
+		assert.Equal(t, int64(2), resp.Moderation.ReportsHandled)
+		require.NotNil(t, resp.Moderation.MedianHandleHours)
+		require.NotNil(t, resp.RolledUpAt)
+		assert.Equal(t, analyticsDay("2026-03-03").Add(time.Hour), *resp.RolledUpAt)
+	})
+
+	t.Run("week", func(t *testing.T) {
+		resp := buildAnalytics(metrics, analyticsDay("2026-03-02"), analyticsDay("2026-03-15"), GranularityWeek, genres)
+		require.Len(t, resp.Series, 2)
+		// 週のDAUは1日あたりの平均（30 / 7日）
+		assert.Equal(t, 4.3, resp.Series[0].ActiveUsers)
+		assert.Equal(t, int64(2), resp.Series[0].ReportBacklog)
+		assert.Equal(t, int64(7), resp.Series[1].ReportBacklog)
+		assert.Equal(t, int64(7), resp.Moderation.Backlog)
+	})
+}
+
+func TestMedianHandleHours(t *testing.T) {
+	assert.Nil(t, medianHandleHours(nil))
+
+	// 4件すべてが 0〜15分の区間にある場合は区間の中央
+	median := medianHandleHours(map[string]int64{"15": 4})
+	require.NotNil(t, median)
+	assert.Equal(t, 0.1, *median)
+
+	// 中央値は 60〜180分の区間の中央（2件目と3件目の間）
+	median = medianHandleHours(map[string]int64{"60": 1, "180": 2, "1440": 1})
+	require.NotNil(t, median)
+	assert.Equal(t, 2.0, *median)
+
+	// 上限のない区間の場合は区間の下限（30日）
+	median = medianHandleHours(map[string]int64{"inf": 3})
+	require.NotNil(t, median)
+	assert.Equal(t, 720.0, *median)
+}
+
+func TestHandleTimeBucketAndArea(t *testing.T) {
+	assert.Equal(t, "15", handleTimeBucket(10*time.Minute))
+	assert.Equal(t, "60", handleTimeBucket(time.Hour))
+	assert.Equal(t, "inf", handleTimeBucket(60*24*time.Hour))
+
+	lat, lng := 33.5597, 133.5311
+	assert.Equal(t, "33.5,133.5", areaOf(&lat, &lng))
+	assert.Equal(t, unknownArea, areaOf(nil, nil))
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	adminrepo "kojan-map/admin/repository"
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// Audit log errors
+var (
+	ErrInvalidAuditRange = errors.New("from must be before to")
+)
+
+// maxAuditExportRows は CSV で出力する監査ログの上限件数です。
+const maxAuditExportRows = 10000
+
+// AuditActor identifies the admin who performed an action and the request it came from.
+type AuditActor struct {
+	GoogleID  string
+	RequestID string
+	IPAddress string
+}
+
+// AuditLogQuery represents the filters of the audit log list.
+type AuditLogQuery struct {
+	ActorGoogleID string
+	Action        string
+	TargetType    string
+	TargetID      string
+	From          *time.Time
+	To            *time.Time
+	Page          int
+	PageSize      int
+}
+
+// AuditLogListResponse represents the paginated audit log list response
+type AuditLogListResponse struct {
+	Logs     []models.AdminAuditLog `json:"logs"`
+	Total    int64                  `json:"total"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"pageSize"`
+}
+
+// AdminAuditService lets admins search and export the audit log of admin actions.
+type AdminAuditService struct {
+	repo *adminrepo.AuditLogRepository
+}
+
+// NewAdminAuditService creates a new AdminAuditService.
+//
+// Parameters:
+//   - repo: 監査ログのリポジトリ
+//
+// Returns:
+//   - *AdminAuditService: 新しいサービスインスタンス
+func NewAdminAuditService(repo *adminrepo.AuditLogRepository) *AdminAuditService {
+	return &AdminAuditService{repo: repo}
+}
+
+// GetLogs retrieves audit logs matching the query, newest first.
+//
+// Parameters:
+//   - q: 絞り込み条件とページ番号
+//
+// Returns:
+//   - *AuditLogListResponse: 監査ログ一覧
+//   - error: ErrInvalidAuditRange またはDBエラー
+func (s *AdminAuditService) GetLogs(q AuditLogQuery) (*AuditLogListResponse, error) {
+	filter, err := q.filter()
+	if err != nil {
+		return nil, err
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 || q.PageSize > 100 {
+		q.PageSize = 20
+	}
+
+	logs, total, err := s.repo.FindAll(filter, q.Page, q.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+	return &AuditLogListResponse{
+		Logs:     logs,
+		Total:    total,
+		Page:     q.Page,
+		PageSize: q.PageSize,
+	}, nil
+}
+
+// ExportCSV writes audit logs matching the query to w as CSV, newest first.
+// ページ指定は無視し、最大 maxAuditExportRows 件を出力します。
+//
+// Parameters:
+//   - w: 出力先
+//   - q: 絞り込み条件
+//
+// Returns:
+//   - error: ErrInvalidAuditRange、DBエラーまたは書き込みエラー
+func (s *AdminAuditService) ExportCSV(w io.Writer, q AuditLogQuery) error {
+	filter, err := q.filter()
+	if err != nil {
+		return err
+	}
+
+	logs, _, err := s.repo.FindAll(filter, 1, maxAuditExportRows)
+	if err != nil {
+		return fmt.Errorf("failed to get audit logs: %w", err)
+	}
+	return writeAuditCSV(w, logs)
+}
+
+// filter converts the query to the repository filter.
+func (q AuditLogQuery) filter() (adminrepo.AuditLogFilter, error) {
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return adminrepo.AuditLogFilter{}, ErrInvalidAuditRange
+	}
+	return adminrepo.AuditLogFilter{
+		ActorGoogleID: q.ActorGoogleID,
+		Action:        q.Action,
+		TargetType:    q.TargetType,
+		TargetID:      q.TargetID,
+		From:          q.From,
+		To:            q.To,
+	}, nil
+}
+
+// writeAuditCSV writes the header and one row per audit log.
+func writeAuditCSV(w io.Writer, logs []models.AdminAuditLog) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"auditId", "createdAt", "actorGoogleId", "action", "targetType", "targetId", "before", "after", "requestId", "ipAddress"}); err != nil {
+		return err
+	}
+	for _, l := range logs {
+		if err := cw.Write(csvSafeRecord([]string{
+			strconv.FormatInt(l.ID, 10),
+			l.CreatedAt.Format(time.RFC3339),
+			l.ActorGoogleID,
+			l.Action,
+			l.TargetType,
+			l.TargetID,
+			derefString(l.Before),
+			derefString(l.After),
+			l.RequestID,
+			l.IPAddress,
+		})); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// recordAudit は管理者操作の監査ログを、操作と同じトランザクション tx で記録します。
+// before・after は操作前後の対象のスナップショットで、nil の場合は記録しません。
+func recordAudit(tx *gorm.DB, actor AuditActor, action, targetType, targetID string, before, after interface{}) error {
+	if actor.GoogleID == "" {
+		return errors.New("audit actor is required")
+	}
+
+	entry := &models.AdminAuditLog{
+		ActorGoogleID: actor.GoogleID,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		RequestID:     actor.RequestID,
+		IPAddress:     actor.IPAddress,
+		CreatedAt:     time.Now(),
+	}
+	var err error
+	if entry.Before, err = auditSnapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = auditSnapshot(after); err != nil {
+		return err
+	}
+
+	if err := adminrepo.NewAuditLogRepository(tx).Create(entry); err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+	return nil
+}
+
+// auditSnapshot は対象をJSONに変換します（nil の場合は nil）。
+func auditSnapshot(v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	s := string(b)
+	return &s, nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
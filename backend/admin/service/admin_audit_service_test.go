@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"kojan-map/business/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogQuery_filter(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("copies filters", func(t *testing.T) {
+		f, err := AuditLogQuery{ActorGoogleID: "admin-1", Action: models.AuditActionPostDelete, TargetType: models.AuditTargetPost, TargetID: "42", From: &from, To: &to}.filter()
+		require.NoError(t, err)
+		assert.Equal(t, "admin-1", f.ActorGoogleID)
+		assert.Equal(t, models.AuditActionPostDelete, f.Action)
+		assert.Equal(t, "42", f.TargetID)
+		assert.Equal(t, &from, f.From)
+	})
+
+	t.Run("rejects empty or reversed range", func(t *testing.T) {
+		_, err := AuditLogQuery{From: &to, To: &from}.filter()
+		assert.ErrorIs(t, err, ErrInvalidAuditRange)
+		_, err = AuditLogQuery{From: &from, To: &from}.filter()
+		assert.ErrorIs(t, err, ErrInvalidAuditRange)
+	})
+}
+
+func TestAuditSnapshot(t *testing.T) {
+	s, err := auditSnapshot(nil)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = auditSnapshot(models.Ask{AskID: 3, Subject: "件名"})
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Contains(t, *s, `"askId":3`)
+}
+
+func TestRecordAudit_RequiresActor(t *testing.T) {
+	err := recordAudit(nil, AuditActor{}, models.AuditActionUserDelete, models.AuditTargetUser, "user-1", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestWriteAuditCSV(t *testing.T) {
+	before := `{"title":"a, \"quoted\""}`
+	logs := []models.AdminAuditLog{{
+		ID:            7,
+		ActorGoogleID: "admin-1",
+		Action:        models.AuditActionPostDelete,
+		TargetType:    models.AuditTargetPost,
+		TargetID:      "42",
+		Before:        &before,
+		RequestID:     "req-1",
+		IPAddress:     "192.0.2.1",
+		CreatedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeAuditCSV(&buf, logs))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "auditId", rows[0][0])
+	assert.Equal(t, []string{"7", "2026-01-02T03:04:05Z", "admin-1", "post.delete", "post", "42", before, "", "req-1", "192.0.2.1"}, rows[1])
+}
@@ -2,26 +2,114 @@ package service
 
 import (
 	"errors"
-	"log"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	adminrepo "kojan-map/admin/repository"
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/outbox"
 	sharedrepo "kojan-map/shared/repository"
 
 	"gorm.io/gorm"
 )
 
+// Business application errors
+var (
+	ErrInvalidApplicationStatus    = errors.New("status must be one of pending, needs_info, approved, rejected")
+	ErrApplicationNotFound         = errors.New("application not found")
+	ErrApplicationAlreadyProcessed = errors.New("application is already processed")
+	ErrApplicationAwaitingInfo     = errors.New("application is waiting for more information from the applicant")
+	ErrApplicationDocumentNotFound = errors.New("document not found")
+	ErrInfoRequestRequired         = errors.New("message is required")
+	ErrInfoRequestTooLong          = errors.New("message is too long")
+	ErrApplicantNotFound           = errors.New("applicant not found")
+	ErrApplicantAlreadyBusiness    = errors.New("applicant already has a business account")
+	ErrInvalidKanaBusinessName     = errors.New("kanaBusinessName must be 1-50 full-width katakana characters")
+	ErrInvalidZipCode              = errors.New("zipCode must be 7 digits")
+	ErrBusinessPlaceRequired       = errors.New("specify either placeId or both latitude and longitude")
+	ErrBusinessPlaceNotFound       = errors.New("place not found")
+	ErrRejectReasonTooLong         = errors.New("reason is too long")
+)
+
+// maxRejectReasonLength is the maximum length of the reason sent to a rejected applicant
+const maxRejectReasonLength = 1000
+
+// maxInfoRequestLength is the maximum length of the information requested from an applicant
+const maxInfoRequestLength = 1000
+
+// ApproveApplicationRequest represents the details an admin confirms when approving an application
+// 場所は既存の placeId か、住所の緯度・経度（近い場所があればそれを使い、なければ作成）のどちらかを指定します
+type ApproveApplicationRequest struct {
+	KanaBusinessName string   `json:"kanaBusinessName"` // 全角カタカナ（申請の事業者名は写さない）
+	ZipCode          string   `json:"zipCode"`          // 任意。7桁（ハイフンは除く）
+	PlaceID          *int32   `json:"placeId"`
+	Latitude         *float64 `json:"latitude"`
+	Longitude        *float64 `json:"longitude"`
+}
+
+// RejectApplicationRequest represents the reason sent to a rejected applicant (任意)
+type RejectApplicationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RequestApplicationInfoRequest represents the information an admin asks the applicant to add
+type RequestApplicationInfoRequest struct {
+	Message string `json:"message"`
+}
+
+// ApplicationDetail represents a business application with its documents, status history and possible duplicates
+type ApplicationDetail struct {
+	BusinessApplicationResponse
+	UpdatedAt  *time.Time                       `json:"updatedAt,omitempty"`
+	Documents  []models.BusinessRequestDocument `json:"documents"`  // 書類の内容は含めない
+	History    []models.BusinessRequestHistory  `json:"history"`    // 古い順
+	Duplicates []BusinessApplicationResponse    `json:"duplicates"` // 申請者・電話番号・法人番号のいずれかが同じ他の申請
+}
+
+// applicationSortColumns maps the sort keys of the application list to SQL
+var applicationSortColumns = map[string]string{
+	"createdAt":    "businessReq.createdAt",
+	"businessName": "businessReq.name",
+	"status":       "businessReq.status",
+}
+
+// ApplicationQuery represents the filters and order of the business application list
+type ApplicationQuery struct {
+	Status   string     // pending, needs_info, approved, rejected
+	Query    string     // 事業者名・住所・電話番号・申請者のメールアドレスの部分一致、または申請者の googleId
+	From     *time.Time // この日時以降の申請（含む）
+	To       *time.Time // この日時より前の申請（含まない）
+	Sort     string     // createdAt（既定）, businessName, status
+	Order    string     // asc, desc（既定）
+	Page     int
+	PageSize int
+}
+
+// ApplicationListResponse represents the paginated business application list response
+type ApplicationListResponse struct {
+	Applications []BusinessApplicationResponse `json:"applications"`
+	Total        int                           `json:"total"`
+	Page         int                           `json:"page"`
+	PageSize     int                           `json:"pageSize"`
+}
+
 // BusinessApplicationResponse represents a business application with user info
 type BusinessApplicationResponse struct {
-	RequestID      int32  `json:"requestId"`
-	BusinessName   string `json:"businessName"`
-	ApplicantName  string `json:"applicantName"`
-	ApplicantEmail string `json:"applicantEmail"`
-	Status         string `json:"status"`
-	Address        string `json:"address"`
-	Phone          string `json:"phone"`
-	CreatedAt      string `json:"createdAt"`
+	RequestID       int32  `json:"requestId"`
+	BusinessName    string `json:"businessName"`
+	UserID          string `json:"userId"`
+	ApplicantName   string `json:"applicantName"`
+	ApplicantEmail  string `json:"applicantEmail"`
+	Status          string `json:"status"`
+	Address         string `json:"address"`
+	Phone           string `json:"phone"`
+	CorporateNumber string `json:"corporateNumber,omitempty"`
+	CreatedAt       string `json:"createdAt"`
 }
 
 // AdminBusinessService handles admin business application management
@@ -47,96 +135,421 @@ func NewAdminBusinessService(
 	}
 }
 
-// GetApplications retrieves all business applications
-func (s *AdminBusinessService) GetApplications() ([]BusinessApplicationResponse, error) {
-	requests, err := s.requestRepo.FindAll()
+// GetApplications retrieves business applications matching the query with the applicant's email
+func (s *AdminBusinessService) GetApplications(q ApplicationQuery) (*ApplicationListResponse, error) {
+	filter, order, err := q.filter()
 	if err != nil {
 		return nil, err
 	}
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
 
-	var responses []BusinessApplicationResponse
-	for _, req := range requests {
-		user, err := s.userRepo.FindByGoogleID(req.UserID)
-		if err != nil {
-			log.Printf("Failed to find user %s: %v", req.UserID, err)
-			// Continue processing other requests
-		}
-		applicantName := ""
-		applicantEmail := ""
-		if user != nil {
-			applicantEmail = user.Gmail
-			// Gmail prefix as name (簡易的な実装)
-			applicantName = user.Gmail
-		}
-
-		responses = append(responses, BusinessApplicationResponse{
-			RequestID:      req.RequestID,
-			BusinessName:   req.Name,
-			ApplicantName:  applicantName,
-			ApplicantEmail: applicantEmail,
-			Status:         req.Status,
-			Address:        req.Address,
-			Phone:          req.Phone,
-			CreatedAt:      req.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	requests, total, err := s.requestRepo.FindAllPaginated(filter, order, q.Page, q.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applications: %w", err)
+	}
+
+	responses := make([]BusinessApplicationResponse, len(requests))
+	for i := range requests {
+		responses[i] = applicationResponse(&requests[i])
+	}
+	return &ApplicationListResponse{
+		Applications: responses,
+		Total:        total,
+		Page:         q.Page,
+		PageSize:     q.PageSize,
+	}, nil
+}
+
+// ExportApplicationsCSV writes every business application matching the query to w as CSV, streaming rows as they are read
+func (s *AdminBusinessService) ExportApplicationsCSV(w io.Writer, q ApplicationQuery) error {
+	filter, order, err := q.filter()
+	if err != nil {
+		return err
+	}
+
+	out, err := newCSVStream(w, []string{"requestId", "createdAt", "status", "businessName", "address", "phone", "corporateNumber", "userId", "applicantEmail"})
+	if err != nil {
+		return err
+	}
+	if err := s.requestRepo.Each(filter, order, func(r *adminrepo.BusinessRequestRow) error {
+		return out.Write([]string{
+			strconv.Itoa(int(r.RequestID)),
+			r.CreatedAt.Format(time.RFC3339),
+			r.Status,
+			r.Name,
+			r.Address,
+			r.Phone,
+			derefString(r.CorporateNumber),
+			r.UserID,
+			r.ApplicantEmail,
 		})
+	}); err != nil {
+		return fmt.Errorf("failed to export applications: %w", err)
+	}
+	return out.Flush()
+}
+
+// filter validates the query and converts it to the repository filter and order
+func (q ApplicationQuery) filter() (adminrepo.BusinessRequestFilter, string, error) {
+	switch q.Status {
+	case "", models.BusinessRequestPending, models.BusinessRequestNeedsInfo, models.BusinessRequestApproved, models.BusinessRequestRejected:
+	default:
+		return adminrepo.BusinessRequestFilter{}, "", ErrInvalidApplicationStatus
+	}
+	if !validRange(q.From, q.To) {
+		return adminrepo.BusinessRequestFilter{}, "", ErrInvalidListRange
 	}
+	order, err := orderBy(q.Sort, q.Order, applicationSortColumns, "createdAt", "businessReq.requestId DESC")
+	if err != nil {
+		return adminrepo.BusinessRequestFilter{}, "", err
+	}
+	return adminrepo.BusinessRequestFilter{
+		Status: q.Status,
+		Query:  strings.TrimSpace(q.Query),
+		From:   q.From,
+		To:     q.To,
+	}, order, nil
+}
 
-	return responses, nil
+// applicationResponse converts a business application row to the response
+func applicationResponse(r *adminrepo.BusinessRequestRow) BusinessApplicationResponse {
+	return BusinessApplicationResponse{
+		RequestID:    r.RequestID,
+		BusinessName: r.Name,
+		UserID:       r.UserID,
+		// Gmail prefix as name (簡易的な実装)
+		ApplicantName:   r.ApplicantEmail,
+		ApplicantEmail:  r.ApplicantEmail,
+		Status:          r.Status,
+		Address:         r.Address,
+		Phone:           r.Phone,
+		CorporateNumber: derefString(r.CorporateNumber),
+		CreatedAt:       r.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
 }
 
-// ApproveApplication approves a business application
-func (s *AdminBusinessService) ApproveApplication(id int32) error {
+// GetApplication retrieves a business application with its documents, status history and possible duplicates
+func (s *AdminBusinessService) GetApplication(id int32) (*ApplicationDetail, error) {
 	request, err := s.requestRepo.FindByID(id)
 	if err != nil {
-		return errors.New("application not found")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	row := adminrepo.BusinessRequestRow{BusinessRequest: *request}
+	row.ApplicantEmail = userEmails(s.db, []string{request.UserID})[request.UserID]
+
+	documents, err := s.requestRepo.FindDocuments(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application documents: %w", err)
+	}
+	history, err := s.requestRepo.FindHistory(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application history: %w", err)
+	}
+	duplicates, err := s.requestRepo.FindDuplicates(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate applications: %w", err)
+	}
+
+	detail := &ApplicationDetail{
+		BusinessApplicationResponse: applicationResponse(&row),
+		UpdatedAt:                   request.UpdatedAt,
+		Documents:                   documents,
+		History:                     history,
+		Duplicates:                  make([]BusinessApplicationResponse, len(duplicates)),
 	}
+	for i := range duplicates {
+		detail.Duplicates[i] = applicationResponse(&duplicates[i])
+	}
+	return detail, nil
+}
 
-	if request.Status != "pending" {
-		return errors.New("application is already processed")
+// GetApplicationDocument retrieves a document attached to a business application including its content
+func (s *AdminBusinessService) GetApplicationDocument(id int32, documentID int64) (*models.BusinessRequestDocument, error) {
+	document, err := s.requestRepo.FindDocument(id, documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to get application document: %w", err)
 	}
+	return document, nil
+}
 
-	// Verify user exists before creating business member
-	_, err = s.userRepo.FindByGoogleID(request.UserID)
+// ApproveApplication approves a business application
+// 申請者のロールを business に変更し、全モジュールが読む business テーブルに事業者情報を作成して、申請者にメールで通知します（すべて1つのトランザクション）
+// ロールの変更はログイン中の申請者には次のトークン更新から反映されます
+func (s *AdminBusinessService) ApproveApplication(id int32, req ApproveApplicationRequest, actor AuditActor) (*models.BusinessMember, error) {
+	kana, zipCode, err := req.normalize()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Use transaction to ensure data consistency
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		// Update request status to approved
-		if err := s.requestRepo.UpdateStatus(id, "approved"); err != nil {
+	var member *models.BusinessMember
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		requestRepo := s.requestRepo.WithTx(tx)
+		request, err := s.findActiveForUpdate(requestRepo, id)
+		if err != nil {
+			return err
+		}
+		if request.Status == models.BusinessRequestNeedsInfo {
+			return ErrApplicationAwaitingInfo
+		}
+
+		userRepo := s.userRepo.WithTx(tx)
+		user, err := userRepo.FindByGoogleID(request.UserID)
+		if err != nil || user.DeletedAt != nil {
+			return ErrApplicantNotFound
+		}
+		memberRepo := s.businessMemberRepo.WithTx(tx)
+		exists, err := memberRepo.ExistsByUserID(request.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to check business account: %w", err)
+		}
+		if exists || user.Role == models.RoleBusiness {
+			return ErrApplicantAlreadyBusiness
+		}
+
+		place, err := req.place(adminrepo.NewPlaceRepository(tx))
+		if err != nil {
 			return err
 		}
 
-		// Create business member entry
-		businessMember := &models.BusinessMember{
+		member = &models.BusinessMember{
 			BusinessName:     request.Name,
-			KanaBusinessName: request.Name, // Simplified
+			KanaBusinessName: kana,
+			ZipCode:          zipCode,
 			Address:          request.Address,
 			Phone:            request.Phone,
 			UserID:           request.UserID,
-			PlaceID:          nil, // To be set later when place is created
+			PlaceID:          place.PlaceID,
 			RegistDate:       time.Now(),
 		}
+		if err := memberRepo.Create(member); err != nil {
+			return fmt.Errorf("failed to create business account: %w", err)
+		}
+		// 管理者のロールは変更しない
+		if user.Role == models.RoleUser {
+			if err := userRepo.UpdateRole(request.UserID, models.RoleBusiness); err != nil {
+				return fmt.Errorf("failed to promote applicant: %w", err)
+			}
+		}
+		history, err := s.transition(requestRepo, request, models.BusinessRequestApproved, actor, "")
+		if err != nil {
+			return err
+		}
+		if err := notifyApplicationDecision(tx, request, history, notification.KindApplicationApproved, ""); err != nil {
+			return err
+		}
+		return s.recordApplication(tx, requestRepo, actor, models.AuditActionApplicationApprove, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
 
-		if err := s.businessMemberRepo.Create(businessMember); err != nil {
+// RejectApplication rejects a business application and notifies the applicant with the reason
+// 追加情報の依頼中の申請も却下できます
+func (s *AdminBusinessService) RejectApplication(id int32, req RejectApplicationRequest, actor AuditActor) error {
+	reason := strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(reason) > maxRejectReasonLength {
+		return ErrRejectReasonTooLong
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		requestRepo := s.requestRepo.WithTx(tx)
+		request, err := s.findActiveForUpdate(requestRepo, id)
+		if err != nil {
 			return err
 		}
 
-		return nil
+		history, err := s.transition(requestRepo, request, models.BusinessRequestRejected, actor, reason)
+		if err != nil {
+			return err
+		}
+		if err := notifyApplicationDecision(tx, request, history, notification.KindApplicationRejected, reason); err != nil {
+			return err
+		}
+		return s.recordApplication(tx, requestRepo, actor, models.AuditActionApplicationReject, request)
 	})
 }
 
-// RejectApplication rejects a business application
-func (s *AdminBusinessService) RejectApplication(id int32) error {
-	request, err := s.requestRepo.FindByID(id)
+// RequestApplicationInfo sends a pending application back to the applicant with a request for more information
+// 申請者が内容を修正・書類を添付して再提出すると審査待ちに戻ります
+func (s *AdminBusinessService) RequestApplicationInfo(id int32, req RequestApplicationInfoRequest, actor AuditActor) error {
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return ErrInfoRequestRequired
+	}
+	if utf8.RuneCountInString(message) > maxInfoRequestLength {
+		return ErrInfoRequestTooLong
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		requestRepo := s.requestRepo.WithTx(tx)
+		request, err := s.findActiveForUpdate(requestRepo, id)
+		if err != nil {
+			return err
+		}
+		if request.Status == models.BusinessRequestNeedsInfo {
+			return ErrApplicationAwaitingInfo
+		}
+
+		history, err := s.transition(requestRepo, request, models.BusinessRequestNeedsInfo, actor, message)
+		if err != nil {
+			return err
+		}
+		if err := notifyApplicationInfo(tx, request, history, message); err != nil {
+			return err
+		}
+		return s.recordApplication(tx, requestRepo, actor, models.AuditActionApplicationInfo, request)
+	})
+}
+
+// transition changes the status of a locked application and records the change in its history
+func (s *AdminBusinessService) transition(requestRepo *adminrepo.BusinessRequestRepository, request *models.BusinessRequest, to string, actor AuditActor, note string) (*models.BusinessRequestHistory, error) {
+	if err := requestRepo.UpdateStatus(request.RequestID, to, time.Now()); err != nil {
+		return nil, err
+	}
+	history := &models.BusinessRequestHistory{
+		RequestID:  request.RequestID,
+		FromStatus: request.Status,
+		ToStatus:   to,
+		ActorID:    actor.GoogleID,
+		Note:       note,
+	}
+	if err := requestRepo.CreateHistory(history); err != nil {
+		return nil, fmt.Errorf("failed to record application history: %w", err)
+	}
+	return history, nil
+}
+
+// findActiveForUpdate locks an application under review (pending or needs_info) until the end of the transaction
+func (s *AdminBusinessService) findActiveForUpdate(requestRepo *adminrepo.BusinessRequestRepository, id int32) (*models.BusinessRequest, error) {
+	request, err := requestRepo.FindByIDForUpdate(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	if !request.IsActive() {
+		return nil, ErrApplicationAlreadyProcessed
+	}
+	return request, nil
+}
+
+// normalize validates the request and returns the kana name and the zip code without a hyphen
+func (r ApproveApplicationRequest) normalize() (string, string, error) {
+	kana := strings.TrimSpace(r.KanaBusinessName)
+	if !isKatakanaName(kana) {
+		return "", "", ErrInvalidKanaBusinessName
+	}
+	zipCode := strings.ReplaceAll(strings.TrimSpace(r.ZipCode), "-", "")
+	if zipCode != "" && !isDigits(zipCode, 7) {
+		return "", "", ErrInvalidZipCode
+	}
+	if r.PlaceID == nil {
+		if r.Latitude == nil || r.Longitude == nil || !validCoordinates(*r.Latitude, *r.Longitude) {
+			return "", "", ErrBusinessPlaceRequired
+		}
+	}
+	return kana, zipCode, nil
+}
+
+// place returns the place given by placeId, or finds or creates the place at the coordinates
+func (r ApproveApplicationRequest) place(placeRepo *adminrepo.PlaceRepository) (*models.Place, error) {
+	if r.PlaceID != nil {
+		place, err := placeRepo.FindByID(*r.PlaceID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBusinessPlaceNotFound
+		}
+		return place, err
+	}
+	place, err := placeRepo.FindOrCreate(*r.Latitude, *r.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create place: %w", err)
+	}
+	return place, nil
+}
+
+// isKatakanaName reports whether s is 1-50 full-width katakana (長音符・中黒・スペースを含む)
+func isKatakanaName(s string) bool {
+	n := utf8.RuneCountInString(s)
+	if n == 0 || n > 50 {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'ァ' && r <= 'ヺ', r == 'ー', r == '・', r == ' ', r == '　':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s consists of exactly n ASCII digits
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validCoordinates reports whether the latitude and longitude are in range
+func validCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// notifyApplicationDecision は申請の承認・却下を申請者にメールで通知します（tx 内で呼び出す）。
+// 却下後に再提出して承認された場合も通知されるよう、冪等キーには状態の変更履歴の ID を使います。
+func notifyApplicationDecision(tx *gorm.DB, request *models.BusinessRequest, history *models.BusinessRequestHistory, kind notification.Kind, reason string) error {
+	to := userEmails(tx, []string{request.UserID})[request.UserID]
+	if to == "" {
+		return nil
+	}
+	msg, err := outbox.NewEmail(fmt.Sprintf("application-decision:%d", history.HistoryID), notification.Message{
+		Kind: kind,
+		To:   to,
+		Data: notification.ApplicationDecisionData{BusinessName: request.Name, Reason: reason},
+	})
 	if err != nil {
-		return errors.New("application not found")
+		return err
 	}
+	return outbox.Enqueue(tx, msg)
+}
 
-	if request.Status != "pending" {
-		return errors.New("application is already processed")
+// notifyApplicationInfo は申請者に追加情報の依頼をメールで通知します（tx 内で呼び出す）。
+func notifyApplicationInfo(tx *gorm.DB, request *models.BusinessRequest, history *models.BusinessRequestHistory, message string) error {
+	to := userEmails(tx, []string{request.UserID})[request.UserID]
+	if to == "" {
+		return nil
+	}
+	msg, err := outbox.NewEmail(fmt.Sprintf("application-info:%d", history.HistoryID), notification.Message{
+		Kind: notification.KindApplicationInfo,
+		To:   to,
+		Data: notification.ApplicationInfoData{BusinessName: request.Name, Request: message},
+	})
+	if err != nil {
+		return err
 	}
+	return outbox.Enqueue(tx, msg)
+}
 
-	return s.requestRepo.UpdateStatus(id, "rejected")
+// recordApplication records the application before and after the action in the audit log
+func (s *AdminBusinessService) recordApplication(tx *gorm.DB, requestRepo *adminrepo.BusinessRequestRepository, actor AuditActor, action string, before *models.BusinessRequest) error {
+	after, err := requestRepo.FindByID(before.RequestID)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, actor, action, models.AuditTargetApplication, strconv.Itoa(int(before.RequestID)), before, after)
 }
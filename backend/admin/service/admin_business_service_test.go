@@ -1,7 +1,12 @@
 package service
 
 import (
+	"io"
+	"strings"
 	"testing"
+	"time"
+
+	"kojan-map/business/pkg/models"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,3 +53,88 @@ func TestBusinessApplicationResponse(t *testing.T) {
 		assert.Equal(t, "pending", response.Status)
 	})
 }
+
+func TestAdminBusinessService_GetApplicationsValidation(t *testing.T) {
+	svc := NewAdminBusinessService(nil, nil, nil, nil)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name  string
+		query ApplicationQuery
+		want  error
+	}{
+		{"unknown status", ApplicationQuery{Status: "archived"}, ErrInvalidApplicationStatus},
+		{"empty range", ApplicationQuery{From: &from, To: &from}, ErrInvalidListRange},
+		{"unknown sort", ApplicationQuery{Sort: "phone"}, ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetApplications(tt.query)
+			assert.ErrorIs(t, err, tt.want)
+			assert.ErrorIs(t, svc.ExportApplicationsCSV(io.Discard, tt.query), tt.want)
+		})
+	}
+}
+
+func TestAdminBusinessService_ApproveApplicationValidation(t *testing.T) {
+	svc := NewAdminBusinessService(nil, nil, nil, nil)
+	placeID := int32(3)
+	lat, lng := 35.6812, 139.7671
+	badLat := 91.0
+
+	tests := []struct {
+		name string
+		req  ApproveApplicationRequest
+		want error
+	}{
+		{"missing kana", ApproveApplicationRequest{PlaceID: &placeID}, ErrInvalidKanaBusinessName},
+		{"kanji name copied as kana", ApproveApplicationRequest{KanaBusinessName: "小鳩珈琲", PlaceID: &placeID}, ErrInvalidKanaBusinessName},
+		{"hiragana", ApproveApplicationRequest{KanaBusinessName: "こばとこーひー", PlaceID: &placeID}, ErrInvalidKanaBusinessName},
+		{"too long kana", ApproveApplicationRequest{KanaBusinessName: strings.Repeat("ア", 51), PlaceID: &placeID}, ErrInvalidKanaBusinessName},
+		{"short zip code", ApproveApplicationRequest{KanaBusinessName: "コバトコーヒー", ZipCode: "123-456", PlaceID: &placeID}, ErrInvalidZipCode},
+		{"no place", ApproveApplicationRequest{KanaBusinessName: "コバトコーヒー"}, ErrBusinessPlaceRequired},
+		{"latitude only", ApproveApplicationRequest{KanaBusinessName: "コバトコーヒー", Latitude: &lat}, ErrBusinessPlaceRequired},
+		{"out of range", ApproveApplicationRequest{KanaBusinessName: "コバトコーヒー", Latitude: &badLat, Longitude: &lng}, ErrBusinessPlaceRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ApproveApplication(1, tt.req, AuditActor{GoogleID: "admin"})
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestApproveApplicationRequest_Normalize(t *testing.T) {
+	lat, lng := 35.6812, 139.7671
+	kana, zipCode, err := ApproveApplicationRequest{
+		KanaBusinessName: " コバト・コーヒー　ホンテン ",
+		ZipCode:          "100-0005",
+		Latitude:         &lat,
+		Longitude:        &lng,
+	}.normalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "コバト・コーヒー　ホンテン", kana)
+	assert.Equal(t, "1000005", zipCode)
+}
+
+func TestAdminBusinessService_RejectApplicationValidation(t *testing.T) {
+	svc := NewAdminBusinessService(nil, nil, nil, nil)
+	err := svc.RejectApplication(1, RejectApplicationRequest{Reason: strings.Repeat("理", maxRejectReasonLength+1)}, AuditActor{GoogleID: "admin"})
+	assert.ErrorIs(t, err, ErrRejectReasonTooLong)
+}
+
+func TestAdminBusinessService_RequestApplicationInfoValidation(t *testing.T) {
+	svc := NewAdminBusinessService(nil, nil, nil, nil)
+
+	err := svc.RequestApplicationInfo(1, RequestApplicationInfoRequest{Message: " \n"}, AuditActor{GoogleID: "admin"})
+	assert.ErrorIs(t, err, ErrInfoRequestRequired)
+
+	err = svc.RequestApplicationInfo(1, RequestApplicationInfoRequest{Message: strings.Repeat("書", maxInfoRequestLength+1)}, AuditActor{GoogleID: "admin"})
+	assert.ErrorIs(t, err, ErrInfoRequestTooLong)
+}
+
+func TestApplicationQuery_FilterNeedsInfo(t *testing.T) {
+	filter, _, err := ApplicationQuery{Status: models.BusinessRequestNeedsInfo}.filter()
+	assert.NoError(t, err)
+	assert.Equal(t, models.BusinessRequestNeedsInfo, filter.Status)
+}
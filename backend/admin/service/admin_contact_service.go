@@ -2,48 +2,332 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	adminrepo "kojan-map/admin/repository"
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/outbox"
+	sharedrepo "kojan-map/shared/repository"
+
+	"gorm.io/gorm"
+)
+
+// Inquiry errors
+var (
+	ErrInvalidInquiryStatus   = errors.New("status must be one of open, waiting, closed")
+	ErrInquiryNotFound        = errors.New("inquiry not found")
+	ErrInquiryAlreadyClosed   = errors.New("inquiry is already closed")
+	ErrInquiryMessageRequired = errors.New("body is required")
+	ErrInquiryMessageTooLong  = errors.New("body is too long")
+	ErrInvalidInquiryAssignee = errors.New("assignee must be an admin user")
 )
 
+// maxInquiryMessageLength is the maximum length of a reply or an internal note
+const maxInquiryMessageLength = 5000
+
+// inquirySortColumns maps the sort keys of the inquiry list to SQL
+var inquirySortColumns = map[string]string{
+	"date":    "ask.date",
+	"subject": "ask.subject",
+	"status":  "ask.status",
+}
+
+// InquiryQuery represents the filters and order of the inquiry list
+type InquiryQuery struct {
+	Status     string     // open, waiting, closed
+	AssigneeID string     // 担当の管理者の googleId（none の場合は担当なし）
+	Query      string     // 件名・本文・送信者のメールアドレスの部分一致、または送信者の googleId
+	From       *time.Time // この日時以降のお問い合わせ（含む）
+	To         *time.Time // この日時より前のお問い合わせ（含まない）
+	Sort       string     // date（既定）, subject, status, updatedAt
+	Order      string     // asc, desc（既定）
+	Page       int
+	PageSize   int
+}
+
+// InquiryDetail represents an inquiry with the sender's email and the whole thread
+type InquiryDetail struct {
+	models.Ask
+	UserEmail string              `json:"userEmail"`
+	Messages  []models.AskMessage `json:"messages"` // 古い順（管理者間のメモを含む）
+}
+
+// InquiryMessageRequest represents a reply to the user or an internal note
+type InquiryMessageRequest struct {
+	Body     string `json:"body"`
+	Internal bool   `json:"internal"` // true の場合は管理者間のメモ（ユーザーには送らない）
+}
+
+// InquiryStatusRequest represents a status change of an inquiry
+type InquiryStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// InquiryAssigneeRequest represents an assignment of an inquiry (空の場合は担当なし)
+type InquiryAssigneeRequest struct {
+	AssigneeID string `json:"assigneeId"`
+}
+
+// InquiryListResponse represents the paginated inquiry list response
+type InquiryListResponse struct {
+	Asks     []adminrepo.AskRow `json:"asks"`
+	Total    int                `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"pageSize"`
+}
+
 // AdminContactService handles admin contact/inquiry management business logic
 type AdminContactService struct {
+	db      *gorm.DB
 	askRepo *adminrepo.AskRepository
+	now     func() time.Time
 }
 
 // NewAdminContactService creates a new AdminContactService
-func NewAdminContactService(askRepo *adminrepo.AskRepository) *AdminContactService {
-	return &AdminContactService{askRepo: askRepo}
+func NewAdminContactService(db *gorm.DB, askRepo *adminrepo.AskRepository) *AdminContactService {
+	return &AdminContactService{db: db, askRepo: askRepo, now: time.Now}
+}
+
+// GetInquiries retrieves contact inquiries matching the query with the sender's email
+func (s *AdminContactService) GetInquiries(q InquiryQuery) (*InquiryListResponse, error) {
+	filter, order, err := q.filter()
+	if err != nil {
+		return nil, err
+	}
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+
+	asks, total, err := s.askRepo.FindAllPaginated(filter, order, q.Page, q.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inquiries: %w", err)
+	}
+	return &InquiryListResponse{
+		Asks:     asks,
+		Total:    total,
+		Page:     q.Page,
+		PageSize: q.PageSize,
+	}, nil
 }
 
-// GetInquiries retrieves all contact inquiries
-func (s *AdminContactService) GetInquiries() ([]models.Ask, error) {
-	return s.askRepo.FindAll()
+// ExportInquiriesCSV writes every inquiry matching the query to w as CSV, streaming rows as they are read
+func (s *AdminContactService) ExportInquiriesCSV(w io.Writer, q InquiryQuery) error {
+	filter, order, err := q.filter()
+	if err != nil {
+		return err
+	}
+
+	out, err := newCSVStream(w, []string{"askId", "date", "status", "assigneeId", "subject", "text", "userId", "userEmail"})
+	if err != nil {
+		return err
+	}
+	if err := s.askRepo.Each(filter, order, func(a *adminrepo.AskRow) error {
+		return out.Write([]string{
+			strconv.Itoa(int(a.AskID)),
+			a.Date.Format(time.RFC3339),
+			string(a.Status),
+			derefString(a.AssigneeID),
+			a.Subject,
+			a.Text,
+			a.UserID,
+			a.UserEmail,
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to export inquiries: %w", err)
+	}
+	return out.Flush()
 }
 
-// ApproveInquiry marks an inquiry as handled
-func (s *AdminContactService) ApproveInquiry(id int32) error {
-	// Verify the inquiry exists
+// filter validates the query and converts it to the repository filter and order
+func (q InquiryQuery) filter() (adminrepo.AskFilter, string, error) {
+	if q.Status != "" && !models.AskStatus(q.Status).IsValid() {
+		return adminrepo.AskFilter{}, "", ErrInvalidInquiryStatus
+	}
+	if !validRange(q.From, q.To) {
+		return adminrepo.AskFilter{}, "", ErrInvalidListRange
+	}
+	order, err := orderBy(q.Sort, q.Order, inquirySortColumns, "date", "ask.askId DESC")
+	if err != nil {
+		return adminrepo.AskFilter{}, "", err
+	}
+	return adminrepo.AskFilter{
+		Status:     q.Status,
+		AssigneeID: q.AssigneeID,
+		Query:      strings.TrimSpace(q.Query),
+		From:       q.From,
+		To:         q.To,
+	}, order, nil
+}
+
+// GetInquiry retrieves an inquiry with its whole thread, including internal notes
+func (s *AdminContactService) GetInquiry(id int32) (*InquiryDetail, error) {
 	ask, err := s.askRepo.FindByID(id)
 	if err != nil {
-		return errors.New("inquiry not found")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInquiryNotFound
+		}
+		return nil, fmt.Errorf("failed to get inquiry: %w", err)
+	}
+	messages, err := s.askRepo.FindMessages(id, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inquiry messages: %w", err)
+	}
+	return &InquiryDetail{
+		Ask:       *ask,
+		UserEmail: userEmails(s.db, []string{ask.UserID})[ask.UserID],
+		Messages:  messages,
+	}, nil
+}
+
+// PostInquiryMessage adds a reply or an internal note to an inquiry
+// 返信はユーザーにメールで送り、お問い合わせをユーザーの返答待ち（waiting）にします。メモは状態を変えません
+func (s *AdminContactService) PostInquiryMessage(id int32, req InquiryMessageRequest, actor AuditActor) (*models.AskMessage, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrInquiryMessageRequired
+	}
+	if utf8.RuneCountInString(body) > maxInquiryMessageLength {
+		return nil, ErrInquiryMessageTooLong
+	}
+
+	message := &models.AskMessage{
+		AskID:     id,
+		Kind:      models.AskMessageReply,
+		AuthorID:  actor.GoogleID,
+		Body:      body,
+		CreatedAt: s.now(),
+	}
+	action := models.AuditActionInquiryReply
+	if req.Internal {
+		message.Kind = models.AskMessageNote
+		action = models.AuditActionInquiryNote
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		askRepo := s.askRepo.WithTx(tx)
+		ask, err := s.findForUpdate(askRepo, id)
+		if err != nil {
+			return err
+		}
+		if err := askRepo.CreateMessage(message); err != nil {
+			return fmt.Errorf("failed to save inquiry message: %w", err)
+		}
+		if !req.Internal {
+			if err := askRepo.UpdateStatus(id, models.AskStatusWaiting, message.CreatedAt); err != nil {
+				return fmt.Errorf("failed to update inquiry: %w", err)
+			}
+			if err := notifyInquiryReply(tx, ask, message); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, actor, action, models.AuditTargetInquiry, strconv.Itoa(int(id)), nil, message)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return message, nil
+}
 
-	if ask.AskFlag {
-		return errors.New("inquiry is already handled")
+// UpdateInquiryStatus changes the status of an inquiry
+func (s *AdminContactService) UpdateInquiryStatus(id int32, req InquiryStatusRequest, actor AuditActor) (*models.Ask, error) {
+	status := models.AskStatus(req.Status)
+	if !status.IsValid() {
+		return nil, ErrInvalidInquiryStatus
 	}
+	return s.update(id, actor, models.AuditActionInquiryStatus, func(askRepo *adminrepo.AskRepository, _ *models.Ask) error {
+		return askRepo.UpdateStatus(id, status, s.now())
+	})
+}
+
+// AssignInquiry sets or clears the admin in charge of an inquiry
+func (s *AdminContactService) AssignInquiry(id int32, req InquiryAssigneeRequest, actor AuditActor) (*models.Ask, error) {
+	assigneeID := strings.TrimSpace(req.AssigneeID)
+	return s.update(id, actor, models.AuditActionInquiryAssign, func(askRepo *adminrepo.AskRepository, _ *models.Ask) error {
+		if assigneeID == "" {
+			return askRepo.UpdateAssignee(id, nil, s.now())
+		}
+		user, err := sharedrepo.NewUserRepository(s.db).FindByGoogleID(assigneeID)
+		if err != nil || user.Role != models.RoleAdmin || user.DeletedAt != nil {
+			return ErrInvalidInquiryAssignee
+		}
+		return askRepo.UpdateAssignee(id, &assigneeID, s.now())
+	})
+}
+
+// ApproveInquiry closes an inquiry as handled
+func (s *AdminContactService) ApproveInquiry(id int32, actor AuditActor) error {
+	_, err := s.close(id, actor, models.AuditActionInquiryApprove)
+	return err
+}
+
+// RejectInquiry closes an inquiry without handling it (対応不要)
+func (s *AdminContactService) RejectInquiry(id int32, actor AuditActor) error {
+	_, err := s.close(id, actor, models.AuditActionInquiryReject)
+	return err
+}
 
-	return s.askRepo.MarkAsHandled(id)
+// close changes an inquiry that is not yet closed to closed
+func (s *AdminContactService) close(id int32, actor AuditActor, action string) (*models.Ask, error) {
+	return s.update(id, actor, action, func(askRepo *adminrepo.AskRepository, ask *models.Ask) error {
+		if ask.Status == models.AskStatusClosed {
+			return ErrInquiryAlreadyClosed
+		}
+		return askRepo.UpdateStatus(id, models.AskStatusClosed, s.now())
+	})
 }
 
-// RejectInquiry marks an inquiry as rejected
-func (s *AdminContactService) RejectInquiry(id int32) error {
-	// Verify the inquiry exists
-	_, err := s.askRepo.FindByID(id)
+// update locks the inquiry, applies fn and records the inquiry before and after in the audit log
+func (s *AdminContactService) update(id int32, actor AuditActor, action string, fn func(*adminrepo.AskRepository, *models.Ask) error) (*models.Ask, error) {
+	var after *models.Ask
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		askRepo := s.askRepo.WithTx(tx)
+		before, err := s.findForUpdate(askRepo, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(askRepo, before); err != nil {
+			return err
+		}
+		if after, err = askRepo.FindByID(id); err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, action, models.AuditTargetInquiry, strconv.Itoa(int(id)), before, after)
+	})
 	if err != nil {
-		return errors.New("inquiry not found")
+		return nil, err
 	}
+	return after, nil
+}
+
+// findForUpdate locks the inquiry until the end of the transaction
+func (s *AdminContactService) findForUpdate(askRepo *adminrepo.AskRepository, id int32) (*models.Ask, error) {
+	ask, err := askRepo.FindByIDForUpdate(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInquiryNotFound
+		}
+		return nil, fmt.Errorf("failed to get inquiry: %w", err)
+	}
+	return ask, nil
+}
 
-	return s.askRepo.MarkAsRejected(id)
+// notifyInquiryReply は管理者の返信をユーザーにメールで送ります（tx 内で呼び出す）。
+func notifyInquiryReply(tx *gorm.DB, ask *models.Ask, message *models.AskMessage) error {
+	to := userEmails(tx, []string{ask.UserID})[ask.UserID]
+	if to == "" {
+		return nil
+	}
+	msg, err := outbox.NewEmail(fmt.Sprintf("inquiry-reply:%d", message.MessageID), notification.Message{
+		Kind: notification.KindInquiryReply,
+		To:   to,
+		Data: notification.InquiryReplyData{Subject: ask.Subject, Reply: message.Body},
+	})
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(tx, msg)
 }
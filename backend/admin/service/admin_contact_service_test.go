@@ -1,7 +1,10 @@
 package service
 
 import (
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -44,3 +47,56 @@ func TestAdminContactService_GetInquiries(t *testing.T) {
 		assert.Equal(t, 0, len(inquiries))
 	})
 }
+
+func TestAdminContactService_GetInquiriesValidation(t *testing.T) {
+	svc := NewAdminContactService(nil, nil)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	to := from.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		query InquiryQuery
+		want  error
+	}{
+		{"unknown status", InquiryQuery{Status: "pending"}, ErrInvalidInquiryStatus},
+		{"from after to", InquiryQuery{From: &from, To: &to}, ErrInvalidListRange},
+		{"unknown order", InquiryQuery{Sort: "date", Order: "up"}, ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetInquiries(tt.query)
+			assert.ErrorIs(t, err, tt.want)
+			assert.ErrorIs(t, svc.ExportInquiriesCSV(io.Discard, tt.query), tt.want)
+		})
+	}
+}
+
+func TestAdminContactService_PostInquiryMessageValidation(t *testing.T) {
+	svc := NewAdminContactService(nil, nil)
+	actor := AuditActor{GoogleID: "admin"}
+
+	_, err := svc.PostInquiryMessage(1, InquiryMessageRequest{Body: "  "}, actor)
+	assert.ErrorIs(t, err, ErrInquiryMessageRequired)
+
+	_, err = svc.PostInquiryMessage(1, InquiryMessageRequest{Body: strings.Repeat("あ", maxInquiryMessageLength+1), Internal: true}, actor)
+	assert.ErrorIs(t, err, ErrInquiryMessageTooLong)
+}
+
+func TestAdminContactService_UpdateInquiryStatusValidation(t *testing.T) {
+	svc := NewAdminContactService(nil, nil)
+
+	for _, status := range []string{"", "pending", "handled", "CLOSED"} {
+		_, err := svc.UpdateInquiryStatus(1, InquiryStatusRequest{Status: status}, AuditActor{GoogleID: "admin"})
+		assert.ErrorIs(t, err, ErrInvalidInquiryStatus, status)
+	}
+}
+
+func TestInquiryQuery_OrderUsesAskTable(t *testing.T) {
+	_, order, err := InquiryQuery{}.filter()
+	assert.NoError(t, err)
+	assert.Equal(t, "ask.date DESC, ask.askId DESC", order)
+
+	_, order, err = InquiryQuery{Sort: "subject", Order: "asc"}.filter()
+	assert.NoError(t, err)
+	assert.Equal(t, "ask.subject ASC, ask.askId DESC", order)
+}
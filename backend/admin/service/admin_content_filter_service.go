@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	adminrepo "kojan-map/admin/repository"
+	"kojan-map/business/pkg/models"
+	"kojan-map/shared/contentfilter"
+
+	"gorm.io/gorm"
+)
+
+// Content filter errors
+var (
+	ErrFilterRuleNotFound  = errors.New("content filter rule not found")
+	ErrInvalidFilterKind   = errors.New("invalid kind")
+	ErrInvalidFilterAction = errors.New("invalid action")
+	ErrFilterPatternNeeded = errors.New("pattern is required for ng_word")
+	ErrFilterRuleDuplicate = errors.New("the same NG word already exists")
+)
+
+// ContentFilterRuleRequest represents a request to create or update a rule.
+type ContentFilterRuleRequest struct {
+	Kind    string `json:"kind" binding:"required"`
+	Pattern string `json:"pattern"`
+	Action  string `json:"action" binding:"required"`
+	Reason  string `json:"reason"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// ContentFilterCheckResponse represents the result of a dry-run check.
+type ContentFilterCheckResponse struct {
+	Text    string                `json:"text"`
+	Action  string                `json:"action"`
+	Reason  string                `json:"reason"`
+	Matches []contentfilter.Match `json:"matches"`
+}
+
+// AdminContentFilterService handles NG-word dictionary and PII detector management.
+type AdminContentFilterService struct {
+	db       *gorm.DB
+	ruleRepo *adminrepo.ContentFilterRuleRepository
+	filter   *contentfilter.Service
+}
+
+// NewAdminContentFilterService creates a new AdminContentFilterService.
+//
+// Parameters:
+//   - db: ルールの変更と監査ログを同じトランザクションで記録するためのDB
+//   - ruleRepo: フィルタルールのリポジトリ
+//   - filter: ユーザー側と共有するフィルタ（ルール変更時にキャッシュを破棄する）
+//
+// Returns:
+//   - *AdminContentFilterService: 新しいサービスインスタンス
+func NewAdminContentFilterService(db *gorm.DB, ruleRepo *adminrepo.ContentFilterRuleRepository, filter *contentfilter.Service) *AdminContentFilterService {
+	return &AdminContentFilterService{db: db, ruleRepo: ruleRepo, filter: filter}
+}
+
+// GetRules retrieves filter rules, optionally filtered by kind.
+func (s *AdminContentFilterService) GetRules(kind *string) ([]models.ContentFilterRule, error) {
+	if kind != nil && !contentfilter.IsValidKind(*kind) {
+		return nil, ErrInvalidFilterKind
+	}
+	return s.ruleRepo.FindAll(kind)
+}
+
+// CreateRule adds an NG word or a detector rule and records it in the audit log.
+// 検出ルール（phone, email, my_number）を登録すると、その種類の既定動作は使われなくなります。
+func (s *AdminContentFilterService) CreateRule(req ContentFilterRuleRequest, actor AuditActor) (*models.ContentFilterRule, error) {
+	rule := &models.ContentFilterRule{Enabled: true}
+	if err := s.apply(rule, req); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ruleRepo := s.ruleRepo.WithTx(tx)
+		if rule.Kind == models.FilterKindNGWord {
+			exists, err := ruleRepo.Exists(rule.Kind, rule.Pattern)
+			if err != nil {
+				return fmt.Errorf("failed to check duplicate rule: %w", err)
+			}
+			if exists {
+				return ErrFilterRuleDuplicate
+			}
+		}
+
+		if err := ruleRepo.Create(rule); err != nil {
+			return fmt.Errorf("failed to create rule: %w", err)
+		}
+		return recordAudit(tx, actor, models.AuditActionFilterRuleCreate, models.AuditTargetFilterRule, strconv.Itoa(int(rule.ID)), nil, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.filter.Invalidate()
+	return rule, nil
+}
+
+// UpdateRule updates an existing rule and records the change in the audit log.
+func (s *AdminContentFilterService) UpdateRule(id int32, req ContentFilterRuleRequest, actor AuditActor) (*models.ContentFilterRule, error) {
+	var after *models.ContentFilterRule
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ruleRepo := s.ruleRepo.WithTx(tx)
+		before, err := s.findForUpdate(ruleRepo, id)
+		if err != nil {
+			return err
+		}
+		rule := *before
+		if err := s.apply(&rule, req); err != nil {
+			return err
+		}
+		if err := ruleRepo.Update(&rule); err != nil {
+			return fmt.Errorf("failed to update rule: %w", err)
+		}
+		after = &rule
+		return recordAudit(tx, actor, models.AuditActionFilterRuleUpdate, models.AuditTargetFilterRule, strconv.Itoa(int(id)), before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.filter.Invalidate()
+	return after, nil
+}
+
+// DeleteRule deletes a rule and records it in the audit log.
+func (s *AdminContentFilterService) DeleteRule(id int32, actor AuditActor) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ruleRepo := s.ruleRepo.WithTx(tx)
+		before, err := s.findForUpdate(ruleRepo, id)
+		if err != nil {
+			return err
+		}
+		if err := ruleRepo.Delete(id); err != nil {
+			return fmt.Errorf("failed to delete rule: %w", err)
+		}
+		return recordAudit(tx, actor, models.AuditActionFilterRuleDelete, models.AuditTargetFilterRule, strconv.Itoa(int(id)), before, nil)
+	})
+	if err != nil {
+		return err
+	}
+	s.filter.Invalidate()
+	return nil
+}
+
+// findForUpdate loads a rule and locks it until the transaction ends
+func (s *AdminContentFilterService) findForUpdate(repo *adminrepo.ContentFilterRuleRepository, id int32) (*models.ContentFilterRule, error) {
+	rule, err := repo.FindByIDForUpdate(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFilterRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+	return rule, nil
+}
+
+// CheckText runs the current rules against text without saving anything.
+func (s *AdminContentFilterService) CheckText(text string) (*ContentFilterCheckResponse, error) {
+	result, err := s.filter.Check(text)
+	if err != nil {
+		return nil, err
+	}
+	matches := result.Matches
+	if matches == nil {
+		matches = []contentfilter.Match{}
+	}
+	return &ContentFilterCheckResponse{
+		Text:    result.Text,
+		Action:  result.Action,
+		Reason:  result.Reason,
+		Matches: matches,
+	}, nil
+}
+
+// apply validates req and copies it onto rule.
+func (s *AdminContentFilterService) apply(rule *models.ContentFilterRule, req ContentFilterRuleRequest) error {
+	if !contentfilter.IsValidKind(req.Kind) {
+		return ErrInvalidFilterKind
+	}
+	if !contentfilter.IsValidAction(req.Action) {
+		return ErrInvalidFilterAction
+	}
+
+	pattern := strings.TrimSpace(req.Pattern)
+	if req.Kind == models.FilterKindNGWord {
+		// 照合は正規化後に行うため、保存時も正規化した形に揃える
+		pattern = contentfilter.Normalize(pattern)
+		if pattern == "" {
+			return ErrFilterPatternNeeded
+		}
+	} else {
+		pattern = ""
+	}
+
+	rule.Kind = req.Kind
+	rule.Pattern = pattern
+	rule.Action = req.Action
+	rule.Reason = strings.TrimSpace(req.Reason)
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"kojan-map/business/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminContentFilterService_apply(t *testing.T) {
+	s := NewAdminContentFilterService(nil, nil, nil)
+
+	t.Run("normalizes NG words", func(t *testing.T) {
+		rule := &models.ContentFilterRule{}
+		err := s.apply(rule, ContentFilterRuleRequest{Kind: models.FilterKindNGWord, Pattern: " ﾊﾞｶ ", Action: models.FilterActionReject})
+		assert.NoError(t, err)
+		assert.Equal(t, "ばか", rule.Pattern)
+	})
+
+	t.Run("requires pattern for NG words", func(t *testing.T) {
+		err := s.apply(&models.ContentFilterRule{}, ContentFilterRuleRequest{Kind: models.FilterKindNGWord, Action: models.FilterActionMask})
+		assert.ErrorIs(t, err, ErrFilterPatternNeeded)
+	})
+
+	t.Run("ignores pattern for detectors", func(t *testing.T) {
+		rule := &models.ContentFilterRule{}
+		err := s.apply(rule, ContentFilterRuleRequest{Kind: models.FilterKindPhone, Pattern: "ignored", Action: models.FilterActionReview})
+		assert.NoError(t, err)
+		assert.Empty(t, rule.Pattern)
+	})
+
+	t.Run("rejects unknown kind and action", func(t *testing.T) {
+		assert.ErrorIs(t, s.apply(&models.ContentFilterRule{}, ContentFilterRuleRequest{Kind: "address", Action: models.FilterActionMask}), ErrInvalidFilterKind)
+		assert.ErrorIs(t, s.apply(&models.ContentFilterRule{}, ContentFilterRuleRequest{Kind: models.FilterKindEmail, Action: "delete"}), ErrInvalidFilterAction)
+	})
+}
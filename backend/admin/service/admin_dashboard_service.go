@@ -2,7 +2,7 @@ package service
 
 import (
 	adminrepo "kojan-map/admin/repository"
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
 	sharedrepo "kojan-map/shared/repository"
 )
 
@@ -46,7 +46,7 @@ func (s *AdminDashboardService) GetSummary() (*DashboardSummary, error) {
 		return nil, err
 	}
 
-	// Active users are those without deletedAt
+	// General members (role user); daily active users are in GET /api/admin/analytics
 	activeUsers, err := s.userRepo.CountByRole(models.RoleUser)
 	if err != nil {
 		return nil, err
@@ -3,7 +3,7 @@ package service
 import (
 	"testing"
 
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// List query errors shared by the admin user, application and inquiry lists
+var (
+	ErrInvalidSort      = errors.New("invalid sort or order")
+	ErrInvalidListRange = errors.New("from must be before to")
+)
+
+// csvFlushRows is the number of CSV rows buffered before they are sent to the client
+const csvFlushRows = 100
+
+// orderBy returns the ORDER BY clause for the sort key and order (asc / desc, default desc)
+// columns maps the sort keys accepted from the client to SQL; tiebreak keeps the order stable across pages
+func orderBy(sort, order string, columns map[string]string, defaultSort, tiebreak string) (string, error) {
+	if sort == "" {
+		sort = defaultSort
+	}
+	column, ok := columns[sort]
+	if !ok {
+		return "", ErrInvalidSort
+	}
+	direction := "DESC"
+	switch order {
+	case "", "desc":
+	case "asc":
+		direction = "ASC"
+	default:
+		return "", ErrInvalidSort
+	}
+	return column + " " + direction + ", " + tiebreak, nil
+}
+
+// validRange reports whether from is before to when both are given
+func validRange(from, to *time.Time) bool {
+	return from == nil || to == nil || from.Before(*to)
+}
+
+// normalizePage applies the default page and page size of the admin lists
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// csvStream writes CSV rows and sends them to the client every csvFlushRows rows
+// w が Flush() を持つ場合（gin の ResponseWriter など）は、全件を読み終える前に送信します
+type csvStream struct {
+	w    io.Writer
+	cw   *csv.Writer
+	rows int
+}
+
+// newCSVStream writes the header and returns the stream
+func newCSVStream(w io.Writer, header []string) (*csvStream, error) {
+	s := &csvStream{w: w, cw: csv.NewWriter(w)}
+	if err := s.cw.Write(header); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write writes one row
+func (s *csvStream) Write(record []string) error {
+	if err := s.cw.Write(csvSafeRecord(record)); err != nil {
+		return err
+	}
+	s.rows++
+	if s.rows%csvFlushRows == 0 {
+		return s.Flush()
+	}
+	return nil
+}
+
+// csvSafeRecord prefixes cells that a spreadsheet would evaluate as a formula with a single quote
+// (CSV injection). User-supplied values such as names, titles and report reasons go through here.
+func csvSafeRecord(record []string) []string {
+	safe := make([]string, len(record))
+	for i, cell := range record {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cell = "'" + cell
+		}
+		safe[i] = cell
+	}
+	return safe
+}
+
+// Flush sends the buffered rows to the client
+func (s *csvStream) Flush() error {
+	s.cw.Flush()
+	if f, ok := s.w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+	return s.cw.Error()
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderBy(t *testing.T) {
+	columns := map[string]string{"date": "ask.date", "subject": "ask.subject"}
+
+	order, err := orderBy("", "", columns, "date", "ask.askId DESC")
+	require.NoError(t, err)
+	assert.Equal(t, "ask.date DESC, ask.askId DESC", order)
+
+	order, err = orderBy("subject", "asc", columns, "date", "ask.askId DESC")
+	require.NoError(t, err)
+	assert.Equal(t, "ask.subject ASC, ask.askId DESC", order)
+
+	// クライアントの値をそのまま SQL にしない
+	_, err = orderBy("subject; DROP TABLE ask", "asc", columns, "date", "ask.askId DESC")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+	_, err = orderBy("date", "ASC", columns, "date", "ask.askId DESC")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestNormalizePage(t *testing.T) {
+	page, pageSize := normalizePage(0, 500)
+	assert.Equal(t, 1, page)
+	assert.Equal(t, 20, pageSize)
+
+	page, pageSize = normalizePage(3, 100)
+	assert.Equal(t, 3, page)
+	assert.Equal(t, 100, pageSize)
+}
+
+// flushRecorder は Flush の回数を数える Writer です
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *flushRecorder) Flush() { f.flushes++ }
+
+func TestCSVStream_FlushesEveryBatch(t *testing.T) {
+	w := &flushRecorder{}
+	out, err := newCSVStream(w, []string{"id", "name"})
+	require.NoError(t, err)
+
+	for i := 0; i < csvFlushRows+1; i++ {
+		require.NoError(t, out.Write([]string{strconv.Itoa(i), "a,\"b\""}))
+	}
+	assert.Equal(t, 1, w.flushes)
+
+	require.NoError(t, out.Flush())
+	assert.Equal(t, 2, w.flushes)
+	assert.Contains(t, w.String(), "id,name\n0,\"a,\"\"b\"\"\"\n")
+}
+
+func TestCSVSafeRecord(t *testing.T) {
+	assert.Equal(t,
+		[]string{"'=HYPERLINK(\"http://evil\")", "'+1", "'-1+2", "'@SUM(A1)", "'\tx", "plain", "", "a=b"},
+		csvSafeRecord([]string{"=HYPERLINK(\"http://evil\")", "+1", "-1+2", "@SUM(A1)", "\tx", "plain", "", "a=b"}),
+	)
+
+	var w bytes.Buffer
+	out, err := newCSVStream(&w, []string{"id", "name"})
+	require.NoError(t, err)
+	require.NoError(t, out.Write([]string{"1", "=cmd|' /C calc'!A0"}))
+	require.NoError(t, out.Flush())
+	assert.Contains(t, w.String(), "1,'=cmd|' /C calc'!A0\n")
+}
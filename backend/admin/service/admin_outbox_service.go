@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/outbox"
+
+	"gorm.io/gorm"
+)
+
+// Outbox errors
+var (
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
+	ErrOutboxNotReplayable   = errors.New("only dead messages can be replayed")
+	ErrInvalidOutboxStatus   = errors.New("invalid status")
+)
+
+// OutboxMessageResponse represents a queued email delivery.
+type OutboxMessageResponse struct {
+	ID             int64           `json:"id"`
+	Topic          string          `json:"topic"`
+	IdempotencyKey string          `json:"idempotencyKey"`
+	Status         outbox.Status   `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxAttempts    int             `json:"maxAttempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastError      string          `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	Payload        json.RawMessage `json:"payload,omitempty"` // 詳細のみ（MFAコードなど機密の内容は含めない）
+	Redacted       bool            `json:"redacted"`
+}
+
+// OutboxListResponse represents the paginated outbox list response
+type OutboxListResponse struct {
+	Messages []OutboxMessageResponse `json:"messages"`
+	Total    int64                   `json:"total"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"pageSize"`
+}
+
+// outboxStore is the part of outbox.Store used to list and show messages.
+type outboxStore interface {
+	List(ctx context.Context, f outbox.Filter) ([]outbox.Message, int64, error)
+	Get(ctx context.Context, id int64) (*outbox.Message, error)
+}
+
+// AdminOutboxService lets admins inspect failed email deliveries and replay them.
+type AdminOutboxService struct {
+	db    *gorm.DB
+	store outboxStore
+	wake  func()
+}
+
+// NewAdminOutboxService creates a new AdminOutboxService.
+//
+// Parameters:
+//   - db: 再送と監査ログを同じトランザクションで記録するためのDB
+//   - store: outbox テーブルのストア
+//   - wake: 再送後に配信ワーカーを起こす関数（nilの場合は次の確認間隔で配信）
+//
+// Returns:
+//   - *AdminOutboxService: 新しいサービスインスタンス
+func NewAdminOutboxService(db *gorm.DB, store outboxStore, wake func()) *AdminOutboxService {
+	return &AdminOutboxService{db: db, store: store, wake: wake}
+}
+
+// GetMessages retrieves outbox messages with pagination, newest first.
+func (s *AdminOutboxService) GetMessages(ctx context.Context, page, pageSize int, status, topic string) (*OutboxListResponse, error) {
+	if status != "" && !outbox.IsValidStatus(outbox.Status(status)) {
+		return nil, ErrInvalidOutboxStatus
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	messages, total, err := s.store.List(ctx, outbox.Filter{
+		Status:   outbox.Status(status),
+		Topic:    topic,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]OutboxMessageResponse, len(messages))
+	for i := range messages {
+		result[i] = toOutboxMessageResponse(&messages[i], false)
+	}
+	return &OutboxListResponse{
+		Messages: result,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetMessage retrieves a single outbox message including its payload.
+func (s *AdminOutboxService) GetMessage(ctx context.Context, id int64) (*OutboxMessageResponse, error) {
+	msg, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, mapOutboxError(err)
+	}
+	resp := toOutboxMessageResponse(msg, true)
+	return &resp, nil
+}
+
+// ReplayMessage resets a dead message so the dispatcher delivers it again, and records it in the audit log.
+// 冪等キーはそのまま使うため、受信側で重複を判定できます。
+// 監査ログには本文（MFAコードなどを含む場合がある）を記録しません。
+func (s *AdminOutboxService) ReplayMessage(ctx context.Context, id int64, actor AuditActor) (*OutboxMessageResponse, error) {
+	var after OutboxMessageResponse
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		store := outbox.NewStore(tx)
+		msg, err := store.Get(ctx, id)
+		if err != nil {
+			return mapOutboxError(err)
+		}
+		before := toOutboxMessageResponse(msg, false)
+
+		if msg, err = store.Replay(ctx, id); err != nil {
+			return mapOutboxError(err)
+		}
+		after = toOutboxMessageResponse(msg, false)
+		return recordAudit(tx, actor, models.AuditActionOutboxReplay, models.AuditTargetOutbox, strconv.FormatInt(id, 10), before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.wake != nil {
+		s.wake()
+	}
+	return &after, nil
+}
+
+func mapOutboxError(err error) error {
+	switch {
+	case errors.Is(err, outbox.ErrNotFound):
+		return ErrOutboxMessageNotFound
+	case errors.Is(err, outbox.ErrNotReplayable):
+		return ErrOutboxNotReplayable
+	}
+	return err
+}
+
+func toOutboxMessageResponse(msg *outbox.Message, withPayload bool) OutboxMessageResponse {
+	resp := OutboxMessageResponse{
+		ID:             msg.ID,
+		Topic:          msg.Topic,
+		IdempotencyKey: msg.IdempotencyKey,
+		Status:         msg.Status,
+		Attempts:       msg.Attempts,
+		MaxAttempts:    msg.MaxAttempts,
+		NextAttemptAt:  msg.NextAttemptAt,
+		LastError:      msg.LastError,
+		DeliveredAt:    msg.DeliveredAt,
+		CreatedAt:      msg.CreatedAt,
+		UpdatedAt:      msg.UpdatedAt,
+		Redacted:       msg.RedactOnDelivery,
+	}
+	if withPayload && !msg.RedactOnDelivery {
+		resp.Payload = msg.Payload
+	}
+	return resp
+}
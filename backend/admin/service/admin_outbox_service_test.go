@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"kojan-map/business/pkg/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutboxStore keeps outbox messages in memory.
+type fakeOutboxStore struct {
+	messages   map[int64]*outbox.Message
+	lastFilter outbox.Filter
+}
+
+func (s *fakeOutboxStore) List(_ context.Context, f outbox.Filter) ([]outbox.Message, int64, error) {
+	s.lastFilter = f
+	var result []outbox.Message
+	for _, m := range s.messages {
+		if f.Status == "" || m.Status == f.Status {
+			result = append(result, *m)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (s *fakeOutboxStore) Get(_ context.Context, id int64) (*outbox.Message, error) {
+	m, ok := s.messages[id]
+	if !ok {
+		return nil, outbox.ErrNotFound
+	}
+	copied := *m
+	return &copied, nil
+}
+
+func newFakeOutboxStore() *fakeOutboxStore {
+	return &fakeOutboxStore{messages: map[int64]*outbox.Message{
+		1: {ID: 1, Topic: outbox.TopicEmail, Status: outbox.StatusDead, Attempts: 8, LastError: "smtp down",
+			Payload: json.RawMessage(`{"kind":"inquiry_reply"}`)},
+		2: {ID: 2, Topic: outbox.TopicEmail, Status: outbox.StatusDead, Attempts: 4, RedactOnDelivery: true,
+			Payload: json.RawMessage(`{"kind":"mfa_code","data":{"Code":"123456"}}`)},
+		3: {ID: 3, Topic: outbox.TopicEmail, Status: outbox.StatusDelivered, Attempts: 1},
+	}}
+}
+
+func TestAdminOutboxService_GetMessages(t *testing.T) {
+	store := newFakeOutboxStore()
+	svc := NewAdminOutboxService(nil, store, nil)
+
+	result, err := svc.GetMessages(context.Background(), 0, 500, "dead", "")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, result.Total)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, 20, result.PageSize)
+	assert.Equal(t, outbox.StatusDead, store.lastFilter.Status)
+	for _, m := range result.Messages {
+		assert.Nil(t, m.Payload, "list must not include payloads")
+	}
+
+	_, err = svc.GetMessages(context.Background(), 1, 20, "failed", "")
+	assert.ErrorIs(t, err, ErrInvalidOutboxStatus)
+}
+
+func TestAdminOutboxService_GetMessage(t *testing.T) {
+	svc := NewAdminOutboxService(nil, newFakeOutboxStore(), nil)
+
+	msg, err := svc.GetMessage(context.Background(), 1)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"inquiry_reply"}`, string(msg.Payload))
+	assert.Equal(t, "smtp down", msg.LastError)
+
+	// MFAコードなど配信後に消去する内容は表示しない
+	msg, err = svc.GetMessage(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Nil(t, msg.Payload)
+	assert.True(t, msg.Redacted)
+
+	_, err = svc.GetMessage(context.Background(), 99)
+	assert.ErrorIs(t, err, ErrOutboxMessageNotFound)
+}
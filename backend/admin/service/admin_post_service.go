@@ -3,8 +3,10 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -13,6 +15,8 @@ import (
 var (
 	// ErrPostNotFound は投稿が見つからない場合に返されるエラー
 	ErrPostNotFound = errors.New("post not found")
+	// ErrPostNotInQueue は投稿が審査待ちでない場合に返されるエラー
+	ErrPostNotInQueue = errors.New("post is not awaiting moderation")
 )
 
 // PostDetailResponse represents detailed post information for admin.
@@ -26,6 +30,22 @@ type PostDetailResponse struct {
 	NumReaction int    `json:"numReaction"`
 	NumView     int    `json:"numView"`
 	GenreID     int    `json:"genreId"`
+	// ModerationStatus is one of approved, pending, hidden or rejected
+	ModerationStatus string `json:"moderationStatus"`
+}
+
+// ModerationQueueItem represents a post awaiting moderation.
+type ModerationQueueItem struct {
+	PostDetailResponse
+	ReportCount int `json:"reportCount"`
+}
+
+// ModerationQueueResponse represents paginated moderation queue.
+type ModerationQueueResponse struct {
+	Posts    []ModerationQueueItem `json:"posts"`
+	Total    int                   `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
 }
 
 // AdminPostService handles admin post management business logic.
@@ -53,8 +73,9 @@ func NewAdminPostService(db *gorm.DB) *AdminPostService {
 //   - *PostDetailResponse: 投稿詳細情報
 //   - error: ErrPostNotFound（投稿が存在しない場合）またはDBエラー
 func (s *AdminPostService) GetPostByID(postID int) (*PostDetailResponse, error) {
+	// 削除済みの投稿も確認できるよう Unscoped で取得する
 	var post models.Post
-	result := s.db.Where("postId = ?", postID).First(&post)
+	result := s.db.Unscoped().Where("postId = ?", postID).First(&post)
 	if result.Error != nil {
 		// レコードが見つからない場合と他のエラーを区別
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -74,18 +95,176 @@ func (s *AdminPostService) GetPostByID(postID int) (*PostDetailResponse, error)
 		NumReaction: int(post.NumReaction),
 		NumView:     int(post.NumView),
 		GenreID:     int(post.GenreID),
+
+		ModerationStatus: post.ModerationStatus,
 	}, nil
 }
 
-// DeletePost deletes a post by ID (hard delete) with transaction.
-// 関連する通報も同時に削除されます。
+// GetModerationQueue retrieves posts awaiting moderation with pagination.
+// 新規アカウントの審査待ち投稿（pending）と通報による自動非表示投稿（hidden）が対象です。
+//
+// Parameters:
+//   - page: ページ番号（1から開始）
+//   - pageSize: 1ページあたりの件数
+//   - status: 絞り込む審査状態（nil の場合は pending と hidden の両方）
+//
+// Returns:
+//   - *ModerationQueueResponse: 審査待ち投稿一覧（古い順）
+//   - error: DBエラー
+func (s *AdminPostService) GetModerationQueue(page, pageSize int, status *string) (*ModerationQueueResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	statuses := []string{models.ModerationPending, models.ModerationHidden}
+	if status != nil {
+		statuses = []string{*status}
+	}
+
+	query := s.db.Model(&models.Post{}).
+		Where("moderationStatus IN ?", statuses)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count moderation queue: %w", err)
+	}
+
+	var posts []models.Post
+	if err := query.Order("postDate ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+
+	// 未処理の通報件数を投稿ごとに取得
+	postIDs := make([]int32, len(posts))
+	for i, p := range posts {
+		postIDs[i] = p.PostID
+	}
+	type reportCount struct {
+		PostID int32 `gorm:"column:postId"`
+		Count  int
+	}
+	var counts []reportCount
+	if len(postIDs) > 0 {
+		if err := s.db.Model(&models.Report{}).
+			Select("postId, COUNT(*) AS count").
+			Where("postId IN ? AND reportFlag = ?", postIDs, false).
+			Group("postId").
+			Scan(&counts).Error; err != nil {
+			return nil, fmt.Errorf("failed to count reports: %w", err)
+		}
+	}
+	countMap := make(map[int32]int, len(counts))
+	for _, c := range counts {
+		countMap[c.PostID] = c.Count
+	}
+
+	items := make([]ModerationQueueItem, len(posts))
+	for i, post := range posts {
+		items[i] = ModerationQueueItem{
+			PostDetailResponse: PostDetailResponse{
+				PostID:      int(post.PostID),
+				PlaceID:     int(post.PlaceID),
+				UserID:      post.UserID,
+				PostDate:    post.PostDate.Format("2006-01-02T15:04:05Z07:00"),
+				Title:       post.Title,
+				Text:        post.Text,
+				NumReaction: int(post.NumReaction),
+				NumView:     int(post.NumView),
+				GenreID:     int(post.GenreID),
+
+				ModerationStatus: post.ModerationStatus,
+			},
+			ReportCount: countMap[post.PostID],
+		}
+	}
+	return &ModerationQueueResponse{
+		Posts:    items,
+		Total:    int(total),
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// ApprovePost approves a post in the moderation queue and makes it public.
+// 自動非表示された投稿の場合、未処理の通報は「問題なし」として処理済みになります。
+//
+// Parameters:
+//   - postID: 承認する投稿のID
+//   - actor: 操作した管理者（監査ログに記録）
+//
+// Returns:
+//   - error: ErrPostNotFound, ErrPostNotInQueue またはDBエラー
+func (s *AdminPostService) ApprovePost(postID int, actor AuditActor) error {
+	return s.moderate(postID, models.ModerationApproved, models.ReportResolutionDismiss, actor, models.AuditActionPostApprove)
+}
+
+// RejectPost rejects a post in the moderation queue so that it stays hidden.
+// 未処理の通報は「投稿を削除」として処理済みになります。
+//
+// Parameters:
+//   - postID: 却下する投稿のID
+//   - actor: 操作した管理者（監査ログに記録）
+//
+// Returns:
+//   - error: ErrPostNotFound, ErrPostNotInQueue またはDBエラー
+func (s *AdminPostService) RejectPost(postID int, actor AuditActor) error {
+	return s.moderate(postID, models.ModerationRejected, models.ReportResolutionRemovePost, actor, models.AuditActionPostReject)
+}
+
+// moderate transitions a queued post to the given status and resolves its open reports.
+func (s *AdminPostService) moderate(postID int, status string, resolution string, actor AuditActor, action string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var post models.Post
+		if err := tx.Where("postId = ?", postID).First(&post).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPostNotFound
+			}
+			return fmt.Errorf("failed to find post: %w", err)
+		}
+
+		// 審査待ちの場合のみ遷移させる（同時操作による二重処理を防止）
+		now := time.Now()
+		result := tx.Model(&models.Post{}).
+			Where("postId = ? AND moderationStatus IN ?", postID, []string{models.ModerationPending, models.ModerationHidden}).
+			Updates(map[string]interface{}{
+				"moderationStatus": status,
+				"moderatedAt":      now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update moderation status: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrPostNotInQueue
+		}
+
+		if _, err := resolvePostReports(tx, post.PostID, post.Title, resolution, "", actor, now); err != nil {
+			return err
+		}
+
+		var after models.Post
+		if err := tx.Where("postId = ?", postID).First(&after).Error; err != nil {
+			return fmt.Errorf("failed to find post: %w", err)
+		}
+		return recordAudit(tx, actor, action, models.AuditTargetPost, strconv.Itoa(postID), postAuditSnapshot(post), postAuditSnapshot(after))
+	})
+}
+
+// DeletePost removes a post by ID (logical delete) with transaction.
+// 関連する通報は削除せず、「投稿を削除」として処理済みにします（対応の記録として残す）。
 //
 // Parameters:
 //   - postID: 削除する投稿のID
+//   - actor: 操作した管理者（削除前の投稿とともに監査ログに記録）
 //
 // Returns:
 //   - error: ErrPostNotFound（投稿が存在しない場合）またはDBエラー
-func (s *AdminPostService) DeletePost(postID int) error {
+func (s *AdminPostService) DeletePost(postID int, actor AuditActor) error {
 	// トランザクションを使用して原子性を保証
 	return s.db.Transaction(func(tx *gorm.DB) error {
 		// Check if post exists
@@ -98,16 +277,38 @@ func (s *AdminPostService) DeletePost(postID int) error {
 			return fmt.Errorf("failed to find post: %w", result.Error)
 		}
 
-		// Delete associated reports first
-		if err := tx.Where("postId = ?", postID).Delete(&models.Report{}).Error; err != nil {
-			return fmt.Errorf("failed to delete reports: %w", err)
+		now := time.Now()
+		if _, err := resolvePostReports(tx, post.PostID, post.Title, models.ReportResolutionRemovePost, "", actor, now); err != nil {
+			return err
 		}
-
-		// Delete the post
-		if err := tx.Where("postId = ?", postID).Delete(&models.Post{}).Error; err != nil {
-			return fmt.Errorf("failed to delete post: %w", err)
+		if err := removePost(tx, post.PostID, now); err != nil {
+			return err
 		}
 
-		return nil
+		var after models.Post
+		if err := tx.Unscoped().Where("postId = ?", postID).First(&after).Error; err != nil {
+			return fmt.Errorf("failed to find post: %w", err)
+		}
+		return recordAudit(tx, actor, models.AuditActionPostDelete, models.AuditTargetPost, strconv.Itoa(postID), postAuditSnapshot(post), postAuditSnapshot(after))
 	})
 }
+
+// postAuditSnapshot は監査ログに記録する投稿を返します（画像は含めない）。
+func postAuditSnapshot(post models.Post) models.Post {
+	post.PostImage = nil
+	return post
+}
+
+// removePost は投稿を論理削除し、公開・審査の対象から外します（通報は対応の記録として残す）。
+func removePost(tx *gorm.DB, postID int32, at time.Time) error {
+	if err := tx.Model(&models.Post{}).
+		Where("postId = ?", postID).
+		Updates(map[string]interface{}{
+			"moderationStatus": models.ModerationRejected,
+			"moderatedAt":      at,
+			"deletedAt":        at,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to remove post: %w", err)
+	}
+	return nil
+}
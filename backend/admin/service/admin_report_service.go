@@ -2,20 +2,65 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	adminrepo "kojan-map/admin/repository"
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/outbox"
+	sharedrepo "kojan-map/shared/repository"
 
 	"gorm.io/gorm"
 )
 
+// Report resolution errors
+var (
+	ErrReportNotFound         = errors.New("report not found")
+	ErrReportAlreadyHandled   = errors.New("report is already handled")
+	ErrInvalidResolution      = errors.New("resolution must be one of dismiss, remove_post, warn_author, suspend_author")
+	ErrResolutionNoteRequired = errors.New("note is required")
+	ErrResolutionNoteTooLong  = errors.New("note is too long")
+	ErrInvalidSuspendDays     = errors.New("suspendDays must be between 1 and 365")
+)
+
+// Report resolution limits
+const (
+	maxResolutionNoteLength = 1000
+	defaultSuspendDays      = 7
+	maxSuspendDays          = 365
+)
+
 // ReportListResponse represents the paginated report list response
 type ReportListResponse struct {
-	Reports  []models.Report `json:"reports"`
-	Total    int             `json:"total"`
-	Page     int             `json:"page"`
-	PageSize int             `json:"pageSize"`
+	Reports  []ReportDetailResponse `json:"reports"`
+	Total    int                    `json:"total"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"pageSize"`
+}
+
+// ReportGroup represents the reports on one post, counted per category
+type ReportGroup struct {
+	PostID      int32  `json:"postId"`
+	PostTitle   string `json:"postTitle"`
+	PostDeleted bool   `json:"postDeleted"`
+	ReportCount int    `json:"reportCount"`
+	// Score is the sum of the category weights (reportcategory.Weight); higher scores are listed first
+	Score            int            `json:"score"`
+	Categories       map[string]int `json:"categories"`
+	LatestReportedAt time.Time      `json:"latestReportedAt"`
+}
+
+// ReportGroupListResponse represents the paginated list of reports grouped by post
+type ReportGroupListResponse struct {
+	Groups   []ReportGroup `json:"groups"`
+	Total    int           `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"pageSize"`
 }
 
 // ReportDetailResponse represents detailed report information with target post
@@ -23,10 +68,16 @@ type ReportDetailResponse struct {
 	ReportID     int    `json:"reportId"`
 	ReporterID   string `json:"reporterGoogleId"`
 	TargetPostID int    `json:"targetPostId"`
+	Category     string `json:"category"`
 	Reason       string `json:"reason"`
 	ReportedAt   string `json:"reportedAt"`
 	Handled      bool   `json:"handled"`
 	Deleted      bool   `json:"deleted"`
+	// Resolution chosen by the admin (empty while open)
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolutionNote string     `json:"resolutionNote,omitempty"`
+	ResolvedBy     string     `json:"resolvedBy,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
 	// Target post details
 	Post *PostInfo `json:"post,omitempty"`
 }
@@ -40,10 +91,27 @@ type PostInfo struct {
 	PostDate string `json:"postDate"`
 }
 
+// ResolveReportRequest represents the admin's decision on a report
+type ResolveReportRequest struct {
+	Resolution  string `json:"resolution" binding:"required"` // dismiss, remove_post, warn_author, suspend_author
+	Note        string `json:"note" binding:"required"`       // 対応のメモ（通報者・投稿者への通知にも含まれる）
+	SuspendDays int    `json:"suspendDays"`                   // suspend_author の利用停止日数（省略時は7日）
+}
+
+// ResolveReportResult represents the reports resolved together and the sanction given to the author
+type ResolveReportResult struct {
+	ReportID          int32                `json:"reportId"`
+	PostID            int32                `json:"postId"`
+	Resolution        string               `json:"resolution"`
+	ResolvedReportIDs []int32              `json:"resolvedReportIds"`
+	Sanction          *models.UserSanction `json:"sanction,omitempty"`
+}
+
 // AdminReportService handles admin report management business logic
 type AdminReportService struct {
 	reportRepo *adminrepo.ReportRepository
 	db         *gorm.DB
+	now        func() time.Time
 }
 
 // NewAdminReportService creates a new AdminReportService
@@ -51,6 +119,7 @@ func NewAdminReportService(reportRepo *adminrepo.ReportRepository, db *gorm.DB)
 	return &AdminReportService{
 		reportRepo: reportRepo,
 		db:         db,
+		now:        time.Now,
 	}
 }
 
@@ -68,34 +137,119 @@ func (s *AdminReportService) GetReports(page, pageSize int, handled *bool) (*Rep
 		return nil, err
 	}
 
+	items := make([]ReportDetailResponse, len(reports))
+	for i := range reports {
+		items[i] = *toReportDetailResponse(&reports[i])
+	}
+
 	return &ReportListResponse{
-		Reports:  reports,
+		Reports:  items,
 		Total:    total,
 		Page:     page,
 		PageSize: pageSize,
 	}, nil
 }
 
-// GetReportDetail retrieves a report with target post information
-func (s *AdminReportService) GetReportDetail(reportID int32) (*ReportDetailResponse, error) {
-	report, err := s.reportRepo.FindByID(reportID)
-	if err != nil {
-		return nil, errors.New("report not found")
-	}
-
-	response := &ReportDetailResponse{
+// toReportDetailResponse converts a report to its response without the target post
+func toReportDetailResponse(report *models.Report) *ReportDetailResponse {
+	return &ReportDetailResponse{
 		ReportID:     int(report.ReportID),
 		ReporterID:   report.UserID,
 		TargetPostID: int(report.PostID),
+		Category:     report.Category,
 		Reason:       report.Reason,
 		ReportedAt:   report.Date.Format("2006-01-02T15:04:05Z07:00"),
 		Handled:      report.ReportFlag,
 		Deleted:      report.RemoveFlag,
+
+		Resolution:     report.Resolution,
+		ResolutionNote: report.ResolutionNote,
+		ResolvedBy:     report.ResolvedBy,
+		ResolvedAt:     report.ResolvedAt,
+	}
+}
+
+// GetReportGroups retrieves reports grouped by target post, most urgent first.
+// 分類の重みの合計が大きい投稿（個人情報・危険な内容の通報が多い投稿）を先に返します。
+// handled が nil の場合は未処理の通報のみを集計します。
+func (s *AdminReportService) GetReportGroups(page, pageSize int, handled *bool) (*ReportGroupListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	if handled == nil {
+		open := false
+		handled = &open
+	}
+
+	rows, total, err := s.reportRepo.FindGroupedByPost(page, pageSize, handled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to group reports: %w", err)
+	}
+	postIDs := make([]int32, len(rows))
+	for i, row := range rows {
+		postIDs[i] = row.PostID
+	}
+	counts, err := s.reportRepo.CountByPostAndCategory(postIDs, handled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count report categories: %w", err)
+	}
+	// 削除済みの投稿もタイトルを表示するため Unscoped で取得する
+	var posts []models.Post
+	if len(postIDs) > 0 {
+		if err := s.db.Unscoped().Where("postId IN ?", postIDs).Find(&posts).Error; err != nil {
+			return nil, fmt.Errorf("failed to find posts: %w", err)
+		}
+	}
+
+	groups := make([]ReportGroup, len(rows))
+	index := make(map[int32]int, len(rows))
+	for i, row := range rows {
+		index[row.PostID] = i
+		groups[i] = ReportGroup{
+			PostID:           row.PostID,
+			PostTitle:        postTitle(nil, row.PostID),
+			PostDeleted:      true,
+			ReportCount:      row.ReportCount,
+			Score:            row.Score,
+			Categories:       map[string]int{},
+			LatestReportedAt: row.LatestAt,
+		}
+	}
+	for _, c := range counts {
+		if i, ok := index[c.PostID]; ok {
+			groups[i].Categories[c.Category] = c.Count
+		}
+	}
+	for i := range posts {
+		if j, ok := index[posts[i].PostID]; ok {
+			groups[j].PostTitle = posts[i].Title
+			groups[j].PostDeleted = posts[i].DeletedAt.Valid
+		}
+	}
+
+	return &ReportGroupListResponse{
+		Groups:   groups,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetReportDetail retrieves a report with target post information
+func (s *AdminReportService) GetReportDetail(reportID int32) (*ReportDetailResponse, error) {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		return nil, ErrReportNotFound
 	}
 
-	// Get target post information
+	response := toReportDetailResponse(report)
+
+	// Get target post information（削除済みの投稿も含む）
 	var post models.Post
-	if err := s.db.Where("postId = ?", report.PostID).First(&post).Error; err == nil {
+	if err := s.db.Unscoped().Where("postId = ?", report.PostID).First(&post).Error; err == nil {
 		response.Post = &PostInfo{
 			PostID:   int(post.PostID),
 			Title:    post.Title,
@@ -111,17 +265,222 @@ func (s *AdminReportService) GetReportDetail(reportID int32) (*ReportDetailRespo
 	return response, nil
 }
 
-// MarkAsHandled marks a report as handled
-func (s *AdminReportService) MarkAsHandled(id int32) error {
-	// Verify the report exists
-	report, err := s.reportRepo.FindByID(id)
+// ResolveReport resolves a report and every other open report on the same post with the chosen resolution.
+// 投稿の削除・投稿者への警告・利用停止を同じトランザクションで行い、通報者と投稿者への通知と監査ログも記録します。
+//
+// Parameters:
+//   - id: 通報ID
+//   - req: 対応内容（resolution）とメモ（note。通報者への通知にも含まれる）
+//   - actor: 操作した管理者
+//
+// Returns:
+//   - *ResolveReportResult: 処理した通報と、警告・利用停止の記録
+//   - error: ErrReportNotFound, ErrReportAlreadyHandled, ErrPostNotFound, 入力エラーまたはDBエラー
+func (s *AdminReportService) ResolveReport(id int32, req ResolveReportRequest, actor AuditActor) (*ResolveReportResult, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	if !models.IsValidReportResolution(req.Resolution) {
+		return nil, ErrInvalidResolution
+	}
+	if req.Note == "" {
+		return nil, ErrResolutionNoteRequired
+	}
+	if utf8.RuneCountInString(req.Note) > maxResolutionNoteLength {
+		return nil, ErrResolutionNoteTooLong
+	}
+	if req.Resolution == models.ReportResolutionSuspendAuthor {
+		if req.SuspendDays == 0 {
+			req.SuspendDays = defaultSuspendDays
+		}
+		if req.SuspendDays < 1 || req.SuspendDays > maxSuspendDays {
+			return nil, ErrInvalidSuspendDays
+		}
+	}
+
+	var result *ResolveReportResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		report, err := s.reportRepo.WithTx(tx).FindByID(id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReportNotFound
+			}
+			return fmt.Errorf("failed to find report: %w", err)
+		}
+		if report.ReportFlag {
+			return ErrReportAlreadyHandled
+		}
+
+		// 削除済みの投稿への通報は「問題なし」としてのみ処理できる
+		var post *models.Post
+		var found models.Post
+		if err := tx.Where("postId = ?", report.PostID).First(&found).Error; err == nil {
+			post = &found
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to find post: %w", err)
+		} else if req.Resolution != models.ReportResolutionDismiss {
+			return ErrPostNotFound
+		}
+
+		now := s.now()
+		result = &ResolveReportResult{ReportID: id, PostID: report.PostID, Resolution: req.Resolution}
+		if result.ResolvedReportIDs, err = resolvePostReports(tx, report.PostID, postTitle(post, report.PostID), req.Resolution, req.Note, actor, now); err != nil {
+			return err
+		}
+
+		switch {
+		case models.ReportResolutionRemovesPost(req.Resolution):
+			if err := removePost(tx, report.PostID, now); err != nil {
+				return err
+			}
+		case post != nil && post.ModerationStatus == models.ModerationHidden:
+			// 投稿を残す場合、通報で自動非表示になった投稿は公開に戻す
+			if err := tx.Model(&models.Post{}).Where("postId = ?", report.PostID).Updates(map[string]interface{}{
+				"moderationStatus": models.ModerationApproved,
+				"moderatedAt":      now,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to restore post: %w", err)
+			}
+		}
+
+		if req.Resolution == models.ReportResolutionWarnAuthor || req.Resolution == models.ReportResolutionSuspendAuthor {
+			if result.Sanction, err = s.sanctionAuthor(tx, post, report.ReportID, req, actor, now); err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(tx, actor, models.AuditActionReportResolve, models.AuditTargetReport, strconv.Itoa(int(id)), toReportDetailResponse(report), result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sanctionAuthor は投稿者への警告・利用停止を記録し、投稿者に通知します。
+func (s *AdminReportService) sanctionAuthor(tx *gorm.DB, post *models.Post, reportID int32, req ResolveReportRequest, actor AuditActor, now time.Time) (*models.UserSanction, error) {
+	sanction := &models.UserSanction{
+		UserID:    post.UserID,
+		Kind:      models.SanctionWarning,
+		Reason:    req.Note,
+		ReportID:  &reportID,
+		StartsAt:  now,
+		CreatedBy: actor.GoogleID,
+		CreatedAt: now,
+	}
+	if req.Resolution == models.ReportResolutionSuspendAuthor {
+		endsAt := now.AddDate(0, 0, req.SuspendDays)
+		sanction.Kind = models.SanctionSuspension
+		sanction.EndsAt = &endsAt
+	}
+	if err := adminrepo.NewUserSanctionRepository(tx).Create(sanction); err != nil {
+		return nil, fmt.Errorf("failed to record sanction: %w", err)
+	}
+
+	if err := notifySanction(tx, sanction, post.Title, !models.ReportResolutionRemovesPost(req.Resolution), fmt.Sprintf("moderation-notice:%d", sanction.ID)); err != nil {
+		return nil, err
+	}
+	return sanction, nil
+}
+
+// notifySanction は警告・利用停止・利用禁止をユーザーにメールで通知します（tx 内で呼び出す）。
+// postTitle は通報された投稿のタイトルで、投稿によらない場合は空です。postKept は投稿を削除しなかった場合に true です。
+// key は outbox の冪等キーです。
+func notifySanction(tx *gorm.DB, sanction *models.UserSanction, postTitle string, postKept bool, key string) error {
+	to := userEmails(tx, []string{sanction.UserID})[sanction.UserID]
+	if to == "" {
+		return nil
+	}
+	msg, err := outbox.NewEmail(key, notification.Message{
+		Kind: notification.KindModerationNotice,
+		To:   to,
+		Data: notification.ModerationNoticeData{
+			PostTitle: postTitle,
+			PostKept:  postKept,
+			Sanction:  sanction.Kind,
+			Note:      sanction.Reason,
+			Until:     sanction.EndsAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(tx, msg)
+}
+
+// resolvePostReports は投稿への未処理の通報をまとめて処理済みにし、通報者に対応結果を通知します（tx 内で呼び出す）。
+// 通報は削除せず、対応の記録として残します。
+func resolvePostReports(tx *gorm.DB, postID int32, title, resolution, note string, actor AuditActor, at time.Time) ([]int32, error) {
+	reportRepo := adminrepo.NewReportRepository(tx)
+	open, err := reportRepo.FindOpenByPostID(postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reports: %w", err)
+	}
+	ids := make([]int32, len(open))
+	for i, r := range open {
+		ids[i] = r.ReportID
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	removed := models.ReportResolutionRemovesPost(resolution)
+	n, err := reportRepo.Resolve(ids, resolution, note, actor.GoogleID, removed, at)
 	if err != nil {
-		return errors.New("report not found")
+		return nil, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+	if n != int64(len(ids)) {
+		// 他の管理者が同時に処理した
+		return nil, ErrReportAlreadyHandled
+	}
+
+	// 同じ通報者には1通だけ送る
+	reporters := make([]string, 0, len(open))
+	first := make(map[string]int32, len(open))
+	for _, r := range open {
+		if _, ok := first[r.UserID]; !ok {
+			first[r.UserID] = r.ReportID
+			reporters = append(reporters, r.UserID)
+		}
+	}
+	emails := userEmails(tx, reporters)
+	for _, userID := range reporters {
+		to := emails[userID]
+		if to == "" {
+			continue
+		}
+		msg, err := outbox.NewEmail(fmt.Sprintf("report-outcome:%d", first[userID]), notification.Message{
+			Kind: notification.KindReportOutcome,
+			To:   to,
+			Data: notification.ReportOutcomeData{PostTitle: title, Outcome: resolution, Note: note},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := outbox.Enqueue(tx, msg); err != nil {
+			return nil, err
+		}
 	}
+	return ids, nil
+}
 
-	if report.ReportFlag {
-		return errors.New("report is already handled")
+// userEmails はユーザーのメールアドレスを取得します。
+// 通知は処理の成否に影響させないため、取得できない場合はログに残して空のまま返します。
+func userEmails(tx *gorm.DB, googleIDs []string) map[string]string {
+	emails := make(map[string]string, len(googleIDs))
+	users, err := sharedrepo.NewUserRepository(tx).FindByGoogleIDs(googleIDs)
+	if err != nil {
+		log.Printf("Warning: failed to look up emails for notification: %v", err)
+		return emails
 	}
+	for _, u := range users {
+		emails[u.GoogleID] = u.Gmail
+	}
+	return emails
+}
 
-	return s.reportRepo.MarkAsHandled(id)
+// postTitle は通知に使う投稿のタイトルを返します（削除済みの場合は投稿ID）。
+func postTitle(post *models.Post, postID int32) string {
+	if post == nil {
+		return fmt.Sprintf("#%d", postID)
+	}
+	return post.Title
 }
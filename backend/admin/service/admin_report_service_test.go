@@ -1,8 +1,11 @@
 package service
 
 import (
+	"strings"
 	"testing"
 
+	"kojan-map/business/pkg/models"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,6 +62,43 @@ func TestAdminReportService_MarkAsHandled(t *testing.T) {
 	})
 }
 
+func TestAdminReportService_ResolveReport_Validation(t *testing.T) {
+	// 入力の検証はDBに接続する前に行う
+	s := NewAdminReportService(nil, nil)
+	actor := AuditActor{GoogleID: "admin-1"}
+
+	tests := []struct {
+		name string
+		req  ResolveReportRequest
+		want error
+	}{
+		{"unknown resolution", ResolveReportRequest{Resolution: "delete", Note: "spam"}, ErrInvalidResolution},
+		{"blank note", ResolveReportRequest{Resolution: models.ReportResolutionDismiss, Note: "  "}, ErrResolutionNoteRequired},
+		{"note too long", ResolveReportRequest{Resolution: models.ReportResolutionDismiss, Note: strings.Repeat("あ", 1001)}, ErrResolutionNoteTooLong},
+		{"negative suspension", ResolveReportRequest{Resolution: models.ReportResolutionSuspendAuthor, Note: "spam", SuspendDays: -1}, ErrInvalidSuspendDays},
+		{"suspension too long", ResolveReportRequest{Resolution: models.ReportResolutionSuspendAuthor, Note: "spam", SuspendDays: 366}, ErrInvalidSuspendDays},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ResolveReport(1, tt.req, actor)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestReportResolutionRemovesPost(t *testing.T) {
+	// 警告は投稿を残し、投稿の削除と利用停止だけが投稿を削除する
+	assert.False(t, models.ReportResolutionRemovesPost(models.ReportResolutionDismiss))
+	assert.False(t, models.ReportResolutionRemovesPost(models.ReportResolutionWarnAuthor))
+	assert.True(t, models.ReportResolutionRemovesPost(models.ReportResolutionRemovePost))
+	assert.True(t, models.ReportResolutionRemovesPost(models.ReportResolutionSuspendAuthor))
+}
+
+func TestPostTitle(t *testing.T) {
+	assert.Equal(t, "朝市", postTitle(&models.Post{Title: "朝市"}, 1))
+	assert.Equal(t, "#42", postTitle(nil, 42))
+}
+
 // Helper functions for testing
 func validateReportExists(id int) error {
 	if id == 0 {
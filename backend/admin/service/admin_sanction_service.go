@@ -0,0 +1,326 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	adminrepo "kojan-map/admin/repository"
+	"kojan-map/business/pkg/models"
+	sharedrepo "kojan-map/shared/repository"
+
+	"gorm.io/gorm"
+)
+
+// Sanction errors
+var (
+	ErrSanctionNotFound       = errors.New("sanction not found")
+	ErrSanctionUserNotFound   = errors.New("user not found")
+	ErrSanctionReasonRequired = errors.New("reason is required")
+	ErrSanctionReasonTooLong  = errors.New("reason is too long")
+	ErrInvalidSanctionPeriod  = errors.New("specify either days (1-365) or a future until within 365 days")
+	ErrInvalidSanctionKind    = errors.New("kind must be one of warning, suspension, ban")
+	ErrCannotSanctionAdmin    = errors.New("cannot suspend or ban admin users")
+	ErrUserAlreadyBanned      = errors.New("user is already banned")
+	ErrSanctionNotActive      = errors.New("sanction is not active")
+	ErrSanctionNotExtendable  = errors.New("only suspensions can be extended")
+)
+
+// maxSanctionReasonLength is the maximum length of a sanction reason
+const maxSanctionReasonLength = 1000
+
+// SanctionQuery represents the filters of the sanction list.
+type SanctionQuery struct {
+	UserID   string
+	Kind     string
+	Active   *bool
+	Page     int
+	PageSize int
+}
+
+// SanctionView represents a sanction with whether it currently restricts the account
+type SanctionView struct {
+	models.UserSanction
+	Active bool `json:"active"`
+}
+
+// SanctionListResponse represents the paginated sanction list response
+type SanctionListResponse struct {
+	Sanctions []SanctionView `json:"sanctions"`
+	Total     int64          `json:"total"`
+	Page      int            `json:"page"`
+	PageSize  int            `json:"pageSize"`
+}
+
+// SuspendUserRequest represents a suspension given directly by an admin
+// days と until のどちらかを指定します（どちらもない場合は7日）
+type SuspendUserRequest struct {
+	Reason string     `json:"reason" binding:"required"`
+	Days   int        `json:"days"`
+	Until  *time.Time `json:"until"`
+}
+
+// BanUserRequest represents a permanent ban
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// LiftSanctionRequest represents lifting a suspension or ban before it ends
+type LiftSanctionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ExtendSanctionRequest represents extending a suspension
+// days（現在の終了日時から延長する日数）と until（新しい終了日時）のどちらかを指定します
+type ExtendSanctionRequest struct {
+	Days  int        `json:"days"`
+	Until *time.Time `json:"until"`
+}
+
+// AdminSanctionService handles suspending, banning and lifting user accounts
+// 有効な利用停止・利用禁止は、認証ミドルウェアとログイン処理で拒否されます（kojan-map/business/pkg/accountstatus）
+type AdminSanctionService struct {
+	db           *gorm.DB
+	userRepo     *sharedrepo.UserRepository
+	sanctionRepo *adminrepo.UserSanctionRepository
+	now          func() time.Time
+}
+
+// NewAdminSanctionService creates a new AdminSanctionService
+func NewAdminSanctionService(db *gorm.DB, userRepo *sharedrepo.UserRepository, sanctionRepo *adminrepo.UserSanctionRepository) *AdminSanctionService {
+	return &AdminSanctionService{
+		db:           db,
+		userRepo:     userRepo,
+		sanctionRepo: sanctionRepo,
+		now:          time.Now,
+	}
+}
+
+// GetSanctions retrieves warnings, suspensions and bans, newest first
+func (s *AdminSanctionService) GetSanctions(q SanctionQuery) (*SanctionListResponse, error) {
+	switch q.Kind {
+	case "", models.SanctionWarning, models.SanctionSuspension, models.SanctionBan:
+	default:
+		return nil, ErrInvalidSanctionKind
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 || q.PageSize > 100 {
+		q.PageSize = 20
+	}
+
+	now := s.now()
+	sanctions, total, err := s.sanctionRepo.FindAll(adminrepo.UserSanctionFilter{
+		UserID: q.UserID,
+		Kind:   q.Kind,
+		Active: q.Active,
+		At:     now,
+	}, q.Page, q.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanctions: %w", err)
+	}
+
+	views := make([]SanctionView, len(sanctions))
+	for i := range sanctions {
+		views[i] = SanctionView{UserSanction: sanctions[i], Active: sanctions[i].IsActive(now)}
+	}
+	return &SanctionListResponse{
+		Sanctions: views,
+		Total:     total,
+		Page:      q.Page,
+		PageSize:  q.PageSize,
+	}, nil
+}
+
+// SuspendUser suspends a user until the given time and notifies the user
+func (s *AdminSanctionService) SuspendUser(userID string, req SuspendUserRequest, actor AuditActor) (*models.UserSanction, error) {
+	reason, err := validateSanctionReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	endsAt, err := sanctionEnd(now, req.Days, req.Until, defaultSuspendDays)
+	if err != nil {
+		return nil, err
+	}
+	return s.restrict(userID, models.SanctionSuspension, reason, &endsAt, models.AuditActionUserSuspend, actor, now)
+}
+
+// BanUser permanently bans a user and notifies the user
+func (s *AdminSanctionService) BanUser(userID string, req BanUserRequest, actor AuditActor) (*models.UserSanction, error) {
+	reason, err := validateSanctionReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	return s.restrict(userID, models.SanctionBan, reason, nil, models.AuditActionUserBan, actor, s.now())
+}
+
+// restrict records a suspension or ban, notifies the user and records the action in the audit log
+func (s *AdminSanctionService) restrict(userID, kind, reason string, endsAt *time.Time, action string, actor AuditActor, now time.Time) (*models.UserSanction, error) {
+	sanction := &models.UserSanction{
+		UserID:    userID,
+		Kind:      kind,
+		Reason:    reason,
+		StartsAt:  now,
+		EndsAt:    endsAt,
+		CreatedBy: actor.GoogleID,
+		CreatedAt: now,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		user, err := s.userRepo.WithTx(tx).FindByGoogleID(userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSanctionUserNotFound
+			}
+			return err
+		}
+		if user.Role == models.RoleAdmin {
+			return ErrCannotSanctionAdmin
+		}
+
+		sanctionRepo := s.sanctionRepo.WithTx(tx)
+		active := true
+		_, banned, err := sanctionRepo.FindAll(adminrepo.UserSanctionFilter{UserID: userID, Kind: models.SanctionBan, Active: &active, At: now}, 1, 1)
+		if err != nil {
+			return err
+		}
+		if banned > 0 {
+			return ErrUserAlreadyBanned
+		}
+
+		if err := sanctionRepo.Create(sanction); err != nil {
+			return fmt.Errorf("failed to record sanction: %w", err)
+		}
+		if err := notifySanction(tx, sanction, "", false, fmt.Sprintf("moderation-notice:%d", sanction.ID)); err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, action, models.AuditTargetUser, userID, nil, sanction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sanction, nil
+}
+
+// LiftSanction lifts an active suspension or ban before it ends
+func (s *AdminSanctionService) LiftSanction(id int64, req LiftSanctionRequest, actor AuditActor) (*models.UserSanction, error) {
+	reason, err := validateSanctionReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *models.UserSanction
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		sanctionRepo := s.sanctionRepo.WithTx(tx)
+		before, err := s.findForUpdate(sanctionRepo, id)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		if !before.IsActive(now) {
+			return ErrSanctionNotActive
+		}
+
+		if _, err := sanctionRepo.Lift(id, actor.GoogleID, reason, now); err != nil {
+			return fmt.Errorf("failed to lift sanction: %w", err)
+		}
+		if after, err = sanctionRepo.FindByIDForUpdate(id); err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, models.AuditActionSanctionLift, models.AuditTargetSanction, strconv.FormatInt(id, 10), before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// ExtendSanction moves the end of an active suspension later and notifies the user
+func (s *AdminSanctionService) ExtendSanction(id int64, req ExtendSanctionRequest, actor AuditActor) (*models.UserSanction, error) {
+	var after *models.UserSanction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		sanctionRepo := s.sanctionRepo.WithTx(tx)
+		before, err := s.findForUpdate(sanctionRepo, id)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		if before.Kind != models.SanctionSuspension || before.EndsAt == nil {
+			return ErrSanctionNotExtendable
+		}
+		if !before.IsActive(now) {
+			return ErrSanctionNotActive
+		}
+
+		// 日数は現在の終了日時から数える。延長後も利用停止は今から最長365日
+		endsAt, err := sanctionEnd(*before.EndsAt, req.Days, req.Until, 0)
+		if err != nil || !endsAt.After(*before.EndsAt) || endsAt.After(now.AddDate(0, 0, maxSuspendDays)) {
+			return ErrInvalidSanctionPeriod
+		}
+
+		if err := sanctionRepo.UpdateEndsAt(id, endsAt); err != nil {
+			return fmt.Errorf("failed to extend sanction: %w", err)
+		}
+		if after, err = sanctionRepo.FindByIDForUpdate(id); err != nil {
+			return err
+		}
+		key := fmt.Sprintf("moderation-notice:%d:extend:%d", id, endsAt.Unix())
+		if err := notifySanction(tx, after, "", false, key); err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, models.AuditActionSanctionExtend, models.AuditTargetSanction, strconv.FormatInt(id, 10), before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// findForUpdate locks the sanction row until the end of the transaction
+func (s *AdminSanctionService) findForUpdate(repo *adminrepo.UserSanctionRepository, id int64) (*models.UserSanction, error) {
+	sanction, err := repo.FindByIDForUpdate(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSanctionNotFound
+		}
+		return nil, err
+	}
+	return sanction, nil
+}
+
+// validateSanctionReason trims the reason and checks its length
+func validateSanctionReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", ErrSanctionReasonRequired
+	}
+	if utf8.RuneCountInString(reason) > maxSanctionReasonLength {
+		return "", ErrSanctionReasonTooLong
+	}
+	return reason, nil
+}
+
+// sanctionEnd returns from + days, or until when given
+// どちらも指定されていない場合は defaultDays（0 の場合はエラー）を使います
+func sanctionEnd(from time.Time, days int, until *time.Time, defaultDays int) (time.Time, error) {
+	if days != 0 && until != nil {
+		return time.Time{}, ErrInvalidSanctionPeriod
+	}
+	if until != nil {
+		if !until.After(from) || until.After(from.AddDate(0, 0, maxSuspendDays)) {
+			return time.Time{}, ErrInvalidSanctionPeriod
+		}
+		return *until, nil
+	}
+	if days == 0 {
+		days = defaultDays
+	}
+	if days < 1 || days > maxSuspendDays {
+		return time.Time{}, ErrInvalidSanctionPeriod
+	}
+	return from.AddDate(0, 0, days), nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"kojan-map/business/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminSanctionService_Validation(t *testing.T) {
+	// 入力の検証はDBに接続する前に行う
+	s := NewAdminSanctionService(nil, nil, nil)
+	actor := AuditActor{GoogleID: "admin-1"}
+	past := time.Now().Add(-time.Hour)
+
+	_, err := s.SuspendUser("user-1", SuspendUserRequest{Reason: " "}, actor)
+	assert.ErrorIs(t, err, ErrSanctionReasonRequired)
+	_, err = s.SuspendUser("user-1", SuspendUserRequest{Reason: strings.Repeat("あ", 1001)}, actor)
+	assert.ErrorIs(t, err, ErrSanctionReasonTooLong)
+	_, err = s.SuspendUser("user-1", SuspendUserRequest{Reason: "spam", Days: 400}, actor)
+	assert.ErrorIs(t, err, ErrInvalidSanctionPeriod)
+	_, err = s.SuspendUser("user-1", SuspendUserRequest{Reason: "spam", Until: &past}, actor)
+	assert.ErrorIs(t, err, ErrInvalidSanctionPeriod)
+	_, err = s.BanUser("user-1", BanUserRequest{Reason: ""}, actor)
+	assert.ErrorIs(t, err, ErrSanctionReasonRequired)
+	_, err = s.LiftSanction(1, LiftSanctionRequest{Reason: "\n"}, actor)
+	assert.ErrorIs(t, err, ErrSanctionReasonRequired)
+	_, err = s.GetSanctions(SanctionQuery{Kind: "mute"})
+	assert.ErrorIs(t, err, ErrInvalidSanctionKind)
+}
+
+func TestSanctionEnd(t *testing.T) {
+	from := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	until := from.AddDate(0, 0, 30)
+
+	end, err := sanctionEnd(from, 0, nil, defaultSuspendDays)
+	require.NoError(t, err)
+	assert.Equal(t, from.AddDate(0, 0, 7), end)
+
+	end, err = sanctionEnd(from, 3, nil, defaultSuspendDays)
+	require.NoError(t, err)
+	assert.Equal(t, from.AddDate(0, 0, 3), end)
+
+	end, err = sanctionEnd(from, 0, &until, defaultSuspendDays)
+	require.NoError(t, err)
+	assert.Equal(t, until, end)
+
+	// 延長では日数か終了日時の指定が必要
+	_, err = sanctionEnd(from, 0, nil, 0)
+	assert.ErrorIs(t, err, ErrInvalidSanctionPeriod)
+	// 日数と終了日時は同時に指定できない
+	_, err = sanctionEnd(from, 3, &until, defaultSuspendDays)
+	assert.ErrorIs(t, err, ErrInvalidSanctionPeriod)
+	tooLate := from.AddDate(0, 0, 366)
+	_, err = sanctionEnd(from, 0, &tooLate, defaultSuspendDays)
+	assert.ErrorIs(t, err, ErrInvalidSanctionPeriod)
+}
+
+func TestUserSanction_IsActive(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	assert.True(t, (&models.UserSanction{Kind: models.SanctionSuspension, StartsAt: earlier, EndsAt: &later}).IsActive(now))
+	assert.True(t, (&models.UserSanction{Kind: models.SanctionBan, StartsAt: earlier}).IsActive(now))
+	assert.False(t, (&models.UserSanction{Kind: models.SanctionSuspension, StartsAt: earlier, EndsAt: &earlier}).IsActive(now), "ended")
+	assert.False(t, (&models.UserSanction{Kind: models.SanctionBan, StartsAt: earlier, LiftedAt: &earlier}).IsActive(now), "lifted")
+	assert.False(t, (&models.UserSanction{Kind: models.SanctionWarning, StartsAt: earlier}).IsActive(now), "warning")
+}
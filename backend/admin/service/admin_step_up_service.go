@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/mfa"
+	"kojan-map/business/pkg/notification"
+)
+
+// Step-up errors
+var (
+	ErrStepUpCodeInvalid = errors.New("invalid or expired verification code")
+	ErrStepUpSendFailed  = errors.New("failed to send verification code")
+)
+
+// StepUpMethodEmail is the only step-up method for admins: a one-time code sent by email.
+const StepUpMethodEmail = "email"
+
+// stepUpKeyPrefix keeps admin step-up codes apart from business login codes for the same address.
+const stepUpKeyPrefix = "admin-step-up:"
+
+// StepUpChallengeResponse represents the response after a verification code was sent.
+// The code itself is only delivered through the notifier (in development the log transport prints it).
+type StepUpChallengeResponse struct {
+	Method string `json:"method"`
+}
+
+// StepUpResponse represents the response after a successful step-up.
+type StepUpResponse struct {
+	Token        string    `json:"jwt_token"`
+	AuthTime     time.Time `json:"authTime"`
+	ExpiresIn    int       `json:"expiresIn"`    // token lifetime in seconds
+	StepUpWindow int       `json:"stepUpWindow"` // seconds destructive actions stay allowed
+}
+
+// AdminStepUpService issues admin tokens that carry amr/auth_time after a second factor.
+type AdminStepUpService struct {
+	tokens   *jwt.TokenManager
+	codes    *mfa.MFAValidator
+	notifier notification.NotificationService
+	tokenTTL time.Duration
+	window   time.Duration
+}
+
+// NewAdminStepUpService creates a new AdminStepUpService.
+//
+// Parameters:
+//   - tokens: ユーザー・管理者で共通のトークン管理
+//   - codes: 確認コードの保存先（複数レプリカでは共有ストアを使用）
+//   - notifier: 確認コードの送信先
+//   - tokenTTL: 追加認証後のアクセストークンの有効期間
+//   - window: 追加認証後に削除・承認などの操作を許可する期間
+func NewAdminStepUpService(tokens *jwt.TokenManager, codes *mfa.MFAValidator, notifier notification.NotificationService, tokenTTL, window time.Duration) *AdminStepUpService {
+	return &AdminStepUpService{
+		tokens:   tokens,
+		codes:    codes,
+		notifier: notifier,
+		tokenTTL: tokenTTL,
+		window:   window,
+	}
+}
+
+// Window returns how long a step-up stays valid for destructive actions.
+func (s *AdminStepUpService) Window() time.Duration {
+	return s.window
+}
+
+// SendCode generates a one-time code and sends it to the admin's address.
+func (s *AdminStepUpService) SendCode(claims *jwt.Claims) (*StepUpChallengeResponse, error) {
+	if claims == nil || claims.Gmail == "" {
+		return nil, errors.New("admin email not found in token")
+	}
+
+	code, err := s.codes.GenerateCode(stepUpKeyPrefix + claims.Gmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	if err := s.notifier.SendMFACode(claims.Gmail, code); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStepUpSendFailed, err)
+	}
+
+	return &StepUpChallengeResponse{Method: StepUpMethodEmail}, nil
+}
+
+// Verify checks the code and issues a new access token with amr and auth_time.
+// The new token stays bound to the same login session as the current one.
+func (s *AdminStepUpService) Verify(claims *jwt.Claims, code string) (*StepUpResponse, error) {
+	if claims == nil || claims.Gmail == "" {
+		return nil, errors.New("admin email not found in token")
+	}
+	if code == "" {
+		return nil, ErrStepUpCodeInvalid
+	}
+
+	valid, err := s.codes.VerifyCode(stepUpKeyPrefix+claims.Gmail, code)
+	if err != nil || !valid {
+		return nil, ErrStepUpCodeInvalid
+	}
+
+	token, err := s.tokens.GenerateStepUpToken(claims.UserID, claims.Gmail, claims.Role, claims.SessionID,
+		[]string{jwt.AMRMFA, jwt.AMROTP}, s.tokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StepUpResponse{
+		Token:        token,
+		AuthTime:     time.Now(),
+		ExpiresIn:    int(s.tokenTTL.Seconds()),
+		StepUpWindow: int(s.window.Seconds()),
+	}, nil
+}
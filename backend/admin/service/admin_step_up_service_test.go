@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/mfa"
+	"kojan-map/business/pkg/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier captures sent codes instead of emailing them.
+type recordingNotifier struct {
+	sent map[string]string
+}
+
+func (n *recordingNotifier) SendMFACode(email, code string) error {
+	n.sent[email] = code
+	return nil
+}
+
+func (n *recordingNotifier) Send(_ context.Context, msg notification.Message) error {
+	if data, ok := msg.Data.(notification.MFACodeData); ok {
+		n.sent[msg.To] = data.Code
+	}
+	return nil
+}
+
+func newTestStepUpService(t *testing.T) (*AdminStepUpService, *jwt.TokenManager, *recordingNotifier) {
+	t.Helper()
+	tokens := jwt.NewTokenManagerWithSecret("test-secret")
+	codes := mfa.NewMFAValidator()
+	t.Cleanup(func() {
+		tokens.Stop()
+		codes.Stop()
+	})
+	notifier := &recordingNotifier{sent: map[string]string{}}
+	return NewAdminStepUpService(tokens, codes, notifier, 15*time.Minute, 10*time.Minute), tokens, notifier
+}
+
+func TestAdminStepUpService_Verify(t *testing.T) {
+	svc, tokens, notifier := newTestStepUpService(t)
+	claims := &jwt.Claims{UserID: "admin-1", Gmail: "admin@example.com", Role: "admin", SessionID: "session-1"}
+
+	_, err := svc.SendCode(claims)
+	require.NoError(t, err)
+	code := notifier.sent["admin@example.com"]
+	require.NotEmpty(t, code)
+
+	t.Run("rejects wrong code", func(t *testing.T) {
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+		_, err := svc.Verify(claims, wrong)
+		assert.ErrorIs(t, err, ErrStepUpCodeInvalid)
+	})
+
+	t.Run("issues token with amr and auth_time", func(t *testing.T) {
+		resp, err := svc.Verify(claims, code)
+		require.NoError(t, err)
+		assert.Equal(t, 600, resp.StepUpWindow)
+
+		stepped, err := tokens.VerifyTokenWithType(resp.Token, "access")
+		require.NoError(t, err)
+		assert.Equal(t, "admin-1", stepped.UserID)
+		assert.Equal(t, "session-1", stepped.SessionID)
+		assert.True(t, stepped.SteppedUpWithin(svc.Window(), time.Now()))
+	})
+
+	t.Run("code is single use", func(t *testing.T) {
+		_, err := svc.Verify(claims, code)
+		assert.ErrorIs(t, err, ErrStepUpCodeInvalid)
+	})
+}
+
+func TestAdminStepUpService_SendCode(t *testing.T) {
+	t.Run("sends code only by email", func(t *testing.T) {
+		for _, env := range []string{"dev", "production"} {
+			t.Setenv("APP_ENV", env)
+			t.Setenv("GO_ENV", env)
+			svc, _, notifier := newTestStepUpService(t)
+			resp, err := svc.SendCode(&jwt.Claims{UserID: "admin-1", Gmail: "admin@example.com", Role: "admin"})
+			require.NoError(t, err)
+			assert.Equal(t, StepUpMethodEmail, resp.Method)
+
+			code := notifier.sent["admin@example.com"]
+			require.NotEmpty(t, code)
+			body, err := json.Marshal(resp)
+			require.NoError(t, err)
+			assert.NotContains(t, string(body), code, "the response must not reveal the code (%s)", env)
+		}
+	})
+
+	t.Run("requires email", func(t *testing.T) {
+		svc, _, _ := newTestStepUpService(t)
+		_, err := svc.SendCode(&jwt.Claims{UserID: "admin-1", Role: "admin"})
+		assert.Error(t, err)
+	})
+}
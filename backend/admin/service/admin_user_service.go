@@ -2,65 +2,177 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
 
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
 	sharedrepo "kojan-map/shared/repository"
+
+	"gorm.io/gorm"
+)
+
+// User list errors
+var (
+	ErrInvalidUserRole   = errors.New("role must be one of user, business, admin")
+	ErrInvalidUserStatus = errors.New("status must be one of active, suspended, banned, deleted")
 )
 
+// userSortColumns maps the sort keys of the user list to SQL
+var userSortColumns = map[string]string{
+	"registrationDate": "user.registrationDate",
+	"gmail":            "user.gmail",
+	"postCount":        "postCount",
+	"reportsReceived":  "reportsReceived",
+	"reportsFiled":     "reportsFiled",
+}
+
+// UserQuery represents the filters and order of the user list
+type UserQuery struct {
+	Query          string // googleId の完全一致、または gmail の部分一致
+	Role           string
+	Status         string     // active, suspended, banned, deleted
+	RegisteredFrom *time.Time // この日時以降に登録（含む）
+	RegisteredTo   *time.Time // この日時より前に登録（含まない）
+	Sort           string     // registrationDate（既定）, gmail, postCount, reportsReceived, reportsFiled
+	Order          string     // asc, desc（既定）
+	Page           int
+	PageSize       int
+}
+
 // UserListResponse represents the paginated user list response
 type UserListResponse struct {
-	Users []models.User `json:"users"`
-	Total int           `json:"total"`
-	Page  int           `json:"page"`
+	Users    []sharedrepo.UserSummary `json:"users"`
+	Total    int                      `json:"total"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"pageSize"`
 }
 
 // AdminUserService handles admin user management business logic
 type AdminUserService struct {
+	db       *gorm.DB
 	userRepo *sharedrepo.UserRepository
+	now      func() time.Time
 }
 
 // NewAdminUserService creates a new AdminUserService
-func NewAdminUserService(userRepo *sharedrepo.UserRepository) *AdminUserService {
-	return &AdminUserService{userRepo: userRepo}
+func NewAdminUserService(db *gorm.DB, userRepo *sharedrepo.UserRepository) *AdminUserService {
+	return &AdminUserService{db: db, userRepo: userRepo, now: time.Now}
 }
 
-// GetUsers retrieves users with pagination
-func (s *AdminUserService) GetUsers(page, pageSize int) (*UserListResponse, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
+// GetUsers retrieves users matching the query with their post and report counts
+func (s *AdminUserService) GetUsers(q UserQuery) (*UserListResponse, error) {
+	filter, order, err := s.userFilter(q)
+	if err != nil {
+		return nil, err
 	}
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
 
-	users, total, err := s.userRepo.FindAll(page, pageSize)
+	users, total, err := s.userRepo.FindAll(filter, order, q.Page, q.PageSize)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 
 	return &UserListResponse{
-		Users: users,
-		Total: total,
-		Page:  page,
+		Users:    users,
+		Total:    total,
+		Page:     q.Page,
+		PageSize: q.PageSize,
 	}, nil
 }
 
-// DeleteUser soft-deletes a user
-func (s *AdminUserService) DeleteUser(googleID string) error {
-	// Verify the user exists
-	user, err := s.userRepo.FindByGoogleID(googleID)
+// ExportUsersCSV writes every user matching the query to w as CSV, streaming rows as they are read
+// ページ指定は無視します
+func (s *AdminUserService) ExportUsersCSV(w io.Writer, q UserQuery) error {
+	filter, order, err := s.userFilter(q)
 	if err != nil {
-		return errors.New("user not found")
+		return err
 	}
 
-	if user.DeletedAt != nil {
-		return errors.New("user is already deleted")
+	out, err := newCSVStream(w, []string{"googleId", "gmail", "role", "registrationDate", "deletedAt", "postCount", "reportsReceived", "reportsFiled"})
+	if err != nil {
+		return err
 	}
+	if err := s.userRepo.Each(filter, order, func(u *sharedrepo.UserSummary) error {
+		deletedAt := ""
+		if u.DeletedAt != nil {
+			deletedAt = u.DeletedAt.Format(time.RFC3339)
+		}
+		return out.Write([]string{
+			u.GoogleID,
+			u.Gmail,
+			string(u.Role),
+			u.RegistrationDate.Format(time.RFC3339),
+			deletedAt,
+			strconv.FormatInt(u.PostCount, 10),
+			strconv.FormatInt(u.ReportsReceived, 10),
+			strconv.FormatInt(u.ReportsFiled, 10),
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to export users: %w", err)
+	}
+	return out.Flush()
+}
 
-	// Prevent deleting admin users
-	if user.Role == models.RoleAdmin {
-		return errors.New("cannot delete admin users")
+// userFilter validates the query and converts it to the repository filter and order
+func (s *AdminUserService) userFilter(q UserQuery) (sharedrepo.UserFilter, string, error) {
+	switch models.Role(q.Role) {
+	case "", models.RoleUser, models.RoleBusiness, models.RoleAdmin:
+	default:
+		return sharedrepo.UserFilter{}, "", ErrInvalidUserRole
+	}
+	switch q.Status {
+	case "", sharedrepo.UserStatusActive, sharedrepo.UserStatusSuspended, sharedrepo.UserStatusBanned, sharedrepo.UserStatusDeleted:
+	default:
+		return sharedrepo.UserFilter{}, "", ErrInvalidUserStatus
+	}
+	if !validRange(q.RegisteredFrom, q.RegisteredTo) {
+		return sharedrepo.UserFilter{}, "", ErrInvalidListRange
+	}
+	order, err := orderBy(q.Sort, q.Order, userSortColumns, "registrationDate", "user.googleId ASC")
+	if err != nil {
+		return sharedrepo.UserFilter{}, "", err
 	}
+	return sharedrepo.UserFilter{
+		Query:          strings.TrimSpace(q.Query),
+		Role:           models.Role(q.Role),
+		Status:         q.Status,
+		RegisteredFrom: q.RegisteredFrom,
+		RegisteredTo:   q.RegisteredTo,
+		At:             s.now(),
+	}, order, nil
+}
+
+// DeleteUser soft-deletes a user and records the action in the audit log
+func (s *AdminUserService) DeleteUser(googleID string, actor AuditActor) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		userRepo := s.userRepo.WithTx(tx)
+
+		// Verify the user exists
+		user, err := userRepo.FindByGoogleID(googleID)
+		if err != nil {
+			return errors.New("user not found")
+		}
+
+		if user.DeletedAt != nil {
+			return errors.New("user is already deleted")
+		}
+
+		// Prevent deleting admin users
+		if user.Role == models.RoleAdmin {
+			return errors.New("cannot delete admin users")
+		}
+
+		if err := userRepo.SoftDelete(googleID); err != nil {
+			return err
+		}
 
-	return s.userRepo.SoftDelete(googleID)
+		after, err := userRepo.FindByGoogleID(googleID)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, models.AuditActionUserDelete, models.AuditTargetUser, googleID, user, after)
+	})
 }
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"io"
 	"testing"
+	"time"
 
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
+	sharedrepo "kojan-map/shared/repository"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +28,31 @@ func TestAdminUserService_GetUsers(t *testing.T) {
 	})
 }
 
+func TestAdminUserService_GetUsersValidation(t *testing.T) {
+	svc := NewAdminUserService(nil, nil)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name  string
+		query UserQuery
+		want  error
+	}{
+		{"unknown role", UserQuery{Role: "owner"}, ErrInvalidUserRole},
+		{"unknown status", UserQuery{Status: "frozen"}, ErrInvalidUserStatus},
+		{"from after to", UserQuery{RegisteredFrom: &from, RegisteredTo: &to}, ErrInvalidListRange},
+		{"unknown sort", UserQuery{Sort: "password"}, ErrInvalidSort},
+		{"unknown order", UserQuery{Order: "random"}, ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetUsers(tt.query)
+			assert.ErrorIs(t, err, tt.want)
+			assert.ErrorIs(t, svc.ExportUsersCSV(io.Discard, tt.query), tt.want)
+		})
+	}
+}
+
 func TestAdminUserService_DeleteUser(t *testing.T) {
 	t.Run("prevents deleting admin users", func(t *testing.T) {
 		user := &models.User{
@@ -61,9 +89,9 @@ func TestAdminUserService_DeleteUser(t *testing.T) {
 func TestUserListResponse(t *testing.T) {
 	t.Run("formats response correctly", func(t *testing.T) {
 		response := UserListResponse{
-			Users: []models.User{
-				{GoogleID: "user1", Gmail: "user1@example.com", Role: models.RoleUser},
-				{GoogleID: "user2", Gmail: "user2@example.com", Role: models.RoleBusiness},
+			Users: []sharedrepo.UserSummary{
+				{User: models.User{GoogleID: "user1", Gmail: "user1@example.com", Role: models.RoleUser}, PostCount: 3},
+				{User: models.User{GoogleID: "user2", Gmail: "user2@example.com", Role: models.RoleBusiness}},
 			},
 			Total: 100,
 			Page:  1,
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// analyticsBackfillDays is how far back missing days are rolled up (e.g. after downtime or on first start)
+const analyticsBackfillDays = 90
+
+// AnalyticsRollup periodically rolls up the admin analytics into daily_metric
+// 今日と昨日の集計は毎回作り直し、直近90日で集計されていない日も集計します
+type AnalyticsRollup struct {
+	service  *AdminAnalyticsService
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewAnalyticsRollup creates a new AnalyticsRollup (interval defaults to one hour)
+func NewAnalyticsRollup(service *AdminAnalyticsService, interval time.Duration) *AnalyticsRollup {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &AnalyticsRollup{
+		service:  service,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start starts rolling up in the background (once right away, then every interval)
+func (r *AnalyticsRollup) Start() {
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.run()
+		for {
+			select {
+			case <-ticker.C:
+				r.run()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops rolling up and waits for the current run to finish
+func (r *AnalyticsRollup) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+func (r *AnalyticsRollup) run() {
+	if _, err := r.service.RollupRecent(analyticsBackfillDays); err != nil {
+		log.Printf("failed to roll up analytics: %v", err)
+	}
+}
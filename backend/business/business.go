@@ -0,0 +1,22 @@
+// Package business はビジネス向けAPIをメインサーバーに組み込むための入口です。
+// internal 配下のパッケージはモジュール外から参照できないため、ここから公開します。
+package business
+
+import (
+	"kojan-map/business/internal/api"
+	svcimpl "kojan-map/business/internal/service/impl"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Options はルート登録時に共有する依存関係です
+type Options = api.Options
+
+// AuthService はビジネス側の認証サービスです（終了時に Close を呼び出してください）
+type AuthService = svcimpl.AuthServiceImpl
+
+// RegisterRoutes はビジネス向けAPIのルートを r に登録します
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, opts Options) *AuthService {
+	return api.RegisterRoutes(r, db, opts)
+}
@@ -6,13 +6,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"kojan-map/business/internal/api"
-	"kojan-map/business/internal/domain"
 	"kojan-map/business/internal/middleware"
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/kvstore"
 	"kojan-map/business/pkg/logger"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/outbox"
+	"kojan-map/business/pkg/ratelimit"
+	"kojan-map/business/pkg/secretbox"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/mysql"
@@ -30,9 +36,14 @@ type App struct {
 func NewApp(db *gorm.DB, log logger.Logger) *App {
 	engine := gin.Default()
 
-	// ミドルウェアの登録
+	// X-Forwarded-For は TRUSTED_PROXIES（カンマ区切りのIP・CIDR）からのリクエストのみ信頼する
+	if err := engine.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Error("Invalid TRUSTED_PROXIES: %v", err)
+		os.Exit(1)
+	}
+
+	// ミドルウェアの登録（エラーハンドリングはルートグループ側で登録）
 	engine.Use(middleware.CORSMiddleware())
-	engine.Use(middleware.ErrorHandlingMiddleware())
 
 	return &App{
 		Engine: engine,
@@ -41,25 +52,31 @@ func NewApp(db *gorm.DB, log logger.Logger) *App {
 	}
 }
 
+// trustedProxies は TRUSTED_PROXIES を返します（未設定の場合は nil でプロキシを信頼しない）
+func trustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// isDevEnv は開発・テスト環境（APP_ENV=dev, development, test）かどうかを返します
+func isDevEnv() bool {
+	switch os.Getenv("APP_ENV") {
+	case "dev", "development", "test":
+		return true
+	}
+	return false
+}
+
 // Setup はアプリケーションのセットアップを実行
 func (a *App) Setup() error {
-	// テーブルの作成（マイグレーション）
-	if err := a.DB.AutoMigrate(
-		&domain.User{},
-		&domain.BusinessMember{},
-		&domain.Post{},
-		&domain.PostImage{},
-		&domain.Genre{},
-		&domain.PostGenre{},
-		&domain.Block{},
-		&domain.Report{},
-		&domain.Contact{},
-	); err != nil {
-		a.Logger.Error("Failed to migrate database: %v", err)
-		return err
-	}
-
-	a.Logger.Info("Database migration completed successfully")
+	// テーブルはメインモジュールの migrate コマンドで作成する（go run ./cmd/migrate up）
+	// 起動時にはスキーマを変更しない
+	a.Logger.Info("Database schema is managed by the migrate command of the main module")
 
 	// ルートの登録はここに追加される
 	// a.setupRoutes() などを呼び出す
@@ -115,8 +132,53 @@ func main() {
 		os.Exit(1)
 	}
 
+	// レート制限ストア（RATE_LIMIT_ENABLED=false で無効）
+	var limiter ratelimit.Store
+	if os.Getenv("RATE_LIMIT_ENABLED") != "false" {
+		limiter = ratelimit.NewMemoryStore()
+	}
+
+	// トークン失効・MFAセッションはDBに保存（再起動・複数レプリカでも共有）
+	store := kvstore.NewMySQLStore(app.DB, kvstore.DefaultCleanupInterval)
+
+	// メインサーバーと同じ環境変数（JWT_SECRET_KEY, JWT_SIGNING_KEY_FILE など）から署名鍵を読み込む
+	tokens, err := jwt.NewTokenManagerFromConfig(jwt.KeyConfigFromEnv())
+	if err != nil {
+		log.Error("Failed to load JWT signing keys: %v", err)
+		os.Exit(1)
+	}
+	tokens.UseRevocationStore(store)
+
+	// 認証アプリのシークレットの暗号鍵（開発・テスト環境以外では MFA_ENCRYPTION_KEY が必須）
+	box, err := secretbox.NewFromEnv(isDevEnv())
+	if err != nil {
+		log.Error("Failed to load MFA encryption key: %v", err)
+		os.Exit(1)
+	}
+
+	// メールの配信ワーカー（送信に失敗したものは再試行する）
+	// 開発・テスト環境以外では NOTIFY_TRANSPORT=smtp / ses が必須（MFAコードをログに書き出さない）
+	notifier, err := notification.NewFromEnv(isDevEnv())
+	if err != nil {
+		log.Error("Failed to configure notifications: %v", err)
+		os.Exit(1)
+	}
+	dispatcher := outbox.NewDispatcher(app.DB, outbox.Config{})
+	dispatcher.Handle(outbox.TopicEmail, outbox.EmailHandler(notifier))
+	dispatcher.Start()
+
 	// ルーティング登録とAuthServiceの取得
-	authService := api.RegisterRoutes(app.Engine, app.DB)
+	authService := api.RegisterRoutes(app.Engine, app.DB, api.Options{
+		TokenManager: tokens,
+		SecretBox:    box,
+		RateLimiter:  limiter,
+		Store:        store,
+		Notifier:     notifier,
+		Outbox:       dispatcher,
+		// 単体起動ではユーザー側のルートと衝突しないため、以前のパスも提供する
+		LegacyRoutes: true,
+	})
+	api.RegisterHealthCheck(app.Engine)
 
 	// HTTPサーバーの設定
 	port := os.Getenv("PORT")
@@ -151,6 +213,8 @@ func main() {
 		authService.Close()
 		log.Info("AuthService resources cleaned up")
 	}
+	dispatcher.Stop()
+	store.Stop()
 
 	// HTTPサーバーのグレースフルシャットダウン（5秒のタイムアウト）
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
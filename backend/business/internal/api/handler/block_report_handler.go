@@ -23,7 +23,7 @@ func NewBlockHandler(blockService service.BlockService) *BlockHandler {
 	}
 }
 
-// CreateBlock は POST /api/block (M1-9-2) を処理します。
+// CreateBlock は POST /api/business/block (M1-9-2) を処理します。
 func (h *BlockHandler) CreateBlock(c *gin.Context) {
 	var req domain.CreateBlockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -49,7 +49,7 @@ func (h *BlockHandler) CreateBlock(c *gin.Context) {
 	})
 }
 
-// DeleteBlock は DELETE /api/block (M1-10-2) を処理します。
+// DeleteBlock は DELETE /api/business/block (M1-10-2) を処理します。
 func (h *BlockHandler) DeleteBlock(c *gin.Context) {
 	var req domain.DeleteBlockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -87,7 +87,7 @@ func NewReportHandler(reportService service.ReportService) *ReportHandler {
 	}
 }
 
-// CreateReport は POST /api/report (M1-12-2) を処理します。
+// CreateReport は POST /api/business/report (M1-12-2) を処理します。
 func (h *ReportHandler) CreateReport(c *gin.Context) {
 	var req domain.CreateReportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -20,7 +20,7 @@ func NewContactHandler(contactService service.ContactService) *ContactHandler {
 	return &ContactHandler{contactService: contactService}
 }
 
-// CreateContact は POST /api/contact (M1-11-2) を処理します。
+// CreateContact は POST /api/business/contact (M1-11-2) を処理します。
 func (h *ContactHandler) CreateContact(c *gin.Context) {
 	var req domain.CreateContactRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -47,7 +47,7 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 	response.SendOK(c, result)
 }
 
-// GetPost は GET /api/posts/:postId (M1-7-2) を処理します。
+// GetPost は GET /api/business/posts/:postId (M1-7-2) を処理します。
 func (h *PostHandler) GetPost(c *gin.Context) {
 	postIDStr := c.Param("postId")
 	if postIDStr == "" {
@@ -70,7 +70,7 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 	response.SendOK(c, result)
 }
 
-// CreatePost は POST /api/posts (M1-8-4) を処理します。
+// CreatePost は POST /api/business/posts (M1-8-4) を処理します。
 // 画像は PNG または JPEG のみ、5MB以下
 func (h *PostHandler) CreatePost(c *gin.Context) {
 	var req domain.CreatePostRequest
@@ -106,7 +106,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 	})
 }
 
-// AnonymizePost は PUT /api/posts/anonymize (M1-13-2) を処理します。
+// AnonymizePost は PUT /api/business/posts/anonymize (M1-13-2) を処理します。
 func (h *PostHandler) AnonymizePost(c *gin.Context) {
 	var req domain.AnonymizePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -128,7 +128,7 @@ func (h *PostHandler) AnonymizePost(c *gin.Context) {
 	})
 }
 
-// GetPostHistory は GET /api/posts/history (M1-14-2) を処理します。
+// GetPostHistory は GET /api/business/posts/history (M1-14-2) を処理します。
 func (h *PostHandler) GetPostHistory(c *gin.Context) {
 	googleID, ok := contextkeys.GetUserID(c.Request.Context())
 	if !ok {
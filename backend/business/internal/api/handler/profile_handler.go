@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"kojan-map/business/internal/domain"
+	"kojan-map/business/internal/service"
+	"kojan-map/business/pkg/contextkeys"
+	"kojan-map/business/pkg/response"
+	"kojan-map/business/pkg/validate"
+)
+
+// ProfileHandler はログイン中の事業者自身のプロフィール・ダッシュボードのエンドポイントを処理するハンドラーです。
+type ProfileHandler struct {
+	profileService service.ProfileService
+}
+
+// NewProfileHandler は新しいプロフィールハンドラーを作成します。
+func NewProfileHandler(profileService service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// userID は認証済みのユーザーIDを返します（取得できない場合は 401 を返して false）。
+func (h *ProfileHandler) userID(c *gin.Context) (string, bool) {
+	userID, ok := contextkeys.GetUserID(c.Request.Context())
+	if !ok || userID == "" {
+		response.SendProblem(c, http.StatusUnauthorized, "unauthorized", "user ID not found in context", c.Request.URL.Path)
+		return "", false
+	}
+	return userID, true
+}
+
+// GetStats は GET /api/business/stats を処理します。
+func (h *ProfileHandler) GetStats(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.profileService.GetDashboardStats(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, result)
+}
+
+// GetProfile は GET /api/business/profile を処理します。
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.profileService.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, result)
+}
+
+// UpdateProfile は PUT /api/business/profile を処理します。
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	var req domain.UpdateBusinessProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", err.Error(), c.Request.URL.Path)
+		return
+	}
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.profileService.UpdateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, result)
+}
+
+// UploadIcon は POST /api/business/icon を処理します。
+// 画像は PNG または JPEG のみ、5MB以下（フォームのフィールド名は file）
+func (h *ProfileHandler) UploadIcon(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", "file is required", c.Request.URL.Path)
+		return
+	}
+
+	const maxSize = 5 * 1024 * 1024 // 5MB
+	if file.Size > maxSize {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", fmt.Sprintf("icon file size must not exceed %dMB", maxSize/1024/1024), c.Request.URL.Path)
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		response.SendProblem(c, http.StatusInternalServerError, "internal-error", fmt.Sprintf("failed to open file: %v", err), c.Request.URL.Path)
+		return
+	}
+	defer f.Close()
+
+	iconData, err := io.ReadAll(f)
+	if err != nil {
+		response.SendProblem(c, http.StatusInternalServerError, "internal-error", fmt.Sprintf("failed to read file: %v", err), c.Request.URL.Path)
+		return
+	}
+
+	if err := validate.NewImageValidator().ValidateImage(iconData, file.Size, maxSize); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", fmt.Sprintf("invalid image format: %v", err), c.Request.URL.Path)
+		return
+	}
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	profileImage, err := h.profileService.UpdateIcon(c.Request.Context(), userID, iconData)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, gin.H{
+		"success":      true,
+		"profileImage": profileImage,
+	})
+}
+
+// GetPostCount は GET /api/business/posts/count を処理します。
+func (h *ProfileHandler) GetPostCount(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.profileService.GetPostCount(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, gin.H{"count": count})
+}
+
+// UpdateName は PUT /api/business/name を処理します。
+func (h *ProfileHandler) UpdateName(c *gin.Context) {
+	var req domain.RenameBusinessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", err.Error(), c.Request.URL.Path)
+		return
+	}
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.profileService.UpdateName(c.Request.Context(), userID, req.Name); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, gin.H{"success": true})
+}
+
+// UpdateAddress は PUT /api/business/address を処理します。
+func (h *ProfileHandler) UpdateAddress(c *gin.Context) {
+	var req domain.UpdateBusinessAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", err.Error(), c.Request.URL.Path)
+		return
+	}
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.profileService.UpdateAddress(c.Request.Context(), userID, req.Address, req.ZipCode); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, gin.H{"success": true})
+}
+
+// UpdatePhone は PUT /api/business/phone を処理します。
+func (h *ProfileHandler) UpdatePhone(c *gin.Context) {
+	var req domain.UpdateBusinessPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", err.Error(), c.Request.URL.Path)
+		return
+	}
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.profileService.UpdatePhone(c.Request.Context(), userID, req.Phone); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, gin.H{"success": true})
+}
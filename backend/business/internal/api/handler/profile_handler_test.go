@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	svcimpl "kojan-map/business/internal/service/impl"
+	"kojan-map/business/pkg/contextkeys"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProfileContext creates a gin context for a JSON request, signed in as userID when it is not empty.
+func newProfileContext(method, path, body, userID string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		httpReq = httpReq.WithContext(contextkeys.WithUserID(httpReq.Context(), userID))
+	}
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httpReq
+	return c, w
+}
+
+// TestProfileHandler_UpdateAddress tests PUT /api/business/address for the signed-in business.
+func TestProfileHandler_UpdateAddress(t *testing.T) {
+	fixtures := svcimpl.NewTestFixtures()
+	fixtures.SetupBusinessMember(1, "test-user-id", "Test Business", nil)
+	profileHandler := NewProfileHandler(fixtures.ProfileService)
+
+	c, w := newProfileContext("PUT", "/api/business/address", `{"address":"Tokyo","zipCode":"1000001"}`, "test-user-id")
+	profileHandler.UpdateAddress(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Tokyo", fixtures.MemberRepo.Members[1].Address)
+	assert.Equal(t, "1000001", fixtures.MemberRepo.Members[1].ZipCode)
+}
+
+// TestProfileHandler_MissingAuth tests that requests without a user ID are rejected.
+func TestProfileHandler_MissingAuth(t *testing.T) {
+	fixtures := svcimpl.NewTestFixtures()
+	profileHandler := NewProfileHandler(fixtures.ProfileService)
+
+	c, w := newProfileContext("PUT", "/api/business/phone", `{"phone":"0300000000"}`, "")
+	profileHandler.UpdatePhone(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+// TestProfileHandler_GetPostCount tests GET /api/business/posts/count.
+func TestProfileHandler_GetPostCount(t *testing.T) {
+	fixtures := svcimpl.NewTestFixtures()
+	fixtures.SetupBusinessMember(1, "test-user-id", "Test Business", nil)
+	profileHandler := NewProfileHandler(fixtures.ProfileService)
+
+	c, w := newProfileContext("GET", "/api/business/posts/count", "", "test-user-id")
+	profileHandler.GetPostCount(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 0, result["count"])
+}
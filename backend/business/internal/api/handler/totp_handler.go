@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"kojan-map/business/internal/domain"
+	"kojan-map/business/internal/service"
+	"kojan-map/business/pkg/contextkeys"
+	"kojan-map/business/pkg/response"
+)
+
+// TOTPHandler は認証アプリ（TOTP）関連のエンドポイントを処理するハンドラーです。
+type TOTPHandler struct {
+	totpService service.TOTPService
+}
+
+// NewTOTPHandler は新しい認証アプリハンドラーを作成します。
+func NewTOTPHandler(totpService service.TOTPService) *TOTPHandler {
+	return &TOTPHandler{totpService: totpService}
+}
+
+// Enroll は POST /api/business/mfa/totp/enroll を処理します。
+// シークレット・otpauth URI・QRコードを返します（確認するまでログインには使われません）。
+func (h *TOTPHandler) Enroll(c *gin.Context) {
+	googleID, ok := contextkeys.GetUserID(c.Request.Context())
+	if !ok {
+		response.SendProblem(c, http.StatusUnauthorized, "unauthorized", "user ID not found in context", c.Request.URL.Path)
+		return
+	}
+	gmail, _ := contextkeys.GetGmail(c.Request.Context())
+
+	result, err := h.totpService.BeginEnrollment(c.Request.Context(), googleID, gmail)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, result)
+}
+
+// Confirm は POST /api/business/mfa/totp/confirm を処理します。
+// 最初のコードで登録を確定し、リカバリーコードを返します。
+func (h *TOTPHandler) Confirm(c *gin.Context) {
+	var req domain.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", err.Error(), c.Request.URL.Path)
+		return
+	}
+
+	googleID, ok := contextkeys.GetUserID(c.Request.Context())
+	if !ok {
+		response.SendProblem(c, http.StatusUnauthorized, "unauthorized", "user ID not found in context", c.Request.URL.Path)
+		return
+	}
+
+	result, err := h.totpService.ConfirmEnrollment(c.Request.Context(), googleID, req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, result)
+}
+
+// RegenerateRecoveryCodes は POST /api/business/mfa/recovery-codes を処理します。
+func (h *TOTPHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req domain.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", err.Error(), c.Request.URL.Path)
+		return
+	}
+
+	googleID, ok := contextkeys.GetUserID(c.Request.Context())
+	if !ok {
+		response.SendProblem(c, http.StatusUnauthorized, "unauthorized", "user ID not found in context", c.Request.URL.Path)
+		return
+	}
+
+	result, err := h.totpService.RegenerateRecoveryCodes(c.Request.Context(), googleID, req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendOK(c, result)
+}
+
+// Disable は DELETE /api/business/mfa/totp を処理します。
+func (h *TOTPHandler) Disable(c *gin.Context) {
+	var req domain.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.SendProblem(c, http.StatusBadRequest, "bad-request", err.Error(), c.Request.URL.Path)
+		return
+	}
+
+	googleID, ok := contextkeys.GetUserID(c.Request.Context())
+	if !ok {
+		response.SendProblem(c, http.StatusUnauthorized, "unauthorized", "user ID not found in context", c.Request.URL.Path)
+		return
+	}
+
+	if err := h.totpService.Disable(c.Request.Context(), googleID, req.Code); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.SendSuccess(c, http.StatusOK, gin.H{"message": "authenticator app disabled"})
+}
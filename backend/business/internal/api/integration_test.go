@@ -25,6 +25,7 @@ import (
 	serviceImpl "kojan-map/business/internal/service/impl"
 	"kojan-map/business/pkg/contextkeys"
 	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/models"
 	"strings"
 )
 
@@ -81,8 +82,9 @@ func setupTestRouter(db *gorm.DB) *gin.Engine {
 	postRepo := impl.NewPostRepoImpl(db)
 	reportRepo := impl.NewReportRepoImpl(db)
 
-	tokenManager := jwt.NewTokenManager()
+	tokenManager := jwt.NewTokenManagerWithSecret("test-secret")
 	authService := serviceImpl.NewAuthServiceImpl(authRepo, tokenManager)
+	authService.SetNotificationService(serviceImpl.NopNotifier{})
 	postService := serviceImpl.NewPostServiceImpl(postRepo)
 	reportService := serviceImpl.NewReportServiceImpl(reportRepo)
 
@@ -111,7 +113,7 @@ func setupTestRouter(db *gorm.DB) *gin.Engine {
 			}
 
 			// BusinessMemberからBusinessIDを取得
-			var businessMember domain.BusinessMember
+			var businessMember models.BusinessMember
 			if err := db.Where("userId = ?", claims.UserID).First(&businessMember).Error; err != nil {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "事業者情報が見つかりません", "userId": claims.UserID, "detail": err.Error()})
 				return
@@ -119,7 +121,7 @@ func setupTestRouter(db *gorm.DB) *gin.Engine {
 
 			// contextにユーザーIDと事業者IDを設定
 			ctx := contextkeys.WithUserID(c.Request.Context(), claims.UserID)
-			ctx = contextkeys.WithBusinessID(ctx, businessMember.ID)
+			ctx = contextkeys.WithBusinessID(ctx, businessMember.BusinessID)
 			c.Request = c.Request.WithContext(ctx)
 			c.Next()
 		}
@@ -141,11 +143,11 @@ func setupTestRouter(db *gorm.DB) *gin.Engine {
 }
 
 // createTestUser はテスト用ユーザーを作成
-func createTestUser(t *testing.T, db *gorm.DB, googleID, gmail string) *domain.User {
-	user := &domain.User{
-		ID:               googleID,
+func createTestUser(t *testing.T, db *gorm.DB, googleID, gmail string) *models.User {
+	user := &models.User{
+		GoogleID:         googleID,
 		Gmail:            gmail,
-		Role:             "business",
+		Role:             models.RoleBusiness,
 		RegistrationDate: time.Now(),
 	}
 	err := db.Create(user).Error
@@ -154,8 +156,8 @@ func createTestUser(t *testing.T, db *gorm.DB, googleID, gmail string) *domain.U
 }
 
 // createTestBusinessMember はテスト用事業者メンバーを作成
-func createTestBusinessMember(t *testing.T, db *gorm.DB, userID, businessName string) *domain.BusinessMember {
-	member := &domain.BusinessMember{
+func createTestBusinessMember(t *testing.T, db *gorm.DB, userID, businessName string) *models.BusinessMember {
+	member := &models.BusinessMember{
 		UserID:           userID,
 		BusinessName:     businessName,
 		KanaBusinessName: "テストジギョウシャ",
@@ -170,8 +172,8 @@ func createTestBusinessMember(t *testing.T, db *gorm.DB, userID, businessName st
 }
 
 // createTestGenre はテスト用ジャンルを作成
-func createTestGenre(t *testing.T, db *gorm.DB, name, color string) *domain.Genre {
-	genre := &domain.Genre{
+func createTestGenre(t *testing.T, db *gorm.DB, name, color string) *models.Genre {
+	genre := &models.Genre{
 		GenreName: name,
 		Color:     color,
 	}
@@ -182,7 +184,7 @@ func createTestGenre(t *testing.T, db *gorm.DB, name, color string) *domain.Genr
 
 // generateTestToken はテスト用JWTトークンを生成
 func generateTestToken(t *testing.T, userID, gmail, role string) string {
-	tokenManager := jwt.NewTokenManager()
+	tokenManager := jwt.NewTokenManagerWithSecret("test-secret")
 	token, err := tokenManager.GenerateToken(userID, gmail, role)
 	require.NoError(t, err, "テストトークンの生成に失敗")
 	return token
@@ -195,15 +197,15 @@ func TestIntegration_POST001_CreatePost(t *testing.T) {
 	defer cleanupTestDB(t, db)
 
 	user := createTestUser(t, db, "test-user-1", "test1@example.com")
-	businessMember := createTestBusinessMember(t, db, user.ID, "Test Business")
-	t.Logf("作成した BusinessMember: ID=%d, UserID=%s", businessMember.ID, businessMember.UserID)
+	businessMember := createTestBusinessMember(t, db, user.GoogleID, "Test Business")
+	t.Logf("作成した BusinessMember: ID=%d, UserID=%s", businessMember.BusinessID, businessMember.UserID)
 
 	// DBで確認
-	var dbMember domain.BusinessMember
-	if err := db.Where("userId = ?", user.ID).First(&dbMember).Error; err != nil {
+	var dbMember models.BusinessMember
+	if err := db.Where("userId = ?", user.GoogleID).First(&dbMember).Error; err != nil {
 		t.Fatalf("BusinessMemberがDBに存在しません: %v", err)
 	}
-	t.Logf("DBから取得した BusinessMember: ID=%d, UserID=%s", dbMember.ID, dbMember.UserID)
+	t.Logf("DBから取得した BusinessMember: ID=%d, UserID=%s", dbMember.BusinessID, dbMember.UserID)
 
 	genre := createTestGenre(t, db, "food", "FF0000")
 
@@ -211,7 +213,7 @@ func TestIntegration_POST001_CreatePost(t *testing.T) {
 
 	reqBody := domain.CreatePostRequest{
 		LocationID:  "35.6895,139.6917",
-		GenreIDs:    []int32{genre.ID},
+		GenreIDs:    []int32{genre.GenreID},
 		Title:       "統合テスト投稿",
 		Description: "これは統合テストの投稿です",
 		Images:      []string{},
@@ -220,7 +222,7 @@ func TestIntegration_POST001_CreatePost(t *testing.T) {
 
 	req, _ := http.NewRequest("POST", "/api/posts", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
-	token := generateTestToken(t, user.ID, user.Gmail, user.Role)
+	token := generateTestToken(t, user.GoogleID, user.Gmail, string(user.Role))
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	w := httptest.NewRecorder()
@@ -241,8 +243,8 @@ func TestIntegration_POST001_CreatePost(t *testing.T) {
 
 	assert.NotNil(t, response["postId"], "postIdが返却されること")
 
-	var post domain.Post
-	err = db.First(&post, "userId = ?", user.ID).Error
+	var post models.Post
+	err = db.First(&post, "userId = ?", user.GoogleID).Error
 	assert.NoError(t, err, "投稿がDBに保存されていること")
 	assert.Equal(t, "統合テスト投稿", post.Title, "タイトルが正しく保存されていること")
 
@@ -256,7 +258,7 @@ func TestIntegration_POST004_SaveLocation(t *testing.T) {
 	defer cleanupTestDB(t, db)
 
 	user := createTestUser(t, db, "test-user-2", "test2@example.com")
-	createTestBusinessMember(t, db, user.ID, "Test Business 2")
+	createTestBusinessMember(t, db, user.GoogleID, "Test Business 2")
 	genre := createTestGenre(t, db, "scene", "00FF00")
 
 	router := setupTestRouter(db)
@@ -267,7 +269,7 @@ func TestIntegration_POST004_SaveLocation(t *testing.T) {
 
 	reqBody := domain.CreatePostRequest{
 		LocationID:  locationID,
-		GenreIDs:    []int32{genre.ID},
+		GenreIDs:    []int32{genre.GenreID},
 		Title:       "位置情報テスト",
 		Description: "東京駅の位置情報",
 		Images:      []string{},
@@ -276,7 +278,7 @@ func TestIntegration_POST004_SaveLocation(t *testing.T) {
 
 	req, _ := http.NewRequest("POST", "/api/posts", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
-	token := generateTestToken(t, user.ID, user.Gmail, user.Role)
+	token := generateTestToken(t, user.GoogleID, user.Gmail, string(user.Role))
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	w := httptest.NewRecorder()
@@ -284,8 +286,8 @@ func TestIntegration_POST004_SaveLocation(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, w.Code, "ステータスコードが201であること")
 
-	var post domain.Post
-	err := db.First(&post, "userId = ?", user.ID).Error
+	var post models.Post
+	err := db.First(&post, "userId = ?", user.GoogleID).Error
 	assert.NoError(t, err, "投稿がDBに保存されていること")
 	assert.NotZero(t, post.PlaceID, "位置情報が保存されていること")
 
@@ -300,15 +302,15 @@ func TestIntegration_REPORT001_CreateReport(t *testing.T) {
 
 	reporter := createTestUser(t, db, "reporter-1", "reporter@example.com")
 	reportedUser := createTestUser(t, db, "reported-1", "reported@example.com")
-	createTestBusinessMember(t, db, reporter.ID, "Reporter Business")
+	createTestBusinessMember(t, db, reporter.GoogleID, "Reporter Business")
 	genre := createTestGenre(t, db, "food", "FF0000")
 
-	post := &domain.Post{
-		UserID:      reportedUser.ID,
+	post := &models.Post{
+		UserID:      reportedUser.GoogleID,
 		PlaceID:     1,
 		Title:       "通報対象投稿",
 		Text:        "この投稿は通報されます",
-		GenreID:     genre.ID,
+		GenreID:     genre.GenreID,
 		NumView:     0,
 		NumReaction: 0,
 		PostDate:    time.Now(),
@@ -319,8 +321,8 @@ func TestIntegration_REPORT001_CreateReport(t *testing.T) {
 	router := setupTestRouter(db)
 
 	reqBody := domain.CreateReportRequest{
-		ReportedGoogleID: reportedUser.ID,
-		TargetPostID:     int(post.ID),
+		ReportedGoogleID: reportedUser.GoogleID,
+		TargetPostID:     int(post.PostID),
 		ReportReason:     "不適切なコンテンツ",
 		ReportedAt:       time.Now().Format(time.RFC3339),
 	}
@@ -328,7 +330,7 @@ func TestIntegration_REPORT001_CreateReport(t *testing.T) {
 
 	req, _ := http.NewRequest("POST", "/api/report", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
-	token := generateTestToken(t, reporter.ID, reporter.Gmail, reporter.Role)
+	token := generateTestToken(t, reporter.GoogleID, reporter.Gmail, string(reporter.Role))
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	w := httptest.NewRecorder()
@@ -336,12 +338,12 @@ func TestIntegration_REPORT001_CreateReport(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, w.Code, "ステータスコードが201であること")
 
-	var report domain.Report
-	err = db.First(&report, "postId = ?", post.ID).Error
+	var report models.Report
+	err = db.First(&report, "postId = ?", post.PostID).Error
 	assert.NoError(t, err, "通報がDBに保存されていること")
 	assert.Equal(t, "不適切なコンテンツ", report.Reason, "通報理由が正しく保存されていること")
 
-	t.Logf("✅ REPORT-001: 通報作成成功 (ReportID: %d)", report.ID)
+	t.Logf("✅ REPORT-001: 通報作成成功 (ReportID: %d)", report.ReportID)
 }
 
 // TestIntegration_BIZ001_BusinessRequest は BIZ-001 のテスト
@@ -356,27 +358,18 @@ func TestIntegration_BIZ001_BusinessRequest(t *testing.T) {
 
 	user := createTestUser(t, db, "applicant-1", "applicant@example.com")
 
-	// businessReqテーブルの構造に合わせた構造体
-	type BusinessRequest struct {
-		RequestID int32  `gorm:"primaryKey;autoIncrement;column:requestId"`
-		Name      string `gorm:"column:name;not null"`
-		Address   string `gorm:"column:address;not null"`
-		Phone     string `gorm:"column:phone"`
-		UserID    string `gorm:"column:userId;not null"`
-	}
-
-	request := &BusinessRequest{
+	request := &models.BusinessRequest{
 		Name:    "新規事業者",
 		Address: "東京都千代田区",
 		Phone:   "03-1234-5678",
-		UserID:  user.ID,
+		UserID:  user.GoogleID,
 	}
 
-	err := db.Table("businessReq").Create(request).Error
+	err := db.Create(request).Error
 	require.NoError(t, err, "事業者申請の作成に失敗")
 
-	var savedRequest BusinessRequest
-	err = db.Table("businessReq").First(&savedRequest, "userId = ?", user.ID).Error
+	var savedRequest models.BusinessRequest
+	err = db.First(&savedRequest, "userId = ?", user.GoogleID).Error
 	assert.NoError(t, err, "事業者申請がDBに保存されていること")
 	assert.Equal(t, "新規事業者", savedRequest.Name, "事業者名が正しく保存されていること")
 	assert.Equal(t, "東京都千代田区", savedRequest.Address, "住所が正しく保存されていること")
@@ -391,15 +384,15 @@ func TestIntegration_AUTH001_LoginFlow(t *testing.T) {
 	defer cleanupTestDB(t, db)
 
 	user := createTestUser(t, db, "login-test-1", "logintest@example.com")
-	createTestBusinessMember(t, db, user.ID, "Login Test Business")
+	createTestBusinessMember(t, db, user.GoogleID, "Login Test Business")
 
-	token := generateTestToken(t, user.ID, user.Gmail, user.Role)
+	token := generateTestToken(t, user.GoogleID, user.Gmail, string(user.Role))
 	assert.NotEmpty(t, token, "JWTトークンが生成されること")
 
-	tokenManager := jwt.NewTokenManager()
+	tokenManager := jwt.NewTokenManagerWithSecret("test-secret")
 	claims, err := tokenManager.VerifyToken(token)
 	assert.NoError(t, err, "トークンが正しく検証されること")
-	assert.Equal(t, user.ID, claims.UserID, "ユーザーIDが正しいこと")
+	assert.Equal(t, user.GoogleID, claims.UserID, "ユーザーIDが正しいこと")
 
 	t.Logf("✅ AUTH-001: ログインフロー成功")
 }
@@ -411,15 +404,15 @@ func TestIntegration_POST002_GetPostIncrementsViewCount(t *testing.T) {
 	defer cleanupTestDB(t, db)
 
 	user := createTestUser(t, db, "view-test-1", "viewtest@example.com")
-	createTestBusinessMember(t, db, user.ID, "View Test Business")
+	createTestBusinessMember(t, db, user.GoogleID, "View Test Business")
 	genre := createTestGenre(t, db, "event", "0000FF")
 
-	post := &domain.Post{
-		UserID:      user.ID,
+	post := &models.Post{
+		UserID:      user.GoogleID,
 		PlaceID:     1,
 		Title:       "閲覧数テスト",
 		Text:        "閲覧数が増加するかテスト",
-		GenreID:     genre.ID,
+		GenreID:     genre.GenreID,
 		NumView:     0,
 		NumReaction: 0,
 		PostDate:    time.Now(),
@@ -429,8 +422,8 @@ func TestIntegration_POST002_GetPostIncrementsViewCount(t *testing.T) {
 
 	router := setupTestRouter(db)
 
-	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/posts/%d", post.ID), nil)
-	token := generateTestToken(t, user.ID, user.Gmail, user.Role)
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/posts/%d", post.PostID), nil)
+	token := generateTestToken(t, user.GoogleID, user.Gmail, string(user.Role))
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	w := httptest.NewRecorder()
@@ -438,8 +431,8 @@ func TestIntegration_POST002_GetPostIncrementsViewCount(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code, "ステータスコードが200であること")
 
-	var updatedPost domain.Post
-	err = db.First(&updatedPost, "postId = ?", post.ID).Error
+	var updatedPost models.Post
+	err = db.First(&updatedPost, "postId = ?", post.PostID).Error
 	assert.NoError(t, err, "投稿が取得できること")
 	assert.Equal(t, int32(1), updatedPost.NumView, "閲覧数が1増加していること")
 
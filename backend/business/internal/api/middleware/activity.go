@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"kojan-map/business/pkg/contextkeys"
+)
+
+// ActivityRecorder はユーザーが利用した日を記録します（管理画面の分析のDAU）
+type ActivityRecorder interface {
+	Touch(ctx context.Context, userID string) error
+}
+
+// RecordActivity は認証済みのユーザーが利用した日を記録します（AuthMiddleware の後に使用）
+// 記録に失敗してもリクエストは拒否しません
+func RecordActivity(recorder ActivityRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, ok := contextkeys.GetUserID(c.Request.Context()); ok {
+			if err := recorder.Touch(c.Request.Context(), userID); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+		c.Next()
+	}
+}
@@ -1,17 +1,27 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"kojan-map/business/pkg/accountstatus"
 	"kojan-map/business/pkg/contextkeys"
 	"kojan-map/business/pkg/jwt"
 )
 
+// AccountChecker はアカウントが利用停止・利用禁止・削除されていないかを確認します
+// 利用できない場合は *accountstatus.Restriction を返します
+type AccountChecker interface {
+	CheckAccount(ctx context.Context, userID string) error
+}
+
 // AuthMiddleware はJWTトークンを検証し、UserID等をContextに設定します
-func AuthMiddleware(tokenManager *jwt.TokenManager) gin.HandlerFunc {
+// accounts が指定されている場合、利用停止中・利用禁止・削除済みのアカウントは 403 で拒否します
+func AuthMiddleware(tokenManager *jwt.TokenManager, accounts AccountChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Authorizationヘッダーから Bearer トークンを抽出
 		authHeader := c.GetHeader("Authorization")
@@ -37,14 +47,26 @@ func AuthMiddleware(tokenManager *jwt.TokenManager) gin.HandlerFunc {
 		}
 
 		// トークンを検証してクレームを取得（ブラックリストチェックも含む）
-		claims, err := tokenManager.VerifyToken(tokenString)
+		claims, err := tokenManager.VerifyTokenWithType(tokenString, "access")
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("invalid token: %v", err)})
 			c.Abort()
 			return
 		}
 
-		// トークンがブラックリストに登録されていないか確認
+		// 発行後に利用停止・利用禁止されたアカウントのトークンを拒否
+		if accounts != nil {
+			if err := accounts.CheckAccount(c.Request.Context(), claims.UserID); err != nil {
+				var restriction *accountstatus.Restriction
+				if errors.As(err, &restriction) {
+					c.JSON(http.StatusForbidden, restriction.Response())
+				} else {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check account status"})
+				}
+				c.Abort()
+				return
+			}
+		}
 
 		// ContextにUserID, Gmail, Roleを設定
 		// BusinessIDはuserIDから取得する必要があるため、ここでは設定しない
@@ -85,7 +107,7 @@ func OptionalAuthMiddleware(tokenManager *jwt.TokenManager) gin.HandlerFunc {
 		}
 
 		// トークンを検証してクレームを取得（ブラックリストチェックも含む）
-		claims, err := tokenManager.VerifyToken(tokenString)
+		claims, err := tokenManager.VerifyTokenWithType(tokenString, "access")
 		if err != nil {
 			// トークンが不正な場合は続行（トークンなしとして扱う）
 			c.Next()
@@ -5,21 +5,74 @@ import (
 
 	"kojan-map/business/internal/api/handler"
 	"kojan-map/business/internal/api/middleware"
+	errmiddleware "kojan-map/business/internal/middleware"
 	"kojan-map/business/internal/repository/impl"
+	"kojan-map/business/internal/service"
 	svcimpl "kojan-map/business/internal/service/impl"
 	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/kvstore"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/outbox"
+	"kojan-map/business/pkg/ratelimit"
 	"kojan-map/business/pkg/response"
+	"kojan-map/business/pkg/secretbox"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// authRateLimit はMFAメール送信を伴う認証ルートのレート制限（IP単位）
+var authRateLimit = ratelimit.PerMinute(5)
+
+// Options はルート登録時に外部から共有する依存関係です
+// メインサーバーに組み込む場合は、ユーザー側と同じものを渡します
+type Options struct {
+	// TokenManager はJWTの発行・検証に使用します（必須。ユーザー側と同じものを渡します）
+	TokenManager *jwt.TokenManager
+	// ContentFilter は投稿・問い合わせ・事業者プロフィール（事業者名・住所・電話番号）の検査に使用します（nilの場合は検査しない）
+	ContentFilter service.ContentFilter
+	// RateLimiter は認証ルートのレート制限に使用します（nilの場合は制限しない）
+	RateLimiter ratelimit.Store
+	// Store はMFAコード・MFAセッションの保存先です（nilの場合はプロセス内メモリ）
+	// トークン失効の保存先は TokenManager の生成時に呼び出し側で設定します
+	Store kvstore.Store
+	// Notifier はMFAコードなどのメール送信に使用します（必須。notification.NewFromEnv で生成）
+	Notifier notification.NotificationService
+	// TokenVerifier はGoogleのIDトークンの検証に使用します（nilの場合は環境変数 GOOGLE_CLIENT_ID などから生成）
+	TokenVerifier oauth.TokenVerifier
+	// Outbox はMFAコードのメールを配信ワーカー経由で送信するために使用します（nilの場合は直接送信）
+	Outbox outbox.Enqueuer
+	// AccountChecker は利用停止・利用禁止・削除されたアカウントのログインとAPI利用を拒否するために使用します（nilの場合は確認しない）
+	AccountChecker middleware.AccountChecker
+	// ActivityRecorder は管理画面の分析（DAU）のため、認証済みのユーザーが利用した日を記録します（nilの場合は記録しない）
+	ActivityRecorder middleware.ActivityRecorder
+	// SecretBox は認証アプリ（TOTP）のシークレットをDBに保存する際の暗号化に使用します（必須）
+	SecretBox *secretbox.Box
+	// LegacyRoutes は /api/business 配下へ移す前のパス（/api/posts/:postId, /api/block など）も登録します
+	// ユーザー側のルートと衝突するため、単体起動時のみ有効にします
+	LegacyRoutes bool
+}
+
 // RegisterRoutes はビジネスバックエンドのルートグループを設定します
-func RegisterRoutes(r *gin.Engine, db *gorm.DB) *svcimpl.AuthServiceImpl {
-	api := r.Group("/api")
+// ルートはすべて /api 配下に登録され、ユーザー側のルートと衝突しないよう
+// 事業者向けのリソースは /api/business 配下にまとめています
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, opts Options) *svcimpl.AuthServiceImpl {
+	// エラーハンドリングはビジネスのルートにのみ適用する
+	api := r.Group("/api", errmiddleware.ErrorHandlingMiddleware())
 
 	// TokenManagerを初期化（サービスとミドルウェア間で共有）
-	tokenManager := jwt.NewTokenManager()
+	// TokenManager の生成（署名鍵の読み込み）は呼び出し側で行う
+	tokenManager := opts.TokenManager
+	if tokenManager == nil {
+		panic("api: Options.TokenManager is required")
+	}
+	if opts.SecretBox == nil {
+		panic("api: Options.SecretBox is required")
+	}
+	if opts.Notifier == nil {
+		panic("api: Options.Notifier is required")
+	}
 
 	// リポジトリを初期化
 	authRepo := impl.NewAuthRepoImpl(db)
@@ -30,90 +83,144 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) *svcimpl.AuthServiceImpl {
 	reportRepo := impl.NewReportRepoImpl(db)
 	contactRepo := impl.NewContactRepoImpl(db)
 	paymentRepo := impl.NewPaymentRepoImpl(db)
+	totpRepo := impl.NewTOTPRepoImpl(db)
 
 	// サービスを初期化
 	authService := svcimpl.NewAuthServiceImpl(authRepo, tokenManager)
+	if opts.Store != nil {
+		authService.UseStore(opts.Store)
+	}
+	authService.SetNotificationService(opts.Notifier)
+	if opts.Outbox != nil {
+		authService.SetOutbox(opts.Outbox)
+	}
+	if opts.TokenVerifier != nil {
+		authService.SetTokenVerifier(opts.TokenVerifier)
+	}
+	if opts.AccountChecker != nil {
+		authService.SetAccountChecker(opts.AccountChecker)
+	}
 	memberService := svcimpl.NewMemberServiceImpl(memberRepo, authRepo)
+	profileService := svcimpl.NewProfileServiceImpl(memberRepo, statsRepo)
 	statsService := svcimpl.NewStatsServiceImpl(statsRepo)
 	postService := svcimpl.NewPostServiceImpl(postRepo)
 	blockService := svcimpl.NewBlockServiceImpl(blockRepo)
 	reportService := svcimpl.NewReportServiceImpl(reportRepo)
 	contactService := svcimpl.NewContactServiceImpl(contactRepo)
 	paymentService := svcimpl.NewPaymentServiceImpl(paymentRepo)
+	// 認証アプリのシークレットは MFA_ENCRYPTION_KEY で暗号化して保存する
+	totpService := svcimpl.NewTOTPServiceImpl(totpRepo, opts.SecretBox)
+	if opts.Store != nil {
+		totpService.UseStore(opts.Store)
+	}
+	authService.SetTOTPService(totpService)
+
+	// NGワード・個人情報フィルタ（投稿・問い合わせ・事業者プロフィール）
+	if opts.ContentFilter != nil {
+		memberService.SetContentFilter(opts.ContentFilter)
+		profileService.SetContentFilter(opts.ContentFilter)
+		postService.SetContentFilter(opts.ContentFilter)
+		contactService.SetContentFilter(opts.ContentFilter)
+	}
 
 	// ハンドラーを初期化
 	authHandler := handler.NewAuthHandler(authService)
 	memberHandler := handler.NewMemberHandler(memberService)
+	profileHandler := handler.NewProfileHandler(profileService)
 	statsHandler := handler.NewStatsHandler(statsService)
 	postHandler := handler.NewPostHandler(postService)
 	blockHandler := handler.NewBlockHandler(blockService)
 	reportHandler := handler.NewReportHandler(reportService)
 	contactHandler := handler.NewContactHandler(contactService)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
+	totpHandler := handler.NewTOTPHandler(totpService)
 
 	// 認証ルート（公開）
-	api.POST("/auth/google", authHandler.GoogleAuth)
-	api.POST("/auth/business/login", authHandler.BusinessLogin)
+	authLimited := api.Group("", ratelimit.Middleware(opts.RateLimiter, "business-auth", authRateLimit, ratelimit.ByClientIP))
+	authLimited.POST("/auth/google", authHandler.GoogleAuth)
+	authLimited.POST("/auth/business/login", authHandler.BusinessLogin)
 	api.POST("/auth/refresh", authHandler.Refresh)
 
 	// 認証ログアウトルート（保護 - 認証必須）
+	// 利用停止中のアカウントでもログアウトはできるよう、アカウントの状態は確認しない
 	logoutRoute := api.Group("/auth")
-	logoutRoute.Use(middleware.AuthMiddleware(tokenManager))
+	logoutRoute.Use(middleware.AuthMiddleware(tokenManager, nil))
 	logoutRoute.POST("/logout", authHandler.Logout)
 
-	// メンバールート（保護）
-	memberRoutes := api.Group("/business")
-	memberRoutes.Use(middleware.AuthMiddleware(tokenManager), middleware.BusinessRoleRequired())
-	memberRoutes.GET("/mypage/details", memberHandler.GetBusinessDetails)
-	memberRoutes.PUT("/member/name", memberHandler.UpdateBusinessName)
-	memberRoutes.PUT("/member/icon", memberHandler.UpdateBusinessIcon)
-	memberRoutes.PUT("/member/anonymize", memberHandler.AnonymizeMember)
-	memberRoutes.GET("/member", memberHandler.GetMemberInfo)
-
-	// ダッシュボード統計ルート（保護）
-	statsRoutes := api.Group("/business")
-	statsRoutes.Use(middleware.AuthMiddleware(tokenManager), middleware.BusinessRoleRequired())
-	statsRoutes.GET("/post/total", statsHandler.GetTotalPosts)
-	statsRoutes.GET("/reaction/total", statsHandler.GetTotalReactions)
-	statsRoutes.GET("/view/total", statsHandler.GetTotalViews)
-	statsRoutes.GET("/engagement", statsHandler.GetEngagementRate)
-
-	// 投稿ルート（保護）
-	postRoutes := api.Group("")
-	postRoutes.Use(middleware.AuthMiddleware(tokenManager), middleware.BusinessRoleRequired())
-	postRoutes.GET("/business/posts", postHandler.ListPosts)
-	postRoutes.GET("/posts/:postId", postHandler.GetPost)
-	postRoutes.POST("/posts", postHandler.CreatePost)
-	postRoutes.PUT("/posts/anonymize", postHandler.AnonymizePost)
-	postRoutes.GET("/posts/history", postHandler.GetPostHistory)
-
-	// ブロックルート（保護）
-	blockRoutes := api.Group("")
-	blockRoutes.Use(middleware.AuthMiddleware(tokenManager), middleware.BusinessRoleRequired())
-	blockRoutes.POST("/block", blockHandler.CreateBlock)
-	blockRoutes.DELETE("/block", blockHandler.DeleteBlock)
-
-	// 通報ルート（保護）
-	reportRoutes := api.Group("")
-	reportRoutes.Use(middleware.AuthMiddleware(tokenManager), middleware.BusinessRoleRequired())
-	reportRoutes.POST("/report", reportHandler.CreateReport)
-
-	// 問い合わせルート（保護）
-	contactRoutes := api.Group("")
-	contactRoutes.Use(middleware.AuthMiddleware(tokenManager), middleware.BusinessRoleRequired())
-	contactRoutes.POST("/contact", contactHandler.CreateContact)
-
-	// Stripeリダイレクトルート（保護）
-	paymentRoutes := api.Group("/business")
-	paymentRoutes.Use(middleware.AuthMiddleware(tokenManager), middleware.BusinessRoleRequired())
-	paymentRoutes.POST("/stripe/redirect", paymentHandler.CreateRedirect)
-
-	// ヘルスチェック
+	// 事業者向けルート（保護）
+	businessRoutes := api.Group("/business")
+	businessRoutes.Use(middleware.AuthMiddleware(tokenManager, opts.AccountChecker), middleware.BusinessRoleRequired())
+	if opts.ActivityRecorder != nil {
+		businessRoutes.Use(middleware.RecordActivity(opts.ActivityRecorder))
+	}
+
+	// メンバー
+	businessRoutes.GET("/mypage/details", memberHandler.GetBusinessDetails)
+	businessRoutes.PUT("/member/name", memberHandler.UpdateBusinessName)
+	businessRoutes.PUT("/member/icon", memberHandler.UpdateBusinessIcon)
+	businessRoutes.PUT("/member/anonymize", memberHandler.AnonymizeMember)
+	businessRoutes.GET("/member", memberHandler.GetMemberInfo)
+
+	// ログイン中の事業者自身のプロフィール・ダッシュボード（事業者はトークンのユーザーIDから特定する）
+	businessRoutes.GET("/stats", profileHandler.GetStats)
+	businessRoutes.GET("/profile", profileHandler.GetProfile)
+	businessRoutes.PUT("/profile", profileHandler.UpdateProfile)
+	businessRoutes.POST("/icon", profileHandler.UploadIcon)
+	businessRoutes.GET("/posts/count", profileHandler.GetPostCount)
+	businessRoutes.PUT("/name", profileHandler.UpdateName)
+	businessRoutes.PUT("/address", profileHandler.UpdateAddress)
+	businessRoutes.PUT("/phone", profileHandler.UpdatePhone)
+
+	// 認証アプリ（TOTP）・リカバリーコード
+	businessRoutes.POST("/mfa/totp/enroll", totpHandler.Enroll)
+	businessRoutes.POST("/mfa/totp/confirm", totpHandler.Confirm)
+	businessRoutes.DELETE("/mfa/totp", totpHandler.Disable)
+	businessRoutes.POST("/mfa/recovery-codes", totpHandler.RegenerateRecoveryCodes)
+
+	// ダッシュボード統計
+	businessRoutes.GET("/post/total", statsHandler.GetTotalPosts)
+	businessRoutes.GET("/reaction/total", statsHandler.GetTotalReactions)
+	businessRoutes.GET("/view/total", statsHandler.GetTotalViews)
+	businessRoutes.GET("/engagement", statsHandler.GetEngagementRate)
+
+	// 投稿
+	businessRoutes.GET("/posts", postHandler.ListPosts)
+	businessRoutes.POST("/posts", postHandler.CreatePost)
+	businessRoutes.GET("/posts/history", postHandler.GetPostHistory)
+	businessRoutes.PUT("/posts/anonymize", postHandler.AnonymizePost)
+	businessRoutes.GET("/posts/:postId", postHandler.GetPost)
+
+	// ブロック・通報・問い合わせ
+	businessRoutes.POST("/block", blockHandler.CreateBlock)
+	businessRoutes.DELETE("/block", blockHandler.DeleteBlock)
+	businessRoutes.POST("/report", reportHandler.CreateReport)
+	businessRoutes.POST("/contact", contactHandler.CreateContact)
+
+	// Stripeリダイレクト
+	businessRoutes.POST("/stripe/redirect", paymentHandler.CreateRedirect)
+
+	// 以前のパス（互換用、単体起動時のみ）
+	if opts.LegacyRoutes {
+		legacyRoutes := api.Group("")
+		legacyRoutes.Use(middleware.AuthMiddleware(tokenManager, opts.AccountChecker), middleware.BusinessRoleRequired())
+		legacyRoutes.GET("/posts/:postId", postHandler.GetPost)
+		legacyRoutes.POST("/posts", postHandler.CreatePost)
+		legacyRoutes.PUT("/posts/anonymize", postHandler.AnonymizePost)
+		legacyRoutes.GET("/posts/history", postHandler.GetPostHistory)
+		legacyRoutes.POST("/block", blockHandler.CreateBlock)
+		legacyRoutes.DELETE("/block", blockHandler.DeleteBlock)
+		legacyRoutes.POST("/report", reportHandler.CreateReport)
+		legacyRoutes.POST("/contact", contactHandler.CreateContact)
+	}
+
+	return authService
+}
+
+// RegisterHealthCheck はヘルスチェックを登録します（単体起動時のみ）
+func RegisterHealthCheck(r *gin.Engine) {
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
-
-	return authService
 }
 
 func notImplemented(c *gin.Context) {
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	svcimpl "kojan-map/business/internal/service/impl"
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/secretbox"
+)
+
+// TestRegisterRoutes_LegacyRoutes は単体起動時のみ以前のパスを登録することを確認します
+func TestRegisterRoutes_LegacyRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 接続しないDB（ルート登録のみ確認）
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:0)/kojanmap",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	box, err := secretbox.New(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	routes := func(legacy bool) map[string]bool {
+		r := gin.New()
+		authService := RegisterRoutes(r, db, Options{TokenManager: jwt.NewTokenManagerWithSecret("test-secret"), SecretBox: box, Notifier: svcimpl.NopNotifier{}, LegacyRoutes: legacy})
+		defer authService.Close()
+		registered := make(map[string]bool)
+		for _, route := range r.Routes() {
+			registered[route.Method+" "+route.Path] = true
+		}
+		return registered
+	}
+
+	legacy := []string{"GET /api/posts/:postId", "POST /api/posts", "PUT /api/posts/anonymize", "GET /api/posts/history",
+		"POST /api/block", "DELETE /api/block", "POST /api/report", "POST /api/contact"}
+
+	standalone := routes(true)
+	embedded := routes(false)
+	for _, route := range legacy {
+		assert.True(t, standalone[route], route)
+		assert.False(t, embedded[route], route)
+	}
+	assert.True(t, embedded["GET /api/business/posts/:postId"])
+
+	// フロントエンドの事業者ダッシュボードが使うパス
+	for _, route := range []string{"GET /api/business/stats", "GET /api/business/profile", "PUT /api/business/profile",
+		"POST /api/business/icon", "GET /api/business/posts/count", "PUT /api/business/name",
+		"PUT /api/business/address", "PUT /api/business/phone"} {
+		assert.True(t, embedded[route], route)
+	}
+}
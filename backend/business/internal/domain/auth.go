@@ -1,20 +1,5 @@
 package domain
 
-import "time"
-
-// User はユーザー基本情報（全ロール共通）
-type User struct {
-	ID               string    `gorm:"primaryKey;column:googleId;type:varchar(50)"`
-	Gmail            string    `gorm:"column:gmail;type:varchar(100);not null"`
-	Role             string    `gorm:"column:role;type:enum('user','business','admin');not null"`
-	RegistrationDate time.Time `gorm:"column:registrationDate;not null"`
-}
-
-// TableName はテーブル名を指定
-func (User) TableName() string {
-	return "user"
-}
-
 // GoogleAuthRequest はGoogle認証のリクエスト
 // googleId: 必須。GoogleユーザーのID
 // gmail: 必須。メールアドレス形式で検証される
@@ -1,40 +1,5 @@
 package domain
 
-import "time"
-
-// BusinessMember は事業者会員を表すドメインモデル
-// ID: 主キー、自動インクリメント
-// BusinessName: 事業者名（最大50文字、必須）
-// KanaBusinessName: 事業者名カナ（最大50文字、必須）
-// ZipCode: 郵便番号（必須）
-// Address: 住所（最大100文字、必須）
-// Phone: 電話番号（必須）
-// RegistDate: 登録日（必須）
-// ProfileImage: プロフィール画像（BLOB型）
-// UserID: ユーザーID（インデックス付き、必須）
-// PlaceID: 場所ID（必須）
-// AnonymizedAt: 匿名化日時（NULL可）
-// IsActive: アクティブ状態フラグ
-// CreatedAt: 作成日時
-// UpdatedAt: 更新日時
-type BusinessMember struct {
-	ID               int32     `gorm:"primaryKey;autoIncrement;column:businessId"`
-	BusinessName     string    `gorm:"column:businessName;type:varchar(50);not null"`
-	KanaBusinessName string    `gorm:"column:kanaBusinessName;type:varchar(50);not null"`
-	ZipCode          string    `gorm:"column:zipCode;type:varchar(7)"`
-	Address          string    `gorm:"column:address;type:varchar(100);not null"`
-	Phone            string    `gorm:"column:phone;type:varchar(15)"`
-	RegistDate       time.Time `gorm:"column:registDate;not null"`
-	ProfileImage     []byte    `gorm:"column:profileImage;type:blob"`
-	UserID           string    `gorm:"column:userId;type:varchar(50);not null"`
-	PlaceID          int32     `gorm:"column:placeId;not null"`
-}
-
-// TableName は対応するテーブル名を指定
-func (BusinessMember) TableName() string {
-	return "business"
-}
-
 // CreateBusinessMemberRequest は事業者会員作成時のリクエスト
 // businessName: 必須。事業者名（最大50文字）
 // kanaBusinessName: 必須。事業者名カナ（最大50文字）
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// BusinessProfileResponse はログイン中の事業者自身のプロフィール
+// profileImage: アイコン画像（data URI、未設定の場合は空）
+type BusinessProfileResponse struct {
+	BusinessID       int32     `json:"businessId"`
+	BusinessName     string    `json:"businessName"`
+	KanaBusinessName string    `json:"kanaBusinessName"`
+	ZipCode          string    `json:"zipCode"`
+	Address          string    `json:"address"`
+	Phone            string    `json:"phone"`
+	RegistDate       time.Time `json:"registDate"`
+	ProfileImage     string    `json:"profileImage"`
+	UserID           string    `json:"userId"`
+	PlaceID          int32     `json:"placeId"`
+}
+
+// BusinessProfileUpdate はプロフィールの更新内容（空のフィールドは変更しない）
+type BusinessProfileUpdate struct {
+	BusinessName     string
+	KanaBusinessName string
+	ZipCode          string
+	Address          string
+	Phone            string
+}
+
+// UpdateBusinessProfileRequest はプロフィール更新のリクエスト
+// 省略したフィールドは変更しない
+type UpdateBusinessProfileRequest struct {
+	Name             string `json:"name" binding:"max=50"`
+	KanaBusinessName string `json:"kanaBusinessName" binding:"max=50"`
+	ZipCode          string `json:"zipCode" binding:"max=7"`
+	Address          string `json:"address" binding:"max=100"`
+	Phone            string `json:"phone" binding:"max=15"`
+}
+
+// RenameBusinessRequest は事業者名変更のリクエスト
+// name: 必須。新しい事業者名（1〜50文字）
+type RenameBusinessRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// UpdateBusinessAddressRequest は住所変更のリクエスト
+// address: 必須。住所（最大100文字）
+// zipCode: 郵便番号（省略した場合は変更しない）
+type UpdateBusinessAddressRequest struct {
+	Address string `json:"address" binding:"required,max=100"`
+	ZipCode string `json:"zipCode" binding:"max=7"`
+}
+
+// UpdateBusinessPhoneRequest は電話番号変更のリクエスト
+// phone: 必須。電話番号（最大15文字）
+type UpdateBusinessPhoneRequest struct {
+	Phone string `json:"phone" binding:"required,max=15"`
+}
@@ -2,107 +2,27 @@ package domain
 
 import (
 	"time"
-
-	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
-// Post は投稿を表すドメインモデル
-// ID: 主キー
-// AuthorID: 投稿者ID
-// BusinessMember: 事業者メンバー情報
-// LocationID: 場所ID
-// Title: タイトル
-// Description: 説明
-// ViewCount: 閲覧数
-// ReactionCount: リアクション数
-// PostedAt: 投稿日時
-// UpdatedAt: 更新日時
-// AnonymizedAt: 匿名化日時（NULL可）
-// IsActive: アクティブフラグ（論理削除用）
-// Images: 投稿画像リスト（外部キー: PostID）
-// Genres: ジャンルリスト（多対多リレーション、中間テーブル: post_genre）
-type Post struct {
-	ID             int32          `gorm:"primaryKey;autoIncrement;column:postId"`
-	PlaceID        int32          `gorm:"column:placeId;not null"`
-	UserID         string         `gorm:"column:userId;type:varchar(50);not null"`
-	PostDate       time.Time      `gorm:"column:postDate;not null"`
-	Title          string         `gorm:"column:title;type:varchar(50);not null"`
-	Text           string         `gorm:"column:text;type:text;not null"`
-	PostImage      []byte         `gorm:"column:postImage;type:blob"`
-	NumReaction    int32          `gorm:"column:numReaction;not null"`
-	NumView        int32          `gorm:"column:numView;not null"`
-	GenreID        int32          `gorm:"column:genreId;not null"`
-	BusinessMember BusinessMember `gorm:"foreignKey:UserID;references:UserID"`
-}
-
-// TableName は対応するテーブル名を指定
-func (Post) TableName() string {
-	return "post"
-}
-
-// PostImage は投稿画像を表すドメインモデル
-// ID: 主キー
-// PostID: 投稿ID
-// ImageURL: 画像URL
-type PostImage struct {
-	ID       string `gorm:"primaryKey"`
-	PostID   int32
-	ImageURL string
-}
-
-// TableName は対応するテーブル名を指定
-func (PostImage) TableName() string {
-	return "post_images"
-}
-
-// BeforeCreate はレコード作成前にIDを生成します
-func (p *PostImage) BeforeCreate(tx *gorm.DB) error {
-	if p.ID == "" {
-		p.ID = uuid.New().String()
-	}
-	return nil
-}
-
-// PostGenre は投稿とジャンルの多対多の中間テーブル
-// PostID: 投稿ID（複合主キー）
-// GenreID: ジャンルID（複合主キー）
-type PostGenre struct {
-	PostID  int32 `gorm:"primaryKey"`
-	GenreID int32 `json:"genreIds"`
-}
-
-// TableName は対応するテーブル名を指定
-func (PostGenre) TableName() string {
-	return "post_genre"
-}
-
-// Genre はジャンルを表すドメインモデル
-// ID: 主キー
-// Name: ジャンル名
-type Genre struct {
-	ID        int32  `gorm:"primaryKey;autoIncrement;column:genreId"`
-	GenreName string `gorm:"column:genreName;type:enum('food','event','scene','store','emergency','other');not null"`
-	Color     string `gorm:"column:color;type:varchar(6);not null"`
-}
-
-// TableName は対応するテーブル名を指定
-func (Genre) TableName() string {
-	return "genre"
-}
-
 // CreatePostRequest は投稿作成時のリクエスト
 // locationId: 必須。場所ID
 // genreIds: 必須。ジャンルIDのリスト（最低1つ必要）
 // title: 必須。投稿タイトル
 // description: 必須。投稿の説明
 // images: 画像URLのリスト（任意）
+// status: 公開状態（任意。draft / scheduled / published、既定は published）
+// publishAt: 公開日時（status が scheduled の場合は必須）
 type CreatePostRequest struct {
-	LocationID  string   `json:"locationId" binding:"required"`
-	GenreIDs    []int32  `json:"genreIds" binding:"required,min=1"`
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Images      []string `json:"images"`
+	LocationID  string     `json:"locationId" binding:"required"`
+	GenreIDs    []int32    `json:"genreIds" binding:"required,min=1"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	Images      []string   `json:"images"`
+	Status      string     `json:"status" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt   *time.Time `json:"publishAt"`
+
+	// ModerationStatus はコンテンツフィルタの判定結果（サーバー側で設定）
+	ModerationStatus string `json:"-"`
 }
 
 // PostResponse は投稿情報のレスポンス
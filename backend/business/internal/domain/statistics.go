@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // PostStatistics は投稿統計を表す
 // totalPostNumber: 合計投稿数
 // totalReactionNumber: 合計リアクション数
@@ -51,3 +53,28 @@ type EngagementResponse struct {
 	ViewCount      int32   `json:"viewCount"`
 	EngagementRate float64 `json:"engagementRate"`
 }
+
+// PostStats は投稿1件の統計（ダッシュボードの集計に使用）
+type PostStats struct {
+	PostDate    time.Time `gorm:"column:postDate"`
+	NumReaction int32     `gorm:"column:numReaction"`
+	NumView     int32     `gorm:"column:numView"`
+}
+
+// DailyStats は1日分のリアクション数・閲覧数
+// date: 日付（MM/DD）
+type DailyStats struct {
+	Date      string `json:"date"`
+	Reactions int32  `json:"reactions"`
+	Views     int32  `json:"views"`
+}
+
+// DashboardStatsResponse はダッシュボードの統計
+// weeklyData: 直近7日間に投稿した投稿の日別集計（古い日から順）
+type DashboardStatsResponse struct {
+	TotalPosts       int32        `json:"totalPosts"`
+	TotalReactions   int32        `json:"totalReactions"`
+	TotalViews       int32        `json:"totalViews"`
+	AverageReactions int32        `json:"averageReactions"`
+	WeeklyData       []DailyStats `json:"weeklyData"`
+}
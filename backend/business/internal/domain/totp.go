@@ -0,0 +1,24 @@
+package domain
+
+// TOTPEnrollResponse は認証アプリ登録開始のレスポンス
+// secret: 手入力用のシークレット（base32）
+// otpauthUri: 認証アプリに登録する otpauth:// URI
+// qrCode: URIを埋め込んだQRコード（PNGのdata URL）
+type TOTPEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+	QRCode     string `json:"qrCode"`
+}
+
+// TOTPCodeRequest は認証アプリのコードを送るリクエスト
+// code: 必須。認証アプリに表示された6桁のコード
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse はリカバリーコードのレスポンス
+// 平文のコードはこのレスポンスでのみ返却される
+// recoveryCodes: リカバリーコード（各1回限り）
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
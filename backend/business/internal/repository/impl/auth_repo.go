@@ -5,9 +5,13 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
-	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/oauth"
 )
 
+// userIdentitiesTable はログインに使う外部アカウントとユーザーの連携テーブルです（メインサーバー側で作成）
+const userIdentitiesTable = "user_identities"
+
 // AuthRepoImpl は GORM を使用して AuthRepo インターフェースを実装します。
 type AuthRepoImpl struct {
 	db *gorm.DB
@@ -18,35 +22,37 @@ func NewAuthRepoImpl(db *gorm.DB) *AuthRepoImpl {
 	return &AuthRepoImpl{db: db}
 }
 
-// GetOrCreateUser はユーザーレコードを取得、または作成します。
-func (r *AuthRepoImpl) GetOrCreateUser(ctx context.Context, googleID, gmail, role string) (interface{}, error) {
-	var user domain.User
-	result := r.db.WithContext(ctx).Where("id = ?", googleID).First(&user)
-
-	if result.Error == nil {
-		// ユーザーが存在します
-		return &user, nil
+// FindUserByGoogleSubject は Google の sub に連携したユーザーを取得します（見つからない場合は nil）。
+// ユーザーは連携情報（user_identities）から特定し、
+// 連携情報のない以前のユーザーは内部ユーザーIDが sub と同じものを使用します。
+// 事業者アカウントは申請の承認時に作成されるため、ここではユーザーを作成しません。
+func (r *AuthRepoImpl) FindUserByGoogleSubject(ctx context.Context, googleID string) (interface{}, error) {
+	db := r.db.WithContext(ctx)
+	var linked []string
+	if err := db.Table(userIdentitiesTable).
+		Where("issuer = ? AND subject = ?", oauth.GoogleIssuer, googleID).
+		Limit(1).Pluck("googleId", &linked).Error; err != nil {
+		return nil, err
 	}
-
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		// 新しいユーザーを作成します
-		user = domain.User{
-			ID:    googleID,
-			Gmail: gmail,
-			Role:  role,
-		}
-		if err := r.db.WithContext(ctx).Create(&user).Error; err != nil {
-			return nil, err
-		}
-		return &user, nil
+	userID := googleID
+	if len(linked) > 0 {
+		userID = linked[0]
 	}
 
-	return nil, result.Error
+	var user models.User
+	err := db.Where("googleId = ?", userID).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
 // GetUserByID は Google ID を使用してユーザーを取得します。
 func (r *AuthRepoImpl) GetUserByID(ctx context.Context, googleID string) (interface{}, error) {
-	var user domain.User
+	var user models.User
 	if err := r.db.WithContext(ctx).Where("id = ?", googleID).First(&user).Error; err != nil {
 		return nil, err
 	}
@@ -55,7 +61,7 @@ func (r *AuthRepoImpl) GetUserByID(ctx context.Context, googleID string) (interf
 
 // GetUserByGmail は Gmail アドレスを使用してユーザーを取得します。
 func (r *AuthRepoImpl) GetUserByGmail(ctx context.Context, gmail string) (interface{}, error) {
-	var user domain.User
+	var user models.User
 	if err := r.db.WithContext(ctx).Where("gmail = ?", gmail).First(&user).Error; err != nil {
 		return nil, err
 	}
@@ -64,7 +70,7 @@ func (r *AuthRepoImpl) GetUserByGmail(ctx context.Context, gmail string) (interf
 
 // GetBusinessMemberByUserID はユーザー ID を使用して事業者メンバー情報を取得します。
 func (r *AuthRepoImpl) GetBusinessMemberByUserID(ctx context.Context, userID string) (interface{}, error) {
-	var member domain.BusinessMember
+	var member models.BusinessMember
 	if err := r.db.WithContext(ctx).Where("userId = ?", userID).First(&member).Error; err != nil {
 		return nil, err
 	}
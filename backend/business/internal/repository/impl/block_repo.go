@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 
-	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -27,9 +27,9 @@ func (r *BlockRepoImpl) Create(ctx context.Context, blockerID, blockedID string)
 		return fmt.Errorf("both blockerID and blockedID are required")
 	}
 
-	block := &domain.Block{
-		BlockerID: blockerID,
-		BlockedID: blockedID,
+	block := &models.UserBlock{
+		BlockerId: blockerID,
+		BlockedId: blockedID,
 	}
 
 	if err := r.db.WithContext(ctx).Create(block).Error; err != nil {
@@ -52,7 +52,7 @@ func (r *BlockRepoImpl) Delete(ctx context.Context, blockerID, blockedID string)
 
 	result := r.db.WithContext(ctx).
 		Where("blockerId = ? AND blockedId = ?", blockerID, blockedID).
-		Delete(&domain.Block{})
+		Delete(&models.UserBlock{})
 
 	if result.Error != nil {
 		return result.Error
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/models"
 	"unicode/utf8"
 
 	"gorm.io/gorm"
@@ -23,7 +24,7 @@ func NewBusinessMemberRepoImpl(db *gorm.DB) *BusinessMemberRepoImpl {
 
 // GetByGoogleID はユーザー ID（Google ID）を使用して事業者メンバー情報を取得します。
 func (r *BusinessMemberRepoImpl) GetByGoogleID(ctx context.Context, googleID string) (interface{}, error) {
-	var member domain.BusinessMember
+	var member models.BusinessMember
 	if err := r.db.WithContext(ctx).Where("userId = ?", googleID).First(&member).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("business member not found for userId %s", googleID)
@@ -40,7 +41,7 @@ func (r *BusinessMemberRepoImpl) UpdateName(ctx context.Context, businessID int3
 		return fmt.Errorf("business name must be between 1 and 50 characters")
 	}
 
-	result := r.db.WithContext(ctx).Model(&domain.BusinessMember{}).
+	result := r.db.WithContext(ctx).Model(&models.BusinessMember{}).
 		Where("businessId = ?", businessID).
 		Update("businessName", name)
 
@@ -62,7 +63,7 @@ func (r *BusinessMemberRepoImpl) UpdateIcon(ctx context.Context, businessID int3
 		return fmt.Errorf("icon data cannot be empty")
 	}
 
-	result := r.db.WithContext(ctx).Model(&domain.BusinessMember{}).
+	result := r.db.WithContext(ctx).Model(&models.BusinessMember{}).
 		Where("businessId = ?", businessID).
 		Update("profileImage", icon)
 
@@ -77,11 +78,56 @@ func (r *BusinessMemberRepoImpl) UpdateIcon(ctx context.Context, businessID int3
 	return nil
 }
 
+// UpdateProfile は事業者メンバーのプロフィールのうち空でないフィールドを更新します。
+func (r *BusinessMemberRepoImpl) UpdateProfile(ctx context.Context, businessID int32, payload interface{}) error {
+	update, ok := payload.(*domain.BusinessProfileUpdate)
+	if !ok || update == nil {
+		return fmt.Errorf("invalid profile update type")
+	}
+
+	fields := map[string]interface{}{}
+	for column, value := range map[string]string{
+		"businessName":     update.BusinessName,
+		"kanaBusinessName": update.KanaBusinessName,
+		"zipCode":          update.ZipCode,
+		"address":          update.Address,
+		"phone":            update.Phone,
+	} {
+		if value != "" {
+			fields[column] = value
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	result := r.db.WithContext(ctx).Model(&models.BusinessMember{}).
+		Where("businessId = ?", businessID).
+		Updates(fields)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		// 値が変わらない場合も 0 になるため、存在を確認する
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&models.BusinessMember{}).Where("businessId = ?", businessID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("business member not found for id %d", businessID)
+		}
+	}
+
+	return nil
+}
+
 // Anonymize は事業者メンバーを匿名化します（M3-3）。
 // 識別可能な個人情報は復元不能な値に置き換える、主キーおよび外部キーは変更しない、物理削除は行わない
 func (r *BusinessMemberRepoImpl) Anonymize(ctx context.Context, businessID int32) error {
 	// 機密フィールドを匿名化します
-	result := r.db.WithContext(ctx).Model(&domain.BusinessMember{}).
+	result := r.db.WithContext(ctx).Model(&models.BusinessMember{}).
 		Where("businessId = ?", businessID).
 		Updates(map[string]interface{}{
 			"businessName":     "[Anonymized]",
@@ -106,7 +152,7 @@ func (r *BusinessMemberRepoImpl) Anonymize(ctx context.Context, businessID int32
 // GetMemberInfoByGoogleID は表示用のメンバー情報を取得します（M1-2）。
 // ユーザーのメールアドレスとロールを返します。
 func (r *BusinessMemberRepoImpl) GetMemberInfoByGoogleID(ctx context.Context, googleID string) (interface{}, error) {
-	var user domain.User
+	var user models.User
 	if err := r.db.WithContext(ctx).Where("id = ?", googleID).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user not found for id %s", googleID)
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"time"
 
-	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -22,7 +22,7 @@ func NewContactRepoImpl(db *gorm.DB) *ContactRepoImpl {
 
 // Create は新しいお問い合わせを保存します（M1-11-2）。
 func (r *ContactRepoImpl) Create(ctx context.Context, googleID string, subject, message string) error {
-	contact := &domain.Contact{
+	contact := &models.Ask{
 		UserID:  googleID,
 		Subject: subject,
 		Text:    message,
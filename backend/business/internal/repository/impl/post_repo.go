@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -23,7 +24,7 @@ func NewPostRepoImpl(db *gorm.DB) *PostRepoImpl {
 
 // ListByBusiness は事業者のすべての投稿を取得します（M1-6-1）。
 func (r *PostRepoImpl) ListByBusiness(ctx context.Context, businessID int32) (interface{}, error) {
-	var posts []domain.Post
+	var posts []models.Post
 	if err := r.db.WithContext(ctx).
 		Where("userId = (SELECT userId FROM business WHERE businessId = ?)", businessID).
 		Order("postDate DESC").
@@ -35,7 +36,7 @@ func (r *PostRepoImpl) ListByBusiness(ctx context.Context, businessID int32) (in
 
 // GetByID は ID を使用して投稿を取得します（M1-7-2）。
 func (r *PostRepoImpl) GetByID(ctx context.Context, postID int32) (interface{}, error) {
-	var post domain.Post
+	var post models.Post
 	if err := r.db.WithContext(ctx).Where("postId = ?", postID).First(&post).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("post not found for id %d", postID)
@@ -48,7 +49,7 @@ func (r *PostRepoImpl) GetByID(ctx context.Context, postID int32) (interface{},
 // IncrementViewCount は投稿の閲覧数を1増やします。
 func (r *PostRepoImpl) IncrementViewCount(ctx context.Context, postID int32) error {
 	result := r.db.WithContext(ctx).
-		Model(&domain.Post{}).
+		Model(&models.Post{}).
 		Where("postId = ?", postID).
 		UpdateColumn("numView", gorm.Expr("numView + 1"))
 
@@ -72,7 +73,7 @@ func (r *PostRepoImpl) Create(ctx context.Context, businessID int32, placeID int
 	}
 
 	// ビジネスメンバーのUserIDを取得
-	var business domain.BusinessMember
+	var business models.BusinessMember
 	if err := r.db.WithContext(ctx).Where("businessId = ?", businessID).First(&business).Error; err != nil {
 		return 0, fmt.Errorf("failed to find business member: %w", err)
 	}
@@ -82,22 +83,36 @@ func (r *PostRepoImpl) Create(ctx context.Context, businessID int32, placeID int
 		genreID = int32(genreIDs[0])
 	}
 
-	post := &domain.Post{
+	status := req.Status
+	if status == "" {
+		status = models.PostStatusPublished
+	}
+	postDate := time.Now()
+	if status == models.PostStatusScheduled && req.PublishAt != nil {
+		postDate = *req.PublishAt
+	}
+
+	post := &models.Post{
 		UserID:      business.UserID,
 		Title:       req.Title,
 		Text:        req.Description,
 		PlaceID:     placeID,
 		NumView:     0,
 		NumReaction: 0,
-		PostDate:    time.Now(),
+		PostDate:    postDate,
 		GenreID:     genreID,
+		Status:      status,
+		PublishAt:   req.PublishAt,
+	}
+	if req.ModerationStatus != "" {
+		post.ModerationStatus = req.ModerationStatus
 	}
 
 	if err := r.db.WithContext(ctx).Create(post).Error; err != nil {
 		return 0, fmt.Errorf("failed to create post: %w", err)
 	}
 
-	return post.ID, nil
+	return post.PostID, nil
 }
 
 // SetGenres は投稿に対してジャンルを設定します（M1-8-4）。
@@ -107,7 +122,7 @@ func (r *PostRepoImpl) SetGenres(ctx context.Context, postID int32, genreIDs []i
 		return nil
 	}
 
-	result := r.db.WithContext(ctx).Model(&domain.Post{}).
+	result := r.db.WithContext(ctx).Model(&models.Post{}).
 		Where("postId = ?", postID).
 		Update("genreId", genreIDs[0])
 
@@ -125,7 +140,7 @@ func (r *PostRepoImpl) SetGenres(ctx context.Context, postID int32, genreIDs []i
 // Anonymize は投稿を匿名化します（M1-13-2）。
 // 投稿内容は復元不能な値に置き換える、主キーおよび外部キーは変更しない
 func (r *PostRepoImpl) Anonymize(ctx context.Context, postID int32) error {
-	result := r.db.WithContext(ctx).Model(&domain.Post{}).
+	result := r.db.WithContext(ctx).Model(&models.Post{}).
 		Where("postId = ?", postID).
 		Updates(map[string]interface{}{
 			"title": "[Anonymized]",
@@ -145,7 +160,7 @@ func (r *PostRepoImpl) Anonymize(ctx context.Context, postID int32) error {
 
 // History はユーザーの投稿履歴を取得します（M1-14-2）。
 func (r *PostRepoImpl) History(ctx context.Context, googleID string) (interface{}, error) {
-	var posts []domain.Post
+	var posts []models.Post
 	if err := r.db.WithContext(ctx).
 		Where("userId = ?", googleID).
 		Order("postDate DESC").
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"kojan-map/business/internal/domain"
+	"kojan-map/business/internal/repository"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -22,7 +24,7 @@ func NewReportRepoImpl(db *gorm.DB) *ReportRepoImpl {
 }
 
 // Create は新しい通報レコードを作成します（M1-12-2）。
-// 通報者 ID と通報対象 ID・投稿 ID の組み合わせは一意
+// 同じ通報者の同じ投稿への未対応の通報は1件まで（対応済みになれば再度通報できる）
 func (r *ReportRepoImpl) Create(ctx context.Context, reporterID string, payload interface{}) error {
 	req, ok := payload.(*domain.CreateReportRequest)
 	if !ok {
@@ -39,28 +41,33 @@ func (r *ReportRepoImpl) Create(ctx context.Context, reporterID string, payload
 		return fmt.Errorf("invalid reportedAt format: %w", err)
 	}
 
-	// 重複する通報が存在するかを確認します（同じ通報者、通報対象ユーザー、投稿）
-	var existingReport domain.Report
+	// 未対応の通報が存在するかを確認します（同じ通報者、投稿）
+	var existingReport models.Report
 	err = r.db.WithContext(ctx).
-		Where("userId = ? AND postId = ?", reporterID, req.TargetPostID).
+		Where("userId = ? AND postId = ? AND reportFlag = ?", reporterID, req.TargetPostID, false).
 		First(&existingReport).Error
 	if err == nil {
-		return fmt.Errorf("duplicate report already exists")
+		return repository.ErrDuplicateReport
 	}
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
 		return fmt.Errorf("failed to check existing report: %w", err)
 	}
 
-	report := &domain.Report{
-		UserID:     reporterID,
-		PostID:     int32(req.TargetPostID),
-		Reason:     req.ReportReason,
-		Date:       reportedAt,
-		ReportFlag: 0,
-		RemoveFlag: 0,
+	open := true
+	report := &models.Report{
+		UserID:   reporterID,
+		PostID:   int32(req.TargetPostID),
+		Category: req.Category,
+		Reason:   req.ReportReason,
+		OpenFlag: &open,
+		Date:     reportedAt,
 	}
 
+	// 同時に送信された場合は一意インデックスで重複を検出する
 	if err := r.db.WithContext(ctx).Create(report).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repository.ErrDuplicateReport
+		}
 		return fmt.Errorf("failed to create report: %w", err)
 	}
 
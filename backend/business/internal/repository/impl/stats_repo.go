@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -22,7 +23,7 @@ func NewStatsRepoImpl(db *gorm.DB) *StatsRepoImpl {
 // TotalPosts は事業者の投稿総数を取得します（M3-7-1）。
 func (r *StatsRepoImpl) TotalPosts(ctx context.Context, businessID int32) (int32, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&domain.Post{}).
+	if err := r.db.WithContext(ctx).Model(&models.Post{}).
 		Where("userId = (SELECT userId FROM business WHERE businessId = ?)", businessID).
 		Count(&count).Error; err != nil {
 		return 0, fmt.Errorf("failed to count total posts: %w", err)
@@ -49,7 +50,7 @@ func (r *StatsRepoImpl) TotalViews(ctx context.Context, businessID int32) (int32
 	var totalViews int64
 
 	if err := r.db.WithContext(ctx).
-		Model(&domain.Post{}).
+		Model(&models.Post{}).
 		Where("userId = (SELECT userId FROM business WHERE businessId = ?)", businessID).
 		Select("COALESCE(SUM(numView), 0)").
 		Scan(&totalViews).Error; err != nil {
@@ -79,3 +80,16 @@ func (r *StatsRepoImpl) EngagementStats(ctx context.Context, businessID int32) (
 
 	return postCount, reactionCount, viewCount, nil
 }
+
+// PostStatsByUser はユーザーの削除されていない投稿ごとの投稿日時・リアクション数・閲覧数を返します。
+func (r *StatsRepoImpl) PostStatsByUser(ctx context.Context, userID string) (interface{}, error) {
+	var stats []domain.PostStats
+	if err := r.db.WithContext(ctx).
+		Model(&models.Post{}).
+		Select("postDate", "numReaction", "numView").
+		Where("userId = ?", userID).
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch post stats: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,136 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TOTPRepoImpl は GORM を使用して TOTPRepo を実装します。
+type TOTPRepoImpl struct {
+	db *gorm.DB
+}
+
+// NewTOTPRepoImpl は新しい認証アプリリポジトリを作成します。
+func NewTOTPRepoImpl(db *gorm.DB) *TOTPRepoImpl {
+	return &TOTPRepoImpl{db: db}
+}
+
+// Get は登録情報を取得します。未登録の場合は nil を返します。
+func (r *TOTPRepoImpl) Get(ctx context.Context, userID string) (interface{}, error) {
+	var cred models.TOTPCredential
+	if err := r.db.WithContext(ctx).Where("userId = ?", userID).First(&cred).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get totp credential: %w", err)
+	}
+	return &cred, nil
+}
+
+// SavePending は確認前のシークレットを保存します。
+func (r *TOTPRepoImpl) SavePending(ctx context.Context, userID, secretEnc string) error {
+	cred := &models.TOTPCredential{
+		UserID:    userID,
+		SecretEnc: secretEnc,
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "userId"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"secretEnc":    secretEnc,
+			"confirmedAt":  nil,
+			"lastUsedStep": 0,
+			"updatedAt":    time.Now(),
+		}),
+	}).Create(cred).Error
+	if err != nil {
+		return fmt.Errorf("failed to save totp credential: %w", err)
+	}
+	return nil
+}
+
+// Confirm は登録を確定し、リカバリーコードを同じトランザクションで保存します。
+func (r *TOTPRepoImpl) Confirm(ctx context.Context, userID string, step int64, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TOTPCredential{}).
+			Where("userId = ? AND confirmedAt IS NULL", userID).
+			Updates(map[string]interface{}{
+				"confirmedAt":  time.Now(),
+				"lastUsedStep": step,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to confirm totp credential: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// MarkStepUsed は lastUsedStep より新しいステップの場合のみ更新します。
+func (r *TOTPRepoImpl) MarkStepUsed(ctx context.Context, userID string, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.TOTPCredential{}).
+		Where("userId = ? AND lastUsedStep < ?", userID, step).
+		Update("lastUsedStep", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update totp step: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes は既存のリカバリーコードを削除して再発行します。
+func (r *TOTPRepoImpl) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// ConsumeRecoveryCode は未使用のリカバリーコードを使用済みにします。
+func (r *TOTPRepoImpl) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("userId = ? AND codeHash = ? AND usedAt IS NULL", userID, codeHash).
+		Limit(1).
+		Update("usedAt", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete は登録情報とリカバリーコードを削除します。
+func (r *TOTPRepoImpl) Delete(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("userId = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+		if err := tx.Where("userId = ?", userID).Delete(&models.TOTPCredential{}).Error; err != nil {
+			return fmt.Errorf("failed to delete totp credential: %w", err)
+		}
+		return nil
+	})
+}
+
+// replaceRecoveryCodes はトランザクション内でリカバリーコードを置き換えます。
+func replaceRecoveryCodes(tx *gorm.DB, userID string, codeHashes []string) error {
+	if err := tx.Where("userId = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	codes := make([]models.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if err := tx.Create(&codes).Error; err != nil {
+		return fmt.Errorf("failed to create recovery codes: %w", err)
+	}
+	return nil
+}
@@ -5,44 +5,40 @@ package mock
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
 	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/models"
 )
 
 // MockAuthRepo mocks AuthRepo interface for testing user authentication operations.
 // It uses an in-memory map to store users, with thread-safety via sync.Mutex.
 type MockAuthRepo struct {
 	mu              sync.Mutex
-	Users           map[string]*domain.User           // Key: googleID, Value: User
-	BusinessMembers map[string]*domain.BusinessMember // Key: userID, Value: BusinessMember
+	Users           map[string]*models.User           // Key: googleID, Value: User
+	BusinessMembers map[string]*models.BusinessMember // Key: userID, Value: BusinessMember
 }
 
 // NewMockAuthRepo creates a new MockAuthRepo with an empty user map.
 func NewMockAuthRepo() *MockAuthRepo {
 	return &MockAuthRepo{
-		Users:           make(map[string]*domain.User),
-		BusinessMembers: make(map[string]*domain.BusinessMember),
+		Users:           make(map[string]*models.User),
+		BusinessMembers: make(map[string]*models.BusinessMember),
 	}
 }
 
-// GetOrCreateUser retrieves an existing user or creates a new one if not found.
-// Thread-safe operation with mutex locking.
-func (m *MockAuthRepo) GetOrCreateUser(ctx context.Context, googleID, gmail, role string) (interface{}, error) {
+// FindUserByGoogleSubject retrieves a user by the Google subject.
+// Returns nil if user is not found.
+func (m *MockAuthRepo) FindUserByGoogleSubject(ctx context.Context, googleID string) (interface{}, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if user, exists := m.Users[googleID]; exists {
 		return user, nil
 	}
-
-	user := &domain.User{
-		ID:    googleID,
-		Gmail: gmail,
-		Role:  role,
-	}
-	m.Users[googleID] = user
-	return user, nil
+	return nil, nil
 }
 
 // GetUserByID retrieves a user by Google ID.
@@ -86,13 +82,13 @@ func (m *MockAuthRepo) GetBusinessMemberByUserID(ctx context.Context, userID str
 // It uses an in-memory map to store members, indexed by business ID.
 type MockBusinessMemberRepo struct {
 	mu      sync.Mutex
-	Members map[int32]*domain.BusinessMember // Key: businessID, Value: BusinessMember
+	Members map[int32]*models.BusinessMember // Key: businessID, Value: BusinessMember
 }
 
 // NewMockBusinessMemberRepo creates a new MockBusinessMemberRepo with an empty members map.
 func NewMockBusinessMemberRepo() *MockBusinessMemberRepo {
 	return &MockBusinessMemberRepo{
-		Members: make(map[int32]*domain.BusinessMember),
+		Members: make(map[int32]*models.BusinessMember),
 	}
 }
 
@@ -136,6 +132,37 @@ func (m *MockBusinessMemberRepo) UpdateIcon(ctx context.Context, businessID int3
 	return nil
 }
 
+// UpdateProfile updates the non-empty fields of a member's profile.
+// Returns nil if member is not found.
+func (m *MockBusinessMemberRepo) UpdateProfile(ctx context.Context, businessID int32, payload interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	update, ok := payload.(*domain.BusinessProfileUpdate)
+	if !ok || update == nil {
+		return errors.New("invalid profile update type")
+	}
+	member, exists := m.Members[businessID]
+	if !exists {
+		return nil
+	}
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&member.BusinessName, update.BusinessName},
+		{&member.KanaBusinessName, update.KanaBusinessName},
+		{&member.ZipCode, update.ZipCode},
+		{&member.Address, update.Address},
+		{&member.Phone, update.Phone},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	return nil
+}
+
 // Anonymize anonymizes a business member by setting their name to "[Anonymized]".
 // Returns nil if member is not found.
 func (m *MockBusinessMemberRepo) Anonymize(ctx context.Context, businessID int32) error {
@@ -153,14 +180,14 @@ func (m *MockBusinessMemberRepo) Anonymize(ctx context.Context, businessID int32
 // It uses an in-memory map to store posts and maintains an auto-incrementing ID counter.
 type MockPostRepo struct {
 	mu     sync.Mutex
-	Posts  map[int32]*domain.Post // Key: postID, Value: Post
+	Posts  map[int32]*models.Post // Key: postID, Value: Post
 	NextID int32                  // Auto-increment counter
 }
 
 // NewMockPostRepo creates a new MockPostRepo with an empty posts map and NextID initialized to 1.
 func NewMockPostRepo() *MockPostRepo {
 	return &MockPostRepo{
-		Posts:  make(map[int32]*domain.Post),
+		Posts:  make(map[int32]*models.Post),
 		NextID: 1,
 	}
 }
@@ -172,7 +199,7 @@ func (m *MockPostRepo) ListByBusiness(ctx context.Context, businessID int32) (in
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	posts := make([]domain.Post, 0)
+	posts := make([]models.Post, 0)
 	for _, post := range m.Posts {
 		posts = append(posts, *post)
 	}
@@ -214,13 +241,15 @@ func (m *MockPostRepo) Create(ctx context.Context, businessID int32, placeID int
 	postID := m.NextID
 	m.NextID++
 
-	post := &domain.Post{
-		ID:          postID,
+	post := &models.Post{
+		PostID:      postID,
 		Title:       req.Title,
 		Text:        req.Description,
 		PlaceID:     placeID,
 		NumView:     0,
 		NumReaction: 0,
+		Status:      req.Status,
+		PublishAt:   req.PublishAt,
 	}
 	m.Posts[postID] = post
 	return postID, nil
@@ -249,15 +278,16 @@ func (m *MockPostRepo) Anonymize(ctx context.Context, postID int32) error {
 // History retrieves the post history for a user.
 // This is a stub implementation for the mock.
 func (m *MockPostRepo) History(ctx context.Context, googleID string) (interface{}, error) {
-	return []domain.Post{}, nil
+	return []models.Post{}, nil
 }
 
 // MockStatsRepo mocks StatsRepo interface for testing statistics aggregation operations.
 // It uses simple fields to store configurable return values for testing various scenarios.
 type MockStatsRepo struct {
-	TotalPostsVal     int32 // Configurable return value for TotalPosts
-	TotalReactionsVal int32 // Configurable return value for TotalReactions
-	TotalViewsVal     int32 // Configurable return value for TotalViews
+	TotalPostsVal     int32              // Configurable return value for TotalPosts
+	TotalReactionsVal int32              // Configurable return value for TotalReactions
+	TotalViewsVal     int32              // Configurable return value for TotalViews
+	PostStats         []domain.PostStats // Configurable return value for PostStatsByUser
 }
 
 // NewMockStatsRepo creates a new MockStatsRepo with zero-initialized values.
@@ -285,6 +315,11 @@ func (m *MockStatsRepo) EngagementStats(ctx context.Context, businessID int32) (
 	return m.TotalPostsVal, m.TotalReactionsVal, m.TotalViewsVal, nil
 }
 
+// PostStatsByUser returns the configured per-post statistics regardless of the user.
+func (m *MockStatsRepo) PostStatsByUser(ctx context.Context, userID string) (interface{}, error) {
+	return m.PostStats, nil
+}
+
 // MockBlockRepo mocks BlockRepo interface for testing block/unblock operations.
 // It uses an in-memory map to store block relationships as strings in "blocker:blocked" format.
 type MockBlockRepo struct {
@@ -378,3 +413,115 @@ func NewMockPaymentRepo() *MockPaymentRepo {
 func (m *MockPaymentRepo) CreatePayment(ctx context.Context, businessID int32, amount int, payFlag bool) (int32, error) {
 	return 1, nil
 }
+
+// MockTOTPRepo mocks TOTPRepo interface for testing authenticator app enrollment.
+// Recovery codes are kept per user as a map of code hash to used flag.
+type MockTOTPRepo struct {
+	mu            sync.Mutex
+	Credentials   map[string]*models.TOTPCredential // Key: userID
+	RecoveryCodes map[string]map[string]bool        // Key: userID, Value: codeHash -> used
+}
+
+// NewMockTOTPRepo creates a new MockTOTPRepo with empty storage.
+func NewMockTOTPRepo() *MockTOTPRepo {
+	return &MockTOTPRepo{
+		Credentials:   make(map[string]*models.TOTPCredential),
+		RecoveryCodes: make(map[string]map[string]bool),
+	}
+}
+
+// Get returns a copy of the credential, or nil if the user has not enrolled.
+func (m *MockTOTPRepo) Get(ctx context.Context, userID string) (interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cred, exists := m.Credentials[userID]
+	if !exists {
+		return nil, nil
+	}
+	c := *cred
+	return &c, nil
+}
+
+// SavePending stores an unconfirmed secret, resetting any previous state.
+func (m *MockTOTPRepo) SavePending(ctx context.Context, userID, secretEnc string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Credentials[userID] = &models.TOTPCredential{
+		UserID:    userID,
+		SecretEnc: secretEnc,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	return nil
+}
+
+// Confirm marks a pending credential as confirmed and stores the recovery codes.
+func (m *MockTOTPRepo) Confirm(ctx context.Context, userID string, step int64, codeHashes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cred, exists := m.Credentials[userID]
+	if !exists || cred.ConfirmedAt != nil {
+		return errors.New("pending totp credential not found")
+	}
+	now := time.Now()
+	cred.ConfirmedAt = &now
+	cred.LastUsedStep = step
+	m.setRecoveryCodes(userID, codeHashes)
+	return nil
+}
+
+// MarkStepUsed records the step only if it is newer than the last used one.
+func (m *MockTOTPRepo) MarkStepUsed(ctx context.Context, userID string, step int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cred, exists := m.Credentials[userID]
+	if !exists || cred.LastUsedStep >= step {
+		return false, nil
+	}
+	cred.LastUsedStep = step
+	return true, nil
+}
+
+// ReplaceRecoveryCodes discards existing recovery codes and stores new ones.
+func (m *MockTOTPRepo) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setRecoveryCodes(userID, codeHashes)
+	return nil
+}
+
+// ConsumeRecoveryCode marks an unused recovery code as used.
+func (m *MockTOTPRepo) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	used, exists := m.RecoveryCodes[userID][codeHash]
+	if !exists || used {
+		return false, nil
+	}
+	m.RecoveryCodes[userID][codeHash] = true
+	return true, nil
+}
+
+// Delete removes the credential and recovery codes.
+func (m *MockTOTPRepo) Delete(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.Credentials, userID)
+	delete(m.RecoveryCodes, userID)
+	return nil
+}
+
+func (m *MockTOTPRepo) setRecoveryCodes(userID string, codeHashes []string) {
+	codes := make(map[string]bool, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes[hash] = false
+	}
+	m.RecoveryCodes[userID] = codes
+}
@@ -1,10 +1,16 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrDuplicateReport は同じ投稿に未対応の通報が既にある場合のエラーです。
+var ErrDuplicateReport = errors.New("an open report for this post already exists")
 
 // AuthRepo は認証に関するデータアクセスメソッドを定義します。
 type AuthRepo interface {
-	GetOrCreateUser(ctx context.Context, googleID, gmail, role string) (interface{}, error)
+	FindUserByGoogleSubject(ctx context.Context, googleID string) (interface{}, error)
 	GetUserByID(ctx context.Context, googleID string) (interface{}, error)
 	GetUserByGmail(ctx context.Context, gmail string) (interface{}, error)
 	GetBusinessMemberByUserID(ctx context.Context, userID string) (interface{}, error)
@@ -15,6 +21,8 @@ type BusinessMemberRepo interface {
 	GetByGoogleID(ctx context.Context, googleID string) (interface{}, error)
 	UpdateName(ctx context.Context, businessID int32, name string) error
 	UpdateIcon(ctx context.Context, businessID int32, icon []byte) error
+	// UpdateProfile はプロフィールのうち空でないフィールドを更新します（payload は *domain.BusinessProfileUpdate）
+	UpdateProfile(ctx context.Context, businessID int32, payload interface{}) error
 	Anonymize(ctx context.Context, businessID int32) error
 }
 
@@ -37,6 +45,7 @@ type BlockRepo interface {
 }
 
 // ReportRepo は通報に関するデータアクセスメソッドを定義します。
+// 同じ投稿に未対応の通報が既にある場合、Create は ErrDuplicateReport を返します。
 type ReportRepo interface {
 	Create(ctx context.Context, reporterID string, payload interface{}) error
 }
@@ -52,6 +61,8 @@ type StatsRepo interface {
 	TotalReactions(ctx context.Context, businessID int32) (int32, error)
 	TotalViews(ctx context.Context, businessID int32) (int32, error)
 	EngagementStats(ctx context.Context, businessID int32) (int32, int32, int32, error)
+	// PostStatsByUser はユーザーの削除されていない投稿ごとの統計を返します（[]domain.PostStats）
+	PostStatsByUser(ctx context.Context, userID string) (interface{}, error)
 }
 
 // PaymentRepo は支払いに関するデータアクセスメソッドを定義します。
@@ -75,3 +86,21 @@ type GenreRepo interface {
 	GetByID(ctx context.Context, genreID int32) (interface{}, error)
 	ListByPostID(ctx context.Context, postID int32) ([]int32, error)
 }
+
+// TOTPRepo は認証アプリ（TOTP）とリカバリーコードに関するデータアクセスメソッドを定義します。
+type TOTPRepo interface {
+	// Get は登録情報を返します（未登録の場合は nil）
+	Get(ctx context.Context, userID string) (interface{}, error)
+	// SavePending は確認前のシークレットを保存します（既存の登録途中の情報は置き換えます）
+	SavePending(ctx context.Context, userID, secretEnc string) error
+	// Confirm は登録を確定し、使用したステップとリカバリーコードを保存します
+	Confirm(ctx context.Context, userID string, step int64, codeHashes []string) error
+	// MarkStepUsed は使用済みステップを更新します（既に同じか新しいステップが使われていれば false）
+	MarkStepUsed(ctx context.Context, userID string, step int64) (bool, error)
+	// ReplaceRecoveryCodes はリカバリーコードを再発行します
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	// ConsumeRecoveryCode は未使用のリカバリーコードを使用済みにします（該当なしの場合は false）
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	// Delete は登録情報とリカバリーコードを削除します
+	Delete(ctx context.Context, userID string) error
+}
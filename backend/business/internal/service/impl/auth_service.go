@@ -4,17 +4,23 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	stderrors "errors"
 	"fmt"
 	"os"
 	"time"
 
 	"kojan-map/business/internal/domain"
 	"kojan-map/business/internal/repository"
+	"kojan-map/business/internal/service"
+	"kojan-map/business/pkg/accountstatus"
 	"kojan-map/business/pkg/errors"
 	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/kvstore"
 	"kojan-map/business/pkg/mfa"
+	"kojan-map/business/pkg/models"
 	"kojan-map/business/pkg/notification"
 	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/outbox"
 	"kojan-map/business/pkg/session"
 )
 
@@ -26,8 +32,14 @@ type AuthServiceImpl struct {
 	mfaValidator        *mfa.MFAValidator
 	notificationService notification.NotificationService
 	sessionStore        *session.SessionStore
+	totpService         service.TOTPService
+	outbox              outbox.Enqueuer
+	accounts            service.AccountChecker
 }
 
+// mfaSessionTTL はMFAセッション（メールで送るコード）の有効期間です
+const mfaSessionTTL = 5 * time.Minute
+
 func generateSecureSessionID() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
@@ -48,13 +60,70 @@ func NewAuthServiceImpl(authRepo repository.AuthRepo, tokenManager *jwt.TokenMan
 	}
 
 	return &AuthServiceImpl{
-		authRepo:            authRepo,
-		tokenVerifier:       oauth.NewGoogleTokenVerifier(googleClientID),
-		tokenManager:        tokenManager,
-		mfaValidator:        mfa.NewMFAValidator(),
-		notificationService: notification.NewEmailNotificationService(),
-		sessionStore:        session.NewSessionStore(),
+		authRepo:      authRepo,
+		tokenVerifier: oauth.NewIDTokenVerifier(oauth.ConfigFromEnv(googleClientID)),
+		tokenManager:  tokenManager,
+		mfaValidator:  mfa.NewMFAValidator(),
+		sessionStore:  session.NewSessionStore(),
+	}
+}
+
+// SetTokenVerifier はGoogleのIDトークンの検証器を設定します。
+// メインサーバーに組み込む場合は、ユーザー側と同じ検証器（JWKSのキャッシュ）を共有します。
+func (s *AuthServiceImpl) SetTokenVerifier(verifier oauth.TokenVerifier) {
+	s.tokenVerifier = verifier
+}
+
+// SetNotificationService はMFAコードの送信に使う通知サービスを設定します（outbox を使わない場合は必須）。
+func (s *AuthServiceImpl) SetNotificationService(notifier notification.NotificationService) {
+	s.notificationService = notifier
+}
+
+// SetOutbox はMFAコードのメールを outbox 経由で送信するよう設定します。
+// 設定すると送信の失敗でログインが失敗せず、配信ワーカーが再試行します。
+func (s *AuthServiceImpl) SetOutbox(enqueuer outbox.Enqueuer) {
+	s.outbox = enqueuer
+}
+
+// UseStore はMFAコードとMFAセッションの保存先を共有ストアに切り替えます。
+// 再起動後や複数レプリカ間でもMFAチャレンジを引き継ぐため、起動時に呼び出します。
+// ストアの停止は呼び出し側で行います。
+func (s *AuthServiceImpl) UseStore(store kvstore.Store) {
+	if s.sessionStore != nil {
+		s.sessionStore.Stop()
+	}
+	if s.mfaValidator != nil {
+		s.mfaValidator.Stop()
+	}
+	s.mfaValidator = mfa.NewMFAValidatorWithStore(store)
+	s.sessionStore = session.NewSessionStoreWithStore(store)
+}
+
+// SetTOTPService は認証アプリ（TOTP）による検証を有効にします。
+// 設定すると、事業者ログインでメールのコードの代わりに認証アプリのコードまたはリカバリーコードを受け付けます。
+func (s *AuthServiceImpl) SetTOTPService(totpService service.TOTPService) {
+	s.totpService = totpService
+}
+
+// SetAccountChecker は利用停止・利用禁止・削除されたアカウントのログインとトークン更新を拒否するよう設定します。
+func (s *AuthServiceImpl) SetAccountChecker(accounts service.AccountChecker) {
+	s.accounts = accounts
+}
+
+// checkAccount はアカウントが利用できるかを確認します（AccountChecker が未設定の場合は確認しない）。
+func (s *AuthServiceImpl) checkAccount(ctx context.Context, userID string) error {
+	if s.accounts == nil {
+		return nil
+	}
+	err := s.accounts.CheckAccount(ctx, userID)
+	if err == nil {
+		return nil
 	}
+	var restriction *accountstatus.Restriction
+	if stderrors.As(err, &restriction) {
+		return errors.NewAPIError(errors.ErrAccountRestricted, restriction.Error())
+	}
+	return errors.NewAPIError(errors.ErrInternalServer, "failed to check account status")
 }
 
 // GoogleAuth はGoogle認証を処理します（M3-1）。
@@ -87,105 +156,129 @@ func (s *AuthServiceImpl) GoogleAuth(ctx context.Context, payload interface{}) (
 		return nil, errors.NewAPIError(errors.ErrUnauthorized, "token email does not match gmail")
 	}
 
-	// MFA完了を検証 - MFAコードを生成
+	// 事業者アカウントは申請の承認時に作成・昇格される
+	// 未登録のアカウントや承認前のユーザーは作成・昇格せずに拒否する
+	user, err := s.authRepo.FindUserByGoogleSubject(ctx, req.GoogleID)
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to get user: %v", err))
+	}
+	userData, _ := user.(*models.User)
+	if userData == nil {
+		return nil, errors.NewAPIError(errors.ErrForbidden, "no business account is linked to this Google account")
+	}
+	if userData.Role != models.RoleBusiness {
+		return nil, errors.NewAPIError(errors.ErrForbidden, "business application has not been approved")
+	}
+
+	// 利用停止中・利用禁止のアカウントにはMFAコードを送らない
+	if err := s.checkAccount(ctx, userData.GoogleID); err != nil {
+		return nil, err
+	}
+
+	// MFAコードを生成
 	mfaCode, err := s.mfaValidator.GenerateCode(req.Gmail)
 	if err != nil {
 		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to generate MFA code: %v", err))
 	}
 
-	// ユーザーを取得または作成
-	user, err := s.authRepo.GetOrCreateUser(ctx, req.GoogleID, req.Gmail, "business")
+	// セッションIDを生成し、MFAコードは帯域外（メール）で送信する
+	sessionID, err := generateSecureSessionID()
 	if err != nil {
-		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to get or create user: %v", err))
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, "failed to generate session ID")
 	}
 
-	// 本番環境と開発環境で動作を分岐
-	isProduction := os.Getenv("GO_ENV") == "production"
-	var sessionID string
-
-	if isProduction {
-		// 本番環境: セッションIDを生成し、MFAコードは帯域外で送信
-		var err error
-		sessionID, err = generateSecureSessionID()
-		if err != nil {
-			return nil, errors.NewAPIError(errors.ErrOperationFailed, "failed to generate session ID")
-		}
-
-		// セッション情報を保存（5分間有効）
-		s.sessionStore.CreateSession(sessionID, req.Gmail, mfaCode, req.GoogleID, 5*time.Minute)
-
-		// MFAコードをメールで送信
-		if err := s.notificationService.SendMFACode(req.Gmail, mfaCode); err != nil {
-			return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to send MFA code: %v", err))
-		}
+	// セッション情報を保存（5分間有効）
+	// 認証アプリの確認に使うため、Google の sub ではなく内部ユーザーIDを保存する
+	if err := s.sessionStore.CreateSession(sessionID, req.Gmail, mfaCode, userData.GoogleID, mfaSessionTTL); err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, "failed to create MFA session")
+	}
 
-		// セキュリティ: MFAコードはレスポンスに含めない
-	} else {
-		// 開発環境: MFAコードをセッションIDとして返却（テスト用）
-		sessionID = mfaCode
+	// MFAコードをメールで送信
+	if err := s.sendMFACode(ctx, sessionID, req.Gmail, mfaCode); err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to send MFA code: %v", err))
 	}
 
+	// セキュリティ: MFAコードはレスポンスに含めない
 	// MFAチャレンジを返却 - ユーザーは次のステップでコードを検証する必要がある
 	return &domain.GoogleAuthResponse{
 		SessionID: sessionID,
-		UserID:    user.(*domain.User).ID,
-		Role:      user.(*domain.User).Role,
+		UserID:    userData.GoogleID,
+		Role:      string(userData.Role),
 	}, nil
 }
 
+// sendMFACode はMFAコードのメールを送信します。
+// outbox が設定されている場合は配信ワーカーに任せ、一時的な送信エラーは再試行されます。
+// 冪等キーにはMFAセッションIDを使い、同じチャレンジのメールが重複して送られないようにします。
+func (s *AuthServiceImpl) sendMFACode(ctx context.Context, sessionID, gmail, mfaCode string) error {
+	if s.outbox == nil {
+		if s.notificationService == nil {
+			return fmt.Errorf("no notification service configured")
+		}
+		return s.notificationService.SendMFACode(gmail, mfaCode)
+	}
+	msg, err := outbox.NewEmail("mfa-code:"+sessionID, notification.Message{
+		Kind: notification.KindMFACode,
+		To:   gmail,
+		Data: notification.MFACodeData{Code: mfaCode, ExpiresInMins: int(mfaSessionTTL / time.Minute)},
+	})
+	if err != nil {
+		return err
+	}
+	// コードはセッションの有効期限を過ぎると使えないため、再試行はその間だけ行う
+	msg.MaxAttempts = 4
+	return s.outbox.Enqueue(ctx, msg)
+}
+
 // BusinessLogin は事業者メンバーのログインを処理します（M1-1）。
 func (s *AuthServiceImpl) BusinessLogin(ctx context.Context, sessionID, gmail, mfaCode string) (interface{}, error) {
 	if sessionID == "" || gmail == "" || mfaCode == "" {
 		return nil, errors.NewAPIError(errors.ErrInvalidInput, "sessionId, gmail and mfaCode are required")
 	}
 
-	// 本番環境: sessionStoreで検証
-	// 開発環境: mfaValidatorで検証（後方互換性）
-	isProduction := os.Getenv("GO_ENV") == "production"
-
-	if isProduction {
-		// sessionStoreを使用してMFAコードを検証
-		session, err := s.sessionStore.ValidateMFACode(sessionID, mfaCode)
-		if err != nil {
+	// GoogleAuth で作成したMFAセッションでメールのコードを検証する
+	// メールのコードで検証できない場合は、認証アプリのコード・リカバリーコードを試す
+	session, err := s.sessionStore.ValidateMFACode(sessionID, mfaCode)
+	if err != nil {
+		// 認証アプリのコードも、GoogleAuthで作成したセッションに紐づくユーザーでのみ受け付ける
+		session, err = s.sessionStore.GetSession(sessionID)
+		if err != nil || session.Gmail != gmail || !s.verifyTOTP(ctx, session.GoogleID, mfaCode) {
 			// 詳細なエラー内容はログに出力し、APIレスポンスには汎用メッセージを返す
 			return nil, errors.NewAPIError(errors.ErrMissingMFA, "MFA verification failed")
 		}
+	}
 
-		// セッションのgmailと一致するか確認
-		if session.Gmail != gmail {
-			return nil, errors.NewAPIError(errors.ErrUnauthorized, "gmail mismatch")
-		}
-
-		// 検証成功後、セッションを削除
-		s.sessionStore.DeleteSession(sessionID)
-	} else {
-		// 開発環境: 従来のmfaValidatorを使用
-		valid, err := s.mfaValidator.VerifyCode(gmail, mfaCode)
-		if err != nil || !valid {
-			// 詳細なエラー内容はログに出力し、APIレスポンスには汎用メッセージを返す
-			return nil, errors.NewAPIError(errors.ErrMissingMFA, "MFA verification failed")
-		}
+	// セッションのgmailと一致するか確認
+	if session.Gmail != gmail {
+		return nil, errors.NewAPIError(errors.ErrUnauthorized, "gmail mismatch")
 	}
 
+	// 検証成功後、セッションを削除
+	s.sessionStore.DeleteSession(sessionID)
+
 	// gmailでユーザーを取得し、ロールが'business'であることを確認
 	user, err := s.authRepo.GetUserByGmail(ctx, gmail)
 	if err != nil {
 		return nil, errors.NewAPIError(errors.ErrNotFound, "user not found")
 	}
 
-	userData := user.(*domain.User)
-	if userData.Role != "business" {
+	userData := user.(*models.User)
+	if userData.Role != models.RoleBusiness {
 		return nil, errors.NewAPIError(errors.ErrUnauthorized, "user is not a business member")
 	}
+	// MFAの確認中に利用停止された場合もトークンを発行しない
+	if err := s.checkAccount(ctx, userData.GoogleID); err != nil {
+		return nil, err
+	}
 
 	// JWTトークンを生成
-	token, err := s.tokenManager.GenerateToken(userData.ID, userData.Gmail, userData.Role)
+	token, err := s.tokenManager.GenerateToken(userData.GoogleID, userData.Gmail, string(userData.Role))
 	if err != nil {
 		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to generate JWT token: %v", err))
 	}
 
 	// ビジネスメンバーテーブルから実際の事業者IDを取得
-	businessMember, err := s.authRepo.GetBusinessMemberByUserID(ctx, userData.ID)
+	businessMember, err := s.authRepo.GetBusinessMemberByUserID(ctx, userData.GoogleID)
 	if err != nil {
 		return nil, errors.NewAPIError(errors.ErrNotFound, fmt.Sprintf("business member not found for user: %v", err))
 	}
@@ -193,17 +286,26 @@ func (s *AuthServiceImpl) BusinessLogin(ctx context.Context, sessionID, gmail, m
 		return nil, errors.NewAPIError(errors.ErrNotFound, "business member not found for user")
 	}
 
-	member := businessMember.(*domain.BusinessMember)
+	member := businessMember.(*models.BusinessMember)
 
 	response := &domain.BusinessLoginResponse{
 		Token: token,
 	}
-	response.Business.ID = int(member.ID)
-	response.Business.Role = userData.Role
+	response.Business.ID = int(member.BusinessID)
+	response.Business.Role = string(userData.Role)
 
 	return response, nil
 }
 
+// verifyTOTP は認証アプリのコードまたはリカバリーコードを検証します（未設定・未登録の場合は false）。
+func (s *AuthServiceImpl) verifyTOTP(ctx context.Context, userID, code string) bool {
+	if s.totpService == nil || userID == "" {
+		return false
+	}
+	ok, err := s.totpService.Verify(ctx, userID, code)
+	return err == nil && ok
+}
+
 // RefreshToken はトークンのリフレッシュを処理します（新規エンドポイント）。
 func (s *AuthServiceImpl) RefreshToken(ctx context.Context, refreshTokenString string) (interface{}, error) {
 	if refreshTokenString == "" {
@@ -222,13 +324,16 @@ func (s *AuthServiceImpl) RefreshToken(ctx context.Context, refreshTokenString s
 		return nil, errors.NewAPIError(errors.ErrNotFound, "user not found")
 	}
 
-	userData := user.(*domain.User)
-	if userData.Role != "business" {
+	userData := user.(*models.User)
+	if userData.Role != models.RoleBusiness {
 		return nil, errors.NewAPIError(errors.ErrUnauthorized, "user is not a business member")
 	}
+	if err := s.checkAccount(ctx, userData.GoogleID); err != nil {
+		return nil, err
+	}
 
 	// 新しいアクセストークンを生成（リフレッシュトークンは同じものを維持）
-	newAccessToken, err := s.tokenManager.GenerateToken(userData.ID, userData.Gmail, userData.Role)
+	newAccessToken, err := s.tokenManager.GenerateToken(userData.GoogleID, userData.Gmail, string(userData.Role))
 	if err != nil {
 		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to generate new access token: %v", err))
 	}
@@ -266,6 +371,9 @@ func (s *AuthServiceImpl) Close() {
 	if s.sessionStore != nil {
 		s.sessionStore.Stop()
 	}
+	if s.mfaValidator != nil {
+		s.mfaValidator.Stop()
+	}
 	if s.tokenManager != nil {
 		s.tokenManager.Stop()
 	}
@@ -2,190 +2,174 @@ package impl
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/accountstatus"
+	"kojan-map/business/pkg/errors"
 	"kojan-map/business/pkg/jwt"
 	"kojan-map/business/pkg/mfa"
+	"kojan-map/business/pkg/notification"
 	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/outbox"
+	"kojan-map/business/pkg/session"
+	"kojan-map/business/pkg/totp"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// TestAuthServiceImpl_GoogleAuth tests the Google OAuth authentication flow.
-// Test cases cover valid ID tokens and error conditions like empty tokens.
-func TestAuthServiceImpl_GoogleAuth(t *testing.T) {
-	type args struct {
-		googleID string
-		gmail    string
-		idToken  string
+// newTestAuthService creates an AuthServiceImpl whose MFA mails are recorded in the returned outbox.
+func newTestAuthService(t *testing.T, fixtures *TestFixtures) (*AuthServiceImpl, *recordingOutbox) {
+	t.Helper()
+	mfaValidator := mfa.NewMFAValidator()
+	t.Cleanup(mfaValidator.Stop)
+	sessions := session.NewSessionStore()
+	t.Cleanup(sessions.Stop)
+	queue := &recordingOutbox{}
+	svc := &AuthServiceImpl{
+		authRepo:      fixtures.AuthRepo,
+		tokenVerifier: oauth.NewMockGoogleTokenVerifier("test-client-id"),
+		tokenManager:  jwt.NewTokenManagerWithSecret("test-secret"),
+		mfaValidator:  mfaValidator,
+		sessionStore:  sessions,
+		outbox:        queue,
 	}
+	return svc, queue
+}
+
+// sentMFACode returns the MFA code in the last mail queued to the outbox.
+func sentMFACode(t *testing.T, queue *recordingOutbox) string {
+	t.Helper()
+	require.NotEmpty(t, queue.messages)
+	var mail notification.Message
+	require.NoError(t, json.Unmarshal(queue.messages[len(queue.messages)-1].Payload, &mail))
+	return mail.Data.(notification.MFACodeData).Code
+}
 
+// TestAuthServiceImpl_GoogleAuth tests the Google OAuth authentication flow.
+// Only approved business accounts receive an MFA challenge, and the code is never returned.
+func TestAuthServiceImpl_GoogleAuth(t *testing.T) {
 	tests := []struct {
-		name          string
-		args          args
-		wantErr       bool
-		wantMFACode   bool
-		checkResponse func(t *testing.T, result interface{})
+		name     string
+		setup    func(f *TestFixtures)
+		idToken  string
+		wantCode errors.ErrorCode
 	}{
 		{
-			name: "valid_google_id_token",
-			args: args{
-				googleID: "user123",
-				gmail:    "test@example.com",
-				idToken:  "dummy-jwt-token",
-			},
-			wantErr:     false,
-			wantMFACode: true,
-			checkResponse: func(t *testing.T, result interface{}) {
-				resp, ok := result.(*domain.GoogleAuthResponse)
-				require.True(t, ok, "response should be GoogleAuthResponse")
-				assert.NotEmpty(t, resp.SessionID, "SessionID (MFA code) should not be empty")
+			name: "approved_business_account",
+			setup: func(f *TestFixtures) {
+				f.SetupUser("user123", "test@example.com")
 			},
+			idToken: "dummy-jwt-token",
 		},
 		{
-			name: "empty_id_token",
-			args: args{
-				googleID: "user456",
-				gmail:    "test2@example.com",
-				idToken:  "",
+			name:     "unknown_account_is_not_created",
+			idToken:  "dummy-jwt-token",
+			wantCode: errors.ErrForbidden,
+		},
+		{
+			name: "application_not_approved",
+			setup: func(f *TestFixtures) {
+				f.SetupUser("user123", "test@example.com").Role = "user"
 			},
-			wantErr: true,
+			idToken:  "dummy-jwt-token",
+			wantCode: errors.ErrForbidden,
+		},
+		{
+			name:     "empty_id_token",
+			idToken:  "",
+			wantCode: errors.ErrInvalidInput,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Initialize fixtures
 			fixtures := NewTestFixtures()
-
-			// Initialize authentication components
-			tokenVerifier := oauth.NewMockGoogleTokenVerifier("test-client-id")
-			tokenManager := jwt.NewTokenManager()
-			mfaValidator := mfa.NewMFAValidator()
-
-			// Create service with initialized components
-			svc := &AuthServiceImpl{
-				authRepo:      fixtures.AuthRepo,
-				tokenVerifier: tokenVerifier,
-				tokenManager:  tokenManager,
-				mfaValidator:  mfaValidator,
+			if tt.setup != nil {
+				tt.setup(fixtures)
 			}
+			svc, queue := newTestAuthService(t, fixtures)
 
-			// Execute and verify
-			req := &domain.GoogleAuthRequest{
-				GoogleID: tt.args.googleID,
-				Gmail:    tt.args.gmail,
-				IDToken:  tt.args.idToken,
-			}
-			result, err := svc.GoogleAuth(context.Background(), req)
+			result, err := svc.GoogleAuth(context.Background(), &domain.GoogleAuthRequest{
+				GoogleID: "user123",
+				Gmail:    "test@example.com",
+				IDToken:  tt.idToken,
+			})
 
-			if tt.wantErr {
-				assert.Error(t, err, "GoogleAuth should return error for invalid input")
-			} else {
-				require.NoError(t, err, "GoogleAuth should not return error for valid input")
-				if tt.checkResponse != nil {
-					tt.checkResponse(t, result)
-				}
+			if tt.wantCode != "" {
+				var apiErr *errors.APIError
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, tt.wantCode, apiErr.ErrorCode)
+				assert.Empty(t, queue.messages, "no MFA code should be sent")
+				_, exists := fixtures.AuthRepo.Users["user123"]
+				assert.Equal(t, tt.setup != nil, exists, "GoogleAuth must not create users")
+				return
 			}
+			require.NoError(t, err)
+			resp := result.(*domain.GoogleAuthResponse)
+			assert.NotEmpty(t, resp.SessionID)
+			assert.NotEqual(t, sentMFACode(t, queue), resp.SessionID, "the MFA code must not be returned")
+			assert.Equal(t, "business", resp.Role)
 		})
 	}
 }
 
 // TestAuthServiceImpl_BusinessLogin tests the business login flow with MFA verification.
-// Test cases cover successful login with valid MFA code and error conditions.
 func TestAuthServiceImpl_BusinessLogin(t *testing.T) {
-	type args struct {
-		sessionID string
-		gmail     string
-		mfaCode   string
-	}
-
 	tests := []struct {
-		name          string
-		args          args
-		setupMFA      func(validator *mfa.MFAValidator, email string) string
-		wantErr       bool
-		checkResponse func(t *testing.T, result interface{})
+		name        string
+		wrongCode   bool
+		wrongGmail  bool
+		noMember    bool
+		wantErr     bool
+		wantSession bool // whether the MFA session remains after the attempt
 	}{
-		{
-			name: "valid_credentials_with_mfa",
-			args: args{
-				sessionID: "dummy-session-id",
-				gmail:     "business@example.com",
-				mfaCode:   "",
-			},
-			setupMFA: func(validator *mfa.MFAValidator, email string) string {
-				// Generate and return valid MFA code
-				code, _ := validator.GenerateCode(email)
-				return code
-			},
-			wantErr: false,
-			checkResponse: func(t *testing.T, result interface{}) {
-				resp, ok := result.(*domain.BusinessLoginResponse)
-				require.True(t, ok, "response should be BusinessLoginResponse")
-				assert.NotEmpty(t, resp.Token, "JWT Token should not be empty")
-				assert.Equal(t, "business", resp.Business.Role)
-			},
-		},
-		{
-			name: "invalid_mfa_code",
-			args: args{
-				sessionID: "dummy-session-id",
-				gmail:     "business@example.com",
-				mfaCode:   "000000", // Intentionally wrong code
-			},
-			setupMFA: func(validator *mfa.MFAValidator, email string) string {
-				// Generate code but return wrong one
-				validator.GenerateCode(email)
-				return "000000"
-			},
-			wantErr: true,
-		},
+		{name: "valid_mfa_code"},
+		{name: "invalid_mfa_code", wrongCode: true, wantErr: true, wantSession: true},
+		{name: "gmail_mismatch", wrongGmail: true, wantErr: true, wantSession: true},
+		{name: "no_business_member", noMember: true, wantErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Initialize fixtures
 			fixtures := NewTestFixtures()
-
-			// Initialize authentication components
-			mfaValidator := mfa.NewMFAValidator()
-			tokenManager := jwt.NewTokenManager()
-
-			// Generate MFA code if setup function provided
-			mfaCode := ""
-			if tt.setupMFA != nil {
-				mfaCode = tt.setupMFA(mfaValidator, tt.args.gmail)
+			fixtures.SetupUser("user123", "test@example.com")
+			if !tt.noMember {
+				fixtures.SetupBusinessMember(1, "user123", "Test Business", nil)
 			}
+			svc, queue := newTestAuthService(t, fixtures)
 
-			// Create service with initialized components
-			svc := &AuthServiceImpl{
-				authRepo:     fixtures.AuthRepo,
-				tokenManager: tokenManager,
-				mfaValidator: mfaValidator,
-			}
+			challenge, err := svc.GoogleAuth(context.Background(), &domain.GoogleAuthRequest{
+				GoogleID: "user123",
+				Gmail:    "test@example.com",
+				IDToken:  "dummy-jwt-token",
+			})
+			require.NoError(t, err)
+			sessionID := challenge.(*domain.GoogleAuthResponse).SessionID
 
-			// Create test user in repository (use gmail as googleID for mock)
-			fixtures.SetupUser(tt.args.gmail, tt.args.gmail)
-
-			// Setup Business Member for successful login cases
-			if !tt.wantErr {
-				fixtures.SetupBusinessMember(1, tt.args.gmail, "Test Business", nil)
+			code := sentMFACode(t, queue)
+			if tt.wrongCode {
+				code = "000000"
+			}
+			gmail := "test@example.com"
+			if tt.wrongGmail {
+				gmail = "other@example.com"
 			}
 
-			// Execute and verify
-			result, err := svc.BusinessLogin(context.Background(), tt.args.sessionID, tt.args.gmail, mfaCode)
-
+			result, err := svc.BusinessLogin(context.Background(), sessionID, gmail, code)
+			_, sessionErr := svc.sessionStore.GetSession(sessionID)
+			assert.Equal(t, tt.wantSession, sessionErr == nil)
 			if tt.wantErr {
-				assert.Error(t, err, "BusinessLogin should return error for invalid input")
-			} else {
-				require.NoError(t, err, "BusinessLogin should not return error for valid input")
-				if tt.checkResponse != nil {
-					tt.checkResponse(t, result)
-				}
+				assert.Error(t, err)
+				return
 			}
+			require.NoError(t, err)
+			resp := result.(*domain.BusinessLoginResponse)
+			assert.NotEmpty(t, resp.Token)
+			assert.Equal(t, "business", resp.Business.Role)
 		})
 	}
 }
@@ -224,7 +208,7 @@ func TestAuthServiceImpl_Logout(t *testing.T) {
 			fixtures := NewTestFixtures()
 
 			// Initialize authentication components
-			tokenManager := jwt.NewTokenManager()
+			tokenManager := jwt.NewTokenManagerWithSecret("test-secret")
 			mfaValidator := mfa.NewMFAValidator()
 
 			// Create service with initialized components
@@ -252,3 +236,190 @@ func TestAuthServiceImpl_Logout(t *testing.T) {
 		})
 	}
 }
+
+// TestAuthServiceImpl_BusinessLogin_TOTP tests that an authenticator code or recovery code
+// is accepted in place of the email code.
+func TestAuthServiceImpl_BusinessLogin_TOTP(t *testing.T) {
+	now := time.Now()
+	fixtures := NewTestFixtures()
+	fixtures.SetupUser("user123", "test@example.com")
+	fixtures.SetupBusinessMember(1, "user123", "Test Business", nil)
+
+	totpService, _ := newTestTOTPService(t, now)
+	secret, recoveryCodes := enrollTOTP(t, totpService, "user123", now.Add(-totp.Period*time.Second))
+
+	svc, _ := newTestAuthService(t, fixtures)
+	challenge := func() string {
+		result, err := svc.GoogleAuth(context.Background(), &domain.GoogleAuthRequest{GoogleID: "user123", Gmail: "test@example.com", IDToken: "dummy-jwt-token"})
+		require.NoError(t, err)
+		return result.(*domain.GoogleAuthResponse).SessionID
+	}
+
+	code, err := totp.Code(secret, now)
+	require.NoError(t, err)
+
+	// Not accepted until the TOTP service is configured
+	sessionID := challenge()
+	_, err = svc.BusinessLogin(context.Background(), sessionID, "test@example.com", code)
+	assert.Error(t, err)
+
+	svc.SetTOTPService(totpService)
+
+	result, err := svc.BusinessLogin(context.Background(), sessionID, "test@example.com", code)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.(*domain.BusinessLoginResponse).Token)
+
+	// Same code cannot be replayed
+	_, err = svc.BusinessLogin(context.Background(), challenge(), "test@example.com", code)
+	assert.Error(t, err)
+
+	_, err = svc.BusinessLogin(context.Background(), challenge(), "test@example.com", recoveryCodes[0])
+	require.NoError(t, err)
+}
+
+// TestAuthServiceImpl_BusinessLogin_TOTPSession tests that the TOTP code
+// is only accepted for the user bound to the MFA session.
+func TestAuthServiceImpl_BusinessLogin_TOTPSession(t *testing.T) {
+	now := time.Now()
+	fixtures := NewTestFixtures()
+	fixtures.SetupUser("biz-1", "business@example.com")
+	fixtures.SetupBusinessMember(1, "biz-1", "Test Business", nil)
+
+	totpService, _ := newTestTOTPService(t, now)
+	secret, _ := enrollTOTP(t, totpService, "biz-1", now.Add(-totp.Period*time.Second))
+
+	sessions := session.NewSessionStore()
+	defer sessions.Stop()
+	require.NoError(t, sessions.CreateSession("sess-1", "business@example.com", "123456", "biz-1", 5*time.Minute))
+	require.NoError(t, sessions.CreateSession("sess-2", "other@example.com", "654321", "other", 5*time.Minute))
+
+	svc := &AuthServiceImpl{
+		authRepo:     fixtures.AuthRepo,
+		tokenManager: jwt.NewTokenManagerWithSecret("test-secret"),
+		sessionStore: sessions,
+	}
+	svc.SetTOTPService(totpService)
+
+	code, err := totp.Code(secret, now)
+	require.NoError(t, err)
+
+	// A session issued to another account cannot be used
+	_, err = svc.BusinessLogin(context.Background(), "sess-2", "business@example.com", code)
+	assert.Error(t, err)
+	_, err = svc.BusinessLogin(context.Background(), "unknown", "business@example.com", code)
+	assert.Error(t, err)
+
+	_, err = svc.BusinessLogin(context.Background(), "sess-1", "business@example.com", code)
+	require.NoError(t, err)
+
+	_, err = sessions.GetSession("sess-1")
+	assert.Error(t, err, "session should be deleted after login")
+}
+
+// failingNotifier always fails to send, like an unreachable mail server.
+type failingNotifier struct{}
+
+func (failingNotifier) Send(context.Context, notification.Message) error {
+	return assert.AnError
+}
+
+func (failingNotifier) SendMFACode(string, string) error {
+	return assert.AnError
+}
+
+// recordingOutbox records the messages handed to the outbox.
+type recordingOutbox struct {
+	messages []*outbox.Message
+}
+
+func (o *recordingOutbox) Enqueue(_ context.Context, msg *outbox.Message) error {
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+// TestAuthServiceImpl_GoogleAuth_Outbox tests that a mail failure no longer
+// fails the login once the outbox is configured, and the code is queued for delivery instead.
+func TestAuthServiceImpl_GoogleAuth_Outbox(t *testing.T) {
+	fixtures := NewTestFixtures()
+	fixtures.SetupUser("user123", "test@example.com")
+
+	mfaValidator := mfa.NewMFAValidator()
+	defer mfaValidator.Stop()
+	sessions := session.NewSessionStore()
+	defer sessions.Stop()
+
+	svc := &AuthServiceImpl{
+		authRepo:            fixtures.AuthRepo,
+		tokenVerifier:       oauth.NewMockGoogleTokenVerifier("test-client-id"),
+		tokenManager:        jwt.NewTokenManagerWithSecret("test-secret"),
+		mfaValidator:        mfaValidator,
+		notificationService: failingNotifier{},
+		sessionStore:        sessions,
+	}
+	req := &domain.GoogleAuthRequest{GoogleID: "user123", Gmail: "test@example.com", IDToken: "dummy-jwt-token"}
+
+	// Without the outbox the mail is sent inline and its failure fails the login
+	_, err := svc.GoogleAuth(context.Background(), req)
+	assert.Error(t, err)
+
+	queue := &recordingOutbox{}
+	svc.SetOutbox(queue)
+
+	result, err := svc.GoogleAuth(context.Background(), req)
+	require.NoError(t, err)
+	sessionID := result.(*domain.GoogleAuthResponse).SessionID
+
+	require.Len(t, queue.messages, 1)
+	msg := queue.messages[0]
+	assert.Equal(t, outbox.TopicEmail, msg.Topic)
+	assert.Equal(t, "mfa-code:"+sessionID, msg.IdempotencyKey)
+	assert.True(t, msg.RedactOnDelivery)
+
+	var mail notification.Message
+	require.NoError(t, json.Unmarshal(msg.Payload, &mail))
+	assert.Equal(t, "test@example.com", mail.To)
+	sess, err := sessions.GetSession(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, sess.MFACode, mail.Data.(notification.MFACodeData).Code)
+}
+
+// restrictedAccounts reports every account in the map as restricted.
+type restrictedAccounts map[string]*accountstatus.Restriction
+
+func (r restrictedAccounts) CheckAccount(_ context.Context, userID string) error {
+	if restriction, ok := r[userID]; ok {
+		return restriction
+	}
+	return nil
+}
+
+func TestAuthServiceImpl_AccountRestricted(t *testing.T) {
+	fixtures := NewTestFixtures()
+	fixtures.SetupUser("user123", "test@example.com")
+	svc, _ := newTestAuthService(t, fixtures)
+	tokenManager := svc.tokenManager
+	until := time.Now().Add(72 * time.Hour)
+	svc.SetAccountChecker(restrictedAccounts{
+		"user123": {Kind: accountstatus.KindSuspended, Reason: "spam", Until: &until},
+	})
+
+	// 利用停止中のアカウントにはMFAチャレンジを発行しない
+	_, err := svc.GoogleAuth(context.Background(), &domain.GoogleAuthRequest{GoogleID: "user123", Gmail: "test@example.com", IDToken: "dummy-jwt-token"})
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errors.ErrAccountRestricted, apiErr.ErrorCode)
+	assert.Equal(t, 403, apiErr.StatusCode)
+	assert.Contains(t, apiErr.Message, until.Format(time.RFC3339))
+
+	// 利用停止前に発行されたリフレッシュトークンでもアクセストークンを再発行しない
+	_, refreshToken, err := tokenManager.GenerateTokenPair("user123", "test@example.com", "business")
+	require.NoError(t, err)
+	_, err = svc.RefreshToken(context.Background(), refreshToken)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errors.ErrAccountRestricted, apiErr.ErrorCode)
+
+	// 制限が解除されるとログインできる
+	svc.SetAccountChecker(restrictedAccounts{})
+	_, err = svc.GoogleAuth(context.Background(), &domain.GoogleAuthRequest{GoogleID: "user123", Gmail: "test@example.com", IDToken: "dummy-jwt-token"})
+	require.NoError(t, err)
+}
@@ -2,10 +2,14 @@ package impl
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"strings"
 
+	"kojan-map/business/internal/domain"
 	"kojan-map/business/internal/repository"
 	"kojan-map/business/pkg/errors"
+	"kojan-map/business/pkg/reportcategory"
 )
 
 // BlockServiceImpl はBlockServiceインターフェースを実装します。
@@ -66,13 +70,30 @@ func NewReportServiceImpl(reportRepo repository.ReportRepo) *ReportServiceImpl {
 }
 
 // CreateReport は新しい通報を作成します（M1-12-2）。
-// 通報内容は必須フィールド全て埋める必要がある
+// 分類を省略した場合は other とし、other の場合は通報理由が必須です。
+// 同じ投稿に未対応の通報が既にある場合は重複エラーを返します。
 func (s *ReportServiceImpl) CreateReport(ctx context.Context, reporterID string, payload interface{}) error {
 	if reporterID == "" {
 		return errors.NewAPIError(errors.ErrInvalidInput, "reporterID is required")
 	}
 
+	if req, ok := payload.(*domain.CreateReportRequest); ok {
+		req.ReportReason = strings.TrimSpace(req.ReportReason)
+		if req.Category == "" {
+			req.Category = string(reportcategory.Other)
+		}
+		if !reportcategory.IsValid(reportcategory.Category(req.Category)) {
+			return errors.NewAPIError(errors.ErrInvalidInput, "invalid report category")
+		}
+		if req.Category == string(reportcategory.Other) && req.ReportReason == "" {
+			return errors.NewAPIError(errors.ErrInvalidInput, "reportReason is required for category other")
+		}
+	}
+
 	err := s.reportRepo.Create(ctx, reporterID, payload)
+	if stderrors.Is(err, repository.ErrDuplicateReport) {
+		return errors.NewAPIError(errors.ErrDuplicate, "you have already reported this post")
+	}
 	if err != nil {
 		return errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to create report: %v", err))
 	}
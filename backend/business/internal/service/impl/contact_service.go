@@ -5,12 +5,14 @@ import (
 	"fmt"
 
 	"kojan-map/business/internal/repository"
+	"kojan-map/business/internal/service"
 	"kojan-map/business/pkg/errors"
 )
 
 // ContactServiceImpl はContactServiceインターフェースを実装します。
 type ContactServiceImpl struct {
-	contactRepo repository.ContactRepo
+	contactRepo   repository.ContactRepo
+	contentFilter service.ContentFilter
 }
 
 // NewContactServiceImpl は新しいお問い合わせサービスを作成します。
@@ -18,6 +20,11 @@ func NewContactServiceImpl(contactRepo repository.ContactRepo) *ContactServiceIm
 	return &ContactServiceImpl{contactRepo: contactRepo}
 }
 
+// SetContentFilter はNGワード・個人情報フィルタを設定します。
+func (s *ContactServiceImpl) SetContentFilter(filter service.ContentFilter) {
+	s.contentFilter = filter
+}
+
 // CreateContact はお問い合わせの送信を処理します（M1-11-2）。
 func (s *ContactServiceImpl) CreateContact(ctx context.Context, googleID, subject, message string) error {
 	if subject == "" || message == "" {
@@ -28,6 +35,11 @@ func (s *ContactServiceImpl) CreateContact(ctx context.Context, googleID, subjec
 		return errors.NewAPIError(errors.ErrInvalidInput, "googleId is required")
 	}
 
+	// お問い合わせは管理者のみが閲覧するため、拒否・マスクのみ適用する
+	if _, err := applyContentFilter(s.contentFilter, &subject, &message); err != nil {
+		return err
+	}
+
 	if err := s.contactRepo.Create(ctx, googleID, subject, message); err != nil {
 		return errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to create contact: %v", err))
 	}
@@ -0,0 +1,19 @@
+package impl
+
+import (
+	"kojan-map/business/internal/service"
+	"kojan-map/business/pkg/errors"
+)
+
+// applyContentFilter はフィルタが設定されている場合にフィールドを検査します。
+// 拒否された場合は ErrContentRejected を返します。
+func applyContentFilter(filter service.ContentFilter, fields ...*string) (bool, error) {
+	if filter == nil {
+		return false, nil
+	}
+	review, err := filter.Apply(fields...)
+	if err != nil {
+		return false, errors.NewAPIError(errors.ErrContentRejected, err.Error())
+	}
+	return review, nil
+}
@@ -11,14 +11,17 @@ import (
 
 	"kojan-map/business/internal/domain"
 	"kojan-map/business/internal/repository"
+	"kojan-map/business/internal/service"
 	"kojan-map/business/pkg/contextkeys"
 	"kojan-map/business/pkg/errors"
+	"kojan-map/business/pkg/models"
 )
 
 // MemberServiceImpl はMemberServiceインターフェースを実装します。
 type MemberServiceImpl struct {
-	memberRepo repository.BusinessMemberRepo
-	authRepo   repository.AuthRepo
+	memberRepo    repository.BusinessMemberRepo
+	authRepo      repository.AuthRepo
+	contentFilter service.ContentFilter
 }
 
 // NewMemberServiceImpl は新しいメンバーサービスを作成します。
@@ -29,6 +32,11 @@ func NewMemberServiceImpl(memberRepo repository.BusinessMemberRepo, authRepo rep
 	}
 }
 
+// SetContentFilter はNGワード・個人情報フィルタを設定します。
+func (s *MemberServiceImpl) SetContentFilter(filter service.ContentFilter) {
+	s.contentFilter = filter
+}
+
 // GetBusinessDetails は事業者メンバーの詳細情報を取得します（M3-2-2）。
 // 存在しないGoogle IDが指定された場合はエラーを返す
 func (s *MemberServiceImpl) GetBusinessDetails(ctx context.Context, googleID string) (interface{}, error) {
@@ -47,11 +55,11 @@ func (s *MemberServiceImpl) GetBusinessDetails(ctx context.Context, googleID str
 		return nil, errors.NewAPIError(errors.ErrNotFound, "user not found")
 	}
 
-	memberData, ok := member.(*domain.BusinessMember)
+	memberData, ok := member.(*models.BusinessMember)
 	if !ok || memberData == nil {
 		return nil, errors.NewAPIError(errors.ErrOperationFailed, "invalid business member type")
 	}
-	userData, ok := user.(*domain.User)
+	userData, ok := user.(*models.User)
 	if !ok || userData == nil {
 		return nil, errors.NewAPIError(errors.ErrOperationFailed, "invalid user type")
 	}
@@ -68,7 +76,7 @@ func (s *MemberServiceImpl) GetBusinessDetails(ctx context.Context, googleID str
 	}
 
 	return &domain.BusinessMemberResponse{
-		ID:           memberData.ID,
+		ID:           memberData.BusinessID,
 		BusinessName: memberData.BusinessName,
 		Gmail:        userData.Gmail,
 		RegistDate:   memberData.RegistDate.Format(time.RFC3339),
@@ -93,7 +101,16 @@ func (s *MemberServiceImpl) UpdateBusinessName(ctx context.Context, businessID i
 		return errors.NewAPIError(errors.ErrForbidden, "you are not authorized to update this business")
 	}
 
-	err := s.memberRepo.UpdateName(ctx, businessID, name)
+	// プロフィールには審査キューがないため、審査待ち判定は拒否として扱う
+	review, err := applyContentFilter(s.contentFilter, &name)
+	if err != nil {
+		return err
+	}
+	if review {
+		return errors.NewAPIError(errors.ErrContentRejected, "business name requires review")
+	}
+
+	err = s.memberRepo.UpdateName(ctx, businessID, name)
 	if err != nil {
 		return errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to update business name: %v", err))
 	}
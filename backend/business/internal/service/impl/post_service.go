@@ -3,16 +3,20 @@ package impl
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"kojan-map/business/internal/domain"
 	"kojan-map/business/internal/repository"
+	"kojan-map/business/internal/service"
 	"kojan-map/business/pkg/contextkeys"
 	"kojan-map/business/pkg/errors"
+	"kojan-map/business/pkg/models"
 )
 
 // PostServiceImpl はPostServiceインターフェースを実装します。
 type PostServiceImpl struct {
-	postRepo repository.PostRepo
+	postRepo      repository.PostRepo
+	contentFilter service.ContentFilter
 }
 
 // NewPostServiceImpl は新しい投稿サービスを作成します。
@@ -22,6 +26,11 @@ func NewPostServiceImpl(postRepo repository.PostRepo) *PostServiceImpl {
 	}
 }
 
+// SetContentFilter はNGワード・個人情報フィルタを設定します。
+func (s *PostServiceImpl) SetContentFilter(filter service.ContentFilter) {
+	s.contentFilter = filter
+}
+
 // List は事業者の全投稿を取得します（M1-6-1）。
 func (s *PostServiceImpl) List(ctx context.Context, businessID int32) (interface{}, error) {
 	if businessID <= 0 {
@@ -33,7 +42,36 @@ func (s *PostServiceImpl) List(ctx context.Context, businessID int32) (interface
 		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to list posts: %v", err))
 	}
 
-	return posts, nil
+	// 下書き・予約投稿は投稿者本人にのみ返す
+	postList, ok := posts.([]models.Post)
+	if !ok {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, "invalid post list type")
+	}
+	userID, _ := contextkeys.GetUserID(ctx)
+	visible := make([]models.Post, 0, len(postList))
+	for _, p := range postList {
+		if isVisibleTo(&p, userID) {
+			visible = append(visible, p)
+		}
+	}
+
+	return visible, nil
+}
+
+// isVisibleTo は投稿が指定ユーザーに閲覧可能かを判定します。
+// 公開済みかつ審査で承認された投稿以外は投稿者本人のみ閲覧できます。
+func isVisibleTo(post *models.Post, userID string) bool {
+	if isPublic(post) {
+		return true
+	}
+	return userID != "" && post.UserID == userID
+}
+
+// isPublic は投稿が一般公開されているかを判定します。
+func isPublic(post *models.Post) bool {
+	published := post.Status == "" || post.Status == models.PostStatusPublished
+	approved := post.ModerationStatus == "" || post.ModerationStatus == models.ModerationApproved
+	return published && approved
 }
 
 // Get はIDで投稿を取得します（M1-7-2）。
@@ -42,6 +80,24 @@ func (s *PostServiceImpl) Get(ctx context.Context, postID int32) (interface{}, e
 		return nil, errors.NewAPIError(errors.ErrInvalidInput, "postId must be greater than 0")
 	}
 
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrNotFound, fmt.Sprintf("post not found: %v", err))
+	}
+
+	// 未公開の投稿は投稿者本人以外には存在しないものとして扱う
+	postData, ok := post.(*models.Post)
+	if !ok || postData == nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, "invalid post type")
+	}
+	userID, _ := contextkeys.GetUserID(ctx)
+	if !isVisibleTo(postData, userID) {
+		return nil, errors.NewAPIError(errors.ErrNotFound, fmt.Sprintf("post not found for id %d", postID))
+	}
+	if !isPublic(postData) {
+		return postData, nil
+	}
+
 	// 投稿を返す前に閲覧数をインクリメント
 	// インクリメントが失敗しても、投稿内容は返す
 	if err := s.postRepo.IncrementViewCount(ctx, postID); err != nil {
@@ -50,9 +106,9 @@ func (s *PostServiceImpl) Get(ctx context.Context, postID int32) (interface{}, e
 		_ = err
 	}
 
-	post, err := s.postRepo.GetByID(ctx, postID)
-	if err != nil {
-		return nil, errors.NewAPIError(errors.ErrNotFound, fmt.Sprintf("post not found: %v", err))
+	// インクリメント後の閲覧数を返すため再取得する
+	if refreshed, err := s.postRepo.GetByID(ctx, postID); err == nil && refreshed != nil {
+		return refreshed, nil
 	}
 
 	return post, nil
@@ -70,6 +126,18 @@ func (s *PostServiceImpl) Create(ctx context.Context, businessID int32, placeID
 		return 0, errors.NewAPIError(errors.ErrInvalidInput, "invalid payload type")
 	}
 
+	if err := validatePublishStatus(req, time.Now()); err != nil {
+		return 0, err
+	}
+
+	review, err := applyContentFilter(s.contentFilter, &req.Title, &req.Description)
+	if err != nil {
+		return 0, err
+	}
+	if review {
+		req.ModerationStatus = models.ModerationPending
+	}
+
 	// 画像URLの検証は省略（クライアントまたは画像アップロードエンドポイントで実施）
 	// 本番環境では、画像は事前にS3などにアップロードされ、URLが渡される想定
 
@@ -81,6 +149,28 @@ func (s *PostServiceImpl) Create(ctx context.Context, businessID int32, placeID
 	return postID, nil
 }
 
+// validatePublishStatus は公開状態と公開日時を検証し、既定値を補完します。
+// 予約投稿は未来の publishAt が必須です。
+func validatePublishStatus(req *domain.CreatePostRequest, now time.Time) error {
+	switch req.Status {
+	case "", models.PostStatusPublished:
+		req.Status = models.PostStatusPublished
+		req.PublishAt = nil
+	case models.PostStatusDraft:
+		req.PublishAt = nil
+	case models.PostStatusScheduled:
+		if req.PublishAt == nil {
+			return errors.NewAPIError(errors.ErrInvalidInput, "publishAt is required for scheduled posts")
+		}
+		if !req.PublishAt.After(now) {
+			return errors.NewAPIError(errors.ErrInvalidInput, "publishAt must be in the future")
+		}
+	default:
+		return errors.NewAPIError(errors.ErrInvalidInput, "status must be one of draft, scheduled, published")
+	}
+	return nil
+}
+
 // SetGenres は投稿のジャンルを設定します（M1-8-4）。
 func (s *PostServiceImpl) SetGenres(ctx context.Context, postID int32, genreIDs []int32) error {
 	if postID <= 0 {
@@ -117,7 +207,7 @@ func (s *PostServiceImpl) Anonymize(ctx context.Context, postID int32) error {
 	}
 
 	// 型アサーション
-	postData, ok := post.(*domain.Post)
+	postData, ok := post.(*models.Post)
 	if !ok {
 		return errors.NewAPIError(errors.ErrOperationFailed, "invalid post type")
 	}
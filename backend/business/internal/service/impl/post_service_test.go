@@ -3,9 +3,12 @@ package impl
 import (
 	"context"
 	"testing"
+	"time"
 
 	"kojan-map/business/internal/domain"
+	"kojan-map/business/internal/repository/mock"
 	"kojan-map/business/pkg/contextkeys"
+	"kojan-map/business/pkg/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -191,6 +194,72 @@ func TestPostServiceImpl_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid_draft_create",
+			args: args{
+				businessID: 1,
+				placeID:    10,
+				genreIDs:   []int32{1},
+				payload: &domain.CreatePostRequest{
+					LocationID:  "loc-123",
+					GenreIDs:    []int32{1},
+					Title:       "Draft Post",
+					Description: "Draft Description",
+					Status:      models.PostStatusDraft,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid_scheduled_create",
+			args: args{
+				businessID: 1,
+				placeID:    10,
+				genreIDs:   []int32{1},
+				payload: &domain.CreatePostRequest{
+					LocationID:  "loc-123",
+					GenreIDs:    []int32{1},
+					Title:       "Scheduled Post",
+					Description: "Scheduled Description",
+					Status:      models.PostStatusScheduled,
+					PublishAt:   timePtr(time.Now().Add(time.Hour)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "scheduled_without_publish_at",
+			args: args{
+				businessID: 1,
+				placeID:    10,
+				genreIDs:   []int32{1},
+				payload: &domain.CreatePostRequest{
+					LocationID:  "loc-123",
+					GenreIDs:    []int32{1},
+					Title:       "Scheduled Post",
+					Description: "Scheduled Description",
+					Status:      models.PostStatusScheduled,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "scheduled_in_the_past",
+			args: args{
+				businessID: 1,
+				placeID:    10,
+				genreIDs:   []int32{1},
+				payload: &domain.CreatePostRequest{
+					LocationID:  "loc-123",
+					GenreIDs:    []int32{1},
+					Title:       "Scheduled Post",
+					Description: "Scheduled Description",
+					Status:      models.PostStatusScheduled,
+					PublishAt:   timePtr(time.Now().Add(-time.Hour)),
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -418,3 +487,132 @@ func TestPostServiceImpl_History(t *testing.T) {
 		})
 	}
 }
+
+// TestPostServiceImpl_Get_Unpublished tests that drafts and scheduled posts are visible only to their author.
+func TestPostServiceImpl_Get_Unpublished(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		requester string
+		wantErr   bool
+	}{
+		{name: "draft_by_author", status: models.PostStatusDraft, requester: "author-1", wantErr: false},
+		{name: "draft_by_other_user", status: models.PostStatusDraft, requester: "other-user", wantErr: true},
+		{name: "scheduled_by_other_user", status: models.PostStatusScheduled, requester: "other-user", wantErr: true},
+		{name: "scheduled_without_auth", status: models.PostStatusScheduled, requester: "", wantErr: true},
+		{name: "published_by_other_user", status: models.PostStatusPublished, requester: "other-user", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixtures := NewTestFixtures()
+			post := fixtures.SetupPost(1, "author-1", "Test Post", "Test Content", 0)
+			post.Status = tt.status
+
+			svc := &PostServiceImpl{
+				postRepo: fixtures.PostRepo,
+			}
+
+			ctx := context.Background()
+			if tt.requester != "" {
+				ctx = contextkeys.WithUserID(ctx, tt.requester)
+			}
+
+			_, err := svc.Get(ctx, 1)
+			if tt.wantErr {
+				assert.Error(t, err, "unpublished post should not be visible to other users")
+			} else {
+				require.NoError(t, err)
+			}
+
+			// 未公開の投稿は閲覧数を加算しない
+			if tt.status != models.PostStatusPublished {
+				assert.Equal(t, int32(0), post.NumView)
+			}
+		})
+	}
+}
+
+// TestPostServiceImpl_List_HidesOthersDrafts tests that listing hides other users' unpublished posts.
+func TestPostServiceImpl_List_HidesOthersDrafts(t *testing.T) {
+	fixtures := NewTestFixtures()
+	fixtures.SetupPost(1, "author-1", "Published", "Content", 0).Status = models.PostStatusPublished
+	fixtures.SetupPost(2, "author-1", "Draft", "Content", 0).Status = models.PostStatusDraft
+
+	svc := &PostServiceImpl{
+		postRepo: fixtures.PostRepo,
+	}
+
+	result, err := svc.List(contextkeys.WithUserID(context.Background(), "other-user"), 1)
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	result, err = svc.List(contextkeys.WithUserID(context.Background(), "author-1"), 1)
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// stubContentFilter is a ContentFilter returning fixed results for testing.
+type stubContentFilter struct {
+	review bool
+	err    error
+}
+
+func (f stubContentFilter) Apply(fields ...*string) (bool, error) {
+	return f.review, f.err
+}
+
+// TestPostServiceImpl_Create_ContentFilter tests that filter decisions are applied on post creation.
+func TestPostServiceImpl_Create_ContentFilter(t *testing.T) {
+	newRequest := func() *domain.CreatePostRequest {
+		return &domain.CreatePostRequest{
+			LocationID:  "loc-123",
+			GenreIDs:    []int32{1},
+			Title:       "Test Post",
+			Description: "Test Description",
+		}
+	}
+
+	t.Run("rejected_content", func(t *testing.T) {
+		fixtures := NewTestFixtures()
+		svc := &PostServiceImpl{postRepo: fixtures.PostRepo, contentFilter: stubContentFilter{err: assert.AnError}}
+
+		_, err := svc.Create(context.Background(), 1, 10, []int32{1}, newRequest())
+		assert.Error(t, err, "Create should fail when the filter rejects the content")
+		assert.Empty(t, fixtures.PostRepo.Posts)
+	})
+
+	t.Run("content_needs_review", func(t *testing.T) {
+		fixtures := NewTestFixtures()
+		svc := &PostServiceImpl{postRepo: fixtures.PostRepo, contentFilter: stubContentFilter{review: true}}
+
+		req := newRequest()
+		_, err := svc.Create(context.Background(), 1, 10, []int32{1}, req)
+		require.NoError(t, err)
+		assert.Equal(t, models.ModerationPending, req.ModerationStatus)
+	})
+}
+
+// untypedPostRepo は想定外の型で投稿一覧を返すリポジトリです。
+type untypedPostRepo struct {
+	*mock.MockPostRepo
+}
+
+func (untypedPostRepo) ListByBusiness(ctx context.Context, businessID int32) (interface{}, error) {
+	return []map[string]interface{}{{"postId": 1, "status": models.PostStatusDraft}}, nil
+}
+
+func TestPostServiceImpl_List_UnexpectedType(t *testing.T) {
+	svc := &PostServiceImpl{
+		postRepo: untypedPostRepo{mock.NewMockPostRepo()},
+	}
+
+	// 未公開の投稿を絞り込めない場合は結果を返さない
+	result, err := svc.List(context.Background(), 1)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,289 @@
+package impl
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"kojan-map/business/internal/domain"
+	"kojan-map/business/internal/repository"
+	"kojan-map/business/internal/service"
+	"kojan-map/business/pkg/errors"
+	"kojan-map/business/pkg/models"
+)
+
+// ProfileServiceImpl はProfileServiceインターフェースを実装します。
+type ProfileServiceImpl struct {
+	memberRepo    repository.BusinessMemberRepo
+	statsRepo     repository.StatsRepo
+	contentFilter service.ContentFilter
+	now           func() time.Time
+}
+
+// NewProfileServiceImpl は新しいプロフィールサービスを作成します。
+func NewProfileServiceImpl(memberRepo repository.BusinessMemberRepo, statsRepo repository.StatsRepo) *ProfileServiceImpl {
+	return &ProfileServiceImpl{
+		memberRepo: memberRepo,
+		statsRepo:  statsRepo,
+		now:        time.Now,
+	}
+}
+
+// SetContentFilter はNGワード・個人情報フィルタを設定します。
+func (s *ProfileServiceImpl) SetContentFilter(filter service.ContentFilter) {
+	s.contentFilter = filter
+}
+
+// member はユーザーIDから事業者メンバーを取得します。
+func (s *ProfileServiceImpl) member(ctx context.Context, userID string) (*models.BusinessMember, error) {
+	if userID == "" {
+		return nil, errors.NewAPIError(errors.ErrUnauthorized, "user ID not found in context")
+	}
+	found, err := s.memberRepo.GetByGoogleID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrNotFound, "business profile not found")
+	}
+	member, ok := found.(*models.BusinessMember)
+	if !ok || member == nil {
+		return nil, errors.NewAPIError(errors.ErrNotFound, "business profile not found")
+	}
+	return member, nil
+}
+
+// filterProfileText は事業者名・住所などを検査し、マスク結果で置き換えます。
+// プロフィールには審査キューがないため、審査待ち判定は拒否として扱う
+func (s *ProfileServiceImpl) filterProfileText(fields ...*string) error {
+	review, err := applyContentFilter(s.contentFilter, fields...)
+	if err != nil {
+		return err
+	}
+	if review {
+		return errors.NewAPIError(errors.ErrContentRejected, "business profile requires review")
+	}
+	return nil
+}
+
+// filterPhone は電話番号を事業者名と同じフィルタで検査します。
+// 電話番号の欄は電話番号を載せるためのものなので、電話番号のマスクは反映せず、拒否・審査待ちの判定だけを使う
+func (s *ProfileServiceImpl) filterPhone(phone string) error {
+	checked := phone
+	return s.filterProfileText(&checked)
+}
+
+// GetProfile はプロフィールを取得します。
+func (s *ProfileServiceImpl) GetProfile(ctx context.Context, userID string) (interface{}, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return profileResponse(member), nil
+}
+
+// UpdateProfile はプロフィールのうち指定されたフィールドを更新します。
+func (s *ProfileServiceImpl) UpdateProfile(ctx context.Context, userID string, payload interface{}) (interface{}, error) {
+	req, ok := payload.(*domain.UpdateBusinessProfileRequest)
+	if !ok || req == nil {
+		return nil, errors.NewAPIError(errors.ErrInvalidInput, "invalid profile payload")
+	}
+
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &domain.BusinessProfileUpdate{
+		BusinessName:     strings.TrimSpace(req.Name),
+		KanaBusinessName: strings.TrimSpace(req.KanaBusinessName),
+		ZipCode:          strings.TrimSpace(req.ZipCode),
+		Address:          strings.TrimSpace(req.Address),
+		Phone:            strings.TrimSpace(req.Phone),
+	}
+	if err := s.filterProfileText(&update.BusinessName, &update.KanaBusinessName, &update.Address); err != nil {
+		return nil, err
+	}
+	if err := s.filterPhone(update.Phone); err != nil {
+		return nil, err
+	}
+
+	if err := s.memberRepo.UpdateProfile(ctx, member.BusinessID, update); err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to update business profile: %v", err))
+	}
+
+	return s.GetProfile(ctx, userID)
+}
+
+// UpdateName は事業者名を更新します。
+func (s *ProfileServiceImpl) UpdateName(ctx context.Context, userID, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 50 {
+		return errors.NewAPIError(errors.ErrValidationFailed, "business name must be between 1 and 50 characters")
+	}
+
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.filterProfileText(&name); err != nil {
+		return err
+	}
+
+	if err := s.memberRepo.UpdateName(ctx, member.BusinessID, name); err != nil {
+		return errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to update business name: %v", err))
+	}
+	return nil
+}
+
+// UpdateAddress は住所（と郵便番号）を更新します。
+func (s *ProfileServiceImpl) UpdateAddress(ctx context.Context, userID, address, zipCode string) error {
+	address, zipCode = strings.TrimSpace(address), strings.TrimSpace(zipCode)
+	if address == "" {
+		return errors.NewAPIError(errors.ErrValidationFailed, "address is required")
+	}
+
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.filterProfileText(&address); err != nil {
+		return err
+	}
+
+	update := &domain.BusinessProfileUpdate{Address: address, ZipCode: zipCode}
+	if err := s.memberRepo.UpdateProfile(ctx, member.BusinessID, update); err != nil {
+		return errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to update business address: %v", err))
+	}
+	return nil
+}
+
+// UpdatePhone は電話番号を更新します。
+func (s *ProfileServiceImpl) UpdatePhone(ctx context.Context, userID, phone string) error {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return errors.NewAPIError(errors.ErrValidationFailed, "phone is required")
+	}
+
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.filterPhone(phone); err != nil {
+		return err
+	}
+
+	update := &domain.BusinessProfileUpdate{Phone: phone}
+	if err := s.memberRepo.UpdateProfile(ctx, member.BusinessID, update); err != nil {
+		return errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to update business phone: %v", err))
+	}
+	return nil
+}
+
+// UpdateIcon はアイコン画像（PNG または JPEG、5MB以下）を更新します。
+func (s *ProfileServiceImpl) UpdateIcon(ctx context.Context, userID string, icon []byte) (string, error) {
+	if len(icon) == 0 {
+		return "", errors.NewAPIError(errors.ErrInvalidInput, "icon data is required")
+	}
+	if len(icon) > 5*1024*1024 {
+		return "", errors.NewAPIError(errors.ErrImageTooLarge, "image size must not exceed 5MB")
+	}
+	iconURL := imageDataURI(icon)
+	if iconURL == "" {
+		return "", errors.NewAPIError(errors.ErrInvalidImage, "icon must be PNG or JPEG")
+	}
+
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.memberRepo.UpdateIcon(ctx, member.BusinessID, icon); err != nil {
+		return "", errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to update business icon: %v", err))
+	}
+	return iconURL, nil
+}
+
+// GetDashboardStats は投稿の合計と、直近7日間に投稿した投稿の日別のリアクション数・閲覧数を返します。
+func (s *ProfileServiceImpl) GetDashboardStats(ctx context.Context, userID string) (interface{}, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.statsRepo.PostStatsByUser(ctx, member.UserID)
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, fmt.Sprintf("failed to get post stats: %v", err))
+	}
+	posts, ok := result.([]domain.PostStats)
+	if !ok {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, "invalid post stats type")
+	}
+
+	stats := &domain.DashboardStatsResponse{TotalPosts: int32(len(posts))}
+	for _, post := range posts {
+		stats.TotalReactions += post.NumReaction
+		stats.TotalViews += post.NumView
+	}
+	if len(posts) > 0 {
+		stats.AverageReactions = stats.TotalReactions / int32(len(posts))
+	}
+
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := 6; i >= 0; i-- {
+		dayStart := today.AddDate(0, 0, -i)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		day := domain.DailyStats{Date: dayStart.Format("01/02")}
+		for _, post := range posts {
+			if !post.PostDate.Before(dayStart) && post.PostDate.Before(dayEnd) {
+				day.Reactions += post.NumReaction
+				day.Views += post.NumView
+			}
+		}
+		stats.WeeklyData = append(stats.WeeklyData, day)
+	}
+
+	return stats, nil
+}
+
+// GetPostCount は削除されていない投稿の数を返します。
+func (s *ProfileServiceImpl) GetPostCount(ctx context.Context, userID string) (int32, error) {
+	stats, err := s.GetDashboardStats(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return stats.(*domain.DashboardStatsResponse).TotalPosts, nil
+}
+
+// profileResponse はプロフィールのレスポンスを生成します。
+func profileResponse(member *models.BusinessMember) *domain.BusinessProfileResponse {
+	return &domain.BusinessProfileResponse{
+		BusinessID:       member.BusinessID,
+		BusinessName:     member.BusinessName,
+		KanaBusinessName: member.KanaBusinessName,
+		ZipCode:          member.ZipCode,
+		Address:          member.Address,
+		Phone:            member.Phone,
+		RegistDate:       member.RegistDate,
+		ProfileImage:     imageDataURI(member.ProfileImage),
+		UserID:           member.UserID,
+		PlaceID:          member.PlaceID,
+	}
+}
+
+// imageDataURI は PNG・JPEG の画像を data URI にします（それ以外は空文字）。
+func imageDataURI(image []byte) string {
+	if len(image) == 0 {
+		return ""
+	}
+	contentType := http.DetectContentType(image)
+	if contentType != "image/png" && contentType != "image/jpeg" {
+		return ""
+	}
+	return fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(image))
+}
@@ -0,0 +1,137 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"kojan-map/business/internal/domain"
+	"kojan-map/business/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProfileServiceImpl_Profile tests reading and updating the signed-in business's own profile.
+func TestProfileServiceImpl_Profile(t *testing.T) {
+	fixtures := NewTestFixtures()
+	fixtures.SetupBusinessMember(1, "user-123", "Old Name", nil)
+	svc := fixtures.ProfileService
+	ctx := context.Background()
+
+	result, err := svc.UpdateProfile(ctx, "user-123", &domain.UpdateBusinessProfileRequest{
+		Name:    "New Name",
+		Address: "Tokyo",
+	})
+	require.NoError(t, err)
+	profile := result.(*domain.BusinessProfileResponse)
+	assert.Equal(t, "New Name", profile.BusinessName)
+	assert.Equal(t, "Tokyo", profile.Address)
+
+	require.NoError(t, svc.UpdateAddress(ctx, "user-123", "Osaka", "5300001"))
+	require.NoError(t, svc.UpdatePhone(ctx, "user-123", "0600000000"))
+	require.NoError(t, svc.UpdateName(ctx, "user-123", "Renamed"))
+
+	member := fixtures.MemberRepo.Members[1]
+	assert.Equal(t, "Renamed", member.BusinessName)
+	assert.Equal(t, "Osaka", member.Address)
+	assert.Equal(t, "5300001", member.ZipCode)
+	assert.Equal(t, "0600000000", member.Phone)
+
+	_, err = svc.GetProfile(ctx, "unknown-user")
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errors.ErrNotFound, apiErr.ErrorCode)
+
+	err = svc.UpdatePhone(ctx, "user-123", " ")
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errors.ErrValidationFailed, apiErr.ErrorCode)
+}
+
+// TestProfileServiceImpl_ContentFilter tests that the business name is filtered and review results are rejected.
+func TestProfileServiceImpl_ContentFilter(t *testing.T) {
+	fixtures := NewTestFixtures()
+	fixtures.SetupBusinessMember(1, "user-123", "Old Name", nil)
+	svc := fixtures.ProfileService
+	svc.SetContentFilter(stubContentFilter{review: true})
+
+	err := svc.UpdateName(context.Background(), "user-123", "Suspicious Name")
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errors.ErrContentRejected, apiErr.ErrorCode)
+	assert.Equal(t, "Old Name", fixtures.MemberRepo.Members[1].BusinessName)
+}
+
+// TestProfileServiceImpl_GetDashboardStats tests the totals and the daily breakdown of the last 7 days.
+func TestProfileServiceImpl_GetDashboardStats(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+	fixtures := NewTestFixtures()
+	fixtures.SetupBusinessMember(1, "user-123", "Shop", nil)
+	fixtures.StatsRepo.PostStats = []domain.PostStats{
+		{PostDate: now.Add(-time.Hour), NumReaction: 4, NumView: 10},
+		{PostDate: now.AddDate(0, 0, -2), NumReaction: 2, NumView: 5},
+		{PostDate: now.AddDate(0, 0, -30), NumReaction: 3, NumView: 7},
+	}
+	svc := fixtures.ProfileService
+	svc.now = func() time.Time { return now }
+
+	result, err := svc.GetDashboardStats(context.Background(), "user-123")
+	require.NoError(t, err)
+	stats := result.(*domain.DashboardStatsResponse)
+	assert.Equal(t, int32(3), stats.TotalPosts)
+	assert.Equal(t, int32(9), stats.TotalReactions)
+	assert.Equal(t, int32(22), stats.TotalViews)
+	assert.Equal(t, int32(3), stats.AverageReactions)
+	require.Len(t, stats.WeeklyData, 7)
+	assert.Equal(t, domain.DailyStats{Date: "05/08", Reactions: 2, Views: 5}, stats.WeeklyData[4])
+	assert.Equal(t, domain.DailyStats{Date: "05/10", Reactions: 4, Views: 10}, stats.WeeklyData[6])
+
+	count, err := svc.GetPostCount(context.Background(), "user-123")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), count)
+}
+
+// ngWordFilter rejects fields containing "NG" and masks digits, like the phone-number rule of the real filter.
+type ngWordFilter struct{}
+
+func (ngWordFilter) Apply(fields ...*string) (bool, error) {
+	for _, f := range fields {
+		if strings.Contains(*f, "NG") {
+			return false, assert.AnError
+		}
+		*f = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return '*'
+			}
+			return r
+		}, *f)
+	}
+	return false, nil
+}
+
+// TestProfileServiceImpl_ContentFilter_AddressAndPhone tests that address and phone edits go through the same filter as the name.
+func TestProfileServiceImpl_ContentFilter_AddressAndPhone(t *testing.T) {
+	fixtures := NewTestFixtures()
+	member := fixtures.SetupBusinessMember(1, "user-123", "Shop", nil)
+	member.Address, member.Phone = "Tokyo", "0300000000"
+	svc := fixtures.ProfileService
+	svc.SetContentFilter(ngWordFilter{})
+	ctx := context.Background()
+
+	var apiErr *errors.APIError
+	require.ErrorAs(t, svc.UpdateAddress(ctx, "user-123", "NG street", ""), &apiErr)
+	assert.Equal(t, errors.ErrContentRejected, apiErr.ErrorCode)
+	require.ErrorAs(t, svc.UpdatePhone(ctx, "user-123", "NG-0000"), &apiErr)
+	assert.Equal(t, errors.ErrContentRejected, apiErr.ErrorCode)
+	_, err := svc.UpdateProfile(ctx, "user-123", &domain.UpdateBusinessProfileRequest{Address: "NG street"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "Tokyo", member.Address)
+	assert.Equal(t, "0300000000", member.Phone)
+
+	// 住所はマスク結果で保存し、電話番号の欄の電話番号はマスクしない
+	require.NoError(t, svc.UpdateAddress(ctx, "user-123", "Room 101", ""))
+	require.NoError(t, svc.UpdatePhone(ctx, "user-123", "0611112222"))
+	assert.Equal(t, "Room ***", member.Address)
+	assert.Equal(t, "0611112222", member.Phone)
+}
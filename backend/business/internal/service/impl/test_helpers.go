@@ -1,12 +1,23 @@
 package impl
 
 import (
-	"kojan-map/business/internal/domain"
+	"context"
 	"kojan-map/business/internal/repository/mock"
 	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/notification"
 	"time"
 )
 
+// NopNotifier は通知を送信せずに破棄します（テスト用）
+type NopNotifier struct{}
+
+// Send は何もしません
+func (NopNotifier) Send(context.Context, notification.Message) error { return nil }
+
+// SendMFACode は何もしません
+func (NopNotifier) SendMFACode(string, string) error { return nil }
+
 // TestFixtures は各テストで使用する共通フィクスチャを管理します
 type TestFixtures struct {
 	AuthRepo       *mock.MockAuthRepo
@@ -19,6 +30,7 @@ type TestFixtures struct {
 	PaymentRepo    *mock.MockPaymentRepo
 	AuthService    *AuthServiceImpl
 	MemberService  *MemberServiceImpl
+	ProfileService *ProfileServiceImpl
 	PostService    *PostServiceImpl
 	StatsService   *StatsServiceImpl
 	BlockService   *BlockServiceImpl
@@ -38,6 +50,9 @@ func NewTestFixtures() *TestFixtures {
 	contactRepo := mock.NewMockContactRepo()
 	paymentRepo := mock.NewMockPaymentRepo()
 
+	authService := NewAuthServiceImpl(authRepo, jwt.NewTokenManagerWithSecret("test-secret"))
+	authService.SetNotificationService(NopNotifier{})
+
 	return &TestFixtures{
 		AuthRepo:       authRepo,
 		MemberRepo:     memberRepo,
@@ -47,8 +62,9 @@ func NewTestFixtures() *TestFixtures {
 		ReportRepo:     reportRepo,
 		ContactRepo:    contactRepo,
 		PaymentRepo:    paymentRepo,
-		AuthService:    NewAuthServiceImpl(authRepo, jwt.NewTokenManager()),
+		AuthService:    authService,
 		MemberService:  NewMemberServiceImpl(memberRepo, authRepo),
+		ProfileService: NewProfileServiceImpl(memberRepo, statsRepo),
 		PostService:    NewPostServiceImpl(postRepo),
 		StatsService:   NewStatsServiceImpl(statsRepo),
 		BlockService:   NewBlockServiceImpl(blockRepo),
@@ -59,11 +75,11 @@ func NewTestFixtures() *TestFixtures {
 }
 
 // SetupUser はモックリポジトリにテストユーザーを登録します
-func (f *TestFixtures) SetupUser(googleID, gmail string) *domain.User {
-	user := &domain.User{
-		ID:    googleID,
-		Gmail: gmail,
-		Role:  "business",
+func (f *TestFixtures) SetupUser(googleID, gmail string) *models.User {
+	user := &models.User{
+		GoogleID: googleID,
+		Gmail:    gmail,
+		Role:     models.RoleBusiness,
 	}
 	f.AuthRepo.Users[googleID] = user
 	return user
@@ -75,9 +91,9 @@ func (f *TestFixtures) SetupBusinessMember(
 	googleID string,
 	businessName string,
 	profileImage []byte,
-) *domain.BusinessMember {
-	member := &domain.BusinessMember{
-		ID:           int32(businessID),
+) *models.BusinessMember {
+	member := &models.BusinessMember{
+		BusinessID:   businessID,
 		BusinessName: businessName,
 		UserID:       googleID,
 		ProfileImage: profileImage,
@@ -94,9 +110,9 @@ func (f *TestFixtures) SetupPost(
 	title string,
 	description string,
 	viewCount int32,
-) *domain.Post {
-	post := &domain.Post{
-		ID:       int32(postID),
+) *models.Post {
+	post := &models.Post{
+		PostID:   postID,
 		UserID:   authorID,
 		Title:    title,
 		Text:     description,
@@ -152,7 +168,7 @@ func validateEmail(email string) bool {
 
 // GenerateTestJWT はテスト用の有効なJWTトークンを生成します
 func GenerateTestJWT(userID, gmail, role string) string {
-	tokenManager := jwt.NewTokenManager()
+	tokenManager := jwt.NewTokenManagerWithSecret("test-secret")
 	token, _ := tokenManager.GenerateToken(userID, gmail, role)
 	return token
 }
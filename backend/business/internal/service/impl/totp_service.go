@@ -0,0 +1,320 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"kojan-map/business/internal/domain"
+	"kojan-map/business/internal/repository"
+	"kojan-map/business/pkg/errors"
+	"kojan-map/business/pkg/kvstore"
+	"kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/secretbox"
+	"kojan-map/business/pkg/totp"
+)
+
+const (
+	// totpIssuer は認証アプリに表示されるサービス名です
+	totpIssuer = "Kojan Map"
+	// recoveryCodeCount は一度に発行するリカバリーコードの数です
+	recoveryCodeCount = 10
+	// recoveryCodeAlphabet は読み間違えやすい文字（0/o, 1/l/i）を除いた文字集合です
+	recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+	// totpMaxFailedAttempts はロックするまでに許容する連続した失敗の回数です
+	totpMaxFailedAttempts = 5
+	// totpLockoutDuration は最初の失敗からロックを解除するまでの時間です
+	totpLockoutDuration = 15 * time.Minute
+)
+
+// TOTPServiceImpl はTOTPServiceインターフェースを実装します。
+// シークレットは secretbox で暗号化して保存し、リカバリーコードはハッシュのみ保存します。
+// IP単位のレート制限とは別に、ユーザー単位で連続した失敗を数えてロックします。
+type TOTPServiceImpl struct {
+	totpRepo repository.TOTPRepo
+	box      *secretbox.Box
+	failures kvstore.Store
+	now      func() time.Time
+}
+
+// NewTOTPServiceImpl は新しい認証アプリサービスを作成します。
+func NewTOTPServiceImpl(totpRepo repository.TOTPRepo, box *secretbox.Box) *TOTPServiceImpl {
+	return &TOTPServiceImpl{
+		totpRepo: totpRepo,
+		box:      box,
+		failures: kvstore.NewMemoryStore(kvstore.DefaultCleanupInterval),
+		now:      time.Now,
+	}
+}
+
+// UseStore は失敗回数の保存先を共有ストアに切り替えます。
+// 複数レプリカでも同じユーザーの失敗回数を数えるため、起動時に呼び出します。
+func (s *TOTPServiceImpl) UseStore(store kvstore.Store) {
+	s.failures.Stop()
+	s.failures = store
+}
+
+// BeginEnrollment はシークレットを生成し、otpauth URI とQRコードを返します。
+// 最初のコードで ConfirmEnrollment を呼ぶまで、ログインには使用されません。
+func (s *TOTPServiceImpl) BeginEnrollment(ctx context.Context, userID, gmail string) (interface{}, error) {
+	if userID == "" || gmail == "" {
+		return nil, errors.NewAPIError(errors.ErrInvalidInput, "userId and gmail are required")
+	}
+
+	cred, err := s.getCredential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cred != nil && cred.Confirmed() {
+		return nil, errors.NewAPIError(errors.ErrAlreadyExists, "authenticator app is already enrolled")
+	}
+
+	enrollment, err := totp.Generate(totpIssuer, gmail)
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+
+	secretEnc, err := s.box.Seal(enrollment.Secret)
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, "failed to encrypt TOTP secret")
+	}
+	if err := s.totpRepo.SavePending(ctx, userID, secretEnc); err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+
+	return &domain.TOTPEnrollResponse{
+		Secret:     enrollment.Secret,
+		OtpauthURI: enrollment.URI,
+		QRCode:     enrollment.QRCodeDataURL(),
+	}, nil
+}
+
+// ConfirmEnrollment は最初のコードで登録を確定し、リカバリーコードを発行します。
+func (s *TOTPServiceImpl) ConfirmEnrollment(ctx context.Context, userID, code string) (interface{}, error) {
+	cred, err := s.getCredential(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		return nil, errors.NewAPIError(errors.ErrNotFound, "authenticator app enrollment not started")
+	}
+	if cred.Confirmed() {
+		return nil, errors.NewAPIError(errors.ErrAlreadyExists, "authenticator app is already enrolled")
+	}
+
+	step, ok, err := s.validateTOTP(cred, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.NewAPIError(errors.ErrMissingMFA, "invalid authenticator code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+	if err := s.totpRepo.Confirm(ctx, userID, step, hashes); err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes は現在のコードを確認してリカバリーコードを再発行します。
+// 以前のリカバリーコードはすべて無効になります。
+func (s *TOTPServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID, code string) (interface{}, error) {
+	if err := s.requireCode(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+	if err := s.totpRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable は現在のコードを確認して認証アプリの登録を解除します。
+func (s *TOTPServiceImpl) Disable(ctx context.Context, userID, code string) error {
+	if err := s.requireCode(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.totpRepo.Delete(ctx, userID); err != nil {
+		return errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+	return nil
+}
+
+// Verify はTOTPまたはリカバリーコードを検証します。
+// 登録が完了していない場合は false を返します。
+// 連続して totpMaxFailedAttempts 回失敗したユーザーは、totpLockoutDuration の間ロックされます。
+func (s *TOTPServiceImpl) Verify(ctx context.Context, userID, code string) (bool, error) {
+	if userID == "" || code == "" {
+		return false, nil
+	}
+
+	cred, err := s.getCredential(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if cred == nil || !cred.Confirmed() {
+		return false, nil
+	}
+
+	if s.failedAttempts(ctx, userID) >= totpMaxFailedAttempts {
+		return false, errors.NewAPIError(errors.ErrTooManyRequests, "too many invalid authenticator codes, please try again later")
+	}
+
+	ok, err := s.verifyCode(ctx, userID, cred, code)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		_ = s.failures.Delete(ctx, failureKey(userID))
+	} else {
+		s.recordFailure(ctx, userID)
+	}
+	return ok, nil
+}
+
+// verifyCode はTOTP（使用済みのステップを除く）、次にリカバリーコードの順に照合します。
+func (s *TOTPServiceImpl) verifyCode(ctx context.Context, userID string, cred *models.TOTPCredential, code string) (bool, error) {
+	step, ok, err := s.validateTOTP(cred, code)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		// 同時に送られた同じコードの再利用を防ぐため、条件付きで更新する
+		marked, err := s.totpRepo.MarkStepUsed(ctx, userID, step)
+		if err != nil {
+			return false, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+		}
+		return marked, nil
+	}
+
+	used, err := s.totpRepo.ConsumeRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return false, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+	return used, nil
+}
+
+// failedAttempts はロック期間内の連続した失敗の回数を返します。
+func (s *TOTPServiceImpl) failedAttempts(ctx context.Context, userID string) int {
+	value, err := s.failures.Get(ctx, failureKey(userID))
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(string(value))
+	return n
+}
+
+// recordFailure は失敗の回数を増やします。最初の失敗から totpLockoutDuration で期限切れになります。
+// ストアは実時刻で有効期限を判定するため、s.now ではなく time.Now を使います。
+func (s *TOTPServiceImpl) recordFailure(ctx context.Context, userID string) {
+	key := failureKey(userID)
+	counted := false
+	_ = s.failures.Update(ctx, key, func(value []byte, found bool) []byte {
+		if !found {
+			return nil
+		}
+		n, _ := strconv.Atoi(string(value))
+		counted = true
+		return []byte(strconv.Itoa(n + 1))
+	})
+	if !counted {
+		_ = s.failures.Set(ctx, key, []byte("1"), time.Now().Add(totpLockoutDuration))
+	}
+}
+
+func failureKey(userID string) string {
+	return "totp-failures:" + userID
+}
+
+// requireCode は登録済みであることを確認し、コードを検証します。
+func (s *TOTPServiceImpl) requireCode(ctx context.Context, userID, code string) error {
+	cred, err := s.getCredential(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if cred == nil || !cred.Confirmed() {
+		return errors.NewAPIError(errors.ErrNotFound, "authenticator app is not enrolled")
+	}
+
+	ok, err := s.Verify(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.NewAPIError(errors.ErrMissingMFA, "invalid authenticator code")
+	}
+	return nil
+}
+
+func (s *TOTPServiceImpl) getCredential(ctx context.Context, userID string) (*models.TOTPCredential, error) {
+	result, err := s.totpRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, errors.NewAPIError(errors.ErrOperationFailed, err.Error())
+	}
+	if result == nil {
+		return nil, nil
+	}
+	cred, ok := result.(*models.TOTPCredential)
+	if !ok || cred == nil {
+		return nil, nil
+	}
+	return cred, nil
+}
+
+// validateTOTP はシークレットを復号してコードを検証し、一致したステップを返します。
+func (s *TOTPServiceImpl) validateTOTP(cred *models.TOTPCredential, code string) (int64, bool, error) {
+	secret, err := s.box.Open(cred.SecretEnc)
+	if err != nil {
+		return 0, false, errors.NewAPIError(errors.ErrOperationFailed, "failed to decrypt TOTP secret")
+	}
+	step, ok := totp.Validate(secret, code, s.now(), cred.LastUsedStep)
+	return step, ok, nil
+}
+
+// generateRecoveryCodes は "xxxx-xxxx" 形式のリカバリーコードとそのハッシュを生成します。
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		var b strings.Builder
+		for j := 0; j < 8; j++ {
+			if j == 4 {
+				b.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+			}
+			b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+		}
+		code := b.String()
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode は大文字小文字・区切り文字を正規化してハッシュ化します。
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,218 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"kojan-map/business/internal/domain"
+	"kojan-map/business/internal/repository/mock"
+	"kojan-map/business/pkg/errors"
+	"kojan-map/business/pkg/secretbox"
+	"kojan-map/business/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTOTPService creates a TOTP service backed by the mock repo with a fixed clock.
+func newTestTOTPService(t *testing.T, now time.Time) (*TOTPServiceImpl, *mock.MockTOTPRepo) {
+	t.Helper()
+	box, err := secretbox.New(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+
+	repo := mock.NewMockTOTPRepo()
+	svc := NewTOTPServiceImpl(repo, box)
+	svc.now = func() time.Time { return now }
+	return svc, repo
+}
+
+// enrollTOTP runs the enrollment flow and returns the secret and recovery codes.
+func enrollTOTP(t *testing.T, svc *TOTPServiceImpl, userID string, at time.Time) (string, []string) {
+	t.Helper()
+	result, err := svc.BeginEnrollment(context.Background(), userID, userID+"@example.com")
+	require.NoError(t, err)
+	enroll := result.(*domain.TOTPEnrollResponse)
+
+	code, err := totp.Code(enroll.Secret, at)
+	require.NoError(t, err)
+	result, err = svc.ConfirmEnrollment(context.Background(), userID, code)
+	require.NoError(t, err)
+	return enroll.Secret, result.(*domain.RecoveryCodesResponse).RecoveryCodes
+}
+
+// TestTOTPServiceImpl_Enrollment tests that the secret is encrypted at rest and
+// enrollment is only confirmed by a valid code.
+func TestTOTPServiceImpl_Enrollment(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	svc, repo := newTestTOTPService(t, now)
+	ctx := context.Background()
+
+	result, err := svc.BeginEnrollment(ctx, "biz-1", "biz@example.com")
+	require.NoError(t, err)
+	enroll, ok := result.(*domain.TOTPEnrollResponse)
+	require.True(t, ok)
+	assert.Contains(t, enroll.OtpauthURI, "otpauth://totp/")
+	assert.Contains(t, enroll.QRCode, "data:image/png;base64,")
+
+	stored := repo.Credentials["biz-1"]
+	require.NotNil(t, stored)
+	assert.NotContains(t, stored.SecretEnc, enroll.Secret, "secret must be encrypted at rest")
+	assert.Nil(t, stored.ConfirmedAt)
+
+	// Unconfirmed enrollment cannot be used to log in
+	code, err := totp.Code(enroll.Secret, now)
+	require.NoError(t, err)
+	ok, err = svc.Verify(ctx, "biz-1", code)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = svc.ConfirmEnrollment(ctx, "biz-1", "000000")
+	assert.Error(t, err)
+
+	result, err = svc.ConfirmEnrollment(ctx, "biz-1", code)
+	require.NoError(t, err)
+	codes := result.(*domain.RecoveryCodesResponse).RecoveryCodes
+	assert.Len(t, codes, recoveryCodeCount)
+	for _, c := range codes {
+		assert.Regexp(t, `^[a-z0-9]{4}-[a-z0-9]{4}$`, c)
+	}
+
+	// Already enrolled
+	_, err = svc.BeginEnrollment(ctx, "biz-1", "biz@example.com")
+	assert.Error(t, err)
+}
+
+// TestTOTPServiceImpl_Verify tests the ±1 step window and replay protection.
+func TestTOTPServiceImpl_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	svc, _ := newTestTOTPService(t, now)
+	ctx := context.Background()
+	secret, _ := enrollTOTP(t, svc, "biz-1", now.Add(-totp.Period*time.Second))
+
+	// The code used for confirmation has been consumed; the current one is accepted once
+	code, err := totp.Code(secret, now)
+	require.NoError(t, err)
+	ok, err := svc.Verify(ctx, "biz-1", code)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = svc.Verify(ctx, "biz-1", code)
+	require.NoError(t, err)
+	assert.False(t, ok, "replayed code must be rejected")
+
+	// The previous step was already used for confirmation
+	prev, err := totp.Code(secret, now.Add(-totp.Period*time.Second))
+	require.NoError(t, err)
+	ok, err = svc.Verify(ctx, "biz-1", prev)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The next step is within the window
+	next, err := totp.Code(secret, now.Add(totp.Period*time.Second))
+	require.NoError(t, err)
+	ok, err = svc.Verify(ctx, "biz-1", next)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Two steps ahead is outside the window
+	far, err := totp.Code(secret, now.Add(2*totp.Period*time.Second))
+	require.NoError(t, err)
+	ok, err = svc.Verify(ctx, "biz-1", far)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Users without enrollment are never verified
+	ok, err = svc.Verify(ctx, "unknown", code)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// TestTOTPServiceImpl_RecoveryCodes tests that recovery codes work once and are replaced on regeneration.
+func TestTOTPServiceImpl_RecoveryCodes(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	svc, _ := newTestTOTPService(t, now)
+	ctx := context.Background()
+	secret, codes := enrollTOTP(t, svc, "biz-1", now.Add(-totp.Period*time.Second))
+
+	// Case and separators are normalized
+	ok, err := svc.Verify(ctx, "biz-1", " "+codes[0][:4]+codes[0][5:]+" ")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = svc.Verify(ctx, "biz-1", codes[0])
+	require.NoError(t, err)
+	assert.False(t, ok, "recovery code must be single use")
+
+	// Regeneration requires a valid code and invalidates the old set
+	_, err = svc.RegenerateRecoveryCodes(ctx, "biz-1", "000000")
+	assert.Error(t, err)
+
+	current, err := totp.Code(secret, now)
+	require.NoError(t, err)
+	result, err := svc.RegenerateRecoveryCodes(ctx, "biz-1", current)
+	require.NoError(t, err)
+	fresh := result.(*domain.RecoveryCodesResponse).RecoveryCodes
+
+	ok, err = svc.Verify(ctx, "biz-1", codes[1])
+	require.NoError(t, err)
+	assert.False(t, ok, "old recovery codes must be invalidated")
+
+	ok, err = svc.Verify(ctx, "biz-1", fresh[0])
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+// TestTOTPServiceImpl_Disable tests that disabling requires a valid code and removes the enrollment.
+func TestTOTPServiceImpl_Disable(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	svc, repo := newTestTOTPService(t, now)
+	ctx := context.Background()
+	_, codes := enrollTOTP(t, svc, "biz-1", now.Add(-totp.Period*time.Second))
+
+	assert.Error(t, svc.Disable(ctx, "biz-1", "000000"))
+	require.NoError(t, svc.Disable(ctx, "biz-1", codes[0]))
+
+	assert.Empty(t, repo.Credentials)
+	assert.Empty(t, repo.RecoveryCodes)
+	assert.Error(t, svc.Disable(ctx, "biz-1", codes[1]))
+}
+
+// TestTOTPServiceImpl_Lockout tests that repeated invalid codes lock the user
+// even when they come from different IPs, and that a valid code resets the count.
+func TestTOTPServiceImpl_Lockout(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	svc, _ := newTestTOTPService(t, now)
+	ctx := context.Background()
+	_, codes := enrollTOTP(t, svc, "biz-1", now.Add(-totp.Period*time.Second))
+	enrollTOTP(t, svc, "biz-2", now.Add(-totp.Period*time.Second))
+
+	// A valid code before the limit resets the count
+	for i := 0; i < totpMaxFailedAttempts-1; i++ {
+		ok, err := svc.Verify(ctx, "biz-1", "000000")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+	ok, err := svc.Verify(ctx, "biz-1", codes[0])
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	for i := 0; i < totpMaxFailedAttempts; i++ {
+		ok, err := svc.Verify(ctx, "biz-1", "000000")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	// Locked: even a valid recovery code is refused
+	ok, err = svc.Verify(ctx, "biz-1", codes[1])
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, errors.ErrTooManyRequests, apiErr.ErrorCode)
+	assert.False(t, ok)
+
+	// Other users are not affected
+	ok, err = svc.Verify(ctx, "biz-2", "000000")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
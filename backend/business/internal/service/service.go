@@ -2,6 +2,19 @@ package service
 
 import "context"
 
+// ContentFilter はNGワード・個人情報フィルタです。
+// 各フィールドを検査してマスク結果で置き換え、拒否の場合はエラーを返します。
+// review は審査待ちにすべき内容が含まれていたかを表します。
+type ContentFilter interface {
+	Apply(fields ...*string) (review bool, err error)
+}
+
+// AccountChecker はアカウントが利用停止・利用禁止・削除されていないかを確認します。
+// 利用できない場合は *accountstatus.Restriction を返します。
+type AccountChecker interface {
+	CheckAccount(ctx context.Context, userID string) error
+}
+
 // AuthService は認証フローを処理します。
 type AuthService interface {
 	GoogleAuth(ctx context.Context, payload interface{}) (interface{}, error)
@@ -18,6 +31,20 @@ type MemberService interface {
 	AnonymizeMember(ctx context.Context, businessID int32) error
 }
 
+// ProfileService はログイン中の事業者自身のプロフィールとダッシュボードを処理します。
+// 事業者はトークンのユーザーID（Google ID）から特定します。
+type ProfileService interface {
+	GetProfile(ctx context.Context, userID string) (interface{}, error)
+	UpdateProfile(ctx context.Context, userID string, payload interface{}) (interface{}, error)
+	UpdateName(ctx context.Context, userID, name string) error
+	UpdateAddress(ctx context.Context, userID, address, zipCode string) error
+	UpdatePhone(ctx context.Context, userID, phone string) error
+	// UpdateIcon はアイコン画像を保存し、表示用の data URI を返します
+	UpdateIcon(ctx context.Context, userID string, icon []byte) (string, error)
+	GetDashboardStats(ctx context.Context, userID string) (interface{}, error)
+	GetPostCount(ctx context.Context, userID string) (int32, error)
+}
+
 // StatsService はダッシュボードの統計情報を処理します。
 type StatsService interface {
 	GetTotalPosts(ctx context.Context, businessID int32) (interface{}, error)
@@ -56,3 +83,13 @@ type ContactService interface {
 type PaymentService interface {
 	CreateRedirect(ctx context.Context, businessID int32) (string, error)
 }
+
+// TOTPService は認証アプリ（TOTP）による二要素認証を処理します。
+type TOTPService interface {
+	BeginEnrollment(ctx context.Context, userID, gmail string) (interface{}, error)
+	ConfirmEnrollment(ctx context.Context, userID, code string) (interface{}, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) (interface{}, error)
+	Disable(ctx context.Context, userID, code string) error
+	// Verify はログイン時のコード（TOTPまたはリカバリーコード）を検証します
+	Verify(ctx context.Context, userID, code string) (bool, error)
+}
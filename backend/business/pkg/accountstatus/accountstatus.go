@@ -0,0 +1,170 @@
+// Package accountstatus はアカウントが利用できる状態かを判定します。
+//
+// 管理者による期限付きの利用停止（suspension）・無期限の利用禁止（ban）と、退会・削除（user.deletedAt）を確認し、
+// ユーザー・管理者・ビジネスの認証ミドルウェアとログイン処理で同じ判定を使います。
+// 利用停止・利用禁止は user_sanction テーブルの行で、解除（liftedAt）または期限（endsAt）を過ぎると無効になります。
+package accountstatus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kind は利用できない理由の種類です
+type Kind string
+
+const (
+	KindDeleted   Kind = "deleted"   // 退会・管理者による削除
+	KindSuspended Kind = "suspended" // 期限付きの利用停止
+	KindBanned    Kind = "banned"    // 無期限の利用禁止
+)
+
+// user_sanction テーブルの kind のうち、ログインを制限するもの
+const (
+	SanctionSuspension = "suspension"
+	SanctionBan        = "ban"
+)
+
+// Restriction はアカウントが利用できない理由です。error として返します
+type Restriction struct {
+	Kind       Kind
+	Reason     string
+	Until      *time.Time // 利用停止の終了日時（利用禁止・削除の場合は nil）
+	SanctionID int64      // 元になった制裁のID（削除の場合は 0）
+}
+
+// Error は利用者に表示するメッセージを返します（利用停止の場合は終了日時を含む）
+func (r *Restriction) Error() string {
+	switch r.Kind {
+	case KindSuspended:
+		if r.Until != nil {
+			return fmt.Sprintf("account is suspended until %s", r.Until.Format(time.RFC3339))
+		}
+		return "account is suspended"
+	case KindBanned:
+		return "account is banned"
+	default:
+		return "account has been deleted"
+	}
+}
+
+// Code はAPIレスポンスのエラーコードを返します
+func (r *Restriction) Code() string {
+	switch r.Kind {
+	case KindSuspended:
+		return "ACCOUNT_SUSPENDED"
+	case KindBanned:
+		return "ACCOUNT_BANNED"
+	default:
+		return "ACCOUNT_DELETED"
+	}
+}
+
+// Response は 403 レスポンスの本文です（error・code・reason・suspendedUntil）
+func (r *Restriction) Response() map[string]interface{} {
+	body := map[string]interface{}{
+		"error": r.Error(),
+		"code":  r.Code(),
+	}
+	if r.Reason != "" {
+		body["reason"] = r.Reason
+	}
+	if r.Until != nil {
+		body["suspendedUntil"] = r.Until.Format(time.RFC3339)
+	}
+	return body
+}
+
+// sanction は判定に使う user_sanction の列です
+type sanction struct {
+	ID     int64      `gorm:"column:sanctionId"`
+	Kind   string     `gorm:"column:kind"`
+	Reason string     `gorm:"column:reason"`
+	EndsAt *time.Time `gorm:"column:endsAt"`
+}
+
+// Checker はアカウントの状態をデータベースから判定します
+type Checker struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// NewChecker は新しい Checker を作成します
+func NewChecker(db *gorm.DB) *Checker {
+	return &Checker{db: db, now: time.Now}
+}
+
+// Check はアカウントが利用できない場合に *Restriction を返します（利用できる場合は nil）
+// ユーザーが存在しない場合は制限なしとして扱います（存在確認は呼び出し側で行う）
+func (c *Checker) Check(ctx context.Context, userID string) (*Restriction, error) {
+	var user struct {
+		DeletedAt *time.Time `gorm:"column:deletedAt"`
+	}
+	result := c.db.WithContext(ctx).Table("user").Select("deletedAt").Where("googleId = ?", userID).Limit(1).Find(&user)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get account status: %w", result.Error)
+	}
+	if result.RowsAffected > 0 && user.DeletedAt != nil {
+		return &Restriction{Kind: KindDeleted}, nil
+	}
+
+	now := c.now()
+	var sanctions []sanction
+	if err := c.db.WithContext(ctx).Table("user_sanction").
+		Select("sanctionId, kind, reason, endsAt").
+		Where("userId = ? AND kind IN ? AND liftedAt IS NULL AND startsAt <= ?", userID, []string{SanctionSuspension, SanctionBan}, now).
+		Where("endsAt IS NULL OR endsAt > ?", now).
+		Find(&sanctions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get account sanctions: %w", err)
+	}
+	return strongest(sanctions), nil
+}
+
+// CheckAccount は Check と同じ判定を error で返します（利用できない場合は *Restriction）
+// 認証ミドルウェアの AccountChecker として使います
+func (c *Checker) CheckAccount(ctx context.Context, userID string) error {
+	restriction, err := c.Check(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if restriction != nil {
+		return restriction
+	}
+	return nil
+}
+
+// strongest は有効な制裁のうち最も重いものを返します
+// 利用禁止を優先し、利用停止が複数ある場合は終了日時が最も遅いものを返します
+func strongest(sanctions []sanction) *Restriction {
+	var best *sanction
+	for i := range sanctions {
+		s := &sanctions[i]
+		if best == nil || heavier(s, best) {
+			best = s
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	r := &Restriction{Kind: KindSuspended, Reason: best.Reason, Until: best.EndsAt, SanctionID: best.ID}
+	if best.Kind == SanctionBan || best.EndsAt == nil {
+		r.Kind = KindBanned
+		r.Until = nil
+	}
+	return r
+}
+
+// heavier は a が b より重い制裁かを返します（終了日時のないものは無期限として扱う）
+func heavier(a, b *sanction) bool {
+	if (a.Kind == SanctionBan) != (b.Kind == SanctionBan) {
+		return a.Kind == SanctionBan
+	}
+	if a.EndsAt == nil || b.EndsAt == nil {
+		return a.EndsAt == nil && b.EndsAt != nil
+	}
+	return a.EndsAt.After(*b.EndsAt)
+}
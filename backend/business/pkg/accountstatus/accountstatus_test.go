@@ -0,0 +1,62 @@
+package accountstatus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrongest(t *testing.T) {
+	soon := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	later := soon.AddDate(0, 0, 7)
+
+	t.Run("制裁がない場合は nil", func(t *testing.T) {
+		assert.Nil(t, strongest(nil))
+	})
+
+	t.Run("利用停止は終了日時が最も遅いもの", func(t *testing.T) {
+		r := strongest([]sanction{
+			{ID: 1, Kind: SanctionSuspension, Reason: "spam", EndsAt: &soon},
+			{ID: 2, Kind: SanctionSuspension, Reason: "abuse", EndsAt: &later},
+		})
+		require.NotNil(t, r)
+		assert.Equal(t, KindSuspended, r.Kind)
+		assert.Equal(t, int64(2), r.SanctionID)
+		assert.Equal(t, "abuse", r.Reason)
+		assert.Equal(t, later, *r.Until)
+	})
+
+	t.Run("利用禁止を優先", func(t *testing.T) {
+		r := strongest([]sanction{
+			{ID: 1, Kind: SanctionSuspension, EndsAt: &later},
+			{ID: 2, Kind: SanctionBan, Reason: "fraud"},
+		})
+		require.NotNil(t, r)
+		assert.Equal(t, KindBanned, r.Kind)
+		assert.Equal(t, int64(2), r.SanctionID)
+		assert.Nil(t, r.Until)
+	})
+}
+
+func TestRestriction_Response(t *testing.T) {
+	until := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	r := &Restriction{Kind: KindSuspended, Reason: "spam", Until: &until}
+
+	var err error = r
+	var target *Restriction
+	require.True(t, errors.As(err, &target))
+	assert.Equal(t, "account is suspended until 2026-01-10T09:00:00Z", err.Error())
+
+	body := r.Response()
+	assert.Equal(t, "ACCOUNT_SUSPENDED", body["code"])
+	assert.Equal(t, "spam", body["reason"])
+	assert.Equal(t, "2026-01-10T09:00:00Z", body["suspendedUntil"])
+
+	banned := (&Restriction{Kind: KindBanned}).Response()
+	assert.Equal(t, "ACCOUNT_BANNED", banned["code"])
+	assert.NotContains(t, banned, "suspendedUntil")
+	assert.NotContains(t, banned, "reason")
+}
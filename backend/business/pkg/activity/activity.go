@@ -0,0 +1,80 @@
+// Package activity は認証済みのリクエストから、ユーザーが利用した日を記録します。
+//
+// user_daily_activity テーブルにユーザー・日付ごとに1行だけ保存し、管理画面の分析（DAU）で集計します。
+// 同じ日の2回目以降のリクエストはプロセス内で記録済みとして扱うため、リクエストごとの書き込みは発生しません。
+package activity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DailyActivity は user_daily_activity テーブルの行です（ユーザーが利用した日）
+type DailyActivity struct {
+	UserID string    `gorm:"column:userId;primaryKey"`
+	Date   time.Time `gorm:"column:date;primaryKey"`
+}
+
+// TableName はテーブル名を返します
+func (DailyActivity) TableName() string {
+	return "user_daily_activity"
+}
+
+// Day は t の日付（t のタイムゾーンの0時）を返します
+func Day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Recorder はユーザーが利用した日を記録します
+type Recorder struct {
+	db  *gorm.DB
+	now func() time.Time
+
+	mu   sync.Mutex
+	day  time.Time
+	seen map[string]struct{} // day に記録済みのユーザー
+}
+
+// NewRecorder は新しい Recorder を作成します
+func NewRecorder(db *gorm.DB) *Recorder {
+	return &Recorder{db: db, now: time.Now, seen: make(map[string]struct{})}
+}
+
+// Touch はユーザーが今日利用したことを記録します
+// 複数のインスタンスから同じ日に記録しても1行になります
+func (r *Recorder) Touch(ctx context.Context, userID string) error {
+	if userID == "" {
+		return nil
+	}
+	day := Day(r.now())
+
+	r.mu.Lock()
+	if !day.Equal(r.day) {
+		// 日付が変わったら前日の記録済みユーザーを忘れる
+		r.day = day
+		r.seen = make(map[string]struct{})
+	}
+	_, ok := r.seen[userID]
+	r.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&DailyActivity{UserID: userID, Date: day}).Error; err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+
+	r.mu.Lock()
+	if day.Equal(r.day) {
+		r.seen[userID] = struct{}{}
+	}
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,47 @@
+package activity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB はSQLを実行せずに INSERT の回数を数えるDBを返します
+func dryRunDB(t *testing.T, inserts *int) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:1)/test?parseTime=true", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("count_inserts", func(*gorm.DB) { *inserts++ }))
+	return db
+}
+
+func TestDay(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, jst), Day(time.Date(2026, 3, 1, 23, 59, 0, 0, jst)))
+}
+
+func TestRecorder_TouchOncePerDay(t *testing.T) {
+	var inserts int
+	r := NewRecorder(dryRunDB(t, &inserts))
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, r.Touch(ctx, "user-1"))
+	require.NoError(t, r.Touch(ctx, "user-1"))
+	require.NoError(t, r.Touch(ctx, "user-2"))
+	require.NoError(t, r.Touch(ctx, ""))
+	assert.Equal(t, 2, inserts)
+
+	// 日付が変わると再度記録する
+	now = now.AddDate(0, 0, 1)
+	require.NoError(t, r.Touch(ctx, "user-1"))
+	assert.Equal(t, 3, inserts)
+	assert.Len(t, r.seen, 1)
+}
@@ -15,6 +15,7 @@ const (
 	ErrUnauthorized       ErrorCode = "UNAUTHORIZED"
 	ErrForbidden          ErrorCode = "FORBIDDEN"
 	ErrTokenExpired       ErrorCode = "TOKEN_EXPIRED"
+	ErrAccountRestricted  ErrorCode = "ACCOUNT_RESTRICTED" // 利用停止中・利用禁止・削除済みのアカウント
 
 	// リソース関連
 	ErrNotFound      ErrorCode = "NOT_FOUND"
@@ -27,10 +28,12 @@ const (
 	ErrInvalidEmail     ErrorCode = "INVALID_EMAIL"
 	ErrInvalidImage     ErrorCode = "INVALID_IMAGE"
 	ErrImageTooLarge    ErrorCode = "IMAGE_TOO_LARGE"
+	ErrContentRejected  ErrorCode = "CONTENT_REJECTED"
 
 	// ビジネスロジック関連
 	ErrOperationFailed      ErrorCode = "OPERATION_FAILED"
 	ErrExternalServiceError ErrorCode = "EXTERNAL_SERVICE_ERROR"
+	ErrTooManyRequests      ErrorCode = "TOO_MANY_REQUESTS"
 
 	// その他
 	ErrInternalServer ErrorCode = "INTERNAL_SERVER_ERROR"
@@ -62,16 +65,18 @@ func getStatusCode(code ErrorCode) int {
 	switch code {
 	case ErrInvalidCredentials, ErrMissingMFA, ErrUnauthorized, ErrTokenExpired:
 		return http.StatusUnauthorized
-	case ErrForbidden:
+	case ErrForbidden, ErrAccountRestricted:
 		return http.StatusForbidden
 	case ErrNotFound:
 		return http.StatusNotFound
 	case ErrAlreadyExists, ErrDuplicate:
 		return http.StatusConflict
-	case ErrInvalidInput, ErrValidationFailed, ErrInvalidEmail, ErrInvalidImage, ErrImageTooLarge:
+	case ErrInvalidInput, ErrValidationFailed, ErrInvalidEmail, ErrInvalidImage, ErrImageTooLarge, ErrContentRejected:
 		return http.StatusBadRequest
 	case ErrOperationFailed, ErrExternalServiceError:
 		return http.StatusBadGateway
+	case ErrTooManyRequests:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1,79 +1,68 @@
 package jwt
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
 	"sync"
 	"time"
+
+	"kojan-map/business/pkg/kvstore"
 )
 
 // TokenBlacklist は失効したJWTトークンを管理します
+// トークン本体ではなくハッシュをキーとし、トークンの有効期限まで保持します
 type TokenBlacklist struct {
-	mu       sync.RWMutex
-	tokens   map[string]time.Time // token -> 有効期限
-	ticker   *time.Ticker
-	stopChan chan struct{}
-	stopOnce sync.Once
+	store     kvstore.Store
+	ownsStore bool
+	stopOnce  sync.Once
 }
 
-// NewTokenBlacklist はトークンブラックリストマネージャーを生成します
+// NewTokenBlacklist はメモリ上のトークンブラックリストを生成します（テスト・単体起動用）
 func NewTokenBlacklist() *TokenBlacklist {
-	tb := &TokenBlacklist{
-		tokens:   make(map[string]time.Time),
-		ticker:   time.NewTicker(1 * time.Hour), // 1時間ごとにクリーンアップ
-		stopChan: make(chan struct{}),
+	return &TokenBlacklist{
+		store:     kvstore.NewMemoryStore(1 * time.Hour), // 1時間ごとにクリーンアップ
+		ownsStore: true,
 	}
+}
 
-	// クリーンアップゴルーチンを起動
-	go tb.cleanupExpiredTokens()
-
-	return tb
+// NewTokenBlacklistWithStore は共有ストアを使うトークンブラックリストを生成します
+// ストアの停止は呼び出し側で行います
+func NewTokenBlacklistWithStore(store kvstore.Store) *TokenBlacklist {
+	return &TokenBlacklist{store: store}
 }
 
 // RevokeToken はトークンをブラックリストに追加します
 // expiresAt: トークンが期限切れになる時刻（期限後はブラックリストに保持する必要なし）
-func (tb *TokenBlacklist) RevokeToken(token string, expiresAt time.Time) {
-	tb.mu.Lock()
-	defer tb.mu.Unlock()
-	tb.tokens[token] = expiresAt
+func (tb *TokenBlacklist) RevokeToken(token string, expiresAt time.Time) error {
+	return tb.store.Set(context.Background(), blacklistKey(token), []byte{1}, expiresAt)
 }
 
 // IsRevoked はトークンが失効されているか確認します
+// ストアに問い合わせられない場合は、失効済みのトークンを通さないよう失効扱いにします
 func (tb *TokenBlacklist) IsRevoked(token string) bool {
-	tb.mu.RLock()
-	defer tb.mu.RUnlock()
-
-	expiresAt, exists := tb.tokens[token]
-	if !exists {
+	_, err := tb.store.Get(context.Background(), blacklistKey(token))
+	if errors.Is(err, kvstore.ErrNotFound) {
 		return false
 	}
-
-	// 期限内のみ失効扱い（期限切れは false。後続のクリーンアップで削除）
-	return time.Now().Before(expiresAt)
-}
-
-// cleanupExpiredTokens periodically removes expired tokens from blacklist
-func (tb *TokenBlacklist) cleanupExpiredTokens() {
-	for {
-		select {
-		case <-tb.ticker.C:
-			tb.mu.Lock()
-			now := time.Now()
-			for token, expiresAt := range tb.tokens {
-				if expiresAt.Before(now) {
-					delete(tb.tokens, token)
-				}
-			}
-			tb.mu.Unlock()
-
-		case <-tb.stopChan:
-			tb.ticker.Stop()
-			return
-		}
+	if err != nil {
+		log.Printf("token blacklist lookup failed: %v", err)
 	}
+	return true
 }
 
-// Stop stops the cleanup goroutine
+// Stop stops the cleanup goroutine of the store owned by the blacklist
 func (tb *TokenBlacklist) Stop() {
 	tb.stopOnce.Do(func() {
-		close(tb.stopChan)
+		if tb.ownsStore {
+			tb.store.Stop()
+		}
 	})
 }
+
+func blacklistKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "jwt-revoked:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyConfig はトークンマネージャーの署名鍵・検証鍵の設定です
+// メインサーバーとビジネスバックエンドの単体起動で同じ設定を使います
+type KeyConfig struct {
+	// Secret はHS256の共有シークレットです（JWT_SECRET_KEY）
+	// SigningKeyFile が未設定の場合はこのシークレットで署名します
+	Secret string
+	// SigningKeyFile は現在の署名鍵（RSA / Ed25519 の秘密鍵、PEM）です（JWT_SIGNING_KEY_FILE）
+	SigningKeyFile string
+	// VerifyKeyFiles はローテーション前の旧鍵です。検証のみに使用します（JWT_VERIFY_KEY_FILES）
+	VerifyKeyFiles []string
+	// AcceptHS256 は SigningKeyFile で署名する場合も、Secret で署名されたトークンを受け付けます（JWT_ACCEPT_HS256）
+	// HS256 から非対称鍵へ移行する間だけ有効にします
+	AcceptHS256 bool
+}
+
+// KeyConfigFromEnv は環境変数から鍵の設定を読み込みます
+func KeyConfigFromEnv() KeyConfig {
+	cfg := KeyConfig{
+		Secret:         os.Getenv("JWT_SECRET_KEY"),
+		SigningKeyFile: os.Getenv("JWT_SIGNING_KEY_FILE"),
+		AcceptHS256:    os.Getenv("JWT_ACCEPT_HS256") == "true",
+	}
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFY_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			cfg.VerifyKeyFiles = append(cfg.VerifyKeyFiles, path)
+		}
+	}
+	return cfg
+}
+
+// NewTokenManagerFromConfig は鍵の設定からトークンマネージャーを生成します
+//
+// SigningKeyFile が設定されている場合はその鍵（RS256 / EdDSA）で署名し、
+// VerifyKeyFiles の旧鍵は検証のみに使用します。Secret（HS256）は AcceptHS256 の場合のみ検証に使用します。
+// 鍵をローテーションする場合は、新しい鍵を SigningKeyFile に、
+// それまでの鍵を VerifyKeyFiles に指定して再起動します。
+func NewTokenManagerFromConfig(cfg KeyConfig) (*TokenManager, error) {
+	var active *Key
+	others := []*Key{}
+	if cfg.SigningKeyFile == "" {
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("JWT_SECRET_KEY or JWT_SIGNING_KEY_FILE must be set")
+		}
+		key, err := NewHMACKey("", []byte(cfg.Secret))
+		if err != nil {
+			return nil, err
+		}
+		active = key
+	} else {
+		key, err := loadKeyFile(cfg.SigningKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		active = key
+		if cfg.AcceptHS256 {
+			if cfg.Secret == "" {
+				return nil, fmt.Errorf("JWT_ACCEPT_HS256 requires JWT_SECRET_KEY")
+			}
+			hmacKey, err := NewHMACKey("", []byte(cfg.Secret))
+			if err != nil {
+				return nil, err
+			}
+			others = append(others, hmacKey)
+		}
+	}
+	for _, path := range cfg.VerifyKeyFiles {
+		key, err := loadKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		others = append(others, key)
+	}
+
+	keys, err := NewKeySet(active, others...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenManagerWithKeySet(keys), nil
+}
+
+func loadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+	key, err := ParseKeyPEM("", data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+	return key, nil
+}
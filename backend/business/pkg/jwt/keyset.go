@@ -0,0 +1,304 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+// 対応する署名アルゴリズム
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key は kid で識別される署名鍵です
+// 公開鍵のみを持つ鍵は検証専用です（ローテーション後の旧鍵など）
+type Key struct {
+	ID        string
+	Algorithm string
+
+	secret     []byte        // HS256
+	privateKey crypto.Signer // RS256 / EdDSA（検証専用の場合はnil）
+	publicKey  crypto.PublicKey
+}
+
+// NewHMACKey はHS256の鍵を生成します。kid が空の場合はシークレットから導出します
+func NewHMACKey(kid string, secret []byte) (*Key, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("HMAC secret must not be empty")
+	}
+	if kid == "" {
+		sum := sha256.Sum256(append([]byte("kid:"), secret...))
+		kid = "hs-" + hex.EncodeToString(sum[:4])
+	}
+	return &Key{ID: kid, Algorithm: AlgHS256, secret: secret}, nil
+}
+
+// NewRSAKey はRS256の鍵を生成します。kid が空の場合は公開鍵から導出します
+func NewRSAKey(kid string, privateKey *rsa.PrivateKey) (*Key, error) {
+	if privateKey.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA key must be at least 2048 bits")
+	}
+	return newAsymmetricKey(kid, AlgRS256, privateKey, privateKey.Public())
+}
+
+// NewEd25519Key はEdDSA（Ed25519）の鍵を生成します。kid が空の場合は公開鍵から導出します
+func NewEd25519Key(kid string, privateKey ed25519.PrivateKey) (*Key, error) {
+	return newAsymmetricKey(kid, AlgEdDSA, privateKey, privateKey.Public())
+}
+
+// ParseKeyPEM はPEM形式の鍵を読み込みます
+// 秘密鍵（PKCS#8 / PKCS#1）なら署名用、公開鍵（PKIX）なら検証専用の鍵になります
+func ParseKeyPEM(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		switch k := parsed.(type) {
+		case *rsa.PrivateKey:
+			return NewRSAKey(kid, k)
+		case ed25519.PrivateKey:
+			return NewEd25519Key(kid, k)
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T", parsed)
+		}
+	case "RSA PRIVATE KEY":
+		k, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		return NewRSAKey(kid, k)
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		switch k := parsed.(type) {
+		case *rsa.PublicKey:
+			return newAsymmetricKey(kid, AlgRS256, nil, k)
+		case ed25519.PublicKey:
+			return newAsymmetricKey(kid, AlgEdDSA, nil, k)
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", parsed)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+func newAsymmetricKey(kid, alg string, privateKey crypto.Signer, publicKey crypto.PublicKey) (*Key, error) {
+	if kid == "" {
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal public key: %w", err)
+		}
+		sum := sha256.Sum256(der)
+		kid = base64.RawURLEncoding.EncodeToString(sum[:12])
+	}
+	return &Key{ID: kid, Algorithm: alg, privateKey: privateKey, publicKey: publicKey}, nil
+}
+
+// CanSign は署名に使える鍵かどうかを返します
+func (k *Key) CanSign() bool {
+	return k.secret != nil || k.privateKey != nil
+}
+
+func (k *Key) signingMethod() jwtlib.SigningMethod {
+	switch k.Algorithm {
+	case AlgRS256:
+		return jwtlib.SigningMethodRS256
+	case AlgEdDSA:
+		return jwtlib.SigningMethodEdDSA
+	default:
+		return jwtlib.SigningMethodHS256
+	}
+}
+
+func (k *Key) signingKey() interface{} {
+	if k.Algorithm == AlgHS256 {
+		return k.secret
+	}
+	return k.privateKey
+}
+
+func (k *Key) verificationKey() interface{} {
+	if k.Algorithm == AlgHS256 {
+		return k.secret
+	}
+	return k.publicKey
+}
+
+// KeySet は署名に使う現在の鍵と、検証のみに使う旧鍵をまとめて保持します
+// 鍵を入れ替えても旧鍵で署名済みのトークンは有効期限まで検証できるため、
+// ローテーションで全員がログアウトされることはありません
+type KeySet struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string]*Key
+}
+
+// NewKeySet は active を署名鍵とするキーセットを生成します。others は検証専用として扱います
+func NewKeySet(active *Key, others ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key)}
+	for _, k := range others {
+		if err := ks.Add(k); err != nil {
+			return nil, err
+		}
+	}
+	if err := ks.Rotate(active); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Add は検証用の鍵を追加します
+func (ks *KeySet) Add(k *Key) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if existing, ok := ks.keys[k.ID]; ok && existing != k {
+		return fmt.Errorf("duplicate key id %q", k.ID)
+	}
+	ks.keys[k.ID] = k
+	return nil
+}
+
+// Rotate は新しい署名鍵に切り替えます。それまでの署名鍵は検証用として残ります
+func (ks *KeySet) Rotate(k *Key) error {
+	if !k.CanSign() {
+		return fmt.Errorf("key %q cannot be used for signing", k.ID)
+	}
+	if err := ks.Add(k); err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	ks.active = k.ID
+	ks.mu.Unlock()
+	return nil
+}
+
+// Retire は検証用の鍵を削除します（その鍵で署名されたトークンは無効になります）
+func (ks *KeySet) Retire(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if kid == ks.active {
+		return fmt.Errorf("cannot retire the active key %q", kid)
+	}
+	delete(ks.keys, kid)
+	return nil
+}
+
+// Active は現在の署名鍵を返します
+func (ks *KeySet) Active() *Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.keys[ks.active]
+}
+
+// sign はクレームを現在の鍵で署名し、ヘッダーに kid を設定します
+func (ks *KeySet) sign(claims jwtlib.Claims) (string, error) {
+	k := ks.Active()
+	token := jwtlib.NewWithClaims(k.signingMethod(), claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.signingKey())
+}
+
+// keyFunc は kid とアルゴリズムから検証鍵を選びます
+// kid のない旧形式のトークンは、同じアルゴリズムのすべての鍵で検証を試みます
+func (ks *KeySet) keyFunc(token *jwtlib.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+		k, found := ks.keys[kid]
+		if !found {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		// アルゴリズムの取り違え（HS256で公開鍵を使う等）を防ぐ
+		if k.Algorithm != alg {
+			return nil, fmt.Errorf("unexpected signing method: %v", alg)
+		}
+		return k.verificationKey(), nil
+	}
+
+	var set jwtlib.VerificationKeySet
+	for _, k := range ks.keys {
+		if k.Algorithm == alg {
+			set.Keys = append(set.Keys, k.verificationKey())
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("unexpected signing method: %v", alg)
+	}
+	return set, nil
+}
+
+// JWK はJSON Web Key（RFC 7517）の公開鍵表現です
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`   // RSA
+	E         string `json:"e,omitempty"`   // RSA
+	Curve     string `json:"crv,omitempty"` // OKP
+	X         string `json:"x,omitempty"`   // OKP
+}
+
+// JWKS はJWKの集合です
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS は公開鍵の一覧を返します。HS256の鍵は共有秘密のため含めません
+func (ks *KeySet) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	out := JWKS{Keys: []JWK{}}
+	for _, k := range ks.keys {
+		switch pub := k.publicKey.(type) {
+		case *rsa.PublicKey:
+			out.Keys = append(out.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     k.ID,
+				Algorithm: k.Algorithm,
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			out.Keys = append(out.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     k.ID,
+				Algorithm: k.Algorithm,
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	sort.Slice(out.Keys, func(i, j int) bool { return out.Keys[i].KeyID < out.Keys[j].KeyID })
+	return out
+}
@@ -2,65 +2,147 @@ package jwt
 
 import (
 	"fmt"
-	"os"
 	"time"
 
+	"kojan-map/business/pkg/kvstore"
+
 	jwtlib "github.com/golang-jwt/jwt/v5"
 )
 
 // TokenManager はJWTトークンの生成と検証を処理します
+// ユーザー・管理者・ビジネスの各ルートで同じ TokenManager を共有します
 type TokenManager struct {
-	secret    string
+	keys      *KeySet
 	blacklist *TokenBlacklist
 }
 
-// NewTokenManager はトークンマネージャーを生成します
-func NewTokenManager() *TokenManager {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		// 本番環境ではJWT_SECRETが未設定の場合は起動を中止
-		if os.Getenv("GO_ENV") == "production" {
-			panic("JWT_SECRET environment variable is required in production")
-		}
-		secret = "dev-secret-key-please-change-in-production"
-		// 警告: 開発環境でデフォルトシークレットを使用中
+// Issuer は発行するトークンの iss です
+const Issuer = "kojan-map"
+
+// NewTokenManagerWithSecret は指定したシークレット（HS256）でトークンマネージャーを生成します
+func NewTokenManagerWithSecret(secret string) *TokenManager {
+	key, err := NewHMACKey("", []byte(secret))
+	if err != nil {
+		panic("JWT secret must not be empty")
+	}
+	keys, err := NewKeySet(key)
+	if err != nil {
+		panic(err)
 	}
+	return NewTokenManagerWithKeySet(keys)
+}
+
+// NewTokenManagerWithKeySet はキーセットを使うトークンマネージャーを生成します
+func NewTokenManagerWithKeySet(keys *KeySet) *TokenManager {
 	return &TokenManager{
-		secret:    secret,
+		keys:      keys,
 		blacklist: NewTokenBlacklist(),
 	}
 }
 
+// UseRevocationStore は失効したトークンの保存先を共有ストアに切り替えます
+// 再起動後やレプリカ間でもログアウトを有効にするため、起動時に呼び出します
+func (tm *TokenManager) UseRevocationStore(store kvstore.Store) {
+	old := tm.blacklist
+	tm.blacklist = NewTokenBlacklistWithStore(store)
+	old.Stop()
+}
+
+// Keys はキーセットを返します（鍵のローテーションやJWKSの公開に使用）
+func (tm *TokenManager) Keys() *KeySet {
+	return tm.keys
+}
+
 // Claims はJWTカスタムクレームを表します
+// ユーザー・管理者・ビジネスのすべてのトークンで同じ形式を使用します
 type Claims struct {
-	UserID    string `json:"userId"`
+	UserID    string `json:"userId"` // googleId
 	Gmail     string `json:"gmail"`
 	Role      string `json:"role"`
-	TokenType string `json:"tokenType"` // "access"または"refresh"
+	TokenType string `json:"tokenType"`     // "access"または"refresh"
+	SessionID string `json:"sid,omitempty"` // ログインセッション（ユーザー側のみ。失効の確認に使用）
+
+	// 追加認証（ステップアップ）のクレーム（RFC 8176 / OpenID Connect Core）
+	AMR      []string            `json:"amr,omitempty"`       // 使用した認証方式（"mfa", "otp" など）
+	AuthTime *jwtlib.NumericDate `json:"auth_time,omitempty"` // 追加認証を行った時刻
+
+	// 旧形式（ユーザー側）のトークンのクレーム。読み取り専用で、発行はしません
+	LegacyGoogleID string `json:"google_id,omitempty"`
+	LegacyEmail    string `json:"email,omitempty"`
+
 	jwtlib.RegisteredClaims
 }
 
+func newClaims(userID, gmail, role, tokenType string, ttl time.Duration) *Claims {
+	now := time.Now()
+	return &Claims{
+		UserID:    userID,
+		Gmail:     gmail,
+		Role:      role,
+		TokenType: tokenType,
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			Subject:   userID,
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwtlib.NewNumericDate(now),
+			Issuer:    Issuer,
+		},
+	}
+}
+
 // GenerateToken はユーザー用のJWTトークンを生成します（アクセストークン、1時間有効）
 func (tm *TokenManager) GenerateToken(userID, gmail, role string) (string, error) {
+	return tm.GenerateTokenWithTTL(userID, gmail, role, 1*time.Hour)
+}
+
+// GenerateTokenWithTTL は有効期間を指定してアクセストークンを生成します
+func (tm *TokenManager) GenerateTokenWithTTL(userID, gmail, role string, ttl time.Duration) (string, error) {
 	if userID == "" || gmail == "" || role == "" {
 		return "", fmt.Errorf("userId, gmail, and role are required")
 	}
 
-	expirationTime := time.Now().Add(1 * time.Hour) // アクセストークンは1時間
-	claims := &Claims{
-		UserID:    userID,
-		Gmail:     gmail,
-		Role:      role,
-		TokenType: "access",
-		RegisteredClaims: jwtlib.RegisteredClaims{
-			ExpiresAt: jwtlib.NewNumericDate(expirationTime),
-			IssuedAt:  jwtlib.NewNumericDate(time.Now()),
-			Issuer:    "kojan-map-business",
-		},
+	tokenString, err := tm.keys.sign(newClaims(userID, gmail, role, "access", ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// GenerateSessionToken はログインセッションに紐づくアクセストークンを生成します
+// セッションが失効すると、トークンの有効期限内でも認証ミドルウェアで拒否されます
+func (tm *TokenManager) GenerateSessionToken(userID, gmail, role, sessionID string, ttl time.Duration) (string, error) {
+	if userID == "" || gmail == "" || role == "" || sessionID == "" {
+		return "", fmt.Errorf("userId, gmail, role, and sessionId are required")
+	}
+
+	claims := newClaims(userID, gmail, role, "access", ttl)
+	claims.SessionID = sessionID
+	tokenString, err := tm.keys.sign(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// 認証方式（amr）の値
+const (
+	AMRMFA = "mfa" // 多要素認証を行った
+	AMROTP = "otp" // ワンタイムパスワード（メールのコード・認証アプリ）
+)
+
+// GenerateStepUpToken は追加認証を行ったことを示すアクセストークンを生成します
+// amr と auth_time（現在時刻）を付与し、sessionID が指定された場合はセッションにも紐づけます
+func (tm *TokenManager) GenerateStepUpToken(userID, gmail, role, sessionID string, amr []string, ttl time.Duration) (string, error) {
+	if userID == "" || gmail == "" || role == "" || len(amr) == 0 {
+		return "", fmt.Errorf("userId, gmail, role, and amr are required")
 	}
 
-	token := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(tm.secret))
+	claims := newClaims(userID, gmail, role, "access", ttl)
+	claims.SessionID = sessionID
+	claims.AMR = amr
+	claims.AuthTime = claims.IssuedAt
+	tokenString, err := tm.keys.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -68,6 +150,29 @@ func (tm *TokenManager) GenerateToken(userID, gmail, role string) (string, error
 	return tokenString, nil
 }
 
+// HasAMR はトークンに指定した認証方式が含まれているかを返します
+func (c *Claims) HasAMR(method string) bool {
+	for _, m := range c.AMR {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// SteppedUpWithin は now から window 以内に多要素認証を行ったトークンかを返します
+func (c *Claims) SteppedUpWithin(window time.Duration, now time.Time) bool {
+	if c.AuthTime == nil || !c.HasAMR(AMRMFA) {
+		return false
+	}
+	authTime := c.AuthTime.Time
+	// 未来の時刻は改ざん・時計ずれとして扱う（1分の猶予）
+	if authTime.After(now.Add(time.Minute)) {
+		return false
+	}
+	return now.Sub(authTime) <= window
+}
+
 // GenerateTokenPair はアクセストークンとリフレッシュトークンの両方を生成します
 func (tm *TokenManager) GenerateTokenPair(userID, gmail, role string) (accessToken, refreshToken string, err error) {
 	if userID == "" || gmail == "" || role == "" {
@@ -75,39 +180,13 @@ func (tm *TokenManager) GenerateTokenPair(userID, gmail, role string) (accessTok
 	}
 
 	// アクセストークン: 1時間
-	accessExpiration := time.Now().Add(1 * time.Hour)
-	accessClaims := &Claims{
-		UserID:    userID,
-		Gmail:     gmail,
-		Role:      role,
-		TokenType: "access",
-		RegisteredClaims: jwtlib.RegisteredClaims{
-			ExpiresAt: jwtlib.NewNumericDate(accessExpiration),
-			IssuedAt:  jwtlib.NewNumericDate(time.Now()),
-			Issuer:    "kojan-map-business",
-		},
-	}
-
-	accessToken, err = jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, accessClaims).SignedString([]byte(tm.secret))
+	accessToken, err = tm.keys.sign(newClaims(userID, gmail, role, "access", 1*time.Hour))
 	if err != nil {
 		return "", "", fmt.Errorf("failed to sign access token: %w", err)
 	}
 
 	// リフレッシュトークン: 7日間
-	refreshExpiration := time.Now().Add(7 * 24 * time.Hour)
-	refreshClaims := &Claims{
-		UserID:    userID,
-		Gmail:     gmail,
-		Role:      role,
-		TokenType: "refresh",
-		RegisteredClaims: jwtlib.RegisteredClaims{
-			ExpiresAt: jwtlib.NewNumericDate(refreshExpiration),
-			IssuedAt:  jwtlib.NewNumericDate(time.Now()),
-			Issuer:    "kojan-map-business",
-		},
-	}
-
-	refreshToken, err = jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, refreshClaims).SignedString([]byte(tm.secret))
+	refreshToken, err = tm.keys.sign(newClaims(userID, gmail, role, "refresh", 7*24*time.Hour))
 	if err != nil {
 		return "", "", fmt.Errorf("failed to sign refresh token: %w", err)
 	}
@@ -128,12 +207,10 @@ func (tm *TokenManager) VerifyTokenWithType(tokenString, expectedType string) (*
 	}
 
 	claims := &Claims{}
-	token, err := jwtlib.ParseWithClaims(tokenString, claims, func(token *jwtlib.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwtlib.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(tm.secret), nil
-	})
+	token, err := jwtlib.ParseWithClaims(tokenString, claims, tm.keys.keyFunc,
+		jwtlib.WithValidMethods([]string{AlgHS256, AlgRS256, AlgEdDSA}),
+		jwtlib.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -143,6 +220,17 @@ func (tm *TokenManager) VerifyTokenWithType(tokenString, expectedType string) (*
 		return nil, fmt.Errorf("token is invalid")
 	}
 
+	if claims.UserID == "" {
+		claims.UserID = claims.LegacyGoogleID
+	}
+	if claims.Gmail == "" {
+		claims.Gmail = claims.LegacyEmail
+	}
+	if claims.TokenType == "" {
+		// 旧形式のトークンはアクセストークンのみ
+		claims.TokenType = "access"
+	}
+
 	// 指定された場合はトークンタイプを確認
 	if expectedType != "" && claims.TokenType != expectedType {
 		return nil, fmt.Errorf("token type mismatch: expected %s, got %s", expectedType, claims.TokenType)
@@ -159,11 +247,12 @@ func (tm *TokenManager) RevokeToken(tokenString string) error {
 	}
 
 	// 有効期限と共にトークンをブラックリストに追加
+	expiresAt := time.Now().Add(24 * time.Hour) // フォールバック: 有効期限がない場合は合理的な未来時間で失効
 	if claims.ExpiresAt != nil {
-		tm.blacklist.RevokeToken(tokenString, claims.ExpiresAt.Time)
-	} else {
-		// フォールバック: 有効期限がない場合は合理的な未来時間で失効
-		tm.blacklist.RevokeToken(tokenString, time.Now().Add(24*time.Hour))
+		expiresAt = claims.ExpiresAt.Time
+	}
+	if err := tm.blacklist.RevokeToken(tokenString, expiresAt); err != nil {
+		return fmt.Errorf("failed to store revoked token: %w", err)
 	}
 
 	return nil
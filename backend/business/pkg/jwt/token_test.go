@@ -0,0 +1,305 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kojan-map/business/pkg/kvstore"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRSAKey(t *testing.T) *Key {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := NewRSAKey("", priv)
+	require.NoError(t, err)
+	return key
+}
+
+func newTestEd25519Key(t *testing.T) *Key {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := NewEd25519Key("", priv)
+	require.NoError(t, err)
+	return key
+}
+
+func TestTokenManager_SignAndVerify(t *testing.T) {
+	hmacKey, err := NewHMACKey("", []byte("test-secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		key  *Key
+	}{
+		{"HS256", hmacKey},
+		{"RS256", newTestRSAKey(t)},
+		{"EdDSA", newTestEd25519Key(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := NewKeySet(tt.key)
+			require.NoError(t, err)
+			tm := NewTokenManagerWithKeySet(keys)
+			defer tm.Stop()
+
+			token, err := tm.GenerateToken("google-123", "user@example.com", "business")
+			require.NoError(t, err)
+
+			parsed, _, err := jwtlib.NewParser().ParseUnverified(token, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.key.ID, parsed.Header["kid"])
+			assert.Equal(t, tt.key.Algorithm, parsed.Header["alg"])
+
+			claims, err := tm.VerifyToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "google-123", claims.UserID)
+			assert.Equal(t, "google-123", claims.Subject)
+			assert.Equal(t, "user@example.com", claims.Gmail)
+			assert.Equal(t, "business", claims.Role)
+		})
+	}
+}
+
+func TestTokenManager_Rotation(t *testing.T) {
+	oldKey, err := NewHMACKey("", []byte("old-secret"))
+	require.NoError(t, err)
+	keys, err := NewKeySet(oldKey)
+	require.NoError(t, err)
+	tm := NewTokenManagerWithKeySet(keys)
+	defer tm.Stop()
+
+	oldToken, err := tm.GenerateToken("google-123", "user@example.com", "user")
+	require.NoError(t, err)
+
+	newKey := newTestEd25519Key(t)
+	require.NoError(t, keys.Rotate(newKey))
+
+	newToken, err := tm.GenerateToken("google-123", "user@example.com", "user")
+	require.NoError(t, err)
+
+	// ローテーション後も旧鍵のトークンは検証できる
+	_, err = tm.VerifyToken(oldToken)
+	assert.NoError(t, err)
+	_, err = tm.VerifyToken(newToken)
+	assert.NoError(t, err)
+
+	// 旧鍵を削除すると旧トークンは無効になる
+	require.NoError(t, keys.Retire(oldKey.ID))
+	_, err = tm.VerifyToken(oldToken)
+	assert.Error(t, err)
+	assert.Error(t, keys.Retire(newKey.ID), "active key cannot be retired")
+}
+
+func TestTokenManager_RejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey := newTestRSAKey(t)
+	keys, err := NewKeySet(rsaKey)
+	require.NoError(t, err)
+	tm := NewTokenManagerWithKeySet(keys)
+	defer tm.Stop()
+
+	// 公開鍵をHMACシークレットとして使った偽造トークン
+	pubDER, err := x509.MarshalPKIXPublicKey(rsaKey.publicKey)
+	require.NoError(t, err)
+	forged := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, newClaims("attacker", "a@example.com", "admin", "access", time.Hour))
+	forged.Header["kid"] = rsaKey.ID
+	forgedString, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	require.NoError(t, err)
+
+	_, err = tm.VerifyToken(forgedString)
+	assert.Error(t, err)
+}
+
+func TestTokenManager_LegacyTokens(t *testing.T) {
+	tm := NewTokenManagerWithSecret("test-secret")
+	defer tm.Stop()
+
+	// kid のない旧形式（ユーザー側）のトークン
+	legacy := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, jwtlib.MapClaims{
+		"user_id":   "google-123",
+		"google_id": "google-123",
+		"email":     "user@example.com",
+		"role":      "user",
+		"exp":       time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, err := legacy.SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	claims, err := tm.VerifyToken(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "google-123", claims.UserID)
+	assert.Equal(t, "user@example.com", claims.Gmail)
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	hmacKey, err := NewHMACKey("", []byte("test-secret"))
+	require.NoError(t, err)
+	rsaKey := newTestRSAKey(t)
+	edKey := newTestEd25519Key(t)
+
+	keys, err := NewKeySet(edKey, hmacKey, rsaKey)
+	require.NoError(t, err)
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2, "HMAC keys must not be published")
+	for _, k := range jwks.Keys {
+		assert.Equal(t, "sig", k.Use)
+		switch k.KeyID {
+		case rsaKey.ID:
+			assert.Equal(t, "RSA", k.KeyType)
+			assert.Equal(t, "AQAB", k.E)
+		case edKey.ID:
+			assert.Equal(t, "OKP", k.KeyType)
+			assert.Equal(t, "Ed25519", k.Curve)
+		default:
+			t.Fatalf("unexpected key %q", k.KeyID)
+		}
+	}
+}
+
+func TestParseKeyPEM(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+
+	key, err := ParseKeyPEM("ed-1", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.Equal(t, "ed-1", key.ID)
+	assert.Equal(t, AlgEdDSA, key.Algorithm)
+	assert.True(t, key.CanSign())
+
+	pubDER, err := x509.MarshalPKIXPublicKey(priv.Public())
+	require.NoError(t, err)
+	pub, err := ParseKeyPEM("", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	require.NoError(t, err)
+	assert.False(t, pub.CanSign())
+
+	_, err = ParseKeyPEM("", []byte("not a pem"))
+	assert.Error(t, err)
+}
+
+func TestNewTokenManagerFromConfig(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "signing.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	hs256Token, err := NewTokenManagerWithSecret("test-secret").GenerateToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+
+	t.Run("secret only signs with HS256", func(t *testing.T) {
+		tm, err := NewTokenManagerFromConfig(KeyConfig{Secret: "test-secret"})
+		require.NoError(t, err)
+		assert.Equal(t, AlgHS256, tm.Keys().Active().Algorithm)
+		_, err = tm.VerifyToken(hs256Token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("signing key rejects HS256 tokens by default", func(t *testing.T) {
+		tm, err := NewTokenManagerFromConfig(KeyConfig{Secret: "test-secret", SigningKeyFile: keyFile})
+		require.NoError(t, err)
+		assert.Equal(t, AlgEdDSA, tm.Keys().Active().Algorithm)
+		_, err = tm.VerifyToken(hs256Token)
+		assert.Error(t, err)
+	})
+
+	t.Run("signing key accepts HS256 tokens during the transition", func(t *testing.T) {
+		tm, err := NewTokenManagerFromConfig(KeyConfig{Secret: "test-secret", SigningKeyFile: keyFile, AcceptHS256: true})
+		require.NoError(t, err)
+		_, err = tm.VerifyToken(hs256Token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := NewTokenManagerFromConfig(KeyConfig{})
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenManager_RevocationSharedAcrossReplicas(t *testing.T) {
+	store := kvstore.NewMemoryStore(time.Hour)
+	defer store.Stop()
+
+	// 同じ鍵・同じストアを使う2つのレプリカ
+	a := NewTokenManagerWithSecret("test-secret")
+	defer a.Stop()
+	b := NewTokenManagerWithSecret("test-secret")
+	defer b.Stop()
+	a.UseRevocationStore(store)
+	b.UseRevocationStore(store)
+
+	token, err := a.GenerateToken("google-123", "user@example.com", "business")
+	require.NoError(t, err)
+	_, err = b.VerifyToken(token)
+	require.NoError(t, err)
+
+	require.NoError(t, a.RevokeToken(token))
+	_, err = b.VerifyToken(token)
+	assert.ErrorContains(t, err, "revoked")
+
+	// 共有ストアはトークンマネージャーの Stop では停止しない
+	a.Stop()
+	_, err = store.Get(context.Background(), blacklistKey(token))
+	assert.NoError(t, err)
+}
+
+func TestTokenManager_GenerateSessionToken(t *testing.T) {
+	tm := NewTokenManagerWithSecret("test-secret")
+	defer tm.Stop()
+
+	token, err := tm.GenerateSessionToken("google-123", "user@example.com", "user", "session-1", time.Hour)
+	require.NoError(t, err)
+
+	claims, err := tm.VerifyTokenWithType(token, "access")
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+
+	_, err = tm.GenerateSessionToken("google-123", "user@example.com", "user", "", time.Hour)
+	assert.Error(t, err)
+}
+
+func TestTokenManager_GenerateStepUpToken(t *testing.T) {
+	tm := NewTokenManagerWithSecret("test-secret")
+	defer tm.Stop()
+
+	token, err := tm.GenerateStepUpToken("admin-1", "admin@example.com", "admin", "session-1", []string{AMRMFA, AMROTP}, time.Hour)
+	require.NoError(t, err)
+
+	claims, err := tm.VerifyTokenWithType(token, "access")
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.True(t, claims.HasAMR(AMRMFA))
+	require.NotNil(t, claims.AuthTime)
+
+	now := claims.AuthTime.Time
+	assert.True(t, claims.SteppedUpWithin(10*time.Minute, now.Add(5*time.Minute)))
+	assert.False(t, claims.SteppedUpWithin(10*time.Minute, now.Add(11*time.Minute)))
+
+	// 通常のトークンには追加認証のクレームがない
+	plain, err := tm.GenerateToken("admin-1", "admin@example.com", "admin")
+	require.NoError(t, err)
+	plainClaims, err := tm.VerifyTokenWithType(plain, "access")
+	require.NoError(t, err)
+	assert.Nil(t, plainClaims.AuthTime)
+	assert.False(t, plainClaims.SteppedUpWithin(10*time.Minute, time.Now()))
+
+	_, err = tm.GenerateStepUpToken("admin-1", "admin@example.com", "admin", "", nil, time.Hour)
+	assert.Error(t, err)
+}
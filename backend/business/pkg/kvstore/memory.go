@@ -0,0 +1,109 @@
+package kvstore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore はプロセス内のマップに保存するストアです
+// 再起動で内容が失われ、レプリカ間でも共有されないため、テストや単体起動向けです
+type MemoryStore struct {
+	mu       sync.Mutex
+	entries  map[string]memoryEntry
+	now      func() time.Time
+	janitor  *janitor
+	stopOnce sync.Once
+}
+
+// NewMemoryStore はメモリストアを生成し、定期クリーンアップを開始します
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+	s.janitor = startJanitor(cleanupInterval, func() {
+		_ = s.DeleteExpired(context.Background())
+	})
+	return s
+}
+
+// Set は値を保存します
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryEntry{value: append([]byte(nil), value...), expiresAt: expiresAt}
+	return nil
+}
+
+// Get は値を取得します
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.lookup(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), e.value...), nil
+}
+
+// Delete は値を削除します
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Update は値を排他的に読み込み・更新します
+func (s *MemoryStore) Update(_ context.Context, key string, fn UpdateFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, found := s.lookup(key)
+	var current []byte
+	if found {
+		current = append([]byte(nil), e.value...)
+	}
+
+	next := fn(current, found)
+	switch {
+	case next == nil:
+		delete(s.entries, key)
+	case found:
+		s.entries[key] = memoryEntry{value: append([]byte(nil), next...), expiresAt: e.expiresAt}
+	}
+	return nil
+}
+
+// DeleteExpired は有効期限切れの値を削除します
+func (s *MemoryStore) DeleteExpired(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// Stop は定期クリーンアップを停止します
+func (s *MemoryStore) Stop() {
+	s.stopOnce.Do(s.janitor.stop)
+}
+
+// lookup は有効期限内の値を返します（呼び出し側でロックを保持すること）
+func (s *MemoryStore) lookup(key string) (memoryEntry, bool) {
+	e, ok := s.entries[key]
+	if !ok || !s.now().Before(e.expiresAt) {
+		return memoryEntry{}, false
+	}
+	return e, true
+}
@@ -0,0 +1,86 @@
+package kvstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_SetGetDelete(t *testing.T) {
+	s := NewMemoryStore(time.Hour)
+	defer s.Stop()
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Set(ctx, "k", []byte("v1"), time.Now().Add(time.Minute)))
+	v, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), v)
+
+	// 上書き
+	require.NoError(t, s.Set(ctx, "k", []byte("v2"), time.Now().Add(time.Minute)))
+	v, _ = s.Get(ctx, "k")
+	assert.Equal(t, []byte("v2"), v)
+
+	require.NoError(t, s.Delete(ctx, "k"))
+	_, err = s.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	s := NewMemoryStore(time.Hour)
+	defer s.Stop()
+	ctx := context.Background()
+
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.Set(ctx, "k", []byte("v"), now.Add(time.Minute)))
+
+	s.now = func() time.Time { return now.Add(2 * time.Minute) }
+	_, err := s.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.DeleteExpired(ctx))
+	assert.Empty(t, s.entries)
+}
+
+func TestMemoryStore_Update(t *testing.T) {
+	s := NewMemoryStore(time.Hour)
+	defer s.Stop()
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	// 存在しないキーは作成しない
+	require.NoError(t, s.Update(ctx, "k", func(value []byte, found bool) []byte {
+		assert.False(t, found)
+		return []byte("ignored")
+	}))
+	_, err := s.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Set(ctx, "k", []byte("1"), expiresAt))
+	require.NoError(t, s.Update(ctx, "k", func(value []byte, found bool) []byte {
+		assert.True(t, found)
+		assert.Equal(t, []byte("1"), value)
+		return []byte("2")
+	}))
+	v, _ := s.Get(ctx, "k")
+	assert.Equal(t, []byte("2"), v)
+	assert.Equal(t, expiresAt, s.entries["k"].expiresAt)
+
+	// nil を返すと削除
+	require.NoError(t, s.Update(ctx, "k", func([]byte, bool) []byte { return nil }))
+	_, err = s.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryStore_StopIsIdempotent(t *testing.T) {
+	s := NewMemoryStore(time.Millisecond)
+	s.Stop()
+	s.Stop()
+}
@@ -0,0 +1,123 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Entry は kv_store テーブルの行です
+type Entry struct {
+	Key       string    `gorm:"column:key;primaryKey"`
+	Value     []byte    `gorm:"column:value"`
+	ExpiresAt time.Time `gorm:"column:expiresAt"`
+}
+
+// TableName はテーブル名を返します
+func (Entry) TableName() string {
+	return "kv_store"
+}
+
+// MySQLStore はデータベースに保存するストアです
+// 再起動後も内容が残り、同じデータベースを参照するレプリカ間で共有されます
+type MySQLStore struct {
+	db       *gorm.DB
+	now      func() time.Time
+	janitor  *janitor
+	stopOnce sync.Once
+}
+
+// NewMySQLStore はデータベースストアを生成し、定期クリーンアップを開始します
+// テーブルはメインモジュールの migrations で作成されます
+func NewMySQLStore(db *gorm.DB, cleanupInterval time.Duration) *MySQLStore {
+	s := &MySQLStore{db: db, now: time.Now}
+	s.janitor = startJanitor(cleanupInterval, func() {
+		_ = s.DeleteExpired(context.Background())
+	})
+	return s
+}
+
+// Set は値を保存します
+func (s *MySQLStore) Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	entry := Entry{Key: key, Value: value, ExpiresAt: expiresAt}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expiresAt"}),
+	}).Create(&entry).Error
+	if err != nil {
+		return fmt.Errorf("kvstore: failed to set %q: %w", key, err)
+	}
+	return nil
+}
+
+// Get は値を取得します
+func (s *MySQLStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var entry Entry
+	err := s.db.WithContext(ctx).
+		Where("`key` = ? AND expiresAt > ?", key, s.now()).
+		Take(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kvstore: failed to get %q: %w", key, err)
+	}
+	return entry.Value, nil
+}
+
+// Delete は値を削除します
+func (s *MySQLStore) Delete(ctx context.Context, key string) error {
+	if err := s.db.WithContext(ctx).Where("`key` = ?", key).Delete(&Entry{}).Error; err != nil {
+		return fmt.Errorf("kvstore: failed to delete %q: %w", key, err)
+	}
+	return nil
+}
+
+// Update は行ロック（SELECT ... FOR UPDATE）を取得して値を更新します
+func (s *MySQLStore) Update(ctx context.Context, key string, fn UpdateFunc) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entry Entry
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("`key` = ? AND expiresAt > ?", key, s.now()).
+			Take(&entry).Error
+		found := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var current []byte
+		if found {
+			current = entry.Value
+		}
+		next := fn(current, found)
+		switch {
+		case next == nil:
+			return tx.Where("`key` = ?", key).Delete(&Entry{}).Error
+		case found:
+			return tx.Model(&Entry{}).Where("`key` = ?", key).Update("value", next).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("kvstore: failed to update %q: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteExpired は有効期限切れの値を削除します
+func (s *MySQLStore) DeleteExpired(ctx context.Context) error {
+	if err := s.db.WithContext(ctx).Where("expiresAt <= ?", s.now()).Delete(&Entry{}).Error; err != nil {
+		return fmt.Errorf("kvstore: failed to delete expired entries: %w", err)
+	}
+	return nil
+}
+
+// Stop は定期クリーンアップを停止します（データベース接続は閉じません）
+func (s *MySQLStore) Stop() {
+	s.stopOnce.Do(s.janitor.stop)
+}
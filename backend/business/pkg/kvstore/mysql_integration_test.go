@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package kvstore
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupMySQLStore(t *testing.T) *MySQLStore {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "root:root@tcp(localhost:3306)/kojanmap_test?parseTime=true&charset=utf8mb4&loc=Local"
+	}
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err, "データベース接続に失敗")
+	// テーブルはメインモジュールの migrations で作成済みのものを使う
+	db.Exec("TRUNCATE TABLE kv_store")
+
+	s := NewMySQLStore(db, time.Hour)
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestMySQLStore_SetGetUpdate(t *testing.T) {
+	s := setupMySQLStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.Set(ctx, "k", []byte("1"), time.Now().Add(time.Minute)))
+	require.NoError(t, s.Set(ctx, "k", []byte("2"), time.Now().Add(time.Minute)))
+	v, err := s.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("2"), v)
+
+	require.NoError(t, s.Update(ctx, "k", func(value []byte, found bool) []byte {
+		assert.True(t, found)
+		return append(value, '3')
+	}))
+	v, _ = s.Get(ctx, "k")
+	assert.Equal(t, []byte("23"), v)
+
+	require.NoError(t, s.Update(ctx, "k", func([]byte, bool) []byte { return nil }))
+	_, err = s.Get(ctx, "k")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMySQLStore_Expiry(t *testing.T) {
+	s := setupMySQLStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.Set(ctx, "old", []byte("v"), time.Now().Add(-time.Minute)))
+	_, err := s.Get(ctx, "old")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.DeleteExpired(ctx))
+	var count int64
+	s.db.Model(&Entry{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
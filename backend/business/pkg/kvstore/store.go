@@ -0,0 +1,74 @@
+// Package kvstore は有効期限付きのキー・バリューストアです。
+// トークンの失効リスト・MFAセッション・MFAコードなど、再起動や複数レプリカ間で
+// 共有する必要がある一時的な状態の保存先として使用します。
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound はキーが存在しないか有効期限が切れている場合のエラーです
+var ErrNotFound = errors.New("kvstore: key not found")
+
+// UpdateFunc は現在の値を受け取り、新しい値を返します
+// found が false の場合 value は nil です。nil を返すとキーを削除します
+type UpdateFunc func(value []byte, found bool) []byte
+
+// Store は有効期限付きのキー・バリューストアです
+// 有効期限を過ぎた値は存在しないものとして扱われ、定期的に削除されます
+type Store interface {
+	// Set は値を保存します（既存の値は上書きします）
+	Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error
+	// Get は値を取得します。存在しない場合は ErrNotFound を返します
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete は値を削除します（存在しない場合もエラーにしません）
+	Delete(ctx context.Context, key string) error
+	// Update は値を排他的に読み込み・更新します。有効期限は変更しません
+	// 存在しないキーに対して値を返した場合は何もしません
+	Update(ctx context.Context, key string, fn UpdateFunc) error
+	// DeleteExpired は有効期限切れの値を削除します
+	DeleteExpired(ctx context.Context) error
+	// Stop は定期クリーンアップを停止します
+	Stop()
+}
+
+// DefaultCleanupInterval は有効期限切れの値を削除する既定の間隔です
+const DefaultCleanupInterval = time.Minute
+
+// janitor は DeleteExpired を定期的に呼び出します
+type janitor struct {
+	ticker   *time.Ticker
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+func startJanitor(interval time.Duration, cleanup func()) *janitor {
+	if interval <= 0 {
+		interval = DefaultCleanupInterval
+	}
+	j := &janitor{
+		ticker:   time.NewTicker(interval),
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(j.done)
+		for {
+			select {
+			case <-j.ticker.C:
+				cleanup()
+			case <-j.stopChan:
+				j.ticker.Stop()
+				return
+			}
+		}
+	}()
+	return j
+}
+
+func (j *janitor) stop() {
+	close(j.stopChan)
+	<-j.done
+}
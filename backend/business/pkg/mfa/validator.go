@@ -1,19 +1,24 @@
 package mfa
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"sync"
 	"time"
+
+	"kojan-map/business/pkg/kvstore"
 )
 
 // MFAValidator はMFAコード検証を処理します
+// コードは kvstore に保存するため、共有ストアを使えば再起動後やレプリカ間でも検証できます
 type MFAValidator struct {
-	// In production, store MFA codes in Redis or database with TTL
-	// For now, using simple in-memory storage (not thread-safe; for dev only)
-	mu    sync.RWMutex
-	codes map[string]*MFACode
+	store     kvstore.Store
+	ownsStore bool
+	stopOnce  sync.Once
 }
 
 // MFACode はメタデータ付きの生成されたMFAコードを表します
@@ -24,13 +29,23 @@ type MFACode struct {
 	MaxAttempts int
 }
 
-// NewMFAValidator はMFA検証器を生成します
+// codeTTL はMFAコードの有効期間です
+const codeTTL = 10 * time.Minute
+
+// NewMFAValidator はメモリ上に保存するMFA検証器を生成します（テスト・単体起動用）
 func NewMFAValidator() *MFAValidator {
 	return &MFAValidator{
-		codes: make(map[string]*MFACode),
+		store:     kvstore.NewMemoryStore(kvstore.DefaultCleanupInterval),
+		ownsStore: true,
 	}
 }
 
+// NewMFAValidatorWithStore は共有ストアを使うMFA検証器を生成します
+// ストアの停止は呼び出し側で行います
+func NewMFAValidatorWithStore(store kvstore.Store) *MFAValidator {
+	return &MFAValidator{store: store}
+}
+
 // GenerateCode generates a 6-digit MFA code for a user.
 func (m *MFAValidator) GenerateCode(email string) (string, error) {
 	if email == "" {
@@ -44,16 +59,20 @@ func (m *MFAValidator) GenerateCode(email string) (string, error) {
 	}
 	code := fmt.Sprintf("%06d", n.Int64())
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	// Store code with 10-minute expiration
-	m.codes[email] = &MFACode{
+	mfaCode := &MFACode{
 		Code:        code,
-		ExpiresAt:   time.Now().Add(10 * time.Minute),
+		ExpiresAt:   time.Now().Add(codeTTL),
 		Attempts:    0,
 		MaxAttempts: 5,
 	}
+	data, err := json.Marshal(mfaCode)
+	if err != nil {
+		return "", fmt.Errorf("failed to store MFA code: %w", err)
+	}
+	if err := m.store.Set(context.Background(), codeKey(email), data, mfaCode.ExpiresAt); err != nil {
+		return "", fmt.Errorf("failed to store MFA code: %w", err)
+	}
 
 	// In production, send code via SMS or email
 	// For now, just return the code (for testing)
@@ -61,58 +80,78 @@ func (m *MFAValidator) GenerateCode(email string) (string, error) {
 }
 
 // VerifyCode verifies the MFA code provided by the user.
+// 試行回数の更新はストアの排他更新で行うため、同時に検証しても上限を超えません
 func (m *MFAValidator) VerifyCode(email, providedCode string) (bool, error) {
 	if email == "" || providedCode == "" {
 		return false, fmt.Errorf("email and code are required")
 	}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	var valid bool
+	var verifyErr error
+	err := m.store.Update(context.Background(), codeKey(email), func(value []byte, found bool) []byte {
+		var mfaCode MFACode
+		if !found || json.Unmarshal(value, &mfaCode) != nil {
+			verifyErr = fmt.Errorf("invalid MFA code")
+			return nil
+		}
 
-	mfaCode, exists := m.codes[email]
-	if !exists {
-		return false, fmt.Errorf("invalid MFA code")
-	}
+		// Check if code expired
+		if time.Now().After(mfaCode.ExpiresAt) {
+			verifyErr = fmt.Errorf("invalid MFA code")
+			return nil
+		}
 
-	// Check if code expired
-	if time.Now().After(mfaCode.ExpiresAt) {
-		delete(m.codes, email)
-		return false, fmt.Errorf("invalid MFA code")
-	}
+		// Check if max attempts exceeded
+		if mfaCode.Attempts >= mfaCode.MaxAttempts {
+			verifyErr = fmt.Errorf("invalid MFA code: max attempts exceeded")
+			return nil
+		}
 
-	// Check if max attempts exceeded
-	if mfaCode.Attempts >= mfaCode.MaxAttempts {
-		delete(m.codes, email)
-		return false, fmt.Errorf("invalid MFA code: max attempts exceeded")
-	}
+		// Verify code
+		if subtle.ConstantTimeCompare([]byte(mfaCode.Code), []byte(providedCode)) == 1 {
+			valid = true
+			return nil
+		}
 
-	// Verify code
-	if mfaCode.Code == providedCode {
-		delete(m.codes, email)
-		return true, nil
-	}
+		// Increment attempt count on failure
+		mfaCode.Attempts++
 
-	// Increment attempt count on failure
-	mfaCode.Attempts++
+		// If max attempts reached after this failure, invalidate immediately
+		if mfaCode.Attempts >= mfaCode.MaxAttempts {
+			verifyErr = fmt.Errorf("invalid MFA code: max attempts exceeded")
+			return nil
+		}
 
-	// If max attempts reached after this failure, invalidate immediately
-	if mfaCode.Attempts >= mfaCode.MaxAttempts {
-		delete(m.codes, email)
-		return false, fmt.Errorf("invalid MFA code: max attempts exceeded")
+		verifyErr = fmt.Errorf("invalid MFA code")
+		updated, err := json.Marshal(&mfaCode)
+		if err != nil {
+			return nil
+		}
+		return updated
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to verify MFA code: %w", err)
 	}
-
-	return false, fmt.Errorf("invalid MFA code")
+	if verifyErr != nil {
+		return false, verifyErr
+	}
+	return valid, nil
 }
 
 // CleanupExpiredCodes removes expired codes from storage.
 func (m *MFAValidator) CleanupExpiredCodes() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	_ = m.store.DeleteExpired(context.Background())
+}
 
-	now := time.Now()
-	for email, code := range m.codes {
-		if now.After(code.ExpiresAt) {
-			delete(m.codes, email)
+// Stop stops the cleanup goroutine of the store owned by the validator
+func (m *MFAValidator) Stop() {
+	m.stopOnce.Do(func() {
+		if m.ownsStore {
+			m.store.Stop()
 		}
-	}
+	})
+}
+
+func codeKey(email string) string {
+	return "mfa-code:" + email
 }
@@ -1,13 +1,31 @@
 package mfa
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
+	"kojan-map/business/pkg/kvstore"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// storedCode はストアに保存されているコードを返します
+func storedCode(t *testing.T, v *MFAValidator, email string) (*MFACode, bool) {
+	t.Helper()
+	data, err := v.store.Get(context.Background(), codeKey(email))
+	if errors.Is(err, kvstore.ErrNotFound) {
+		return nil, false
+	}
+	require.NoError(t, err)
+	var code MFACode
+	require.NoError(t, json.Unmarshal(data, &code))
+	return &code, true
+}
+
 func TestMFAValidator_GenerateCode(t *testing.T) {
 	v := NewMFAValidator()
 	email := "test@example.com"
@@ -18,9 +36,7 @@ func TestMFAValidator_GenerateCode(t *testing.T) {
 	assert.Regexp(t, "^[0-9]{6}$", code)
 
 	// Verify code is stored
-	v.mu.RLock()
-	stored, exists := v.codes[email]
-	v.mu.RUnlock()
+	stored, exists := storedCode(t, v, email)
 	require.True(t, exists)
 	assert.Equal(t, code, stored.Code)
 	assert.Equal(t, 0, stored.Attempts)
@@ -38,9 +54,7 @@ func TestMFAValidator_VerifyCode(t *testing.T) {
 	assert.False(t, valid)
 
 	// 検証試行回数の増加
-	v.mu.RLock()
-	stored, exists := v.codes[email]
-	v.mu.RUnlock()
+	stored, exists := storedCode(t, v, email)
 	require.True(t, exists)
 	assert.Equal(t, 1, stored.Attempts)
 
@@ -50,9 +64,7 @@ func TestMFAValidator_VerifyCode(t *testing.T) {
 	assert.True(t, valid)
 
 	// 有効なコードの検証後、コードが削除されること
-	v.mu.RLock()
-	_, exists = v.codes[email]
-	v.mu.RUnlock()
+	_, exists = storedCode(t, v, email)
 	assert.False(t, exists)
 }
 
@@ -75,9 +87,7 @@ func TestMFAValidator_RateLimiting(t *testing.T) {
 	assert.False(t, valid)
 	assert.Contains(t, err.Error(), "invalid MFA code")
 
-	v.mu.RLock()
-	_, exists := v.codes[email]
-	v.mu.RUnlock()
+	_, exists := storedCode(t, v, email)
 	assert.False(t, exists)
 }
 
@@ -88,14 +98,35 @@ func TestMFAValidator_CleanupExpiredCodes(t *testing.T) {
 	v.GenerateCode(email)
 
 	// 手動でコードを期限切れにする
-	v.mu.Lock()
-	v.codes[email].ExpiresAt = time.Now().Add(-1 * time.Minute)
-	v.mu.Unlock()
+	stored, _ := storedCode(t, v, email)
+	stored.ExpiresAt = time.Now().Add(-1 * time.Minute)
+	data, _ := json.Marshal(stored)
+	require.NoError(t, v.store.Set(context.Background(), codeKey(email), data, stored.ExpiresAt))
 
 	v.CleanupExpiredCodes()
 
-	v.mu.RLock()
-	_, exists := v.codes[email]
-	v.mu.RUnlock()
+	_, exists := storedCode(t, v, email)
 	assert.False(t, exists)
 }
+
+func TestMFAValidator_SharedStore(t *testing.T) {
+	store := kvstore.NewMemoryStore(time.Hour)
+	defer store.Stop()
+	email := "test@example.com"
+
+	// 別のレプリカで生成したコードも検証できる
+	issuer := NewMFAValidatorWithStore(store)
+	verifier := NewMFAValidatorWithStore(store)
+
+	code, err := issuer.GenerateCode(email)
+	require.NoError(t, err)
+
+	valid, err := verifier.VerifyCode(email, code)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// 共有ストアは検証器の Stop では停止しない
+	issuer.Stop()
+	_, err = issuer.GenerateCode(email)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// Admin audit actions
+const (
+	AuditActionPostDelete         = "post.delete"
+	AuditActionPostApprove        = "post.approve"
+	AuditActionPostReject         = "post.reject"
+	AuditActionUserDelete         = "user.delete"
+	AuditActionUserSuspend        = "user.suspend"
+	AuditActionUserBan            = "user.ban"
+	AuditActionSanctionLift       = "sanction.lift"
+	AuditActionSanctionExtend     = "sanction.extend"
+	AuditActionApplicationApprove = "application.approve"
+	AuditActionApplicationReject  = "application.reject"
+	AuditActionApplicationInfo    = "application.request_info"
+	AuditActionReportResolve      = "report.resolve"
+	AuditActionInquiryApprove     = "inquiry.approve"
+	AuditActionInquiryReject      = "inquiry.reject"
+	AuditActionInquiryReply       = "inquiry.reply"
+	AuditActionInquiryNote        = "inquiry.note"
+	AuditActionInquiryStatus      = "inquiry.status"
+	AuditActionInquiryAssign      = "inquiry.assign"
+	AuditActionFilterRuleCreate   = "content_filter_rule.create"
+	AuditActionFilterRuleUpdate   = "content_filter_rule.update"
+	AuditActionFilterRuleDelete   = "content_filter_rule.delete"
+	AuditActionOutboxReplay       = "outbox.replay"
+)
+
+// Admin audit target types
+const (
+	AuditTargetPost        = "post"
+	AuditTargetUser        = "user"
+	AuditTargetApplication = "application"
+	AuditTargetReport      = "report"
+	AuditTargetInquiry     = "inquiry"
+	AuditTargetSanction    = "sanction"
+	AuditTargetFilterRule  = "content_filter_rule"
+	AuditTargetOutbox      = "outbox_message"
+)
+
+// AdminAuditLog represents an append-only record of an admin action (更新・削除はしない)
+type AdminAuditLog struct {
+	ID            int64     `gorm:"column:auditId;primaryKey;autoIncrement" json:"auditId"`
+	ActorGoogleID string    `gorm:"column:actorGoogleId" json:"actorGoogleId"`
+	Action        string    `gorm:"column:action" json:"action"`
+	TargetType    string    `gorm:"column:targetType" json:"targetType"`
+	TargetID      string    `gorm:"column:targetId" json:"targetId"`
+	Before        *string   `gorm:"column:before" json:"before,omitempty"` // 操作前のJSON（作成時は nil）
+	After         *string   `gorm:"column:after" json:"after,omitempty"`   // 操作後のJSON
+	RequestID     string    `gorm:"column:requestId;default:''" json:"requestId"`
+	IPAddress     string    `gorm:"column:ipAddress;default:''" json:"ipAddress"`
+	CreatedAt     time.Time `gorm:"column:createdAt" json:"createdAt"`
+}
+
+// TableName specifies the table name for AdminAuditLog
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_log"
+}
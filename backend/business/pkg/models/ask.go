@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// AskStatus represents the status of an inquiry
+type AskStatus string
+
+const (
+	AskStatusOpen    AskStatus = "open"    // 管理者の対応待ち
+	AskStatusWaiting AskStatus = "waiting" // 返信済みでユーザーの返答待ち
+	AskStatusClosed  AskStatus = "closed"  // 対応済み（ユーザーが追加で問い合わせると open に戻る）
+)
+
+// IsValid reports whether s is one of the inquiry statuses
+func (s AskStatus) IsValid() bool {
+	switch s {
+	case AskStatusOpen, AskStatusWaiting, AskStatusClosed:
+		return true
+	}
+	return false
+}
+
+// Ask represents the 問い合わせ情報 table
+// ユーザー側・事業者側からの問い合わせで、やり取りは AskMessage に保存する
+type Ask struct {
+	AskID      int32      `gorm:"column:askId;primaryKey;autoIncrement" json:"askId"`
+	Date       time.Time  `gorm:"column:date" json:"date"`
+	Subject    string     `gorm:"column:subject" json:"subject"`
+	Text       string     `gorm:"column:text" json:"text"`
+	UserID     string     `gorm:"column:userId" json:"userId"`
+	AskFlag    bool       `gorm:"column:askFlag;default:false" json:"askFlag"` // closed の場合に true
+	Status     AskStatus  `gorm:"column:status;default:'open'" json:"status"`
+	AssigneeID *string    `gorm:"column:assigneeId" json:"assigneeId"` // 担当の管理者
+	UpdatedAt  *time.Time `gorm:"column:updatedAt" json:"updatedAt"`   // 最後のやり取り・状態の変更
+}
+
+// TableName specifies the table name for Ask
+func (Ask) TableName() string {
+	return "ask"
+}
+
+// Inquiry message kinds
+const (
+	AskMessageReply    = "reply"     // 管理者からの返信（ユーザーにメールで送る）
+	AskMessageFollowUp = "follow_up" // ユーザーからの追加の問い合わせ
+	AskMessageNote     = "note"      // 管理者間のメモ（ユーザーには見せない）
+)
+
+// AskMessage represents one message in the thread of an inquiry
+// 最初の問い合わせ本文は Ask.Text にあり、2件目以降のやり取りを保存する
+type AskMessage struct {
+	MessageID int64     `gorm:"column:messageId;primaryKey;autoIncrement" json:"messageId"`
+	AskID     int32     `gorm:"column:askId" json:"askId"`
+	Kind      string    `gorm:"column:kind" json:"kind"`
+	AuthorID  string    `gorm:"column:authorId" json:"authorId"`
+	Body      string    `gorm:"column:body" json:"body"`
+	CreatedAt time.Time `gorm:"column:createdAt" json:"createdAt"`
+}
+
+// TableName specifies the table name for AskMessage
+func (AskMessage) TableName() string {
+	return "ask_message"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// BusinessMember represents the 事業者会員情報 table
+// 事業者申請の承認時に作成する（ユーザー側では Business の名前で参照する）
+type BusinessMember struct {
+	BusinessID       int32     `gorm:"column:businessId;primaryKey;autoIncrement" json:"businessId"`
+	BusinessName     string    `gorm:"column:businessName" json:"businessName"`
+	KanaBusinessName string    `gorm:"column:kanaBusinessName" json:"kanaBusinessName"`
+	ZipCode          string    `gorm:"column:zipCode" json:"zipCode"` // ハイフンなしの7桁（未設定の場合は空）
+	Address          string    `gorm:"column:address" json:"address"`
+	Phone            string    `gorm:"column:phone" json:"phone"`
+	RegistDate       time.Time `gorm:"column:registDate" json:"registDate"`
+	ProfileImage     []byte    `gorm:"column:profileImage" json:"-"` // アイコン画像（一覧には含めない）
+	UserID           string    `gorm:"column:userId" json:"userId"`
+	PlaceID          int32     `gorm:"column:placeId" json:"placeId"` // 住所の場所（地図に表示する位置）
+}
+
+// TableName specifies the table name for BusinessMember
+func (BusinessMember) TableName() string {
+	return "business"
+}
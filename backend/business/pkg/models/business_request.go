@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+)
+
+// Business application statuses
+// pending（審査待ち）→ needs_info（追加情報の依頼中）→ pending（再提出）→ approved / rejected
+const (
+	BusinessRequestPending   = "pending"
+	BusinessRequestNeedsInfo = "needs_info"
+	BusinessRequestApproved  = "approved"
+	BusinessRequestRejected  = "rejected"
+)
+
+// BusinessRequest represents the 事業者申請情報 table
+type BusinessRequest struct {
+	RequestID       int32      `gorm:"column:requestId;primaryKey;autoIncrement" json:"requestId"`
+	Name            string     `gorm:"column:name" json:"businessName"`
+	Address         string     `gorm:"column:address" json:"address"`
+	Phone           string     `gorm:"column:phone" json:"phone"`                               // 数字のみ（重複申請の検出に使う）
+	CorporateNumber *string    `gorm:"column:corporateNumber" json:"corporateNumber,omitempty"` // 法人番号（任意）
+	UserID          string     `gorm:"column:userId" json:"userId"`
+	Status          string     `gorm:"column:status;default:'pending'" json:"status"`
+	CreatedAt       time.Time  `gorm:"column:createdAt;autoCreateTime" json:"createdAt"`
+	UpdatedAt       *time.Time `gorm:"column:updatedAt" json:"updatedAt,omitempty"`
+}
+
+// TableName specifies the table name for BusinessRequest
+func (BusinessRequest) TableName() string {
+	return "businessReq"
+}
+
+// IsActive reports whether the application is still under review
+func (r *BusinessRequest) IsActive() bool {
+	return r.Status == BusinessRequestPending || r.Status == BusinessRequestNeedsInfo
+}
+
+// Business application document kinds
+const (
+	BusinessDocumentLicense  = "license"  // 営業許可証など
+	BusinessDocumentRegistry = "registry" // 登記事項証明書
+	BusinessDocumentOther    = "other"
+)
+
+// BusinessRequestDocument represents a verification document attached to a business application
+type BusinessRequestDocument struct {
+	DocumentID  int64     `gorm:"column:documentId;primaryKey;autoIncrement" json:"documentId"`
+	RequestID   int32     `gorm:"column:requestId" json:"requestId"`
+	Kind        string    `gorm:"column:kind" json:"kind"`
+	FileName    string    `gorm:"column:fileName" json:"fileName"`
+	ContentType string    `gorm:"column:contentType" json:"contentType"`
+	Size        int64     `gorm:"column:size" json:"size"`
+	Data        []byte    `gorm:"column:data" json:"-"`
+	CreatedAt   time.Time `gorm:"column:createdAt;autoCreateTime" json:"createdAt"`
+}
+
+// TableName specifies the table name for BusinessRequestDocument
+func (BusinessRequestDocument) TableName() string {
+	return "businessReq_document"
+}
+
+// BusinessRequestHistory represents a status transition of a business application
+type BusinessRequestHistory struct {
+	HistoryID  int64     `gorm:"column:historyId;primaryKey;autoIncrement" json:"historyId"`
+	RequestID  int32     `gorm:"column:requestId" json:"requestId"`
+	FromStatus string    `gorm:"column:fromStatus" json:"fromStatus"` // 申請の作成時は空
+	ToStatus   string    `gorm:"column:toStatus" json:"toStatus"`
+	ActorID    string    `gorm:"column:actorId" json:"actorId"` // 申請者または管理者の googleId
+	Note       string    `gorm:"column:note" json:"note"`       // 追加情報の依頼内容・却下理由・再提出時のコメント
+	CreatedAt  time.Time `gorm:"column:createdAt;autoCreateTime" json:"createdAt"`
+}
+
+// TableName specifies the table name for BusinessRequestHistory
+func (BusinessRequestHistory) TableName() string {
+	return "businessReq_history"
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// TOTPCredential represents the 認証アプリ（TOTP）登録情報 table of a business account
+// シークレットは暗号化して保存し、最初のコードで確認するまで（ConfirmedAt が nil の間）は登録途中として扱う
+type TOTPCredential struct {
+	UserID       string     `gorm:"column:userId;primaryKey" json:"-"`
+	SecretEnc    string     `gorm:"column:secretEnc" json:"-"`              // 暗号化したTOTPシークレット（平文では保存しない）
+	ConfirmedAt  *time.Time `gorm:"column:confirmedAt" json:"confirmedAt"`  // 最初のコードで確認した日時
+	LastUsedStep int64      `gorm:"column:lastUsedStep;default:0" json:"-"` // 最後に使用したステップ番号（同じコードの再利用を防ぐ）
+	CreatedAt    time.Time  `gorm:"column:createdAt" json:"createdAt"`
+	UpdatedAt    time.Time  `gorm:"column:updatedAt" json:"updatedAt"`
+}
+
+// TableName specifies the table name for TOTPCredential
+func (TOTPCredential) TableName() string {
+	return "business_totp"
+}
+
+// Confirmed reports whether the enrollment has been confirmed
+func (c *TOTPCredential) Confirmed() bool {
+	return c.ConfirmedAt != nil
+}
+
+// RecoveryCode represents a single-use recovery code for when the authenticator app is unavailable
+type RecoveryCode struct {
+	ID        int32      `gorm:"column:recoveryCodeId;primaryKey;autoIncrement" json:"-"`
+	UserID    string     `gorm:"column:userId" json:"-"`
+	CodeHash  string     `gorm:"column:codeHash" json:"-"` // コードのSHA-256ハッシュ（平文では保存しない）
+	UsedAt    *time.Time `gorm:"column:usedAt" json:"usedAt,omitempty"`
+	CreatedAt time.Time  `gorm:"column:createdAt" json:"createdAt"`
+}
+
+// TableName specifies the table name for RecoveryCode
+func (RecoveryCode) TableName() string {
+	return "business_recovery_code"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Content filter rule kinds
+const (
+	FilterKindNGWord   = "ng_word"   // admin-managed NG word (Pattern holds the word)
+	FilterKindPhone    = "phone"     // Japanese phone numbers
+	FilterKindEmail    = "email"     // email addresses
+	FilterKindMyNumber = "my_number" // 12-digit My Number-like sequences
+)
+
+// Content filter actions
+const (
+	FilterActionReject = "reject" // reject the text with Reason
+	FilterActionMask   = "mask"   // replace the matched part with asterisks
+	FilterActionReview = "review" // accept but send to the moderation queue
+)
+
+// ContentFilterRule represents an NG word or a PII detector and its action
+type ContentFilterRule struct {
+	ID        int32     `gorm:"column:ruleId;primaryKey;autoIncrement" json:"ruleId"`
+	Kind      string    `gorm:"column:kind" json:"kind"`
+	Pattern   string    `gorm:"column:pattern;default:''" json:"pattern"`
+	Action    string    `gorm:"column:action" json:"action"`
+	Reason    string    `gorm:"column:reason;default:''" json:"reason"`
+	Enabled   bool      `gorm:"column:enabled;default:true" json:"enabled"`
+	CreatedAt time.Time `gorm:"column:createdAt" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updatedAt" json:"updatedAt"`
+}
+
+// TableName specifies the table name for ContentFilterRule
+func (ContentFilterRule) TableName() string {
+	return "content_filter_rule"
+}
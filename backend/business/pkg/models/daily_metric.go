@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Daily metrics rolled up for the admin analytics
+const (
+	MetricNewUsers       = "new_users"       // 登録したユーザー数
+	MetricActiveUsers    = "active_users"    // 利用したユーザー数（DAU）
+	MetricPosts          = "posts"           // 投稿数
+	MetricPostsByGenre   = "posts_by_genre"  // ジャンルごとの投稿数（dimension: genreId）
+	MetricPostsByArea    = "posts_by_area"   // 地域ごとの投稿数（dimension: 地域）
+	MetricReactions      = "reactions"       // リアクション数
+	MetricReports        = "reports"         // 通報数
+	MetricReportsHandled = "reports_handled" // 対応した通報数
+	MetricReportBacklog  = "report_backlog"  // その日の終わりに未対応の通報数
+	MetricHandleTime     = "handle_time"     // 通報から対応までの時間の分布（dimension: 区間の上限の分数、上限なしは "inf"）
+)
+
+// DailyMetric represents one rolled-up value of a metric for a day
+// 集計ジョブが日ごとに作り直すため、管理画面の分析は元のテーブルを全件集計しません
+type DailyMetric struct {
+	Date      time.Time `gorm:"column:date;primaryKey" json:"date"`
+	Metric    string    `gorm:"column:metric;primaryKey" json:"metric"`
+	Dimension string    `gorm:"column:dimension;primaryKey;default:''" json:"dimension,omitempty"`
+	Value     int64     `gorm:"column:value" json:"value"`
+	UpdatedAt time.Time `gorm:"column:updatedAt" json:"updatedAt"`
+}
+
+// TableName specifies the table name for DailyMetric
+func (DailyMetric) TableName() string {
+	return "daily_metric"
+}
@@ -0,0 +1,6 @@
+// Package models はユーザー側・管理者側・事業者側で共有するテーブルのモデルです。
+//
+// メインモジュールと事業者モジュールの両方から参照するため、テーブルのモデルはこのパッケージにだけ置きます。
+// スキーマの正は backend/migrations の SQL で、GORM のタグには列名・主キー・既定値など
+// クエリに必要なものだけを書きます（型・NOT NULL・インデックスなどの DDL はマイグレーションで管理します）。
+package models
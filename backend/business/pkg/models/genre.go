@@ -0,0 +1,14 @@
+package models
+
+// Genre represents the ジャンル table
+// ジャンルは 000001_init のマイグレーションで作成する6件で固定
+type Genre struct {
+	GenreID   int32  `gorm:"column:genreId;primaryKey;autoIncrement" json:"genreId"`
+	GenreName string `gorm:"column:genreName" json:"genreName"`
+	Color     string `gorm:"column:color" json:"color"` // # なしの16進数
+}
+
+// TableName specifies the table name for Genre
+func (Genre) TableName() string {
+	return "genre"
+}
@@ -1,14 +1,15 @@
 package models
 
-// Place 場所モデル
+// Place represents the 場所情報 table
+// 投稿と事業者の位置で、近い位置（約11m以内）は同じ場所として扱う
 type Place struct {
-	ID        int32   `gorm:"column:placeId;primaryKey;autoIncrement" json:"placeId"`
+	PlaceID   int32   `gorm:"column:placeId;primaryKey;autoIncrement" json:"placeId"`
 	NumPost   int32   `gorm:"column:numPost;default:0" json:"numPost"`
 	Latitude  float64 `gorm:"column:latitude" json:"latitude"`
 	Longitude float64 `gorm:"column:longitude" json:"longitude"`
 }
 
-// TableName テーブル名を指定
+// TableName specifies the table name for Place
 func (Place) TableName() string {
 	return "place"
 }
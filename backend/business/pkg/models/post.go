@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Post represents the 投稿内容 table
+// ユーザー側・管理者側・事業者側で同じ post テーブルを参照する（管理者の審査キューで同じ投稿を扱うため）
+type Post struct {
+	PostID      int32      `gorm:"column:postId;primaryKey;autoIncrement" json:"postId"`
+	PlaceID     int32      `gorm:"column:placeId" json:"placeId"`
+	UserID      string     `gorm:"column:userId" json:"userId"`
+	PostDate    time.Time  `gorm:"column:postDate" json:"postDate"`
+	Title       string     `gorm:"column:title" json:"title"`
+	Text        string     `gorm:"column:text" json:"text"`
+	PostImage   []byte     `gorm:"column:postImage" json:"postImage,omitempty"`
+	NumReaction int32      `gorm:"column:numReaction;default:0" json:"numReaction"`
+	NumView     int32      `gorm:"column:numView;default:0" json:"numView"`
+	GenreID     int32      `gorm:"column:genreId" json:"genreId"`
+	Status      string     `gorm:"column:status;default:'published'" json:"status"` // PostStatus* のいずれか
+	PublishAt   *time.Time `gorm:"column:publishAt" json:"publishAt,omitempty"`     // 予約投稿の公開日時
+	// ModerationStatus is one of ModerationApproved, ModerationPending, ModerationHidden or ModerationRejected
+	ModerationStatus string     `gorm:"column:moderationStatus;default:'approved'" json:"moderationStatus"`
+	ModeratedAt      *time.Time `gorm:"column:moderatedAt" json:"moderatedAt,omitempty"`
+	// DeletedAt is set when the post is removed; its reports are kept as the moderation record
+	// 削除済みの投稿はクエリから自動で除外される。管理者側で削除済みの投稿も扱う場合は Unscoped を使う
+	DeletedAt gorm.DeletedAt `gorm:"column:deletedAt" json:"-"`
+}
+
+// Publish statuses of a post
+const (
+	PostStatusDraft     = "draft"     // 下書き（投稿者本人のみ閲覧可）
+	PostStatusScheduled = "scheduled" // 予約投稿（publishAt に公開）
+	PostStatusPublished = "published" // 公開済み
+)
+
+// Moderation statuses of a post（approved 以外は公開されない）
+const (
+	ModerationApproved = "approved" // 公開可
+	ModerationPending  = "pending"  // 新規アカウントの投稿で審査待ち
+	ModerationHidden   = "hidden"   // 通報多数により自動非表示（審査待ち）
+	ModerationRejected = "rejected" // 審査で却下
+)
+
+// TableName specifies the table name for Post
+func (Post) TableName() string {
+	return "post"
+}
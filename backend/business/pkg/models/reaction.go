@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// UserReaction represents the リアクション table（表17）
+type UserReaction struct {
+	ID        int32     `gorm:"column:reactionId;primaryKey;autoIncrement" json:"reactionId"`
+	UserID    string    `gorm:"column:userId" json:"userId"`
+	PostID    int32     `gorm:"column:postId" json:"postId"`
+	CreatedAt time.Time `gorm:"column:createdAt" json:"createdAt"`
+}
+
+// TableName specifies the table name for UserReaction
+func (UserReaction) TableName() string {
+	return "reaction"
+}
+
+// UserBlock represents the ブロック table
+type UserBlock struct {
+	BlockId   int32     `gorm:"column:blockId;primaryKey;autoIncrement" json:"blockId"`
+	BlockerId string    `gorm:"column:blockerId" json:"blockerId"`
+	BlockedId string    `gorm:"column:blockedId" json:"blockedId"`
+	CreatedAt time.Time `gorm:"column:createdAt" json:"createdAt"`
+}
+
+// TableName specifies the table name for UserBlock
+func (UserBlock) TableName() string {
+	return "block"
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Report represents the 通報情報 table
+type Report struct {
+	ReportID int32  `gorm:"column:reportId;primaryKey;autoIncrement" json:"reportId"`
+	UserID   string `gorm:"column:userId" json:"userId"` // 通報したユーザー
+	PostID   int32  `gorm:"column:postId" json:"postId"`
+	Category string `gorm:"column:category;default:'other'" json:"category"` // reportcategory の分類
+	Reason   string `gorm:"column:reason" json:"reason"`                     // 通報者の任意のコメント
+	// OpenFlag is true while the report is open and NULL once resolved
+	// 同じユーザーが同じ投稿に未対応の通報を複数持たないよう、uq_report_open（userId, postId, openFlag）の対象にする
+	OpenFlag   *bool     `gorm:"column:openFlag" json:"-"`
+	Date       time.Time `gorm:"column:date" json:"date"`
+	ReportFlag bool      `gorm:"column:reportFlag;default:false" json:"reportFlag"` // 対応済み
+	RemoveFlag bool      `gorm:"column:removeFlag;default:false" json:"removeFlag"` // 投稿を削除した
+	// Resolution is one of the ReportResolution* values (empty while open)
+	Resolution     string     `gorm:"column:resolution;default:''" json:"resolution"`
+	ResolutionNote string     `gorm:"column:resolutionNote" json:"resolutionNote,omitempty"`
+	ResolvedBy     string     `gorm:"column:resolvedBy" json:"-"`
+	ResolvedAt     *time.Time `gorm:"column:resolvedAt" json:"resolvedAt,omitempty"`
+	// DeletedAt hides the report; deleted reports are scoped out of queries
+	DeletedAt gorm.DeletedAt `gorm:"column:deletedAt" json:"-"`
+}
+
+// Report resolutions chosen by an admin
+const (
+	ReportResolutionDismiss       = "dismiss"        // 問題なし（自動非表示の投稿は公開に戻す）
+	ReportResolutionRemovePost    = "remove_post"    // 投稿を削除
+	ReportResolutionWarnAuthor    = "warn_author"    // 投稿は残し、投稿者に警告（自動非表示の投稿は公開に戻す）
+	ReportResolutionSuspendAuthor = "suspend_author" // 投稿を削除し、投稿者を利用停止
+)
+
+// IsValidReportResolution reports whether r is one of the ReportResolution* values
+func IsValidReportResolution(r string) bool {
+	switch r {
+	case ReportResolutionDismiss, ReportResolutionRemovePost, ReportResolutionWarnAuthor, ReportResolutionSuspendAuthor:
+		return true
+	}
+	return false
+}
+
+// ReportResolutionRemovesPost reports whether resolving a report with r removes the reported post
+func ReportResolutionRemovesPost(r string) bool {
+	return r == ReportResolutionRemovePost || r == ReportResolutionSuspendAuthor
+}
+
+// TableName specifies the table name for Report
+func (Report) TableName() string {
+	return "report"
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// Session represents the ログインセッション table
+type Session struct {
+	SessionID  string     `gorm:"column:sessionId;primaryKey" json:"sessionId"`
+	GoogleID   string     `gorm:"column:googleId" json:"googleId"`
+	Expiry     time.Time  `gorm:"column:expiry" json:"expiry"`
+	UserAgent  string     `gorm:"column:userAgent" json:"userAgent"`
+	IPAddress  string     `gorm:"column:ipAddress" json:"ipAddress"`
+	CreatedAt  time.Time  `gorm:"column:createdAt;autoCreateTime" json:"createdAt"` // 初回ログイン
+	LastSeenAt *time.Time `gorm:"column:lastSeenAt" json:"lastSeenAt"`
+	RevokedAt  *time.Time `gorm:"column:revokedAt" json:"revokedAt,omitempty"`
+}
+
+// TableName specifies the table name for Session
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// RefreshToken represents the リフレッシュトークン table（トークン本体は保存せずハッシュのみ保持）
+// 同じログインから発行されたトークンは FamilyID を共有し、
+// 使用済みトークンが再提示された場合はファミリー全体を失効させる
+type RefreshToken struct {
+	TokenID   string     `gorm:"column:tokenId;primaryKey" json:"tokenId"`
+	FamilyID  string     `gorm:"column:familyId" json:"familyId"`
+	GoogleID  string     `gorm:"column:googleId" json:"googleId"`
+	SessionID string     `gorm:"column:sessionId" json:"sessionId"`
+	TokenHash string     `gorm:"column:tokenHash" json:"-"`
+	ExpiresAt time.Time  `gorm:"column:expiresAt" json:"expiresAt"`
+	UsedAt    *time.Time `gorm:"column:usedAt" json:"usedAt,omitempty"`
+	RevokedAt *time.Time `gorm:"column:revokedAt" json:"revokedAt,omitempty"`
+	CreatedAt time.Time  `gorm:"column:createdAt;autoCreateTime" json:"createdAt"`
+}
+
+// TableName specifies the table name for RefreshToken
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+// SignInHistory represents the ログイン履歴 table
+// NewDevice はこれまでに使われていない端末（User-Agent）からのログインを表す
+type SignInHistory struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	GoogleID  string    `gorm:"column:googleId" json:"-"`
+	SessionID string    `gorm:"column:sessionId" json:"sessionId"`
+	UserAgent string    `gorm:"column:userAgent" json:"userAgent"`
+	IPAddress string    `gorm:"column:ipAddress" json:"ipAddress"`
+	NewDevice bool      `gorm:"column:newDevice;default:false" json:"newDevice"`
+	CreatedAt time.Time `gorm:"column:createdAt;autoCreateTime" json:"createdAt"`
+}
+
+// TableName specifies the table name for SignInHistory
+func (SignInHistory) TableName() string {
+	return "sign_in_history"
+}
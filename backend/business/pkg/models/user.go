@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Role represents user role enum
+type Role string
+
+const (
+	RoleUser     Role = "user"
+	RoleBusiness Role = "business"
+	RoleAdmin    Role = "admin"
+)
+
+// User represents the 会員情報 table
+// ユーザー側・管理者側・事業者側で同じ user テーブルを参照し、ロールの変更はログイン中のユーザーにも次のトークン更新から反映される
+// GoogleID は内部ユーザーID（カラム名は以前の名残）。既存ユーザーは Google の sub、新規ユーザーは UUID
+// ログインに使う外部アカウントは UserIdentity で連携する。メールアドレスを提供しないプロバイダーでは Gmail は空（NULL）
+type User struct {
+	GoogleID         string     `gorm:"column:googleId;primaryKey" json:"googleId"`
+	Gmail            string     `gorm:"column:gmail;default:null" json:"gmail"`
+	Role             Role       `gorm:"column:role" json:"role"`
+	RegistrationDate time.Time  `gorm:"column:registrationDate" json:"registrationDate"`
+	DeletedAt        *time.Time `gorm:"column:deletedAt" json:"deletedAt,omitempty"` // 管理者による削除（退会は物理削除）
+}
+
+// TableName specifies the table name for User
+func (User) TableName() string {
+	return "user"
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// UserIdentity represents a login account linked to a user（OpenID Connect の発行者と sub の組）
+// 1人のユーザーに複数のプロバイダー（Google・LINE など）を連携できる（同じプロバイダーは1件まで）
+type UserIdentity struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"-"`
+	GoogleID    string     `gorm:"column:googleId" json:"-"`
+	Provider    string     `gorm:"column:provider" json:"provider"`
+	Issuer      string     `gorm:"column:issuer" json:"issuer"`
+	Subject     string     `gorm:"column:subject" json:"-"`
+	Email       string     `gorm:"column:email" json:"email,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:createdAt;autoCreateTime" json:"linkedAt"`
+	LastLoginAt *time.Time `gorm:"column:lastLoginAt" json:"lastLoginAt,omitempty"`
+}
+
+// TableName specifies the table name for UserIdentity
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// Sanction kinds
+// suspension・ban の判定（ログイン・認証時）は kojan-map/business/pkg/accountstatus で行う
+const (
+	SanctionWarning    = "warning"    // 警告（記録のみ）
+	SanctionSuspension = "suspension" // 期限付きの利用停止
+	SanctionBan        = "ban"        // 無期限の利用禁止
+)
+
+// UserSanction represents a warning, suspension or ban given to a user by an admin
+type UserSanction struct {
+	ID         int64      `gorm:"column:sanctionId;primaryKey;autoIncrement" json:"sanctionId"`
+	UserID     string     `gorm:"column:userId" json:"userId"`
+	Kind       string     `gorm:"column:kind" json:"kind"`
+	Reason     string     `gorm:"column:reason" json:"reason"`
+	ReportID   *int32     `gorm:"column:reportId" json:"reportId,omitempty"` // きっかけになった通報
+	StartsAt   time.Time  `gorm:"column:startsAt" json:"startsAt"`
+	EndsAt     *time.Time `gorm:"column:endsAt" json:"endsAt,omitempty"` // 警告・利用禁止の場合は nil
+	CreatedBy  string     `gorm:"column:createdBy" json:"createdBy"`
+	CreatedAt  time.Time  `gorm:"column:createdAt" json:"createdAt"`
+	LiftedAt   *time.Time `gorm:"column:liftedAt" json:"liftedAt,omitempty"` // 管理者が解除した日時
+	LiftedBy   string     `gorm:"column:liftedBy;default:''" json:"liftedBy,omitempty"`
+	LiftReason string     `gorm:"column:liftReason" json:"liftReason,omitempty"`
+}
+
+// TableName specifies the table name for UserSanction
+func (UserSanction) TableName() string {
+	return "user_sanction"
+}
+
+// IsActive reports whether the sanction restricts the account at t
+// 警告はアカウントを制限しないため常に false
+func (s *UserSanction) IsActive(t time.Time) bool {
+	if s.Kind != SanctionSuspension && s.Kind != SanctionBan {
+		return false
+	}
+	if s.LiftedAt != nil || s.StartsAt.After(t) {
+		return false
+	}
+	return s.EndsAt == nil || s.EndsAt.After(t)
+}
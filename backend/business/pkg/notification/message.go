@@ -0,0 +1,191 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Kind は通知の種類です（テンプレートのファイル名に対応）
+type Kind string
+
+const (
+	KindMFACode             Kind = "mfa_code"             // 多要素認証・追加認証のコード
+	KindApplicationApproved Kind = "application_approved" // 事業者申請の承認
+	KindApplicationRejected Kind = "application_rejected" // 事業者申請の却下
+	KindApplicationInfo     Kind = "application_info"     // 事業者申請への追加情報の依頼
+	KindInquiryReply        Kind = "inquiry_reply"        // お問い合わせへの返信
+	KindReportOutcome       Kind = "report_outcome"       // 通報の対応結果
+	KindModerationNotice    Kind = "moderation_notice"    // 通報による投稿者への警告・利用停止
+	KindDigest              Kind = "digest"               // 定期まとめ
+)
+
+// Locale は通知の言語です
+type Locale string
+
+const (
+	LocaleJA Locale = "ja"
+	LocaleEN Locale = "en"
+)
+
+// DefaultLocale はテンプレートが見つからない場合に使う言語です
+const DefaultLocale = LocaleJA
+
+// Message は送信する通知です
+// Data には Kind に対応する型（MFACodeData など）を指定します
+type Message struct {
+	Kind   Kind
+	To     string
+	Locale Locale // 空の場合は Notifier の既定の言語
+	Data   interface{}
+}
+
+// MFACodeData は KindMFACode のデータです
+type MFACodeData struct {
+	Code          string
+	ExpiresInMins int
+}
+
+// ApplicationDecisionData は KindApplicationApproved / KindApplicationRejected のデータです
+type ApplicationDecisionData struct {
+	BusinessName string
+	Reason       string // 却下理由（承認の場合は空）
+}
+
+// ApplicationInfoData は KindApplicationInfo のデータです
+type ApplicationInfoData struct {
+	BusinessName string
+	Request      string // 追加で必要な情報
+}
+
+// InquiryReplyData は KindInquiryReply のデータです
+type InquiryReplyData struct {
+	Subject string
+	Reply   string
+}
+
+// ReportOutcomeData は KindReportOutcome のデータです
+type ReportOutcomeData struct {
+	PostTitle string
+	Outcome   string // 対応内容（dismiss, remove_post, warn_author, suspend_author。それ以外はそのまま表示）
+	Note      string
+}
+
+// ModerationNoticeData は KindModerationNotice のデータです
+type ModerationNoticeData struct {
+	PostTitle string     // 通報された投稿（管理者が直接利用停止・利用禁止した場合は空）
+	PostKept  bool       // 投稿を削除せずに警告した場合は true
+	Sanction  string     // warning・suspension または ban
+	Note      string     // 理由
+	Until     *time.Time // 利用停止の終了日時（警告・利用禁止の場合は nil）
+}
+
+// DigestItem はまとめに含める1件です
+type DigestItem struct {
+	Title string
+	URL   string
+}
+
+// DigestData は KindDigest のデータです
+type DigestData struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Items       []DigestItem
+}
+
+// dataTypes は Kind ごとに Data として受け付ける型です
+var dataTypes = map[Kind]func(interface{}) bool{
+	KindMFACode:             func(d interface{}) bool { _, ok := d.(MFACodeData); return ok },
+	KindApplicationApproved: func(d interface{}) bool { _, ok := d.(ApplicationDecisionData); return ok },
+	KindApplicationRejected: func(d interface{}) bool { _, ok := d.(ApplicationDecisionData); return ok },
+	KindApplicationInfo:     func(d interface{}) bool { _, ok := d.(ApplicationInfoData); return ok },
+	KindInquiryReply:        func(d interface{}) bool { _, ok := d.(InquiryReplyData); return ok },
+	KindReportOutcome:       func(d interface{}) bool { _, ok := d.(ReportOutcomeData); return ok },
+	KindModerationNotice:    func(d interface{}) bool { _, ok := d.(ModerationNoticeData); return ok },
+	KindDigest:              func(d interface{}) bool { _, ok := d.(DigestData); return ok },
+}
+
+// dataDecoders は Kind ごとに JSON から Data を復元します
+var dataDecoders = map[Kind]func(json.RawMessage) (interface{}, error){
+	KindMFACode:             decodeData[MFACodeData],
+	KindApplicationApproved: decodeData[ApplicationDecisionData],
+	KindApplicationRejected: decodeData[ApplicationDecisionData],
+	KindApplicationInfo:     decodeData[ApplicationInfoData],
+	KindInquiryReply:        decodeData[InquiryReplyData],
+	KindReportOutcome:       decodeData[ReportOutcomeData],
+	KindModerationNotice:    decodeData[ModerationNoticeData],
+	KindDigest:              decodeData[DigestData],
+}
+
+func decodeData[T any](raw json.RawMessage) (interface{}, error) {
+	var data T
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// messageJSON は Message の JSON 表現です
+type messageJSON struct {
+	Kind   Kind            `json:"kind"`
+	To     string          `json:"to"`
+	Locale Locale          `json:"locale,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// MarshalJSON は Message を JSON に変換します（outbox への保存に使用）
+func (m Message) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(m.Data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(messageJSON{Kind: m.Kind, To: m.To, Locale: m.Locale, Data: data})
+}
+
+// UnmarshalJSON は JSON から Message を復元します
+// Data は Kind に対応する型（MFACodeData など）で復元されます
+func (m *Message) UnmarshalJSON(b []byte) error {
+	var raw messageJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	decode, ok := dataDecoders[raw.Kind]
+	if !ok {
+		return fmt.Errorf("notification: unknown kind %q", raw.Kind)
+	}
+	data, err := decode(raw.Data)
+	if err != nil {
+		return fmt.Errorf("notification: invalid data for kind %q: %w", raw.Kind, err)
+	}
+	*m = Message{Kind: raw.Kind, To: raw.To, Locale: raw.Locale, Data: data}
+	return nil
+}
+
+// Kinds は定義済みの通知の種類を返します
+func Kinds() []Kind {
+	return []Kind{
+		KindMFACode,
+		KindApplicationApproved,
+		KindApplicationRejected,
+		KindApplicationInfo,
+		KindInquiryReply,
+		KindReportOutcome,
+		KindModerationNotice,
+		KindDigest,
+	}
+}
+
+// validate は宛先と Data の型を確認します
+func (m Message) validate() error {
+	if m.To == "" {
+		return fmt.Errorf("notification: recipient is required")
+	}
+	check, ok := dataTypes[m.Kind]
+	if !ok {
+		return fmt.Errorf("notification: unknown kind %q", m.Kind)
+	}
+	if !check(m.Data) {
+		return fmt.Errorf("notification: invalid data %T for kind %q", m.Data, m.Kind)
+	}
+	return nil
+}
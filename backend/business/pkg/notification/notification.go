@@ -1,105 +1,168 @@
+// Package notification はメールなどの通知を送信します
+// 通知は種類（Kind）ごとのテンプレート（ja / en）から作成し、
+// 配送はトランスポート（SMTP / AWS SES / ファイル / ログ）に任せます
 package notification
 
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/mail"
 	"os"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/ses"
-	"github.com/aws/aws-sdk-go-v2/service/ses/types"
+	"strconv"
+	"strings"
 )
 
-// NotificationService はMFAコードなどの通知を送信するインターフェース
+// NotificationService は通知を送信するインターフェース
 type NotificationService interface {
+	// Send は通知をテンプレートから作成して送信します
+	Send(ctx context.Context, msg Message) error
+	// SendMFACode は多要素認証コードを送信します
 	SendMFACode(email string, code string) error
 }
 
-// EmailNotificationService はメール経由でMFAコードを送信します
-type EmailNotificationService struct {
-	// 本番環境では AWS SES, SendGrid などを使用
-	enabled bool
-}
+// defaultMFACodeValidMins は MFA コードのメールに記載する有効期間（分）です
+// 事業者ログインのMFAセッション（5分）に合わせています
+const defaultMFACodeValidMins = 5
 
-// NewEmailNotificationService はメール通知サービスを生成します
-func NewEmailNotificationService() *EmailNotificationService {
-	// 本番環境でのみ有効化
-	enabled := os.Getenv("GO_ENV") == "production" && os.Getenv("SMTP_ENABLED") == "true"
-	return &EmailNotificationService{
-		enabled: enabled,
-	}
+// Notifier はテンプレートとトランスポートを組み合わせた NotificationService の実装です
+type Notifier struct {
+	renderer  *Renderer
+	transport Transport
+	from      string
+	locale    Locale
 }
 
-// SendMFACode はMFAコードをメールで送信します
-func (s *EmailNotificationService) SendMFACode(email string, code string) error {
-	if !s.enabled {
-		// 開発環境ではログ出力のみ
-		log.Printf("[DEV] MFA Code for %s: %s", email, code)
-		return nil
+// NewNotifier は通知サービスを生成します
+// from は送信元アドレス、locale はメッセージで言語が指定されない場合の言語です
+func NewNotifier(renderer *Renderer, transport Transport, from string, locale Locale) *Notifier {
+	if locale == "" {
+		locale = DefaultLocale
 	}
-
-	// 本番環境: AWS SESを使用してメール送信
-	sesRegion := os.Getenv("AWS_REGION")
-	if sesRegion == "" {
-		sesRegion = "ap-northeast-1" // デフォルトリージョン
+	return &Notifier{
+		renderer:  renderer,
+		transport: transport,
+		from:      from,
+		locale:    locale,
 	}
+}
 
-	sesFromEmail := os.Getenv("SES_FROM_EMAIL")
-	if sesFromEmail == "" {
-		return fmt.Errorf("SES_FROM_EMAIL environment variable not set")
+// Send は通知をテンプレートから作成して送信します
+func (n *Notifier) Send(ctx context.Context, msg Message) error {
+	if msg.Locale == "" {
+		msg.Locale = n.locale
 	}
-
-	// AWS SDKの設定
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(sesRegion))
+	email, err := n.renderer.Render(msg)
 	if err != nil {
-		return fmt.Errorf("failed to load AWS config: %w", err)
+		return err
 	}
+	if err := n.transport.Send(ctx, n.from, email); err != nil {
+		return err
+	}
+	return nil
+}
 
-	sesClient := ses.NewFromConfig(cfg)
-
-	// メール本文の作成
-	subject := "Kojan-Map 多要素認証コード"
-	body := fmt.Sprintf(`
-Kojan-Mapをご利用いただきありがとうございます。
+// SendMFACode は多要素認証コードをメールで送信します
+func (n *Notifier) SendMFACode(email string, code string) error {
+	return n.Send(context.Background(), Message{
+		Kind: KindMFACode,
+		To:   email,
+		Data: MFACodeData{Code: code, ExpiresInMins: defaultMFACodeValidMins},
+	})
+}
 
-あなたの多要素認証コードは以下です：
+// Transports（NOTIFY_TRANSPORT に指定する値）
+const (
+	TransportSMTP = "smtp"
+	TransportSES  = "ses"
+	TransportFile = "file"
+	TransportLog  = "log"
+)
 
-%s
+// NewFromEnv は環境変数の設定から通知サービスを生成します
+//
+//	NOTIFY_TRANSPORT  smtp / ses / file / log
+//	                  未設定の場合、allowDevTransport（開発・テスト環境）なら log、それ以外で SMTP_ENABLED=true なら ses（従来の設定）
+//	MAIL_FROM         送信元アドレス（未設定の場合は SES_FROM_EMAIL。smtp / ses では必須）
+//	NOTIFY_LOCALE     既定の言語（ja / en、既定は ja）
+//	SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_IMPLICIT_TLS
+//	AWS_REGION        SES のリージョン
+//	NOTIFY_FILE_DIR   file の保存先（既定は tmp/mail）
+//
+// file・log はメール（MFAコードを含む）を実際には送らずファイル・ログに書き出すため、
+// allowDevTransport でない場合は smtp か ses の指定が必須です
+func NewFromEnv(allowDevTransport bool) (*Notifier, error) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		return nil, err
+	}
 
-このコードは5分間有効です。
-第三者と共有しないでください。
+	kind := strings.ToLower(os.Getenv("NOTIFY_TRANSPORT"))
+	if kind == "" {
+		switch {
+		case allowDevTransport:
+			kind = TransportLog
+		case os.Getenv("SMTP_ENABLED") == "true":
+			kind = TransportSES
+		default:
+			return nil, fmt.Errorf("NOTIFY_TRANSPORT environment variable is required outside dev/test (expected smtp or ses)")
+		}
+	}
 
-※このメールに心当たりがない場合は、削除してください。
-`, code)
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = os.Getenv("SES_FROM_EMAIL")
+	}
 
-	// メール送信リクエストの作成
-	input := &ses.SendEmailInput{
-		Source: aws.String(sesFromEmail),
-		Destination: &types.Destination{
-			ToAddresses: []string{email},
-		},
-		Message: &types.Message{
-			Subject: &types.Content{
-				Data:    aws.String(subject),
-				Charset: aws.String("UTF-8"),
-			},
-			Body: &types.Body{
-				Text: &types.Content{
-					Data:    aws.String(body),
-					Charset: aws.String("UTF-8"),
-				},
-			},
-		},
+	var transport Transport
+	switch kind {
+	case TransportSMTP:
+		if os.Getenv("SMTP_HOST") == "" {
+			return nil, fmt.Errorf("SMTP_HOST environment variable is required when NOTIFY_TRANSPORT=smtp")
+		}
+		port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		transport = NewSMTPTransport(SMTPConfig{
+			Host:        os.Getenv("SMTP_HOST"),
+			Port:        port,
+			Username:    os.Getenv("SMTP_USERNAME"),
+			Password:    os.Getenv("SMTP_PASSWORD"),
+			ImplicitTLS: os.Getenv("SMTP_IMPLICIT_TLS") == "true",
+		})
+	case TransportSES:
+		transport = NewSESTransport(os.Getenv("AWS_REGION"))
+	case TransportFile, TransportLog:
+		if !allowDevTransport {
+			return nil, fmt.Errorf("NOTIFY_TRANSPORT=%s is only allowed in dev/test; use smtp or ses", kind)
+		}
+		if kind == TransportLog {
+			transport = NewLogTransport(nil)
+			break
+		}
+		dir := os.Getenv("NOTIFY_FILE_DIR")
+		if dir == "" {
+			dir = "tmp/mail"
+		}
+		transport, err = NewFileTransport(dir)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown NOTIFY_TRANSPORT %q (expected smtp, ses, file or log)", kind)
 	}
 
-	// メール送信
-	_, err = sesClient.SendEmail(context.TODO(), input)
-	if err != nil {
-		return fmt.Errorf("failed to send email via SES: %w", err)
+	if from == "" {
+		if kind == TransportSMTP || kind == TransportSES {
+			return nil, fmt.Errorf("MAIL_FROM environment variable is required when NOTIFY_TRANSPORT=%s", kind)
+		}
+		from = "Kojan Map <no-reply@kojan-map.local>"
 	}
 
-	log.Printf("[PROD] MFA code sent to %s via AWS SES", email)
-	return nil
+	return NewNotifier(renderer, transport, from, Locale(os.Getenv("NOTIFY_LOCALE"))), nil
+}
+
+// addressOnly は "名前 <addr>" 形式からアドレスのみを取り出します
+func addressOnly(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		return addr.Address
+	}
+	return from
 }
@@ -0,0 +1,341 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"kojan-map/business/pkg/notification/smtptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suspendedUntil = time.Date(2025, 4, 8, 0, 0, 0, 0, time.UTC)
+
+// sampleData は各 Kind のテスト用データです
+var sampleData = map[Kind]interface{}{
+	KindMFACode:             MFACodeData{Code: "123456", ExpiresInMins: 5},
+	KindApplicationApproved: ApplicationDecisionData{BusinessName: "こじゃん商店"},
+	KindApplicationRejected: ApplicationDecisionData{BusinessName: "こじゃん商店", Reason: "住所が確認できません"},
+	KindApplicationInfo:     ApplicationInfoData{BusinessName: "こじゃん商店", Request: "営業許可証の写しを添付してください"},
+	KindInquiryReply:        InquiryReplyData{Subject: "ログインについて", Reply: "再度お試しください"},
+	KindReportOutcome:       ReportOutcomeData{PostTitle: "朝市", Outcome: "remove_post", Note: "ガイドライン違反"},
+	KindModerationNotice:    ModerationNoticeData{PostTitle: "朝市", Sanction: "suspension", Note: "ガイドライン違反", Until: &suspendedUntil},
+	KindDigest: DigestData{
+		PeriodStart: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC),
+		Items:       []DigestItem{{Title: "新しい投稿", URL: "https://example.com/posts/1"}},
+	},
+}
+
+// TestRenderer_AllKinds は全種類・全言語のテンプレートが描画できることを確認します
+func TestRenderer_AllKinds(t *testing.T) {
+	r, err := NewRenderer()
+	require.NoError(t, err)
+
+	for _, kind := range Kinds() {
+		for _, locale := range []Locale{LocaleJA, LocaleEN} {
+			email, err := r.Render(Message{Kind: kind, To: "to@example.com", Locale: locale, Data: sampleData[kind]})
+			require.NoError(t, err, "%s/%s", locale, kind)
+			assert.NotEmpty(t, email.Subject, "%s/%s", locale, kind)
+			assert.NotContains(t, email.Subject, "\n")
+			assert.NotEmpty(t, email.Text, "%s/%s", locale, kind)
+			assert.Contains(t, email.HTML, `<html lang="`+string(locale)+`">`)
+		}
+	}
+
+	email, err := r.Render(Message{Kind: KindMFACode, To: "to@example.com", Locale: LocaleEN, Data: sampleData[KindMFACode]})
+	require.NoError(t, err)
+	assert.Equal(t, "Your Kojan Map verification code", email.Subject)
+	assert.Contains(t, email.Text, "123456")
+	assert.Contains(t, email.Text, "5 minutes")
+
+	// 通報者には対応内容の説明を表示し、投稿者への処分は明かさない
+	email, err = r.Render(Message{Kind: KindReportOutcome, To: "to@example.com", Data: ReportOutcomeData{PostTitle: "朝市", Outcome: "suspend_author"}})
+	require.NoError(t, err)
+	assert.Contains(t, email.Text, "投稿を削除し、投稿者に対応しました")
+	assert.NotContains(t, email.Text, "suspend_author")
+
+	// 警告のみの場合は投稿を削除していない
+	email, err = r.Render(Message{Kind: KindReportOutcome, To: "to@example.com", Data: ReportOutcomeData{PostTitle: "朝市", Outcome: "warn_author"}})
+	require.NoError(t, err)
+	assert.Contains(t, email.Text, "投稿者に対応しました")
+	assert.NotContains(t, email.Text, "削除")
+
+	email, err = r.Render(Message{Kind: KindModerationNotice, To: "to@example.com", Locale: LocaleJA, Data: ModerationNoticeData{PostTitle: "朝市", PostKept: true, Sanction: "warning"}})
+	require.NoError(t, err)
+	assert.Contains(t, email.Text, "警告します")
+	assert.NotContains(t, email.Text, "削除")
+
+	email, err = r.Render(Message{Kind: KindModerationNotice, To: "to@example.com", Locale: LocaleEN, Data: sampleData[KindModerationNotice]})
+	require.NoError(t, err)
+	assert.Equal(t, "[Kojan Map] Your account has been suspended", email.Subject)
+	assert.Contains(t, email.Text, "until Apr 8, 2025 00:00")
+
+	// 投稿によらない利用禁止
+	email, err = r.Render(Message{Kind: KindModerationNotice, To: "to@example.com", Locale: LocaleJA, Data: ModerationNoticeData{Sanction: "ban", Note: "なりすまし"}})
+	require.NoError(t, err)
+	assert.Equal(t, "【Kojan-Map】アカウントの利用禁止のお知らせ", email.Subject)
+	assert.Contains(t, email.Text, "無期限で停止")
+	assert.NotContains(t, email.Text, "投稿「")
+}
+
+// TestMessage_JSONRoundTrip は outbox に保存した通知が同じ型の Data で復元されることを確認します
+func TestMessage_JSONRoundTrip(t *testing.T) {
+	for _, kind := range Kinds() {
+		msg := Message{Kind: kind, To: "to@example.com", Locale: LocaleEN, Data: sampleData[kind]}
+		b, err := json.Marshal(msg)
+		require.NoError(t, err, kind)
+
+		var got Message
+		require.NoError(t, json.Unmarshal(b, &got), kind)
+		assert.Equal(t, msg, got, kind)
+		assert.NoError(t, got.validate(), kind)
+	}
+
+	var got Message
+	assert.Error(t, json.Unmarshal([]byte(`{"kind":"unknown","to":"to@example.com","data":{}}`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`{"kind":"mfa_code","to":"to@example.com","data":"x"}`), &got))
+}
+
+// TestRenderer_EscapesHTML はHTML本文でユーザー入力がエスケープされることを確認します
+func TestRenderer_EscapesHTML(t *testing.T) {
+	r, err := NewRenderer()
+	require.NoError(t, err)
+
+	email, err := r.Render(Message{
+		Kind: KindInquiryReply,
+		To:   "to@example.com",
+		Data: InquiryReplyData{Subject: "<script>alert(1)</script>", Reply: "a & b"},
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, email.HTML, "<script>")
+	assert.Contains(t, email.HTML, "&lt;script&gt;")
+	// テキスト本文はそのまま
+	assert.Contains(t, email.Text, "a & b")
+}
+
+// TestRenderer_Validation は不正なメッセージを拒否することを確認します
+func TestRenderer_Validation(t *testing.T) {
+	r, err := NewRenderer()
+	require.NoError(t, err)
+
+	_, err = r.Render(Message{Kind: KindMFACode, Data: MFACodeData{Code: "1"}})
+	assert.Error(t, err, "recipient is required")
+
+	_, err = r.Render(Message{Kind: "unknown", To: "to@example.com"})
+	assert.Error(t, err)
+
+	_, err = r.Render(Message{Kind: KindMFACode, To: "to@example.com", Data: InquiryReplyData{}})
+	assert.Error(t, err, "data type must match the kind")
+}
+
+// TestRenderer_LocaleFallback は翻訳がない場合に既定の言語で描画することを確認します
+func TestRenderer_LocaleFallback(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/layout.html.tmpl": {Data: []byte(`{{define "layout"}}{{template "content" .Data}}{{end}}`)},
+	}
+	for _, kind := range Kinds() {
+		fsys["templates/ja/"+string(kind)+".txt.tmpl"] = &fstest.MapFile{
+			Data: []byte(`{{define "subject"}}件名{{end}}{{define "body"}}本文{{end}}`),
+		}
+	}
+	r, err := NewRendererFS(fsys)
+	require.NoError(t, err)
+
+	email, err := r.Render(Message{Kind: KindMFACode, To: "to@example.com", Locale: LocaleEN, Data: sampleData[KindMFACode]})
+	require.NoError(t, err)
+	assert.Equal(t, "件名", email.Subject)
+	assert.Empty(t, email.HTML, "html template is optional")
+
+	delete(fsys, "templates/ja/digest.txt.tmpl")
+	_, err = NewRendererFS(fsys)
+	assert.Error(t, err, "default locale templates are required")
+}
+
+// TestSMTPTransport はローカルのSMTPサーバーにマルチパートのメールを送信できることを確認します
+func TestSMTPTransport(t *testing.T) {
+	server, err := smtptest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+
+	r, err := NewRenderer()
+	require.NoError(t, err)
+	transport := NewSMTPTransport(SMTPConfig{Host: server.Host(), Port: server.Port()})
+	n := NewNotifier(r, transport, "Kojan Map <no-reply@example.com>", LocaleJA)
+
+	require.NoError(t, n.SendMFACode("biz@example.com", "654321"))
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "no-reply@example.com", messages[0].From)
+	assert.Equal(t, []string{"biz@example.com"}, messages[0].To)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(messages[0].Data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Kojan-Map 多要素認証コード", subject)
+
+	text, html := readAlternative(t, parsed)
+	assert.Contains(t, text, "654321")
+	assert.Contains(t, text, "5分間有効")
+	assert.Contains(t, html, "654321")
+}
+
+// TestSMTPTransport_ConnectionError は接続できない場合にエラーを返すことを確認します
+func TestSMTPTransport_ConnectionError(t *testing.T) {
+	server, err := smtptest.NewServer()
+	require.NoError(t, err)
+	host, port := server.Host(), server.Port()
+	require.NoError(t, server.Close())
+
+	transport := NewSMTPTransport(SMTPConfig{Host: host, Port: port, Timeout: time.Second})
+	err = transport.Send(context.Background(), "from@example.com", &Email{To: "to@example.com", Subject: "s", Text: "t"})
+	assert.Error(t, err)
+}
+
+// TestFileTransport はメールを .eml として保存することを確認します
+func TestFileTransport(t *testing.T) {
+	dir := t.TempDir()
+	transport, err := NewFileTransport(dir)
+	require.NoError(t, err)
+
+	err = transport.Send(context.Background(), "from@example.com", &Email{To: "to@example.com", Subject: "件名", Text: "本文\n"})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "to@example.com", parsed.Header.Get("To"))
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "本文\r\n", string(body))
+}
+
+// TestLogTransport はログに出力することを確認します
+func TestLogTransport(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := NewRenderer()
+	require.NoError(t, err)
+	n := NewNotifier(r, NewLogTransport(log.New(&buf, "", 0)), "from@example.com", LocaleEN)
+
+	require.NoError(t, n.Send(context.Background(), Message{
+		Kind: KindApplicationApproved,
+		To:   "biz@example.com",
+		Data: ApplicationDecisionData{BusinessName: "Kojan Shop"},
+	}))
+	assert.Contains(t, buf.String(), "biz@example.com")
+	assert.Contains(t, buf.String(), "approved")
+}
+
+// TestNewFromEnv は環境変数からトランスポートを選択することを確認します
+func TestNewFromEnv(t *testing.T) {
+	t.Run("defaults to log in dev", func(t *testing.T) {
+		t.Setenv("NOTIFY_TRANSPORT", "")
+		n, err := NewFromEnv(true)
+		require.NoError(t, err)
+		assert.IsType(t, &LogTransport{}, n.transport)
+		assert.Equal(t, LocaleJA, n.locale)
+	})
+
+	t.Run("requires a real transport outside dev", func(t *testing.T) {
+		t.Setenv("NOTIFY_TRANSPORT", "")
+		t.Setenv("SMTP_ENABLED", "")
+		_, err := NewFromEnv(false)
+		assert.Error(t, err)
+
+		for _, kind := range []string{TransportLog, TransportFile} {
+			t.Setenv("NOTIFY_TRANSPORT", kind)
+			t.Setenv("NOTIFY_FILE_DIR", t.TempDir())
+			_, err := NewFromEnv(false)
+			assert.Error(t, err, kind)
+		}
+	})
+
+	t.Run("legacy production setting uses SES", func(t *testing.T) {
+		t.Setenv("NOTIFY_TRANSPORT", "")
+		t.Setenv("SMTP_ENABLED", "true")
+		t.Setenv("SES_FROM_EMAIL", "no-reply@example.com")
+		n, err := NewFromEnv(false)
+		require.NoError(t, err)
+		assert.IsType(t, &SESTransport{}, n.transport)
+		assert.Equal(t, "no-reply@example.com", n.from)
+	})
+
+	t.Run("smtp requires host and sender", func(t *testing.T) {
+		t.Setenv("NOTIFY_TRANSPORT", "smtp")
+		t.Setenv("SMTP_HOST", "")
+		_, err := NewFromEnv(false)
+		assert.Error(t, err)
+
+		t.Setenv("SMTP_HOST", "localhost")
+		t.Setenv("MAIL_FROM", "")
+		t.Setenv("SES_FROM_EMAIL", "")
+		_, err = NewFromEnv(false)
+		assert.Error(t, err)
+
+		t.Setenv("MAIL_FROM", "no-reply@example.com")
+		t.Setenv("NOTIFY_LOCALE", "en")
+		n, err := NewFromEnv(false)
+		require.NoError(t, err)
+		assert.IsType(t, &SMTPTransport{}, n.transport)
+		assert.Equal(t, LocaleEN, n.locale)
+	})
+
+	t.Run("file in dev", func(t *testing.T) {
+		t.Setenv("NOTIFY_TRANSPORT", "file")
+		t.Setenv("NOTIFY_FILE_DIR", t.TempDir())
+		n, err := NewFromEnv(true)
+		require.NoError(t, err)
+		assert.IsType(t, &FileTransport{}, n.transport)
+	})
+
+	t.Run("unknown transport", func(t *testing.T) {
+		t.Setenv("NOTIFY_TRANSPORT", "pigeon")
+		_, err := NewFromEnv(true)
+		assert.Error(t, err)
+	})
+}
+
+// readAlternative は multipart/alternative のテキスト・HTMLパートを取り出します
+func readAlternative(t *testing.T, msg *mail.Message) (string, string) {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		// multipart.Reader は quoted-printable を自動でデコードする
+		body, err := io.ReadAll(p)
+		require.NoError(t, err)
+		parts[ct] = string(body)
+	}
+	return parts["text/plain"], parts["text/html"]
+}
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/ses/types"
+)
+
+// SESTransport は AWS SES でメールを送信します
+// クライアントは最初の送信時に一度だけ作成して使い回します
+type SESTransport struct {
+	region string
+
+	once   sync.Once
+	client *ses.Client
+	err    error
+}
+
+// NewSESTransport は SES トランスポートを生成します
+func NewSESTransport(region string) *SESTransport {
+	if region == "" {
+		region = "ap-northeast-1" // デフォルトリージョン
+	}
+	return &SESTransport{region: region}
+}
+
+func (t *SESTransport) sesClient(ctx context.Context) (*ses.Client, error) {
+	t.once.Do(func() {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(t.region))
+		if err != nil {
+			t.err = fmt.Errorf("failed to load AWS config: %w", err)
+			return
+		}
+		t.client = ses.NewFromConfig(cfg)
+	})
+	return t.client, t.err
+}
+
+// Send は SES の SendEmail API でメールを送信します
+func (t *SESTransport) Send(ctx context.Context, from string, email *Email) error {
+	client, err := t.sesClient(ctx)
+	if err != nil {
+		return fmt.Errorf("notification: %w", err)
+	}
+
+	body := &types.Body{
+		Text: &types.Content{Data: aws.String(email.Text), Charset: aws.String("UTF-8")},
+	}
+	if email.HTML != "" {
+		body.Html = &types.Content{Data: aws.String(email.HTML), Charset: aws.String("UTF-8")}
+	}
+
+	_, err = client.SendEmail(ctx, &ses.SendEmailInput{
+		Source:      aws.String(from),
+		Destination: &types.Destination{ToAddresses: []string{email.To}},
+		Message: &types.Message{
+			Subject: &types.Content{Data: aws.String(email.Subject), Charset: aws.String("UTF-8")},
+			Body:    body,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("notification: failed to send email via SES: %w", err)
+	}
+	return nil
+}
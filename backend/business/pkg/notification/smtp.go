@@ -0,0 +1,105 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTPConfig は SMTP サーバーの設定です
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // 空の場合は認証しない
+	Password string
+	// ImplicitTLS は接続時から TLS を使います（465番ポート）
+	// false の場合、サーバーが対応していれば STARTTLS を使います
+	ImplicitTLS bool
+	Timeout     time.Duration // 0 の場合は 10 秒
+}
+
+// SMTPTransport は SMTP でメールを送信します
+type SMTPTransport struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPTransport は SMTP トランスポートを生成します
+func NewSMTPTransport(cfg SMTPConfig) *SMTPTransport {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMTPTransport{cfg: cfg}
+}
+
+// Send は SMTP サーバーにメールを送信します
+func (t *SMTPTransport) Send(ctx context.Context, from string, email *Email) error {
+	raw, err := buildMIME(from, email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(t.cfg.Host, fmt.Sprint(t.cfg.Port))
+	deadline := time.Now().Add(t.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	if t.cfg.ImplicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: t.cfg.Host})
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("notification: failed to connect to SMTP server: %w", err)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, t.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("notification: SMTP handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if !t.cfg.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: t.cfg.Host}); err != nil {
+				return fmt.Errorf("notification: STARTTLS failed: %w", err)
+			}
+		}
+	}
+
+	if t.cfg.Username != "" {
+		// smtp.PlainAuth は TLS 以外では localhost のみ許可する
+		auth := smtp.PlainAuth("", t.cfg.Username, t.cfg.Password, t.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("notification: SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(addressOnly(from)); err != nil {
+		return fmt.Errorf("notification: MAIL FROM rejected: %w", err)
+	}
+	if err := client.Rcpt(email.To); err != nil {
+		return fmt.Errorf("notification: RCPT TO rejected: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("notification: DATA rejected: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return fmt.Errorf("notification: failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("notification: message rejected: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,138 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// templates はメールのテンプレートです
+// templates/<locale>/<kind>.txt.tmpl  件名（"subject"）と本文（"body"）の text/template
+// templates/<locale>/<kind>.html.tmpl HTML本文（"content"）の html/template（layout.html.tmpl に埋め込む）
+//
+//go:embed templates
+var templateFS embed.FS
+
+// Email は描画済みのメールです
+type Email struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string // 空の場合はテキストのみ
+}
+
+// Renderer はテンプレートから Email を作成します
+type Renderer struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// NewRenderer は埋め込みのテンプレートを読み込みます
+func NewRenderer() (*Renderer, error) {
+	return NewRendererFS(templateFS)
+}
+
+// NewRendererFS は templates ディレクトリを含むファイルシステムからテンプレートを読み込みます
+// すべての Kind について既定の言語のテキストテンプレートが必要です
+func NewRendererFS(fsys fs.FS) (*Renderer, error) {
+	r := &Renderer{
+		text: map[string]*texttemplate.Template{},
+		html: map[string]*htmltemplate.Template{},
+	}
+
+	layout, err := fs.ReadFile(fsys, "templates/layout.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to read layout: %w", err)
+	}
+
+	for _, locale := range []Locale{LocaleJA, LocaleEN} {
+		for _, kind := range Kinds() {
+			key := templateKey(locale, kind)
+			base := fmt.Sprintf("templates/%s/%s", locale, kind)
+
+			if src, err := fs.ReadFile(fsys, base+".txt.tmpl"); err == nil {
+				t, err := texttemplate.New(key).Parse(string(src))
+				if err != nil {
+					return nil, fmt.Errorf("notification: failed to parse %s.txt.tmpl: %w", base, err)
+				}
+				if t.Lookup("subject") == nil || t.Lookup("body") == nil {
+					return nil, fmt.Errorf("notification: %s.txt.tmpl must define subject and body", base)
+				}
+				r.text[key] = t
+			} else if locale == DefaultLocale {
+				return nil, fmt.Errorf("notification: missing template %s.txt.tmpl", base)
+			}
+
+			if src, err := fs.ReadFile(fsys, base+".html.tmpl"); err == nil {
+				t, err := htmltemplate.New(key).Parse(string(layout))
+				if err == nil {
+					_, err = t.Parse(string(src))
+				}
+				if err != nil {
+					return nil, fmt.Errorf("notification: failed to parse %s.html.tmpl: %w", base, err)
+				}
+				r.html[key] = t
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// Render はメッセージを描画します
+// 指定した言語のテンプレートがない場合は既定の言語で描画します
+func (r *Renderer) Render(msg Message) (*Email, error) {
+	if err := msg.validate(); err != nil {
+		return nil, err
+	}
+
+	locale := msg.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	key := templateKey(locale, msg.Kind)
+	if _, ok := r.text[key]; !ok {
+		locale = DefaultLocale
+		key = templateKey(locale, msg.Kind)
+	}
+
+	text := r.text[key]
+	var subject, body bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", msg.Data); err != nil {
+		return nil, fmt.Errorf("notification: failed to render subject: %w", err)
+	}
+	if err := text.ExecuteTemplate(&body, "body", msg.Data); err != nil {
+		return nil, fmt.Errorf("notification: failed to render body: %w", err)
+	}
+
+	email := &Email{
+		To:      msg.To,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+	}
+
+	if html, ok := r.html[key]; ok {
+		var buf bytes.Buffer
+		if err := html.ExecuteTemplate(&buf, "layout", layoutData{Subject: email.Subject, Lang: string(locale), Data: msg.Data}); err != nil {
+			return nil, fmt.Errorf("notification: failed to render html: %w", err)
+		}
+		email.HTML = buf.String()
+	}
+
+	return email, nil
+}
+
+// layoutData は HTML レイアウトに渡すデータです（各テンプレートの content には .Data を渡す）
+type layoutData struct {
+	Subject string
+	Lang    string
+	Data    interface{}
+}
+
+func templateKey(locale Locale, kind Kind) string {
+	return string(locale) + "/" + string(kind)
+}
@@ -0,0 +1,143 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport は描画済みのメールを配送します
+type Transport interface {
+	Send(ctx context.Context, from string, email *Email) error
+}
+
+// buildMIME は RFC 5322 形式のメッセージを作成します（HTMLがある場合は multipart/alternative）
+func buildMIME(from string, email *Email, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+
+	header("From", from)
+	header("To", email.To)
+	header("Subject", mime.QEncoding.Encode("UTF-8", email.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+
+	if email.HTML == "" {
+		header("Content-Type", `text/plain; charset="UTF-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, email.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	header("Content-Type", `multipart/alternative; boundary="`+mw.Boundary()+`"`)
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", email.Text},
+		{"text/html", email.HTML},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + `; charset="UTF-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(s, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func messageID(from string) string {
+	domain := "kojan-map.local"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// LogTransport はメールを送信せずログに出力します（開発用）
+type LogTransport struct {
+	logger *log.Logger
+}
+
+// NewLogTransport はログに出力するトランスポートを生成します（nil の場合は標準のロガー）
+func NewLogTransport(logger *log.Logger) *LogTransport {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogTransport{logger: logger}
+}
+
+// Send はメールの内容をログに出力します
+func (t *LogTransport) Send(_ context.Context, from string, email *Email) error {
+	t.logger.Printf("[DEV] mail to %s: %s\n%s", email.To, email.Subject, email.Text)
+	return nil
+}
+
+// FileTransport はメールを .eml ファイルとして保存します（開発・結合テスト用）
+type FileTransport struct {
+	dir string
+	mu  sync.Mutex
+	seq int
+}
+
+// NewFileTransport はディレクトリにメールを保存するトランスポートを生成します
+func NewFileTransport(dir string) (*FileTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("notification: failed to create mail directory: %w", err)
+	}
+	return &FileTransport{dir: dir}, nil
+}
+
+// Send はメールをファイルに書き出します
+func (t *FileTransport) Send(_ context.Context, from string, email *Email) error {
+	now := time.Now()
+	raw, err := buildMIME(from, email, now)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.seq++
+	name := fmt.Sprintf("%s-%04d.eml", now.Format("20060102-150405"), t.seq)
+	t.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(t.dir, name), raw, 0o644); err != nil {
+		return fmt.Errorf("notification: failed to write mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+// Google の発行者・公開鍵の既定値
+const (
+	GoogleIssuer  = "https://accounts.google.com"
+	GoogleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+)
+
+// googleIssuers は Google が iss に設定する値です（スキームなしの形式も使われる）
+var googleIssuers = []string{GoogleIssuer, "accounts.google.com"}
+
+// ErrEmailNotVerified はメールアドレスが確認されていないアカウントのトークンです
+var ErrEmailNotVerified = errors.New("email is not verified")
+
+// VerifierConfig はIDトークン検証器の設定です
+type VerifierConfig struct {
+	Issuers    []string     // 受け付ける iss（いずれかに一致）
+	JWKSURL    string       // 署名検証に使う公開鍵（JWKS）の取得先
+	Audiences  []string     // 受け付ける aud（OAuth クライアントID）
+	HTTPClient *http.Client // JWKS の取得に使うクライアント（nilの場合は10秒タイムアウト）
+	Leeway     time.Duration
+	// EmailOptional の場合、email のないトークンも受け付ける（LINE などメールアドレスの提供が任意のプロバイダー）
+	// 確認されていないメールアドレスはエラーにせず、クレームから除く
+	EmailOptional bool
+}
+
+// GoogleConfig は Google のIDトークンを検証する設定を返します
+func GoogleConfig(clientID string) VerifierConfig {
+	return VerifierConfig{
+		Issuers:   googleIssuers,
+		JWKSURL:   GoogleJWKSURL,
+		Audiences: []string{clientID},
+		Leeway:    time.Minute,
+	}
+}
+
+// ConfigFromEnv は Google の設定に環境変数 GOOGLE_ISSUER・GOOGLE_JWKS_URL を反映した設定を返します
+// 検証用の OIDC 発行者（oidctest）やオフライン環境のミラーを使う場合に設定します
+func ConfigFromEnv(clientID string) VerifierConfig {
+	return ConfigWithOverrides(clientID, os.Getenv("GOOGLE_ISSUER"), os.Getenv("GOOGLE_JWKS_URL"))
+}
+
+// ConfigWithOverrides は Google の設定の発行者・JWKS の取得先を置き換えた設定を返します（空の場合は既定値）
+func ConfigWithOverrides(clientID, issuer, jwksURL string) VerifierConfig {
+	cfg := GoogleConfig(clientID)
+	if issuer != "" {
+		cfg.Issuers = []string{issuer}
+	}
+	if jwksURL != "" {
+		cfg.JWKSURL = jwksURL
+	}
+	return cfg
+}
+
+// IDTokenVerifier は OpenID Connect のIDトークンをネットワークに問い合わせずに検証します
+// 署名は JWKS の公開鍵で検証し、鍵はキャッシュします（Cache-Control の max-age の間、未知の kid の場合は再取得）
+// iss・aud・exp と email_verified を確認します
+type IDTokenVerifier struct {
+	cfg  VerifierConfig
+	now  func() time.Time
+	mu   sync.Mutex
+	keys map[string]crypto.PublicKey
+	// expiresAt までは JWKS を再取得しない（未知の kid の場合は fetchedAt から minRefresh 経過後に再取得）
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+// JWKS キャッシュの期間
+const (
+	defaultJWKSCacheTTL = time.Hour
+	maxJWKSCacheTTL     = 24 * time.Hour
+	minJWKSRefresh      = 30 * time.Second
+)
+
+// NewIDTokenVerifier はIDトークン検証器を生成します
+func NewIDTokenVerifier(cfg VerifierConfig) *IDTokenVerifier {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &IDTokenVerifier{cfg: cfg, now: time.Now}
+}
+
+// idTokenClaims はIDトークンのクレームです
+type idTokenClaims struct {
+	Email         string       `json:"email"`
+	EmailVerified verifiedFlag `json:"email_verified"`
+	Name          string       `json:"name"`
+	Picture       string       `json:"picture"`
+	jwtlib.RegisteredClaims
+}
+
+// verifiedFlag は email_verified を真偽値・文字列のどちらでも受け付けます
+type verifiedFlag bool
+
+func (f *verifiedFlag) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch x := v.(type) {
+	case bool:
+		*f = verifiedFlag(x)
+	case string:
+		*f = verifiedFlag(x == "true")
+	default:
+		*f = false
+	}
+	return nil
+}
+
+// VerifyToken はIDトークンを検証し、クレームを返します
+func (v *IDTokenVerifier) VerifyToken(ctx context.Context, token string) (*TokenClaims, error) {
+	if token == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+	if len(v.cfg.Audiences) == 0 || v.cfg.Audiences[0] == "" {
+		return nil, fmt.Errorf("client id is not configured")
+	}
+
+	claims := &idTokenClaims{}
+	_, err := jwtlib.ParseWithClaims(token, claims,
+		func(t *jwtlib.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			return v.key(ctx, kid)
+		},
+		jwtlib.WithValidMethods([]string{"RS256", "ES256"}),
+		jwtlib.WithExpirationRequired(),
+		jwtlib.WithIssuedAt(),
+		jwtlib.WithLeeway(v.cfg.Leeway),
+		jwtlib.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	if !contains(v.cfg.Issuers, claims.Issuer) {
+		return nil, fmt.Errorf("failed to validate token: unexpected issuer %q", claims.Issuer)
+	}
+	if !v.audienceAllowed(claims.Audience) {
+		return nil, fmt.Errorf("failed to validate token: audience does not match client id")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("failed to validate token: sub is missing")
+	}
+	email := claims.Email
+	if v.cfg.EmailOptional {
+		if !claims.EmailVerified {
+			email = ""
+		}
+	} else {
+		if email == "" {
+			return nil, fmt.Errorf("email claim not found in token")
+		}
+		if !claims.EmailVerified {
+			return nil, ErrEmailNotVerified
+		}
+	}
+
+	result := &TokenClaims{
+		Sub:           claims.Subject,
+		Email:         email,
+		EmailVerified: email != "",
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+		Issuer:        claims.Issuer,
+		Expiration:    claims.ExpiresAt.Unix(),
+	}
+	if len(claims.Audience) > 0 {
+		result.AUD = claims.Audience[0]
+	}
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Unix()
+	}
+	return result, nil
+}
+
+func (v *IDTokenVerifier) audienceAllowed(aud jwtlib.ClaimStrings) bool {
+	for _, a := range aud {
+		if contains(v.cfg.Audiences, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// key は kid に対応する公開鍵を返します
+func (v *IDTokenVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if v.keys == nil || now.After(v.expiresAt) {
+		if err := v.refresh(ctx, now); err != nil {
+			if v.keys == nil {
+				return nil, err
+			}
+			// 取得できない間はキャッシュ済みの鍵で検証を続け、少し待ってから再取得する
+			v.expiresAt = now.Add(minJWKSRefresh)
+		}
+	}
+	if k, ok := v.keys[kid]; ok {
+		return k, nil
+	}
+	// 鍵のローテーション直後は未知の kid が届くため、間隔を空けて再取得する
+	if now.Sub(v.fetchedAt) >= minJWKSRefresh {
+		if err := v.refresh(ctx, now); err != nil {
+			return nil, err
+		}
+		if k, ok := v.keys[kid]; ok {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// refresh は JWKS を取得します（呼び出し側で mu を保持）
+func (v *IDTokenVerifier) refresh(ctx context.Context, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	resp, err := v.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			// 未対応の鍵は無視する（ほかの鍵で検証できるため）
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("failed to parse JWKS: no usable keys")
+	}
+
+	v.keys = keys
+	v.fetchedAt = now
+	v.expiresAt = now.Add(cacheTTL(resp.Header.Get("Cache-Control")))
+	return nil
+}
+
+// cacheTTL は Cache-Control の max-age を返します
+func cacheTTL(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil || secs <= 0 {
+			break
+		}
+		ttl := time.Duration(secs) * time.Second
+		if ttl > maxJWKSCacheTTL {
+			ttl = maxJWKSCacheTTL
+		}
+		return ttl
+	}
+	return defaultJWKSCacheTTL
+}
+
+// jwk は JWKS の1件です（RSA と P-256 の EC 鍵に対応）
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	if k.Use != "" && k.Use != "sig" {
+		return nil, fmt.Errorf("key %q is not a signing key", k.Kid)
+	}
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		// 曲線上の点かを確認する（非圧縮形式 0x04 || X || Y）
+		point := make([]byte, 65)
+		point[0] = 4
+		if len(x.Bytes()) > 32 || len(y.Bytes()) > 32 {
+			return nil, fmt.Errorf("invalid EC key")
+		}
+		x.FillBytes(point[1:33])
+		y.FillBytes(point[33:])
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid EC key")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
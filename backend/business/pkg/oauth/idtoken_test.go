@@ -0,0 +1,215 @@
+package oauth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/oauth/oidctest"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClientID = "test-client.apps.googleusercontent.com"
+
+func newTestIssuer(t *testing.T) (*oidctest.Issuer, *oauth.IDTokenVerifier) {
+	t.Helper()
+	issuer, err := oidctest.NewIssuer()
+	require.NoError(t, err)
+	t.Cleanup(issuer.Close)
+	verifier := oauth.NewIDTokenVerifier(oauth.ConfigWithOverrides(testClientID, issuer.URL(), issuer.JWKSURL()))
+	return issuer, verifier
+}
+
+func TestIDTokenVerifier_Valid(t *testing.T) {
+	issuer, verifier := newTestIssuer(t)
+
+	token, err := issuer.MintIDToken(testClientID, oidctest.Identity{
+		Subject: "1234567890", Email: "user@example.com", EmailVerified: true, Name: "Test User",
+	})
+	require.NoError(t, err)
+
+	claims, err := verifier.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", claims.Sub)
+	assert.Equal(t, "user@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "Test User", claims.Name)
+	assert.Equal(t, issuer.URL(), claims.Issuer)
+	assert.Equal(t, testClientID, claims.AUD)
+
+	// JWKS はキャッシュされ、2回目以降は取得しない
+	_, err = verifier.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, issuer.JWKSRequests())
+}
+
+func TestIDTokenVerifier_Rejects(t *testing.T) {
+	issuer, verifier := newTestIssuer(t)
+	other, err := oidctest.NewIssuer()
+	require.NoError(t, err)
+	defer other.Close()
+
+	now := time.Now()
+	valid := func() jwtlib.MapClaims {
+		return jwtlib.MapClaims{
+			"iss": issuer.URL(), "aud": testClientID, "sub": "1", "email": "user@example.com",
+			"email_verified": true, "iat": now.Unix(), "exp": now.Add(time.Hour).Unix(),
+		}
+	}
+	with := func(key string, value interface{}) jwtlib.MapClaims {
+		c := valid()
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		claims jwtlib.MapClaims
+		signer *oidctest.Issuer
+	}{
+		{"wrong audience", with("aud", "someone-else"), issuer},
+		{"wrong issuer", with("iss", "https://evil.example.com"), issuer},
+		{"expired", with("exp", now.Add(-2*time.Hour).Unix()), issuer},
+		{"missing exp", with("exp", nil), issuer},
+		{"issued in the future", with("iat", now.Add(time.Hour).Unix()), issuer},
+		{"email not verified", with("email_verified", false), issuer},
+		{"email_verified missing", with("email_verified", nil), issuer},
+		{"missing email", with("email", nil), issuer},
+		{"missing subject", with("sub", nil), issuer},
+		{"signed by another issuer", valid(), other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.signer.Mint(tt.claims)
+			require.NoError(t, err)
+			_, err = verifier.VerifyToken(context.Background(), token)
+			assert.Error(t, err)
+		})
+	}
+
+	// email_verified は文字列でも受け付ける
+	token, err := issuer.Mint(with("email_verified", "true"))
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(context.Background(), token)
+	assert.NoError(t, err)
+
+	_, err = verifier.VerifyToken(context.Background(), "")
+	assert.Error(t, err)
+	_, err = verifier.VerifyToken(context.Background(), "not-a-jwt")
+	assert.Error(t, err)
+}
+
+func TestIDTokenVerifier_KeyRotation(t *testing.T) {
+	issuer, verifier := newTestIssuer(t)
+
+	token, err := issuer.MintIDToken(testClientID, oidctest.Identity{Subject: "1", Email: "user@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+
+	// 未知の kid の場合は JWKS を再取得する
+	require.NoError(t, issuer.RotateKey())
+	token, err = issuer.MintIDToken(testClientID, oidctest.Identity{Subject: "1", Email: "user@example.com", EmailVerified: true})
+	require.NoError(t, err)
+
+	// 直前に取得したばかりの場合は再取得しない（不正な kid による取得の連発を防ぐ）
+	_, err = verifier.VerifyToken(context.Background(), token)
+	assert.Error(t, err)
+	assert.Equal(t, 1, issuer.JWKSRequests())
+
+	fresh := oauth.NewIDTokenVerifier(oauth.ConfigWithOverrides(testClientID, issuer.URL(), issuer.JWKSURL()))
+	_, err = fresh.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+}
+
+func TestIDTokenVerifier_ClientIDRequired(t *testing.T) {
+	issuer, _ := newTestIssuer(t)
+	verifier := oauth.NewIDTokenVerifier(oauth.ConfigWithOverrides("", issuer.URL(), issuer.JWKSURL()))
+
+	token, err := issuer.MintIDToken("", oidctest.Identity{Subject: "1", Email: "user@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(context.Background(), token)
+	assert.Error(t, err)
+}
+
+func TestIDTokenVerifier_JWKSUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	issuer, _ := newTestIssuer(t)
+	verifier := oauth.NewIDTokenVerifier(oauth.ConfigWithOverrides(testClientID, issuer.URL(), srv.URL))
+
+	token, err := issuer.MintIDToken(testClientID, oidctest.Identity{Subject: "1", Email: "user@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(context.Background(), token)
+	assert.ErrorContains(t, err, "JWKS")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("GOOGLE_ISSUER", "")
+	t.Setenv("GOOGLE_JWKS_URL", "")
+	cfg := oauth.ConfigFromEnv("client")
+	assert.Equal(t, oauth.GoogleJWKSURL, cfg.JWKSURL)
+	assert.Contains(t, cfg.Issuers, oauth.GoogleIssuer)
+	assert.Contains(t, cfg.Issuers, "accounts.google.com")
+	assert.Equal(t, []string{"client"}, cfg.Audiences)
+
+	t.Setenv("GOOGLE_ISSUER", "http://127.0.0.1:9999")
+	t.Setenv("GOOGLE_JWKS_URL", "http://127.0.0.1:9999/jwks")
+	cfg = oauth.ConfigFromEnv("client")
+	assert.Equal(t, []string{"http://127.0.0.1:9999"}, cfg.Issuers)
+	assert.Equal(t, "http://127.0.0.1:9999/jwks", cfg.JWKSURL)
+}
+
+func TestIDTokenVerifier_EmailOptional(t *testing.T) {
+	issuer, _ := newTestIssuer(t)
+	cfg := oauth.ConfigWithOverrides(testClientID, issuer.URL(), issuer.JWKSURL())
+	cfg.EmailOptional = true
+	verifier := oauth.NewIDTokenVerifier(cfg)
+
+	// メールアドレスのないトークンも受け付ける
+	token, err := issuer.MintIDToken(testClientID, oidctest.Identity{Subject: "line-user"})
+	require.NoError(t, err)
+	claims, err := verifier.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "line-user", claims.Sub)
+	assert.Empty(t, claims.Email)
+	assert.False(t, claims.EmailVerified)
+
+	// 確認されていないメールアドレスは使わない
+	token, err = issuer.MintIDToken(testClientID, oidctest.Identity{Subject: "line-user", Email: "user@example.com"})
+	require.NoError(t, err)
+	claims, err = verifier.VerifyToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Email)
+}
+
+func TestRegistry(t *testing.T) {
+	_, verifier := newTestIssuer(t)
+	registry := oauth.NewRegistry(
+		&oauth.Provider{Name: "line", Issuer: "https://access.line.me", ClientID: "line-client", Verifier: verifier},
+		&oauth.Provider{Name: oauth.ProviderGoogle, Issuer: oauth.GoogleIssuer, ClientID: testClientID, Verifier: verifier},
+	)
+
+	p, err := registry.Get("line")
+	require.NoError(t, err)
+	assert.Equal(t, "https://access.line.me", p.Issuer)
+
+	_, err = registry.Get("facebook")
+	assert.ErrorIs(t, err, oauth.ErrUnknownProvider)
+
+	list := registry.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, oauth.ProviderGoogle, list[0].Name)
+}
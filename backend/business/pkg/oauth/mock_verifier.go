@@ -25,12 +25,13 @@ func (v *MockGoogleTokenVerifier) VerifyToken(ctx context.Context, token string)
 	// For test purposes, use the token as a hint for the subject
 	// In real tests, you'd pass specific tokens that encode user information
 	claims := &TokenClaims{
-		Sub:        "user123", // Default test user
-		Email:      "test@example.com",
-		Name:       "Test User",
-		Issuer:     "https://accounts.google.com",
-		AUD:        v.clientID,
-		Expiration: int64(9999999999), // Far future
+		Sub:           "user123", // Default test user
+		Email:         "test@example.com",
+		EmailVerified: true,
+		Name:          "Test User",
+		Issuer:        "https://accounts.google.com",
+		AUD:           v.clientID,
+		Expiration:    int64(9999999999), // Far future
 	}
 
 	return claims, nil
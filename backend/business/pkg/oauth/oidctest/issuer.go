@@ -0,0 +1,164 @@
+// Package oidctest はIDトークンを発行するプロセス内の OpenID Connect 発行者です。
+// Google の代わりに開発環境・結合テストで使用し、ネットワークに接続せずにログインを試せます。
+// 本番環境では使用しないでください。
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+// Issuer はループバックアドレスで待ち受ける OIDC 発行者です
+// /.well-known/openid-configuration と JWKS を公開し、Mint で署名済みのIDトークンを発行します
+type Issuer struct {
+	srv          *httptest.Server
+	mu           sync.RWMutex
+	keys         []signingKey // 先頭が現在の署名鍵
+	jwksRequests atomic.Int64
+}
+
+type signingKey struct {
+	kid  string
+	priv *rsa.PrivateKey
+}
+
+// NewIssuer は発行者を起動します（Close で停止してください）
+func NewIssuer() (*Issuer, error) {
+	i := &Issuer{}
+	if err := i.RotateKey(); err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", i.handleDiscovery)
+	mux.HandleFunc("/jwks", i.handleJWKS)
+	i.srv = httptest.NewServer(mux)
+	return i, nil
+}
+
+// URL は発行者の識別子（iss）です
+func (i *Issuer) URL() string {
+	return i.srv.URL
+}
+
+// JWKSURL は公開鍵（JWKS）の取得先です
+func (i *Issuer) JWKSURL() string {
+	return i.srv.URL + "/jwks"
+}
+
+// JWKSRequests は JWKS が取得された回数です（キャッシュの確認用）
+func (i *Issuer) JWKSRequests() int {
+	return int(i.jwksRequests.Load())
+}
+
+// Close は発行者を停止します
+func (i *Issuer) Close() {
+	i.srv.Close()
+}
+
+// RotateKey は新しい署名鍵に切り替えます（旧鍵も JWKS に残します）
+func (i *Issuer) RotateKey() error {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return fmt.Errorf("oidctest: failed to generate key: %w", err)
+	}
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return fmt.Errorf("oidctest: failed to generate key id: %w", err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys = append([]signingKey{{kid: hex.EncodeToString(kid), priv: priv}}, i.keys...)
+	return nil
+}
+
+// Identity はIDトークンに含めるユーザー情報です
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// MintIDToken は audience（クライアントID）向けに1時間有効なIDトークンを発行します
+func (i *Issuer) MintIDToken(audience string, id Identity) (string, error) {
+	now := time.Now()
+	claims := jwtlib.MapClaims{
+		"iss":            i.URL(),
+		"aud":            audience,
+		"sub":            id.Subject,
+		"email":          id.Email,
+		"email_verified": id.EmailVerified,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+	if id.Name != "" {
+		claims["name"] = id.Name
+	}
+	if id.Picture != "" {
+		claims["picture"] = id.Picture
+	}
+	return i.Mint(claims)
+}
+
+// Mint は任意のクレームに現在の鍵で署名します（期限切れ・不正な aud などのテスト用）
+func (i *Issuer) Mint(claims jwtlib.MapClaims) (string, error) {
+	i.mu.RLock()
+	key := i.keys[0]
+	i.mu.RUnlock()
+
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
+	token.Header["kid"] = key.kid
+	signed, err := token.SignedString(key.priv)
+	if err != nil {
+		return "", fmt.Errorf("oidctest: failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+func (i *Issuer) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"issuer":                                i.URL(),
+		"jwks_uri":                              i.JWKSURL(),
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"subject_types_supported":               []string{"public"},
+		"response_types_supported":              []string{"id_token"},
+	})
+}
+
+func (i *Issuer) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	i.jwksRequests.Add(1)
+	i.mu.RLock()
+	keys := make([]map[string]string, 0, len(i.keys))
+	for _, k := range i.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": k.kid,
+			"n":   base64.RawURLEncoding.EncodeToString(k.priv.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.priv.E)).Bytes()),
+		})
+	}
+	i.mu.RUnlock()
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	writeJSON(w, map[string]interface{}{"keys": keys})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,59 @@
+package oauth
+
+import (
+	"errors"
+	"sort"
+)
+
+// ProviderGoogle は Google のプロバイダー名です
+const ProviderGoogle = "google"
+
+// ErrUnknownProvider は設定されていないプロバイダーが指定された場合のエラーです
+var ErrUnknownProvider = errors.New("unknown login provider")
+
+// Provider はログインに使う OpenID Connect プロバイダーです
+type Provider struct {
+	Name     string        // API で指定する名前（google, line など）
+	Issuer   string        // 連携情報に保存する発行者（検証用の発行者に差し替えても変わらない）
+	ClientID string        // IDトークンの aud
+	Verifier TokenVerifier // IDトークンの検証器
+}
+
+// Registry は設定済みのプロバイダーの一覧です
+type Registry struct {
+	providers map[string]*Provider
+}
+
+// NewRegistry はプロバイダーの一覧を生成します（同じ名前は後のものが優先）
+func NewRegistry(providers ...*Provider) *Registry {
+	r := &Registry{providers: make(map[string]*Provider, len(providers))}
+	for _, p := range providers {
+		if p != nil && p.Name != "" && p.Verifier != nil {
+			r.providers[p.Name] = p
+		}
+	}
+	return r
+}
+
+// Get は名前に対応するプロバイダーを返します
+func (r *Registry) Get(name string) (*Provider, error) {
+	if r != nil {
+		if p, ok := r.providers[name]; ok {
+			return p, nil
+		}
+	}
+	return nil, ErrUnknownProvider
+}
+
+// List はプロバイダーを名前順に返します
+func (r *Registry) List() []*Provider {
+	if r == nil {
+		return nil
+	}
+	list := make([]*Provider, 0, len(r.providers))
+	for _, p := range r.providers {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
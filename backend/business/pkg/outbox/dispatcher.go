@@ -0,0 +1,280 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Handler はメッセージを配信します
+// 再試行しても成功しないエラーは Permanent で包んで返すと、すぐに配信不能になります
+type Handler func(ctx context.Context, msg *Message) error
+
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent は再試行しないエラーであることを示します（宛先の誤り・不正なペイロードなど）
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent は Permanent で包まれたエラーかを返します
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Backoff は再試行の間隔です（BaseDelay * 2^(試行回数-1)、MaxDelay まで）
+type Backoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Jitter    float64 // 間隔をランダムに増減する割合（0.2 なら ±20%）
+}
+
+// DefaultBackoff は再試行間隔の既定値です（10秒、20秒、40秒…最大1時間）
+var DefaultBackoff = Backoff{BaseDelay: 10 * time.Second, MaxDelay: time.Hour, Jitter: 0.2}
+
+// Delay は attempts 回目の配信に失敗した後、次の配信までの間隔を返します
+func (b Backoff) Delay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := b.BaseDelay
+	for i := 1; i < attempts && delay < b.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+	if b.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * b.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// Config は Dispatcher の設定です（0 の項目は既定値）
+type Config struct {
+	Interval  time.Duration // 配信待ちを確認する間隔（既定 5秒）
+	BatchSize int           // 1回に取得する件数（既定 20）
+	Lease     time.Duration // 配信中として確保する時間（既定 2分）
+	Timeout   time.Duration // 1件の配信のタイムアウト（既定 30秒）
+	Backoff   Backoff       // 再試行の間隔（既定 DefaultBackoff）
+}
+
+func (c Config) withDefaults() Config {
+	if c.Interval <= 0 {
+		c.Interval = 5 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 20
+	}
+	if c.Lease <= 0 {
+		c.Lease = 2 * time.Minute
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	if c.Backoff.BaseDelay <= 0 {
+		c.Backoff = DefaultBackoff
+	}
+	return c
+}
+
+// queue は Dispatcher が使う outbox テーブルの操作です
+type queue interface {
+	claim(ctx context.Context, limit int, lease time.Duration) ([]Message, error)
+	complete(ctx context.Context, msg *Message) error
+}
+
+// Dispatcher は outbox のメッセージをバックグラウンドで配信します
+// 複数のプロセスで起動しても、同じメッセージを同時に配信することはありません
+type Dispatcher struct {
+	store    *Store
+	queue    queue
+	cfg      Config
+	now      func() time.Time
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	started  bool
+	once     sync.Once
+}
+
+// NewDispatcher は配信ワーカーを生成します
+// 配信先は Handle で topic ごとに登録し、Start で配信を開始します
+func NewDispatcher(db *gorm.DB, cfg Config) *Dispatcher {
+	store := NewStore(db)
+	return newDispatcher(store, store, cfg)
+}
+
+func newDispatcher(store *Store, q queue, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		store:    store,
+		queue:    q,
+		cfg:      cfg.withDefaults(),
+		now:      time.Now,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Handle は topic のメッセージの配信先を登録します
+func (d *Dispatcher) Handle(topic string, h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[topic] = h
+}
+
+// Store は配信ワーカーが使うストアを返します（管理画面・Enqueuer 用）
+func (d *Dispatcher) Store() *Store {
+	return d.store
+}
+
+// Enqueue はメッセージを保存し、すぐに配信を試みます（Enqueuer の実装）
+func (d *Dispatcher) Enqueue(ctx context.Context, msg *Message) error {
+	if err := d.store.Enqueue(ctx, msg); err != nil {
+		return err
+	}
+	d.Wake()
+	return nil
+}
+
+// Wake は次の確認間隔を待たずに配信待ちを確認します
+// トランザクションで Enqueue した後、コミットしてから呼び出します
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start はバックグラウンドで配信を開始します（起動直後に一度実行し、停止中に溜まった分を配信する）
+func (d *Dispatcher) Start() {
+	d.started = true
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.cfg.Interval)
+		defer ticker.Stop()
+
+		d.run()
+		for {
+			select {
+			case <-ticker.C:
+				d.run()
+			case <-d.wake:
+				d.run()
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop は配信を停止し、配信中の処理の完了を待ちます
+func (d *Dispatcher) Stop() {
+	d.once.Do(func() {
+		close(d.stop)
+	})
+	if d.started {
+		<-d.done
+	}
+}
+
+func (d *Dispatcher) run() {
+	for {
+		n, err := d.RunOnce(context.Background())
+		if err != nil {
+			log.Printf("[outbox] dispatch failed: %v", err)
+			return
+		}
+		// 1回分を使い切った場合は続けて取得する
+		if n < d.cfg.BatchSize {
+			return
+		}
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+	}
+}
+
+// RunOnce は配信期限を過ぎたメッセージを1回分配信し、処理した件数を返します
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	messages, err := d.queue.claim(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+	for i := range messages {
+		msg := &messages[i]
+		d.deliver(ctx, msg)
+		if err := d.queue.complete(ctx, msg); err != nil {
+			log.Printf("[outbox] %v", err)
+		}
+	}
+	return len(messages), nil
+}
+
+// deliver は配信を行い、結果に応じて msg の状態を更新します
+func (d *Dispatcher) deliver(ctx context.Context, msg *Message) {
+	var err error
+	switch {
+	case msg.Attempts > msg.MaxAttempts:
+		// 最後の配信中に停止し、lease が切れて再取得された場合
+		err = Permanent(fmt.Errorf("delivery lease expired after %d attempts", msg.MaxAttempts))
+	default:
+		d.mu.RLock()
+		h, ok := d.handlers[msg.Topic]
+		d.mu.RUnlock()
+		if !ok {
+			// 配信先を登録したバージョンで処理されるよう、再試行に回す
+			err = fmt.Errorf("no handler for topic %q", msg.Topic)
+			break
+		}
+		deliverCtx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+		err = safeCall(deliverCtx, h, msg)
+		cancel()
+	}
+
+	now := d.now()
+	if err == nil {
+		msg.Status = StatusDelivered
+		msg.DeliveredAt = &now
+		msg.LastError = ""
+		return
+	}
+
+	msg.LastError = err.Error()
+	if IsPermanent(err) || msg.Attempts >= msg.MaxAttempts {
+		msg.Status = StatusDead
+		log.Printf("[outbox] message %d (%s) is dead after %d attempts: %v", msg.ID, msg.Topic, msg.Attempts, err)
+		return
+	}
+	msg.Status = StatusPending
+	msg.NextAttemptAt = now.Add(d.cfg.Backoff.Delay(msg.Attempts))
+}
+
+// safeCall は配信先の panic をエラーとして扱います
+func safeCall(ctx context.Context, h Handler, msg *Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return h(ctx, msg)
+}
@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"kojan-map/business/pkg/notification"
+)
+
+// 組み込みの topic
+const (
+	TopicEmail = "email" // notification.Message をメールで送信
+)
+
+// NewEmail はメール通知のメッセージを作成します
+// MFAコードなど再利用されると困る内容は、配信後にペイロードを消去します
+func NewEmail(key string, n notification.Message) (*Message, error) {
+	msg, err := NewMessage(TopicEmail, key, n)
+	if err != nil {
+		return nil, err
+	}
+	msg.RedactOnDelivery = n.Kind == notification.KindMFACode
+	return msg, nil
+}
+
+// EmailHandler は TopicEmail のメッセージを notifier で送信します
+func EmailHandler(notifier notification.NotificationService) Handler {
+	return func(ctx context.Context, msg *Message) error {
+		var n notification.Message
+		if err := json.Unmarshal(msg.Payload, &n); err != nil {
+			return Permanent(fmt.Errorf("invalid email payload: %w", err))
+		}
+		return notifier.Send(ctx, n)
+	}
+}
@@ -0,0 +1,269 @@
+// Package outbox はメールなどの外部への送信を確実に行うためのトランザクショナルアウトボックスです。
+//
+// 送信内容は業務データの変更と同じトランザクションで outbox テーブルに保存し、
+// Dispatcher がバックグラウンドで配信します。配信に失敗したメッセージは指数バックオフで再試行し、
+// 上限に達したものは dead（配信不能）として残すため、管理画面から確認・再送できます。
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Status はメッセージの配信状態です
+type Status string
+
+const (
+	StatusPending    Status = "pending"    // 配信待ち（再試行待ちを含む）
+	StatusProcessing Status = "processing" // 配信中
+	StatusDelivered  Status = "delivered"  // 配信済み
+	StatusDead       Status = "dead"       // 再試行の上限に達した、または再試行しても成功しないエラー
+)
+
+// IsValidStatus は定義済みの状態かを返します
+func IsValidStatus(s Status) bool {
+	switch s {
+	case StatusPending, StatusProcessing, StatusDelivered, StatusDead:
+		return true
+	}
+	return false
+}
+
+// DefaultMaxAttempts は再試行を含めた配信の最大回数の既定値です
+const DefaultMaxAttempts = 8
+
+// Message は outbox テーブルの行です
+type Message struct {
+	ID             int64           `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Topic          string          `gorm:"column:topic" json:"topic"`
+	IdempotencyKey string          `gorm:"column:idempotencyKey" json:"idempotencyKey"`
+	Payload        json.RawMessage `gorm:"column:payload" json:"payload,omitempty"`
+	Status         Status          `gorm:"column:status" json:"status"`
+	Attempts       int             `gorm:"column:attempts;default:0" json:"attempts"`
+	MaxAttempts    int             `gorm:"column:maxAttempts" json:"maxAttempts"`
+	NextAttemptAt  time.Time       `gorm:"column:nextAttemptAt" json:"nextAttemptAt"`
+	LockedUntil    *time.Time      `gorm:"column:lockedUntil" json:"-"`
+	LastError      string          `gorm:"column:lastError" json:"lastError,omitempty"`
+	// RedactOnDelivery が true の場合、配信後にペイロードを消去します（MFAコードなど）
+	RedactOnDelivery bool       `gorm:"column:redactOnDelivery;default:false" json:"redactOnDelivery"`
+	DeliveredAt      *time.Time `gorm:"column:deliveredAt" json:"deliveredAt,omitempty"`
+	CreatedAt        time.Time  `gorm:"column:createdAt;autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time  `gorm:"column:updatedAt;autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName はテーブル名を返します
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// NewMessage は topic 宛てのメッセージを作成します
+// key は冪等キーです。同じキーのメッセージは一度だけ保存され、配信先にも渡されます
+func NewMessage(topic, key string, payload interface{}) (*Message, error) {
+	if topic == "" || key == "" {
+		return nil, fmt.Errorf("outbox: topic and idempotency key are required")
+	}
+	if len(key) > 191 {
+		return nil, fmt.Errorf("outbox: idempotency key is too long")
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("outbox: failed to encode payload: %w", err)
+	}
+	return &Message{
+		Topic:          topic,
+		IdempotencyKey: key,
+		Payload:        body,
+		MaxAttempts:    DefaultMaxAttempts,
+	}, nil
+}
+
+// Enqueue はメッセージを outbox に保存します
+// 業務データの変更と同じトランザクション（tx）で呼び出すと、変更が確定した場合にだけ配信されます
+// 同じ冪等キーのメッセージが既にある場合は何もしません
+func Enqueue(tx *gorm.DB, msg *Message) error {
+	if msg.Topic == "" || msg.IdempotencyKey == "" {
+		return fmt.Errorf("outbox: topic and idempotency key are required")
+	}
+	msg.Status = StatusPending
+	if msg.MaxAttempts <= 0 {
+		msg.MaxAttempts = DefaultMaxAttempts
+	}
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = time.Now()
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotencyKey"}},
+		DoNothing: true,
+	}).Create(msg).Error
+	if err != nil {
+		return fmt.Errorf("outbox: failed to enqueue %s message: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Enqueuer はトランザクションを使わずにメッセージを追加する呼び出し側のためのインターフェースです
+// 業務データを変更するトランザクションがある場合は Enqueue を使用してください
+type Enqueuer interface {
+	Enqueue(ctx context.Context, msg *Message) error
+}
+
+// 管理画面向けのエラー
+var (
+	ErrNotFound      = errors.New("outbox message not found")
+	ErrNotReplayable = errors.New("only dead messages can be replayed")
+)
+
+// Filter は一覧の絞り込み条件です
+type Filter struct {
+	Status   Status // 空の場合はすべて
+	Topic    string // 空の場合はすべて
+	Page     int
+	PageSize int
+}
+
+// Store は outbox テーブルの読み書きを行います
+type Store struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// NewStore は outbox のストアを生成します
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db, now: time.Now}
+}
+
+// List は条件に一致するメッセージを新しい順に取得します
+func (s *Store) List(ctx context.Context, f Filter) ([]Message, int64, error) {
+	query := s.db.WithContext(ctx).Model(&Message{})
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.Topic != "" {
+		query = query.Where("topic = ?", f.Topic)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("outbox: failed to count messages: %w", err)
+	}
+
+	var messages []Message
+	err := query.Order("id DESC").
+		Offset((f.Page - 1) * f.PageSize).
+		Limit(f.PageSize).
+		Find(&messages).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("outbox: failed to list messages: %w", err)
+	}
+	return messages, total, nil
+}
+
+// Get はメッセージを取得します
+func (s *Store) Get(ctx context.Context, id int64) (*Message, error) {
+	var msg Message
+	err := s.db.WithContext(ctx).Where("id = ?", id).Take(&msg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("outbox: failed to get message %d: %w", id, err)
+	}
+	return &msg, nil
+}
+
+// Replay は配信不能になったメッセージを再試行回数を戻して配信待ちにします
+func (s *Store) Replay(ctx context.Context, id int64) (*Message, error) {
+	result := s.db.WithContext(ctx).Model(&Message{}).
+		Where("id = ? AND status = ?", id, StatusDead).
+		Updates(map[string]interface{}{
+			"status":        StatusPending,
+			"attempts":      0,
+			"nextAttemptAt": s.now(),
+			"lockedUntil":   nil,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("outbox: failed to replay message %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := s.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotReplayable
+	}
+	return s.Get(ctx, id)
+}
+
+// Enqueue はメッセージを保存します（Enqueuer の実装）
+func (s *Store) Enqueue(ctx context.Context, msg *Message) error {
+	return Enqueue(s.db.WithContext(ctx), msg)
+}
+
+// claim は配信期限を過ぎたメッセージを最大 limit 件取得し、lease の間ほかのワーカーが取得しないよう配信中にします
+// 配信中のまま lease が切れたメッセージ（配信中にプロセスが停止した場合など）も再取得します
+func (s *Store) claim(ctx context.Context, limit int, lease time.Duration) ([]Message, error) {
+	now := s.now()
+	var messages []Message
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND nextAttemptAt <= ?) OR (status = ? AND lockedUntil <= ?)",
+				StatusPending, now, StatusProcessing, now).
+			Order("nextAttemptAt").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(messages))
+		for i := range messages {
+			ids[i] = messages[i].ID
+		}
+		lockedUntil := now.Add(lease)
+		// 試行回数は配信前に加算する（配信中に停止しても回数に含める）
+		if err := tx.Model(&Message{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":      StatusProcessing,
+			"attempts":    gorm.Expr("attempts + 1"),
+			"lockedUntil": lockedUntil,
+		}).Error; err != nil {
+			return err
+		}
+		for i := range messages {
+			messages[i].Status = StatusProcessing
+			messages[i].Attempts++
+			messages[i].LockedUntil = &lockedUntil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox: failed to claim messages: %w", err)
+	}
+	return messages, nil
+}
+
+// complete は配信結果を保存します
+func (s *Store) complete(ctx context.Context, msg *Message) error {
+	updates := map[string]interface{}{
+		"status":        msg.Status,
+		"nextAttemptAt": msg.NextAttemptAt,
+		"lastError":     msg.LastError,
+		"deliveredAt":   msg.DeliveredAt,
+		"lockedUntil":   nil,
+	}
+	if msg.Status == StatusDelivered && msg.RedactOnDelivery {
+		updates["payload"] = nil
+	}
+	// 配信中に管理画面から再送された場合などは上書きしない
+	err := s.db.WithContext(ctx).Model(&Message{}).
+		Where("id = ? AND status = ? AND attempts = ?", msg.ID, StatusProcessing, msg.Attempts).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("outbox: failed to update message %d: %w", msg.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+//go:build integration
+// +build integration
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupOutboxDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		dsn = "root:root@tcp(localhost:3306)/kojanmap_test?parseTime=true&charset=utf8mb4&loc=Local"
+	}
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err, "データベース接続に失敗")
+	// テーブルはメインモジュールの migrations で作成済みのものを使う
+	db.Exec("TRUNCATE TABLE outbox")
+	return db
+}
+
+func TestOutbox_EnqueueInTransaction(t *testing.T) {
+	db := setupOutboxDB(t)
+	ctx := context.Background()
+
+	// ロールバックしたトランザクションのメッセージは配信されない
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		msg, err := NewMessage("test", "rolled-back", map[string]string{"a": "b"})
+		require.NoError(t, err)
+		require.NoError(t, Enqueue(tx, msg))
+		return errors.New("rollback")
+	})
+
+	msg, err := NewMessage("test", "committed", map[string]string{"a": "b"})
+	require.NoError(t, err)
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error { return Enqueue(tx, msg) }))
+
+	// 同じ冪等キーは一度だけ保存される
+	dup, err := NewMessage("test", "committed", map[string]string{"a": "c"})
+	require.NoError(t, err)
+	require.NoError(t, Enqueue(db, dup))
+
+	messages, total, err := NewStore(db).List(ctx, Filter{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "committed", messages[0].IdempotencyKey)
+	assert.JSONEq(t, `{"a":"b"}`, string(messages[0].Payload))
+}
+
+func TestOutbox_DispatchDeadLetterAndReplay(t *testing.T) {
+	db := setupOutboxDB(t)
+	ctx := context.Background()
+	store := NewStore(db)
+
+	fail := true
+	d := NewDispatcher(db, Config{Backoff: Backoff{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}})
+	d.Handle("test", func(context.Context, *Message) error {
+		if fail {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	msg, err := NewMessage("test", "dead-letter", nil)
+	require.NoError(t, err)
+	msg.MaxAttempts = 2
+	msg.RedactOnDelivery = true
+	require.NoError(t, d.Enqueue(ctx, msg))
+
+	for i := 0; i < 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+		n, err := d.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	}
+
+	got, err := store.Get(ctx, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDead, got.Status)
+	assert.Equal(t, 2, got.Attempts)
+	assert.Equal(t, "unavailable", got.LastError)
+
+	// 配信不能になったものは取得されない
+	n, err := d.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	fail = false
+	replayed, err := store.Replay(ctx, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, replayed.Status)
+	assert.Zero(t, replayed.Attempts)
+
+	_, err = d.RunOnce(ctx)
+	require.NoError(t, err)
+	got, err = store.Get(ctx, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDelivered, got.Status)
+	assert.Empty(t, got.Payload, "payload should be redacted after delivery")
+
+	_, err = store.Replay(ctx, msg.ID)
+	assert.ErrorIs(t, err, ErrNotReplayable)
+	_, err = store.Replay(ctx, 999999)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
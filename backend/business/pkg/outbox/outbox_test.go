@@ -0,0 +1,211 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"kojan-map/business/pkg/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQueue はDBを使わない queue です
+type fakeQueue struct {
+	mu        sync.Mutex
+	pending   []Message
+	completed []Message
+}
+
+func (q *fakeQueue) claim(_ context.Context, limit int, _ time.Duration) ([]Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if limit > len(q.pending) {
+		limit = len(q.pending)
+	}
+	claimed := append([]Message(nil), q.pending[:limit]...)
+	q.pending = q.pending[limit:]
+	for i := range claimed {
+		claimed[i].Status = StatusProcessing
+		claimed[i].Attempts++
+	}
+	return claimed, nil
+}
+
+func (q *fakeQueue) complete(_ context.Context, msg *Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.completed = append(q.completed, *msg)
+	return nil
+}
+
+func newTestDispatcher(q *fakeQueue, now time.Time) *Dispatcher {
+	d := newDispatcher(nil, q, Config{Backoff: Backoff{BaseDelay: time.Second, MaxDelay: time.Minute}})
+	d.now = func() time.Time { return now }
+	return d
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+	assert.Equal(t, 10*time.Second, b.Delay(0))
+	assert.Equal(t, 10*time.Second, b.Delay(1))
+	assert.Equal(t, 20*time.Second, b.Delay(2))
+	assert.Equal(t, 40*time.Second, b.Delay(3))
+	assert.Equal(t, time.Minute, b.Delay(4))
+	assert.Equal(t, time.Minute, b.Delay(100))
+
+	b.Jitter = 0.5
+	for i := 0; i < 50; i++ {
+		d := b.Delay(2)
+		assert.GreaterOrEqual(t, d, 10*time.Second)
+		assert.LessOrEqual(t, d, 30*time.Second)
+	}
+}
+
+func TestDispatcher_Outcomes(t *testing.T) {
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeQueue{pending: []Message{
+		{ID: 1, Topic: "ok", MaxAttempts: 3},
+		{ID: 2, Topic: "flaky", MaxAttempts: 3, Attempts: 1},
+		{ID: 3, Topic: "flaky", MaxAttempts: 3, Attempts: 2},
+		{ID: 4, Topic: "broken", MaxAttempts: 3},
+		{ID: 5, Topic: "unknown", MaxAttempts: 3},
+		{ID: 6, Topic: "ok", MaxAttempts: 3, Attempts: 3},
+		{ID: 7, Topic: "panics", MaxAttempts: 3},
+	}}
+	d := newTestDispatcher(q, now)
+	d.Handle("ok", func(context.Context, *Message) error { return nil })
+	d.Handle("flaky", func(context.Context, *Message) error { return errors.New("temporary") })
+	d.Handle("broken", func(context.Context, *Message) error { return Permanent(errors.New("bad address")) })
+	d.Handle("panics", func(context.Context, *Message) error { panic("boom") })
+
+	n, err := d.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	got := map[int64]Message{}
+	for _, m := range q.completed {
+		got[m.ID] = m
+	}
+
+	assert.Equal(t, StatusDelivered, got[1].Status)
+	require.NotNil(t, got[1].DeliveredAt)
+
+	// 2回目の失敗は 2秒後に再試行
+	assert.Equal(t, StatusPending, got[2].Status)
+	assert.Equal(t, now.Add(2*time.Second), got[2].NextAttemptAt)
+	assert.Equal(t, "temporary", got[2].LastError)
+
+	// 上限に達したら配信不能
+	assert.Equal(t, StatusDead, got[3].Status)
+	assert.Equal(t, 3, got[3].Attempts)
+
+	// 再試行しないエラーは1回目で配信不能
+	assert.Equal(t, StatusDead, got[4].Status)
+	assert.Equal(t, "bad address", got[4].LastError)
+
+	// 配信先がない topic は再試行
+	assert.Equal(t, StatusPending, got[5].Status)
+	assert.Contains(t, got[5].LastError, "no handler")
+
+	// 最後の配信中に lease が切れたものは配信しない
+	assert.Equal(t, StatusDead, got[6].Status)
+	assert.Contains(t, got[6].LastError, "lease expired")
+
+	assert.Equal(t, StatusPending, got[7].Status)
+	assert.Contains(t, got[7].LastError, "panicked")
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	q := &fakeQueue{}
+	d := newDispatcher(nil, q, Config{Interval: time.Hour})
+
+	delivered := make(chan int64, 1)
+	d.Handle("ok", func(_ context.Context, msg *Message) error {
+		delivered <- msg.ID
+		return nil
+	})
+	d.Start()
+	defer d.Stop()
+
+	q.mu.Lock()
+	q.pending = append(q.pending, Message{ID: 42, Topic: "ok", MaxAttempts: 1})
+	q.mu.Unlock()
+	d.Wake()
+
+	select {
+	case id := <-delivered:
+		assert.Equal(t, int64(42), id)
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not delivered after Wake")
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	_, err := NewMessage("", "key", nil)
+	assert.Error(t, err)
+	_, err = NewMessage("email", "", nil)
+	assert.Error(t, err)
+
+	msg, err := NewEmail("mfa:1", notification.Message{
+		Kind: notification.KindMFACode,
+		To:   "to@example.com",
+		Data: notification.MFACodeData{Code: "123456", ExpiresInMins: 5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TopicEmail, msg.Topic)
+	assert.True(t, msg.RedactOnDelivery, "MFA codes must not be kept after delivery")
+	assert.Equal(t, DefaultMaxAttempts, msg.MaxAttempts)
+
+	msg, err = NewEmail("inquiry:1", notification.Message{
+		Kind: notification.KindInquiryReply,
+		To:   "to@example.com",
+		Data: notification.InquiryReplyData{Subject: "件名", Reply: "返信"},
+	})
+	require.NoError(t, err)
+	assert.False(t, msg.RedactOnDelivery)
+}
+
+// recordingNotifier は送信した通知を記録します
+type recordingNotifier struct {
+	sent []notification.Message
+	err  error
+}
+
+func (n *recordingNotifier) Send(_ context.Context, msg notification.Message) error {
+	n.sent = append(n.sent, msg)
+	return n.err
+}
+
+func (n *recordingNotifier) SendMFACode(email, code string) error {
+	return n.Send(context.Background(), notification.Message{
+		Kind: notification.KindMFACode, To: email, Data: notification.MFACodeData{Code: code},
+	})
+}
+
+func TestEmailHandler(t *testing.T) {
+	notifier := &recordingNotifier{}
+	h := EmailHandler(notifier)
+
+	msg, err := NewEmail("mfa:1", notification.Message{
+		Kind: notification.KindMFACode,
+		To:   "to@example.com",
+		Data: notification.MFACodeData{Code: "123456", ExpiresInMins: 5},
+	})
+	require.NoError(t, err)
+	require.NoError(t, h(context.Background(), msg))
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, notification.MFACodeData{Code: "123456", ExpiresInMins: 5}, notifier.sent[0].Data)
+
+	notifier.err = errors.New("smtp down")
+	err = h(context.Background(), msg)
+	assert.Error(t, err)
+	assert.False(t, IsPermanent(err))
+
+	err = h(context.Background(), &Message{Topic: TopicEmail, Payload: json.RawMessage(`{"kind":"nope"}`)})
+	assert.True(t, IsPermanent(err))
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"kojan-map/business/pkg/contextkeys"
+	"kojan-map/business/pkg/errors"
+	"kojan-map/business/pkg/response"
+)
+
+// KeyFunc はリクエストからバケットのキーを決めます。
+type KeyFunc func(c *gin.Context) string
+
+// ByClientIP はクライアントIPをキーにします（公開ルート用）。
+// X-Forwarded-For を使うのは gin の SetTrustedProxies で信頼したプロキシからのリクエストのみです。
+func ByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser は認証済みユーザーの googleId をキーにします。
+// 認証ミドルウェアの後に使用し、googleId がない場合はクライアントIPを使います。
+// メインサーバーの認証ミドルウェア（gin の googleId）とビジネスの AuthMiddleware（context）の両方に対応します。
+func ByUser(c *gin.Context) string {
+	if googleID := c.GetString("googleId"); googleID != "" {
+		return "user:" + googleID
+	}
+	if userID, ok := contextkeys.GetUserID(c.Request.Context()); ok && userID != "" {
+		return "user:" + userID
+	}
+	return ByClientIP(c)
+}
+
+// Middleware はルートグループにレート制限をかけるミドルウェアを返します。
+// name はグループ名で、グループごとに別のバケットを使います。
+// store が nil の場合は何もしません（レート制限無効）。
+func Middleware(store Store, name string, limit Limit, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store == nil {
+			c.Next()
+			return
+		}
+
+		d, err := store.Take(c.Request.Context(), name+":"+key(c), limit)
+		if err != nil {
+			// ストア障害時はリクエストを止めない
+			log.Printf("rate limit store error: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+
+		if !d.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(d.RetryAfter)))
+			response.SendError(c, errors.NewAPIError(errors.ErrTooManyRequests, "too many requests"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kojan-map/business/pkg/contextkeys"
+)
+
+func newTestStore(now *time.Time) *MemoryStore {
+	s := NewMemoryStore()
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestStore(&now)
+	limit := PerMinute(2)
+
+	for i := 0; i < 2; i++ {
+		d, err := s.Take(context.Background(), "k", limit)
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+	}
+
+	d, _ := s.Take(context.Background(), "k", limit)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, 0, d.Remaining)
+	assert.Equal(t, 30*time.Second, d.RetryAfter)
+
+	// 別キーは独立している
+	d, _ = s.Take(context.Background(), "other", limit)
+	assert.True(t, d.Allowed)
+
+	// 30秒で1トークン回復する
+	now = now.Add(30 * time.Second)
+	d, _ = s.Take(context.Background(), "k", limit)
+	assert.True(t, d.Allowed)
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestStore(&now)
+
+	_, _ = s.Take(context.Background(), "idle", PerMinute(1))
+	now = now.Add(2 * time.Minute)
+	_, _ = s.Take(context.Background(), "active", PerMinute(1))
+
+	assert.NotContains(t, s.buckets, "idle")
+	assert.Contains(t, s.buckets, "active")
+}
+
+func TestMiddleware_TooManyRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/auth/google", Middleware(NewMemoryStore(), "auth", PerMinute(1), ByClientIP), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/google", nil))
+		return w
+	}
+
+	w := do()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = do()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Equal(t, "60", w.Header().Get("X-RateLimit-Reset"))
+	assert.Contains(t, w.Body.String(), "TOO_MANY_REQUESTS")
+}
+
+func TestMiddleware_NilStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/limited", Middleware(nil, "test", PerMinute(1), ByClientIP), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/limited", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestByUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newContext := func() *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.RemoteAddr = "203.0.113.1:1234"
+		return c
+	}
+
+	// メインサーバーの認証ミドルウェア
+	c := newContext()
+	c.Set("googleId", "user-1")
+	assert.Equal(t, "user:user-1", ByUser(c))
+
+	// ビジネスの AuthMiddleware
+	c = newContext()
+	c.Request = c.Request.WithContext(contextkeys.WithUserID(c.Request.Context(), "user-2"))
+	assert.Equal(t, "user:user-2", ByUser(c))
+
+	// 未認証の場合はクライアントIP
+	assert.Equal(t, "ip:203.0.113.1", ByUser(newContext()))
+}
+
+func TestByClientIP_UntrustedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(nil))
+	r.POST("/auth/google", Middleware(NewMemoryStore(), "auth", PerMinute(1), ByClientIP), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	do := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/auth/google", nil)
+		req.RemoteAddr = "203.0.113.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// プロキシを信頼しない場合、X-Forwarded-For を変えても同じクライアントとして数える
+	assert.Equal(t, http.StatusOK, do("198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, do("198.51.100.2"))
+}
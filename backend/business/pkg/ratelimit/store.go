@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit はトークンバケットの設定です。
+// Burst 個まで連続で受け付け、その後は Rate（1秒あたり）で回復します。
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute は1分あたり n 回のリミットを返します。
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// PerHour は1時間あたり n 回のリミットを返します。
+func PerHour(n int) Limit {
+	return Limit{Rate: float64(n) / 3600, Burst: n}
+}
+
+// Decision はトークン取得の結果です。
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // 次のトークンが使えるまでの時間（拒否時のみ）
+	Reset      time.Duration // バケットが満タンに戻るまでの時間
+}
+
+// Store はバケットの保存先です。
+// 複数インスタンスで共有する場合は Redis などで実装したものを差し替えます。
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// MemoryStore はプロセス内でバケットを保持する Store です。
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// sweepInterval ごとに満タンに戻ったバケットを破棄します。
+const sweepInterval = time.Minute
+
+// NewMemoryStore は新しい MemoryStore を作成します。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take はキーのバケットからトークンを1つ取り出します。
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	burst := float64(limit.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now, limit: limit}
+		s.buckets[key] = b
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+	b.limit = limit
+
+	d := Decision{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else if limit.Rate > 0 {
+		d.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+	d.Remaining = int(b.tokens)
+	if limit.Rate > 0 {
+		d.Reset = secondsToDuration((burst - b.tokens) / limit.Rate)
+	}
+	return d, nil
+}
+
+// sweep は回復しきったバケットを削除してメモリの増加を防ぎます。
+// 呼び出し元でロックを取得していること。
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(sec float64) time.Duration {
+	return time.Duration(math.Ceil(sec * float64(time.Second)))
+}
@@ -0,0 +1,49 @@
+// Package reportcategory は通報の分類（スパム・個人情報・嫌がらせなど）を定義します。
+//
+// ユーザー・ビジネスの通報作成と、管理画面での投稿ごとの集計で同じ分類を使います。
+// 分類ごとの重みは、管理画面で対応が急がれる投稿を先に表示するために使います。
+package reportcategory
+
+// Category は通報の分類です
+type Category string
+
+const (
+	Spam          Category = "spam"           // スパム・宣伝
+	PersonalInfo  Category = "personal_info"  // 個人情報の掲載
+	Harassment    Category = "harassment"     // 嫌がらせ・誹謗中傷
+	Dangerous     Category = "dangerous"      // 危険な行為・違法な内容
+	WrongLocation Category = "wrong_location" // 場所が間違っている
+	Other         Category = "other"          // その他（コメントで説明）
+)
+
+// all は表示順の分類一覧です
+var all = []Category{Spam, PersonalInfo, Harassment, Dangerous, WrongLocation, Other}
+
+// weights は対応の優先度の重みです（個人情報・危険な内容を優先）
+var weights = map[Category]int{
+	Spam:          1,
+	PersonalInfo:  3,
+	Harassment:    2,
+	Dangerous:     3,
+	WrongLocation: 1,
+	Other:         1,
+}
+
+// All は定義済みの分類を表示順に返します
+func All() []Category {
+	return append([]Category(nil), all...)
+}
+
+// IsValid は定義済みの分類かを返します
+func IsValid(c Category) bool {
+	_, ok := weights[c]
+	return ok
+}
+
+// Weight は分類の重みを返します（未定義の分類は Other と同じ）
+func Weight(c Category) int {
+	if w, ok := weights[c]; ok {
+		return w
+	}
+	return weights[Other]
+}
@@ -0,0 +1,22 @@
+package reportcategory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategories(t *testing.T) {
+	assert.Len(t, All(), 6)
+	for _, c := range All() {
+		assert.True(t, IsValid(c), c)
+		assert.Positive(t, Weight(c), c)
+	}
+	assert.False(t, IsValid("rude"))
+	assert.Equal(t, Weight(Other), Weight("rude"))
+	assert.Greater(t, Weight(Dangerous), Weight(Spam))
+
+	// All は内部の一覧のコピーを返す
+	All()[0] = "changed"
+	assert.Equal(t, Spam, All()[0])
+}
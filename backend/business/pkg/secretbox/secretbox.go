@@ -0,0 +1,92 @@
+// Package secretbox はDBに保存する秘密情報（TOTPシークレット等）を AES-256-GCM で暗号化します
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// KeyEnv は暗号鍵（base64 でエンコードした32バイト）を指定する環境変数です
+const KeyEnv = "MFA_ENCRYPTION_KEY"
+
+// ErrDecrypt は復号に失敗した場合のエラーです（鍵の不一致・改ざん）
+var ErrDecrypt = errors.New("secretbox: failed to decrypt")
+
+// Box は秘密情報の暗号化・復号を行います
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New は32バイトの鍵から Box を生成します
+func New(key []byte) (*Box, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secretbox: key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// NewFromEnv は環境変数 MFA_ENCRYPTION_KEY の鍵から Box を生成します
+// 鍵の指定は必須です。allowDevKey（開発・テスト環境）の場合のみ、未設定ならプロセスごとのランダムな鍵を使用します
+// （再起動すると、それまでに保存したシークレットは復号できなくなります）
+func NewFromEnv(allowDevKey bool) (*Box, error) {
+	encoded := os.Getenv(KeyEnv)
+	if encoded == "" {
+		if !allowDevKey {
+			return nil, fmt.Errorf("%s environment variable is required outside dev/test", KeyEnv)
+		}
+		key := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, fmt.Errorf("secretbox: failed to generate key: %w", err)
+		}
+		log.Printf("WARNING: %s is not set; using a random key for this process (enrolled authenticator apps stop working after a restart)", KeyEnv)
+		return New(key)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be base64 encoded: %w", KeyEnv, err)
+	}
+	return New(key)
+}
+
+// Seal は平文を暗号化し、nonce を先頭に付けて base64 で返します
+func (b *Box) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("secretbox: failed to generate nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open は Seal で暗号化した値を復号します
+func (b *Box) Open(ciphertext string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	size := b.aead.NonceSize()
+	if len(raw) < size {
+		return "", ErrDecrypt
+	}
+	plaintext, err := b.aead.Open(nil, raw[:size], raw[size:], nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,85 @@
+package secretbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBox_SealOpen は暗号化した値が復号でき、毎回異なる暗号文になることを確認します
+func TestBox_SealOpen(t *testing.T) {
+	box, err := New(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	a, err := box.Seal("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	b, err := box.Seal("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b, "nonce must differ per seal")
+	assert.NotContains(t, a, "JBSWY3DPEHPK3PXP")
+
+	plain, err := box.Open(a)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plain)
+}
+
+// TestBox_OpenRejectsWrongKeyAndTampering は別の鍵・改ざんされた値を拒否することを確認します
+func TestBox_OpenRejectsWrongKeyAndTampering(t *testing.T) {
+	box, err := New(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	other, err := New(bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+
+	sealed, err := box.Seal("secret")
+	require.NoError(t, err)
+
+	_, err = other.Open(sealed)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	raw, _ := base64.StdEncoding.DecodeString(sealed)
+	raw[len(raw)-1] ^= 0xff
+	_, err = box.Open(base64.StdEncoding.EncodeToString(raw))
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = box.Open("not base64!")
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+// TestNewFromEnv は環境変数の鍵を使用し、未設定なら開発・テスト環境以外ではエラー、開発・テスト環境ではランダムな鍵になることを確認します
+func TestNewFromEnv(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32))
+	t.Setenv(KeyEnv, key)
+	box, err := NewFromEnv(false)
+	require.NoError(t, err)
+	sealed, err := box.Seal("x")
+	require.NoError(t, err)
+
+	fromKey, err := New(bytes.Repeat([]byte{3}, 32))
+	require.NoError(t, err)
+	plain, err := fromKey.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "x", plain)
+
+	t.Setenv(KeyEnv, "")
+	_, err = NewFromEnv(false)
+	assert.Error(t, err)
+	devBox, err := NewFromEnv(true)
+	require.NoError(t, err)
+	otherDevBox, err := NewFromEnv(true)
+	require.NoError(t, err)
+	// 開発用の鍵は固定値ではなく、生成ごとに異なる
+	devSealed, err := devBox.Seal("x")
+	require.NoError(t, err)
+	_, err = otherDevBox.Open(devSealed)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	t.Setenv(KeyEnv, "not base64!")
+	_, err = NewFromEnv(false)
+	assert.Error(t, err)
+
+	_, err = New([]byte("short"))
+	assert.Error(t, err)
+}
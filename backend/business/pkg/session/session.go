@@ -1,10 +1,15 @@
 package session
 
 import (
+	"context"
 	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"kojan-map/business/pkg/kvstore"
 )
 
 // MFASession はMFA認証セッション情報を保持します
@@ -18,63 +23,69 @@ type MFASession struct {
 
 // SessionStore はMFAセッションを管理します
 type SessionStore struct {
-	mu       sync.RWMutex
-	sessions map[string]*MFASession
-	ticker   *time.Ticker
-	stopChan chan struct{}
-	stopOnce sync.Once
+	store     kvstore.Store
+	ownsStore bool
+	stopOnce  sync.Once
 }
 
-// NewSessionStore はセッションストアを生成します
+// NewSessionStore はメモリ上のセッションストアを生成します（テスト・単体起動用）
 func NewSessionStore() *SessionStore {
-	store := &SessionStore{
-		sessions: make(map[string]*MFASession),
-		ticker:   time.NewTicker(1 * time.Minute), // 1分ごとにクリーンアップ
-		stopChan: make(chan struct{}),
+	return &SessionStore{
+		store:     kvstore.NewMemoryStore(1 * time.Minute), // 1分ごとにクリーンアップ
+		ownsStore: true,
 	}
+}
 
-	// 期限切れセッションの自動削除
-	go store.cleanupExpiredSessions()
-
-	return store
+// NewSessionStoreWithStore は共有ストアを使うセッションストアを生成します
+// ストアの停止は呼び出し側で行います
+func NewSessionStoreWithStore(store kvstore.Store) *SessionStore {
+	return &SessionStore{store: store}
 }
 
 // CreateSession は新しいMFAセッションを作成します
-func (s *SessionStore) CreateSession(sessionID, gmail, mfaCode, googleID string, ttl time.Duration) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	s.sessions[sessionID] = &MFASession{
+func (s *SessionStore) CreateSession(sessionID, gmail, mfaCode, googleID string, ttl time.Duration) error {
+	session := &MFASession{
 		SessionID: sessionID,
 		Gmail:     gmail,
 		MFACode:   mfaCode,
 		GoogleID:  googleID,
 		ExpiresAt: time.Now().Add(ttl),
 	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("セッションの保存に失敗しました: %w", err)
+	}
+	if err := s.store.Set(context.Background(), sessionKey(sessionID), data, session.ExpiresAt); err != nil {
+		return fmt.Errorf("セッションの保存に失敗しました: %w", err)
+	}
+	return nil
 }
 
 // GetSession はセッションIDからセッション情報を取得します
 func (s *SessionStore) GetSession(sessionID string) (*MFASession, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	session, exists := s.sessions[sessionID]
-	if !exists {
+	data, err := s.store.Get(context.Background(), sessionKey(sessionID))
+	if errors.Is(err, kvstore.ErrNotFound) {
 		return nil, fmt.Errorf("セッションが見つかりません")
 	}
+	if err != nil {
+		return nil, fmt.Errorf("セッションの取得に失敗しました: %w", err)
+	}
+
+	var session MFASession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("セッションの取得に失敗しました: %w", err)
+	}
 
 	if time.Now().After(session.ExpiresAt) {
 		return nil, fmt.Errorf("セッションの有効期限が切れています")
 	}
 
-	return session, nil
+	return &session, nil
 }
 
 // DeleteSession はセッションを削除します
 func (s *SessionStore) DeleteSession(sessionID string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	delete(s.sessions, sessionID)
+	_ = s.store.Delete(context.Background(), sessionKey(sessionID))
 }
 
 // ValidateMFACode はMFAコードを検証します
@@ -91,29 +102,15 @@ func (s *SessionStore) ValidateMFACode(sessionID, mfaCode string) (*MFASession,
 	return session, nil
 }
 
-// cleanupExpiredSessions は期限切れセッションを定期的に削除します
-func (s *SessionStore) cleanupExpiredSessions() {
-	for {
-		select {
-		case <-s.ticker.C:
-			s.mu.Lock()
-			now := time.Now()
-			for id, session := range s.sessions {
-				if now.After(session.ExpiresAt) {
-					delete(s.sessions, id)
-				}
-			}
-			s.mu.Unlock()
-		case <-s.stopChan:
-			return
-		}
-	}
-}
-
 // Stop はセッションストアを停止します
 func (s *SessionStore) Stop() {
 	s.stopOnce.Do(func() {
-		s.ticker.Stop()
-		close(s.stopChan)
+		if s.ownsStore {
+			s.store.Stop()
+		}
 	})
 }
+
+func sessionKey(sessionID string) string {
+	return "mfa-session:" + sessionID
+}
@@ -0,0 +1,106 @@
+// Package totp は認証アプリ（Google Authenticator 等）向けの RFC 6238 ワンタイムパスワードを扱います
+package totp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// Period は1ステップの秒数です
+	Period = 30
+	// Skew は前後に許容するステップ数です（時計のずれ対策）
+	Skew = 1
+	// qrSize はQRコード画像の一辺のピクセル数です
+	qrSize = 256
+)
+
+// validateOpts は発行・検証で共通のパラメータです（認証アプリの既定値に合わせる）
+var validateOpts = totp.ValidateOpts{
+	Period:    Period,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// Enrollment は登録開始時に利用者へ提示する情報です
+type Enrollment struct {
+	Secret string // base32 のシークレット（手入力用）
+	URI    string // otpauth:// URI
+	QRCode []byte // URI を埋め込んだ PNG 画像
+}
+
+// QRCodeDataURL はQRコードを <img src> にそのまま使える data URL で返します
+func (e *Enrollment) QRCodeDataURL() string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(e.QRCode)
+}
+
+// Generate は新しいシークレットを生成し、URI とQRコードを作成します
+func Generate(issuer, account string) (*Enrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      Period,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+
+	img, err := key.Image(qrSize, qrSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	return &Enrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: buf.Bytes(),
+	}, nil
+}
+
+// Code は時刻 t におけるコードを返します
+func Code(secret string, t time.Time) (string, error) {
+	return totp.GenerateCodeCustom(secret, t, validateOpts)
+}
+
+// Step は時刻 t が属するステップ番号を返します
+func Step(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// Validate はコードを前後 Skew ステップの範囲で検証し、一致したステップ番号を返します
+// afterStep 以前のステップに一致したコードは再利用とみなして拒否します
+func Validate(secret, code string, t time.Time, afterStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != validateOpts.Digits.Length() {
+		return 0, false
+	}
+
+	current := Step(t)
+	for offset := int64(-Skew); offset <= Skew; offset++ {
+		step := current + offset
+		if step <= afterStep {
+			continue
+		}
+		expected, err := Code(secret, time.Unix(step*Period, 0))
+		if err != nil {
+			return 0, false
+		}
+		if expected == code {
+			return step, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+package totp
+
+import (
+	"bytes"
+	"encoding/base32"
+	"image/png"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret は RFC 6238 付録Bのテストベクタで使われる SHA1 のシークレットです
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// TestCode_RFC6238Vectors は RFC 6238 のテストベクタ（下6桁）と一致することを確認します
+func TestCode_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "T=%d", tt.unix)
+	}
+}
+
+// TestValidate_Window は前後1ステップのみ許容されることを確認します
+func TestValidate_Window(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+
+	for offset := int64(-2); offset <= 2; offset++ {
+		code, err := Code(rfcSecret, time.Unix((current+offset)*Period, 0))
+		require.NoError(t, err)
+
+		step, ok := Validate(rfcSecret, code, now, 0)
+		if offset < -Skew || offset > Skew {
+			assert.False(t, ok, "offset %d should be rejected", offset)
+			continue
+		}
+		assert.True(t, ok, "offset %d should be accepted", offset)
+		assert.Equal(t, current+offset, step)
+	}
+}
+
+// TestValidate_Replay は使用済みステップのコードが拒否されることを確認します
+func TestValidate_Replay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := Code(rfcSecret, now)
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, code, now, 0)
+	require.True(t, ok)
+
+	_, ok = Validate(rfcSecret, code, now, step)
+	assert.False(t, ok, "a code must not be accepted twice")
+
+	// 次のステップのコードは使用できる
+	next, err := Code(rfcSecret, now.Add(Period*time.Second))
+	require.NoError(t, err)
+	_, ok = Validate(rfcSecret, next, now.Add(Period*time.Second), step)
+	assert.True(t, ok)
+}
+
+// TestValidate_Malformed は桁数が異なるコードを拒否することを確認します
+func TestValidate_Malformed(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "12345", "1234567", "94287082"} {
+		_, ok := Validate(rfcSecret, code, now, 0)
+		assert.False(t, ok, "code %q", code)
+	}
+}
+
+// TestGenerate は URI とQRコードが生成されることを確認します
+func TestGenerate(t *testing.T) {
+	e, err := Generate("Kojan Map", "biz@example.com")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, e.Secret)
+
+	u, err := url.Parse(e.URI)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, e.Secret, u.Query().Get("secret"))
+	assert.Equal(t, "Kojan Map", u.Query().Get("issuer"))
+
+	img, err := png.Decode(bytes.NewReader(e.QRCode))
+	require.NoError(t, err)
+	assert.Equal(t, qrSize, img.Bounds().Dx())
+	assert.Contains(t, e.QRCodeDataURL(), "data:image/png;base64,")
+
+	code, err := Code(e.Secret, time.Now())
+	require.NoError(t, err)
+	_, ok := Validate(e.Secret, code, time.Now(), 0)
+	assert.True(t, ok)
+}
@@ -0,0 +1,29 @@
+package validate
+
+// CorporateNumber reports whether s is a valid 13-digit Japanese corporate number (法人番号)
+// 先頭の1桁は検査用数字で、残りの12桁（基礎番号）から次の式で求めます
+//
+//	検査用数字 = 9 - (Σ(n=1..12) Pn × Qn を 9 で割った余り)
+//	Pn: 基礎番号の最下位の桁を1桁目としたときの n 桁目の数字
+//	Qn: n が奇数のとき 1、偶数のとき 2
+func CorporateNumber(s string) bool {
+	if len(s) != 13 {
+		return false
+	}
+	sum := 0
+	for n := 1; n <= 12; n++ {
+		c := s[13-n]
+		if c < '0' || c > '9' {
+			return false
+		}
+		p := int(c - '0')
+		if n%2 == 0 {
+			p *= 2
+		}
+		sum += p
+	}
+	if s[0] < '0' || s[0] > '9' {
+		return false
+	}
+	return int(s[0]-'0') == 9-sum%9
+}
@@ -0,0 +1,30 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorporateNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"national tax agency", "7000012050002", true},
+		{"toyota", "1180301018771", true},
+		{"wrong check digit", "8000012050002", false},
+		{"wrong base digit", "7000012050003", false},
+		{"too short", "700001205000", false},
+		{"too long", "70000120500020", false},
+		{"hyphenated", "7-000012-050002", false},
+		{"full-width digits", "７０００ ０１２０５０００２", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CorporateNumber(tt.input))
+		})
+	}
+}
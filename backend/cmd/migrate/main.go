@@ -0,0 +1,135 @@
+// Command migrate はデータベースのスキーマを番号付きの SQL マイグレーションで更新します。
+//
+//	go run ./cmd/migrate up [N]       未適用のマイグレーションを N 件（省略時はすべて）適用
+//	go run ./cmd/migrate down [N]     最後に適用したマイグレーションを N 件（省略時は1件）戻す
+//	go run ./cmd/migrate status       マイグレーションごとの適用日時を表示
+//	go run ./cmd/migrate create NAME  次の番号の NAME.up.sql / NAME.down.sql を作成
+//	go run ./cmd/migrate baseline N   マイグレーション導入前に作成したデータベースで、N までを実行せずに適用済みとして記録
+//
+// 接続先はサーバーと同じ環境変数（DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME）で指定します。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"kojan-map/migrations"
+	"kojan-map/shared/config"
+	"kojan-map/shared/migrate"
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `Usage: migrate [flags] <command> [args]
+
+Commands:
+  up [N]        apply N pending migrations (all if omitted)
+  down [N]      revert the N most recently applied migrations (1 if omitted)
+  status        show which migrations have been applied
+  create NAME   create an empty migration pair in -dir
+  baseline N    record migrations up to version N as applied without running them
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+func main() {
+	dir := flag.String("dir", "migrations", "directory to create migrations in")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command, args := flag.Arg(0), flag.Args()[1:]
+
+	// create はファイルを作成するだけなのでデータベースに接続しない
+	if command == "create" {
+		if len(args) != 1 {
+			log.Fatal("usage: migrate create NAME")
+		}
+		up, down, err := migrate.Create(*dir, args[0])
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", up, down)
+		return
+	}
+
+	cfg := config.Load()
+	db := config.ConnectDB(cfg)
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(steps(args, 0))
+		for _, m := range applied {
+			fmt.Printf("Applied %s\n", m)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+	case "down":
+		reverted, err := migrator.Down(steps(args, 1))
+		for _, m := range reverted {
+			fmt.Printf("Reverted %s\n", m)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No applied migrations")
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+	case "baseline":
+		if len(args) != 1 {
+			log.Fatal("usage: migrate baseline N")
+		}
+		recorded, err := migrator.Baseline(int64(steps(args, 0)))
+		for _, m := range recorded {
+			fmt.Printf("Recorded %s\n", m)
+		}
+		if err != nil {
+			log.Fatalf("Baseline failed: %v", err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// steps は up / down の件数・baseline のバージョンの引数を返します（省略時は def）
+func steps(args []string, def int) int {
+	if len(args) == 0 {
+		return def
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		log.Fatalf("invalid number of migrations: %s", args[0])
+	}
+	return n
+}
@@ -1601,32 +1601,6 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "kojan-map_shared_models.Report": {
-            "type": "object",
-            "properties": {
-                "deleted": {
-                    "type": "boolean"
-                },
-                "handled": {
-                    "type": "boolean"
-                },
-                "reason": {
-                    "type": "string"
-                },
-                "reportId": {
-                    "type": "integer"
-                },
-                "reportedAt": {
-                    "type": "string"
-                },
-                "reporterGoogleId": {
-                    "type": "string"
-                },
-                "targetPostId": {
-                    "type": "integer"
-                }
-            }
-        },
         "service.DashboardSummary": {
             "type": "object",
             "properties": {
@@ -1748,7 +1722,7 @@ const docTemplate = `{
                 "reports": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/kojan-map_shared_models.Report"
+                        "$ref": "#/definitions/service.ReportDetailResponse"
                     }
                 },
                 "total": {
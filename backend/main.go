@@ -9,13 +9,25 @@ import (
 	"os/signal" // ★追加
 	"time"      // ★追加
 
+	adminrepo "kojan-map/admin/repository"
+	adminservice "kojan-map/admin/service"
+	"kojan-map/business"
+	"kojan-map/business/pkg/accountstatus"
+	"kojan-map/business/pkg/activity"
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/kvstore"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/outbox"
+	"kojan-map/business/pkg/ratelimit"
+	"kojan-map/business/pkg/secretbox"
+	"kojan-map/migrations"
 	"kojan-map/router"
 	"kojan-map/shared/config"
+	"kojan-map/shared/contentfilter"
+	"kojan-map/shared/migrate"
 	userconfig "kojan-map/user/config"
-	usermiddleware "kojan-map/user/middleware"
-
-	// "kojan-map/user/migrations"
-	"kojan-map/user/models"
+	"kojan-map/user/services"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -49,57 +61,65 @@ func main() {
 	// Connect to database
 	db := config.ConnectDB(cfg)
 
-	// DBマイグレーション（dev/test環境のみ）
+	// DBマイグレーション（dev/test環境のみ自動で適用。それ以外は go run ./cmd/migrate up で適用する）
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
 	if cfg.AppEnv == "dev" || cfg.AppEnv == "test" {
-		log.Printf("Current Environment: %s - Running AutoMigrate...", cfg.AppEnv)
-		if err := db.AutoMigrate(
-			&models.User{},
-			&models.Post{},
-			&models.Place{},
-			&models.Genre{},
-			&models.UserReaction{},
-			&models.UserBlock{},
-			&models.Report{},
-			&models.Contact{},
-			&models.BusinessRequest{},
-			&models.Session{}, // Sessionテーブル保証
-		); err != nil {
-			log.Fatalf("DB migration failed: %v", err)
+		log.Printf("Current Environment: %s - Applying migrations...", cfg.AppEnv)
+		applied, err := migrator.Up(0)
+		for _, m := range applied {
+			log.Printf("Applied migration %s", m)
 		}
-
-		// Seed default genres if they don't exist
-		var count int64
-		db.Model(&models.Genre{}).Count(&count)
-		if count == 0 {
-			genres := []models.Genre{
-				{GenreName: "food", Color: "#FF6384"},
-				{GenreName: "event", Color: "#36A2EB"},
-				{GenreName: "scene", Color: "#FFCE56"},
-				{GenreName: "store", Color: "#4BC0C0"},
-				{GenreName: "emergency", Color: "#9966FF"},
-				{GenreName: "other", Color: "#FF9F40"},
-			}
-			for _, genre := range genres {
-				if err := db.Create(&genre).Error; err != nil {
-					log.Fatalf("Failed to seed genre: %v", err)
-				}
-			}
-			log.Println("Default genres seeded.")
+		if err != nil {
+			log.Fatalf("DB migration failed: %v", err)
 		}
-	} else {
-		log.Printf("Current Environment: %s - Skipping AutoMigrate for safety.", cfg.AppEnv)
+	} else if pending, err := migrator.Pending(); err != nil {
+		log.Fatalf("Failed to check migrations: %v", err)
+	} else if len(pending) > 0 {
+		log.Printf("Current Environment: %s - %d pending migrations (run `go run ./cmd/migrate up`), starting with %s", cfg.AppEnv, len(pending), pending[0])
 	}
 
 	// Initialize user-side database context
 	userconfig.DB = db
 
-	// Initialize user-side middleware
-	jwtSecret := cfg.GetJWTSecret()
-	usermiddleware.SetJWTSecret(jwtSecret)
+	// ユーザー・管理者・ビジネスで共通のトークン管理（署名鍵の読み込み）
+	tokens, err := jwt.NewTokenManagerFromConfig(jwt.KeyConfig{
+		Secret:         cfg.JWTSecret,
+		SigningKeyFile: cfg.JWTSigningKeyFile,
+		VerifyKeyFiles: cfg.JWTVerifyKeyFiles,
+		AcceptHS256:    cfg.JWTAcceptHS256,
+	})
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	// Google・LINE などのIDトークンは公開鍵（JWKS）をキャッシュしてオフラインで検証する
+	// DEV_OIDC_ISSUER=true（dev/testのみ）の場合はプロセス内の発行者を各プロバイダーの代わりに使う
+	providers, devIssuer, err := config.NewLoginProviders(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up ID token verification: %v", err)
+	}
+	google, err := providers.Get(oauth.ProviderGoogle)
+	if err != nil {
+		log.Fatalf("Failed to set up ID token verification: %v", err)
+	}
+	verifier := google.Verifier
+
+	// トークン失効・MFAセッションはDBに保存（再起動後・複数レプリカ間でも共有）
+	store := kvstore.NewMySQLStore(db, kvstore.DefaultCleanupInterval)
+	tokens.UseRevocationStore(store)
 
 	// Create Gin router
 	r := gin.Default()
 
+	// X-Forwarded-For は信頼するリバースプロキシ（TRUSTED_PROXIES）から受け取った場合のみ使う
+	// 未設定の場合は接続元のアドレスをクライアントIPとする（ヘッダーの偽装でIP単位の制限を回避できないように）
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// CORS configuration
 	// Configから取得したURL（複数可）を使用するように統一
 	r.Use(cors.New(cors.Config{
@@ -119,9 +139,70 @@ func main() {
 		})
 	})
 
+	// メールの配信ワーカー（送信に失敗したものは再試行し、上限に達したものは管理画面から再送できる）
+	// APP_ENV=dev/test 以外では NOTIFY_TRANSPORT=smtp / ses が必須（MFAコードをログに書き出さない）
+	notifier, err := notification.NewFromEnv(cfg.AppEnv == "dev" || cfg.AppEnv == "test")
+	if err != nil {
+		log.Fatalf("Failed to configure notifications: %v", err)
+	}
+	dispatcher := outbox.NewDispatcher(db, outbox.Config{})
+	dispatcher.Handle(outbox.TopicEmail, outbox.EmailHandler(notifier))
+	dispatcher.Start()
+
 	// Setup routes
-	router.SetupAdminRoutes(r, db, cfg)
-	router.SetupUserRoutes(r, db, cfg)
+	deps := router.Dependencies{
+		DB:            db,
+		Config:        cfg,
+		Tokens:        tokens,
+		ContentFilter: contentfilter.NewService(db),
+		Sessions:      services.NewSessionService(db),
+		Store:         store,
+		Notifier:      notifier,
+		TokenVerifier: verifier,
+		Providers:     providers,
+		Outbox:        dispatcher,
+		Accounts:      accountstatus.NewChecker(db), // 利用停止・利用禁止・削除されたアカウントを認証時に拒否
+		Activity:      activity.NewRecorder(db),     // 認証済みのリクエストから利用日を記録（DAU）
+		Analytics:     adminservice.NewAdminAnalyticsService(db, adminrepo.NewAnalyticsRepository(db)),
+	}
+	// ユーザー・管理者・ビジネスのルートで同じストアを使う（同じクライアントの上限を共有する）
+	if cfg.RateLimitEnabled {
+		deps.RateLimiter = ratelimit.NewMemoryStore()
+	}
+	router.SetupAdminRoutes(r, deps)
+	router.SetupUserRoutes(r, deps)
+	if devIssuer != nil {
+		log.Printf("Development OIDC issuer is running at %s (POST /api/dev/id-token to mint ID tokens)", devIssuer.URL())
+		router.SetupDevRoutes(r, devIssuer, providers)
+	}
+
+	// ビジネス向けAPI（MFAログイン・統計・決済など）を同じサーバーで提供
+	// トークン管理を共有するため、どちらで発行したトークンも利用できる
+	// 認証アプリのシークレットの暗号鍵（APP_ENV=dev/test 以外では MFA_ENCRYPTION_KEY が必須）
+	mfaBox, err := secretbox.NewFromEnv(cfg.AppEnv == "dev" || cfg.AppEnv == "test")
+	if err != nil {
+		log.Fatalf("Failed to load MFA encryption key: %v", err)
+	}
+	businessAuth := business.RegisterRoutes(r, db, business.Options{
+		TokenManager:     tokens,
+		SecretBox:        mfaBox,
+		ContentFilter:    deps.ContentFilter,
+		RateLimiter:      deps.RateLimiter,
+		Store:            store,
+		Notifier:         deps.Notifier,
+		TokenVerifier:    verifier,
+		Outbox:           dispatcher,
+		AccountChecker:   deps.Accounts,
+		ActivityRecorder: deps.Activity,
+	})
+
+	// 予約投稿スケジューラ起動
+	postScheduler := services.NewPostScheduler(db, time.Minute)
+	postScheduler.Start()
+
+	// 管理画面の分析の集計（1時間ごとに今日・昨日を作り直し、集計されていない日を補う）
+	analyticsRollup := adminservice.NewAnalyticsRollup(deps.Analytics, time.Hour)
+	analyticsRollup.Start()
 
 	// Swagger UI endpoint
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -146,6 +227,14 @@ func main() {
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 	log.Println("Shutting down server...")
+	postScheduler.Stop()
+	analyticsRollup.Stop()
+	dispatcher.Stop()
+	businessAuth.Close()
+	store.Stop()
+	if devIssuer != nil {
+		devIssuer.Close()
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
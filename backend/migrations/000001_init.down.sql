@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS `sessions`;
+DROP TABLE IF EXISTS `session`;
+DROP TABLE IF EXISTS `businessReq`;
+DROP TABLE IF EXISTS `business`;
+DROP TABLE IF EXISTS `ask`;
+DROP TABLE IF EXISTS `report`;
+DROP TABLE IF EXISTS `block`;
+DROP TABLE IF EXISTS `reaction`;
+DROP TABLE IF EXISTS `post`;
+DROP TABLE IF EXISTS `place`;
+DROP TABLE IF EXISTS `genre`;
+DROP TABLE IF EXISTS `user`;
@@ -0,0 +1,156 @@
+-- ベースライン: マイグレーション導入前のスキーマ
+-- db/kojanmap_dump.sql のダンプに、当時のバックエンドの AutoMigrate が追加したもの
+-- （sessions テーブル、post.deletedAt・reaction.createdAt・report.deletedAt の列）を加えている
+-- すべて IF NOT EXISTS で作成するため、当時のデータベースに適用しても何も変更しない
+-- 000002 以降は、この時点になかった列・テーブルだけを追加する
+
+CREATE TABLE IF NOT EXISTS `user` (
+  `googleId` varchar(50) NOT NULL,
+  `gmail` varchar(100) NOT NULL,
+  `role` enum('user','business','admin') NOT NULL,
+  `registrationDate` datetime NOT NULL,
+  PRIMARY KEY (`googleId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `genre` (
+  `genreId` int NOT NULL AUTO_INCREMENT,
+  `genreName` enum('food','event','scene','store','emergency','other') NOT NULL,
+  `color` varchar(6) NOT NULL,
+  PRIMARY KEY (`genreId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+INSERT IGNORE INTO `genre` (`genreId`, `genreName`, `color`) VALUES
+  (1, 'food', 'FF2E00'),
+  (2, 'event', 'FFA400'),
+  (3, 'scene', '00E500'),
+  (4, 'store', '008AFF'),
+  (5, 'emergency', 'B600FF'),
+  (6, 'other', 'CDCCD4');
+
+CREATE TABLE IF NOT EXISTS `place` (
+  `placeId` int NOT NULL AUTO_INCREMENT,
+  `numPost` int NOT NULL,
+  `latitude` double NOT NULL,
+  `longitude` double NOT NULL,
+  PRIMARY KEY (`placeId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `post` (
+  `postId` int NOT NULL AUTO_INCREMENT,
+  `placeId` int NOT NULL,
+  `userId` varchar(50) NOT NULL,
+  `postDate` datetime NOT NULL,
+  `title` varchar(50) NOT NULL,
+  `text` text NOT NULL,
+  `postImage` blob,
+  `numReaction` int NOT NULL,
+  `numView` int NOT NULL,
+  `genreId` int NOT NULL,
+  `deletedAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`postId`),
+  KEY `userId` (`userId`),
+  KEY `idx_post_deletedAt` (`deletedAt`),
+  KEY `post_ibfk_1` (`placeId`),
+  KEY `post_ibfk_3` (`genreId`),
+  CONSTRAINT `post_ibfk_1` FOREIGN KEY (`placeId`) REFERENCES `place` (`placeId`),
+  CONSTRAINT `post_ibfk_2` FOREIGN KEY (`userId`) REFERENCES `user` (`googleId`),
+  CONSTRAINT `post_ibfk_3` FOREIGN KEY (`genreId`) REFERENCES `genre` (`genreId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `reaction` (
+  `reactionId` int NOT NULL AUTO_INCREMENT,
+  `userId` varchar(50) NOT NULL,
+  `postId` int NOT NULL,
+  `createdAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`reactionId`),
+  KEY `userId` (`userId`),
+  KEY `reaction_ibfk_2` (`postId`),
+  CONSTRAINT `reaction_ibfk_1` FOREIGN KEY (`userId`) REFERENCES `user` (`googleId`),
+  CONSTRAINT `reaction_ibfk_2` FOREIGN KEY (`postId`) REFERENCES `post` (`postId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `block` (
+  `blockId` int NOT NULL AUTO_INCREMENT,
+  `blockerId` varchar(50) NOT NULL,
+  `blockedId` varchar(50) NOT NULL,
+  PRIMARY KEY (`blockId`),
+  KEY `blockerId` (`blockerId`),
+  KEY `blockedId` (`blockedId`),
+  CONSTRAINT `block_ibfk_1` FOREIGN KEY (`blockerId`) REFERENCES `user` (`googleId`),
+  CONSTRAINT `block_ibfk_2` FOREIGN KEY (`blockedId`) REFERENCES `user` (`googleId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `report` (
+  `reportId` int NOT NULL AUTO_INCREMENT,
+  `userId` varchar(50) NOT NULL,
+  `postId` int NOT NULL,
+  `reason` text NOT NULL,
+  `date` datetime NOT NULL,
+  `reportFlag` tinyint(1) NOT NULL,
+  `removeFlag` tinyint(1) NOT NULL,
+  `deletedAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`reportId`),
+  KEY `userId` (`userId`),
+  KEY `idx_report_deletedAt` (`deletedAt`),
+  KEY `report_ibfk_2` (`postId`),
+  CONSTRAINT `report_ibfk_1` FOREIGN KEY (`userId`) REFERENCES `user` (`googleId`),
+  CONSTRAINT `report_ibfk_2` FOREIGN KEY (`postId`) REFERENCES `post` (`postId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `ask` (
+  `askId` int NOT NULL AUTO_INCREMENT,
+  `date` datetime NOT NULL,
+  `subject` varchar(100) NOT NULL,
+  `text` text NOT NULL,
+  `userId` varchar(50) NOT NULL,
+  `askFlag` tinyint(1) NOT NULL,
+  PRIMARY KEY (`askId`),
+  KEY `userId` (`userId`),
+  CONSTRAINT `ask_ibfk_1` FOREIGN KEY (`userId`) REFERENCES `user` (`googleId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `business` (
+  `businessId` int NOT NULL AUTO_INCREMENT,
+  `businessName` varchar(50) NOT NULL,
+  `kanaBusinessName` varchar(50) NOT NULL,
+  `zipCode` varchar(7) DEFAULT NULL,
+  `address` varchar(100) NOT NULL,
+  `phone` varchar(15) DEFAULT NULL,
+  `registDate` datetime NOT NULL,
+  `profileImage` blob,
+  `userId` varchar(50) NOT NULL,
+  `placeId` int NOT NULL,
+  PRIMARY KEY (`businessId`),
+  KEY `userId` (`userId`),
+  KEY `business_ibfk_2` (`placeId`),
+  CONSTRAINT `business_ibfk_1` FOREIGN KEY (`userId`) REFERENCES `user` (`googleId`),
+  CONSTRAINT `business_ibfk_2` FOREIGN KEY (`placeId`) REFERENCES `place` (`placeId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `businessReq` (
+  `requestId` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(50) NOT NULL,
+  `address` varchar(100) NOT NULL,
+  `phone` varchar(15) DEFAULT NULL,
+  `userId` varchar(50) NOT NULL,
+  PRIMARY KEY (`requestId`),
+  KEY `userId` (`userId`),
+  CONSTRAINT `businessReq_ibfk_1` FOREIGN KEY (`userId`) REFERENCES `user` (`googleId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `session` (
+  `sessionId` varchar(255) NOT NULL,
+  `googleId` varchar(50) NOT NULL,
+  `expiry` datetime NOT NULL,
+  PRIMARY KEY (`sessionId`),
+  KEY `googleId` (`googleId`),
+  CONSTRAINT `session_ibfk_1` FOREIGN KEY (`googleId`) REFERENCES `user` (`googleId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- 以前の AutoMigrate で作成していたセッション（000002 で端末情報・失効日時を追加する）
+CREATE TABLE IF NOT EXISTS `sessions` (
+  `sessionId` varchar(191) NOT NULL,
+  `googleId` varchar(50) DEFAULT NULL,
+  `expiry` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`sessionId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,32 @@
+DROP TABLE IF EXISTS `user_daily_activity`;
+DROP TABLE IF EXISTS `user_sanction`;
+DROP TABLE IF EXISTS `user_identities`;
+DROP TABLE IF EXISTS `sign_in_history`;
+DROP TABLE IF EXISTS `refresh_token`;
+ALTER TABLE `sessions`
+  DROP KEY `idx_sessions_googleId`,
+  DROP COLUMN `revokedAt`,
+  DROP COLUMN `lastSeenAt`,
+  DROP COLUMN `createdAt`,
+  DROP COLUMN `ipAddress`,
+  DROP COLUMN `userAgent`,
+  MODIFY `expiry` datetime(3) DEFAULT NULL,
+  MODIFY `googleId` varchar(50) DEFAULT NULL;
+
+CREATE TABLE `session` (
+  `sessionId` varchar(255) NOT NULL,
+  `googleId` varchar(50) NOT NULL,
+  `expiry` datetime NOT NULL,
+  PRIMARY KEY (`sessionId`),
+  KEY `googleId` (`googleId`),
+  CONSTRAINT `session_ibfk_1` FOREIGN KEY (`googleId`) REFERENCES `user` (`googleId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- gmail のないユーザー（Google 以外のログインプロバイダー）は空文字になる
+UPDATE `user` SET `gmail` = '' WHERE `gmail` IS NULL;
+
+ALTER TABLE `user`
+  DROP KEY `idx_user_registrationDate`,
+  DROP KEY `uq_user_gmail`,
+  DROP COLUMN `deletedAt`,
+  MODIFY `gmail` varchar(100) NOT NULL;
@@ -0,0 +1,102 @@
+-- アカウント: ログインに使う外部アカウントの連携・セッション・リフレッシュトークン・ログイン履歴・利用停止
+
+-- メールアドレスを提供しないログインプロバイダーでは gmail が NULL になる
+ALTER TABLE `user`
+  MODIFY `gmail` varchar(100) NULL DEFAULT NULL,
+  ADD COLUMN `deletedAt` datetime(3) NULL;
+
+UPDATE `user` SET `gmail` = NULL WHERE `gmail` = '';
+
+ALTER TABLE `user`
+  ADD UNIQUE KEY `uq_user_gmail` (`gmail`),
+  ADD KEY `idx_user_registrationDate` (`registrationDate`);
+
+-- ダンプのセッション（使われていない）は削除し、AutoMigrate で作成された sessions に端末情報・失効日時を追加する
+DROP TABLE IF EXISTS `session`;
+
+DELETE FROM `sessions` WHERE `googleId` IS NULL OR `expiry` IS NULL;
+
+ALTER TABLE `sessions`
+  MODIFY `googleId` varchar(50) NOT NULL,
+  MODIFY `expiry` datetime(3) NOT NULL,
+  ADD COLUMN `userAgent` varchar(255) DEFAULT NULL,
+  ADD COLUMN `ipAddress` varchar(45) DEFAULT NULL,
+  ADD COLUMN `createdAt` datetime(3) DEFAULT NULL,
+  ADD COLUMN `lastSeenAt` datetime(3) DEFAULT NULL,
+  ADD COLUMN `revokedAt` datetime(3) DEFAULT NULL,
+  ADD KEY `idx_sessions_googleId` (`googleId`);
+
+CREATE TABLE `refresh_token` (
+  `tokenId` varchar(36) NOT NULL,
+  `familyId` varchar(36) NOT NULL,
+  `googleId` varchar(50) NOT NULL,
+  `sessionId` varchar(36) DEFAULT NULL,
+  `tokenHash` char(64) NOT NULL,
+  `expiresAt` datetime(3) NOT NULL,
+  `usedAt` datetime(3) DEFAULT NULL,
+  `revokedAt` datetime(3) DEFAULT NULL,
+  `createdAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`tokenId`),
+  UNIQUE KEY `idx_refresh_token_tokenHash` (`tokenHash`),
+  KEY `idx_refresh_token_familyId` (`familyId`),
+  KEY `idx_refresh_token_googleId` (`googleId`),
+  KEY `idx_refresh_token_sessionId` (`sessionId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `sign_in_history` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `googleId` varchar(50) NOT NULL,
+  `sessionId` varchar(36) DEFAULT NULL,
+  `userAgent` varchar(255) DEFAULT NULL,
+  `ipAddress` varchar(45) DEFAULT NULL,
+  `newDevice` tinyint(1) NOT NULL DEFAULT 0,
+  `createdAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_sign_in_history_googleId` (`googleId`),
+  KEY `idx_sign_in_history_createdAt` (`createdAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `user_identities` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `googleId` varchar(50) NOT NULL,
+  `provider` varchar(32) NOT NULL,
+  `issuer` varchar(255) NOT NULL,
+  `subject` varchar(255) NOT NULL,
+  `email` varchar(100) DEFAULT NULL,
+  `createdAt` datetime(3) DEFAULT NULL,
+  `lastLoginAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_identity_user_provider` (`googleId`, `provider`),
+  UNIQUE KEY `idx_identity_issuer_subject` (`issuer`, `subject`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- 既存ユーザーの googleId は Google の sub なので、Google アカウントとして連携する
+INSERT INTO `user_identities` (`googleId`, `provider`, `issuer`, `subject`, `email`, `createdAt`)
+SELECT u.`googleId`, 'google', 'https://accounts.google.com', u.`googleId`, u.`gmail`, u.`registrationDate`
+FROM `user` u
+WHERE u.`googleId` <> 'ANONYMOUS';
+
+CREATE TABLE `user_sanction` (
+  `sanctionId` bigint NOT NULL AUTO_INCREMENT,
+  `userId` varchar(50) NOT NULL,
+  `kind` varchar(20) NOT NULL,
+  `reason` text NOT NULL,
+  `reportId` int DEFAULT NULL,
+  `startsAt` datetime(3) NOT NULL,
+  `endsAt` datetime(3) DEFAULT NULL,
+  `createdBy` varchar(50) NOT NULL,
+  `createdAt` datetime(3) NOT NULL,
+  `liftedAt` datetime(3) DEFAULT NULL,
+  `liftedBy` varchar(50) NOT NULL DEFAULT '',
+  `liftReason` text,
+  PRIMARY KEY (`sanctionId`),
+  KEY `idx_user_sanction_userId` (`userId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- 管理画面の分析（DAU）に使う、ユーザーが利用した日
+CREATE TABLE `user_daily_activity` (
+  `userId` varchar(50) NOT NULL,
+  `date` date NOT NULL,
+  PRIMARY KEY (`userId`, `date`),
+  KEY `idx_user_daily_activity_date` (`date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,39 @@
+ALTER TABLE `report`
+  DROP KEY `idx_report_resolvedAt`,
+  DROP KEY `idx_report_date`,
+  DROP KEY `idx_report_category`,
+  DROP KEY `uq_report_open`,
+  DROP COLUMN `resolvedAt`,
+  DROP COLUMN `resolvedBy`,
+  DROP COLUMN `resolutionNote`,
+  DROP COLUMN `resolution`,
+  DROP COLUMN `openFlag`,
+  DROP COLUMN `category`,
+  MODIFY `removeFlag` tinyint(1) NOT NULL,
+  MODIFY `reportFlag` tinyint(1) NOT NULL;
+
+ALTER TABLE `block`
+  DROP COLUMN `createdAt`;
+
+ALTER TABLE `reaction`
+  DROP KEY `idx_reaction_createdAt`,
+  MODIFY `createdAt` datetime(3) DEFAULT NULL;
+
+DROP TABLE IF EXISTS `post_genre`;
+DROP TABLE IF EXISTS `post_images`;
+
+ALTER TABLE `place`
+  MODIFY `numPost` int NOT NULL;
+
+ALTER TABLE `post`
+  DROP KEY `idx_post_moderationStatus`,
+  DROP KEY `idx_post_publishAt`,
+  DROP KEY `idx_post_status`,
+  DROP KEY `idx_post_postDate`,
+  DROP COLUMN `moderatedAt`,
+  DROP COLUMN `moderationStatus`,
+  DROP COLUMN `publishAt`,
+  DROP COLUMN `status`,
+  MODIFY `numView` int NOT NULL,
+  MODIFY `numReaction` int NOT NULL,
+  MODIFY `postImage` blob;
@@ -0,0 +1,56 @@
+-- 投稿: 公開状態・予約投稿・審査・削除、ビジネス側の投稿画像と複数ジャンル、通報の対応
+
+ALTER TABLE `post`
+  MODIFY `postImage` longblob,
+  MODIFY `numReaction` int NOT NULL DEFAULT 0,
+  MODIFY `numView` int NOT NULL DEFAULT 0,
+  ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'published',
+  ADD COLUMN `publishAt` datetime(3) NULL,
+  ADD COLUMN `moderationStatus` varchar(20) NOT NULL DEFAULT 'approved',
+  ADD COLUMN `moderatedAt` datetime(3) NULL,
+  ADD KEY `idx_post_postDate` (`postDate`),
+  ADD KEY `idx_post_status` (`status`),
+  ADD KEY `idx_post_publishAt` (`publishAt`),
+  ADD KEY `idx_post_moderationStatus` (`moderationStatus`);
+
+ALTER TABLE `place`
+  MODIFY `numPost` int NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS `post_images` (
+  `id` varchar(191) NOT NULL,
+  `post_id` int NOT NULL,
+  `image_url` longtext,
+  PRIMARY KEY (`id`),
+  KEY `idx_post_images_post_id` (`post_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `post_genre` (
+  `post_id` int NOT NULL,
+  `genre_id` int NOT NULL,
+  PRIMARY KEY (`post_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- reaction.createdAt は AutoMigrate で NULL 許可の列として作成されている
+UPDATE `reaction` SET `createdAt` = CURRENT_TIMESTAMP(3) WHERE `createdAt` IS NULL;
+
+ALTER TABLE `reaction`
+  MODIFY `createdAt` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  ADD KEY `idx_reaction_createdAt` (`createdAt`);
+
+ALTER TABLE `block`
+  ADD COLUMN `createdAt` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3);
+
+-- 未対応の通報は openFlag が true、対応後は NULL（同じ投稿への未対応の通報は1人1件）
+ALTER TABLE `report`
+  MODIFY `reportFlag` tinyint(1) NOT NULL DEFAULT 0,
+  MODIFY `removeFlag` tinyint(1) NOT NULL DEFAULT 0,
+  ADD COLUMN `category` varchar(30) NOT NULL DEFAULT 'other',
+  ADD COLUMN `openFlag` tinyint(1) NULL,
+  ADD COLUMN `resolution` varchar(20) NOT NULL DEFAULT '',
+  ADD COLUMN `resolutionNote` text,
+  ADD COLUMN `resolvedBy` varchar(50) DEFAULT NULL,
+  ADD COLUMN `resolvedAt` datetime(3) NULL,
+  ADD UNIQUE KEY `uq_report_open` (`userId`, `postId`, `openFlag`),
+  ADD KEY `idx_report_category` (`category`),
+  ADD KEY `idx_report_date` (`date`),
+  ADD KEY `idx_report_resolvedAt` (`resolvedAt`);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `daily_metric`;
+DROP TABLE IF EXISTS `admin_audit_log`;
+DROP TABLE IF EXISTS `content_filter_rule`;
@@ -0,0 +1,42 @@
+-- 管理画面: NGワード・個人情報フィルタ、監査ログ、分析の日次集計
+
+CREATE TABLE `content_filter_rule` (
+  `ruleId` int NOT NULL AUTO_INCREMENT,
+  `kind` varchar(20) NOT NULL,
+  `pattern` varchar(100) NOT NULL DEFAULT '',
+  `action` varchar(20) NOT NULL,
+  `reason` varchar(255) NOT NULL DEFAULT '',
+  `enabled` tinyint(1) NOT NULL DEFAULT 1,
+  `createdAt` datetime(3) DEFAULT NULL,
+  `updatedAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`ruleId`),
+  KEY `idx_content_filter_rule_kind` (`kind`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- 追記のみ（更新・削除はしない）
+CREATE TABLE `admin_audit_log` (
+  `auditId` bigint NOT NULL AUTO_INCREMENT,
+  `actorGoogleId` varchar(50) NOT NULL,
+  `action` varchar(50) NOT NULL,
+  `targetType` varchar(30) NOT NULL,
+  `targetId` varchar(50) NOT NULL,
+  `before` text,
+  `after` text,
+  `requestId` varchar(64) NOT NULL DEFAULT '',
+  `ipAddress` varchar(45) NOT NULL DEFAULT '',
+  `createdAt` datetime(3) NOT NULL,
+  PRIMARY KEY (`auditId`),
+  KEY `idx_admin_audit_log_actorGoogleId` (`actorGoogleId`),
+  KEY `idx_admin_audit_log_action` (`action`),
+  KEY `idx_admin_audit_target` (`targetType`, `targetId`),
+  KEY `idx_admin_audit_log_createdAt` (`createdAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `daily_metric` (
+  `date` date NOT NULL,
+  `metric` varchar(30) NOT NULL,
+  `dimension` varchar(50) NOT NULL DEFAULT '',
+  `value` bigint NOT NULL,
+  `updatedAt` datetime(3) NOT NULL,
+  PRIMARY KEY (`date`, `metric`, `dimension`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
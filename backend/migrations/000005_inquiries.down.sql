@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS `ask_message`;
+
+ALTER TABLE `ask`
+  DROP KEY `idx_ask_assigneeId`,
+  DROP KEY `idx_ask_status`,
+  DROP COLUMN `updatedAt`,
+  DROP COLUMN `assigneeId`,
+  DROP COLUMN `status`,
+  MODIFY `askFlag` tinyint(1) NOT NULL;
@@ -0,0 +1,23 @@
+-- 問い合わせ: 状態・担当者と、返信・追加の問い合わせ・メモのやり取り
+
+ALTER TABLE `ask`
+  MODIFY `askFlag` tinyint(1) NOT NULL DEFAULT 0,
+  ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'open',
+  ADD COLUMN `assigneeId` varchar(50) DEFAULT NULL,
+  ADD COLUMN `updatedAt` datetime(3) NULL,
+  ADD KEY `idx_ask_status` (`status`),
+  ADD KEY `idx_ask_assigneeId` (`assigneeId`);
+
+-- 処理済み（askFlag）の問い合わせは closed、それ以外は open
+UPDATE `ask` SET `status` = 'closed' WHERE `askFlag` = 1;
+
+CREATE TABLE `ask_message` (
+  `messageId` bigint NOT NULL AUTO_INCREMENT,
+  `askId` int NOT NULL,
+  `kind` varchar(20) NOT NULL,
+  `authorId` varchar(50) NOT NULL,
+  `body` text NOT NULL,
+  `createdAt` datetime(3) NOT NULL,
+  PRIMARY KEY (`messageId`),
+  KEY `idx_ask_message_askId` (`askId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
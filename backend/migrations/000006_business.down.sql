@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS `business_recovery_code`;
+DROP TABLE IF EXISTS `business_totp`;
+DROP TABLE IF EXISTS `businessReq_history`;
+DROP TABLE IF EXISTS `businessReq_document`;
+
+ALTER TABLE `businessReq`
+  DROP KEY `idx_businessReq_status`,
+  DROP KEY `idx_businessReq_phone`,
+  DROP COLUMN `updatedAt`,
+  DROP COLUMN `createdAt`,
+  DROP COLUMN `status`,
+  DROP COLUMN `corporateNumber`;
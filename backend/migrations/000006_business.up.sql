@@ -0,0 +1,54 @@
+-- 事業者: 申請の状態・確認書類・状態の履歴と、ビジネス会員の二段階認証
+
+ALTER TABLE `businessReq`
+  ADD COLUMN `corporateNumber` varchar(13) DEFAULT NULL,
+  ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'pending',
+  ADD COLUMN `createdAt` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  ADD COLUMN `updatedAt` datetime(3) NULL,
+  ADD KEY `idx_businessReq_phone` (`phone`),
+  ADD KEY `idx_businessReq_status` (`status`);
+
+CREATE TABLE `businessReq_document` (
+  `documentId` bigint NOT NULL AUTO_INCREMENT,
+  `requestId` int NOT NULL,
+  `kind` varchar(20) NOT NULL,
+  `fileName` varchar(255) NOT NULL,
+  `contentType` varchar(100) NOT NULL,
+  `size` bigint NOT NULL,
+  `data` mediumblob NOT NULL,
+  `createdAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`documentId`),
+  KEY `idx_businessReq_document_requestId` (`requestId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `businessReq_history` (
+  `historyId` bigint NOT NULL AUTO_INCREMENT,
+  `requestId` int NOT NULL,
+  `fromStatus` varchar(20) DEFAULT NULL,
+  `toStatus` varchar(20) NOT NULL,
+  `actorId` varchar(50) NOT NULL,
+  `note` text,
+  `createdAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`historyId`),
+  KEY `idx_businessReq_history_requestId` (`requestId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `business_totp` (
+  `userId` varchar(50) NOT NULL,
+  `secretEnc` varchar(255) NOT NULL,
+  `confirmedAt` datetime(3) DEFAULT NULL,
+  `lastUsedStep` bigint NOT NULL DEFAULT 0,
+  `createdAt` datetime(3) DEFAULT NULL,
+  `updatedAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`userId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `business_recovery_code` (
+  `recoveryCodeId` int NOT NULL AUTO_INCREMENT,
+  `userId` varchar(50) NOT NULL,
+  `codeHash` char(64) NOT NULL,
+  `usedAt` datetime(3) DEFAULT NULL,
+  `createdAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`recoveryCodeId`),
+  KEY `idx_business_recovery_code_userId` (`userId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
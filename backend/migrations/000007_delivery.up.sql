@@ -0,0 +1,30 @@
+-- メール・Webhook の送信待ち（outbox）と、トークン失効・MFAセッションの保存先（kv_store）
+
+CREATE TABLE `outbox` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `topic` varchar(50) NOT NULL,
+  `idempotencyKey` varchar(191) NOT NULL,
+  `payload` mediumtext,
+  `status` varchar(20) NOT NULL,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `maxAttempts` bigint NOT NULL,
+  `nextAttemptAt` datetime(3) NOT NULL,
+  `lockedUntil` datetime(3) DEFAULT NULL,
+  `lastError` text,
+  `redactOnDelivery` tinyint(1) NOT NULL DEFAULT 0,
+  `deliveredAt` datetime(3) DEFAULT NULL,
+  `createdAt` datetime(3) DEFAULT NULL,
+  `updatedAt` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_outbox_idempotencyKey` (`idempotencyKey`),
+  KEY `idx_outbox_topic` (`topic`),
+  KEY `idx_outbox_due` (`status`, `nextAttemptAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `kv_store` (
+  `key` varchar(191) NOT NULL,
+  `value` blob NOT NULL,
+  `expiresAt` datetime(3) NOT NULL,
+  PRIMARY KEY (`key`),
+  KEY `idx_kv_store_expiresAt` (`expiresAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
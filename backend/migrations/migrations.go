@@ -0,0 +1,16 @@
+// Package migrations はデータベースのスキーマを番号付きの SQL で管理します。
+//
+// NNNNNN_name.up.sql で変更を適用し、NNNNNN_name.down.sql で元に戻します。
+// 適用済みのバージョンは schema_migrations テーブルに記録されます。
+// 新しいマイグレーションは go run ./cmd/migrate create <name> で作成してください。
+// スキーマの正はこのディレクトリの SQL です。GORM のモデルはメインモジュールと事業者モジュールで共有する
+// business/pkg/models にだけあり、型・インデックスなどの DDL はタグに書きません。
+// 列を追加・変更する場合は、マイグレーションとあわせてモデルのフィールドを更新します。
+package migrations
+
+import "embed"
+
+// FS はこのディレクトリのマイグレーションです
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"testing"
+
+	"kojan-map/shared/migrate"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationsLoad(t *testing.T) {
+	migrations, err := migrate.Load(FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	// 番号は1から連続している
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, m.String())
+	}
+}
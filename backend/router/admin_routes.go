@@ -1,79 +1,174 @@
 package router
 
 import (
+	"time"
+
 	"kojan-map/admin/handler"
 	adminrepo "kojan-map/admin/repository"
 	"kojan-map/admin/service"
-	"kojan-map/shared/config"
+	"kojan-map/business/pkg/mfa"
+	"kojan-map/business/pkg/ratelimit"
 	"kojan-map/shared/middleware"
 	sharedrepo "kojan-map/shared/repository"
-	"kojan-map/user/services"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
+// Step-up defaults used when the config leaves them unset
+const (
+	defaultStepUpWindow   = 10 * time.Minute
+	defaultStepUpTokenTTL = 15 * time.Minute
+)
+
+// stepUpRateLimit limits verification emails per admin
+var stepUpRateLimit = ratelimit.PerMinute(3)
+
 // SetupAdminRoutes configures all admin API routes
-func SetupAdminRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+func SetupAdminRoutes(r *gin.Engine, deps Dependencies) {
+	db := deps.DB
+
 	// Initialize shared repositories
 	userRepo := sharedrepo.NewUserRepository(db)
 	postRepo := sharedrepo.NewPostRepository(db)
 
-	authService := services.NewAuthService(db, cfg.GoogleClientID, cfg.JWTSecret, cfg.AppEnv)
-
 	// Initialize admin repositories
 	reportRepo := adminrepo.NewReportRepository(db)
 	businessRequestRepo := adminrepo.NewBusinessRequestRepository(db)
 	askRepo := adminrepo.NewAskRepository(db)
 	businessMemberRepo := adminrepo.NewBusinessMemberRepository(db)
+	contentFilterRuleRepo := adminrepo.NewContentFilterRuleRepository(db)
+	auditLogRepo := adminrepo.NewAuditLogRepository(db)
+	sanctionRepo := adminrepo.NewUserSanctionRepository(db)
 
 	// Initialize services
 	dashboardService := service.NewAdminDashboardService(userRepo, postRepo, reportRepo, businessMemberRepo)
 	reportService := service.NewAdminReportService(reportRepo, db)
 	businessService := service.NewAdminBusinessService(db, businessRequestRepo, userRepo, businessMemberRepo)
-	userService := service.NewAdminUserService(userRepo)
-	contactService := service.NewAdminContactService(askRepo)
+	userService := service.NewAdminUserService(db, userRepo)
+	contactService := service.NewAdminContactService(db, askRepo)
 	postService := service.NewAdminPostService(db)
+	contentFilterService := service.NewAdminContentFilterService(deps.DB, contentFilterRuleRepo, deps.ContentFilter)
+	stepUpService := newStepUpService(deps)
+	outboxService := deps.outboxService()
+	auditService := service.NewAdminAuditService(auditLogRepo)
+	sanctionService := service.NewAdminSanctionService(db, userRepo, sanctionRepo)
+	analyticsService := deps.analyticsService()
 
 	// Initialize handlers
 	dashboardHandler := handler.NewAdminDashboardHandler(dashboardService)
+	analyticsHandler := handler.NewAdminAnalyticsHandler(analyticsService)
 	reportHandler := handler.NewAdminReportHandler(reportService)
 	businessHandler := handler.NewAdminBusinessHandler(businessService)
 	userHandler := handler.NewAdminUserHandler(userService)
 	contactHandler := handler.NewAdminContactHandler(contactService)
 	postHandler := handler.NewAdminPostHandler(postService)
+	contentFilterHandler := handler.NewAdminContentFilterHandler(contentFilterService)
+	stepUpHandler := handler.NewAdminStepUpHandler(stepUpService)
+	outboxHandler := handler.NewAdminOutboxHandler(outboxService)
+	auditHandler := handler.NewAdminAuditHandler(auditService)
+	sanctionHandler := handler.NewAdminSanctionHandler(sanctionService)
 
 	// Apply middleware
 	admin := r.Group("/api/admin")
-	admin.Use(middleware.AuthMiddleware(authService))
+	admin.Use(middleware.RequestIDMiddleware()) // 監査ログに記録するリクエストID
+	admin.Use(middleware.AuthMiddleware(deps.Tokens, deps.sessionValidator(), deps.accountChecker()))
 	admin.Use(middleware.AdminOnlyMiddleware())
 
+	// 取り消せない操作（ユーザー削除・利用禁止・投稿削除・事業者申請の承認）は直近の追加認証が必要
+	stepUp := middleware.RequireStepUp(stepUpService.Window())
+
 	// Admin API routes - 統一されたパス構造
 	{
 		// Dashboard
 		admin.GET("/summary", dashboardHandler.GetSummary)
+		admin.GET("/analytics", analyticsHandler.GetAnalytics)
+
+		// Step-up authentication (追加認証)
+		admin.POST("/step-up", ratelimit.Middleware(deps.RateLimiter, "admin-step-up", stepUpRateLimit, ratelimit.ByUser), stepUpHandler.SendCode)
+		admin.POST("/step-up/verify", stepUpHandler.Verify)
 
 		// Report Management (通報管理)
 		admin.GET("/reports", reportHandler.GetReports)
+		admin.GET("/reports/by-post", reportHandler.GetReportGroups)
 		admin.GET("/reports/:id", reportHandler.GetReportDetail)
 		admin.PUT("/reports/:id/handle", reportHandler.HandleReport)
 
 		// Business Application Management (事業者申請管理)
 		admin.GET("/applications", businessHandler.GetApplications)
-		admin.PUT("/applications/:id/approve", businessHandler.ApproveApplication)
+		admin.GET("/applications/:id", businessHandler.GetApplication)
+		admin.GET("/applications/:id/documents/:documentId", businessHandler.GetApplicationDocument)
+		admin.PUT("/applications/:id/request-info", businessHandler.RequestApplicationInfo)
+		admin.PUT("/applications/:id/approve", stepUp, businessHandler.ApproveApplication)
 		admin.PUT("/applications/:id/reject", businessHandler.RejectApplication)
 
 		// User Management (ユーザー管理)
 		admin.GET("/users", userHandler.GetUsers)
-		admin.DELETE("/users/:userId", userHandler.DeleteUser)
+		admin.DELETE("/users/:userId", stepUp, userHandler.DeleteUser)
+
+		// Suspensions and Bans (利用停止・利用禁止)
+		admin.GET("/sanctions", sanctionHandler.GetSanctions)
+		admin.POST("/users/:userId/suspend", sanctionHandler.SuspendUser)
+		admin.POST("/users/:userId/ban", stepUp, sanctionHandler.BanUser)
+		admin.PUT("/sanctions/:id/lift", sanctionHandler.LiftSanction)
+		admin.PUT("/sanctions/:id/extend", sanctionHandler.ExtendSanction)
 
 		// Post Management (投稿管理)
 		admin.GET("/posts/:postId", postHandler.GetPostByID)
-		admin.DELETE("/posts/:postId", postHandler.DeletePost)
+		admin.DELETE("/posts/:postId", stepUp, postHandler.DeletePost)
+
+		// Moderation Queue (投稿審査)
+		admin.GET("/moderation/posts", postHandler.GetModerationQueue)
+		admin.PUT("/moderation/posts/:postId/approve", postHandler.ApprovePost)
+		admin.PUT("/moderation/posts/:postId/reject", postHandler.RejectPost)
 
 		// Contact/Inquiry Management (問い合わせ管理)
 		admin.GET("/inquiries", contactHandler.GetInquiries)
+		admin.GET("/inquiries/:id", contactHandler.GetInquiry)
+		admin.POST("/inquiries/:id/messages", contactHandler.PostInquiryMessage)
+		admin.PUT("/inquiries/:id/status", contactHandler.UpdateInquiryStatus)
+		admin.PUT("/inquiries/:id/assignee", contactHandler.AssignInquiry)
 		admin.PUT("/inquiries/:id/approve", contactHandler.ApproveInquiry)
 		admin.PUT("/inquiries/:id/reject", contactHandler.RejectInquiry)
+
+		// Content Filter (NGワード・個人情報フィルタ)
+		admin.GET("/content-filter/rules", contentFilterHandler.GetRules)
+		admin.POST("/content-filter/rules", contentFilterHandler.CreateRule)
+		admin.PUT("/content-filter/rules/:id", contentFilterHandler.UpdateRule)
+		admin.DELETE("/content-filter/rules/:id", contentFilterHandler.DeleteRule)
+		admin.POST("/content-filter/check", contentFilterHandler.CheckText)
+
+		// Outbox (メールの送信キュー)
+		admin.GET("/outbox", outboxHandler.GetMessages)
+		admin.GET("/outbox/:id", outboxHandler.GetMessage)
+		admin.POST("/outbox/:id/replay", outboxHandler.ReplayMessage)
+
+		// Audit Log (管理者操作の監査ログ)
+		admin.GET("/audit", auditHandler.GetAuditLogs)
+	}
+}
+
+// newStepUpService builds the admin step-up service from the shared dependencies
+func newStepUpService(deps Dependencies) *service.AdminStepUpService {
+	window := defaultStepUpWindow
+	tokenTTL := defaultStepUpTokenTTL
+	if deps.Config != nil {
+		if deps.Config.AdminStepUpWindow > 0 {
+			window = deps.Config.AdminStepUpWindow
+		}
+		if deps.Config.AccessTokenTTL > 0 {
+			tokenTTL = deps.Config.AccessTokenTTL
+		}
 	}
+
+	codes := mfa.NewMFAValidator()
+	if deps.Store != nil {
+		codes = mfa.NewMFAValidatorWithStore(deps.Store)
+	}
+
+	// 確認コードを送るため、通知サービスは必須（環境変数からの生成は起動時に行う）
+	if deps.Notifier == nil {
+		panic("router: Dependencies.Notifier is required")
+	}
+
+	return service.NewAdminStepUpService(deps.Tokens, codes, deps.Notifier, tokenTTL, window)
 }
@@ -0,0 +1,79 @@
+package router
+
+import (
+	adminrepo "kojan-map/admin/repository"
+	"kojan-map/admin/service"
+	"kojan-map/business/pkg/accountstatus"
+	"kojan-map/business/pkg/activity"
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/kvstore"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/outbox"
+	"kojan-map/business/pkg/ratelimit"
+	"kojan-map/shared/config"
+	"kojan-map/shared/contentfilter"
+	sharedmiddleware "kojan-map/shared/middleware"
+	"kojan-map/user/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Dependencies holds the resources shared by the admin and user routes
+type Dependencies struct {
+	DB            *gorm.DB
+	Config        *config.Config
+	Tokens        *jwt.TokenManager                // ユーザー・管理者・ビジネスで共通のトークン管理
+	ContentFilter *contentfilter.Service           // NGワード・個人情報フィルタ
+	RateLimiter   ratelimit.Store                  // nilの場合はレート制限を行わない
+	Sessions      *services.SessionService         // ログインセッションの失効確認（nilの場合は確認しない）
+	Store         kvstore.Store                    // 管理者の追加認証コードの保存先（nilの場合はプロセス内メモリ）
+	Notifier      notification.NotificationService // メール通知（必須。notification.NewFromEnv で生成）
+	TokenVerifier oauth.TokenVerifier              // GoogleのIDトークンの検証（nilの場合は設定から生成）
+	Providers     *oauth.Registry                  // ログインに使う OIDC プロバイダー（nilの場合は Google のみ）
+	Outbox        *outbox.Dispatcher               // メールの配信ワーカー（nilの場合は管理画面からの再送を次の確認間隔まで待つ）
+	Accounts      *accountstatus.Checker           // 利用停止・利用禁止・削除の確認（nilの場合は確認しない）
+	Activity      *activity.Recorder               // 管理画面の分析（DAU）に使う利用日の記録（nilの場合は記録しない）
+	Analytics     *service.AdminAnalyticsService   // 管理画面の分析（集計ジョブと共有。nilの場合はDBから生成）
+}
+
+// outboxService returns the admin outbox service backed by the dispatcher's store when available
+func (d Dependencies) outboxService() *service.AdminOutboxService {
+	if d.Outbox == nil {
+		return service.NewAdminOutboxService(d.DB, outbox.NewStore(d.DB), nil)
+	}
+	return service.NewAdminOutboxService(d.DB, d.Outbox.Store(), d.Outbox.Wake)
+}
+
+// analyticsService returns the admin analytics service shared with the rollup job when available
+func (d Dependencies) analyticsService() *service.AdminAnalyticsService {
+	if d.Analytics == nil {
+		return service.NewAdminAnalyticsService(d.DB, adminrepo.NewAnalyticsRepository(d.DB))
+	}
+	return d.Analytics
+}
+
+// sessionValidator returns nil (not a typed nil) when no session service is configured
+func (d Dependencies) sessionValidator() sharedmiddleware.SessionValidator {
+	if d.Sessions == nil {
+		return nil
+	}
+	return d.Sessions
+}
+
+// accountChecker returns nil (not a typed nil) when no account checker is configured
+func (d Dependencies) accountChecker() sharedmiddleware.AccountChecker {
+	if d.Accounts == nil {
+		return nil
+	}
+	return d.Accounts
+}
+
+// activityMiddleware records the days users use the service, or does nothing when no recorder is configured
+func (d Dependencies) activityMiddleware() gin.HandlerFunc {
+	if d.Activity == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return sharedmiddleware.RecordActivity(d.Activity)
+}
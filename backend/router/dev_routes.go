@@ -0,0 +1,51 @@
+package router
+
+import (
+	"net/http"
+
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/oauth/oidctest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// devIDTokenRequest is the identity to put in a development ID token
+type devIDTokenRequest struct {
+	Provider string `json:"provider"` // 省略時は google
+	Sub      string `json:"sub" binding:"required"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Name     string `json:"name"`
+}
+
+// SetupDevRoutes registers the development-only ID token endpoint backed by the in-process OIDC issuer.
+// Google・LINE などに接続せずにログインを試すため、発行したトークンを /api/auth/exchange-token などに渡します。
+// DEV_OIDC_ISSUER が有効な dev/test 環境でのみ登録します。
+func SetupDevRoutes(r *gin.Engine, issuer *oidctest.Issuer, providers *oauth.Registry) {
+	r.POST("/api/dev/id-token", func(c *gin.Context) {
+		var req devIDTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Provider == "" {
+			req.Provider = oauth.ProviderGoogle
+		}
+		provider, err := providers.Get(req.Provider)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		token, err := issuer.MintIDToken(provider.ClientID, oidctest.Identity{
+			Subject:       req.Sub,
+			Email:         req.Email,
+			EmailVerified: req.Email != "",
+			Name:          req.Name,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id_token": token})
+	})
+}
@@ -0,0 +1,233 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kojan-map/business"
+	bizjwt "kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/notification"
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/secretbox"
+	"kojan-map/shared/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// newOfflineDB は接続しないDBを返します（ルート登録・ミドルウェアのみ確認）
+func newOfflineDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:0)/kojanmap",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db
+}
+
+// TestRoutes_NoConflicts は管理者・ユーザー・ビジネスのルートを同じエンジンに登録できることを確認します
+// （パスが衝突すると gin が panic する）
+func TestRoutes_NoConflicts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := newOfflineDB(t)
+
+	r := gin.New()
+	deps := Dependencies{
+		DB:       db,
+		Config:   &config.Config{},
+		Tokens:   bizjwt.NewTokenManagerWithSecret("test-secret"),
+		Notifier: &codeNotifier{},
+	}
+	box, err := secretbox.New(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	assert.NotPanics(t, func() {
+		SetupAdminRoutes(r, deps)
+		SetupUserRoutes(r, deps)
+		authService := business.RegisterRoutes(r, db, business.Options{TokenManager: deps.Tokens, SecretBox: box, Notifier: deps.Notifier})
+		authService.Close()
+	})
+}
+
+// codeNotifier は送信した確認コードを記録します
+type codeNotifier struct {
+	code string
+}
+
+func (n *codeNotifier) SendMFACode(_ string, code string) error {
+	n.code = code
+	return nil
+}
+
+func (n *codeNotifier) Send(_ context.Context, msg notification.Message) error {
+	if data, ok := msg.Data.(notification.MFACodeData); ok {
+		n.code = data.Code
+	}
+	return nil
+}
+
+// TestAdminRoutes_RequireStepUp は削除・承認の管理操作に追加認証が必要なことを確認します
+func TestAdminRoutes_RequireStepUp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokens := bizjwt.NewTokenManagerWithSecret("test-secret")
+	defer tokens.Stop()
+
+	mail := &codeNotifier{}
+	r := gin.New()
+	SetupAdminRoutes(r, Dependencies{
+		DB:       newOfflineDB(t),
+		Config:   &config.Config{},
+		Tokens:   tokens,
+		Notifier: mail,
+	})
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	adminToken, err := tokens.GenerateToken("admin-1", "admin@example.com", "admin")
+	require.NoError(t, err)
+
+	for _, route := range [][2]string{
+		{http.MethodDelete, "/api/admin/users/user-1"},
+		{http.MethodDelete, "/api/admin/posts/1"},
+		{http.MethodPut, "/api/admin/applications/1/approve"},
+	} {
+		w := do(route[0], route[1], adminToken, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, route[1])
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"), "insufficient_user_authentication", route[1])
+	}
+
+	// 確認コードをメールで受け取り（レスポンスには含まれない）、追加認証済みのトークンを取得
+	w := do(http.MethodPost, "/api/admin/step-up", adminToken, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotEmpty(t, mail.code)
+	assert.NotContains(t, w.Body.String(), mail.code)
+
+	w = do(http.MethodPost, "/api/admin/step-up/verify", adminToken, `{"code":"`+mail.code+`"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stepped struct {
+		Token string `json:"jwt_token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stepped))
+
+	// 追加認証後はミドルウェアを通過する（DBに接続しないためハンドラーはエラーを返す）
+	w = do(http.MethodDelete, "/api/admin/users/user-1", stepped.Token, "")
+	assert.NotEqual(t, http.StatusUnauthorized, w.Code)
+
+	// 古い追加認証は拒否される
+	claims, err := tokens.VerifyTokenWithType(stepped.Token, "access")
+	require.NoError(t, err)
+	assert.False(t, claims.SteppedUpWithin(defaultStepUpWindow, claims.AuthTime.Add(defaultStepUpWindow+time.Second)))
+}
+
+// TestDevRoutes_MintIDToken は開発用の発行者のIDトークンでログインの検証を通過できることを確認します
+func TestDevRoutes_MintIDToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{AppEnv: "test", GoogleClientID: "client-id", DevOIDCIssuer: true}
+	providers, issuer, err := config.NewLoginProviders(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, issuer)
+	defer issuer.Close()
+	google, err := providers.Get(oauth.ProviderGoogle)
+	require.NoError(t, err)
+	verifier := google.Verifier
+
+	r := gin.New()
+	SetupDevRoutes(r, issuer, providers)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/dev/id-token", strings.NewReader(`{"sub":"dev-user","email":"dev@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body struct {
+		IDToken string `json:"id_token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	claims, err := verifier.VerifyToken(req.Context(), body.IDToken)
+	require.NoError(t, err)
+	assert.Equal(t, "dev-user", claims.Sub)
+	assert.Equal(t, "dev@example.com", claims.Email)
+
+	// 本番環境では開発用の発行者を起動しない
+	_, _, err = config.NewLoginProviders(&config.Config{AppEnv: "prod", GoogleClientID: "client-id", DevOIDCIssuer: true})
+	assert.Error(t, err)
+}
+
+// TestDevRoutes_MintIDToken_OtherProvider は開発用の発行者で LINE などのプロバイダーのIDトークンも発行できることを確認します
+func TestDevRoutes_MintIDToken_OtherProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		AppEnv:         "test",
+		GoogleClientID: "client-id",
+		DevOIDCIssuer:  true,
+		OIDCProviders:  []config.OIDCProviderConfig{{Name: "line", Issuer: "https://access.line.me", ClientID: "line-client"}},
+	}
+	providers, issuer, err := config.NewLoginProviders(cfg)
+	require.NoError(t, err)
+	defer issuer.Close()
+	line, err := providers.Get("line")
+	require.NoError(t, err)
+	// 連携情報には本来の発行者を保存する
+	assert.Equal(t, "https://access.line.me", line.Issuer)
+
+	r := gin.New()
+	SetupDevRoutes(r, issuer, providers)
+
+	// メールアドレスなしで発行できる
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/dev/id-token", strings.NewReader(`{"provider":"line","sub":"line-user"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body struct {
+		IDToken string `json:"id_token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	claims, err := line.Verifier.VerifyToken(req.Context(), body.IDToken)
+	require.NoError(t, err)
+	assert.Equal(t, "line-user", claims.Sub)
+	assert.Empty(t, claims.Email)
+
+	// Google 向けのトークンは LINE の検証を通過しない（aud が異なる）
+	google, err := providers.Get(oauth.ProviderGoogle)
+	require.NoError(t, err)
+	_, err = google.Verifier.VerifyToken(req.Context(), body.IDToken)
+	assert.Error(t, err)
+
+	// 設定されていないプロバイダー
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/dev/id-token", strings.NewReader(`{"provider":"facebook","sub":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 本番環境では JWKS の取得先が必須
+	_, _, err = config.NewLoginProviders(&config.Config{
+		AppEnv:         "prod",
+		GoogleClientID: "client-id",
+		OIDCProviders:  []config.OIDCProviderConfig{{Name: "line", Issuer: "https://access.line.me", ClientID: "line-client"}},
+	})
+	assert.Error(t, err)
+}
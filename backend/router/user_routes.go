@@ -1,20 +1,45 @@
 package router
 
 import (
-	"kojan-map/shared/config"
+	"kojan-map/business/pkg/ratelimit"
+	sharedmiddleware "kojan-map/shared/middleware"
 	"kojan-map/user/handlers"
-	"kojan-map/user/middleware"
 	"kojan-map/user/services"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
+)
+
+// レート制限（ルートグループごと）
+var (
+	authRateLimit    = ratelimit.PerMinute(10) // トークン交換（IP単位）
+	postRateLimit    = ratelimit.PerMinute(5)  // 投稿作成（ユーザー単位）
+	reportRateLimit  = ratelimit.PerMinute(5)  // 通報（ユーザー単位）
+	contactRateLimit = ratelimit.PerHour(10)   // 問い合わせ（ユーザー単位）
 )
 
 // SetupUserRoutes configures all user-facing API routes
-func SetupUserRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+func SetupUserRoutes(r *gin.Engine, deps Dependencies) {
+	db, cfg, limiter := deps.DB, deps.Config, deps.RateLimiter
+	authMiddleware := sharedmiddleware.AuthMiddleware(deps.Tokens, deps.sessionValidator(), deps.accountChecker())
+	// 利用停止中のアカウントでもログアウトはできるよう、アカウントの状態を確認しない
+	signedInMiddleware := sharedmiddleware.AuthMiddleware(deps.Tokens, deps.sessionValidator(), nil)
+	// 公開ルートで投稿者本人を判定する（未ログインでも利用できる）
+	optionalAuthMiddleware := sharedmiddleware.OptionalAuthMiddleware(deps.Tokens, deps.sessionValidator())
+
 	// 1. Services Initialization
-	authService := services.NewAuthService(db, cfg.GoogleClientID, cfg.JWTSecret, cfg.AppEnv)
+	authService := services.NewAuthService(db, cfg.GoogleClientID, deps.Tokens, cfg.AppEnv)
+	authService.SetAccessTokenTTL(cfg.AccessTokenTTL)
+	authService.SetTokenVerifier(deps.TokenVerifier)
+	authService.SetLoginProviders(deps.Providers)
+	authService.SetAccountChecker(deps.Accounts)
+	identityService := services.NewIdentityService(db)
+	refreshService := services.NewRefreshTokenService(db, cfg.RefreshTokenTTL)
+	sessionService := deps.Sessions
+	if sessionService == nil {
+		sessionService = services.NewSessionService(db)
+	}
 	userService := services.NewUserService(db)
+	userService.SetSessionTTL(cfg.RefreshTokenTTL)
 	postService := services.NewPostService(db)
 	placeService := services.NewPlaceService(db)
 	genreService := services.NewGenreService(db)
@@ -22,28 +47,46 @@ func SetupUserRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	reportService := services.NewReportService(db)
 	contactService := services.NewContactService(db)
 	businessAppService := services.NewBusinessApplicationService(db)
-	businessService := services.NewBusinessService(db)
+
+	// 投稿審査ルール（新規アカウントの事前審査・通報多数の自動非表示）
+	moderationService := services.NewModerationService(db, services.ModerationPolicy{
+		NewAccountReviewPeriod:  cfg.NewAccountReviewPeriod,
+		AutoHideReportThreshold: cfg.AutoHideReportThreshold,
+		AutoHideReportWindow:    cfg.AutoHideReportWindow,
+	})
+	postService.SetModeration(moderationService)
+	reportService.SetModeration(moderationService)
+
+	// NGワード・個人情報フィルタ（投稿・問い合わせ）
+	postService.SetContentFilter(deps.ContentFilter)
+	contactService.SetContentFilter(deps.ContentFilter)
 
 	// 2. Handlers Initialization
-	authHandler := handlers.NewAuthHandler(userService, authService)
+	authHandler := handlers.NewAuthHandler(userService, authService, refreshService, sessionService, identityService)
+	identityHandler := handlers.NewIdentityHandler(authService, identityService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
 	postHandler := handlers.NewPostHandler(postService, placeService, genreService)
 	genreHandler := handlers.NewGenreHandler(genreService)
 	otherHandler := handlers.NewBlockHandler(blockService)
 	reportHandler := handlers.NewReportHandler(reportService)
 	contactHandler := handlers.NewContactHandler(contactService)
 	businessAppHandler := handlers.NewBusinessApplicationHandler(businessAppService)
-	businessHandler := handlers.NewBusinessHandler(businessService, postService)
 
 	// 3. Public routes
 	api := r.Group("/api")
 	{
 		// Auth
 		api.POST("/users/register", authHandler.Register)
-		api.POST("/auth/exchange-token", authHandler.ExchangeToken)
+
+		authLimited := api.Group("", ratelimit.Middleware(limiter, "auth", authRateLimit, ratelimit.ByClientIP))
+		authLimited.POST("/auth/exchange-token", authHandler.ExchangeToken)
+		authLimited.POST("/auth/token/refresh", authHandler.Refresh)
+		api.GET("/auth/jwks", authHandler.JWKS)
+		api.GET("/auth/providers", identityHandler.ListProviders)
 
 		// Posts (Read)
 		api.GET("/posts", postHandler.GetPosts)
-		api.GET("/posts/detail", postHandler.GetPostDetail)
+		api.GET("/posts/detail", optionalAuthMiddleware, postHandler.GetPostDetail)
 		api.GET("/posts/search", postHandler.SearchByKeyword)
 		api.GET("/posts/search/genre", postHandler.SearchByGenre)
 
@@ -53,15 +96,20 @@ func SetupUserRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 
 		// Genres (Public)
 		api.GET("/genres", genreHandler.GetGenres)
+
+		// Logout
+		api.PUT("/auth/logout", signedInMiddleware, authHandler.Logout)
 	}
 
 	// 4. Protected routes
 	protected := r.Group("/api")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(authMiddleware, deps.activityMiddleware())
 	{
 		// Posts (Write)
-		protected.POST("/posts", postHandler.CreatePost)
-		protected.DELETE("/posts", postHandler.DeletePost)         // 復活
+		postLimited := protected.Group("", ratelimit.Middleware(limiter, "post", postRateLimit, ratelimit.ByUser))
+		postLimited.POST("/posts", postHandler.CreatePost)
+		protected.DELETE("/posts", postHandler.DeletePost) // 復活
+		protected.PUT("/posts/status", postHandler.UpdatePostStatus)
 		protected.POST("/posts/reaction", postHandler.AddReaction) // 復活
 		protected.GET("/posts/reaction/status", postHandler.CheckReactionStatus)
 		protected.GET("/posts/history", postHandler.GetPostHistory)
@@ -71,30 +119,35 @@ func SetupUserRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
 		protected.POST("/users/block", otherHandler.BlockUser)
 		protected.DELETE("/users/block", otherHandler.UnblockUser)
 		protected.GET("/users/block/list", otherHandler.GetBlockList)
-		protected.POST("/report", reportHandler.CreateReport)
-		protected.POST("/contact/validate", contactHandler.CreateContact)
+		reportLimited := protected.Group("", ratelimit.Middleware(limiter, "report", reportRateLimit, ratelimit.ByUser))
+		reportLimited.POST("/report", reportHandler.CreateReport)
+		protected.GET("/report/history", reportHandler.GetMyReports)
+		contactLimited := protected.Group("", ratelimit.Middleware(limiter, "contact", contactRateLimit, ratelimit.ByUser))
+		contactLimited.POST("/contact/validate", contactHandler.CreateContact)
+		contactLimited.POST("/contact/:id/messages", contactHandler.AddFollowUp)
+		protected.GET("/contact", contactHandler.GetMyContacts)
 
 		// Business Registration
 		protected.POST("/business/application", businessAppHandler.CreateBusinessApplication)
+		protected.GET("/business/application", businessAppHandler.GetMyApplications)
+		protected.PUT("/business/application/:id", businessAppHandler.ResubmitApplication)
+		protected.POST("/business/application/:id/documents", businessAppHandler.AttachApplicationDocument)
 
 		// Auth (Logout/Withdrawal)
 		protected.GET("/auth/me", authHandler.GetCurrentUser) // 追加
-		protected.PUT("/auth/logout", authHandler.Logout)
 		protected.PUT("/auth/withdrawal", authHandler.Withdrawal)
-	}
 
-	// 5. Business-only routes
-	business := r.Group("/api/business")
-	business.Use(middleware.AuthMiddleware(), middleware.BusinessOnlyMiddleware())
-	{
-		business.GET("/stats", businessHandler.GetBusinessStats)
-		business.GET("/profile", businessHandler.GetBusinessProfile)
-		business.PUT("/profile", businessHandler.UpdateBusinessProfile)
-		business.POST("/icon", businessHandler.UploadBusinessIcon)
-		business.GET("/posts/count", businessHandler.GetBusinessPostCount)
-		business.GET("/revenue", businessHandler.GetBusinessRevenue)
-		business.PUT("/name", businessHandler.UpdateBusinessName)
-		business.PUT("/address", businessHandler.UpdateBusinessAddress)
-		business.PUT("/phone", businessHandler.UpdateBusinessPhone)
+		// Sessions (ログイン中の端末・ログイン履歴)
+		protected.GET("/auth/sessions", sessionHandler.ListSessions)
+		protected.DELETE("/auth/sessions/:id", sessionHandler.RevokeSession)
+		protected.GET("/auth/sign-ins", sessionHandler.ListSignIns)
+
+		// Identities (ログインに使う外部アカウントの連携)
+		protected.GET("/auth/identities", identityHandler.ListIdentities)
+		protected.DELETE("/auth/identities/:provider", identityHandler.UnlinkIdentity)
+		authLimitedProtected := protected.Group("", ratelimit.Middleware(limiter, "auth", authRateLimit, ratelimit.ByClientIP))
+		authLimitedProtected.POST("/auth/identities", identityHandler.LinkIdentity)
 	}
+
+	// 事業者向けのルート（/api/business 配下）は business モジュールの RegisterRoutes が登録する
 }
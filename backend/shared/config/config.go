@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
@@ -18,6 +21,33 @@ type Config struct {
 	AppEnv         string
 	FrontendURL    string
 	AllowedOrigins []string // ←追加
+
+	// JWT signing keys (PEM). 未設定の場合は JWTSecret（HS256）で署名する
+	JWTSigningKeyFile string        // 現在の署名鍵（RSA / Ed25519 の秘密鍵）
+	JWTVerifyKeyFiles []string      // ローテーション前の旧鍵（検証のみ）
+	JWTAcceptHS256    bool          // 署名鍵の設定後も JWTSecret（HS256）のトークンを受け付ける（移行期間のみ）
+	AccessTokenTTL    time.Duration // ユーザー側アクセストークンの有効期間
+	RefreshTokenTTL   time.Duration // リフレッシュトークンの有効期間（ローテーションごとに延長）
+
+	// Google ID token verification
+	GoogleIssuer  string // 受け付ける iss（空の場合は Google）
+	GoogleJWKSURL string // 署名検証の公開鍵の取得先（空の場合は Google）
+	DevOIDCIssuer bool   // dev/test のみ: プロセス内の OIDC 発行者を Google の代わりに使う
+
+	// Additional OIDC login providers (LINE など)
+	OIDCProviders []OIDCProviderConfig // OIDC_PROVIDERS に名前を列挙し、OIDC_<NAME>_* で個別に設定
+
+	// Admin step-up
+	AdminStepUpWindow time.Duration // 追加認証後、削除・承認などの操作を許可する期間
+
+	// Moderation rules
+	NewAccountReviewPeriod  time.Duration // 登録からこの期間内のアカウントの投稿は審査待ちになる（既定は0で無効）
+	AutoHideReportThreshold int           // この人数以上の異なる通報者で自動非表示（0で無効）
+	AutoHideReportWindow    time.Duration // 自動非表示の判定対象となる通報期間
+
+	// Rate limiting
+	RateLimitEnabled bool     // falseの場合はレート制限を行わない（負荷試験など）
+	TrustedProxies   []string // X-Forwarded-For を信頼するリバースプロキシのIP・CIDR（空の場合は信頼しない）
 }
 
 // Load loads configuration from environment variables with defaults
@@ -40,6 +70,25 @@ func Load() *Config {
 		AppEnv:         getEnv("APP_ENV", "dev"),
 		FrontendURL:    getEnv("FRONTEND_URL", "http://localhost:5173"),
 		AllowedOrigins: getAllowedOrigins(),
+
+		JWTSigningKeyFile: getEnv("JWT_SIGNING_KEY_FILE", ""),
+		JWTVerifyKeyFiles: getEnvList("JWT_VERIFY_KEY_FILES"),
+		JWTAcceptHS256:    getEnv("JWT_ACCEPT_HS256", "false") == "true",
+		AccessTokenTTL:    getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+		RefreshTokenTTL:   getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+		AdminStepUpWindow: getEnvDuration("ADMIN_STEP_UP_WINDOW", 10*time.Minute),
+
+		GoogleIssuer:  getEnv("GOOGLE_ISSUER", ""),
+		GoogleJWKSURL: getEnv("GOOGLE_JWKS_URL", ""),
+		DevOIDCIssuer: getEnv("DEV_OIDC_ISSUER", "false") == "true",
+		OIDCProviders: loadOIDCProviders(),
+
+		NewAccountReviewPeriod:  getEnvDuration("MODERATION_NEW_ACCOUNT_PERIOD", 0),
+		AutoHideReportThreshold: getEnvInt("MODERATION_AUTO_HIDE_REPORTS", 5),
+		AutoHideReportWindow:    getEnvDuration("MODERATION_AUTO_HIDE_WINDOW", 24*time.Hour),
+
+		RateLimitEnabled: getEnv("RATE_LIMIT_ENABLED", "true") != "false",
+		TrustedProxies:   getEnvList("TRUSTED_PROXIES"),
 	}
 }
 
@@ -77,3 +126,39 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
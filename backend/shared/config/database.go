@@ -27,8 +27,10 @@ func ConnectDB(cfg *Config) *gorm.DB {
 	maxRetries := 30
 	for i := 0; i < maxRetries; i++ {
 		// 接続試行中はエラーログを抑制するために Silent モードを使用
+		// TranslateError: 一意制約違反を gorm.ErrDuplicatedKey として判定できるようにする
 		db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
+			Logger:         logger.Default.LogMode(logger.Silent),
+			TranslateError: true,
 		})
 
 		if err == nil {
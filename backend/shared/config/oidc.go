@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/oauth/oidctest"
+)
+
+// OIDCProviderConfig holds the settings of an additional OpenID Connect login provider
+type OIDCProviderConfig struct {
+	Name         string // API で指定する名前（小文字。例: line）
+	Issuer       string // 受け付ける iss（連携情報にも保存する）
+	JWKSURL      string // 署名検証の公開鍵の取得先
+	ClientID     string // IDトークンの aud
+	RequireEmail bool   // 確認済みのメールアドレスを必須にする（既定では任意）
+}
+
+// loadOIDCProviders reads OIDC_PROVIDERS (e.g. "line") and OIDC_<NAME>_ISSUER / _JWKS_URL / _CLIENT_ID / _REQUIRE_EMAIL
+func loadOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range getEnvList("OIDC_PROVIDERS") {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			JWKSURL:      getEnv(prefix+"JWKS_URL", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			RequireEmail: getEnv(prefix+"REQUIRE_EMAIL", "false") == "true",
+		})
+	}
+	return providers
+}
+
+// NewLoginProviders creates the login providers (Google and OIDC_PROVIDERS) shared by the user and business login routes
+//
+// DevOIDCIssuer が有効な場合（dev/test のみ）は、プロセス内の OIDC 発行者を起動してすべてのプロバイダーの代わりに使用し、
+// 発行者を返します（IDトークンの発行・終了時の Close に使用）。それ以外の場合、発行者は nil です。
+// 連携情報には発行者を差し替えても各プロバイダー本来の iss を保存するため、再起動後も同じアカウントでログインできます。
+func NewLoginProviders(cfg *Config) (*oauth.Registry, *oidctest.Issuer, error) {
+	for _, p := range cfg.OIDCProviders {
+		if p.Name == oauth.ProviderGoogle {
+			return nil, nil, fmt.Errorf("OIDC_PROVIDERS must not include %q (use GOOGLE_CLIENT_ID)", p.Name)
+		}
+		if p.Issuer == "" || p.ClientID == "" {
+			return nil, nil, fmt.Errorf("OIDC provider %q requires ISSUER and CLIENT_ID", p.Name)
+		}
+		if p.JWKSURL == "" && !cfg.DevOIDCIssuer {
+			return nil, nil, fmt.Errorf("OIDC provider %q requires JWKS_URL", p.Name)
+		}
+	}
+
+	var issuer *oidctest.Issuer
+	if cfg.DevOIDCIssuer {
+		if cfg.AppEnv != "dev" && cfg.AppEnv != "test" {
+			return nil, nil, fmt.Errorf("DEV_OIDC_ISSUER is only allowed in dev/test (APP_ENV=%s)", cfg.AppEnv)
+		}
+		if cfg.GoogleClientID == "" {
+			return nil, nil, fmt.Errorf("GOOGLE_CLIENT_ID is required to use the development OIDC issuer")
+		}
+		var err error
+		if issuer, err = oidctest.NewIssuer(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	googleCfg := oauth.ConfigWithOverrides(cfg.GoogleClientID, cfg.GoogleIssuer, cfg.GoogleJWKSURL)
+	if issuer != nil {
+		googleCfg = oauth.ConfigWithOverrides(cfg.GoogleClientID, issuer.URL(), issuer.JWKSURL())
+	}
+	providers := []*oauth.Provider{{
+		Name:     oauth.ProviderGoogle,
+		Issuer:   oauth.GoogleIssuer,
+		ClientID: cfg.GoogleClientID,
+		Verifier: oauth.NewIDTokenVerifier(googleCfg),
+	}}
+
+	for _, p := range cfg.OIDCProviders {
+		verifierCfg := oauth.VerifierConfig{
+			Issuers:       []string{p.Issuer},
+			JWKSURL:       p.JWKSURL,
+			Audiences:     []string{p.ClientID},
+			Leeway:        time.Minute,
+			EmailOptional: !p.RequireEmail,
+		}
+		if issuer != nil {
+			verifierCfg.Issuers = []string{issuer.URL()}
+			verifierCfg.JWKSURL = issuer.JWKSURL()
+		}
+		providers = append(providers, &oauth.Provider{
+			Name:     p.Name,
+			Issuer:   p.Issuer,
+			ClientID: p.ClientID,
+			Verifier: oauth.NewIDTokenVerifier(verifierCfg),
+		})
+	}
+
+	return oauth.NewRegistry(providers...), issuer, nil
+}
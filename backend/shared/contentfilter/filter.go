@@ -0,0 +1,320 @@
+// Package contentfilter inspects user-submitted text for NG words and
+// personal information (phone numbers, email addresses, My Number-like
+// digit sequences) and decides whether to reject, mask or review it.
+package contentfilter
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"kojan-map/business/pkg/models"
+)
+
+// Rule is a single filter rule. For NG words Pattern holds the word.
+type Rule struct {
+	ID      int32
+	Kind    string
+	Pattern string
+	Action  string
+	Reason  string
+}
+
+// Match describes a part of the text that triggered a rule.
+type Match struct {
+	RuleID int32  `json:"ruleId"`
+	Kind   string `json:"kind"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// Result is the outcome of filtering a text.
+type Result struct {
+	// Text is the input with every masked part replaced by asterisks.
+	Text string
+	// Action is the most severe action among the matches
+	// (reject > review > mask), or empty when nothing matched.
+	Action string
+	// Reason is the reason of the rule that decided Action.
+	Reason  string
+	Matches []Match
+}
+
+// Rejected reports whether the text must not be saved.
+func (r Result) Rejected() bool { return r.Action == models.FilterActionReject }
+
+// NeedsReview reports whether the text should go to the moderation queue.
+func (r Result) NeedsReview() bool { return r.Action == models.FilterActionReview }
+
+// RejectedError is returned by services when a text is rejected by a rule.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	if e.Reason == "" {
+		return "content rejected"
+	}
+	return "content rejected: " + e.Reason
+}
+
+var (
+	// 0 から始まる国内番号、または +81 から始まる国際表記
+	phonePattern = regexp.MustCompile(`(?:\+81[\s-]?\(?\d{1,4}\)?|\(?0\d{1,4}\)?)[\s-]?\d{1,4}[\s-]?\d{3,4}`)
+	emailPattern = regexp.MustCompile(`[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`)
+	// 4桁区切りを許容した12桁の数字列
+	myNumberPattern = regexp.MustCompile(`\d{4}[\s-]?\d{4}[\s-]?\d{4}`)
+)
+
+// DefaultRules are the detector rules used when no rule is configured for a kind.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Kind: models.FilterKindPhone, Action: models.FilterActionMask, Reason: "電話番号が含まれています"},
+		{Kind: models.FilterKindEmail, Action: models.FilterActionMask, Reason: "メールアドレスが含まれています"},
+		{Kind: models.FilterKindMyNumber, Action: models.FilterActionReject, Reason: "マイナンバーと思われる数字が含まれています"},
+	}
+}
+
+// Filter applies a fixed set of rules. It is safe for concurrent use.
+type Filter struct {
+	ngWords   []Rule
+	detectors []Rule
+}
+
+// New builds a filter from rules. NG words are normalized once here.
+func New(rules []Rule) *Filter {
+	f := &Filter{}
+	for _, r := range rules {
+		if !isValidAction(r.Action) {
+			continue
+		}
+		switch r.Kind {
+		case models.FilterKindNGWord:
+			r.Pattern = Normalize(r.Pattern)
+			if r.Pattern == "" {
+				continue
+			}
+			f.ngWords = append(f.ngWords, r)
+		case models.FilterKindPhone, models.FilterKindEmail, models.FilterKindMyNumber:
+			f.detectors = append(f.detectors, r)
+		}
+	}
+	return f
+}
+
+func isValidAction(action string) bool {
+	switch action {
+	case models.FilterActionReject, models.FilterActionMask, models.FilterActionReview:
+		return true
+	}
+	return false
+}
+
+// IsValidKind reports whether kind is a known rule kind.
+func IsValidKind(kind string) bool {
+	switch kind {
+	case models.FilterKindNGWord, models.FilterKindPhone, models.FilterKindEmail, models.FilterKindMyNumber:
+		return true
+	}
+	return false
+}
+
+// IsValidAction reports whether action is a known rule action.
+func IsValidAction(action string) bool {
+	return isValidAction(action)
+}
+
+// Check inspects text and returns the decision.
+func (f *Filter) Check(text string) Result {
+	result := Result{Text: text}
+	if f == nil || text == "" {
+		return result
+	}
+
+	n := newNormalized(text)
+	var spans [][2]int // 元テキスト上のマスク範囲（バイトオフセット）
+
+	record := func(r Rule, start, end int) {
+		result.Matches = append(result.Matches, Match{RuleID: r.ID, Kind: r.Kind, Action: r.Action, Reason: r.Reason})
+		if severity(r.Action) > severity(result.Action) {
+			result.Action = r.Action
+			result.Reason = r.Reason
+		}
+		if r.Action == models.FilterActionMask {
+			spans = append(spans, n.original(start, end))
+		}
+	}
+
+	for _, r := range f.ngWords {
+		for _, loc := range indexAll(n.text, r.Pattern) {
+			record(r, loc[0], loc[1])
+		}
+	}
+	for _, r := range f.detectors {
+		for _, loc := range detect(r.Kind, n.text) {
+			record(r, loc[0], loc[1])
+		}
+	}
+
+	if len(spans) > 0 {
+		result.Text = mask(text, spans)
+	}
+	return result
+}
+
+func severity(action string) int {
+	switch action {
+	case models.FilterActionReject:
+		return 3
+	case models.FilterActionReview:
+		return 2
+	case models.FilterActionMask:
+		return 1
+	}
+	return 0
+}
+
+func indexAll(s, substr string) [][2]int {
+	var locs [][2]int
+	for offset := 0; offset < len(s); {
+		i := strings.Index(s[offset:], substr)
+		if i < 0 {
+			break
+		}
+		start := offset + i
+		locs = append(locs, [2]int{start, start + len(substr)})
+		offset = start + len(substr)
+	}
+	return locs
+}
+
+func detect(kind, s string) [][2]int {
+	var pattern *regexp.Regexp
+	switch kind {
+	case models.FilterKindPhone:
+		pattern = phonePattern
+	case models.FilterKindEmail:
+		pattern = emailPattern
+	case models.FilterKindMyNumber:
+		pattern = myNumberPattern
+	default:
+		return nil
+	}
+
+	var locs [][2]int
+	for _, loc := range pattern.FindAllStringIndex(s, -1) {
+		start, end := loc[0], loc[1]
+		// 前後が数字に接している場合は、より長い数字列の一部なので除外
+		if kind != models.FilterKindEmail && (digitBefore(s, start) || digitAfter(s, end)) {
+			continue
+		}
+		if kind == models.FilterKindPhone && !isPhoneNumber(s[start:end]) {
+			continue
+		}
+		locs = append(locs, [2]int{start, end})
+	}
+	return locs
+}
+
+func digitBefore(s string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return unicode.IsDigit(r)
+}
+
+func digitAfter(s string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return unicode.IsDigit(r)
+}
+
+// isPhoneNumber は数字の桁数が国内電話番号として妥当か判定する
+func isPhoneNumber(s string) bool {
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if strings.HasPrefix(s, "+81") {
+		return digits == 11 || digits == 12
+	}
+	return digits == 10 || digits == 11
+}
+
+func mask(text string, spans [][2]int) string {
+	covered := make([]bool, len(text))
+	for _, sp := range spans {
+		for i := sp[0]; i < sp[1] && i < len(text); i++ {
+			covered[i] = true
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for i, r := range text {
+		if covered[i] {
+			b.WriteRune('*')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Normalize applies NFKC, lower-casing and katakana-to-hiragana folding
+// so that full-width, half-width and kana variants compare equal.
+func Normalize(s string) string {
+	return newNormalized(s).text
+}
+
+// normalized keeps the normalized text and, for every byte of it, the
+// byte range of the original text it was produced from.
+type normalized struct {
+	text     string
+	srcStart []int
+	srcEnd   []int
+}
+
+func newNormalized(s string) *normalized {
+	n := &normalized{}
+	var b strings.Builder
+	var it norm.Iter
+	it.InitString(norm.NFKC, s)
+	for !it.Done() {
+		start := it.Pos()
+		seg := it.Next()
+		end := it.Pos()
+		for _, r := range string(seg) {
+			r = foldKana(unicode.ToLower(r))
+			size := utf8.RuneLen(r)
+			if size < 0 {
+				continue
+			}
+			b.WriteRune(r)
+			for i := 0; i < size; i++ {
+				n.srcStart = append(n.srcStart, start)
+				n.srcEnd = append(n.srcEnd, end)
+			}
+		}
+	}
+	n.text = b.String()
+	return n
+}
+
+// original maps a byte range of the normalized text back to the original text.
+func (n *normalized) original(start, end int) [2]int {
+	if start >= end || end > len(n.srcEnd) {
+		return [2]int{0, 0}
+	}
+	return [2]int{n.srcStart[start], n.srcEnd[end-1]}
+}
+
+// foldKana はカタカナをひらがなに変換する
+func foldKana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - ('ァ' - 'ぁ')
+	}
+	return r
+}
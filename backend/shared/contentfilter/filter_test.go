@@ -0,0 +1,78 @@
+package contentfilter
+
+import (
+	"testing"
+
+	"kojan-map/business/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "ばか", Normalize("バカ"))
+	assert.Equal(t, "ばか", Normalize("ﾊﾞｶ"))
+	assert.Equal(t, "abc123", Normalize("ＡＢＣ１２３"))
+}
+
+func TestFilter_NGWord(t *testing.T) {
+	f := New([]Rule{
+		{ID: 1, Kind: models.FilterKindNGWord, Pattern: "ばか", Action: models.FilterActionReject, Reason: "不適切な表現"},
+		{ID: 2, Kind: models.FilterKindNGWord, Pattern: "うざい", Action: models.FilterActionMask},
+	})
+
+	t.Run("rejects variants after normalization", func(t *testing.T) {
+		for _, text := range []string{"ばか", "バカ", "ﾊﾞｶだな"} {
+			result := f.Check(text)
+			assert.True(t, result.Rejected(), text)
+			assert.Equal(t, "不適切な表現", result.Reason)
+		}
+	})
+
+	t.Run("masks only the matched part of the original text", func(t *testing.T) {
+		result := f.Check("あいつｳｻﾞｲね")
+		assert.Equal(t, models.FilterActionMask, result.Action)
+		assert.Equal(t, "あいつ****ね", result.Text)
+	})
+
+	t.Run("passes clean text", func(t *testing.T) {
+		result := f.Check("高知の景色")
+		assert.Empty(t, result.Action)
+		assert.Equal(t, "高知の景色", result.Text)
+	})
+}
+
+func TestFilter_Detectors(t *testing.T) {
+	f := New(DefaultRules())
+
+	t.Run("masks phone numbers including full-width digits", func(t *testing.T) {
+		assert.Equal(t, "電話は************まで", f.Check("電話は088-123-4567まで").Text)
+		assert.Equal(t, "tel: *************", f.Check("tel: ０９０－１２３４－５６７８").Text)
+		assert.Equal(t, "**************", f.Check("+81 88 1234567").Text)
+	})
+
+	t.Run("masks email addresses", func(t *testing.T) {
+		assert.Equal(t, "連絡先 ****************", f.Check("連絡先 taro@example.com").Text)
+	})
+
+	t.Run("rejects My Number-like sequences", func(t *testing.T) {
+		assert.True(t, f.Check("番号 1234 5678 9012").Rejected())
+		assert.True(t, f.Check("123456789012").Rejected())
+	})
+
+	t.Run("ignores short and long digit runs", func(t *testing.T) {
+		assert.Empty(t, f.Check("2024年12月 営業時間 10-18").Action)
+		assert.Empty(t, f.Check("1234567890123456").Action)
+	})
+}
+
+func TestFilter_MostSevereActionWins(t *testing.T) {
+	f := New([]Rule{
+		{Kind: models.FilterKindNGWord, Pattern: "てすと", Action: models.FilterActionReview, Reason: "要確認"},
+		{Kind: models.FilterKindEmail, Action: models.FilterActionMask},
+	})
+
+	result := f.Check("テスト a@example.com")
+	assert.True(t, result.NeedsReview())
+	assert.Equal(t, "要確認", result.Reason)
+	assert.Equal(t, "テスト *************", result.Text)
+}
@@ -0,0 +1,133 @@
+package contentfilter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"kojan-map/business/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// defaultCacheTTL is how long rules loaded from the database are reused.
+const defaultCacheTTL = 30 * time.Second
+
+// Service loads rules from the content_filter_rule table and caches the
+// compiled filter. Detector kinds without a configured rule fall back to
+// DefaultRules.
+type Service struct {
+	db  *gorm.DB
+	ttl time.Duration
+
+	mu       sync.RWMutex
+	filter   *Filter
+	loadedAt time.Time
+}
+
+// NewService creates a new Service. A nil db uses only DefaultRules.
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db, ttl: defaultCacheTTL}
+}
+
+// Invalidate drops the cached rules so that the next check reloads them.
+func (s *Service) Invalidate() {
+	s.mu.Lock()
+	s.filter = nil
+	s.mu.Unlock()
+}
+
+// Check inspects text with the current rules.
+func (s *Service) Check(text string) (Result, error) {
+	f, err := s.current()
+	if err != nil {
+		return Result{Text: text}, err
+	}
+	return f.Check(text), nil
+}
+
+// Apply filters every field in place. Masked parts are replaced, a
+// rejected field returns *RejectedError, and review reports whether any
+// field needs moderation.
+func (s *Service) Apply(fields ...*string) (review bool, err error) {
+	if s == nil {
+		return false, nil
+	}
+	f, err := s.current()
+	if err != nil {
+		return false, err
+	}
+
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		result := f.Check(*field)
+		if result.Rejected() {
+			return false, &RejectedError{Reason: result.Reason}
+		}
+		if result.NeedsReview() {
+			review = true
+		}
+		*field = result.Text
+	}
+	return review, nil
+}
+
+func (s *Service) current() (*Filter, error) {
+	s.mu.RLock()
+	f, loadedAt := s.filter, s.loadedAt
+	s.mu.RUnlock()
+	if f != nil && time.Since(loadedAt) < s.ttl {
+		return f, nil
+	}
+
+	rules, err := s.loadRules()
+	if err != nil {
+		if f != nil {
+			// 読み込みに失敗した場合は直前のルールで継続する
+			return f, nil
+		}
+		return nil, err
+	}
+
+	f = New(rules)
+	s.mu.Lock()
+	s.filter = f
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return f, nil
+}
+
+func (s *Service) loadRules() ([]Rule, error) {
+	var rows []models.ContentFilterRule
+	if s.db != nil {
+		if err := s.db.Where("enabled = ?", true).Find(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to load content filter rules: %w", err)
+		}
+	}
+
+	configured := make(map[string]bool)
+	rules := make([]Rule, 0, len(rows)+3)
+	for _, row := range rows {
+		configured[row.Kind] = true
+		rules = append(rules, Rule{ID: row.ID, Kind: row.Kind, Pattern: row.Pattern, Action: row.Action, Reason: row.Reason})
+	}
+
+	// 管理者が検出ルールを設定していない種類は既定の動作を使う
+	if s.db != nil {
+		var kinds []string
+		if err := s.db.Model(&models.ContentFilterRule{}).Distinct("kind").Pluck("kind", &kinds).Error; err != nil {
+			return nil, fmt.Errorf("failed to load content filter rules: %w", err)
+		}
+		for _, k := range kinds {
+			configured[k] = true
+		}
+	}
+	for _, r := range DefaultRules() {
+		if !configured[r.Kind] {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
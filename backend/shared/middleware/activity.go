@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityRecorder records the days a user used the service (DAU in the admin analytics).
+type ActivityRecorder interface {
+	Touch(ctx context.Context, userID string) error
+}
+
+// RecordActivity records that the authenticated user used the service today.
+// AuthMiddleware の後に使用します。記録に失敗してもリクエストは拒否しません
+func RecordActivity(recorder ActivityRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID := c.GetString("userID"); userID != "" {
+			if err := recorder.Touch(c.Request.Context(), userID); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubRecorder records the users it was called with
+type stubRecorder struct {
+	users []string
+	err   error
+}
+
+func (s *stubRecorder) Touch(_ context.Context, userID string) error {
+	s.users = append(s.users, userID)
+	return s.err
+}
+
+func TestRecordActivity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	serve := func(recorder *stubRecorder, userID string) int {
+		r := gin.New()
+		r.GET("/", func(c *gin.Context) {
+			if userID != "" {
+				c.Set("userID", userID)
+			}
+			c.Next()
+		}, RecordActivity(recorder), func(c *gin.Context) { c.Status(http.StatusOK) })
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Code
+	}
+
+	recorder := &stubRecorder{}
+	assert.Equal(t, http.StatusOK, serve(recorder, "user-1"))
+	assert.Equal(t, http.StatusOK, serve(recorder, ""))
+	assert.Equal(t, []string{"user-1"}, recorder.users)
+
+	// 記録に失敗してもリクエストは拒否しない
+	assert.Equal(t, http.StatusOK, serve(&stubRecorder{err: errors.New("db down")}, "user-1"))
+}
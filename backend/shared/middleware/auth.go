@@ -1,15 +1,35 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
+
+	"kojan-map/business/pkg/accountstatus"
+	"kojan-map/business/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
-	"kojan-map/user/services"
 )
 
+// SessionValidator checks that the login session a token was issued for is still active.
+type SessionValidator interface {
+	ValidateSession(sessionID string) error
+}
+
+// AccountChecker checks that an account is not suspended, banned or deleted.
+// 利用できない場合は *accountstatus.Restriction を返します
+type AccountChecker interface {
+	CheckAccount(ctx context.Context, userID string) error
+}
+
 // AuthMiddleware verifies the JWT token and sets user info in the context.
-func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+// ユーザー・管理者のルートで共通のミドルウェアです（ビジネスのルートも同じ TokenManager を使用）
+// sessions が指定されている場合、セッションに紐づくトークンはセッションの失効も確認します
+// accounts が指定されている場合、利用停止中・利用禁止・削除済みのアカウントは 403 で拒否します
+func AuthMiddleware(tokens *jwt.TokenManager, sessions SessionValidator, accounts AccountChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,16 +45,71 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-		claims, err := authService.VerifyJWT(tokenString)
+		claims, err := tokens.VerifyTokenWithType(parts[1], "access")
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token", "details": err.Error()})
 			c.Abort()
 			return
 		}
 
-		c.Set("googleId", claims.GoogleID)
+		// 端末一覧からログアウトされたセッションのトークンを拒否
+		if claims.SessionID != "" && sessions != nil {
+			if err := sessions.ValidateSession(claims.SessionID); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "session is no longer valid"})
+				c.Abort()
+				return
+			}
+			c.Set("sessionId", claims.SessionID)
+		}
+
+		// トークン発行後に利用停止・利用禁止されたアカウントを拒否
+		if accounts != nil {
+			if err := accounts.CheckAccount(c.Request.Context(), claims.UserID); err != nil {
+				var restriction *accountstatus.Restriction
+				if errors.As(err, &restriction) {
+					c.JSON(http.StatusForbidden, restriction.Response())
+				} else {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check account status"})
+				}
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Set("googleId", claims.UserID)
 		c.Set("userRole", claims.Role)
+		c.Set("user", claims)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware sets user info in the context when a valid access token is sent.
+// 公開エンドポイントで投稿者本人かどうかを判定するために使います
+// トークンがない・無効・失効したセッションの場合は未ログインとして続行します
+func OptionalAuthMiddleware(tokens *jwt.TokenManager, sessions SessionValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			c.Next()
+			return
+		}
+		claims, err := tokens.VerifyTokenWithType(parts[1], "access")
+		if err != nil {
+			c.Next()
+			return
+		}
+		if claims.SessionID != "" && sessions != nil {
+			if err := sessions.ValidateSession(claims.SessionID); err != nil {
+				c.Next()
+				return
+			}
+		}
+
+		c.Set("userID", claims.UserID)
+		c.Set("googleId", claims.UserID)
+		c.Set("userRole", claims.Role)
+		c.Set("user", claims)
 		c.Next()
 	}
 }
@@ -51,3 +126,25 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireStepUp rejects tokens whose second factor is older than window.
+// 削除・承認など取り消せない管理操作の前に、POST /api/admin/step-up で追加認証を求めます
+// （RFC 9470 に合わせて 401 と WWW-Authenticate で必要な条件を返す）
+func RequireStepUp(window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("user")
+		claims, ok := value.(*jwt.Claims)
+		if !ok || !claims.SteppedUpWithin(window, time.Now()) {
+			c.Header("WWW-Authenticate", fmt.Sprintf(
+				`Bearer error="insufficient_user_authentication", error_description="step-up authentication required", max_age=%d`,
+				int(window.Seconds())))
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":        "step-up authentication required",
+				"stepUpWindow": int(window.Seconds()),
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
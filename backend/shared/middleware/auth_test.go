@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kojan-map/business/pkg/accountstatus"
+	"kojan-map/business/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAccounts returns the configured error for every account
+type stubAccounts struct{ err error }
+
+func (s stubAccounts) CheckAccount(context.Context, string) error { return s.err }
+
+func TestAuthMiddleware_AccountChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := jwt.NewTokenManagerWithSecret("test-secret")
+	token, err := tokens.GenerateToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	until := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		accounts AccountChecker
+		want     int
+		wantBody string
+	}{
+		{"no checker", nil, http.StatusOK, ""},
+		{"active account", stubAccounts{}, http.StatusOK, ""},
+		{"suspended", stubAccounts{&accountstatus.Restriction{Kind: accountstatus.KindSuspended, Reason: "spam", Until: &until}}, http.StatusForbidden, `"suspendedUntil":"2026-01-10T09:00:00Z"`},
+		{"banned", stubAccounts{&accountstatus.Restriction{Kind: accountstatus.KindBanned}}, http.StatusForbidden, `"code":"ACCOUNT_BANNED"`},
+		{"check failed", stubAccounts{errors.New("db down")}, http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/me", AuthMiddleware(tokens, nil, tt.accounts), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.want, resp.Code)
+			assert.Contains(t, resp.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+// stubSessions returns the configured error for every session
+type stubSessions struct{ err error }
+
+func (s stubSessions) ValidateSession(string) error { return s.err }
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := jwt.NewTokenManagerWithSecret("test-secret")
+	token, err := tokens.GenerateToken("user-1", "user@example.com", "user")
+	require.NoError(t, err)
+	sessionToken, err := tokens.GenerateSessionToken("user-1", "user@example.com", "user", "session-1", time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		header   string
+		sessions SessionValidator
+		want     string
+	}{
+		{"no token", "", nil, ""},
+		{"valid token", "Bearer " + token, nil, "user-1"},
+		{"invalid token", "Bearer invalid", nil, ""},
+		{"invalid format", token, nil, ""},
+		{"active session", "Bearer " + sessionToken, stubSessions{}, "user-1"},
+		{"revoked session", "Bearer " + sessionToken, stubSessions{errors.New("revoked")}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/posts", OptionalAuthMiddleware(tokens, tt.sessions), func(c *gin.Context) {
+				c.String(http.StatusOK, c.GetString("googleId"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, tt.want, resp.Body.String())
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header used to propagate the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the context key of the request ID
+const RequestIDKey = "requestId"
+
+// validRequestID limits client-supplied request IDs to safe characters so that they can be logged as-is
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestIDMiddleware sets a request ID in the context and the response header.
+// クライアント（ロードバランサーなど）が X-Request-ID を付けている場合はそれを引き継ぎ、
+// ない場合や不正な形式の場合は新しく発行します（監査ログとアクセスログの突き合わせに使用）
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetString(RequestIDKey)
+		if id == "" {
+			id = c.GetHeader(RequestIDHeader)
+			if !validRequestID.MatchString(id) {
+				id = uuid.New().String()
+			}
+			c.Set(RequestIDKey, id)
+		}
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
@@ -0,0 +1,386 @@
+// Package migrate applies the numbered SQL migrations of the database schema.
+//
+// A migration is a pair of files named NNNNNN_name.up.sql and NNNNNN_name.down.sql.
+// Applied versions are recorded in the schema_migrations table. MySQL commits DDL
+// statements implicitly, so a migration that fails halfway is not recorded and the
+// statements that already ran must be reverted by hand before retrying.
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TableName is the table that records the applied migrations
+const TableName = "schema_migrations"
+
+// lockName is the MySQL named lock held while migrations run, so that two
+// processes starting at the same time do not apply the same migration twice
+const lockName = "kojanmap_schema_migrations"
+
+// lockTimeout is how long to wait for another process to finish migrating (seconds)
+const lockTimeout = 60
+
+var (
+	ErrInvalidSteps   = errors.New("number of migrations must be positive")
+	ErrInvalidName    = errors.New("migration name must contain letters or digits")
+	ErrUnknownVersion = errors.New("applied migration has no migration file")
+	ErrLocked         = errors.New("another process is running migrations")
+)
+
+var (
+	// fileName matches 000001_init.up.sql
+	fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	// underscores matches the separators collapsed by sanitizeName
+	underscores = regexp.MustCompile(`_+`)
+)
+
+// Migration is a numbered schema change with the SQL to apply and revert it
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String returns the file name prefix of the migration (000001_init)
+func (m Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+// Status is a migration with the time it was applied (nil if pending)
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// record is a row of schema_migrations
+type record struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:appliedAt"`
+}
+
+func (record) TableName() string {
+	return TableName
+}
+
+// Load reads the migrations in fsys sorted by version
+// Files that do not follow the naming scheme are ignored
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	now        func() time.Time
+}
+
+// New creates a Migrator for the migrations in fsys
+func New(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, now: time.Now}, nil
+}
+
+// Status returns every migration with the time it was applied
+// Applied versions without a migration file are included with an empty Up and Down
+func (m *Migrator) Status() ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(func(db *gorm.DB) error {
+		var err error
+		statuses, err = m.status(db)
+		return err
+	})
+	return statuses, err
+}
+
+// Pending returns the migrations that have not been applied yet
+func (m *Migrator) Pending() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies up to n pending migrations in version order (all of them if n <= 0)
+// and returns the migrations that were applied
+func (m *Migrator) Up(n int) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(func(db *gorm.DB) error {
+		statuses, err := m.status(db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if n > 0 && len(applied) == n {
+				break
+			}
+			if s.AppliedAt != nil {
+				continue
+			}
+			if err := run(db, s.Migration, s.Up); err != nil {
+				return err
+			}
+			if err := db.Create(&record{Version: s.Version, Name: s.Name, AppliedAt: m.now()}).Error; err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", s.Migration, err)
+			}
+			applied = append(applied, s.Migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the n most recently applied migrations and returns them in the order they were reverted
+func (m *Migrator) Down(n int) ([]Migration, error) {
+	if n <= 0 {
+		return nil, ErrInvalidSteps
+	}
+
+	var reverted []Migration
+	err := m.withLock(func(db *gorm.DB) error {
+		statuses, err := m.status(db)
+		if err != nil {
+			return err
+		}
+		for i := len(statuses) - 1; i >= 0 && len(reverted) < n; i-- {
+			s := statuses[i]
+			if s.AppliedAt == nil {
+				continue
+			}
+			if s.Down == "" {
+				return fmt.Errorf("%w: %s", ErrUnknownVersion, s.Migration)
+			}
+			if err := run(db, s.Migration, s.Down); err != nil {
+				return err
+			}
+			if err := db.Delete(&record{}, "version = ?", s.Version).Error; err != nil {
+				return fmt.Errorf("failed to unrecord migration %s: %w", s.Migration, err)
+			}
+			reverted = append(reverted, s.Migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Baseline records the pending migrations up to version as applied without running them
+// and returns them. It is for databases whose schema was created before the migrations
+// were introduced and already matches that version
+func (m *Migrator) Baseline(version int64) ([]Migration, error) {
+	var recorded []Migration
+	err := m.withLock(func(db *gorm.DB) error {
+		statuses, err := m.status(db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Version > version {
+				break
+			}
+			if s.AppliedAt != nil {
+				continue
+			}
+			if err := db.Create(&record{Version: s.Version, Name: s.Name, AppliedAt: m.now()}).Error; err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", s.Migration, err)
+			}
+			recorded = append(recorded, s.Migration)
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// status merges the migration files with the applied versions
+func (m *Migrator) status(db *gorm.DB) ([]Status, error) {
+	var records []record
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", TableName, err)
+	}
+	applied := make(map[int64]record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := Status{Migration: migration}
+		if r, ok := applied[migration.Version]; ok {
+			at := r.AppliedAt
+			s.AppliedAt = &at
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, s)
+	}
+	for _, r := range applied {
+		at := r.AppliedAt
+		statuses = append(statuses, Status{Migration: Migration{Version: r.Version, Name: r.Name}, AppliedAt: &at})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// withLock creates schema_migrations if needed and runs fn on a single connection
+// holding the migration lock
+func (m *Migrator) withLock(fn func(db *gorm.DB) error) error {
+	return m.db.Connection(func(db *gorm.DB) error {
+		var locked int
+		if err := db.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if locked != 1 {
+			return ErrLocked
+		}
+		defer db.Exec("SELECT RELEASE_LOCK(?)", lockName)
+
+		if err := db.Exec("CREATE TABLE IF NOT EXISTS `" + TableName + "` (" +
+			"`version` bigint NOT NULL, " +
+			"`name` varchar(255) NOT NULL, " +
+			"`appliedAt` datetime(3) NOT NULL, " +
+			"PRIMARY KEY (`version`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4").Error; err != nil {
+			return fmt.Errorf("failed to create %s: %w", TableName, err)
+		}
+		return fn(db)
+	})
+}
+
+// run executes the statements of a migration one by one
+func run(db *gorm.DB, m Migration, sql string) error {
+	for _, stmt := range splitStatements(sql) {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("migration %s failed: %w", m, err)
+		}
+	}
+	return nil
+}
+
+// splitStatements splits a migration into statements
+// A statement ends with a line ending in ";". Lines starting with "--" are comments
+func splitStatements(sql string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// Create writes an empty migration pair named name to dir with the next version number
+// and returns the paths of the up and down files
+func Create(dir, name string) (string, string, error) {
+	name = sanitizeName(name)
+	if name == "" {
+		return "", "", ErrInvalidName
+	}
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	m := Migration{Version: version, Name: name}
+	up := filepath.Join(dir, m.String()+".up.sql")
+	down := filepath.Join(dir, m.String()+".down.sql")
+	if err := os.WriteFile(up, []byte("-- "+m.String()+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- revert "+m.String()+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
+
+// sanitizeName lowercases name and replaces characters other than letters and digits with "_"
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(underscores.ReplaceAllString(b.String(), "_"), "_")
+}
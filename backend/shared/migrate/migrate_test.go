@@ -0,0 +1,100 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_posts.up.sql":   {Data: []byte("ALTER TABLE post ADD COLUMN x int;")},
+		"000002_posts.down.sql": {Data: []byte("ALTER TABLE post DROP COLUMN x;")},
+		"000001_init.up.sql":    {Data: []byte("CREATE TABLE a (id int);")},
+		"000001_init.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"README.md":             {Data: []byte("ignored")},
+		"migrations.go":         {Data: []byte("package migrations")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE a;", migrations[0].Down)
+	assert.Equal(t, "000002_posts", migrations[1].String())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"missing down", fstest.MapFS{
+			"000001_init.up.sql": {Data: []byte("CREATE TABLE a (id int);")},
+		}},
+		{"empty up", fstest.MapFS{
+			"000001_init.up.sql":   {Data: []byte("  \n")},
+			"000001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+		}},
+		{"duplicate version", fstest.MapFS{
+			"000001_init.up.sql":    {Data: []byte("CREATE TABLE a (id int);")},
+			"000001_init.down.sql":  {Data: []byte("DROP TABLE a;")},
+			"000001_other.up.sql":   {Data: []byte("CREATE TABLE b (id int);")},
+			"000001_other.down.sql": {Data: []byte("DROP TABLE b;")},
+		}},
+		{"version zero", fstest.MapFS{
+			"000000_init.up.sql":   {Data: []byte("CREATE TABLE a (id int);")},
+			"000000_init.down.sql": {Data: []byte("DROP TABLE a;")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	sql := `-- コメント
+CREATE TABLE a (
+  id int NOT NULL, -- 主キー
+  name varchar(10) DEFAULT 'a;b'
+);
+
+-- 既存の行
+UPDATE a SET name = 'x';
+INSERT INTO a (id) VALUES (1)`
+
+	assert.Equal(t, []string{
+		"CREATE TABLE a (\n  id int NOT NULL, -- 主キー\n  name varchar(10) DEFAULT 'a;b'\n)",
+		"UPDATE a SET name = 'x'",
+		"INSERT INTO a (id) VALUES (1)",
+	}, splitStatements(sql))
+	assert.Empty(t, splitStatements("-- only a comment\n"))
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+
+	up, down, err := Create(dir, "Add Users!")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "000001_add_users.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "000001_add_users.down.sql"), down)
+
+	up, _, err = Create(dir, "posts")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "000002_posts.up.sql"), up)
+
+	migrations, err := Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2)
+
+	_, _, err = Create(dir, "--")
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
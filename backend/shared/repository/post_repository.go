@@ -1,7 +1,7 @@
 package repository
 
 import (
-	"kojan-map/shared/models"
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -16,16 +16,16 @@ func NewPostRepository(db *gorm.DB) *PostRepository {
 	return &PostRepository{db: db}
 }
 
-// CountAll counts all posts
+// CountAll counts all posts including removed ones
 func (r *PostRepository) CountAll() (int, error) {
 	var count int64
-	result := r.db.Model(&models.Post{}).Count(&count)
+	result := r.db.Unscoped().Model(&models.Post{}).Count(&count)
 	return int(count), result.Error
 }
 
-// SumReactions sums all reactions across all posts
+// SumReactions sums all reactions across all posts including removed ones
 func (r *PostRepository) SumReactions() (int, error) {
 	var sum int64
-	result := r.db.Model(&models.Post{}).Select("COALESCE(SUM(numReaction), 0)").Scan(&sum)
+	result := r.db.Unscoped().Model(&models.Post{}).Select("COALESCE(SUM(numReaction), 0)").Scan(&sum)
 	return int(sum), result.Error
 }
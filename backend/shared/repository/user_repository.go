@@ -1,7 +1,10 @@
 package repository
 
 import (
-	"kojan-map/shared/models"
+	"strings"
+	"time"
+
+	"kojan-map/business/pkg/models"
 
 	"gorm.io/gorm"
 )
@@ -16,15 +19,57 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-// FindAll retrieves all users with pagination
-func (r *UserRepository) FindAll(page, pageSize int) ([]models.User, int, error) {
-	var users []models.User
-	var total int64
+// WithTx returns a UserRepository that runs in the given transaction
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
+// User statuses used to filter the admin user list
+const (
+	UserStatusActive    = "active"    // 削除されておらず、利用停止・利用禁止もされていない
+	UserStatusSuspended = "suspended" // 有効な利用停止がある
+	UserStatusBanned    = "banned"    // 有効な利用禁止がある
+	UserStatusDeleted   = "deleted"   // 管理者が削除した
+)
+
+// UserFilter narrows the admin user list; empty fields are ignored
+type UserFilter struct {
+	Query          string // googleId の完全一致、または gmail の部分一致
+	Role           models.Role
+	Status         string     // UserStatus* のいずれか
+	RegisteredFrom *time.Time // この日時以降に登録（含む）
+	RegisteredTo   *time.Time // この日時より前に登録（含まない）
+	At             time.Time  // Status の利用停止・利用禁止を判定する日時
+}
+
+// UserSummary is a user with the counts shown in the admin user list
+type UserSummary struct {
+	models.User
+	PostCount       int64 `gorm:"column:postCount" json:"postCount"`
+	ReportsReceived int64 `gorm:"column:reportsReceived" json:"reportsReceived"` // 投稿への通報数
+	ReportsFiled    int64 `gorm:"column:reportsFiled" json:"reportsFiled"`       // ユーザーがした通報数
+}
 
-	r.db.Model(&models.User{}).Count(&total)
+// userSummaryColumns selects the user and the per-user counts (orderBy can use the count aliases)
+const userSummaryColumns = `user.*,
+	(SELECT COUNT(*) FROM post WHERE post.userId = user.googleId) AS postCount,
+	(SELECT COUNT(*) FROM report JOIN post ON post.postId = report.postId WHERE post.userId = user.googleId) AS reportsReceived,
+	(SELECT COUNT(*) FROM report WHERE report.userId = user.googleId) AS reportsFiled`
 
+// activeSanctionSQL matches an active suspension or ban of the given kind (args: kind, at, at)
+const activeSanctionSQL = `EXISTS (SELECT 1 FROM user_sanction s WHERE s.userId = user.googleId AND s.kind = ?
+	AND s.liftedAt IS NULL AND s.startsAt <= ? AND (s.endsAt IS NULL OR s.endsAt > ?))`
+
+// FindAll retrieves users matching the filter with their counts, ordered by orderBy, with pagination
+func (r *UserRepository) FindAll(filter UserFilter, orderBy string, page, pageSize int) ([]UserSummary, int, error) {
+	var total int64
+	if err := r.filtered(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []UserSummary
 	offset := (page - 1) * pageSize
-	result := r.db.Offset(offset).Limit(pageSize).Find(&users)
+	result := r.filtered(filter).Select(userSummaryColumns).Order(orderBy).Offset(offset).Limit(pageSize).Find(&users)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -32,6 +77,66 @@ func (r *UserRepository) FindAll(page, pageSize int) ([]models.User, int, error)
 	return users, int(total), nil
 }
 
+// Each calls fn for every user matching the filter, ordered by orderBy, reading rows as they are needed
+// CSV の出力など、件数の多い結果をメモリに載せずに処理するために使います
+func (r *UserRepository) Each(filter UserFilter, orderBy string, fn func(*UserSummary) error) error {
+	rows, err := r.filtered(filter).Select(userSummaryColumns).Order(orderBy).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user UserSummary
+		if err := r.db.ScanRows(rows, &user); err != nil {
+			return err
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filtered returns the user query with the filter applied
+func (r *UserRepository) filtered(filter UserFilter) *gorm.DB {
+	query := r.db.Model(&models.User{})
+	if filter.Query != "" {
+		query = query.Where("user.googleId = ? OR user.gmail LIKE ?", filter.Query, "%"+EscapeLike(filter.Query)+"%")
+	}
+	if filter.Role != "" {
+		query = query.Where("user.role = ?", filter.Role)
+	}
+	if filter.RegisteredFrom != nil {
+		query = query.Where("user.registrationDate >= ?", *filter.RegisteredFrom)
+	}
+	if filter.RegisteredTo != nil {
+		query = query.Where("user.registrationDate < ?", *filter.RegisteredTo)
+	}
+
+	at := filter.At
+	switch filter.Status {
+	case UserStatusDeleted:
+		query = query.Where("user.deletedAt IS NOT NULL")
+	case UserStatusActive:
+		query = query.Where("user.deletedAt IS NULL").
+			Where("NOT "+activeSanctionSQL, models.SanctionSuspension, at, at).
+			Where("NOT "+activeSanctionSQL, models.SanctionBan, at, at)
+	case UserStatusSuspended:
+		query = query.Where(activeSanctionSQL, models.SanctionSuspension, at, at)
+	case UserStatusBanned:
+		query = query.Where(activeSanctionSQL, models.SanctionBan, at, at)
+	}
+	return query
+}
+
+// EscapeLike escapes the LIKE wildcards in s so that it matches literally
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // FindByGoogleID finds a user by their Google ID
 func (r *UserRepository) FindByGoogleID(googleID string) (*models.User, error) {
 	var user models.User
@@ -42,6 +147,18 @@ func (r *UserRepository) FindByGoogleID(googleID string) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByGoogleIDs retrieves the users with the given IDs (missing IDs are skipped)
+func (r *UserRepository) FindByGoogleIDs(googleIDs []string) ([]models.User, error) {
+	var users []models.User
+	if len(googleIDs) == 0 {
+		return users, nil
+	}
+	if err := r.db.Where("googleId IN ?", googleIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // CountAll counts all users
 func (r *UserRepository) CountAll() (int, error) {
 	var count int64
@@ -56,6 +173,11 @@ func (r *UserRepository) CountByRole(role models.Role) (int, error) {
 	return int(count), result.Error
 }
 
+// UpdateRole changes the role of a user
+func (r *UserRepository) UpdateRole(googleID string, role models.Role) error {
+	return r.db.Model(&models.User{}).Where("googleId = ?", googleID).Update("role", role).Error
+}
+
 // SoftDelete marks a user as deleted
 func (r *UserRepository) SoftDelete(googleID string) error {
 	return r.db.Model(&models.User{}).
@@ -1,47 +1,152 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"kojan-map/business/pkg/accountstatus"
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/user/models"
 	"kojan-map/user/services"
 )
 
 // AuthHandler 認証関連のハンドラー
 type AuthHandler struct {
-	userService *services.UserService
-	authService *services.AuthService
+	userService     *services.UserService
+	authService     *services.AuthService
+	refreshService  *services.RefreshTokenService
+	sessionService  *services.SessionService
+	identityService *services.IdentityService
 }
 
 // NewAuthHandler 認証ハンドラーを初期化
-func NewAuthHandler(userService *services.UserService, authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(userService *services.UserService, authService *services.AuthService, refreshService *services.RefreshTokenService, sessionService *services.SessionService, identityService *services.IdentityService) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		authService: authService,
+		userService:     userService,
+		authService:     authService,
+		refreshService:  refreshService,
+		sessionService:  sessionService,
+		identityService: identityService,
 	}
 }
 
-// Register はGoogleトークンを使用してユーザー登録またはログインを行います。
+// idTokenRequest ログイン・アカウント連携に使うIDトークン
+// google_token は以前の形式で、provider を省略した id_token（Google）と同じ
+type idTokenRequest struct {
+	Provider    string `json:"provider"`
+	IDToken     string `json:"id_token"`
+	GoogleToken string `json:"google_token"`
+}
+
+// token 指定されたIDトークン
+func (r idTokenRequest) token() string {
+	if r.IDToken != "" {
+		return r.IDToken
+	}
+	return r.GoogleToken
+}
+
+// verifyStatus IDトークンの検証エラーに対応するHTTPステータス
+func verifyStatus(err error) int {
+	if errors.Is(err, oauth.ErrUnknownProvider) {
+		return http.StatusBadRequest
+	}
+	return http.StatusUnauthorized
+}
+
+// loginStatus ログイン・アカウント連携のエラーに対応するHTTPステータス
+func loginStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrIdentityEmailInUse),
+		errors.Is(err, services.ErrIdentityLinkedToOtherUser),
+		errors.Is(err, services.ErrProviderAlreadyLinked):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondAccountError アカウントが利用できない場合のレスポンス（利用停止の場合は終了日時を含む）
+func respondAccountError(c *gin.Context, err error) {
+	var restriction *accountstatus.Restriction
+	if errors.As(err, &restriction) {
+		c.JSON(http.StatusForbidden, restriction.Response())
+		return
+	}
+	c.Error(err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check account status"})
+}
+
+// login IDトークンを検証し、連携したユーザー（未登録の場合は新規作成）のセッションを発行
+func (ah *AuthHandler) login(c *gin.Context, req idTokenRequest, role string) (*models.User, *models.Session, *models.SignInHistory, bool) {
+	if req.token() == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id_token is required"})
+		return nil, nil, nil, false
+	}
+
+	identity, err := ah.authService.VerifyIDToken(req.Provider, req.token())
+	if err != nil {
+		c.Error(err)
+		c.JSON(verifyStatus(err), gin.H{"error": err.Error()})
+		return nil, nil, nil, false
+	}
+
+	user, err := ah.identityService.ResolveUser(*identity, role)
+	if err != nil {
+		c.Error(err)
+		log.Printf("[Login] ResolveUser error: %v", err)
+		c.JSON(loginStatus(err), gin.H{"error": err.Error()})
+		return nil, nil, nil, false
+	}
+
+	// 利用停止中・利用禁止のアカウントにはセッションを発行しない
+	if err := ah.authService.CheckAccount(user.GoogleID); err != nil {
+		respondAccountError(c, err)
+		return nil, nil, nil, false
+	}
+
+	session, signIn, err := ah.userService.LoginFromDevice(user.GoogleID, deviceInfo(c))
+	if err != nil {
+		c.Error(err)
+		log.Printf("[Login] LoginFromDevice error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, nil, false
+	}
+	return user, session, signIn, true
+}
+
+// deviceInfo リクエストの端末情報
+func deviceInfo(c *gin.Context) models.DeviceInfo {
+	return models.DeviceInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// Register はIDトークン（Google・LINE など）を使用してユーザー登録またはログインを行います。
 // 新規ユーザーの場合は登録し、既存ユーザーの場合はログインします。
 //
 // @Summary ユーザー登録・ログイン
-// @Description Googleトークンを使用してユーザー登録またはログインを行います
+// @Description IDトークンを使用してユーザー登録またはログインを行います。provider を省略した場合は Google です（google_token は以前の形式）
 // @Tags 認証
 // @Accept json
 // @Produce json
-// @Param request body object{google_token=string} true "Google認証トークン"
+// @Param request body object{provider=string,id_token=string,google_token=string,role=string} true "プロバイダーとIDトークン"
 // @Success 200 {object} object{sessionId=string} "セッションID"
-// @Failure 400 {object} object{error=string} "不正なリクエスト"
-// @Failure 401 {object} object{error=string} "無効なGoogleトークン"
+// @Failure 400 {object} object{error=string} "不正なリクエスト・未対応のプロバイダー"
+// @Failure 401 {object} object{error=string} "無効なIDトークン"
+// @Failure 403 {object} object{error=string,code=string,reason=string,suspendedUntil=string} "利用停止中・利用禁止・削除済みのアカウント（利用停止の場合は終了日時）"
+// @Failure 409 {object} object{error=string} "メールアドレスが別のログイン方法で登録済み"
 // @Failure 500 {object} object{error=string} "サーバーエラー"
 // @Router /api/users/register [post]
 func (ah *AuthHandler) Register(c *gin.Context) {
 	var req struct {
-		GoogleToken string `json:"google_token" binding:"required"`
-		Role        string `json:"role" binding:"omitempty,oneof=user business"`
+		idTokenRequest
+		Role string `json:"role" binding:"omitempty,oneof=user business"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -49,37 +154,32 @@ func (ah *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Google トークンを検証
-	googleResp, err := ah.authService.VerifyGoogleToken(req.GoogleToken)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid google token"})
-		return
-	}
-
-	session, err := ah.userService.RegisterOrLogin(googleResp.Sub, googleResp.Email, req.Role)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	_, session, _, ok := ah.login(c, req.idTokenRequest, req.Role)
+	if !ok {
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"sessionId": session.SessionID})
 }
 
-// Logout は現在のセッションを無効化してログアウトします。
+// Logout は現在のセッションとリフレッシュトークンを無効化してログアウトします。
+// refresh_token を指定した場合はそのログインのトークンのみ、省略した場合はすべてのトークンを失効させます。
 //
 // @Summary ログアウト
-// @Description 現在のセッションを無効化します
+// @Description 現在のセッションとリフレッシュトークンを無効化します
 // @Tags 認証
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body object{sessionId=string,refresh_token=string} false "セッションIDとリフレッシュトークン"
 // @Success 200 {object} object{sessionId=string} "セッションID"
 // @Failure 400 {object} object{error=string} "セッションIDが必要"
 // @Failure 500 {object} object{error=string} "サーバーエラー"
 // @Router /api/auth/logout [put]
 func (ah *AuthHandler) Logout(c *gin.Context) {
 	var req struct {
-		SessionID string `json:"sessionId"`
+		SessionID    string `json:"sessionId"`
+		RefreshToken string `json:"refresh_token"`
 	}
 	_ = c.ShouldBindJSON(&req)
 	sessionID := req.SessionID
@@ -91,6 +191,18 @@ func (ah *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
+	googleID := c.GetString("googleId")
+	var err error
+	if req.RefreshToken != "" {
+		err = ah.refreshService.RevokeFamily(googleID, req.RefreshToken)
+	} else {
+		err = ah.refreshService.RevokeAll(googleID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := ah.userService.Logout(sessionID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -130,22 +242,25 @@ func (ah *AuthHandler) Withdrawal(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "user deleted"})
 }
 
-// ExchangeToken はGoogleトークンを検証し、JWT トークンとユーザー情報を返します。
+// ExchangeToken はIDトークン（Google・LINE など）を検証し、JWT トークンとユーザー情報を返します。
+// 初めて使う外部アカウントの場合はユーザーを登録して連携します。
 //
-// @Summary GoogleトークンをJWTトークンに交換
-// @Description GoogleトークンをJWTトークンに交換し、ユーザー情報を返します
+// @Summary IDトークンをJWTトークンに交換
+// @Description IDトークンをJWTトークンに交換し、ユーザー情報を返します。provider を省略した場合は Google です（google_token は以前の形式）
 // @Tags 認証
 // @Accept json
 // @Produce json
-// @Param request body object{google_token=string,role=string} true "Googleトークンとロール(general/business)"
-// @Success 200 {object} object{jwt_token=string,user=object} "JWTトークンとユーザー情報"
-// @Failure 400 {object} object{error=string} "不正なリクエスト"
+// @Param request body object{provider=string,id_token=string,google_token=string,role=string} true "プロバイダー・IDトークンとロール(user/business)"
+// @Success 200 {object} object{jwt_token=string,refresh_token=string,user=object,sessionId=string,newDevice=bool} "JWTトークン・リフレッシュトークンとユーザー情報（newDeviceは初めて使う端末からのログイン）"
+// @Failure 400 {object} object{error=string} "不正なリクエスト・未対応のプロバイダー"
 // @Failure 401 {object} object{error=string} "認証失敗"
+// @Failure 403 {object} object{error=string,code=string,reason=string,suspendedUntil=string} "利用停止中・利用禁止・削除済みのアカウント（利用停止の場合は終了日時）"
+// @Failure 409 {object} object{error=string} "メールアドレスが別のログイン方法で登録済み（ログイン後にアカウント連携してください）"
 // @Router /api/auth/exchange-token [post]
 func (ah *AuthHandler) ExchangeToken(c *gin.Context) {
 	var req struct {
-		GoogleToken string `json:"google_token" binding:"required"`
-		Role        string `json:"role" binding:"required,oneof=user business"`
+		idTokenRequest
+		Role string `json:"role" binding:"required,oneof=user business"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -154,47 +269,37 @@ func (ah *AuthHandler) ExchangeToken(c *gin.Context) {
 		return
 	}
 
-	// 1. Googleトークン検証
-	googleResp, err := ah.authService.VerifyGoogleToken(req.GoogleToken)
-	if err != nil {
-		c.Error(err)
-		log.Printf("[ExchangeToken] error: %v, role: %s", err, req.Role)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	// 1. IDトークン検証・ユーザー登録またはログイン（セッション発行・ログイン履歴の記録）
+	user, session, signIn, ok := ah.login(c, req.idTokenRequest, req.Role)
+	if !ok {
 		return
 	}
 
-	// 2. ユーザー登録またはログイン（セッション発行）
-	session, err := ah.userService.RegisterOrLogin(googleResp.Sub, googleResp.Email, req.Role)
+	// 2. JWT発行（セッションを失効させるとトークンも使えなくなる）
+	jwttoken, err := ah.authService.GenerateSessionJWT(user, session.SessionID)
 	if err != nil {
 		c.Error(err)
-		log.Printf("[ExchangeToken] RegisterOrLogin error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	// 3. ユーザー情報取得
-	user, err := ah.authService.GetUserByID(googleResp.Sub)
-	if err != nil {
-		c.Error(err)
-		log.Printf("[ExchangeToken] GetUserByID error: %v", err)
+		log.Printf("[ExchangeToken] GenerateJWT error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 4. JWT発行
-	jwttoken, err := ah.authService.GenerateJWT(user)
+	// 3. リフレッシュトークン発行（ログインごとに新しいファミリー）
+	refreshToken, err := ah.refreshService.Issue(user.GoogleID, session.SessionID)
 	if err != nil {
 		c.Error(err)
-		log.Printf("[ExchangeToken] GenerateJWT error: %v", err)
+		log.Printf("[ExchangeToken] Issue refresh token error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 5. レスポンス
+	// 4. レスポンス
 	c.JSON(http.StatusOK, gin.H{
-		"jwt_token": jwttoken,
-		"user":      user,
-		"sessionId": session.SessionID,
+		"jwt_token":     jwttoken,
+		"refresh_token": refreshToken,
+		"user":          user,
+		"sessionId":     session.SessionID,
+		"newDevice":     signIn.NewDevice,
 	})
 }
 
@@ -228,7 +333,7 @@ func (ah *AuthHandler) VerifyToken(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"user_id": claims.UserID,
-		"email":   claims.Email,
+		"email":   claims.Gmail,
 		"role":    claims.Role,
 	})
 }
@@ -275,50 +380,91 @@ func (ah *AuthHandler) GetCurrentUser(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
-// Refresh は既存のJWTトークンから新しいトークンを生成します。
+// Refresh はリフレッシュトークンを使って新しいアクセストークンを発行します。
+// リフレッシュトークンは1回限り有効で、使用するたびに新しいものに置き換わります。
+// 使用済みのトークンが再度使われた場合は漏洩とみなし、同じログインのトークンをすべて失効させます。
 //
-// @Summary JWTトークンのリフレッシュ
-// @Description 既存のJWTトークンから新しいトークンを生成します
+// @Summary アクセストークンの再発行
+// @Description リフレッシュトークンを新しいアクセストークンとリフレッシュトークンに交換します
 // @Tags 認証
 // @Accept json
 // @Produce json
-// @Security BearerAuth
-// @Success 200 {object} object{jwt_token=string} "新しいJWTトークン"
-// @Failure 401 {object} object{error=string} "認証エラー"
-// @Failure 404 {object} object{error=string} "ユーザーが見つかりません"
+// @Param request body object{refresh_token=string} true "リフレッシュトークン"
+// @Success 200 {object} object{jwt_token=string,refresh_token=string} "新しいトークン"
+// @Failure 400 {object} object{error=string} "不正なリクエスト"
+// @Failure 401 {object} object{error=string} "無効・期限切れ・再利用されたリフレッシュトークン"
+// @Failure 403 {object} object{error=string,code=string,reason=string,suspendedUntil=string} "利用停止中・利用禁止・削除済みのアカウント（利用停止の場合は終了日時）"
 // @Failure 500 {object} object{error=string} "サーバーエラー"
-// @Router /api/auth/refresh [post]
+// @Router /api/auth/token/refresh [post]
 func (ah *AuthHandler) Refresh(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "no authorization header"})
-		return
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
-
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
 		return
 	}
 
-	token := parts[1]
-	claims, err := ah.authService.VerifyJWT(token)
+	issued, refreshToken, err := ah.refreshService.Rotate(req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			log.Printf("[Refresh] refresh token reuse detected; token family revoked")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenExpired):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	user, err := ah.authService.GetUserByID(claims.UserID)
+	user, err := ah.authService.GetUserByID(issued.GoogleID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+	// 利用停止前に発行されたリフレッシュトークンでもアクセストークンを再発行しない
+	if err := ah.authService.CheckAccount(user.GoogleID); err != nil {
+		respondAccountError(c, err)
 		return
 	}
 
-	newToken, err := ah.authService.GenerateJWT(user)
+	var newToken string
+	if issued.SessionID != "" {
+		// 失効したセッション（端末）のトークンは再発行しない
+		if err := ah.sessionService.ValidateSession(issued.SessionID); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err := ah.sessionService.ExtendSession(issued.SessionID, ah.userService.SessionTTL()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		newToken, err = ah.authService.GenerateSessionJWT(user, issued.SessionID)
+	} else {
+		newToken, err = ah.authService.GenerateJWT(user)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"jwt_token": newToken})
+	c.JSON(http.StatusOK, gin.H{
+		"jwt_token":     newToken,
+		"refresh_token": refreshToken,
+	})
+}
+
+// JWKS はトークン検証用の公開鍵一覧を返します。
+//
+// @Summary JWKSの取得
+// @Description RS256 / EdDSA で署名されたトークンを検証するための公開鍵（JWK Set）を返します。HS256の鍵は含まれません
+// @Tags 認証
+// @Produce json
+// @Success 200 {object} object{keys=[]object} "JWK Set"
+// @Router /api/auth/jwks [get]
+func (ah *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ah.authService.JWKS())
 }
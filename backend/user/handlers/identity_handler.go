@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"kojan-map/user/services"
+)
+
+// IdentityHandler ログインプロバイダーとアカウント連携のハンドラー
+type IdentityHandler struct {
+	authService     *services.AuthService
+	identityService *services.IdentityService
+}
+
+// NewIdentityHandler アカウント連携ハンドラーを初期化
+func NewIdentityHandler(authService *services.AuthService, identityService *services.IdentityService) *IdentityHandler {
+	return &IdentityHandler{authService: authService, identityService: identityService}
+}
+
+// providerResponse ログイン画面に表示するプロバイダー
+type providerResponse struct {
+	Name     string `json:"name"`
+	Issuer   string `json:"issuer"`
+	ClientID string `json:"clientId"`
+}
+
+// ListProviders はログインに使えるプロバイダーの一覧を取得します。
+//
+// @Summary ログインプロバイダー一覧
+// @Description 設定済みの OpenID Connect プロバイダー（Google・LINE など）と、IDトークンの取得に使うクライアントIDを返します
+// @Tags 認証
+// @Produce json
+// @Success 200 {object} object{providers=[]object} "プロバイダー一覧"
+// @Router /api/auth/providers [get]
+func (ih *IdentityHandler) ListProviders(c *gin.Context) {
+	providers := ih.authService.LoginProviders()
+	resp := make([]providerResponse, 0, len(providers))
+	for _, p := range providers {
+		resp = append(resp, providerResponse{Name: p.Name, Issuer: p.Issuer, ClientID: p.ClientID})
+	}
+	c.JSON(http.StatusOK, gin.H{"providers": resp})
+}
+
+// ListIdentities はログイン中のユーザーに連携している外部アカウントの一覧を取得します。
+//
+// @Summary 連携アカウント一覧
+// @Description ログインに使える外部アカウント（プロバイダー・メールアドレス・連携日時・最終ログイン日時）を返します
+// @Tags 認証
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{identities=[]object} "連携アカウント一覧"
+// @Failure 401 {object} object{error=string} "認証されていません"
+// @Failure 500 {object} object{error=string} "サーバーエラー"
+// @Router /api/auth/identities [get]
+func (ih *IdentityHandler) ListIdentities(c *gin.Context) {
+	googleID := c.GetString("googleId")
+	if googleID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	identities, err := ih.identityService.ListIdentities(googleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"identities": identities})
+}
+
+// LinkIdentity はログイン中のユーザーに外部アカウントを連携します。
+// 連携後は、どちらのアカウントでも同じユーザーとしてログインできます。
+//
+// @Summary アカウント連携
+// @Description 指定したプロバイダーのIDトークンを検証し、その外部アカウントをログイン中のユーザーに連携します
+// @Tags 認証
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object{provider=string,id_token=string} true "プロバイダーとIDトークン"
+// @Success 200 {object} object{identity=object} "連携したアカウント"
+// @Failure 400 {object} object{error=string} "不正なリクエスト・未対応のプロバイダー"
+// @Failure 401 {object} object{error=string} "認証されていない・無効なIDトークン"
+// @Failure 409 {object} object{error=string} "別のユーザーに連携済み・同じプロバイダーのアカウントを連携済み"
+// @Failure 500 {object} object{error=string} "サーバーエラー"
+// @Router /api/auth/identities [post]
+func (ih *IdentityHandler) LinkIdentity(c *gin.Context) {
+	googleID := c.GetString("googleId")
+	if googleID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req struct {
+		Provider string `json:"provider" binding:"required"`
+		IDToken  string `json:"id_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	identity, err := ih.authService.VerifyIDToken(req.Provider, req.IDToken)
+	if err != nil {
+		c.JSON(verifyStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	linked, err := ih.identityService.Link(googleID, *identity)
+	if err != nil {
+		c.JSON(loginStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"identity": linked})
+}
+
+// UnlinkIdentity は外部アカウントの連携を解除します（最後の1件は解除できません）。
+//
+// @Summary アカウント連携の解除
+// @Description 指定したプロバイダーのアカウントの連携を解除します。ログインできなくなるため、最後の1件は解除できません
+// @Tags 認証
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "プロバイダー名"
+// @Success 200 {object} object{provider=string} "連携を解除したプロバイダー"
+// @Failure 401 {object} object{error=string} "認証されていません"
+// @Failure 404 {object} object{error=string} "連携していないプロバイダー"
+// @Failure 409 {object} object{error=string} "最後のログイン方法"
+// @Failure 500 {object} object{error=string} "サーバーエラー"
+// @Router /api/auth/identities/{provider} [delete]
+func (ih *IdentityHandler) UnlinkIdentity(c *gin.Context) {
+	googleID := c.GetString("googleId")
+	if googleID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	provider := c.Param("provider")
+	if err := ih.identityService.Unlink(googleID, provider); err != nil {
+		switch {
+		case errors.Is(err, services.ErrIdentityNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrLastIdentity):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"provider": provider})
+}
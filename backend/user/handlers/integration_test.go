@@ -42,7 +42,7 @@ func cleanupTestDB(t *testing.T, db *gorm.DB) {
 	// テストデータを削除
 	db.Exec("DELETE FROM report WHERE reportId >= 10000")
 	db.Exec("DELETE FROM post WHERE postId >= 12345")
-	db.Exec("DELETE FROM session WHERE googleId LIKE 'test-%' OR googleId LIKE '%test%'")
+	db.Exec("DELETE FROM sessions WHERE googleId LIKE 'test-%' OR googleId LIKE '%test%'")
 	db.Exec("DELETE FROM user WHERE googleId LIKE 'test-%' OR googleId LIKE '%test%' OR gmail LIKE '%test%' OR googleId LIKE '%creator%' OR googleId LIKE '%viewer%' OR googleId LIKE '%reporter%' OR googleId LIKE '%reported%'")
 	db.Exec("DELETE FROM place WHERE placeId >= 9000")
 
@@ -63,7 +63,7 @@ func createTestUser(t *testing.T, db *gorm.DB, googleID, email string) string {
 // createTestSession テスト用セッションを作成
 func createTestSession(t *testing.T, db *gorm.DB, googleID string) string {
 	sessionID := "test-" + uuid.New().String()
-	query := "INSERT INTO session (sessionId, googleId, expiry) VALUES (?, ?, ?)"
+	query := "INSERT INTO sessions (sessionId, googleId, expiry) VALUES (?, ?, ?)"
 	err := db.Exec(query, sessionID, googleID, time.Now().Add(24*time.Hour)).Error
 	if err != nil {
 		t.Fatalf("テストセッションの作成に失敗しました: %v", err)
@@ -107,7 +107,7 @@ func TestIntegration_USER001_UserRegistrationFlow(t *testing.T) {
 	// DBからセッションを取得して確認
 	var dbSessionID, dbSessionGoogleID string
 	var dbExpiry time.Time
-	err = db.Raw("SELECT sessionId, googleId, expiry FROM session WHERE sessionId = ?", sessionID).Row().Scan(&dbSessionID, &dbSessionGoogleID, &dbExpiry)
+	err = db.Raw("SELECT sessionId, googleId, expiry FROM sessions WHERE sessionId = ?", sessionID).Row().Scan(&dbSessionID, &dbSessionGoogleID, &dbExpiry)
 	assert.NoError(t, err, "セッションがDBに保存されていません")
 	assert.Equal(t, googleID, dbSessionGoogleID, "GoogleIDが一致しません")
 	assert.True(t, dbExpiry.After(time.Now()), "セッションの有効期限が過去です")
@@ -222,18 +222,18 @@ func TestIntegration_USER005_SessionExtensionOnLogin(t *testing.T) {
 	// 旧セッション作成（1時間後に期限切れ）
 	oldSessionID := "test-old-" + uuid.New().String()
 	oldExpiry := time.Now().Add(1 * time.Hour)
-	err := db.Exec("INSERT INTO session (sessionId, googleId, expiry) VALUES (?, ?, ?)", oldSessionID, googleID, oldExpiry).Error
+	err := db.Exec("INSERT INTO sessions (sessionId, googleId, expiry) VALUES (?, ?, ?)", oldSessionID, googleID, oldExpiry).Error
 	assert.NoError(t, err, "旧セッションの作成に失敗しました")
 
 	// 新しいセッションを作成（延長をシミュレート）
 	newSessionID := "test-new-" + uuid.New().String()
 	newExpiry := time.Now().Add(24 * time.Hour)
-	err = db.Exec("INSERT INTO session (sessionId, googleId, expiry) VALUES (?, ?, ?)", newSessionID, googleID, newExpiry).Error
+	err = db.Exec("INSERT INTO sessions (sessionId, googleId, expiry) VALUES (?, ?, ?)", newSessionID, googleID, newExpiry).Error
 	assert.NoError(t, err, "新しいセッションの作成に失敗しました")
 
 	// DBからセッションを取得
 	var dbExpiry time.Time
-	err = db.Raw("SELECT expiry FROM session WHERE sessionId = ?", newSessionID).Row().Scan(&dbExpiry)
+	err = db.Raw("SELECT expiry FROM sessions WHERE sessionId = ?", newSessionID).Row().Scan(&dbExpiry)
 	assert.NoError(t, err, "セッションの取得に失敗しました")
 
 	// セッションの有効期限が延長されているか確認
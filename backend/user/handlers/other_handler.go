@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"kojan-map/business/pkg/reportcategory"
 	"kojan-map/user/services"
 )
 
@@ -98,16 +102,26 @@ func NewReportHandler(reportService *services.ReportService) *ReportHandler {
 
 // CreateReport 通報を作成
 // POST /api/report
+// category（spam, personal_info, harassment, dangerous, wrong_location, other）と任意の comment を指定する
+// 以前の形式の reason のみの通報は、category other・comment reason として扱う
 func (rh *ReportHandler) CreateReport(c *gin.Context) {
 	var req struct {
-		PostID int    `json:"postId" binding:"required"`
-		Reason string `json:"reason" binding:"required"`
+		PostID   int    `json:"postId" binding:"required"`
+		Category string `json:"category"`
+		Comment  string `json:"comment"`
+		Reason   string `json:"reason"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.Category == "" {
+		req.Category = string(reportcategory.Other)
+	}
+	if req.Comment == "" {
+		req.Comment = req.Reason
+	}
 
 	reporterID := c.GetString("googleId")
 	if reporterID == "" {
@@ -115,14 +129,41 @@ func (rh *ReportHandler) CreateReport(c *gin.Context) {
 		return
 	}
 
-	if err := rh.reportService.CreateReport(reporterID, int32(req.PostID), req.Reason); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := rh.reportService.CreateReport(reporterID, int32(req.PostID), req.Category, req.Comment); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidReportCategory),
+			errors.Is(err, services.ErrReportCommentRequired),
+			errors.Is(err, services.ErrReportCommentTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrDuplicateReport):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"message": "report created"})
 }
 
+// GetMyReports 自分の通報と対応結果（resolution・resolutionNote・resolvedAt）を取得
+// GET /api/report/history
+func (rh *ReportHandler) GetMyReports(c *gin.Context) {
+	reporterID := c.GetString("googleId")
+	if reporterID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	reports, err := rh.reportService.GetMyReports(reporterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
 // ContactHandler 問い合わせ関連のハンドラー
 type ContactHandler struct {
 	contactService *services.ContactService
@@ -153,6 +194,9 @@ func (ch *ContactHandler) CreateContact(c *gin.Context) {
 	}
 
 	if err := ch.contactService.CreateContact(userID, req.Subject, req.Text); err != nil {
+		if respondContentRejected(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -160,6 +204,67 @@ func (ch *ContactHandler) CreateContact(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"message": "contact created"})
 }
 
+// GetMyContacts 自分の問い合わせと管理者からの返信・追加の問い合わせを取得
+// GET /api/contact
+func (ch *ContactHandler) GetMyContacts(c *gin.Context) {
+	userID := c.GetString("googleId")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	contacts, err := ch.contactService.GetMyContacts(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"contacts": contacts})
+}
+
+// AddFollowUp 自分の問い合わせに追加で問い合わせる
+// POST /api/contact/:id/messages
+func (ch *ContactHandler) AddFollowUp(c *gin.Context) {
+	askID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contact ID"})
+		return
+	}
+
+	var req struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetString("googleId")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	message, err := ch.contactService.AddFollowUp(userID, int32(askID), req.Text)
+	if err != nil {
+		if respondContentRejected(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrContactMessageRequired),
+			errors.Is(err, services.ErrContactMessageTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrContactNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
 // BusinessApplicationHandler 事業者申請関連のハンドラー
 type BusinessApplicationHandler struct {
 	businessApplicationService *services.BusinessApplicationService
@@ -174,10 +279,10 @@ func NewBusinessApplicationHandler(businessApplicationService *services.Business
 // POST /api/business/application
 func (bah *BusinessApplicationHandler) CreateBusinessApplication(c *gin.Context) {
 	var req struct {
-		UserID  string `json:"userId" binding:"required"`
-		Name    string `json:"name" binding:"required"`
-		Address string `json:"address" binding:"required"`
-		Phone   string `json:"phone" binding:"required"`
+		Name            string `json:"name" binding:"required"`
+		Address         string `json:"address" binding:"required"`
+		Phone           string `json:"phone" binding:"required"`
+		CorporateNumber string `json:"corporateNumber"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -185,15 +290,155 @@ func (bah *BusinessApplicationHandler) CreateBusinessApplication(c *gin.Context)
 		return
 	}
 
-	if err := bah.businessApplicationService.CreateBusinessApplication(
-		req.UserID,
-		req.Name,
-		req.Address,
-		req.Phone,
-	); err != nil {
+	userID := c.GetString("googleId")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	app, err := bah.businessApplicationService.CreateBusinessApplication(userID, services.BusinessApplicationInput{
+		Name:            req.Name,
+		Address:         req.Address,
+		Phone:           req.Phone,
+		CorporateNumber: req.CorporateNumber,
+	})
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, app)
+}
+
+// GetMyApplications 自分の事業者申請と添付書類・状態の履歴を取得
+// GET /api/business/application
+func (bah *BusinessApplicationHandler) GetMyApplications(c *gin.Context) {
+	userID := c.GetString("googleId")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	apps, err := bah.businessApplicationService.GetMyApplications(userID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "business application created"})
+	c.JSON(http.StatusOK, gin.H{"applications": apps})
+}
+
+// ResubmitApplication 追加情報を依頼された事業者申請を修正して再提出
+// PUT /api/business/application/:id
+func (bah *BusinessApplicationHandler) ResubmitApplication(c *gin.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid application ID"})
+		return
+	}
+
+	var req struct {
+		Name            string `json:"name" binding:"required"`
+		Address         string `json:"address" binding:"required"`
+		Phone           string `json:"phone" binding:"required"`
+		CorporateNumber string `json:"corporateNumber"`
+		Note            string `json:"note"` // 運営へのコメント（任意）
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetString("googleId")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	app, err := bah.businessApplicationService.ResubmitApplication(userID, int32(requestID), services.BusinessApplicationInput{
+		Name:            req.Name,
+		Address:         req.Address,
+		Phone:           req.Phone,
+		CorporateNumber: req.CorporateNumber,
+	}, req.Note)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
+
+// AttachApplicationDocument 事業者申請に確認書類（営業許可証など）を添付
+// POST /api/business/application/:id/documents (multipart: file, kind)
+func (bah *BusinessApplicationHandler) AttachApplicationDocument(c *gin.Context) {
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid application ID"})
+		return
+	}
+
+	userID := c.GetString("googleId")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	// ファイルサイズ制限（5MB）
+	if file.Size > 5*1024*1024 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrApplicationDocumentTooLarge.Error()})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer func() {
+		_ = f.Close() // nolint:errcheck
+	}()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+
+	document, err := bah.businessApplicationService.AttachDocument(userID, int32(requestID), c.PostForm("kind"), file.Filename, data)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// respondApplicationError 事業者申請のエラーをステータスコードに変換して返す
+func respondApplicationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrApplicationFieldsRequired),
+		errors.Is(err, services.ErrApplicationFieldTooLong),
+		errors.Is(err, services.ErrInvalidApplicationPhone),
+		errors.Is(err, services.ErrInvalidCorporateNumber),
+		errors.Is(err, services.ErrApplicationNoteTooLong),
+		errors.Is(err, services.ErrInvalidApplicationDocument),
+		errors.Is(err, services.ErrApplicationDocumentType),
+		errors.Is(err, services.ErrApplicationDocumentTooLarge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrApplicationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyBusiness),
+		errors.Is(err, services.ErrApplicationInProgress),
+		errors.Is(err, services.ErrDuplicateApplicationPhone),
+		errors.Is(err, services.ErrApplicationNotEditable),
+		errors.Is(err, services.ErrTooManyApplicationDocuments):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/base64"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"kojan-map/shared/contentfilter"
 	"kojan-map/user/models"
 	"kojan-map/user/services"
 )
@@ -73,9 +75,14 @@ func (ph *PostHandler) GetPostDetail(c *gin.Context) {
 		return
 	}
 
-	post, err := ph.postService.GetPostDetail(int32(postID))
+	// 投稿者本人には下書き・予約投稿も返す
+	post, err := ph.postService.GetPostDetail(int32(postID), c.GetString("googleId"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -91,7 +98,7 @@ func (ph *PostHandler) GetPostDetail(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body object{latitude=number,longitude=number,title=string,description=string,genre=string,images=[]string} true "投稿情報"
+// @Param request body object{latitude=number,longitude=number,title=string,description=string,genre=string,images=[]string,status=string,publishAt=string} true "投稿情報（status: draft/scheduled/published、scheduled の場合は publishAt 必須）"
 // @Success 201 {object} object{postId=int,message=string} "投稿作成成功"
 // @Failure 400 {object} object{error=string} "不正なリクエスト"
 // @Failure 401 {object} object{error=string} "認証されていません"
@@ -99,13 +106,15 @@ func (ph *PostHandler) GetPostDetail(c *gin.Context) {
 // @Router /api/posts [post]
 func (ph *PostHandler) CreatePost(c *gin.Context) {
 	var req struct {
-		Latitude    float64  `json:"latitude" binding:"required"`
-		Longitude   float64  `json:"longitude" binding:"required"`
-		Title       string   `json:"title" binding:"required"`
-		Description string   `json:"description" binding:"required"`
-		Genre       string   `json:"genre" binding:"required"`
-		Images      []string `json:"images"`
-		PlaceID     int      `json:"placeId"` // Optional
+		Latitude    float64    `json:"latitude" binding:"required"`
+		Longitude   float64    `json:"longitude" binding:"required"`
+		Title       string     `json:"title" binding:"required"`
+		Description string     `json:"description" binding:"required"`
+		Genre       string     `json:"genre" binding:"required"`
+		Images      []string   `json:"images"`
+		PlaceID     int        `json:"placeId"`   // Optional
+		Status      string     `json:"status"`    // Optional: draft / scheduled / published（既定: published）
+		PublishAt   *time.Time `json:"publishAt"` // status=scheduled の場合に必須（RFC3339）
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -185,9 +194,18 @@ func (ph *PostHandler) CreatePost(c *gin.Context) {
 		PostDate:    time.Now(),
 		NumReaction: 0, // 初期値
 		NumView:     0, // 初期値
+		Status:      req.Status,
+		PublishAt:   req.PublishAt,
 	}
 
 	if err := ph.postService.CreatePost(&post); err != nil {
+		if respondContentRejected(c, err) {
+			return
+		}
+		if isPublishStatusError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create post", "details": err.Error()})
 		return
 	}
@@ -199,17 +217,81 @@ func (ph *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{
-		"postId":    post.ID,
+		"postId":    post.PostID,
 		"placeId":   post.PlaceID,
 		"latitude":  place.Latitude,
 		"longitude": place.Longitude,
+		"status":    post.Status,
+		"publishAt": post.PublishAt,
 		"message":   "post created successfully",
 	})
 }
 
+// respondContentRejected コンテンツフィルタで拒否された場合に400を返す
+func respondContentRejected(c *gin.Context, err error) bool {
+	var rejected *contentfilter.RejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "content rejected", "reason": rejected.Reason})
+	return true
+}
+
+// isPublishStatusError 公開状態の入力エラーか判定
+func isPublishStatusError(err error) bool {
+	return errors.Is(err, services.ErrInvalidPostStatus) ||
+		errors.Is(err, services.ErrPublishAtRequired) ||
+		errors.Is(err, services.ErrPublishAtNotFuture)
+}
+
+// UpdatePostStatus 下書き・予約投稿を公開または予約に変更
+// PUT /api/posts/status
+func (ph *PostHandler) UpdatePostStatus(c *gin.Context) {
+	var req struct {
+		PostID    int32      `json:"postId" binding:"required"`
+		Status    string     `json:"status" binding:"required"`
+		PublishAt *time.Time `json:"publishAt"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request format", "details": err.Error()})
+		return
+	}
+
+	userID := c.GetString("googleId")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	post, err := ph.postService.UpdatePostStatus(req.PostID, userID, req.Status, req.PublishAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPostNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPostNotOwned):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPostAlreadyPublished):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case isPublishStatusError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"postId":    post.PostID,
+		"status":    post.Status,
+		"publishAt": post.PublishAt,
+		"message":   "post status updated",
+	})
+}
+
 // AnonymizePost 投稿を匿名化
 
 // GetPostHistory ユーザーの投稿履歴を取得
+// 下書き・予約投稿や審査状態（moderationStatus）も含めて投稿者本人に返す
 // GET /api/posts/history
 func (ph *PostHandler) GetPostHistory(c *gin.Context) {
 	userID := c.GetString("googleId")
@@ -295,6 +377,10 @@ func (ph *PostHandler) AddReaction(c *gin.Context) {
 	}
 
 	if err := ph.postService.AddReaction(userID, int32(req.PostID)); err != nil {
+		if errors.Is(err, services.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
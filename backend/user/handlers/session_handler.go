@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"kojan-map/user/models"
+	"kojan-map/user/services"
+)
+
+// SessionHandler ログイン中の端末・ログイン履歴のハンドラー
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+// NewSessionHandler セッションハンドラーを初期化
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// sessionResponse 端末一覧の要素（現在の端末かどうかを付加）
+type sessionResponse struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+// ListSessions はログイン中の端末（セッション）の一覧を取得します。
+//
+// @Summary ログイン中の端末一覧
+// @Description 有効なセッションを、User-Agent・IPアドレス・初回ログイン・最終アクセス日時とともに返します
+// @Tags 認証
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{sessions=[]object} "セッション一覧"
+// @Failure 401 {object} object{error=string} "認証されていません"
+// @Failure 500 {object} object{error=string} "サーバーエラー"
+// @Router /api/auth/sessions [get]
+func (sh *SessionHandler) ListSessions(c *gin.Context) {
+	googleID := c.GetString("googleId")
+	if googleID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	sessions, err := sh.sessionService.ListSessions(googleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := c.GetString("sessionId")
+	resp := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, sessionResponse{Session: s, Current: s.SessionID == current})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": resp})
+}
+
+// RevokeSession は指定した端末のセッションを失効させます（その端末はログアウトされます）。
+//
+// @Summary 端末のログアウト
+// @Description 指定したセッションと、そのセッションのリフレッシュトークンを失効させます
+// @Tags 認証
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "セッションID"
+// @Success 200 {object} object{sessionId=string} "失効したセッションID"
+// @Failure 401 {object} object{error=string} "認証されていません"
+// @Failure 404 {object} object{error=string} "セッションが見つかりません"
+// @Failure 500 {object} object{error=string} "サーバーエラー"
+// @Router /api/auth/sessions/{id} [delete]
+func (sh *SessionHandler) RevokeSession(c *gin.Context) {
+	googleID := c.GetString("googleId")
+	if googleID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if err := sh.sessionService.RevokeSession(googleID, sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessionId": sessionID})
+}
+
+// ListSignIns はログイン履歴を取得します。
+//
+// @Summary ログイン履歴
+// @Description ログイン履歴を新しい順に返します。初めて使う端末からのログインは newDevice が true になります
+// @Tags 認証
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "取得件数（既定20、最大100）"
+// @Success 200 {object} object{signIns=[]models.SignInHistory} "ログイン履歴"
+// @Failure 401 {object} object{error=string} "認証されていません"
+// @Failure 500 {object} object{error=string} "サーバーエラー"
+// @Router /api/auth/sign-ins [get]
+func (sh *SessionHandler) ListSignIns(c *gin.Context) {
+	googleID := c.GetString("googleId")
+	if googleID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	history, err := sh.sessionService.ListSignIns(googleID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signIns": history})
+}
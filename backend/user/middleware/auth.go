@@ -1,87 +0,0 @@
-package middleware
-
-import (
-	"fmt"
-	"log"
-	"net/http"
-	"strings"
-	"time"
-
-	"kojan-map/user/models"
-
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-)
-
-var jwtSecret []byte
-
-// SetJWTSecret sets the JWT secret for middleware to use
-func SetJWTSecret(secret string) {
-	if secret == "" {
-		log.Fatal("JWT_SECRET_KEY cannot be empty")
-	}
-	jwtSecret = []byte(secret)
-}
-
-// AuthMiddleware JWT認証ミドルウェア
-func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
-		}
-
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header"})
-			c.Abort()
-			return
-		}
-
-		tokenString := parts[1]
-		if jwtSecret == nil {
-			log.Println("JWT secret is not initialized")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			c.Abort()
-			return
-		}
-
-		token, err := jwt.ParseWithClaims(tokenString, &models.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			// [must] アルゴリズム検証: HMACのみを許可
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return jwtSecret, nil
-		})
-
-		if err != nil {
-			log.Printf("Token parsing failed: %v", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
-		}
-
-		claims, ok := token.Claims.(*models.JWTClaims)
-		if !ok || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
-			return
-		}
-
-		// セッション無効化チェック（revoked_at は使用しない仕様のためスキップ）
-
-		// セッション有効期限チェック
-		if claims.ExpiresAt == nil || time.Now().After(claims.ExpiresAt.Time) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
-			c.Abort()
-			return
-		}
-
-		c.Set("userID", claims.UserID)
-		c.Set("googleId", claims.GoogleID)
-		c.Set("user", claims)
-		c.Next()
-	}
-}
@@ -0,0 +1,9 @@
+package models
+
+// ExternalIdentity 検証済みのIDトークンから得た外部アカウントの情報
+type ExternalIdentity struct {
+	Provider string
+	Issuer   string
+	Subject  string
+	Email    string // 確認済みのメールアドレス（提供されない場合は空）
+}
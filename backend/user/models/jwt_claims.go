@@ -1,12 +1,7 @@
 package models
 
-import "github.com/golang-jwt/jwt/v5"
+import "kojan-map/business/pkg/jwt"
 
 // JWTClaims - Custom JWT claims shared across auth middleware and services.
-type JWTClaims struct {
-	UserID   string `json:"user_id"`
-	GoogleID string `json:"google_id"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
-	jwt.RegisteredClaims
-}
+// ユーザー・管理者・ビジネスで共通のクレーム形式です
+type JWTClaims = jwt.Claims
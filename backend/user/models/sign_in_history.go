@@ -0,0 +1,7 @@
+package models
+
+// DeviceInfo ログイン元の端末情報
+type DeviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
@@ -0,0 +1,33 @@
+package models
+
+import shared "kojan-map/business/pkg/models"
+
+// テーブルのモデルは kojan-map/business/pkg/models にあり、ユーザー側ではこれまでの名前で参照する
+// post・report は削除済みの行をクエリから自動で除外する（gorm.DeletedAt）
+// 列の変更は migrations の SQL で行い、必要に応じて business/pkg/models のモデルを更新する
+type (
+	// User 一般会員モデル
+	User = shared.User
+	// Session セッション情報モデル
+	Session = shared.Session
+	// RefreshToken リフレッシュトークン
+	RefreshToken = shared.RefreshToken
+	// SignInHistory ログイン履歴
+	SignInHistory = shared.SignInHistory
+	// UserIdentity ログインに使う外部アカウントとユーザーの連携
+	UserIdentity = shared.UserIdentity
+	// Genre ジャンルモデル
+	Genre = shared.Genre
+	// Place 場所モデル
+	Place = shared.Place
+	// Post 投稿モデル
+	Post = shared.Post
+	// Report 通報情報モデル
+	Report = shared.Report
+	// UserReaction ユーザーのリアクション記録
+	UserReaction = shared.UserReaction
+	// UserBlock ユーザーブロック情報
+	UserBlock = shared.UserBlock
+	// Business 事業者モデル (承認済み)
+	Business = shared.BusinessMember
+)
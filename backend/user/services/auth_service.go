@@ -2,240 +2,205 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 
-	shared "kojan-map/shared/models"
+	"kojan-map/business/pkg/accountstatus"
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/oauth"
 	"kojan-map/user/models"
 )
 
 type AuthService struct {
 	db             *gorm.DB
 	googleClientID string
-	jwtSecret      []byte
+	tokens         *jwt.TokenManager
+	verifier       oauth.TokenVerifier
+	providers      *oauth.Registry // ログインに使う OIDC プロバイダー（nilの場合は Google のみ）
 	appEnv         string
+	accessTokenTTL time.Duration
+	accounts       *accountstatus.Checker // 利用停止・利用禁止・削除の確認（nilの場合は確認しない）
 }
 
-func NewAuthService(db *gorm.DB, googleClientID string, jwtSecretKey string, appEnv string) *AuthService {
-	if jwtSecretKey == "" {
-		log.Fatal("JWT_SECRET_KEY is not set")
+// defaultAccessTokenTTL はユーザー側で発行するアクセストークンの既定の有効期間です
+// 期限切れ後はリフレッシュトークンで再発行する
+const defaultAccessTokenTTL = 15 * time.Minute
+
+func NewAuthService(db *gorm.DB, googleClientID string, tokens *jwt.TokenManager, appEnv string) *AuthService {
+	if tokens == nil {
+		log.Fatal("token manager is not set")
 	}
 	return &AuthService{
 		db:             db,
 		googleClientID: googleClientID,
-		jwtSecret:      []byte(jwtSecretKey),
+		tokens:         tokens,
+		verifier:       oauth.NewGoogleTokenVerifier(googleClientID),
 		appEnv:         appEnv,
+		accessTokenTTL: defaultAccessTokenTTL,
 	}
 }
 
-// Google OAuth Token response
-type GoogleTokenResponse struct {
-	Iss           string `json:"iss"`
-	Azp           string `json:"azp"`
-	Aud           string `json:"aud"`
-	Sub           string `json:"sub"`
-	Email         string `json:"email"`
-	EmailVerified string `json:"email_verified"`
-	Picture       string `json:"picture"`
-	Name          string `json:"name"`
-	Iat           string `json:"iat"`
-	Exp           string `json:"exp"`
+// SetAccessTokenTTL アクセストークンの有効期間を設定（0以下は無視）
+func (as *AuthService) SetAccessTokenTTL(ttl time.Duration) {
+	if ttl > 0 {
+		as.accessTokenTTL = ttl
+	}
 }
 
-// Request body for token exchange
-type TokenExchangeRequest struct {
-	GoogleToken string `json:"google_token"`
-	Role        string `json:"role"` // 'user' or 'business'
+// SetTokenVerifier GoogleのIDトークンの検証器を設定（発行者・JWKSの取得先を変更する場合や、ビジネス側と共有する場合）
+func (as *AuthService) SetTokenVerifier(verifier oauth.TokenVerifier) {
+	if verifier != nil {
+		as.verifier = verifier
+	}
 }
 
-// Response for successful authentication
-type AuthResponse struct {
-	JWTToken string       `json:"jwt_token"`
-	User     *models.User `json:"user"`
+// SetLoginProviders ログインに使う OIDC プロバイダー（Google・LINE など）を設定
+func (as *AuthService) SetLoginProviders(providers *oauth.Registry) {
+	as.providers = providers
 }
 
-// VerifyGoogleToken - Verify Google ID token via tokeninfo endpoint
-func (as *AuthService) VerifyGoogleToken(idToken string) (*GoogleTokenResponse, error) {
-	// ★追加: テスト用トークンの特例対応（dev/test環境のみ許可）
-	if (as.appEnv == "dev" || as.appEnv == "test") && len(idToken) > 6 && idToken[len(idToken)-6:] == "-token" {
-		dummyID := idToken[:len(idToken)-6]
-		return &GoogleTokenResponse{
-			Sub:           dummyID,
-			Email:         dummyID + "@example.com",
-			EmailVerified: "true",
-			Name:          "Test User " + dummyID,
-			Picture:       "",
-		}, nil
-	}
-	if idToken == "" {
-		return nil, errors.New("empty id token")
-	}
-
-	if as.googleClientID == "" {
-		return nil, errors.New("google client id is not configured")
-	}
-
-	// Try checking as ID Token
-	url := fmt.Sprintf("https://oauth2.googleapis.com/tokeninfo?id_token=%s", idToken)
-	client := &http.Client{Timeout: 5 * time.Second}
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
-	if err != nil {
-		log.Printf("[VerifyGoogleToken] failed to create request: %v", err)
-		return nil, errors.New("failed to create request")
-	}
+// SetAccountChecker 利用停止・利用禁止・削除されたアカウントのログインとトークン更新を拒否する
+func (as *AuthService) SetAccountChecker(accounts *accountstatus.Checker) {
+	as.accounts = accounts
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("[VerifyGoogleToken] failed to contact Google tokeninfo: %v", err)
-		return nil, errors.New("failed to contact Google tokeninfo")
+// CheckAccount - Return *accountstatus.Restriction when the account is suspended, banned or deleted
+func (as *AuthService) CheckAccount(userID string) error {
+	if as.accounts == nil {
+		return nil
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	// If ID token check failed (likely 400), try checking as Access Token
-	if resp.StatusCode != http.StatusOK {
-		// New Check: Try access_token endpoint
-		urlAccess := fmt.Sprintf("https://oauth2.googleapis.com/tokeninfo?access_token=%s", idToken)
-		reqAccess, err := http.NewRequestWithContext(context.Background(), http.MethodGet, urlAccess, nil)
-		if err != nil {
-			log.Printf("[VerifyGoogleToken] failed to create access_token request: %v", err)
-			return nil, errors.New("failed to create access_token request")
-		}
-		respAccess, errAccess := client.Do(reqAccess)
-		if errAccess != nil {
-			log.Printf("[VerifyGoogleToken] failed to contact Google tokeninfo (access_token): %v", errAccess)
-			return nil, errors.New("failed to contact Google tokeninfo (access_token)")
-		}
-		defer func() { _ = respAccess.Body.Close() }()
+	return as.accounts.CheckAccount(context.Background(), userID)
+}
 
-		if respAccess.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(respAccess.Body)
-			log.Printf("[VerifyGoogleToken] google token verification failed (both id_token and access_token): %s", string(body))
-			return nil, fmt.Errorf("google token verification failed (both id_token and access_token): %s", string(body))
-		}
-		// Use the access token response
-		resp = respAccess
+// LoginProviders 設定済みのログインプロバイダー
+func (as *AuthService) LoginProviders() []*oauth.Provider {
+	if as.providers == nil {
+		return []*oauth.Provider{as.googleProvider()}
 	}
+	return as.providers.List()
+}
 
-	var googleResp GoogleTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&googleResp); err != nil {
-		log.Printf("[VerifyGoogleToken] failed to parse Google response: %v", err)
-		return nil, errors.New("failed to parse Google response")
+// googleProvider SetLoginProviders を使わない場合の Google プロバイダー
+func (as *AuthService) googleProvider() *oauth.Provider {
+	return &oauth.Provider{
+		Name:     oauth.ProviderGoogle,
+		Issuer:   oauth.GoogleIssuer,
+		ClientID: as.googleClientID,
+		Verifier: as.verifier,
 	}
-
-	return &googleResp, nil
 }
 
-// ExchangeTokenForUser - Exchange Google token for User and JWT
-func (as *AuthService) ExchangeTokenForUser(googleToken, role string) (*AuthResponse, error) {
-	// Validate role
-	switch role {
-	case "user", "business":
-		// ok
-	default:
-		return nil, errors.New("invalid role")
+// VerifyIDToken - Verify an ID token of the given provider (empty means google) and return the external identity
+func (as *AuthService) VerifyIDToken(providerName, idToken string) (*models.ExternalIdentity, error) {
+	if idToken == "" {
+		return nil, errors.New("empty id token")
 	}
-
-	// Verify Google ID token via tokeninfo endpoint
-	googleResp, err := as.VerifyGoogleToken(googleToken)
-	if err != nil {
-		return nil, fmt.Errorf("google token verification failed: %w", err)
+	if providerName == "" {
+		providerName = oauth.ProviderGoogle
 	}
 
-	// Find or create user
-	user, err := as.findOrCreateUser(googleResp, role)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find or create user: %w", err)
+	var provider *oauth.Provider
+	if as.providers != nil {
+		p, err := as.providers.Get(providerName)
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	} else if providerName == oauth.ProviderGoogle {
+		provider = as.googleProvider()
+	} else {
+		return nil, oauth.ErrUnknownProvider
+	}
+	if provider.ClientID == "" {
+		return nil, fmt.Errorf("%s client id is not configured", provider.Name)
 	}
 
-	// Generate JWT token
-	jwtToken, err := as.GenerateJWT(user)
+	claims, err := provider.Verifier.VerifyToken(context.Background(), idToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+		log.Printf("[VerifyIDToken] %s token verification failed: %v", provider.Name, err)
+		return nil, fmt.Errorf("%s token verification failed: %w", provider.Name, err)
 	}
 
-	return &AuthResponse{
-		JWTToken: jwtToken,
-		User:     user,
+	return &models.ExternalIdentity{
+		Provider: provider.Name,
+		Issuer:   provider.Issuer,
+		Subject:  claims.Sub,
+		Email:    claims.Email,
 	}, nil
 }
 
-// findOrCreateUser - Find existing user or create new one
-func (as *AuthService) findOrCreateUser(googleResp *GoogleTokenResponse, role string) (*models.User, error) {
-	var user models.User
-
-	// Check if user with this Google ID exists
-	result := as.db.Where("googleId = ?", googleResp.Sub).First(&user)
-
-	if result.Error == nil {
-		return &user, nil
-	}
+// Google OAuth Token response
+type GoogleTokenResponse struct {
+	Iss           string `json:"iss"`
+	Azp           string `json:"azp"`
+	Aud           string `json:"aud"`
+	Sub           string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified string `json:"email_verified"`
+	Picture       string `json:"picture"`
+	Name          string `json:"name"`
+	Iat           string `json:"iat"`
+	Exp           string `json:"exp"`
+}
 
-	if result.Error != gorm.ErrRecordNotFound {
-		return nil, result.Error
+// VerifyGoogleToken - Verify Google ID token offline (signature via cached JWKS, iss, aud, exp, email_verified)
+func (as *AuthService) VerifyGoogleToken(idToken string) (*GoogleTokenResponse, error) {
+	if idToken == "" {
+		return nil, errors.New("empty id token")
 	}
 
-	// Create new user
-	newUser := models.User{
-		GoogleID:         googleResp.Sub,
-		Gmail:            googleResp.Email,
-		Role:             shared.Role(role),
-		RegistrationDate: time.Now(),
+	if as.googleClientID == "" {
+		return nil, errors.New("google client id is not configured")
 	}
 
-	if err := as.db.Create(&newUser).Error; err != nil {
-		return nil, err
+	claims, err := as.verifier.VerifyToken(context.Background(), idToken)
+	if err != nil {
+		log.Printf("[VerifyGoogleToken] google token verification failed: %v", err)
+		return nil, fmt.Errorf("google token verification failed: %w", err)
 	}
 
-	return &newUser, nil
+	return &GoogleTokenResponse{
+		Iss:           claims.Issuer,
+		Aud:           claims.AUD,
+		Sub:           claims.Sub,
+		Email:         claims.Email,
+		EmailVerified: strconv.FormatBool(claims.EmailVerified),
+		Picture:       claims.Picture,
+		Name:          claims.Name,
+		Iat:           strconv.FormatInt(claims.IssuedAt, 10),
+		Exp:           strconv.FormatInt(claims.Expiration, 10),
+	}, nil
 }
 
 // GenerateJWT - Generate JWT token for user
 func (as *AuthService) GenerateJWT(user *models.User) (string, error) {
-	claims := models.JWTClaims{
-		UserID:   user.GoogleID,
-		GoogleID: user.GoogleID,
-		Email:    user.Gmail,
-		Role:     string(user.Role),
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // 24時間有効
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
+	return as.tokens.GenerateTokenWithTTL(user.GoogleID, user.Gmail, string(user.Role), as.accessTokenTTL)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(as.jwtSecret)
+// GenerateSessionJWT - Generate JWT token bound to a login session (rejected once the session is revoked)
+func (as *AuthService) GenerateSessionJWT(user *models.User, sessionID string) (string, error) {
+	return as.tokens.GenerateSessionToken(user.GoogleID, user.Gmail, string(user.Role), sessionID, as.accessTokenTTL)
 }
 
 // VerifyJWT - Verify and parse JWT token
 func (as *AuthService) VerifyJWT(tokenString string) (*models.JWTClaims, error) {
-	claims := &models.JWTClaims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		// HS256アルゴリズムのみ許可（Algorithm Confusion Attack対策）
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return as.jwtSecret, nil
-	})
-
+	claims, err := as.tokens.VerifyTokenWithType(tokenString, "access")
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
-
-	if !token.Valid {
-		return nil, errors.New("token is not valid")
-	}
-
 	return claims, nil
 }
 
+// JWKS - Public keys for verifying tokens issued by this server
+func (as *AuthService) JWKS() jwt.JWKS {
+	return as.tokens.Keys().JWKS()
+}
+
 // GetUserByID - Get user by ID
 func (as *AuthService) GetUserByID(userID string) (*models.User, error) {
 	var user models.User
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kojan-map/business/pkg/jwt"
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/business/pkg/oauth/oidctest"
+)
+
+func TestAuthService_VerifyGoogleToken(t *testing.T) {
+	issuer, err := oidctest.NewIssuer()
+	require.NoError(t, err)
+	defer issuer.Close()
+
+	tokens := jwt.NewTokenManagerWithSecret("test-secret")
+	defer tokens.Stop()
+	service := NewAuthService(nil, "client-id", tokens, "test")
+	service.SetTokenVerifier(oauth.NewIDTokenVerifier(oauth.ConfigWithOverrides("client-id", issuer.URL(), issuer.JWKSURL())))
+
+	idToken, err := issuer.MintIDToken("client-id", oidctest.Identity{Subject: "google123", Email: "user@example.com", EmailVerified: true, Name: "Test User"})
+	require.NoError(t, err)
+
+	resp, err := service.VerifyGoogleToken(idToken)
+	require.NoError(t, err)
+	assert.Equal(t, "google123", resp.Sub)
+	assert.Equal(t, "user@example.com", resp.Email)
+	assert.Equal(t, "true", resp.EmailVerified)
+	assert.Equal(t, "client-id", resp.Aud)
+
+	// 別のクライアント向けのトークンは受け付けない
+	otherAud, err := issuer.MintIDToken("other-client", oidctest.Identity{Subject: "google123", Email: "user@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	_, err = service.VerifyGoogleToken(otherAud)
+	assert.Error(t, err)
+
+	// メールアドレスが確認されていないアカウントは受け付けない
+	unverified, err := issuer.MintIDToken("client-id", oidctest.Identity{Subject: "google123", Email: "user@example.com"})
+	require.NoError(t, err)
+	_, err = service.VerifyGoogleToken(unverified)
+	assert.Error(t, err)
+
+	// 以前のテスト用トークン（"-token" で終わる文字列）は受け付けない
+	_, err = service.VerifyGoogleToken("google123-token")
+	assert.Error(t, err)
+}
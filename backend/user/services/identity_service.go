@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	shared "kojan-map/business/pkg/models"
+	"kojan-map/user/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 外部アカウント連携のエラー
+var (
+	ErrIdentityEmailInUse        = errors.New("email is already registered with another sign-in method")
+	ErrIdentityLinkedToOtherUser = errors.New("this account is already linked to another user")
+	ErrProviderAlreadyLinked     = errors.New("another account of this provider is already linked")
+	ErrIdentityNotFound          = errors.New("linked account not found")
+	ErrLastIdentity              = errors.New("cannot unlink the only sign-in method")
+)
+
+// IdentityService ログインに使う外部アカウント（Google・LINE など）とユーザーの連携
+type IdentityService struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+func NewIdentityService(db *gorm.DB) *IdentityService {
+	return &IdentityService{db: db, now: time.Now}
+}
+
+// ResolveUser 外部アカウントに連携したユーザーを取得し、未登録の場合は新しいユーザーを作成して連携する
+// 同じメールアドレスのユーザーが別のログイン方法で登録済みの場合は自動で連携せず ErrIdentityEmailInUse を返す
+// （ログイン後にアカウント連携する）
+func (s *IdentityService) ResolveUser(identity models.ExternalIdentity, role string) (*models.User, error) {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return nil, errors.New("issuer and subject are required")
+	}
+	if role == "" {
+		role = string(shared.RoleUser)
+	}
+	r := shared.Role(role)
+	if r != shared.RoleUser && r != shared.RoleBusiness {
+		return nil, errors.New("invalid role: must be user or business")
+	}
+
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var linked models.UserIdentity
+		err := tx.Where("issuer = ? AND subject = ?", identity.Issuer, identity.Subject).First(&linked).Error
+		switch {
+		case err == nil:
+			if err := tx.Where("googleId = ?", linked.GoogleID).First(&user).Error; err != nil {
+				return fmt.Errorf("failed to get linked user: %w", err)
+			}
+			return s.touch(tx, &linked, identity.Email)
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to check identity: %w", err)
+		}
+
+		if identity.Email != "" {
+			var count int64
+			if err := tx.Model(&models.User{}).Where("gmail = ?", identity.Email).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check email: %w", err)
+			}
+			if count > 0 {
+				return ErrIdentityEmailInUse
+			}
+		}
+
+		user = models.User{
+			GoogleID:         uuid.New().String(),
+			Gmail:            identity.Email,
+			Role:             r,
+			RegistrationDate: s.now(),
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return s.create(tx, user.GoogleID, identity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Link ログイン中のユーザーに外部アカウントを連携する（既に連携済みの場合はそのまま返す）
+func (s *IdentityService) Link(googleID string, identity models.ExternalIdentity) (*models.UserIdentity, error) {
+	if googleID == "" {
+		return nil, errors.New("googleID is required")
+	}
+	if identity.Provider == "" || identity.Issuer == "" || identity.Subject == "" {
+		return nil, errors.New("provider, issuer and subject are required")
+	}
+
+	var linked models.UserIdentity
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("issuer = ? AND subject = ?", identity.Issuer, identity.Subject).First(&linked).Error
+		switch {
+		case err == nil:
+			if linked.GoogleID != googleID {
+				return ErrIdentityLinkedToOtherUser
+			}
+			return s.touch(tx, &linked, identity.Email)
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to check identity: %w", err)
+		}
+
+		var count int64
+		if err := tx.Model(&models.UserIdentity{}).Where("googleId = ? AND provider = ?", googleID, identity.Provider).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check identity: %w", err)
+		}
+		if count > 0 {
+			return ErrProviderAlreadyLinked
+		}
+
+		if err := s.create(tx, googleID, identity); err != nil {
+			return err
+		}
+		return tx.Where("issuer = ? AND subject = ?", identity.Issuer, identity.Subject).First(&linked).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &linked, nil
+}
+
+// Unlink 外部アカウントの連携を解除する（ログインできなくなるため最後の1件は解除できない）
+func (s *IdentityService) Unlink(googleID, provider string) error {
+	if googleID == "" || provider == "" {
+		return errors.New("googleID and provider are required")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var identities []models.UserIdentity
+		if err := tx.Where("googleId = ?", googleID).Find(&identities).Error; err != nil {
+			return fmt.Errorf("failed to get identities: %w", err)
+		}
+		var target *models.UserIdentity
+		for i := range identities {
+			if identities[i].Provider == provider {
+				target = &identities[i]
+			}
+		}
+		if target == nil {
+			return ErrIdentityNotFound
+		}
+		if len(identities) <= 1 {
+			return ErrLastIdentity
+		}
+		if err := tx.Delete(target).Error; err != nil {
+			return fmt.Errorf("failed to unlink identity: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListIdentities ユーザーに連携している外部アカウントを連携した順に取得
+func (s *IdentityService) ListIdentities(googleID string) ([]models.UserIdentity, error) {
+	if googleID == "" {
+		return nil, errors.New("googleID is required")
+	}
+
+	var identities []models.UserIdentity
+	if err := s.db.Where("googleId = ?", googleID).Order("createdAt ASC").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("failed to get identities: %w", err)
+	}
+	return identities, nil
+}
+
+// create 連携情報を作成する（同じ外部アカウントが同時に連携された場合は一意制約で失敗する）
+func (s *IdentityService) create(tx *gorm.DB, googleID string, identity models.ExternalIdentity) error {
+	now := s.now()
+	record := models.UserIdentity{
+		GoogleID:    googleID,
+		Provider:    identity.Provider,
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		LastLoginAt: &now,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to link identity: %w", err)
+	}
+	return nil
+}
+
+// touch 最終ログイン日時と、プロバイダーから得たメールアドレスを更新する
+func (s *IdentityService) touch(tx *gorm.DB, linked *models.UserIdentity, email string) error {
+	now := s.now()
+	updates := map[string]interface{}{"lastLoginAt": now}
+	if email != "" {
+		updates["email"] = email
+	}
+	if err := tx.Model(linked).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update identity: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kojan-map/business/pkg/oauth"
+	"kojan-map/user/models"
+)
+
+var (
+	googleIdentity = models.ExternalIdentity{Provider: oauth.ProviderGoogle, Issuer: oauth.GoogleIssuer, Subject: "google-sub-1", Email: "user@example.com"}
+	lineIdentity   = models.ExternalIdentity{Provider: "line", Issuer: "https://access.line.me", Subject: "line-sub-1"}
+)
+
+func TestIdentityService_ResolveUser(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewIdentityService(db)
+
+	// 初回は内部ユーザーIDを割り当てて登録
+	user, err := service.ResolveUser(googleIdentity, "user")
+	require.NoError(t, err)
+	assert.NotEqual(t, googleIdentity.Subject, user.GoogleID)
+	assert.Equal(t, "user@example.com", user.Gmail)
+
+	// 同じアカウントは同じユーザー
+	again, err := service.ResolveUser(googleIdentity, "user")
+	require.NoError(t, err)
+	assert.Equal(t, user.GoogleID, again.GoogleID)
+
+	// メールアドレスのない LINE アカウントでも登録できる
+	lineUser, err := service.ResolveUser(lineIdentity, "user")
+	require.NoError(t, err)
+	assert.NotEqual(t, user.GoogleID, lineUser.GoogleID)
+	assert.Empty(t, lineUser.Gmail)
+
+	// メールアドレスのないユーザーは複数登録できる
+	other := lineIdentity
+	other.Subject = "line-sub-2"
+	_, err = service.ResolveUser(other, "user")
+	require.NoError(t, err)
+
+	// 同じメールアドレスの別のアカウントは自動で連携しない
+	sameEmail := models.ExternalIdentity{Provider: "line", Issuer: "https://access.line.me", Subject: "line-sub-3", Email: "user@example.com"}
+	_, err = service.ResolveUser(sameEmail, "user")
+	assert.ErrorIs(t, err, ErrIdentityEmailInUse)
+}
+
+func TestIdentityService_LinkAndUnlink(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewIdentityService(db)
+
+	user, err := service.ResolveUser(googleIdentity, "user")
+	require.NoError(t, err)
+
+	// 最後のログイン方法は解除できない
+	assert.ErrorIs(t, service.Unlink(user.GoogleID, oauth.ProviderGoogle), ErrLastIdentity)
+
+	// LINE を連携すると、どちらでも同じユーザーとしてログインできる
+	_, err = service.Link(user.GoogleID, lineIdentity)
+	require.NoError(t, err)
+	viaLine, err := service.ResolveUser(lineIdentity, "user")
+	require.NoError(t, err)
+	assert.Equal(t, user.GoogleID, viaLine.GoogleID)
+
+	// 連携は冪等
+	_, err = service.Link(user.GoogleID, lineIdentity)
+	assert.NoError(t, err)
+
+	// 同じプロバイダーの別のアカウントは連携できない
+	other := lineIdentity
+	other.Subject = "line-sub-2"
+	_, err = service.Link(user.GoogleID, other)
+	assert.ErrorIs(t, err, ErrProviderAlreadyLinked)
+
+	// 別のユーザーに連携済みのアカウントは連携できない
+	otherUser, err := service.ResolveUser(other, "user")
+	require.NoError(t, err)
+	_, err = service.Link(otherUser.GoogleID, lineIdentity)
+	assert.ErrorIs(t, err, ErrIdentityLinkedToOtherUser)
+
+	identities, err := service.ListIdentities(user.GoogleID)
+	require.NoError(t, err)
+	assert.Len(t, identities, 2)
+
+	require.NoError(t, service.Unlink(user.GoogleID, oauth.ProviderGoogle))
+	assert.ErrorIs(t, service.Unlink(user.GoogleID, oauth.ProviderGoogle), ErrIdentityNotFound)
+}
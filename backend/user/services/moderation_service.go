@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	shared "kojan-map/business/pkg/models"
+	"kojan-map/user/models"
+
+	"gorm.io/gorm"
+)
+
+// ModerationPolicy 投稿審査のルール
+type ModerationPolicy struct {
+	NewAccountReviewPeriod  time.Duration // 登録からこの期間内のアカウントの投稿は審査待ち（0で無効）
+	AutoHideReportThreshold int           // この人数以上の異なる通報者で自動非表示（0で無効）
+	AutoHideReportWindow    time.Duration // 通報者数を数える期間
+}
+
+// ModerationService 投稿審査（事前審査・自動非表示）のビジネスロジック
+type ModerationService struct {
+	db     *gorm.DB
+	policy ModerationPolicy
+}
+
+func NewModerationService(db *gorm.DB, policy ModerationPolicy) *ModerationService {
+	return &ModerationService{db: db, policy: policy}
+}
+
+// InitialStatus 新規投稿の審査状態を判定（新規アカウントは審査待ち）
+func (ms *ModerationService) InitialStatus(userID string) (string, error) {
+	if ms == nil || ms.policy.NewAccountReviewPeriod <= 0 {
+		return shared.ModerationApproved, nil
+	}
+
+	var user models.User
+	if err := ms.db.Where("googleId = ?", userID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// ユーザー情報がない場合は安全側に倒す
+			return shared.ModerationPending, nil
+		}
+		return "", err
+	}
+
+	if time.Since(user.RegistrationDate) < ms.policy.NewAccountReviewPeriod {
+		return shared.ModerationPending, nil
+	}
+	return shared.ModerationApproved, nil
+}
+
+// EvaluateReports 期間内の異なる通報者数が閾値以上なら投稿を自動非表示にする
+// db には通報を作成したトランザクションを渡す。非表示にした場合は true を返す
+func (ms *ModerationService) EvaluateReports(db *gorm.DB, postID int32) (bool, error) {
+	if ms == nil || ms.policy.AutoHideReportThreshold <= 0 {
+		return false, nil
+	}
+
+	since := time.Now().Add(-ms.policy.AutoHideReportWindow)
+	var reporters int64
+	if err := db.Model(&models.Report{}).
+		Where("postId = ? AND reportFlag = ? AND date >= ?", postID, false, since).
+		Distinct("userId").
+		Count(&reporters).Error; err != nil {
+		return false, err
+	}
+	if reporters < int64(ms.policy.AutoHideReportThreshold) {
+		return false, nil
+	}
+
+	// 承認済みの投稿のみ非表示にする（審査待ち・却下済みは変更しない）
+	now := time.Now()
+	result := db.Model(&models.Post{}).
+		Where("postId = ? AND moderationStatus = ?", postID, shared.ModerationApproved).
+		Updates(map[string]interface{}{
+			"moderationStatus": shared.ModerationHidden,
+			"moderatedAt":      now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -2,11 +2,20 @@ package services
 
 import (
 	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	shared "kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/reportcategory"
+	"kojan-map/business/pkg/validate"
+	"kojan-map/shared/contentfilter"
 	"kojan-map/user/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // BlockService ブロック関連のビジネスロジック
@@ -81,57 +90,329 @@ func (bs *BlockService) GetBlockList(userID string) ([]models.UserBlock, error)
 	return blocks, nil
 }
 
+// 通報のエラー
+var (
+	ErrInvalidReportCategory = errors.New("category must be one of spam, personal_info, harassment, dangerous, wrong_location, other")
+	ErrReportCommentRequired = errors.New("comment is required for category other")
+	ErrReportCommentTooLong  = errors.New("comment is too long")
+	ErrDuplicateReport       = errors.New("you have already reported this post")
+)
+
+// maxReportCommentLength 通報コメントの最大文字数
+const maxReportCommentLength = 1000
+
 // ReportService 通報関連のビジネスロジック
 type ReportService struct {
-	db *gorm.DB
+	db         *gorm.DB
+	moderation *ModerationService
 }
 
 func NewReportService(db *gorm.DB) *ReportService {
 	return &ReportService{db: db}
 }
 
+// SetModeration 通報による自動非表示ルールを設定
+func (rs *ReportService) SetModeration(moderation *ModerationService) {
+	rs.moderation = moderation
+}
+
 // CreateReport 通報を作成
-func (rs *ReportService) CreateReport(userID string, postID int32, reason string) error {
-	if userID == "" || postID == 0 || reason == "" {
-		return errors.New("userID, postID, and reason are required")
+// category は reportcategory の分類、comment は任意（other の場合は必須）
+// 同じ投稿への未対応の通報が既にある場合は ErrDuplicateReport（管理者が対応した後は再度通報できる）
+func (rs *ReportService) CreateReport(userID string, postID int32, category, comment string) error {
+	if userID == "" || postID == 0 {
+		return errors.New("userID and postID are required")
+	}
+	comment = strings.TrimSpace(comment)
+	if !reportcategory.IsValid(reportcategory.Category(category)) {
+		return ErrInvalidReportCategory
+	}
+	if category == string(reportcategory.Other) && comment == "" {
+		return ErrReportCommentRequired
+	}
+	if utf8.RuneCountInString(comment) > maxReportCommentLength {
+		return ErrReportCommentTooLong
 	}
 
+	open := true
 	report := models.Report{
 		UserID:     userID,
 		PostID:     postID,
-		Reason:     reason,
+		Category:   category,
+		Reason:     comment,
+		OpenFlag:   &open,
 		Date:       time.Now(),
 		ReportFlag: false,
 		RemoveFlag: false,
 	}
-	return rs.db.Create(&report).Error
+	err := rs.db.Transaction(func(tx *gorm.DB) error {
+		// openFlag のない以前の通報も含めて、未対応の通報を確認する
+		var count int64
+		if err := tx.Model(&models.Report{}).
+			Where("userId = ? AND postId = ? AND reportFlag = ?", userID, postID, false).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDuplicateReport
+		}
+		if err := tx.Create(&report).Error; err != nil {
+			return err
+		}
+		// 通報者数が閾値に達した投稿は審査まで自動非表示（通報と同じトランザクションで判定する）
+		_, err := rs.moderation.EvaluateReports(tx, postID)
+		return err
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// 同時に送信された通報は一意インデックスで検出する
+		return ErrDuplicateReport
+	}
+	return err
+}
+
+// GetMyReports 自分の通報と管理者の対応結果を新しい順に取得
+func (rs *ReportService) GetMyReports(userID string) ([]models.Report, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+
+	var reports []models.Report
+	if err := rs.db.Where("userId = ?", userID).
+		Order("date DESC").
+		Find(&reports).Error; err != nil {
+		return nil, errors.New("failed to fetch reports")
+	}
+	return reports, nil
+}
+
+// 問い合わせのエラー
+var (
+	ErrContactNotFound        = errors.New("contact not found")
+	ErrContactMessageRequired = errors.New("text is required")
+	ErrContactMessageTooLong  = errors.New("text is too long")
+)
+
+// maxContactMessageLength 追加の問い合わせの最大文字数
+const maxContactMessageLength = 5000
+
+// ContactThread 自分の問い合わせと管理者とのやり取り
+type ContactThread struct {
+	AskID     int32            `json:"askId"`
+	Date      time.Time        `json:"date"`
+	Subject   string           `json:"subject"`
+	Text      string           `json:"text"`
+	Status    shared.AskStatus `json:"status"` // open（対応待ち）, waiting（返信済み）, closed（対応済み）
+	UpdatedAt *time.Time       `json:"updatedAt,omitempty"`
+	Messages  []ContactMessage `json:"messages"` // 古い順（管理者間のメモは含めない）
+}
+
+// ContactMessage 問い合わせのやり取り
+type ContactMessage struct {
+	MessageID int64     `json:"messageId"`
+	Kind      string    `json:"kind"` // reply（管理者からの返信）または follow_up（自分の追加の問い合わせ）
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 // ContactService 問い合わせ関連のビジネスロジック
 type ContactService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	contentFilter *contentfilter.Service
 }
 
 func NewContactService(db *gorm.DB) *ContactService {
 	return &ContactService{db: db}
 }
 
+// SetContentFilter NGワード・個人情報フィルタを設定
+func (cs *ContactService) SetContentFilter(contentFilter *contentfilter.Service) {
+	cs.contentFilter = contentFilter
+}
+
 // CreateContact 問い合わせを作成
 func (cs *ContactService) CreateContact(userID, subject, text string) error {
 	if userID == "" || subject == "" || text == "" {
 		return errors.New("userID, subject and text are required")
 	}
 
-	contact := models.Contact{
-		AskUserID: userID,
-		Date:      time.Now(),
-		Subject:   subject,
-		Text:      text,
-		AskFlag:   false,
+	// 問い合わせは管理者のみが閲覧するため、審査待ち判定は拒否・マスクのみ適用する
+	if _, err := cs.contentFilter.Apply(&subject, &text); err != nil {
+		return err
+	}
+
+	contact := shared.Ask{
+		UserID:  userID,
+		Date:    time.Now(),
+		Subject: subject,
+		Text:    text,
+		AskFlag: false,
 	}
 	return cs.db.Create(&contact).Error
 }
 
+// GetMyContacts 自分の問い合わせとやり取りを新しい順に取得
+func (cs *ContactService) GetMyContacts(userID string) ([]ContactThread, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+
+	var asks []shared.Ask
+	if err := cs.db.Where("userId = ?", userID).
+		Order("date DESC, askId DESC").
+		Find(&asks).Error; err != nil {
+		return nil, errors.New("failed to fetch contacts")
+	}
+	threads := make([]ContactThread, len(asks))
+	if len(asks) == 0 {
+		return threads, nil
+	}
+
+	ids := make([]int32, len(asks))
+	index := make(map[int32]int, len(asks))
+	for i, a := range asks {
+		ids[i] = a.AskID
+		index[a.AskID] = i
+		threads[i] = ContactThread{
+			AskID:     a.AskID,
+			Date:      a.Date,
+			Subject:   a.Subject,
+			Text:      a.Text,
+			Status:    a.Status,
+			UpdatedAt: a.UpdatedAt,
+			Messages:  []ContactMessage{},
+		}
+	}
+
+	var messages []shared.AskMessage
+	if err := cs.db.Where("askId IN ? AND kind <> ?", ids, shared.AskMessageNote).
+		Order("createdAt ASC, messageId ASC").
+		Find(&messages).Error; err != nil {
+		return nil, errors.New("failed to fetch contact messages")
+	}
+	for _, m := range messages {
+		t := &threads[index[m.AskID]]
+		t.Messages = append(t.Messages, toContactMessage(m))
+	}
+	return threads, nil
+}
+
+// AddFollowUp 自分の問い合わせに追加で問い合わせる
+// 返信済み・対応済みの問い合わせは対応待ち（open）に戻る
+func (cs *ContactService) AddFollowUp(userID string, askID int32, text string) (*ContactMessage, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrContactMessageRequired
+	}
+	if utf8.RuneCountInString(text) > maxContactMessageLength {
+		return nil, ErrContactMessageTooLong
+	}
+	if _, err := cs.contentFilter.Apply(&text); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	message := shared.AskMessage{
+		AskID:     askID,
+		Kind:      shared.AskMessageFollowUp,
+		AuthorID:  userID,
+		Body:      text,
+		CreatedAt: now,
+	}
+	err := cs.db.Transaction(func(tx *gorm.DB) error {
+		// 他のユーザーの問い合わせは存在しないものとして扱う
+		var ask shared.Ask
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("askId = ? AND userId = ?", askID, userID).
+			First(&ask).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrContactNotFound
+			}
+			return err
+		}
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&shared.Ask{}).
+			Where("askId = ?", askID).
+			Updates(map[string]interface{}{
+				"status":    shared.AskStatusOpen,
+				"askFlag":   false,
+				"updatedAt": now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := toContactMessage(message)
+	return &result, nil
+}
+
+func toContactMessage(m shared.AskMessage) ContactMessage {
+	return ContactMessage{
+		MessageID: m.MessageID,
+		Kind:      m.Kind,
+		Body:      m.Body,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// 事業者申請のエラー
+var (
+	ErrApplicationFieldsRequired   = errors.New("name and address are required")
+	ErrApplicationFieldTooLong     = errors.New("name must be at most 50 characters and address at most 100 characters")
+	ErrInvalidApplicationPhone     = errors.New("phone must be 10 or 11 digits")
+	ErrInvalidCorporateNumber      = errors.New("corporateNumber must be a valid 13-digit corporate number")
+	ErrApplicationNoteTooLong      = errors.New("note is too long")
+	ErrAlreadyBusiness             = errors.New("you already have a business account")
+	ErrApplicationInProgress       = errors.New("you already have an application under review")
+	ErrDuplicateApplicationPhone   = errors.New("this phone number is already used by another application")
+	ErrApplicationNotFound         = errors.New("application not found")
+	ErrApplicationNotEditable      = errors.New("application cannot be changed in its current status")
+	ErrInvalidApplicationDocument  = errors.New("kind must be one of license, registry, other")
+	ErrApplicationDocumentType     = errors.New("document must be a PDF, PNG or JPEG file")
+	ErrApplicationDocumentTooLarge = errors.New("document size exceeds 5MB limit")
+	ErrTooManyApplicationDocuments = errors.New("an application can have at most 5 documents")
+)
+
+const (
+	maxApplicationNoteLength   = 1000
+	maxApplicationDocumentSize = 5 * 1024 * 1024
+	maxApplicationDocuments    = 5
+)
+
+// applicationDocumentTypes 添付できる書類の形式（実データから判定）
+var applicationDocumentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+}
+
+// BusinessApplicationInput 事業者申請の入力
+type BusinessApplicationInput struct {
+	Name            string `json:"name"`
+	Address         string `json:"address"`
+	Phone           string `json:"phone"`           // ハイフン・空白は除いて保存
+	CorporateNumber string `json:"corporateNumber"` // 法人番号（任意、13桁）
+}
+
+// BusinessApplication 自分の事業者申請と添付書類・状態の履歴
+type BusinessApplication struct {
+	shared.BusinessRequest
+	Documents []shared.BusinessRequestDocument `json:"documents"` // 書類の内容は含めない
+	History   []ApplicationEvent               `json:"history"`   // 古い順
+}
+
+// ApplicationEvent 事業者申請の状態の変化（管理者の ID は含めない）
+type ApplicationEvent struct {
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Note       string    `json:"note"` // 追加情報の依頼内容・却下理由・再提出時のコメント
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
 // BusinessApplicationService 事業者申請関連のビジネスロジック
 type BusinessApplicationService struct {
 	db *gorm.DB
@@ -142,16 +423,324 @@ func NewBusinessApplicationService(db *gorm.DB) *BusinessApplicationService {
 }
 
 // CreateBusinessApplication 事業者申請を作成
-func (bas *BusinessApplicationService) CreateBusinessApplication(userID, name, address, phone string) error {
-	if userID == "" || name == "" || address == "" {
-		return errors.New("userID, name, and address are required")
+// 事業者アカウントを持っている場合・審査中の申請がある場合・他の申請者と電話番号が重複する場合は受け付けない
+func (bas *BusinessApplicationService) CreateBusinessApplication(userID string, input BusinessApplicationInput) (*shared.BusinessRequest, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+	if err := input.normalize(); err != nil {
+		return nil, err
 	}
 
-	app := models.BusinessRequest{
-		UserID:  userID,
-		Name:    name,
-		Address: address,
-		Phone:   phone,
+	app := shared.BusinessRequest{
+		UserID:          userID,
+		Name:            input.Name,
+		Address:         input.Address,
+		Phone:           input.Phone,
+		CorporateNumber: input.corporateNumber(),
+		Status:          shared.BusinessRequestPending,
+	}
+	err := bas.db.Transaction(func(tx *gorm.DB) error {
+		// 同じユーザーの申請を直列にするため申請者の行をロックする
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("googleId = ?", userID).
+			First(&user).Error; err != nil {
+			return errors.New("user not found")
+		}
+		if user.Role == shared.RoleBusiness {
+			return ErrAlreadyBusiness
+		}
+		var count int64
+		if err := tx.Model(&models.Business{}).Where("userId = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyBusiness
+		}
+		if err := tx.Model(&shared.BusinessRequest{}).
+			Where("userId = ? AND status IN ?", userID, []string{shared.BusinessRequestPending, shared.BusinessRequestNeedsInfo}).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrApplicationInProgress
+		}
+		if err := checkDuplicatePhone(tx, userID, input.Phone); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&app).Error; err != nil {
+			return err
+		}
+		return recordApplicationEvent(tx, &app, "", userID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// GetMyApplications 自分の事業者申請を新しい順に取得
+func (bas *BusinessApplicationService) GetMyApplications(userID string) ([]BusinessApplication, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+
+	var requests []shared.BusinessRequest
+	if err := bas.db.Where("userId = ?", userID).
+		Order("createdAt DESC, requestId DESC").
+		Find(&requests).Error; err != nil {
+		return nil, errors.New("failed to fetch applications")
+	}
+	apps := make([]BusinessApplication, len(requests))
+	if len(requests) == 0 {
+		return apps, nil
+	}
+
+	ids := make([]int32, len(requests))
+	index := make(map[int32]int, len(requests))
+	for i, r := range requests {
+		ids[i] = r.RequestID
+		index[r.RequestID] = i
+		apps[i] = BusinessApplication{
+			BusinessRequest: r,
+			Documents:       []shared.BusinessRequestDocument{},
+			History:         []ApplicationEvent{},
+		}
+	}
+
+	var documents []shared.BusinessRequestDocument
+	if err := bas.db.Omit("data").
+		Where("requestId IN ?", ids).
+		Order("documentId ASC").
+		Find(&documents).Error; err != nil {
+		return nil, errors.New("failed to fetch application documents")
+	}
+	for _, d := range documents {
+		a := &apps[index[d.RequestID]]
+		a.Documents = append(a.Documents, d)
+	}
+
+	var history []shared.BusinessRequestHistory
+	if err := bas.db.Where("requestId IN ?", ids).
+		Order("createdAt ASC, historyId ASC").
+		Find(&history).Error; err != nil {
+		return nil, errors.New("failed to fetch application history")
+	}
+	for _, h := range history {
+		a := &apps[index[h.RequestID]]
+		a.History = append(a.History, ApplicationEvent{
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			Note:       h.Note,
+			CreatedAt:  h.CreatedAt,
+		})
+	}
+	return apps, nil
+}
+
+// AttachDocument 審査中の自分の事業者申請に確認書類（営業許可証など）を添付
+func (bas *BusinessApplicationService) AttachDocument(userID string, requestID int32, kind, fileName string, data []byte) (*shared.BusinessRequestDocument, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+	switch kind {
+	case shared.BusinessDocumentLicense, shared.BusinessDocumentRegistry, shared.BusinessDocumentOther:
+	default:
+		return nil, ErrInvalidApplicationDocument
+	}
+	if len(data) > maxApplicationDocumentSize {
+		return nil, ErrApplicationDocumentTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !applicationDocumentTypes[contentType] {
+		return nil, ErrApplicationDocumentType
+	}
+	fileName = documentFileName(fileName)
+
+	document := shared.BusinessRequestDocument{
+		RequestID:   requestID,
+		Kind:        kind,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Data:        data,
+	}
+	err := bas.db.Transaction(func(tx *gorm.DB) error {
+		app, err := findMyApplicationForUpdate(tx, userID, requestID)
+		if err != nil {
+			return err
+		}
+		if !app.IsActive() {
+			return ErrApplicationNotEditable
+		}
+		var count int64
+		if err := tx.Model(&shared.BusinessRequestDocument{}).Where("requestId = ?", requestID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxApplicationDocuments {
+			return ErrTooManyApplicationDocuments
+		}
+		return tx.Create(&document).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	document.Data = nil
+	return &document, nil
+}
+
+// ResubmitApplication 追加情報を依頼された自分の事業者申請を修正して審査待ちに戻す
+func (bas *BusinessApplicationService) ResubmitApplication(userID string, requestID int32, input BusinessApplicationInput, note string) (*shared.BusinessRequest, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+	if err := input.normalize(); err != nil {
+		return nil, err
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxApplicationNoteLength {
+		return nil, ErrApplicationNoteTooLong
+	}
+
+	var app *shared.BusinessRequest
+	err := bas.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		app, err = findMyApplicationForUpdate(tx, userID, requestID)
+		if err != nil {
+			return err
+		}
+		if app.Status != shared.BusinessRequestNeedsInfo {
+			return ErrApplicationNotEditable
+		}
+		if err := checkDuplicatePhone(tx, userID, input.Phone); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		app.Name = input.Name
+		app.Address = input.Address
+		app.Phone = input.Phone
+		app.CorporateNumber = input.corporateNumber()
+		app.Status = shared.BusinessRequestPending
+		app.UpdatedAt = &now
+		if err := tx.Model(&shared.BusinessRequest{}).Where("requestId = ?", requestID).Updates(map[string]interface{}{
+			"name":            app.Name,
+			"address":         app.Address,
+			"phone":           app.Phone,
+			"corporateNumber": app.CorporateNumber,
+			"status":          app.Status,
+			"updatedAt":       now,
+		}).Error; err != nil {
+			return err
+		}
+		return recordApplicationEvent(tx, app, shared.BusinessRequestNeedsInfo, userID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// normalize 入力の前後の空白と電話番号・法人番号の区切りを取り除いて検証
+func (in *BusinessApplicationInput) normalize() error {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Address = strings.TrimSpace(in.Address)
+	if in.Name == "" || in.Address == "" {
+		return ErrApplicationFieldsRequired
+	}
+	if utf8.RuneCountInString(in.Name) > 50 || utf8.RuneCountInString(in.Address) > 100 {
+		return ErrApplicationFieldTooLong
+	}
+	in.Phone = stripSeparators(in.Phone)
+	if n := len(in.Phone); (n != 10 && n != 11) || strings.Trim(in.Phone, "0123456789") != "" {
+		return ErrInvalidApplicationPhone
 	}
-	return bas.db.Create(&app).Error
+	in.CorporateNumber = stripSeparators(in.CorporateNumber)
+	if in.CorporateNumber != "" && !validate.CorporateNumber(in.CorporateNumber) {
+		return ErrInvalidCorporateNumber
+	}
+	return nil
+}
+
+// corporateNumber 法人番号（未入力の場合は nil）
+func (in *BusinessApplicationInput) corporateNumber() *string {
+	if in.CorporateNumber == "" {
+		return nil
+	}
+	n := in.CorporateNumber
+	return &n
+}
+
+// stripSeparators 電話番号・法人番号の区切り（ハイフン・空白・括弧）を取り除く
+func stripSeparators(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', 'ー', '−', '‐', ' ', '　', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+}
+
+// documentFileName 保存する書類のファイル名（パスを除き、最大255文字）
+func documentFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "document"
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		name = string([]rune(name)[:255])
+	}
+	return name
+}
+
+// checkDuplicatePhone 他のユーザーの審査中・承認済みの申請や事業者アカウントと電話番号が重複していないか確認
+func checkDuplicatePhone(tx *gorm.DB, userID, phone string) error {
+	var count int64
+	if err := tx.Model(&shared.BusinessRequest{}).
+		Where("phone = ? AND userId <> ? AND status IN ?", phone, userID,
+			[]string{shared.BusinessRequestPending, shared.BusinessRequestNeedsInfo, shared.BusinessRequestApproved}).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateApplicationPhone
+	}
+	if err := tx.Model(&models.Business{}).
+		Where("phone = ? AND userId <> ?", phone, userID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateApplicationPhone
+	}
+	return nil
+}
+
+// findMyApplicationForUpdate 自分の事業者申請をトランザクションの終了までロック
+// 他のユーザーの申請は存在しないものとして扱う
+func findMyApplicationForUpdate(tx *gorm.DB, userID string, requestID int32) (*shared.BusinessRequest, error) {
+	var app shared.BusinessRequest
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("requestId = ? AND userId = ?", requestID, userID).
+		First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	return &app, nil
+}
+
+// recordApplicationEvent 事業者申請の状態の変化を履歴に記録（tx 内で呼び出す）
+func recordApplicationEvent(tx *gorm.DB, app *shared.BusinessRequest, from, actorID, note string) error {
+	return tx.Create(&shared.BusinessRequestHistory{
+		RequestID:  app.RequestID,
+		FromStatus: from,
+		ToStatus:   app.Status,
+		ActorID:    actorID,
+		Note:       note,
+	}).Error
 }
@@ -2,7 +2,9 @@ package services
 
 import (
 	"github.com/stretchr/testify/assert"
+	shared "kojan-map/business/pkg/models"
 	"kojan-map/user/models"
+	"strings"
 	"testing"
 	"time"
 )
@@ -148,11 +150,24 @@ func TestReportService_CreateReport(t *testing.T) {
 
 	// FK 満たすためのユーザー・投稿作成
 	db.Create(&models.User{GoogleID: "google_reporter", Gmail: "reporter@example.com", Role: "user", RegistrationDate: time.Now()})
-	db.Create(&models.Post{ID: 100, UserID: "google_reporter", Title: "test", Text: "test", PostDate: time.Now()})
+	db.Create(&models.Post{PostID: 100, UserID: "google_reporter", Title: "test", Text: "test", PostDate: time.Now()})
 
 	// 通報を作成
-	err := service.CreateReport("google_reporter", int32(100), "不適切な内容")
+	err := service.CreateReport("google_reporter", int32(100), "harassment", "不適切な内容")
 	assert.NoError(t, err)
+
+	// 未対応の通報がある間は同じ投稿を通報できない
+	err = service.CreateReport("google_reporter", int32(100), "spam", "")
+	assert.ErrorIs(t, err, ErrDuplicateReport)
+
+	// 管理者が対応した後は再度通報できる
+	db.Model(&models.Report{}).Where("userId = ?", "google_reporter").Updates(map[string]interface{}{"reportFlag": true, "openFlag": nil})
+	err = service.CreateReport("google_reporter", int32(100), "spam", "")
+	assert.NoError(t, err)
+
+	var report models.Report
+	db.Where("userId = ? AND reportFlag = ?", "google_reporter", false).First(&report)
+	assert.Equal(t, "spam", report.Category)
 }
 
 func TestReportService_CreateReport_ValidationError(t *testing.T) {
@@ -164,9 +179,37 @@ func TestReportService_CreateReport_ValidationError(t *testing.T) {
 	db.Create(&models.User{GoogleID: "google_reporter", Gmail: "reporter@example.com", Role: "user", RegistrationDate: time.Now()})
 
 	// Reasonが空
-	err := service.CreateReport("", 0, "")
+	err := service.CreateReport("", 0, "", "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "required")
+
+	assert.ErrorIs(t, service.CreateReport("google_reporter", 100, "rude", "x"), ErrInvalidReportCategory)
+	assert.ErrorIs(t, service.CreateReport("google_reporter", 100, "other", " "), ErrReportCommentRequired)
+	assert.ErrorIs(t, service.CreateReport("google_reporter", 100, "spam", strings.Repeat("あ", 1001)), ErrReportCommentTooLong)
+}
+
+func TestReportService_GetMyReports(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewReportService(db)
+
+	db.Create(&models.User{GoogleID: "google_reporter", Gmail: "reporter@example.com", Role: "user", RegistrationDate: time.Now()})
+	db.Create(&models.Post{PostID: 100, UserID: "google_reporter", Title: "test", Text: "test", PostDate: time.Now()})
+	resolvedAt := time.Now()
+	db.Create(&models.Report{UserID: "google_reporter", PostID: 100, Reason: "スパム", Date: time.Now().Add(-time.Hour),
+		ReportFlag: true, RemoveFlag: true, Resolution: "remove_post", ResolutionNote: "ガイドライン違反", ResolvedAt: &resolvedAt})
+	db.Create(&models.Report{UserID: "google_reporter", PostID: 100, Reason: "不適切な内容", Date: time.Now()})
+
+	reports, err := service.GetMyReports("google_reporter")
+	assert.NoError(t, err)
+	assert.Len(t, reports, 2)
+	// 新しい順で、対応結果を確認できる
+	assert.Empty(t, reports[0].Resolution)
+	assert.Equal(t, "remove_post", reports[1].Resolution)
+	assert.Equal(t, "ガイドライン違反", reports[1].ResolutionNote)
+
+	_, err = service.GetMyReports("")
+	assert.Error(t, err)
 }
 
 func TestContactService_CreateContact(t *testing.T) {
@@ -192,25 +235,146 @@ func TestContactService_CreateContact_ValidationError(t *testing.T) {
 	assert.Contains(t, err.Error(), "required")
 }
 
+func TestContactService_FollowUpThread(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewContactService(db)
+	db.Create(&models.User{GoogleID: "google_sender", Gmail: "sender@example.com", Role: "user", RegistrationDate: time.Now()})
+	db.Create(&models.User{GoogleID: "google_other", Gmail: "other@example.com", Role: "user", RegistrationDate: time.Now()})
+	assert.NoError(t, service.CreateContact("google_sender", "質問", "これは質問です"))
+
+	contacts, err := service.GetMyContacts("google_sender")
+	assert.NoError(t, err)
+	assert.Len(t, contacts, 1)
+	assert.Equal(t, shared.AskStatusOpen, contacts[0].Status)
+	askID := contacts[0].AskID
+
+	// 管理者の返信とメモ（メモはユーザーに見せない）
+	now := time.Now()
+	db.Create(&shared.AskMessage{AskID: askID, Kind: shared.AskMessageReply, AuthorID: "admin", Body: "回答です", CreatedAt: now})
+	db.Create(&shared.AskMessage{AskID: askID, Kind: shared.AskMessageNote, AuthorID: "admin", Body: "社内メモ", CreatedAt: now})
+	db.Model(&shared.Ask{}).Where("askId = ?", askID).Updates(map[string]interface{}{"status": shared.AskStatusClosed, "askFlag": true})
+
+	// 追加の問い合わせで対応待ちに戻る
+	message, err := service.AddFollowUp("google_sender", askID, "  追加の質問です  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "追加の質問です", message.Body)
+
+	contacts, err = service.GetMyContacts("google_sender")
+	assert.NoError(t, err)
+	assert.Equal(t, shared.AskStatusOpen, contacts[0].Status)
+	assert.Len(t, contacts[0].Messages, 2)
+	assert.Equal(t, shared.AskMessageReply, contacts[0].Messages[0].Kind)
+	assert.Equal(t, shared.AskMessageFollowUp, contacts[0].Messages[1].Kind)
+
+	// 他のユーザーの問い合わせには追加できない
+	_, err = service.AddFollowUp("google_other", askID, "横から失礼します")
+	assert.ErrorIs(t, err, ErrContactNotFound)
+
+	_, err = service.AddFollowUp("google_sender", askID, " ")
+	assert.ErrorIs(t, err, ErrContactMessageRequired)
+	_, err = service.AddFollowUp("google_sender", askID, strings.Repeat("あ", maxContactMessageLength+1))
+	assert.ErrorIs(t, err, ErrContactMessageTooLong)
+}
+
 func TestBusinessApplicationService_CreateApplication(t *testing.T) {
 	db := setupTestDB(t)
 	cleanupDB(db)
 	service := NewBusinessApplicationService(db)
 	db.Create(&models.User{GoogleID: "google_applicant", Gmail: "applicant@example.com", Role: "user", RegistrationDate: time.Now()})
+	db.Create(&models.User{GoogleID: "google_other", Gmail: "other@example.com", Role: "user", RegistrationDate: time.Now()})
+
+	// 企業会員申請を作成（電話番号の区切りは取り除いて保存）
+	app, err := service.CreateBusinessApplication("google_applicant", BusinessApplicationInput{
+		Name:            "テスト株式会社",
+		Address:         "東京都渋谷区",
+		Phone:           "090-1234-5678",
+		CorporateNumber: "7000012050002",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "09012345678", app.Phone)
+	assert.Equal(t, shared.BusinessRequestPending, app.Status)
+
+	// 審査中の申請がある間は再申請できない
+	_, err = service.CreateBusinessApplication("google_applicant", BusinessApplicationInput{Name: "別の店", Address: "高知県高知市", Phone: "0881234567"})
+	assert.ErrorIs(t, err, ErrApplicationInProgress)
 
-	// 企業会員申請を作成
-	err := service.CreateBusinessApplication("google_applicant", "テスト株式会社", "東京都渋谷区", "09012345678")
+	// 他のユーザーと同じ電話番号では申請できない
+	_, err = service.CreateBusinessApplication("google_other", BusinessApplicationInput{Name: "別の店", Address: "高知県高知市", Phone: "090 1234 5678"})
+	assert.ErrorIs(t, err, ErrDuplicateApplicationPhone)
+
+	apps, err := service.GetMyApplications("google_applicant")
 	assert.NoError(t, err)
+	assert.Len(t, apps, 1)
+	assert.Len(t, apps[0].History, 1)
+	assert.Equal(t, "", apps[0].History[0].FromStatus)
+	assert.Equal(t, shared.BusinessRequestPending, apps[0].History[0].ToStatus)
 }
 
 func TestBusinessApplicationService_CreateApplication_ValidationError(t *testing.T) {
+	service := NewBusinessApplicationService(nil)
+
+	tests := []struct {
+		name  string
+		input BusinessApplicationInput
+		want  error
+	}{
+		{"empty name", BusinessApplicationInput{Name: " ", Address: "東京都渋谷区", Phone: "09012345678"}, ErrApplicationFieldsRequired},
+		{"long name", BusinessApplicationInput{Name: strings.Repeat("あ", 51), Address: "東京都渋谷区", Phone: "09012345678"}, ErrApplicationFieldTooLong},
+		{"short phone", BusinessApplicationInput{Name: "テスト株式会社", Address: "東京都渋谷区", Phone: "090-1234"}, ErrInvalidApplicationPhone},
+		{"phone with letters", BusinessApplicationInput{Name: "テスト株式会社", Address: "東京都渋谷区", Phone: "090abcd5678"}, ErrInvalidApplicationPhone},
+		{"wrong check digit", BusinessApplicationInput{Name: "テスト株式会社", Address: "東京都渋谷区", Phone: "09012345678", CorporateNumber: "8000012050002"}, ErrInvalidCorporateNumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateBusinessApplication("google_applicant", tt.input)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestBusinessApplicationService_InfoRequestAndResubmit(t *testing.T) {
 	db := setupTestDB(t)
 	cleanupDB(db)
 	service := NewBusinessApplicationService(db)
 	db.Create(&models.User{GoogleID: "google_applicant", Gmail: "applicant@example.com", Role: "user", RegistrationDate: time.Now()})
 
-	// BusinessNameが空
-	err := service.CreateBusinessApplication("google_applicant", "", "東京都渋谷区", "09012345678")
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "userID, name, and address are required")
+	input := BusinessApplicationInput{Name: "テスト株式会社", Address: "東京都渋谷区", Phone: "09012345678"}
+	app, err := service.CreateBusinessApplication("google_applicant", input)
+	assert.NoError(t, err)
+
+	// 追加情報の依頼前は修正できない
+	_, err = service.ResubmitApplication("google_applicant", app.RequestID, input, "")
+	assert.ErrorIs(t, err, ErrApplicationNotEditable)
+
+	// 管理者が追加情報を依頼した状態
+	db.Model(&shared.BusinessRequest{}).Where("requestId = ?", app.RequestID).Update("status", shared.BusinessRequestNeedsInfo)
+	db.Create(&shared.BusinessRequestHistory{RequestID: app.RequestID, FromStatus: shared.BusinessRequestPending, ToStatus: shared.BusinessRequestNeedsInfo, ActorID: "google_admin", Note: "営業許可証を添付してください"})
+
+	pdf := []byte("%PDF-1.4\n%test document\n")
+	document, err := service.AttachDocument("google_applicant", app.RequestID, shared.BusinessDocumentLicense, "C:\\scan\\許可証.pdf", pdf)
+	assert.NoError(t, err)
+	assert.Equal(t, "許可証.pdf", document.FileName)
+	assert.Equal(t, "application/pdf", document.ContentType)
+
+	_, err = service.AttachDocument("google_applicant", app.RequestID, shared.BusinessDocumentLicense, "notes.txt", []byte("plain text"))
+	assert.ErrorIs(t, err, ErrApplicationDocumentType)
+	_, err = service.AttachDocument("google_other", app.RequestID, shared.BusinessDocumentLicense, "許可証.pdf", pdf)
+	assert.ErrorIs(t, err, ErrApplicationNotFound)
+
+	input.Address = "東京都渋谷区神南1-1-1"
+	resubmitted, err := service.ResubmitApplication("google_applicant", app.RequestID, input, "添付しました")
+	assert.NoError(t, err)
+	assert.Equal(t, shared.BusinessRequestPending, resubmitted.Status)
+	assert.Equal(t, "東京都渋谷区神南1-1-1", resubmitted.Address)
+
+	apps, err := service.GetMyApplications("google_applicant")
+	assert.NoError(t, err)
+	assert.Len(t, apps, 1)
+	assert.Len(t, apps[0].Documents, 1)
+	assert.Nil(t, apps[0].Documents[0].Data)
+	assert.Len(t, apps[0].History, 3)
+	assert.Equal(t, "営業許可証を添付してください", apps[0].History[1].Note)
+	assert.Equal(t, shared.BusinessRequestNeedsInfo, apps[0].History[2].FromStatus)
+	assert.Equal(t, "添付しました", apps[0].History[2].Note)
 }
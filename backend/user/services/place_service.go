@@ -42,7 +42,7 @@ func (ps *PlaceService) FindOrCreatePlace(latitude, longitude float64) (int32, e
 			if updateErr := tx.Model(&place).Update("numPost", place.NumPost+1).Error; updateErr != nil {
 				return updateErr
 			}
-			placeID = place.ID
+			placeID = place.PlaceID
 			return nil
 		} else if err != gorm.ErrRecordNotFound {
 			return err
@@ -58,7 +58,7 @@ func (ps *PlaceService) FindOrCreatePlace(latitude, longitude float64) (int32, e
 		if err := tx.Create(&newPlace).Error; err != nil {
 			return err
 		}
-		placeID = newPlace.ID
+		placeID = newPlace.PlaceID
 		return nil
 	})
 
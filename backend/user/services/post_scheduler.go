@@ -0,0 +1,85 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	shared "kojan-map/business/pkg/models"
+	"kojan-map/user/models"
+
+	"gorm.io/gorm"
+)
+
+// PostScheduler 予約投稿を公開日時に公開する
+// 状態はDBに保持するため、再起動しても未公開の予約投稿は次回の実行で公開される
+type PostScheduler struct {
+	db       *gorm.DB
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewPostScheduler 予約投稿スケジューラを初期化
+func NewPostScheduler(db *gorm.DB, interval time.Duration) *PostScheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &PostScheduler{
+		db:       db,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start バックグラウンドで定期実行を開始（起動直後に一度実行し、停止中の取りこぼしを公開する）
+func (s *PostScheduler) Start() {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.run()
+		for {
+			select {
+			case <-ticker.C:
+				s.run()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 定期実行を停止し、実行中の処理の完了を待つ
+func (s *PostScheduler) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *PostScheduler) run() {
+	n, err := s.PublishDuePosts(time.Now())
+	if err != nil {
+		log.Printf("failed to publish scheduled posts: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("published %d scheduled post(s)", n)
+	}
+}
+
+// PublishDuePosts 公開日時を過ぎた予約投稿を公開し、公開件数を返す
+// 条件付きUPDATEで状態を遷移させるため、複数インスタンスで実行しても二重に公開されない
+func (s *PostScheduler) PublishDuePosts(now time.Time) (int64, error) {
+	result := s.db.Model(&models.Post{}).
+		Where("status = ? AND publishAt <= ?", shared.PostStatusScheduled, now).
+		Updates(map[string]interface{}{
+			"status":   shared.PostStatusPublished,
+			"postDate": gorm.Expr("publishAt"),
+		})
+	return result.RowsAffected, result.Error
+}
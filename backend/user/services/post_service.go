@@ -4,20 +4,43 @@ import (
 	"errors"
 	"time"
 
+	shared "kojan-map/business/pkg/models"
+	"kojan-map/shared/contentfilter"
 	"kojan-map/user/models"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrPostNotFound         = errors.New("post not found")
+	ErrPostNotOwned         = errors.New("unauthorized: you can only update your own posts")
+	ErrPostAlreadyPublished = errors.New("post is already published")
+	ErrInvalidPostStatus    = errors.New("invalid status")
+	ErrPublishAtRequired    = errors.New("publishAt is required for scheduled posts")
+	ErrPublishAtNotFuture   = errors.New("publishAt must be in the future")
+)
+
 // PostService 投稿関連のビジネスロジック
 type PostService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	moderation    *ModerationService
+	contentFilter *contentfilter.Service
 }
 
 func NewPostService(db *gorm.DB) *PostService {
 	return &PostService{db: db}
 }
 
+// SetModeration 投稿審査ルールを設定（未設定の場合は審査なしで公開）
+func (ps *PostService) SetModeration(moderation *ModerationService) {
+	ps.moderation = moderation
+}
+
+// SetContentFilter NGワード・個人情報フィルタを設定
+func (ps *PostService) SetContentFilter(contentFilter *contentfilter.Service) {
+	ps.contentFilter = contentFilter
+}
+
 // GetAllPosts 投稿一覧を取得
 func (ps *PostService) GetAllPosts() ([]map[string]interface{}, error) {
 	var posts []struct {
@@ -34,6 +57,7 @@ func (ps *PostService) GetAllPosts() ([]map[string]interface{}, error) {
 		Select("post.*, genre.genreName as genre_name, genre.color as genre_color, place.latitude, place.longitude").
 		Joins("LEFT JOIN genre ON genre.genreId = post.genreId").
 		Joins("LEFT JOIN place ON place.placeId = post.placeId").
+		Where("post.status = ? AND post.moderationStatus = ?", shared.PostStatusPublished, shared.ModerationApproved).
 		Order("post.postDate DESC").
 		Find(&posts).Error
 
@@ -45,7 +69,7 @@ func (ps *PostService) GetAllPosts() ([]map[string]interface{}, error) {
 	result := make([]map[string]interface{}, len(posts))
 	for i, post := range posts {
 		result[i] = map[string]interface{}{
-			"postId":      post.ID,
+			"postId":      post.PostID,
 			"placeId":     post.PlaceID,
 			"genreId":     post.GenreID,
 			"userId":      post.UserID,
@@ -65,17 +89,25 @@ func (ps *PostService) GetAllPosts() ([]map[string]interface{}, error) {
 }
 
 // GetPostDetail 投稿詳細を取得
-func (ps *PostService) GetPostDetail(postID int32) (map[string]interface{}, error) {
+// 下書き・予約投稿・審査中の投稿は投稿者本人（viewerID）にのみ返す
+func (ps *PostService) GetPostDetail(postID int32, viewerID string) (map[string]interface{}, error) {
 	post := models.Post{}
 	if err := ps.db.Where("postId = ?", postID).First(&post).Error; err != nil {
-		return nil, errors.New("post not found")
+		return nil, ErrPostNotFound
+	}
+	public := post.Status == shared.PostStatusPublished && post.ModerationStatus == shared.ModerationApproved
+	if !public && (viewerID == "" || post.UserID != viewerID) {
+		return nil, ErrPostNotFound
 	}
 
 	// 閲覧数をインクリメント（アトミックに実行）
-	if err := ps.db.Model(&post).UpdateColumn("numView", gorm.Expr("numView + ?", 1)).Error; err != nil {
-		return nil, err
+	// 未公開の投稿を投稿者本人が確認する場合は数えない
+	if public {
+		if err := ps.db.Model(&post).UpdateColumn("numView", gorm.Expr("numView + ?", 1)).Error; err != nil {
+			return nil, err
+		}
+		post.NumView++
 	}
-	post.NumView++
 
 	// ユーザー情報を取得
 	user := models.User{}
@@ -96,7 +128,7 @@ func (ps *PostService) GetPostDetail(postID int32) (map[string]interface{}, erro
 	}
 
 	result := map[string]interface{}{
-		"postId":      post.ID,
+		"postId":      post.PostID,
 		"placeId":     post.PlaceID,
 		"genreId":     post.GenreID,
 		"userId":      post.UserID,
@@ -106,6 +138,8 @@ func (ps *PostService) GetPostDetail(postID int32) (map[string]interface{}, erro
 		"numView":     post.NumView,
 		"numReaction": post.NumReaction,
 		"postDate":    post.PostDate,
+		"status":      post.Status,
+		"publishAt":   post.PublishAt,
 		"latitude":    place.Latitude,
 		"longitude":   place.Longitude,
 		"genreName":   genre.GenreName,
@@ -115,14 +149,113 @@ func (ps *PostService) GetPostDetail(postID int32) (map[string]interface{}, erro
 	return result, nil
 }
 
+// publiclyVisible 公開済みかつ審査で承認された投稿のみに絞り込む
+func publiclyVisible(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ? AND moderationStatus = ?", shared.PostStatusPublished, shared.ModerationApproved)
+}
+
+// applyPublishStatus 公開状態と公開日時を検証し、投稿に反映する
+func applyPublishStatus(post *models.Post, status string, publishAt *time.Time, now time.Time) error {
+	switch status {
+	case "", shared.PostStatusPublished:
+		post.Status = shared.PostStatusPublished
+		post.PublishAt = nil
+		if post.PostDate.IsZero() || post.PostDate.After(now) {
+			post.PostDate = now
+		}
+	case shared.PostStatusDraft:
+		post.Status = shared.PostStatusDraft
+		post.PublishAt = nil
+		if post.PostDate.IsZero() {
+			post.PostDate = now
+		}
+	case shared.PostStatusScheduled:
+		if publishAt == nil {
+			return ErrPublishAtRequired
+		}
+		if !publishAt.After(now) {
+			return ErrPublishAtNotFuture
+		}
+		at := *publishAt
+		post.Status = shared.PostStatusScheduled
+		post.PublishAt = &at
+		post.PostDate = at
+	default:
+		return ErrInvalidPostStatus
+	}
+	return nil
+}
+
 // CreatePost 投稿を作成
+// Status が空の場合は即時公開。予約投稿は PublishAt が必須
+// 審査ルールにより新規アカウントの投稿は審査待ちとなる
+// NGワード・個人情報はフィルタの設定に従い拒否・マスク・審査待ちとする
 func (ps *PostService) CreatePost(post *models.Post) error {
 	if post.Title == "" || post.Text == "" {
 		return errors.New("title and text are required")
 	}
+	if err := applyPublishStatus(post, post.Status, post.PublishAt, time.Now()); err != nil {
+		return err
+	}
+	needsReview, err := ps.contentFilter.Apply(&post.Title, &post.Text)
+	if err != nil {
+		return err
+	}
+	moderationStatus, err := ps.moderation.InitialStatus(post.UserID)
+	if err != nil {
+		return err
+	}
+	if needsReview {
+		moderationStatus = shared.ModerationPending
+	}
+	post.ModerationStatus = moderationStatus
 	return ps.db.Create(post).Error
 }
 
+// UpdatePostStatus 下書き・予約投稿の公開状態を変更（投稿者本人のみ）
+// 公開済みの投稿を下書きや予約に戻すことはできない
+func (ps *PostService) UpdatePostStatus(postID int32, userID string, status string, publishAt *time.Time) (*models.Post, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+
+	var post models.Post
+	if err := ps.db.Where("postId = ?", postID).First(&post).Error; err != nil {
+		return nil, ErrPostNotFound
+	}
+	if post.UserID != userID {
+		return nil, ErrPostNotOwned
+	}
+	if post.Status == shared.PostStatusPublished {
+		return nil, ErrPostAlreadyPublished
+	}
+
+	// 下書きから公開する場合は公開時刻を投稿日時とする
+	now := time.Now()
+	if status != shared.PostStatusDraft {
+		post.PostDate = now
+	}
+	if err := applyPublishStatus(&post, status, publishAt, now); err != nil {
+		return nil, err
+	}
+
+	// スケジューラとの競合を避けるため、未公開の場合のみ更新する
+	result := ps.db.Model(&models.Post{}).
+		Where("postId = ? AND status <> ?", postID, shared.PostStatusPublished).
+		Updates(map[string]interface{}{
+			"status":    post.Status,
+			"publishAt": post.PublishAt,
+			"postDate":  post.PostDate,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPostAlreadyPublished
+	}
+	return &post, nil
+}
+
 // GetUserPostHistory ユーザーの投稿履歴を取得
 func (ps *PostService) GetUserPostHistory(userID string) ([]models.Post, error) {
 	var posts []models.Post
@@ -137,7 +270,7 @@ func (ps *PostService) GetUserPostHistory(userID string) ([]models.Post, error)
 // GetPinSize ピンサイズを判定（場所の投稿数が50以上で1.3倍）
 func (ps *PostService) GetPinSize(placeID int32) (float64, error) {
 	var count int64
-	if err := ps.db.Model(&models.Post{}).
+	if err := ps.db.Model(&models.Post{}).Scopes(publiclyVisible).
 		Where("placeId = ?", placeID).
 		Count(&count).Error; err != nil {
 		return 1.0, err
@@ -155,6 +288,17 @@ func (ps *PostService) AddReaction(userID string, postID int32) error {
 		return errors.New("userID is required")
 	}
 
+	// 未公開の投稿にはリアクションできない
+	var published int64
+	if err := ps.db.Model(&models.Post{}).Scopes(publiclyVisible).
+		Where("postId = ?", postID).
+		Count(&published).Error; err != nil {
+		return err
+	}
+	if published == 0 {
+		return ErrPostNotFound
+	}
+
 	// 既にリアクション済みか確認
 	var existingReaction models.UserReaction
 	result := ps.db.Where("userId = ? AND postId = ?", userID, postID).
@@ -189,7 +333,7 @@ func (ps *PostService) AddReaction(userID string, postID int32) error {
 // SearchPostsByKeyword キーワード検索
 func (ps *PostService) SearchPostsByKeyword(keyword string) ([]models.Post, error) {
 	var posts []models.Post
-	if err := ps.db.Where("title LIKE ? OR text LIKE ?",
+	if err := ps.db.Scopes(publiclyVisible).Where("(title LIKE ? OR text LIKE ?)",
 		"%"+keyword+"%", "%"+keyword+"%").
 		Order("postDate DESC").
 		Find(&posts).Error; err != nil {
@@ -201,7 +345,7 @@ func (ps *PostService) SearchPostsByKeyword(keyword string) ([]models.Post, erro
 // SearchPostsByGenre ジャンルで検索
 func (ps *PostService) SearchPostsByGenre(genreID int32) ([]models.Post, error) {
 	var posts []models.Post
-	if err := ps.db.Where("genreId = ?", genreID).
+	if err := ps.db.Scopes(publiclyVisible).Where("genreId = ?", genreID).
 		Order("postDate DESC").
 		Find(&posts).Error; err != nil {
 		return nil, err
@@ -212,7 +356,7 @@ func (ps *PostService) SearchPostsByGenre(genreID int32) ([]models.Post, error)
 // SearchPostsByPeriod 期間で検索
 func (ps *PostService) SearchPostsByPeriod(startDate, endDate time.Time) ([]models.Post, error) {
 	var posts []models.Post
-	if err := ps.db.Where("postDate BETWEEN ? AND ?",
+	if err := ps.db.Scopes(publiclyVisible).Where("postDate BETWEEN ? AND ?",
 		startDate, endDate).
 		Order("postDate DESC").
 		Find(&posts).Error; err != nil {
@@ -229,7 +373,7 @@ func (ps *PostService) DeletePost(postID int32, userID string) error {
 
 	var post models.Post
 	if err := ps.db.Where("postId = ?", postID).First(&post).Error; err != nil {
-		return errors.New("post not found")
+		return ErrPostNotFound
 	}
 
 	// 投稿者本人かチェック
@@ -261,7 +405,7 @@ func (ps *PostService) GetUserReactionHistory(userID string) ([]map[string]inter
 		Joins("INNER JOIN post ON post.postId = reaction.postId").
 		Joins("LEFT JOIN genre ON genre.genreId = post.genreId").
 		Joins("LEFT JOIN place ON place.placeId = post.placeId").
-		Where("reaction.userId = ?", userID).
+		Where("reaction.userId = ? AND post.status = ? AND post.moderationStatus = ?", userID, shared.PostStatusPublished, shared.ModerationApproved).
 		Order("reaction.createdAt DESC").
 		Scan(&results).Error
 
@@ -273,7 +417,7 @@ func (ps *PostService) GetUserReactionHistory(userID string) ([]map[string]inter
 	response := make([]map[string]interface{}, len(results))
 	for i, r := range results {
 		response[i] = map[string]interface{}{
-			"postId":      r.ID,
+			"postId":      r.PostID,
 			"placeId":     r.PlaceID,
 			"genreId":     r.GenreID,
 			"userId":      r.UserID,
@@ -317,6 +461,7 @@ func (ps *PostService) GetPinSizes(placeIDs []int32) (map[int32]float64, error)
 	var counts []CountResult
 	if err := ps.db.Model(&models.Post{}).
 		Select("placeId, COUNT(*) as count").
+		Scopes(publiclyVisible).
 		Where("placeId IN ?", placeIDs).
 		Group("placeId").
 		Scan(&counts).Error; err != nil {
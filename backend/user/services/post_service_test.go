@@ -3,6 +3,7 @@ package services
 import (
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
+	shared "kojan-map/business/pkg/models"
 	"kojan-map/user/models"
 	"testing"
 	"time"
@@ -12,12 +13,22 @@ import (
 func cleanupDB(db *gorm.DB) {
 	db.Exec("SET FOREIGN_KEY_CHECKS = 0;")
 	db.Exec("TRUNCATE TABLE report;")
+	db.Exec("TRUNCATE TABLE ask_message;")
+	db.Exec("TRUNCATE TABLE ask;")
+	db.Exec("TRUNCATE TABLE businessReq_history;")
+	db.Exec("TRUNCATE TABLE businessReq_document;")
+	db.Exec("TRUNCATE TABLE businessReq;")
+	db.Exec("TRUNCATE TABLE business;")
 	db.Exec("TRUNCATE TABLE block;")
 	db.Exec("TRUNCATE TABLE reaction;")
 	db.Exec("TRUNCATE TABLE post;")
 	db.Exec("TRUNCATE TABLE place;")
 	db.Exec("TRUNCATE TABLE genre;")
 	db.Exec("TRUNCATE TABLE user;")
+	db.Exec("TRUNCATE TABLE refresh_token;")
+	db.Exec("TRUNCATE TABLE sessions;")
+	db.Exec("TRUNCATE TABLE sign_in_history;")
+	db.Exec("TRUNCATE TABLE user_identities;")
 	db.Exec("SET FOREIGN_KEY_CHECKS = 1;")
 }
 
@@ -28,7 +39,7 @@ func TestPostService_CreatePost(t *testing.T) {
 	postService := NewPostService(db)
 
 	// テスト用ジャンル・場所をセットアップ
-	genre := models.Genre{GenreName: "グルメ"}
+	genre := models.Genre{GenreName: "food"}
 	db.Create(&genre)
 
 	place := models.Place{Latitude: 35.6762, Longitude: 139.6503, NumPost: 0}
@@ -41,7 +52,7 @@ func TestPostService_CreatePost(t *testing.T) {
 		PostImage:   nil,
 		NumView:     0,
 		NumReaction: 0,
-		PlaceID:     place.ID,
+		PlaceID:     place.PlaceID,
 		GenreID:     genre.GenreID,
 		PostDate:    time.Now(),
 	}
@@ -100,12 +111,12 @@ func TestPostService_GetPostDetail(t *testing.T) {
 	db.First(&testPost)
 
 	// 詳細取得（閲覧数カウント）
-	post, err := postService.GetPostDetail(testPost.ID)
+	post, err := postService.GetPostDetail(testPost.PostID, "")
 	assert.NoError(t, err)
 	assert.NotNil(t, post)
 
 	// レスポンス形式確認
-	assert.Equal(t, testPost.ID, post["postId"])
+	assert.Equal(t, testPost.PostID, post["postId"])
 	assert.Equal(t, testPost.UserID, post["userId"])
 	assert.Equal(t, testPost.Title, post["title"])
 	assert.Equal(t, testPost.Text, post["text"])
@@ -118,7 +129,7 @@ func TestPostService_GetPostDetail(t *testing.T) {
 
 	// 閲覧数が増加したか確認
 	var updatedPost models.Post
-	db.First(&updatedPost, testPost.ID)
+	db.First(&updatedPost, testPost.PostID)
 	assert.Equal(t, testPost.NumView+1, updatedPost.NumView)
 }
 
@@ -130,7 +141,7 @@ func TestPostService_GetPostDetail_NotFound(t *testing.T) {
 
 	setupTestPostData(db)
 
-	post, err := postService.GetPostDetail(99999)
+	post, err := postService.GetPostDetail(99999, "")
 	assert.Error(t, err)
 	assert.Nil(t, post)
 }
@@ -178,11 +189,11 @@ func TestPostService_AddReaction(t *testing.T) {
 	db.First(&testPost)
 
 	// リアクション追加
-	err := postService.AddReaction("user123", testPost.ID)
+	err := postService.AddReaction("user123", testPost.PostID)
 	assert.NoError(t, err)
 
 	// リアクション確認
-	reacted, err := postService.IsUserReacted("user123", testPost.ID)
+	reacted, err := postService.IsUserReacted("user123", testPost.PostID)
 	assert.NoError(t, err)
 	assert.True(t, reacted)
 }
@@ -198,16 +209,16 @@ func TestPostService_IsUserReacted(t *testing.T) {
 	db.First(&testPost)
 
 	// リアクションなし
-	reacted, err := postService.IsUserReacted("user999", testPost.ID)
+	reacted, err := postService.IsUserReacted("user999", testPost.PostID)
 	assert.NoError(t, err)
 	assert.False(t, reacted)
 
 	// リアクション追加
-	err = postService.AddReaction("user999", testPost.ID)
+	err = postService.AddReaction("user999", testPost.PostID)
 	assert.NoError(t, err)
 
 	// リアクション確認
-	reacted, err = postService.IsUserReacted("user999", testPost.ID)
+	reacted, err = postService.IsUserReacted("user999", testPost.PostID)
 	assert.NoError(t, err)
 	assert.True(t, reacted)
 }
@@ -221,7 +232,7 @@ func TestPostService_DeletePost(t *testing.T) {
 
 	var testPost models.Post
 	db.First(&testPost)
-	postID := testPost.ID
+	postID := testPost.PostID
 	userID := testPost.UserID
 
 	// 所有者による削除
@@ -245,12 +256,12 @@ func TestPostService_DeletePost_Unauthorized(t *testing.T) {
 	db.First(&testPost)
 
 	// 異なるユーザーが削除を試みる
-	err := postService.DeletePost(testPost.ID, "unauthorized_user")
+	err := postService.DeletePost(testPost.PostID, "unauthorized_user")
 	assert.Error(t, err)
 
 	// 投稿がまだ存在することを確認
 	var stillExistsPost models.Post
-	errResult := db.First(&stillExistsPost, testPost.ID).Error
+	errResult := db.First(&stillExistsPost, testPost.PostID).Error
 	assert.NoError(t, errResult)
 }
 
@@ -286,7 +297,7 @@ func TestPostService_ResponseFieldMapping(t *testing.T) {
 
 		// Post テーブルのフィールド確認
 		requiredFields := []string{
-			"postId",      // Post.ID
+			"postId",      // Post.PostID
 			"userId",      // Post.UserID
 			"title",       // Post.Title
 			"text",        // Post.Text
@@ -316,9 +327,9 @@ func TestPostService_ResponseFieldMapping(t *testing.T) {
 func setupTestPostData(db *gorm.DB) {
 	// ジャンル作成
 	genres := []models.Genre{
-		{GenreName: "グルメ"},
-		{GenreName: "イベント"},
-		{GenreName: "景色"},
+		{GenreName: "food"},
+		{GenreName: "event"},
+		{GenreName: "scene"},
 	}
 	for _, genre := range genres {
 		db.Create(&genre)
@@ -393,7 +404,7 @@ func TestPostService_GetPostTimestamp(t *testing.T) {
 	if err := db.Create(&place).Error; err != nil {
 		t.Fatalf("Failed to create test place: %v", err)
 	}
-	genre := models.Genre{GenreName: "テストジャンル"}
+	genre := models.Genre{GenreName: "other"}
 	if err := db.Create(&genre).Error; err != nil {
 		t.Fatalf("Failed to create test genre: %v", err)
 	}
@@ -404,7 +415,7 @@ func TestPostService_GetPostTimestamp(t *testing.T) {
 		UserID:   "user123",
 		Title:    "Test Post",
 		Text:     "Test Content",
-		PlaceID:  place.ID,
+		PlaceID:  place.PlaceID,
 		GenreID:  genre.GenreID,
 		PostDate: testTime,
 	}
@@ -413,7 +424,7 @@ func TestPostService_GetPostTimestamp(t *testing.T) {
 	}
 
 	// 投稿日時を取得
-	detail, err := postService.GetPostDetail(testPost.ID)
+	detail, err := postService.GetPostDetail(testPost.PostID, "")
 	assert.NoError(t, err)
 	assert.NotNil(t, detail)
 
@@ -430,7 +441,7 @@ func TestPostService_GetReactionList(t *testing.T) {
 	// 必要なユーザー・ジャンル・場所を作成
 	user := models.User{GoogleID: "user123", Gmail: "user123@example.com", Role: "user", RegistrationDate: time.Now()}
 	db.Create(&user)
-	genre := models.Genre{GenreName: "テストジャンル"}
+	genre := models.Genre{GenreName: "other"}
 	db.Create(&genre)
 	place := models.Place{Latitude: 35.0, Longitude: 135.0, NumPost: 0}
 	db.Create(&place)
@@ -440,7 +451,7 @@ func TestPostService_GetReactionList(t *testing.T) {
 		UserID:   "user123",
 		Title:    "Test Post",
 		Text:     "Test Content",
-		PlaceID:  place.ID,
+		PlaceID:  place.PlaceID,
 		GenreID:  genre.GenreID,
 		PostDate: time.Now(),
 	}
@@ -449,11 +460,11 @@ func TestPostService_GetReactionList(t *testing.T) {
 	}
 
 	// ユーザーがリアクションを追加
-	err := postService.AddReaction("user123", testPost.ID)
+	err := postService.AddReaction("user123", testPost.PostID)
 	assert.NoError(t, err)
 
 	// リアクション履歴を確認
-	isReacted, err := postService.IsUserReacted("user123", testPost.ID)
+	isReacted, err := postService.IsUserReacted("user123", testPost.PostID)
 	assert.NoError(t, err)
 	assert.True(t, isReacted)
 
@@ -462,3 +473,135 @@ func TestPostService_GetReactionList(t *testing.T) {
 	db.Where("userId = ?", "user123").Find(&reactions)
 	assert.Greater(t, len(reactions), 0)
 }
+
+// TestPostService_CreatePost_DraftAndScheduled - 下書き・予約投稿は公開一覧に含まれない
+func TestPostService_CreatePost_DraftAndScheduled(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	postService := NewPostService(db)
+	setupTestPostData(db)
+
+	draft := &models.Post{UserID: "user123", Title: "下書き", Text: "下書きの内容", PlaceID: 1, GenreID: 1, Status: shared.PostStatusDraft}
+	assert.NoError(t, postService.CreatePost(draft))
+	assert.Equal(t, shared.PostStatusDraft, draft.Status)
+
+	publishAt := time.Now().Add(time.Hour)
+	scheduled := &models.Post{UserID: "user123", Title: "予約", Text: "予約の内容", PlaceID: 1, GenreID: 1, Status: shared.PostStatusScheduled, PublishAt: &publishAt}
+	assert.NoError(t, postService.CreatePost(scheduled))
+
+	posts, err := postService.GetAllPosts()
+	assert.NoError(t, err)
+	for _, p := range posts {
+		assert.NotEqual(t, draft.PostID, p["postId"])
+		assert.NotEqual(t, scheduled.PostID, p["postId"])
+	}
+
+	_, err = postService.GetPostDetail(draft.PostID, "")
+	assert.Error(t, err)
+	_, err = postService.GetPostDetail(scheduled.PostID, "")
+	assert.ErrorIs(t, err, ErrPostNotFound)
+	_, err = postService.GetPostDetail(draft.PostID, "other-user")
+	assert.ErrorIs(t, err, ErrPostNotFound)
+
+	// 投稿者本人は下書きの詳細を確認できる（閲覧数は増えない）
+	detail, err := postService.GetPostDetail(draft.PostID, "user123")
+	assert.NoError(t, err)
+	assert.Equal(t, shared.PostStatusDraft, detail["status"])
+	assert.Equal(t, int32(0), detail["numView"])
+
+	// 投稿者本人の履歴には含まれる
+	history, err := postService.GetUserPostHistory("user123")
+	assert.NoError(t, err)
+	statuses := map[int32]string{}
+	for _, p := range history {
+		statuses[p.PostID] = p.Status
+	}
+	assert.Equal(t, shared.PostStatusDraft, statuses[draft.PostID])
+	assert.Equal(t, shared.PostStatusScheduled, statuses[scheduled.PostID])
+}
+
+// TestPostService_CreatePost_InvalidSchedule - 予約日時の検証
+func TestPostService_CreatePost_InvalidSchedule(t *testing.T) {
+	db := setupTestDB(t)
+	postService := NewPostService(db)
+
+	past := time.Now().Add(-time.Hour)
+	err := postService.CreatePost(&models.Post{UserID: "user123", Title: "予約", Text: "内容", Status: shared.PostStatusScheduled, PublishAt: &past})
+	assert.ErrorIs(t, err, ErrPublishAtNotFuture)
+
+	err = postService.CreatePost(&models.Post{UserID: "user123", Title: "予約", Text: "内容", Status: shared.PostStatusScheduled})
+	assert.ErrorIs(t, err, ErrPublishAtRequired)
+
+	err = postService.CreatePost(&models.Post{UserID: "user123", Title: "予約", Text: "内容", Status: "hidden"})
+	assert.ErrorIs(t, err, ErrInvalidPostStatus)
+}
+
+// TestPostScheduler_PublishDuePosts - 公開日時を過ぎた予約投稿が一度だけ公開される
+func TestPostScheduler_PublishDuePosts(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	postService := NewPostService(db)
+	setupTestPostData(db)
+
+	publishAt := time.Now().Add(time.Hour)
+	scheduled := &models.Post{UserID: "user123", Title: "予約", Text: "予約の内容", PlaceID: 1, GenreID: 1, Status: shared.PostStatusScheduled, PublishAt: &publishAt}
+	assert.NoError(t, postService.CreatePost(scheduled))
+
+	scheduler := NewPostScheduler(db, time.Minute)
+
+	// 公開日時前は公開されない
+	n, err := scheduler.PublishDuePosts(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	n, err = scheduler.PublishDuePosts(publishAt.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// 二重に公開されない
+	n, err = scheduler.PublishDuePosts(publishAt.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	detail, err := postService.GetPostDetail(scheduled.PostID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, scheduled.PostID, detail["postId"])
+}
+
+// TestPostService_CreatePost_NewAccountPending - 新規アカウントの投稿は審査待ちで非公開
+func TestPostService_CreatePost_NewAccountPending(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	postService := NewPostService(db)
+	postService.SetModeration(NewModerationService(db, ModerationPolicy{NewAccountReviewPeriod: 24 * time.Hour}))
+	setupTestPostData(db)
+
+	post := &models.Post{UserID: "user123", Title: "新規", Text: "新規アカウントの投稿", PlaceID: 1, GenreID: 1}
+	assert.NoError(t, postService.CreatePost(post))
+	assert.Equal(t, shared.ModerationPending, post.ModerationStatus)
+
+	_, err := postService.GetPostDetail(post.PostID, "")
+	assert.Error(t, err)
+}
+
+// TestReportService_CreateReport_AutoHide - 異なる通報者数が閾値に達すると自動非表示
+func TestReportService_CreateReport_AutoHide(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	setupTestPostData(db)
+	reportService := NewReportService(db)
+	reportService.SetModeration(NewModerationService(db, ModerationPolicy{AutoHideReportThreshold: 2, AutoHideReportWindow: time.Hour}))
+
+	var post models.Post
+	db.First(&post)
+
+	// 同一ユーザーの重複通報は受け付けない
+	assert.NoError(t, reportService.CreateReport("user123", post.PostID, "spam", ""))
+	assert.ErrorIs(t, reportService.CreateReport("user123", post.PostID, "spam", ""), ErrDuplicateReport)
+	db.First(&post, post.PostID)
+	assert.Equal(t, shared.ModerationApproved, post.ModerationStatus)
+
+	assert.NoError(t, reportService.CreateReport("user456", post.PostID, "spam", ""))
+	db.First(&post, post.PostID)
+	assert.Equal(t, shared.ModerationHidden, post.ModerationStatus)
+}
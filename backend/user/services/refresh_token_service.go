@@ -0,0 +1,165 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"kojan-map/user/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// リフレッシュトークンのエラー
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+// RefreshTokenService リフレッシュトークンの発行・ローテーション・失効
+type RefreshTokenService struct {
+	db  *gorm.DB
+	ttl time.Duration
+	now func() time.Time
+}
+
+// defaultRefreshTokenTTL リフレッシュトークンの既定の有効期間
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// NewRefreshTokenService ttl はトークン1つあたりの有効期間（ローテーションのたびに更新、0以下は既定値）
+func NewRefreshTokenService(db *gorm.DB, ttl time.Duration) *RefreshTokenService {
+	if ttl <= 0 {
+		ttl = defaultRefreshTokenTTL
+	}
+	return &RefreshTokenService{db: db, ttl: ttl, now: time.Now}
+}
+
+// Issue ログイン時に新しいファミリーのリフレッシュトークンを発行
+// sessionID のセッションが失効すると、そのファミリーも失効する
+func (s *RefreshTokenService) Issue(googleID, sessionID string) (string, error) {
+	if googleID == "" {
+		return "", errors.New("googleID is required")
+	}
+	token, _, err := s.create(s.db, googleID, sessionID, uuid.New().String())
+	return token, err
+}
+
+// Rotate リフレッシュトークンを使用済みにし、同じファミリーの新しいトークンを発行
+// 使用済みのトークンが再提示された場合はファミリー全体を失効させる
+func (s *RefreshTokenService) Rotate(token string) (issued *models.RefreshToken, newToken string, err error) {
+	now := s.now()
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Where("tokenHash = ?", hashRefreshToken(token)).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+
+		if current.RevokedAt != nil {
+			return ErrInvalidRefreshToken
+		}
+		if current.UsedAt != nil {
+			return ErrRefreshTokenReused
+		}
+		if !now.Before(current.ExpiresAt) {
+			return ErrRefreshTokenExpired
+		}
+
+		// 同時に使用された場合は一方のみ成功させる
+		result := tx.Model(&models.RefreshToken{}).
+			Where("tokenId = ? AND usedAt IS NULL", current.TokenID).
+			Update("usedAt", now)
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark refresh token as used: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		newToken, issued, err = s.create(tx, current.GoogleID, current.SessionID, current.FamilyID)
+		return err
+	})
+
+	if errors.Is(err, ErrRefreshTokenReused) {
+		// 漏洩の可能性があるため、正規の利用者のトークンも含めて失効させる
+		if revokeErr := s.revokeFamilyByHash(hashRefreshToken(token)); revokeErr != nil {
+			return nil, "", revokeErr
+		}
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return issued, newToken, nil
+}
+
+// RevokeFamily トークンが属するファミリーを失効（ログアウト）
+// トークンが他のユーザーのものである場合は何もしない
+func (s *RefreshTokenService) RevokeFamily(googleID, token string) error {
+	var current models.RefreshToken
+	if err := s.db.Where("tokenHash = ? AND googleId = ?", hashRefreshToken(token), googleID).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return s.revokeWhere(s.db, "familyId = ?", current.FamilyID)
+}
+
+// RevokeAll ユーザーのすべてのリフレッシュトークンを失効
+func (s *RefreshTokenService) RevokeAll(googleID string) error {
+	return s.revokeWhere(s.db, "googleId = ?", googleID)
+}
+
+func (s *RefreshTokenService) revokeFamilyByHash(hash string) error {
+	var current models.RefreshToken
+	if err := s.db.Where("tokenHash = ?", hash).First(&current).Error; err != nil {
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return s.revokeWhere(s.db, "familyId = ?", current.FamilyID)
+}
+
+func (s *RefreshTokenService) revokeWhere(db *gorm.DB, query string, arg string) error {
+	if err := db.Model(&models.RefreshToken{}).
+		Where(query, arg).
+		Where("revokedAt IS NULL").
+		Update("revokedAt", s.now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+func (s *RefreshTokenService) create(db *gorm.DB, googleID, sessionID, familyID string) (string, *models.RefreshToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	record := models.RefreshToken{
+		TokenID:   uuid.New().String(),
+		FamilyID:  familyID,
+		GoogleID:  googleID,
+		SessionID: sessionID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: s.now().Add(s.ttl),
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return "", nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return token, &record, nil
+}
+
+// hashRefreshToken トークンはランダムな256bitのため、ソルトなしのSHA-256で十分
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
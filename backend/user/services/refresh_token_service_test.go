@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"kojan-map/user/models"
+)
+
+func TestRefreshTokenService_Rotate(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewRefreshTokenService(db, time.Hour)
+
+	token, err := service.Issue("google123", "session-1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	// 平文のトークンは保存されない
+	var count int64
+	db.Model(&models.RefreshToken{}).Where("tokenHash = ?", token).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	issued, next, err := service.Rotate(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "google123", issued.GoogleID)
+	assert.Equal(t, "session-1", issued.SessionID)
+	assert.NotEqual(t, token, next)
+
+	// 新しいトークンでさらにローテーションできる
+	_, _, err = service.Rotate(next)
+	assert.NoError(t, err)
+}
+
+func TestRefreshTokenService_Rotate_ReuseRevokesFamily(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewRefreshTokenService(db, time.Hour)
+
+	token, _ := service.Issue("google123", "session-1")
+	_, next, err := service.Rotate(token)
+	assert.NoError(t, err)
+
+	// 使用済みトークンの再利用
+	_, _, err = service.Rotate(token)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	// 正規の利用者が持つ最新のトークンも失効している
+	_, _, err = service.Rotate(next)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestRefreshTokenService_Rotate_Expired(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewRefreshTokenService(db, time.Hour)
+
+	token, _ := service.Issue("google123", "session-1")
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	_, _, err := service.Rotate(token)
+	assert.ErrorIs(t, err, ErrRefreshTokenExpired)
+}
+
+func TestRefreshTokenService_RevokeFamily(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewRefreshTokenService(db, time.Hour)
+
+	loggedOut, _ := service.Issue("google123", "session-1")
+	otherDevice, _ := service.Issue("google123", "session-1")
+
+	// 他人のトークンは失効できない
+	assert.NoError(t, service.RevokeFamily("google999", loggedOut))
+	_, loggedOut, err := service.Rotate(loggedOut)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.RevokeFamily("google123", loggedOut))
+	_, _, err = service.Rotate(loggedOut)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	// 別のログインのトークンは有効なまま
+	_, _, err = service.Rotate(otherDevice)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.RevokeAll("google123"))
+	var active int64
+	db.Model(&models.RefreshToken{}).Where("googleId = ? AND revokedAt IS NULL", "google123").Count(&active)
+	assert.Equal(t, int64(0), active)
+}
+
+func TestRefreshTokenService_Rotate_Unknown(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewRefreshTokenService(db, time.Hour)
+
+	_, _, err := service.Rotate("unknown-token")
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"kojan-map/user/models"
+
+	"gorm.io/gorm"
+)
+
+// セッションのエラー
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session has been revoked")
+)
+
+// lastSeenInterval 最終アクセス時刻を更新する最小間隔（リクエストごとの書き込みを避ける）
+const lastSeenInterval = time.Minute
+
+// SessionService ログイン中の端末（セッション）の管理
+type SessionService struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+func NewSessionService(db *gorm.DB) *SessionService {
+	return &SessionService{db: db, now: time.Now}
+}
+
+// ListSessions 有効なセッションを最終アクセスの新しい順に取得
+func (s *SessionService) ListSessions(googleID string) ([]models.Session, error) {
+	if googleID == "" {
+		return nil, errors.New("googleID is required")
+	}
+
+	var sessions []models.Session
+	if err := s.db.
+		Where("googleId = ? AND expiry > ? AND revokedAt IS NULL", googleID, s.now()).
+		Order("COALESCE(lastSeenAt, createdAt) DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession 自分のセッションを失効させる（紐づくリフレッシュトークンも失効）
+func (s *SessionService) RevokeSession(googleID, sessionID string) error {
+	if googleID == "" || sessionID == "" {
+		return errors.New("googleID and sessionID are required")
+	}
+
+	now := s.now()
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Session{}).
+			Where("sessionId = ? AND googleId = ? AND revokedAt IS NULL", sessionID, googleID).
+			Updates(map[string]interface{}{"revokedAt": now, "expiry": now})
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke session: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrSessionNotFound
+		}
+
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("sessionId = ? AND revokedAt IS NULL", sessionID).
+			Update("revokedAt", now).Error; err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
+}
+
+// ValidateSession セッションが有効か確認し、最終アクセス時刻を更新
+// 認証ミドルウェアから呼び出される
+func (s *SessionService) ValidateSession(sessionID string) error {
+	var session models.Session
+	if err := s.db.Where("sessionId = ?", sessionID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	now := s.now()
+	if session.RevokedAt != nil || !now.Before(session.Expiry) {
+		return ErrSessionRevoked
+	}
+
+	if session.LastSeenAt == nil || now.Sub(*session.LastSeenAt) >= lastSeenInterval {
+		if err := s.db.Model(&models.Session{}).
+			Where("sessionId = ?", sessionID).
+			Update("lastSeenAt", now).Error; err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+	}
+	return nil
+}
+
+// ExtendSession セッションの有効期限を延長（リフレッシュ時）
+func (s *SessionService) ExtendSession(sessionID string, ttl time.Duration) error {
+	now := s.now()
+	if err := s.db.Model(&models.Session{}).
+		Where("sessionId = ? AND revokedAt IS NULL AND expiry > ?", sessionID, now).
+		Updates(map[string]interface{}{"expiry": now.Add(ttl), "lastSeenAt": now}).Error; err != nil {
+		return fmt.Errorf("failed to extend session: %w", err)
+	}
+	return nil
+}
+
+// ListSignIns ログイン履歴を新しい順に取得
+func (s *SessionService) ListSignIns(googleID string, limit int) ([]models.SignInHistory, error) {
+	if googleID == "" {
+		return nil, errors.New("googleID is required")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var history []models.SignInHistory
+	if err := s.db.
+		Where("googleId = ?", googleID).
+		Order("createdAt DESC, id DESC").
+		Limit(limit).
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sign-in history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"kojan-map/user/models"
+)
+
+func TestUserService_RegisterOrLoginFromDevice_NewDevice(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	service := NewUserService(db)
+
+	laptop := models.DeviceInfo{UserAgent: "Mozilla/5.0 (Macintosh)", IPAddress: "203.0.113.1"}
+	phone := models.DeviceInfo{UserAgent: "Mozilla/5.0 (iPhone)", IPAddress: "198.51.100.2"}
+
+	// 初回ログインは新しい端末として扱わない
+	first, signIn, err := service.RegisterOrLoginFromDevice("google123", "test@example.com", "user", laptop)
+	assert.NoError(t, err)
+	assert.False(t, signIn.NewDevice)
+	assert.Equal(t, laptop.UserAgent, first.UserAgent)
+	assert.Equal(t, laptop.IPAddress, first.IPAddress)
+
+	// 同じ端末からの再ログインはセッションを再利用
+	again, signIn, err := service.RegisterOrLoginFromDevice("google123", "test@example.com", "user", laptop)
+	assert.NoError(t, err)
+	assert.False(t, signIn.NewDevice)
+	assert.Equal(t, first.SessionID, again.SessionID)
+
+	// 別の端末は新しいセッション
+	other, signIn, err := service.RegisterOrLoginFromDevice("google123", "test@example.com", "user", phone)
+	assert.NoError(t, err)
+	assert.True(t, signIn.NewDevice)
+	assert.NotEqual(t, first.SessionID, other.SessionID)
+
+	sessions, err := NewSessionService(db).ListSessions("google123")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	userService := NewUserService(db)
+	sessionService := NewSessionService(db)
+	refreshService := NewRefreshTokenService(db, time.Hour)
+
+	session, _, err := userService.RegisterOrLoginFromDevice("google123", "test@example.com", "user", models.DeviceInfo{UserAgent: "ua"})
+	assert.NoError(t, err)
+	refreshToken, err := refreshService.Issue("google123", session.SessionID)
+	assert.NoError(t, err)
+	assert.NoError(t, sessionService.ValidateSession(session.SessionID))
+
+	// 他人のセッションは失効できない
+	assert.ErrorIs(t, sessionService.RevokeSession("google999", session.SessionID), ErrSessionNotFound)
+
+	assert.NoError(t, sessionService.RevokeSession("google123", session.SessionID))
+	assert.ErrorIs(t, sessionService.ValidateSession(session.SessionID), ErrSessionRevoked)
+
+	// セッションのリフレッシュトークンも使えない
+	_, _, err = refreshService.Rotate(refreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	sessions, err := sessionService.ListSessions("google123")
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestSessionService_ListSignIns(t *testing.T) {
+	db := setupTestDB(t)
+	cleanupDB(db)
+	userService := NewUserService(db)
+
+	_, _, _ = userService.RegisterOrLoginFromDevice("google123", "test@example.com", "user", models.DeviceInfo{UserAgent: "a"})
+	_, _, _ = userService.RegisterOrLoginFromDevice("google123", "test@example.com", "user", models.DeviceInfo{UserAgent: "b"})
+
+	history, err := NewSessionService(db).ListSignIns("google123", 0)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "b", history[0].UserAgent)
+	assert.True(t, history[0].NewDevice)
+}
@@ -5,7 +5,8 @@ import (
 	"fmt"
 	"time"
 
-	shared "kojan-map/shared/models"
+	shared "kojan-map/business/pkg/models"
+	"kojan-map/business/pkg/oauth"
 	"kojan-map/user/models"
 
 	"github.com/google/uuid"
@@ -14,14 +15,32 @@ import (
 
 // UserService ユーザー関連のビジネスロジック
 type UserService struct {
-	db *gorm.DB
+	db         *gorm.DB
+	sessionTTL time.Duration
 }
 
+// defaultSessionTTL ログインセッションの既定の有効期間
+const defaultSessionTTL = 24 * time.Hour
+
 func NewUserService(db *gorm.DB) *UserService {
-	return &UserService{db: db}
+	return &UserService{db: db, sessionTTL: defaultSessionTTL}
+}
+
+// SetSessionTTL ログインセッションの有効期間を設定（0以下は無視）
+// リフレッシュトークンで再発行を続ける間、セッションも同じ期間延長される
+func (us *UserService) SetSessionTTL(ttl time.Duration) {
+	if ttl > 0 {
+		us.sessionTTL = ttl
+	}
+}
+
+// SessionTTL ログインセッションの有効期間
+func (us *UserService) SessionTTL() time.Duration {
+	return us.sessionTTL
 }
 
 // CreateTestUser テスト用ユーザーを直接登録
+// googleID を sub とする Google アカウントを連携するため、同じ sub のIDトークンでログインできる
 func (us *UserService) CreateTestUser(googleID, gmail, role string) error {
 	if googleID == "" || gmail == "" || role == "" {
 		return errors.New("all fields are required")
@@ -32,16 +51,35 @@ func (us *UserService) CreateTestUser(googleID, gmail, role string) error {
 		Role:             shared.Role(role),
 		RegistrationDate: time.Now(),
 	}
-	return us.db.Create(&user).Error
+	return us.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.UserIdentity{
+			GoogleID: googleID,
+			Provider: oauth.ProviderGoogle,
+			Issuer:   oauth.GoogleIssuer,
+			Subject:  googleID,
+			Email:    gmail,
+		}).Error
+	})
 }
 
 // RegisterOrLogin Google認証でユーザーを登録またはログイン（role指定対応）
 func (us *UserService) RegisterOrLogin(googleID, email, role string) (*models.Session, error) {
+	session, _, err := us.RegisterOrLoginFromDevice(googleID, email, role, models.DeviceInfo{})
+	return session, err
+}
+
+// RegisterOrLoginFromDevice 端末情報を記録してログイン
+// 同じ端末（User-Agent）の有効なセッションがあれば延長し、なければ新しいセッションを作成する
+// ログイン履歴を残し、これまでに使われていない端末からのログインには NewDevice を立てる
+func (us *UserService) RegisterOrLoginFromDevice(googleID, email, role string, device models.DeviceInfo) (*models.Session, *models.SignInHistory, error) {
 	if googleID == "" {
-		return nil, errors.New("googleID is required")
+		return nil, nil, errors.New("googleID is required")
 	}
 	if email == "" {
-		return nil, errors.New("email is required")
+		return nil, nil, errors.New("email is required")
 	}
 	if role == "" {
 		role = string(shared.RoleUser)
@@ -50,7 +88,7 @@ func (us *UserService) RegisterOrLogin(googleID, email, role string) (*models.Se
 	// Validate Role
 	r := shared.Role(role)
 	if r != shared.RoleUser && r != shared.RoleBusiness && r != shared.RoleAdmin {
-		return nil, errors.New("invalid role: must be user, business, or admin")
+		return nil, nil, errors.New("invalid role: must be user, business, or admin")
 	}
 
 	var user models.User
@@ -60,7 +98,7 @@ func (us *UserService) RegisterOrLogin(googleID, email, role string) (*models.Se
 
 	if result.Error != nil {
 		if result.Error != gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("failed to check user: %w", result.Error)
+			return nil, nil, fmt.Errorf("failed to check user: %w", result.Error)
 		}
 		// 新規ユーザーの登録
 		user = models.User{
@@ -70,33 +108,95 @@ func (us *UserService) RegisterOrLogin(googleID, email, role string) (*models.Se
 			RegistrationDate: time.Now(),
 		}
 		if err := us.db.Create(&user).Error; err != nil {
-			return nil, fmt.Errorf("failed to create user: %w", err)
+			return nil, nil, fmt.Errorf("failed to create user: %w", err)
 		}
 	}
 
-	// 既存の有効なセッションを確認
-	var existingSession models.Session
-	if err := us.db.Where("googleId = ? AND expiry > ?", user.GoogleID, time.Now()).First(&existingSession).Error; err == nil {
+	return us.startSession(&user, device)
+}
+
+// LoginFromDevice 登録済みのユーザーのセッションを端末情報とともに発行する
+// 外部アカウント（IdentityService.ResolveUser）で特定したユーザーのログインに使用する
+func (us *UserService) LoginFromDevice(googleID string, device models.DeviceInfo) (*models.Session, *models.SignInHistory, error) {
+	if googleID == "" {
+		return nil, nil, errors.New("googleID is required")
+	}
+
+	var user models.User
+	if err := us.db.Where("googleId = ?", googleID).First(&user).Error; err != nil {
+		return nil, nil, us.handleDBError(err)
+	}
+	return us.startSession(&user, device)
+}
+
+// startSession 同じ端末の有効なセッションを延長または新規作成し、ログイン履歴を記録する
+func (us *UserService) startSession(user *models.User, device models.DeviceInfo) (*models.Session, *models.SignInHistory, error) {
+	device.UserAgent = truncate(device.UserAgent, 255)
+	device.IPAddress = truncate(device.IPAddress, 45)
+
+	// 新しい端末か判定（初回ログインは対象外）
+	var signIns, sameDevice int64
+	if err := us.db.Model(&models.SignInHistory{}).Where("googleId = ?", user.GoogleID).Count(&signIns).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to check sign-in history: %w", err)
+	}
+	if err := us.db.Model(&models.SignInHistory{}).Where("googleId = ? AND userAgent = ?", user.GoogleID, device.UserAgent).Count(&sameDevice).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to check sign-in history: %w", err)
+	}
+
+	now := time.Now()
+	var session models.Session
+
+	// 同じ端末の有効なセッションを確認
+	err := us.db.Where("googleId = ? AND userAgent = ? AND expiry > ? AND revokedAt IS NULL", user.GoogleID, device.UserAgent, now).
+		Order("expiry DESC").
+		First(&session).Error
+	switch {
+	case err == nil:
 		// 有効なセッションが存在する場合は延長
-		existingSession.Expiry = time.Now().Add(24 * time.Hour)
-		if err := us.db.Save(&existingSession).Error; err != nil {
-			return nil, fmt.Errorf("failed to update session: %w", err)
+		session.Expiry = now.Add(us.sessionTTL)
+		session.IPAddress = device.IPAddress
+		session.LastSeenAt = &now
+		if err := us.db.Save(&session).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to update session: %w", err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// 新しいセッションIDを生成
+		session = models.Session{
+			SessionID:  uuid.New().String(),
+			GoogleID:   user.GoogleID,
+			Expiry:     now.Add(us.sessionTTL),
+			UserAgent:  device.UserAgent,
+			IPAddress:  device.IPAddress,
+			LastSeenAt: &now,
+		}
+		if err := us.db.Create(&session).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to create session: %w", err)
 		}
-		return &existingSession, nil
+	default:
+		return nil, nil, fmt.Errorf("failed to check session: %w", err)
 	}
 
-	// 新しいセッションIDを生成
-	session := models.Session{
-		SessionID: uuid.New().String(),
+	history := models.SignInHistory{
 		GoogleID:  user.GoogleID,
-		Expiry:    time.Now().Add(24 * time.Hour),
+		SessionID: session.SessionID,
+		UserAgent: device.UserAgent,
+		IPAddress: device.IPAddress,
+		NewDevice: signIns > 0 && sameDevice == 0,
 	}
-
-	if err := us.db.Create(&session).Error; err != nil {
-		return nil, fmt.Errorf("failed to create session: %w", err)
+	if err := us.db.Create(&history).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to record sign-in: %w", err)
 	}
 
-	return &session, nil
+	return &session, &history, nil
+}
+
+// truncate 文字列をカラム長に収まるよう切り詰める
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) > max {
+		return string(r[:max])
+	}
+	return s
 }
 
 // GetUserInfo ユーザー情報を取得
@@ -211,10 +311,10 @@ func (us *UserService) DeleteUser(googleID string) error {
 			return fmt.Errorf("failed to anonymize reactions: %w", err)
 		}
 
-		// 通報のuserIdを匿名化
+		// 通報のuserIdを匿名化（匿名化した通報同士が未対応の通報の一意インデックスで衝突しないよう openFlag を外す）
 		if err := tx.Model(&models.Report{}).
 			Where("userId = ?", googleID).
-			Update("userId", "ANONYMOUS").Error; err != nil {
+			Updates(map[string]interface{}{"userId": "ANONYMOUS", "openFlag": nil}).Error; err != nil {
 			fmt.Printf("[退会エラー] 通報匿名化失敗: %v\n", err)
 			return fmt.Errorf("failed to anonymize reports: %w", err)
 		}
@@ -228,6 +328,20 @@ func (us *UserService) DeleteUser(googleID string) error {
 			return fmt.Errorf("failed to revoke sessions: %w", err)
 		}
 
+		// リフレッシュトークンを失効
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("googleId = ? AND revokedAt IS NULL", googleID).
+			Update("revokedAt", now).Error; err != nil {
+			fmt.Printf("[退会エラー] リフレッシュトークン失効失敗: %v\n", err)
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+
+		// 外部アカウントの連携を解除（同じアカウントで再登録できるように）
+		if err := tx.Where("googleId = ?", googleID).Delete(&models.UserIdentity{}).Error; err != nil {
+			fmt.Printf("[退会エラー] アカウント連携解除失敗: %v\n", err)
+			return fmt.Errorf("failed to unlink identities: %w", err)
+		}
+
 		// ユーザーを物理削除（userIdは変更しない）
 		if err := tx.Delete(&user).Error; err != nil {
 			fmt.Printf("[退会エラー] ユーザー削除失敗: %v\n", err)
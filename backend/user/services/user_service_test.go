@@ -11,7 +11,9 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
-	sharedmodels "kojan-map/shared/models"
+	sharedmodels "kojan-map/business/pkg/models"
+	"kojan-map/migrations"
+	"kojan-map/shared/migrate"
 	"kojan-map/user/config"
 	"kojan-map/user/models"
 )
@@ -23,25 +25,15 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		host = "127.0.0.1"
 	}
 	password := os.Getenv("MYSQL_PASSWORD")
-	dsn := fmt.Sprintf("root:%s@tcp(%s:3306)/kojanmap?charset=utf8mb4&parseTime=True&loc=Local", password, host)
+	// テストは外部キーの確認なしで行う（foreign_key_checks=0 を各接続に設定する）
+	dsn := fmt.Sprintf("root:%s@tcp(%s:3306)/kojanmap?charset=utf8mb4&parseTime=True&loc=Local&foreign_key_checks=0", password, host)
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
 	assert.NoError(t, err)
 
-	// マイグレーション
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.Session{},
-		&models.Genre{},
-		&models.Place{},
-		&models.Post{},
-		&models.UserReaction{},
-		&models.UserBlock{},
-		&models.Report{},
-		&models.Contact{},
-		&models.Contact{},
-		&models.BusinessRequest{},
-		&models.Business{},
-	)
+	// テーブルは本番と同じ migrations の SQL で作成する
+	migrator, err := migrate.New(db, migrations.FS)
+	assert.NoError(t, err)
+	_, err = migrator.Up(0)
 	assert.NoError(t, err)
 
 	config.DB = db
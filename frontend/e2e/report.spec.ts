@@ -98,7 +98,7 @@ test.describe('通報機能 E2Eテスト', () => {
       headers: { Authorization: `Bearer ${reporterJwt}` },
       data: { postId, reason: 'Second report' },
     });
-    expect(reportRes2.status()).toBe(400); // Expect a conflict or bad request
+    expect(reportRes2.status()).toBe(409); // 未対応の通報は1件まで
     const error = await reportRes2.json();
     expect(error.error).toContain('already reported');
   });
@@ -6,7 +6,7 @@ const API_BASE_URL = process.env.API_URL || 'http://backend:8080';
 /**
  * テスト用のユーザーを作成し、JWTトークンとユーザー情報を返す
  * @param request PlaywrightのAPIリクエストコンテキスト
- * @param googleId 作成するユーザーのGoogle ID
+ * @param googleId 作成するユーザーの Google アカウントの sub（内部ユーザーIDはレスポンスの user.googleId）
  * @param role 'user' | 'business' | 'admin'
  */
 export const createUser = async (
@@ -14,9 +14,21 @@ export const createUser = async (
   googleId: string,
   role: 'user' | 'business' | 'admin'
 ) => {
+  // 開発用の OIDC 発行者で ID トークンを発行する（バックエンドは DEV_OIDC_ISSUER=true で起動）
+  const idTokenResponse = await request.post(`${API_BASE_URL}/api/dev/id-token`, {
+    data: {
+      sub: googleId,
+      email: `${googleId}@example.com`,
+      name: `Test User ${googleId}`,
+    },
+  });
+  expect(idTokenResponse.status()).toBe(200);
+  const { id_token } = await idTokenResponse.json();
+
   const response = await request.post(`${API_BASE_URL}/api/auth/exchange-token`, {
     data: {
-      google_token: `${googleId}-token`,
+      provider: 'google',
+      id_token: id_token,
       role: role,
     },
   });
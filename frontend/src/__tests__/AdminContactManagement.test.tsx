@@ -30,7 +30,7 @@ describe('AdminContactManagement', () => {
 
   const mockSetInquiries = vi.fn();
   const mockOnDeleteInquiry = vi.fn();
-  const mockOnApproveInquiry = vi.fn();
+  const mockOnReplyInquiry = vi.fn();
 
   beforeEach(() => {
     vi.clearAllMocks();
@@ -42,7 +42,7 @@ describe('AdminContactManagement', () => {
         inquiries={mockInquiries}
         setInquiries={mockSetInquiries}
         onDeleteInquiry={mockOnDeleteInquiry}
-        onApproveInquiry={mockOnApproveInquiry}
+        onReplyInquiry={mockOnReplyInquiry}
       />
     );
 
@@ -58,7 +58,7 @@ describe('AdminContactManagement', () => {
         inquiries={mockInquiries}
         setInquiries={mockSetInquiries}
         onDeleteInquiry={mockOnDeleteInquiry}
-        onApproveInquiry={mockOnApproveInquiry}
+        onReplyInquiry={mockOnReplyInquiry}
       />
     );
 
@@ -75,7 +75,7 @@ describe('AdminContactManagement', () => {
         inquiries={mockInquiries}
         setInquiries={mockSetInquiries}
         onDeleteInquiry={mockOnDeleteInquiry}
-        onApproveInquiry={mockOnApproveInquiry}
+        onReplyInquiry={mockOnReplyInquiry}
       />
     );
 
@@ -93,7 +93,7 @@ describe('AdminContactManagement', () => {
         inquiries={mockInquiries}
         setInquiries={mockSetInquiries}
         onDeleteInquiry={mockOnDeleteInquiry}
-        onApproveInquiry={mockOnApproveInquiry}
+        onReplyInquiry={mockOnReplyInquiry}
       />
     );
 
@@ -112,7 +112,7 @@ describe('AdminContactManagement', () => {
     const sendButton = screen.getByText('メールで送信');
     fireEvent.click(sendButton);
 
-    expect(mockOnApproveInquiry).toHaveBeenCalledWith(1);
+    expect(mockOnReplyInquiry).toHaveBeenCalledWith(1, '了解しました。');
     // モーダルが閉じていることを確認
     expect(screen.queryByText(/返信: 利用者A 様/)).not.toBeInTheDocument();
   });
@@ -123,7 +123,7 @@ describe('AdminContactManagement', () => {
         inquiries={mockInquiries}
         setInquiries={mockSetInquiries}
         onDeleteInquiry={mockOnDeleteInquiry}
-        onApproveInquiry={mockOnApproveInquiry}
+        onReplyInquiry={mockOnReplyInquiry}
       />
     );
 
@@ -142,7 +142,7 @@ describe('AdminContactManagement', () => {
         inquiries={mockInquiries}
         setInquiries={mockSetInquiries}
         onDeleteInquiry={mockOnDeleteInquiry}
-        onApproveInquiry={mockOnApproveInquiry}
+        onReplyInquiry={mockOnReplyInquiry}
       />
     );
 
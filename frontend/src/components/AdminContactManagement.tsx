@@ -17,14 +17,14 @@ export interface AdminContactManagementProps {
   inquiries: Inquiry[];
   setInquiries: React.Dispatch<React.SetStateAction<Inquiry[]>>;
   onDeleteInquiry: (askId: number) => void;
-  onApproveInquiry: (askId: number) => void;
+  onReplyInquiry: (askId: number, body: string) => void;
 }
 
 export default function AdminContactManagement({
   inquiries,
   setInquiries,
   onDeleteInquiry,
-  onApproveInquiry,
+  onReplyInquiry,
 }: AdminContactManagementProps) {
   const [searchQuery, setSearchQuery] = useState('');
   const [showOnlyOpen, setShowOnlyOpen] = useState(false);
@@ -55,14 +55,11 @@ export default function AdminContactManagement({
   const handleSendEmail = () => {
     if (!replyingInquiry) return;
 
-    //API呼んでいる？
-    onApproveInquiry(replyingInquiry.askId);
+    // 返信はメールで送信され、問い合わせはユーザーの返答待ちになる
+    onReplyInquiry(replyingInquiry.askId, replyText);
     setInquiries((prev) =>
-      prev.map((q) =>
-        q.askId === replyingInquiry.askId ? { ...q, askFlag: true, draft: undefined } : q
-      )
+      prev.map((q) => (q.askId === replyingInquiry.askId ? { ...q, draft: undefined } : q))
     );
-    toast.success('メールを送信しました。問い合わせを対応済みにしました。');
     closeModal();
   };
 
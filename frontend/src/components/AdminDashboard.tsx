@@ -120,7 +120,7 @@ export function AdminDashboard({ user, onLogout }: AdminDashboardProps) {
 
   const fetchInquiries = useCallback(async () => {
     try {
-      const res = await fetch(`${API_BASE}/admin/inquiries`);
+      const res = await fetch(`${API_BASE}/admin/inquiries?pageSize=100`);
       if (!res.ok) throw new Error('Failed to fetch inquiries');
       const data = await res.json();
       const raw = data.inquiries ?? data.asks ?? data;
@@ -162,13 +162,24 @@ export function AdminDashboard({ user, onLogout }: AdminDashboardProps) {
   // const handleApprove... removed
 
   const handleResolveReport = async (reportId: number) => {
+    // 対応メモは必須（通報者への通知にも含まれる）
+    const note = prompt('対応メモを入力してください（通報者にも通知されます）');
+    if (!note || !note.trim()) return;
     try {
-      const res = await fetch(`${API_BASE}/admin/reports/${reportId}/handle`, { method: 'PUT' });
+      const res = await fetch(`${API_BASE}/admin/reports/${reportId}/handle`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ resolution: 'dismiss', note }),
+      });
       if (!res.ok) throw new Error('Failed to resolve report');
 
+      // 同じ投稿への未処理の通報もまとめて処理される
+      const targetPostId = reports.find((report) => report.reportId === reportId)?.postId;
       setReports((prev) =>
         prev.map((report) =>
-          report.reportId === reportId ? { ...report, reportFlag: true } : report
+          report.reportId === reportId || report.postId === targetPostId
+            ? { ...report, reportFlag: true }
+            : report
         )
       );
       // サイドバーのバッジ件数も即時更新
@@ -237,16 +248,22 @@ export function AdminDashboard({ user, onLogout }: AdminDashboardProps) {
     }
   };
 
-  const handleApproveInquiry = async (askId: number) => {
+  const handleReplyInquiry = async (askId: number, body: string) => {
     try {
-      const res = await fetch(`${API_BASE}/admin/inquiries/${askId}/approve`, { method: 'PUT' });
-      if (!res.ok) throw new Error('Failed to approve');
-
-      setInquiries((prev) => prev.map((q) => (q.askId === askId ? { ...q, askFlag: true } : q)));
-      toast.success('問い合わせを対応済みにしました');
+      const res = await fetch(`${API_BASE}/admin/inquiries/${askId}/messages`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ body }),
+      });
+      if (!res.ok) throw new Error('Failed to reply');
+
+      setInquiries((prev) =>
+        prev.map((q) => (q.askId === askId ? { ...q, askFlag: false, status: 'waiting' } : q))
+      );
+      toast.success('返信をメールで送信しました');
     } catch (error) {
       console.error(error);
-      toast.error('対応済みにできませんでした');
+      toast.error('返信を送信できませんでした');
     }
   };
 
@@ -553,7 +570,7 @@ export function AdminDashboard({ user, onLogout }: AdminDashboardProps) {
               inquiries={inquiries}
               setInquiries={setInquiries}
               onDeleteInquiry={handleDeleteInquiry}
-              onApproveInquiry={handleApproveInquiry}
+              onReplyInquiry={handleReplyInquiry}
             />
           )}
         </div>
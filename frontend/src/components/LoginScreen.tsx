@@ -3,8 +3,8 @@ import { Card, CardContent, CardDescription, CardHeader, CardTitle } from './ui/
 import { Checkbox } from './ui/checkbox';
 import { useState } from 'react';
 import { MapPin, User, Building2, Loader2 } from 'lucide-react';
-import { exchangeGoogleTokenForJWT, storeJWT, storeUser } from '../lib/auth';
-import { useGoogleLogin } from '@react-oauth/google';
+import { exchangeGoogleTokenForJWT, storeJWT, storeRefreshToken, storeUser } from '../lib/auth';
+import { GoogleLogin, type CredentialResponse } from '@react-oauth/google';
 
 type UserRole = 'user' | 'business' | 'admin';
 
@@ -23,64 +23,51 @@ export function LoginScreen({ onLogin }: LoginScreenProps) {
   // const [googleId, setGoogleId] = useState<string | null>(null); // Removed
   // const [userEmail, setUserEmail] = useState<string | null>(null); // Removed
 
-  const login = useGoogleLogin({
-    onSuccess: async (tokenResponse) => {
-      setIsLoading(true);
-      try {
-        // 1. Googleユーザー情報を取得
-        const userInfoRes = await fetch('https://www.googleapis.com/oauth2/v3/userinfo', {
-          headers: { Authorization: `Bearer ${tokenResponse.access_token}` },
-        });
-        if (!userInfoRes.ok) {
-          throw new Error('Googleユーザー情報の取得に失敗しました');
-        }
-        const userInfo = await userInfoRes.json();
-
-        const data = await exchangeGoogleTokenForJWT(tokenResponse.access_token, 'user');
-
-        // 3. 保存 & 遷移
-        storeJWT(data.jwt_token);
-        storeUser(data.user);
-        // 追加: セッションID保存
-        let sessionIdToStore = null;
-        if (data.sessionId) {
-          sessionIdToStore = data.sessionId;
-        } else if (data.session_id) {
-          sessionIdToStore = data.session_id;
-        } else if (data.session && data.session.sessionId) {
-          sessionIdToStore = data.session.sessionId;
-        } else if (data.session && data.session.id) {
-          sessionIdToStore = data.session.id;
-        }
-        if (sessionIdToStore) {
-          localStorage.setItem('kojanmap_sessionId', sessionIdToStore);
-        } else {
-          console.warn('[Login] sessionIdが取得できませんでした。APIレスポンス:', data);
-        }
-
-        // バックエンドから返却されたロール（既存なら business の可能性あり）を使用
-        onLogin(data.user.role as UserRole, userInfo.sub, userInfo.email);
+  // Google の ID トークン（credential）をバックエンドで検証して JWT に交換する
+  const handleCredential = async (credentialResponse: CredentialResponse) => {
+    if (!credentialResponse.credential) {
+      alert('Googleログインに失敗しました');
+      return;
+    }
+    setIsLoading(true);
+    try {
+      const data = await exchangeGoogleTokenForJWT(credentialResponse.credential, 'user');
 
-      } catch (error) {
-        console.error('Login Error:', error);
-        alert('ログイン処理に失敗しました。');
-      } finally {
-        setIsLoading(false);
+      // 保存 & 遷移
+      if (data.refresh_token) {
+        storeRefreshToken(data.refresh_token);
+      }
+      storeJWT(data.jwt_token);
+      storeUser(data.user);
+      // 追加: セッションID保存
+      let sessionIdToStore = null;
+      if (data.sessionId) {
+        sessionIdToStore = data.sessionId;
+      } else if (data.session_id) {
+        sessionIdToStore = data.session_id;
+      } else if (data.session && data.session.sessionId) {
+        sessionIdToStore = data.session.sessionId;
+      } else if (data.session && data.session.id) {
+        sessionIdToStore = data.session.id;
+      }
+      if (sessionIdToStore) {
+        localStorage.setItem('kojanmap_sessionId', sessionIdToStore);
+      } else {
+        console.warn('[Login] sessionIdが取得できませんでした。APIレスポンス:', data);
       }
-    },
-    onError: () => {
-      console.error('Login Failed');
-      alert('Googleログインに失敗しました');
-      setIsLoading(false);
-    },
-  });
 
-  const handleGoogleLoginClick = () => {
-    if (!agreedToTerms) {
-      alert('利用規約に同意してください');
-      return;
+      // バックエンドから返却されたロール（既存なら business の可能性あり）を使用
+      onLogin(
+        data.user.role as UserRole,
+        data.user.googleId || '',
+        data.user.gmail || data.user.email || ''
+      );
+    } catch (error) {
+      console.error('Login Error:', error);
+      alert('ログイン処理に失敗しました。');
+    } finally {
+      setIsLoading(false);
     }
-    login();
   };
 
   return (
@@ -123,20 +110,29 @@ export function LoginScreen({ onLogin }: LoginScreenProps) {
               </div>
             )}
 
-            <Button
-              onClick={handleGoogleLoginClick}
-              className="w-full"
-              disabled={!agreedToTerms || isLoading}
-            >
-              {isLoading ? (
-                <Loader2 className="w-5 h-5 animate-spin" />
-              ) : (
-                <span className="flex items-center">
-                  <GoogleIcon />
-                  Googleでログイン
-                </span>
-              )}
-            </Button>
+            {agreedToTerms && !isLoading ? (
+              <div className="flex justify-center">
+                <GoogleLogin
+                  onSuccess={handleCredential}
+                  onError={() => {
+                    console.error('Login Failed');
+                    alert('Googleログインに失敗しました');
+                  }}
+                  text="signin_with"
+                />
+              </div>
+            ) : (
+              <Button className="w-full" disabled>
+                {isLoading ? (
+                  <Loader2 className="w-5 h-5 animate-spin" />
+                ) : (
+                  <span className="flex items-center">
+                    <GoogleIcon />
+                    Googleでログイン
+                  </span>
+                )}
+              </Button>
+            )}
           </div>
         </CardContent>
       </Card>
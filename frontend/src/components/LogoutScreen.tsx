@@ -4,7 +4,13 @@ import { Card, CardContent, CardDescription, CardHeader, CardTitle } from './ui/
 import { LogOut, Check, ArrowLeft, Loader2 } from 'lucide-react';
 import { toast } from 'sonner';
 import { User } from '../types';
-import { getStoredJWT, removeStoredJWT, removeStoredUser } from '../lib/auth';
+import {
+  getStoredJWT,
+  getStoredRefreshToken,
+  removeStoredJWT,
+  removeStoredRefreshToken,
+  removeStoredUser,
+} from '../lib/auth';
 
 import { API_BASE_URL } from '../lib/apiBaseUrl';
 
@@ -33,7 +39,7 @@ export function LogoutScreen({ user, onLogout, onBack }: LogoutScreenProps) {
           'Content-Type': 'application/json',
           ...(token ? { Authorization: `Bearer ${token}` } : {}),
         },
-        body: JSON.stringify({ sessionId }),
+        body: JSON.stringify({ sessionId, refresh_token: getStoredRefreshToken() ?? undefined }),
       });
 
       if (!response.ok && response.status !== 401) {
@@ -46,6 +52,7 @@ export function LogoutScreen({ user, onLogout, onBack }: LogoutScreenProps) {
       localStorage.removeItem('kojanmap_jwt');
       localStorage.removeItem('kojanmap_user');
       localStorage.removeItem('kojanmap_sessionId');
+      removeStoredRefreshToken();
       toast.success('ログアウトしました');
       onLogout();
     } catch (error) {
@@ -54,6 +61,7 @@ export function LogoutScreen({ user, onLogout, onBack }: LogoutScreenProps) {
       localStorage.removeItem('kojanmap_jwt');
       localStorage.removeItem('kojanmap_user');
       localStorage.removeItem('kojanmap_sessionId');
+      removeStoredRefreshToken();
       onLogout();
     } finally {
       setIsPending(false);
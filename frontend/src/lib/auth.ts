@@ -1,5 +1,6 @@
 const JWT_STORAGE_KEY = 'kojanmap_jwt';
 const USER_STORAGE_KEY = 'kojanmap_user';
+const REFRESH_STORAGE_KEY = 'kojanmap_refresh';
 
 import { API_BASE_URL } from './apiBaseUrl';
 
@@ -30,6 +31,7 @@ export type StoredUser = {
 
 export type ExchangeResponse = {
   jwt_token: string;
+  refresh_token?: string;
   user: BackendUser;
 };
 
@@ -74,6 +76,7 @@ export async function exchangeGoogleTokenForJWT(
 
 export function storeJWT(token: string) {
   localStorage.setItem(JWT_STORAGE_KEY, token);
+  scheduleTokenRefresh(token);
 }
 
 export function getStoredJWT(): string | null {
@@ -105,8 +108,90 @@ export function removeStoredUser() {
   localStorage.removeItem(USER_STORAGE_KEY);
 }
 
+export function storeRefreshToken(token: string) {
+  localStorage.setItem(REFRESH_STORAGE_KEY, token);
+}
+
+export function getStoredRefreshToken(): string | null {
+  return localStorage.getItem(REFRESH_STORAGE_KEY);
+}
+
+export function removeStoredRefreshToken() {
+  localStorage.removeItem(REFRESH_STORAGE_KEY);
+}
+
+// アクセストークンは短時間で失効するため、期限の少し前にリフレッシュトークンで再発行する
+// リフレッシュトークンは1回限りなので、再発行のたびに新しいものを保存する
+let refreshInFlight: Promise<string | null> | null = null;
+let refreshTimer: ReturnType<typeof setTimeout> | null = null;
+
+export function refreshJWT(): Promise<string | null> {
+  if (refreshInFlight) return refreshInFlight;
+
+  const refreshToken = getStoredRefreshToken();
+  if (!refreshToken) return Promise.resolve(null);
+
+  refreshInFlight = (async () => {
+    try {
+      const response = await fetch(`${API_BASE_URL}/api/auth/token/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      });
+      if (!response.ok) {
+        if (response.status === 401) {
+          // 失効・再利用されたトークン：再ログインが必要
+          removeStoredRefreshToken();
+        }
+        return null;
+      }
+      const data = (await response.json()) as { jwt_token: string; refresh_token: string };
+      storeRefreshToken(data.refresh_token);
+      storeJWT(data.jwt_token);
+      return data.jwt_token;
+    } catch (error) {
+      console.error('Failed to refresh token', error);
+      return null;
+    } finally {
+      refreshInFlight = null;
+    }
+  })();
+  return refreshInFlight;
+}
+
+function tokenExpiry(token: string): number | null {
+  try {
+    const payload = JSON.parse(atob(token.split('.')[1].replace(/-/g, '+').replace(/_/g, '/')));
+    return typeof payload.exp === 'number' ? payload.exp * 1000 : null;
+  } catch {
+    return null;
+  }
+}
+
+function scheduleTokenRefresh(token: string) {
+  if (refreshTimer) clearTimeout(refreshTimer);
+  refreshTimer = null;
+
+  const expiresAt = tokenExpiry(token);
+  if (!expiresAt || !getStoredRefreshToken()) return;
+
+  const delay = Math.max(expiresAt - Date.now() - 60_000, 0);
+  refreshTimer = setTimeout(() => {
+    void refreshJWT();
+  }, delay);
+}
+
+// ページ再読み込み時も再発行を予約する
+if (typeof window !== 'undefined') {
+  const stored = getStoredJWT();
+  if (stored) scheduleTokenRefresh(stored);
+}
+
 export function logout() {
+  if (refreshTimer) clearTimeout(refreshTimer);
+  refreshTimer = null;
   removeStoredJWT();
+  removeStoredRefreshToken();
   removeStoredUser();
 }
 
@@ -240,27 +325,6 @@ export async function getBusinessPostCount(token: string): Promise<number> {
   return data.count;
 }
 
-// 事業者の月間売上を取得
-export async function getBusinessRevenue(
-  token: string,
-  year: number,
-  month: number
-): Promise<{ revenue: number; currency: string }> {
-  const response = await fetch(`${API_BASE_URL}/api/business/revenue?year=${year}&month=${month}`, {
-    method: 'GET',
-    headers: {
-      'Content-Type': 'application/json',
-      Authorization: `Bearer ${token}`,
-    },
-  });
-
-  if (!response.ok) {
-    throw new Error('売上情報の取得に失敗しました');
-  }
-
-  return response.json() as Promise<{ revenue: number; currency: string }>;
-}
-
 // 事業者の名前を更新
 export async function updateBusinessName(token: string, name: string): Promise<void> {
   const response = await fetch(`${API_BASE_URL}/api/business/name`, {
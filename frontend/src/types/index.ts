@@ -49,6 +49,7 @@ export interface Inquiry {
   text: string; // 内容
   userId: string; // ユーザーID
   askFlag: boolean; // 対応済みフラグ
+  status?: 'open' | 'waiting' | 'closed'; // 対応待ち・ユーザーの返答待ち・対応済み
 }
 
 // 投稿情報インターフェース (投稿管理テーブル)
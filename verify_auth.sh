@@ -1,8 +1,20 @@
 #!/bin/bash
-# 1. Login to get token
+# Requires the backend to run with APP_ENV=dev and DEV_OIDC_ISSUER=true
+# 1. Mint an ID token from the development OIDC issuer
+ID_TOKEN=$(curl -s -X POST http://localhost:8080/api/dev/id-token \
+  -H "Content-Type: application/json" \
+  -d '{"sub": "manual-test-user", "email": "manual-test-user@example.com"}' \
+  | grep -o '"id_token":"[^"]*' | cut -d'"' -f4)
+
+if [ -z "$ID_TOKEN" ]; then
+  echo "Failed to mint ID token (is DEV_OIDC_ISSUER=true set?)"
+  exit 1
+fi
+
+# 2. Login to get token
 LOGIN_RES=$(curl -s -X POST http://localhost:8080/api/auth/exchange-token \
   -H "Content-Type: application/json" \
-  -d '{"google_token": "manual-test-user-token", "role": "user"}')
+  -d "{\"google_token\": \"$ID_TOKEN\", \"role\": \"user\"}")
 
 TOKEN=$(echo $LOGIN_RES | grep -o '"jwt_token":"[^"]*' | cut -d'"' -f4)
 
@@ -13,7 +25,7 @@ fi
 
 echo "Got Token: $TOKEN"
 
-# 2. Check /api/auth/me
+# 3. Check /api/auth/me
 echo "Checking /api/auth/me..."
 curl -v -X GET http://localhost:8080/api/auth/me \
   -H "Authorization: Bearer $TOKEN"